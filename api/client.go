@@ -27,6 +27,8 @@ type ClientConfig struct {
 	Timeout   time.Duration
 	Headers   map[string]string
 	UserAgent string
+	// MaxResponseSize limits the size of decoded response bodies
+	MaxResponseSize int64
 }
 
 // WithBaseURL sets the base URL for the client
@@ -76,9 +78,10 @@ func WithUserAgent(userAgent string) ClientOption {
 func New(opts ...ClientOption) *Client {
 	// Create default configuration
 	config := &ClientConfig{
-		Timeout:   30 * time.Second,
-		Headers:   make(map[string]string),
-		UserAgent: "tykctl-go/1.0.0",
+		Timeout:         30 * time.Second,
+		Headers:         make(map[string]string),
+		UserAgent:       "tykctl-go/1.0.0",
+		MaxResponseSize: DefaultMaxResponseSize,
 	}
 
 	// Apply functional options
@@ -168,6 +171,7 @@ func (c *Client) Clone() *Client {
 		WithClientTimeout(config.Timeout),
 		WithClientHeaders(config.Headers),
 		WithUserAgent(config.UserAgent),
+		WithMaxResponseSize(config.MaxResponseSize),
 	)
 }
 
@@ -178,6 +182,7 @@ func (c *Client) WithBaseURL(baseURL string) *Client {
 		WithClientTimeout(c.config.Timeout),
 		WithClientHeaders(c.config.Headers),
 		WithUserAgent(c.config.UserAgent),
+		WithMaxResponseSize(c.config.MaxResponseSize),
 	)
 }
 
@@ -188,6 +193,7 @@ func (c *Client) WithTimeout(timeout time.Duration) *Client {
 		WithClientTimeout(timeout),
 		WithClientHeaders(c.config.Headers),
 		WithUserAgent(c.config.UserAgent),
+		WithMaxResponseSize(c.config.MaxResponseSize),
 	)
 }
 
@@ -207,6 +213,7 @@ func (c *Client) WithHeader(key, value string) *Client {
 		WithClientTimeout(c.config.Timeout),
 		WithClientHeaders(newHeaders),
 		WithUserAgent(c.config.UserAgent),
+		WithMaxResponseSize(c.config.MaxResponseSize),
 	)
 }
 
@@ -230,6 +237,7 @@ func (c *Client) WithHeaders(headers map[string]string) *Client {
 		WithClientTimeout(c.config.Timeout),
 		WithClientHeaders(newHeaders),
 		WithUserAgent(c.config.UserAgent),
+		WithMaxResponseSize(c.config.MaxResponseSize),
 	)
 }
 
@@ -287,10 +295,16 @@ func (c *Client) Get(ctx context.Context, path string, opts ...RequestOption) (*
 	if err != nil {
 		return nil, err
 	}
+
+	respBody, err := c.decodeResponse(httpResp.Headers, httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Response{
 		StatusCode: httpResp.StatusCode,
 		Headers:    httpResp.Headers,
-		Body:       httpResp.Body,
+		Body:       respBody,
 		Duration:   0, // Will be set by middleware
 	}, nil
 }
@@ -348,15 +362,23 @@ func (c *Client) Post(ctx context.Context, path string, data interface{}, opts .
 	if err != nil {
 		return nil, err
 	}
+
+	respBody, err := c.decodeResponse(httpResp.Headers, httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Response{
 		StatusCode: httpResp.StatusCode,
 		Headers:    httpResp.Headers,
-		Body:       httpResp.Body,
+		Body:       respBody,
 		Duration:   0, // Will be set by middleware
 	}, nil
 }
 
-// Put makes a PUT request
+// Put makes a PUT request. Like Get and Post, it returns statuses of
+// 400 and above as a response with a nil error, where earlier versions
+// returned an error; check IsSuccess or StatusCode
 func (c *Client) Put(ctx context.Context, path string, data interface{}, opts ...RequestOption) (*Response, error) {
 	req := &Request{
 		Method: "PUT",
@@ -396,7 +418,7 @@ func (c *Client) Put(ctx context.Context, path string, data interface{}, opts ..
 		c.httpClient.SetHeader(k, v)
 	}
 
-	respBody, err := c.httpClient.PutWithContext(ctx, fullPath, body)
+	httpResp, err := c.httpClient.PutResponseWithContext(ctx, fullPath, body)
 
 	// Restore original headers
 	for k := range req.Headers {
@@ -409,15 +431,23 @@ func (c *Client) Put(ctx context.Context, path string, data interface{}, opts ..
 	if err != nil {
 		return nil, err
 	}
+
+	respBody, err := c.decodeResponse(httpResp.Headers, httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Response{
-		StatusCode: 200, // Assume success if no error
-		Headers:    make(map[string]string),
+		StatusCode: httpResp.StatusCode,
+		Headers:    httpResp.Headers,
 		Body:       respBody,
-		Duration:   0,
+		Duration:   0, // Will be set by middleware
 	}, nil
 }
 
-// Delete makes a DELETE request
+// Delete makes a DELETE request. Like Get and Post, it returns statuses of
+// 400 and above as a response with a nil error, where earlier versions
+// returned an error; check IsSuccess or StatusCode
 func (c *Client) Delete(ctx context.Context, path string, opts ...RequestOption) (*Response, error) {
 	req := &Request{
 		Method: "DELETE",
@@ -445,7 +475,7 @@ func (c *Client) Delete(ctx context.Context, path string, opts ...RequestOption)
 		c.httpClient.SetHeader(k, v)
 	}
 
-	body, err := c.httpClient.DeleteWithContext(ctx, fullPath)
+	httpResp, err := c.httpClient.DeleteResponseWithContext(ctx, fullPath)
 
 	// Restore original headers
 	for k := range req.Headers {
@@ -458,15 +488,23 @@ func (c *Client) Delete(ctx context.Context, path string, opts ...RequestOption)
 	if err != nil {
 		return nil, err
 	}
+
+	respBody, err := c.decodeResponse(httpResp.Headers, httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Response{
-		StatusCode: 200, // Assume success if no error
-		Headers:    make(map[string]string),
-		Body:       body,
-		Duration:   0,
+		StatusCode: httpResp.StatusCode,
+		Headers:    httpResp.Headers,
+		Body:       respBody,
+		Duration:   0, // Will be set by middleware
 	}, nil
 }
 
-// Patch makes a PATCH request
+// Patch makes a PATCH request. Like Get and Post, it returns statuses of
+// 400 and above as a response with a nil error, where earlier versions
+// returned an error; check IsSuccess or StatusCode
 func (c *Client) Patch(ctx context.Context, path string, data interface{}, opts ...RequestOption) (*Response, error) {
 	req := &Request{
 		Method: "PATCH",
@@ -506,7 +544,7 @@ func (c *Client) Patch(ctx context.Context, path string, data interface{}, opts
 		c.httpClient.SetHeader(k, v)
 	}
 
-	respBody, err := c.httpClient.PatchWithContext(ctx, fullPath, body)
+	httpResp, err := c.httpClient.PatchResponseWithContext(ctx, fullPath, body)
 
 	// Restore original headers
 	for k := range req.Headers {
@@ -519,11 +557,17 @@ func (c *Client) Patch(ctx context.Context, path string, data interface{}, opts
 	if err != nil {
 		return nil, err
 	}
+
+	respBody, err := c.decodeResponse(httpResp.Headers, httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Response{
-		StatusCode: 200, // Assume success if no error
-		Headers:    make(map[string]string),
+		StatusCode: httpResp.StatusCode,
+		Headers:    httpResp.Headers,
 		Body:       respBody,
-		Duration:   0,
+		Duration:   0, // Will be set by middleware
 	}, nil
 }
 
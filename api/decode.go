@@ -0,0 +1,160 @@
+package api
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// DefaultMaxResponseSize is the default limit for decoded response bodies
+const DefaultMaxResponseSize int64 = 32 << 20
+
+// ErrResponseTooLarge is returned when a decoded response body exceeds the configured limit
+var ErrResponseTooLarge = errors.New("response body exceeds maximum size")
+
+// utf8BOM is the byte order mark some servers prepend to UTF-8 bodies
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// WithMaxResponseSize sets the maximum size of a decoded response body
+func WithMaxResponseSize(size int64) ClientOption {
+	return func(c *ClientConfig) {
+		c.MaxResponseSize = size
+	}
+}
+
+// DecodeBody decompresses a response body according to its Content-Encoding and
+// converts it to UTF-8 according to the charset declared in its Content-Type.
+// The decoded body is limited to maxSize bytes; a non-positive maxSize uses
+// DefaultMaxResponseSize.
+func DecodeBody(body []byte, contentEncoding, contentType string, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxResponseSize
+	}
+
+	decompressed, err := decompressBody(body, contentEncoding, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeCharset(decompressed, contentType, maxSize)
+}
+
+// decodeResponse normalizes a response body using the response headers
+func (c *Client) decodeResponse(headers map[string]string, body []byte) ([]byte, error) {
+	return DecodeBody(
+		body,
+		headerValue(headers, "Content-Encoding"),
+		headerValue(headers, "Content-Type"),
+		c.config.MaxResponseSize,
+	)
+}
+
+// decompressBody reverses each content coding in the order it was applied
+func decompressBody(body []byte, contentEncoding string, maxSize int64) ([]byte, error) {
+	if contentEncoding == "" || len(body) == 0 {
+		return body, nil
+	}
+
+	codings := strings.Split(contentEncoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+
+		var reader io.Reader
+		switch coding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			gz, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode gzip response: %w", err)
+			}
+			defer gz.Close()
+			reader = gz
+		case "deflate":
+			reader = newDeflateReader(body)
+		case "br":
+			reader = brotli.NewReader(bytes.NewReader(body))
+		default:
+			return nil, fmt.Errorf("unsupported content encoding: %s", coding)
+		}
+
+		decoded, err := readLimited(reader, maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s response: %w", coding, err)
+		}
+		body = decoded
+	}
+
+	return body, nil
+}
+
+// newDeflateReader handles both zlib-wrapped and raw deflate streams, as
+// servers disagree on what "deflate" means
+func newDeflateReader(body []byte) io.Reader {
+	if zr, err := zlib.NewReader(bytes.NewReader(body)); err == nil {
+		return zr
+	}
+	return flate.NewReader(bytes.NewReader(body))
+}
+
+// normalizeCharset converts a body to UTF-8 using the charset from contentType
+func normalizeCharset(body []byte, contentType string, maxSize int64) ([]byte, error) {
+	charset := ""
+	if contentType != "" {
+		if _, params, err := mime.ParseMediaType(contentType); err == nil {
+			charset = strings.ToLower(strings.TrimSpace(params["charset"]))
+		}
+	}
+
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii":
+		return bytes.TrimPrefix(body, utf8BOM), nil
+	}
+
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported response charset: %s", charset)
+	}
+
+	decoded, err := readLimited(enc.NewDecoder().Reader(bytes.NewReader(body)), maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s response to UTF-8: %w", charset, err)
+	}
+
+	return bytes.TrimPrefix(decoded, utf8BOM), nil
+}
+
+// readLimited reads at most maxSize bytes and fails if more are available
+func readLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrResponseTooLarge
+	}
+	return data, nil
+}
+
+// headerValue looks up a header regardless of the key's case
+func headerValue(headers map[string]string, key string) string {
+	if value, ok := headers[http.CanonicalHeaderKey(key)]; ok {
+		return value
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
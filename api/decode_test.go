@@ -0,0 +1,197 @@
+package api
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestGetDecodesCompressedResponse(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write([]byte(`{"message":"Hello"}`))
+	gz.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A proxy may compress even when the client did not ask for it
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	client := New(WithBaseURL(server.URL), WithClientHeader("Accept-Encoding", "gzip"))
+
+	resp, err := client.Get(context.Background(), "/test")
+	if err != nil {
+		t.Fatalf("GET request failed: %v", err)
+	}
+
+	var body map[string]string
+	if err := resp.UnmarshalJSON(&body); err != nil {
+		t.Fatalf("Failed to unmarshal decoded body: %v", err)
+	}
+
+	if body["message"] != "Hello" {
+		t.Errorf("Expected message 'Hello', got '%s'", body["message"])
+	}
+}
+
+func TestWriteMethodsDecodeResponse(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write([]byte(`{"message":"Hello"}`))
+	gz.Close()
+
+	tests := []struct {
+		method string
+		status int
+		call   func(client *Client) (*Response, error)
+	}{
+		{"PUT", http.StatusAccepted, func(client *Client) (*Response, error) {
+			return client.Put(context.Background(), "/test", map[string]string{"name": "Updated"})
+		}},
+		{"DELETE", http.StatusNotFound, func(client *Client) (*Response, error) {
+			return client.Delete(context.Background(), "/test")
+		}},
+		{"PATCH", http.StatusCreated, func(client *Client) (*Response, error) {
+			return client.Patch(context.Background(), "/test", map[string]string{"name": "Patched"})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.method {
+					t.Errorf("Expected %s method, got %s", tt.method, r.Method)
+				}
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Request-Id", "abc123")
+				w.WriteHeader(tt.status)
+				w.Write(compressed.Bytes())
+			}))
+			defer server.Close()
+
+			client := New(WithBaseURL(server.URL))
+
+			resp, err := tt.call(client)
+			if err != nil {
+				t.Fatalf("%s request failed: %v", tt.method, err)
+			}
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if resp.GetHeader("X-Request-Id") != "abc123" {
+				t.Errorf("Expected X-Request-Id header 'abc123', got '%s'", resp.GetHeader("X-Request-Id"))
+			}
+			if resp.String() != `{"message":"Hello"}` {
+				t.Errorf("Expected decoded body, got '%s'", resp.String())
+			}
+		})
+	}
+}
+
+func TestWriteMethodsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"boom"}`))
+	}))
+	defer server.Close()
+
+	client := New(WithBaseURL(server.URL))
+	calls := map[string]func() (*Response, error){
+		"PUT": func() (*Response, error) {
+			return client.Put(context.Background(), "/test", map[string]string{"name": "Updated"})
+		},
+		"DELETE": func() (*Response, error) {
+			return client.Delete(context.Background(), "/test")
+		},
+		"PATCH": func() (*Response, error) {
+			return client.Patch(context.Background(), "/test", map[string]string{"name": "Patched"})
+		},
+	}
+
+	for method, call := range calls {
+		t.Run(method, func(t *testing.T) {
+			resp, err := call()
+			if err != nil {
+				t.Fatalf("Expected no error for a 500 response, got %v", err)
+			}
+			if resp.StatusCode != http.StatusInternalServerError || resp.IsSuccess() {
+				t.Errorf("Expected an unsuccessful 500 response, got %d", resp.StatusCode)
+			}
+			if resp.String() != `{"error":"boom"}` {
+				t.Errorf("Expected the error body, got '%s'", resp.String())
+			}
+		})
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	payload := []byte(`{"name":"café"}`)
+
+	var deflated bytes.Buffer
+	fw, _ := flate.NewWriter(&deflated, flate.DefaultCompression)
+	fw.Write(payload)
+	fw.Close()
+
+	var brotlied bytes.Buffer
+	bw := brotli.NewWriter(&brotlied)
+	bw.Write(payload)
+	bw.Close()
+
+	tests := []struct {
+		name            string
+		body            []byte
+		contentEncoding string
+		contentType     string
+		expected        []byte
+	}{
+		{"identity", payload, "", "application/json", payload},
+		{"deflate", deflated.Bytes(), "deflate", "application/json", payload},
+		{"brotli", brotlied.Bytes(), "br", "application/json", payload},
+		{"latin1", []byte("{\"name\":\"caf\xe9\"}"), "", "application/json; charset=ISO-8859-1", payload},
+		{"utf8 bom", append([]byte{0xEF, 0xBB, 0xBF}, payload...), "", "application/json; charset=utf-8", payload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeBody(tt.body, tt.contentEncoding, tt.contentType, 0)
+			if err != nil {
+				t.Fatalf("DecodeBody failed: %v", err)
+			}
+			if !bytes.Equal(decoded, tt.expected) {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeBodyErrors(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(bytes.Repeat([]byte("a"), 1024))
+	gz.Close()
+
+	if _, err := DecodeBody(compressed.Bytes(), "gzip", "", 512); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+
+	if _, err := DecodeBody([]byte("data"), "compress", "", 0); err == nil {
+		t.Error("Expected error for unsupported content encoding")
+	}
+
+	if _, err := DecodeBody([]byte("data"), "", "text/plain; charset=unknown-charset", 0); err == nil {
+		t.Error("Expected error for unsupported charset")
+	}
+}
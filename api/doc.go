@@ -5,6 +5,7 @@
 //   - Retry Logic: Configurable retry strategies with exponential backoff
 //   - Middleware Support: Chainable middleware for logging, timeouts, authentication
 //   - Response Handling: Rich response objects with status code checking and JSON unmarshaling
+//   - Response Decoding: Transparent gzip/deflate/br decompression and charset normalization to UTF-8
 //   - Pagination Support: Built-in pagination handling for API responses
 //   - Error Handling: Comprehensive error types and handling with retryable error detection
 //   - Context Support: Full context.Context integration for cancellation and timeouts
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// CanonicalPath returns a privacy-safe identifier for an executed command,
+// such as "apis get <id>". The root command name is omitted and argument
+// values are replaced with the placeholders declared in the command's Use
+// line, or "<arg>" where none is declared, so telemetry and audit logs can
+// report identical command identifiers without leaking user input.
+func CanonicalPath(cmd *cobra.Command, args []string) string {
+	var names []string
+	for c := cmd; c != nil && c.HasParent(); c = c.Parent() {
+		names = append([]string{c.Name()}, names...)
+	}
+	if len(names) == 0 {
+		names = []string{cmd.Name()}
+	}
+
+	return strings.Join(append(names, argPlaceholders(cmd.Use, len(args))...), " ")
+}
+
+// CanonicalFlags returns the sorted names of the flags set on the command
+// line, without their values
+func CanonicalFlags(cmd *cobra.Command) []string {
+	seen := make(map[string]bool)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		seen[f.Name] = true
+	})
+	cmd.InheritedFlags().Visit(func(f *pflag.Flag) {
+		seen[f.Name] = true
+	})
+
+	flags := make([]string, 0, len(seen))
+	for name := range seen {
+		flags = append(flags, "--"+name)
+	}
+	sort.Strings(flags)
+	return flags
+}
+
+// argPlaceholders returns a placeholder for each of count arguments, using
+// the argument names declared in a Use line such as "get <id> [field...]".
+// A variadic argument is reported once however many values it received.
+func argPlaceholders(use string, count int) []string {
+	if count == 0 {
+		return nil
+	}
+
+	var declared []string
+	fields := strings.Fields(use)
+	if len(fields) > 1 {
+		fields = fields[1:]
+	} else {
+		fields = nil
+	}
+	for _, field := range fields {
+		if name := placeholderName(field); name != "" {
+			declared = append(declared, name)
+		}
+	}
+
+	placeholders := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if i >= len(declared) {
+			placeholders = append(placeholders, "<arg>")
+			continue
+		}
+
+		name := declared[i]
+		if strings.HasSuffix(name, "...") {
+			placeholders = append(placeholders, "<"+strings.TrimSuffix(name, "...")+">...")
+			break
+		}
+		placeholders = append(placeholders, "<"+name+">")
+	}
+	return placeholders
+}
+
+// placeholderName extracts the argument name from a Use token such as
+// "<id>", "[name]", "FILE" or "<args>...", returning an empty string for
+// tokens that do not name an argument, like "[flags]"
+func placeholderName(token string) string {
+	variadic := strings.HasSuffix(token, "...")
+	token = strings.TrimSuffix(token, "...")
+	token = strings.Trim(token, "<>[]{}")
+	variadic = variadic || strings.HasSuffix(token, "...")
+	token = strings.TrimSuffix(token, "...")
+
+	if token == "" || strings.EqualFold(token, "flags") || strings.HasPrefix(token, "-") {
+		return ""
+	}
+	for _, r := range token {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '|' {
+			return ""
+		}
+	}
+
+	name := strings.ToLower(token)
+	if variadic {
+		name += "..."
+	}
+	return name
+}
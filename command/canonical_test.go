@@ -0,0 +1,63 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newCanonicalTree() (*cobra.Command, map[string]*cobra.Command) {
+	root := &cobra.Command{Use: "tykctl"}
+	root.PersistentFlags().String("context", "", "")
+
+	apis := &cobra.Command{Use: "apis"}
+	get := &cobra.Command{Use: "get <id> [flags]", Run: func(*cobra.Command, []string) {}}
+	get.Flags().String("output", "", "")
+	del := &cobra.Command{Use: "delete <id>...", Run: func(*cobra.Command, []string) {}}
+	set := &cobra.Command{Use: "set KEY [VALUE]", Run: func(*cobra.Command, []string) {}}
+	list := &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+
+	apis.AddCommand(get, del, set, list)
+	root.AddCommand(apis)
+
+	return root, map[string]*cobra.Command{"get": get, "delete": del, "set": set, "list": list}
+}
+
+func TestCanonicalPath(t *testing.T) {
+	root, cmds := newCanonicalTree()
+
+	tests := []struct {
+		cmd      *cobra.Command
+		args     []string
+		expected string
+	}{
+		{cmds["get"], []string{"my-secret-api"}, "apis get <id>"},
+		{cmds["get"], nil, "apis get"},
+		{cmds["get"], []string{"a", "b"}, "apis get <id> <arg>"},
+		{cmds["delete"], []string{"a", "b", "c"}, "apis delete <id>..."},
+		{cmds["set"], []string{"token", "s3cr3t"}, "apis set <key> <value>"},
+		{cmds["list"], []string{"unexpected"}, "apis list <arg>"},
+		{root, nil, "tykctl"},
+	}
+
+	for _, tt := range tests {
+		if result := CanonicalPath(tt.cmd, tt.args); result != tt.expected {
+			t.Errorf("CanonicalPath(%s, %v) = %q, expected %q", tt.cmd.Name(), tt.args, result, tt.expected)
+		}
+	}
+}
+
+func TestCanonicalFlags(t *testing.T) {
+	root, cmds := newCanonicalTree()
+
+	root.SetArgs([]string{"apis", "get", "123", "--output", "json", "--context", "prod"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := []string{"--context", "--output"}
+	if flags := CanonicalFlags(cmds["get"]); !reflect.DeepEqual(flags, expected) {
+		t.Errorf("CanonicalFlags() = %v, expected %v", flags, expected)
+	}
+}
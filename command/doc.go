@@ -6,6 +6,10 @@
 //   - Context Support: Full context.Context integration
 //   - Command Creation: Helper functions for creating commands
 //   - Long Description Support: Support for detailed command descriptions
+//   - Diagnostics: Built-in paths command for inspecting installation layout
+//   - Canonical Paths: Privacy-safe command identifiers for telemetry and audit logs
+//   - Output Formats: Shared -o/--output flag with table, wide, json, yaml, jq and jsonpath
+//   - Middleware: Composable pre/post run concerns wrapping RunE
 //
 // Example:
 //   cmd := command.New("myapp", "Short description", handler)
@@ -0,0 +1,115 @@
+package command
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// RunFunc is the signature of a Cobra RunE function
+type RunFunc func(cmd *cobra.Command, args []string) error
+
+// Middleware wraps a command's RunE with concerns that run before and after
+// it, such as telemetry tracking, auth checks, config loading, panic
+// recovery and timing
+type Middleware func(next RunFunc) RunFunc
+
+// PanicError is returned by Recover when a command panics
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error describes the panic
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Use wraps the RunE of cmd and of all its subcommands with middleware, so
+// concerns set up once on the root apply to every command instead of being
+// repeated in PersistentPreRun hooks. The first middleware is the
+// outermost. Run functions are converted to RunE, and commands without
+// either, such as command groups, are left alone. Subcommands added after
+// Use are not wrapped, and each call wraps what earlier calls produced, so
+// middleware of later calls runs first
+func Use(cmd *cobra.Command, middleware ...Middleware) *cobra.Command {
+	wrap(cmd, Chain(middleware...))
+	return cmd
+}
+
+func wrap(cmd *cobra.Command, middleware Middleware) {
+	wrapRun(cmd, middleware)
+	for _, subcmd := range cmd.Commands() {
+		wrap(subcmd, middleware)
+	}
+}
+
+// wrapRun wraps the run function of cmd alone
+func wrapRun(cmd *cobra.Command, middleware Middleware) {
+	switch {
+	case cmd.RunE != nil:
+		cmd.RunE = middleware(cmd.RunE)
+	case cmd.Run != nil:
+		run := cmd.Run
+		cmd.RunE = middleware(func(cmd *cobra.Command, args []string) error {
+			run(cmd, args)
+			return nil
+		})
+		cmd.Run = nil
+	}
+}
+
+// Chain composes middleware into one, the first being the outermost
+func Chain(middleware ...Middleware) Middleware {
+	return func(next RunFunc) RunFunc {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+// Before returns middleware running fn before the command, such as to load
+// configuration or check that the user is logged in. The command doesn't
+// run when fn fails
+func Before(fn RunFunc) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) error {
+			if err := fn(cmd, args); err != nil {
+				return err
+			}
+			return next(cmd, args)
+		}
+	}
+}
+
+// Recover returns middleware turning a panic in the command into a
+// *PanicError holding the value and stack, so it is reported like any other
+// error
+func Recover() Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) (err error) {
+			defer func() {
+				if value := recover(); value != nil {
+					err = &PanicError{Value: value, Stack: debug.Stack()}
+				}
+			}()
+			return next(cmd, args)
+		}
+	}
+}
+
+// Timing returns middleware calling report with how long the command took
+// and the error it returned
+func Timing(report func(cmd *cobra.Command, elapsed time.Duration, err error)) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) error {
+			start := time.Now()
+			err := next(cmd, args)
+			report(cmd, time.Since(start), err)
+			return err
+		}
+	}
+}
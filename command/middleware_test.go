@@ -0,0 +1,117 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// record returns middleware appending name to calls before and after the
+// command runs
+func record(calls *[]string, name string) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) error {
+			*calls = append(*calls, name+" before")
+			err := next(cmd, args)
+			*calls = append(*calls, name+" after")
+			return err
+		}
+	}
+}
+
+func TestUse(t *testing.T) {
+	var calls []string
+	root := &cobra.Command{Use: "tykctl"}
+	get := &cobra.Command{Use: "get", RunE: func(cmd *cobra.Command, args []string) error {
+		calls = append(calls, "get")
+		return nil
+	}}
+	list := &cobra.Command{Use: "list", Run: func(cmd *cobra.Command, args []string) {
+		calls = append(calls, "list")
+	}}
+	root.AddCommand(get, list)
+
+	Use(root, record(&calls, "outer"), record(&calls, "inner"))
+
+	if root.RunE != nil {
+		t.Error("Use() should leave commands without a run function alone")
+	}
+	if list.Run != nil || list.RunE == nil {
+		t.Error("Use() should convert Run to RunE")
+	}
+
+	root.SetArgs([]string{"get"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	root.SetArgs([]string{"list"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []string{
+		"outer before", "inner before", "get", "inner after", "outer after",
+		"outer before", "inner before", "list", "inner after", "outer after",
+	}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestBefore(t *testing.T) {
+	errLoggedOut := errors.New("not logged in")
+	ran := false
+	run := Before(func(cmd *cobra.Command, args []string) error {
+		return errLoggedOut
+	})(func(cmd *cobra.Command, args []string) error {
+		ran = true
+		return nil
+	})
+
+	if err := run(&cobra.Command{}, nil); !errors.Is(err, errLoggedOut) {
+		t.Errorf("error = %v, want %v", err, errLoggedOut)
+	}
+	if ran {
+		t.Error("the command ran after Before failed")
+	}
+}
+
+func TestRecover(t *testing.T) {
+	run := Recover()(func(cmd *cobra.Command, args []string) error {
+		panic("boom")
+	})
+
+	err := run(&cobra.Command{}, nil)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("error = %v, want a *PanicError", err)
+	}
+	if panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Errorf("PanicError = %+v", panicErr)
+	}
+	if err.Error() != "panic: boom" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestTiming(t *testing.T) {
+	errFailed := errors.New("failed")
+	var elapsed time.Duration
+	var reported error
+	run := Timing(func(cmd *cobra.Command, d time.Duration, err error) {
+		elapsed, reported = d, err
+	})(func(cmd *cobra.Command, args []string) error {
+		time.Sleep(10 * time.Millisecond)
+		return errFailed
+	})
+
+	if err := run(&cobra.Command{}, nil); err != errFailed {
+		t.Errorf("error = %v, want %v", err, errFailed)
+	}
+	if elapsed < 10*time.Millisecond || reported != errFailed {
+		t.Errorf("reported %v, %v", elapsed, reported)
+	}
+}
@@ -0,0 +1,318 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/edsonmichaque/tykctl-go/jq"
+	"github.com/edsonmichaque/tykctl-go/table"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of the --output flag. Besides these, "jq=EXPR" and
+// "jsonpath=EXPR" print the results of an expression
+const (
+	OutputTable = "table"
+	OutputWide  = "wide"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// OutputFlag is the name of the flag WithOutputFlag registers
+const OutputFlag = "output"
+
+// Tabular is implemented by data with its own table view. Wide is set for
+// -o wide, which may add columns
+type Tabular interface {
+	Table(wide bool) (headers []string, rows [][]string)
+}
+
+// outputKey is the context key of a command's output settings
+type outputKey struct{}
+
+// outputSettings are the format chosen with --output and where to print
+type outputSettings struct {
+	format     string
+	expression string
+	out        io.Writer
+}
+
+// WithOutputFlag registers -o/--output on cmd, taking json, yaml, table,
+// wide, jq=EXPR or jsonpath=EXPR, so every command offers the same output
+// formats. The format is checked before the command runs and passed to
+// Print through the command's context. Call it once the command's RunE or
+// Run is set, such as on the command New returns. When neither is, running
+// the command returns an error rather than silently ignoring the flag
+func WithOutputFlag(cmd *cobra.Command) *cobra.Command {
+	if cmd.RunE == nil && cmd.Run == nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("command %q has no RunE or Run to print output from", cmd.Name())
+		}
+	}
+
+	var value string
+	cmd.Flags().StringVarP(&value, OutputFlag, "o", OutputTable,
+		"Output format (table, wide, json, yaml, jq=EXPR, jsonpath=EXPR)")
+	cmd.RegisterFlagCompletionFunc(OutputFlag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{OutputTable, OutputWide, OutputJSON, OutputYAML, "jq=", "jsonpath="}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	})
+
+	wrapRun(cmd, func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) error {
+			settings, err := parseOutputFormat(value)
+			if err != nil {
+				return err
+			}
+			settings.out = cmd.OutOrStdout()
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			cmd.SetContext(context.WithValue(ctx, outputKey{}, settings))
+			return next(cmd, args)
+		}
+	})
+	return cmd
+}
+
+// parseOutputFormat parses the value of --output
+func parseOutputFormat(value string) (*outputSettings, error) {
+	format, expression, _ := strings.Cut(value, "=")
+	switch format {
+	case OutputTable, OutputWide, OutputJSON, OutputYAML:
+		if expression == "" {
+			return &outputSettings{format: format}, nil
+		}
+	case "jq":
+		if strings.TrimSpace(expression) != "" {
+			return &outputSettings{format: format, expression: expression}, nil
+		}
+	case "jsonpath":
+		if strings.TrimSpace(expression) != "" {
+			return &outputSettings{format: "jq", expression: jsonPathToJQ(expression)}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported output format %q: must be one of table, wide, json, yaml, jq=EXPR or jsonpath=EXPR", value)
+}
+
+// jsonPathToJQ converts a simple JSONPath expression, such as
+// "{.items[*].name}" or "$.items[0]", to the equivalent JQ program
+func jsonPathToJQ(path string) string {
+	path = strings.TrimSpace(path)
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	path = strings.TrimPrefix(path, "$")
+	path = strings.ReplaceAll(path, "[*]", "[]")
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+	return path
+}
+
+// Print writes data in the format chosen with the --output flag registered
+// by WithOutputFlag, to the command's output. ctx is the command's context.
+// JSON is indented, and colored on terminals, jq and jsonpath expressions
+// print their results raw, one per line, and YAML has the data's JSON field
+// names, in the same order. Tables come from data implementing Tabular, or else have a
+// column for each field of the records: the scalar ones for table, and all
+// of them for wide. Without the flag, data is printed as a table to stdout
+func Print(ctx context.Context, data interface{}) error {
+	settings, _ := ctx.Value(outputKey{}).(*outputSettings)
+	if settings == nil {
+		settings = &outputSettings{format: OutputTable, out: os.Stdout}
+	}
+
+	if tabular, ok := data.(Tabular); ok && (settings.format == OutputTable || settings.format == OutputWide) {
+		headers, rows := tabular.Table(settings.format == OutputWide)
+		return printTable(settings.out, headers, rows)
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	switch settings.format {
+	case OutputJSON:
+		return jq.PrettyPrint(settings.out, encoded)
+	case OutputYAML:
+		return printYAML(settings.out, encoded)
+	case "jq":
+		return jq.Run(ctx, bytes.NewReader(encoded), settings.out, settings.expression, jq.WithRawOutput())
+	}
+
+	columns, err := inferColumns(encoded, settings.format == OutputWide)
+	if err != nil || columns == nil {
+		return err
+	}
+	headers, rows, err := jq.ToTable(encoded, columns)
+	if err != nil {
+		return fmt.Errorf("failed to build table: %w", err)
+	}
+	return printTable(settings.out, headers, rows)
+}
+
+func printTable(w io.Writer, headers []string, rows [][]string) error {
+	t := table.NewWithWriter(w)
+	t.SetHeaders(headers)
+	t.AddRows(rows)
+	return t.Render()
+}
+
+// printYAML writes JSON as YAML, keeping the order of fields
+func printYAML(w io.Writer, encoded []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	node, err := yamlNode(decoder)
+	if err != nil {
+		return fmt.Errorf("failed to decode output: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return encoder.Close()
+}
+
+// yamlNode reads the next JSON value from decoder as a YAML node. Scalars
+// are tagged with their JSON type, so strings such as "8080" stay quoted
+func yamlNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if value == '{' {
+			node.Kind = yaml.MappingNode
+		}
+		for decoder.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := yamlNode(decoder)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, key)
+			}
+			item, err := yamlNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, item)
+		}
+		// Consume the closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case json.Number:
+		tag := "!!float"
+		if _, err := value.Int64(); err == nil {
+			tag = "!!int"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value)}, nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+}
+
+// inferColumns returns a column for each field of the records in encoded,
+// in the order they first appear, which for structs is the order of their
+// fields. Unless wide, fields holding arrays or objects are left out.
+// Records that are not objects get a single VALUE column, and an empty
+// list none
+func inferColumns(encoded []byte, wide bool) ([]jq.Column, error) {
+	var records []json.RawMessage
+	if trimmed := bytes.TrimSpace(encoded); len(trimmed) == 0 || trimmed[0] != '[' {
+		records = []json.RawMessage{encoded}
+	} else {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("failed to decode output: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+	}
+	value := []jq.Column{{Header: "VALUE", Expression: "."}}
+
+	var keys []string
+	seen := make(map[string]bool)
+	nested := make(map[string]bool)
+	for _, record := range records {
+		fields, ok := objectFields(record)
+		if !ok {
+			return value, nil
+		}
+		for _, field := range fields {
+			if field.nested {
+				nested[field.key] = true
+			}
+			if !seen[field.key] {
+				seen[field.key] = true
+				keys = append(keys, field.key)
+			}
+		}
+	}
+
+	var columns []jq.Column
+	for _, key := range keys {
+		if nested[key] && !wide {
+			continue
+		}
+		expression, _ := json.Marshal(key)
+		columns = append(columns, jq.Column{
+			Header:     strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(key)),
+			Expression: "." + string(expression),
+		})
+	}
+	if len(columns) == 0 {
+		return value, nil
+	}
+	return columns, nil
+}
+
+// objectField is a field of a JSON object
+type objectField struct {
+	key    string
+	nested bool
+}
+
+// objectFields returns the fields of a JSON object in order, and false when
+// record is not an object
+func objectFields(record json.RawMessage) ([]objectField, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(record))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+
+	var fields []objectField
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, _ := token.(string)
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false
+		}
+		nested := len(value) > 0 && (value[0] == '{' || value[0] == '[')
+		fields = append(fields, objectField{key: key, nested: nested})
+	}
+	return fields, true
+}
@@ -0,0 +1,186 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+type api struct {
+	Name       string   `json:"name"`
+	ListenPath string   `json:"listen_path"`
+	Port       int      `json:"port"`
+	Tags       []string `json:"tags"`
+}
+
+var apis = []api{
+	{Name: "httpbin", ListenPath: "/httpbin/", Port: 8080, Tags: []string{"test"}},
+	{Name: "orders", ListenPath: "/orders/", Port: 1000000},
+}
+
+// runWithOutput runs a command printing data with the given flags, and
+// returns what it printed
+func runWithOutput(t *testing.T, data interface{}, args ...string) (string, error) {
+	t.Helper()
+	t.Setenv("NO_COLOR", "1")
+
+	cmd := WithOutputFlag(&cobra.Command{
+		Use: "list",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Print(cmd.Context(), data)
+		},
+	})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	cmd.SilenceUsage = true
+
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestPrint_Formats(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"json", []string{"-o", "json"}, `"listen_path": "/httpbin/"`},
+		{"yaml", []string{"-o", "yaml"}, "- name: httpbin\n  listen_path: /httpbin/\n  port: 8080\n  tags:\n    - test\n"},
+		{"yaml integers", []string{"-o", "yaml"}, "port: 1000000\n"},
+		{"jq", []string{"-o", "jq=.[].name"}, "httpbin\norders\n"},
+		{"jsonpath", []string{"--output", "jsonpath={[*].listen_path}"}, "/httpbin/\n/orders/\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runWithOutput(t, apis, tt.args...)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("output = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrint_Table(t *testing.T) {
+	got, err := runWithOutput(t, apis)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 3 || strings.Join(strings.Fields(lines[0]), " ") != "NAME LISTEN_PATH PORT" {
+		t.Errorf("table = %q, want the scalar fields in order", got)
+	}
+
+	got, err = runWithOutput(t, apis, "-o", "wide")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if header := strings.Fields(strings.Split(got, "\n")[0]); len(header) != 4 || header[3] != "TAGS" {
+		t.Errorf("wide table header = %v, want TAGS added", header)
+	}
+}
+
+type tabularAPIs []api
+
+func (a tabularAPIs) Table(wide bool) ([]string, [][]string) {
+	headers := []string{"API"}
+	if wide {
+		headers = append(headers, "PATH")
+	}
+	rows := make([][]string, len(a))
+	for i, api := range a {
+		rows[i] = []string{api.Name}
+		if wide {
+			rows[i] = append(rows[i], api.ListenPath)
+		}
+	}
+	return headers, rows
+}
+
+func TestPrint_Tabular(t *testing.T) {
+	got, err := runWithOutput(t, tabularAPIs(apis), "-o", "wide")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "API") || !strings.Contains(got, "/orders/") {
+		t.Errorf("output = %q, want the Tabular view", got)
+	}
+
+	got, err = runWithOutput(t, tabularAPIs(apis), "-o", "json")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(got, `"listen_path"`) {
+		t.Errorf("output = %q, want JSON of the data", got)
+	}
+}
+
+func TestPrint_Empty(t *testing.T) {
+	got, err := runWithOutput(t, []api{})
+	if err != nil || got != "" {
+		t.Errorf("output = %q, %v, want nothing", got, err)
+	}
+}
+
+func TestWithOutputFlag_Invalid(t *testing.T) {
+	ran := false
+	cmd := WithOutputFlag(&cobra.Command{
+		Use: "list",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ran = true
+			return nil
+		},
+	})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	for _, value := range []string{"xml", "jq=", "json=.name"} {
+		cmd.SetArgs([]string{"-o", value})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "unsupported output format") {
+			t.Errorf("-o %s: error = %v", value, err)
+		}
+	}
+	if ran {
+		t.Error("the command ran with an invalid output format")
+	}
+}
+
+func TestWithOutputFlag_NoRun(t *testing.T) {
+	cmd := WithOutputFlag(&cobra.Command{Use: "group"})
+	cmd.SetArgs([]string{"-o", "json"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "has no RunE or Run") {
+		t.Errorf("Execute() error = %v, want an error for the missing run function", err)
+	}
+}
+
+func TestPrint_WithoutFlag(t *testing.T) {
+	if err := Print(context.Background(), nil); err != nil {
+		t.Errorf("Print() error = %v", err)
+	}
+}
+
+func TestJSONPathToJQ(t *testing.T) {
+	tests := map[string]string{
+		"{.items[*].metadata.name}": ".items[].metadata.name",
+		"$.items[0]":                ".items[0]",
+		"name":                      ".name",
+		"{[*].id}":                  ".[].id",
+	}
+	for path, want := range tests {
+		if got := jsonPathToJQ(path); got != want {
+			t.Errorf("jsonPathToJQ(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"github.com/edsonmichaque/tykctl-go/config"
+	"github.com/spf13/cobra"
+)
+
+// PathsOptions configures the paths diagnostic command
+type PathsOptions struct {
+	// AppName is the directory name used under the XDG base directories
+	AppName string
+	// Extension is the extension whose discovery paths are reported
+	Extension string
+	// EnvPrefixes selects which environment variables are reported
+	EnvPrefixes []string
+}
+
+// PathsReport describes the effective installation layout
+type PathsReport struct {
+	Directories map[string]string   `json:"directories"`
+	Discovery   map[string][]string `json:"discovery"`
+	Environment map[string]string   `json:"environment"`
+}
+
+// NewPathsCommand creates a command that prints the effective directories,
+// discovery paths and relevant environment variables
+func NewPathsCommand(opts PathsOptions) *Command {
+	if opts.AppName == "" {
+		opts.AppName = "tykctl"
+	}
+	if len(opts.EnvPrefixes) == 0 {
+		opts.EnvPrefixes = []string{"TYKCTL_", "XDG_"}
+	}
+
+	cmd := NewWithLong(
+		"paths",
+		"Show effective directories and environment",
+		"Print the config, data, cache and state directories, the discovery paths for hooks, plugins and templates, and the environment variables that influence them.",
+		func(cmd *cobra.Command, args []string) error {
+			return Print(cmd.Context(), BuildPathsReport(cmd.Context(), opts))
+		},
+	)
+	cmd.Aliases = []string{"env"}
+	cmd.Args = cobra.NoArgs
+	WithOutputFlag(cmd.Command)
+
+	return cmd
+}
+
+// BuildPathsReport collects the effective installation layout. Values of
+// environment variables that look like secrets are redacted
+func BuildPathsReport(ctx context.Context, opts PathsOptions) *PathsReport {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	report := &PathsReport{
+		Directories: map[string]string{
+			"config": filepath.Join(xdg.ConfigHome, opts.AppName),
+			"data":   filepath.Join(xdg.DataHome, opts.AppName),
+			"cache":  filepath.Join(xdg.CacheHome, opts.AppName),
+			"state":  filepath.Join(xdg.StateHome, opts.AppName),
+		},
+		Discovery:   make(map[string][]string),
+		Environment: make(map[string]string),
+	}
+
+	if opts.Extension != "" {
+		report.Discovery["hooks"] = config.GetHookDiscoveryPaths(ctx, opts.Extension)
+		report.Discovery["plugins"] = config.GetPluginDiscoveryPaths(ctx, opts.Extension)
+		report.Discovery["templates"] = config.GetTemplateDiscoveryPaths(ctx, opts.Extension)
+	}
+
+	for _, env := range os.Environ() {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok {
+			continue
+		}
+		for _, prefix := range opts.EnvPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				report.Environment[key] = config.RedactEnv(key, value)
+				break
+			}
+		}
+	}
+
+	return report
+}
+
+// Table lists the directories, discovery paths and environment variables
+// of the report, one per row
+func (r *PathsReport) Table(wide bool) ([]string, [][]string) {
+	var rows [][]string
+	for _, name := range sortedKeys(r.Directories) {
+		rows = append(rows, []string{"directory", name, r.Directories[name]})
+	}
+
+	for _, name := range sortedKeys(r.Discovery) {
+		for _, path := range r.Discovery[name] {
+			rows = append(rows, []string{"discovery", name, path})
+		}
+	}
+
+	for _, name := range sortedKeys(r.Environment) {
+		rows = append(rows, []string{"environment", name, r.Environment[name]})
+	}
+
+	return []string{"CATEGORY", "NAME", "VALUE"}, rows
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
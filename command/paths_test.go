@@ -0,0 +1,85 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPathsCommandJSON(t *testing.T) {
+	t.Setenv("TYKCTL_HOME", "/tmp/tykctl-home")
+
+	cmd := NewPathsCommand(PathsOptions{Extension: "demo"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--output", "json"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+
+	var report PathsReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	for _, name := range []string{"config", "data", "cache", "state"} {
+		if !strings.HasSuffix(report.Directories[name], "tykctl") {
+			t.Errorf("Expected %s directory under tykctl, got '%s'", name, report.Directories[name])
+		}
+	}
+
+	if len(report.Discovery["hooks"]) == 0 {
+		t.Error("Expected hook discovery paths")
+	}
+
+	if report.Environment["TYKCTL_HOME"] != "/tmp/tykctl-home" {
+		t.Errorf("Expected TYKCTL_HOME in environment, got '%s'", report.Environment["TYKCTL_HOME"])
+	}
+}
+
+func TestBuildPathsReportRedactsSecrets(t *testing.T) {
+	t.Setenv("TYKCTL_HOME", "/tmp/tykctl-home")
+	t.Setenv("TYKCTL_API_TOKEN", "s3cr3t")
+	t.Setenv("TYKCTL_DASHBOARD_SECRET", "s3cr3t")
+	t.Setenv("XDG_AUTH_PASSWORD", "s3cr3t")
+
+	report := BuildPathsReport(context.Background(), PathsOptions{EnvPrefixes: []string{"TYKCTL_", "XDG_"}})
+
+	for _, key := range []string{"TYKCTL_API_TOKEN", "TYKCTL_DASHBOARD_SECRET", "XDG_AUTH_PASSWORD"} {
+		if report.Environment[key] != "[REDACTED]" {
+			t.Errorf("Expected %s to be redacted, got '%s'", key, report.Environment[key])
+		}
+	}
+	if report.Environment["TYKCTL_HOME"] != "/tmp/tykctl-home" {
+		t.Errorf("Expected TYKCTL_HOME to be kept, got '%s'", report.Environment["TYKCTL_HOME"])
+	}
+}
+
+func TestPathsCommandTable(t *testing.T) {
+	cmd := NewPathsCommand(PathsOptions{})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "CATEGORY") {
+		t.Errorf("Expected table headers in output, got:\n%s", buf.String())
+	}
+}
+
+func TestPathsCommandInvalidOutput(t *testing.T) {
+	cmd := NewPathsCommand(PathsOptions{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--output", "xml"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("Expected error for unsupported output format")
+	}
+}
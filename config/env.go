@@ -0,0 +1,19 @@
+package config
+
+import "strings"
+
+// sensitiveEnvMarkers identify environment variables whose values are redacted
+var sensitiveEnvMarkers = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "KEY", "AUTH", "CREDENTIAL", "COOKIE"}
+
+// RedactEnv returns value, or "[REDACTED]" when the name of the environment
+// variable suggests it holds a secret, such as TYKCTL_API_TOKEN, so
+// diagnostics can show the environment without leaking credentials
+func RedactEnv(key, value string) string {
+	upper := strings.ToUpper(key)
+	for _, marker := range sensitiveEnvMarkers {
+		if strings.Contains(upper, marker) {
+			return "[REDACTED]"
+		}
+	}
+	return value
+}
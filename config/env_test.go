@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestRedactEnv(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"TYKCTL_API_TOKEN", "[REDACTED]"},
+		{"TYKCTL_SECRET", "[REDACTED]"},
+		{"TYK_DB_PASSWORD", "[REDACTED]"},
+		{"TYKCTL_ApiKey", "[REDACTED]"},
+		{"TYKCTL_AUTH_HEADER", "[REDACTED]"},
+		{"TYKCTL_CONFIG", "value"},
+		{"XDG_CONFIG_HOME", "value"},
+	}
+
+	for _, tt := range tests {
+		if got := RedactEnv(tt.key, "value"); got != tt.expected {
+			t.Errorf("RedactEnv(%q) = %q, expected %q", tt.key, got, tt.expected)
+		}
+	}
+}
@@ -2,7 +2,10 @@ module github.com/edsonmichaque/tykctl-go/config
 
 go 1.25.1
 
-require github.com/adrg/xdg v0.5.3
+require (
+	github.com/adrg/xdg v0.5.3
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
@@ -0,0 +1,575 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintSeverity represents the severity of a lint issue
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityInfo    LintSeverity = "info"
+)
+
+// Lint issue codes
+const (
+	LintCodeUnknownKey        = "unknown_key"
+	LintCodeTypeMismatch      = "type_mismatch"
+	LintCodeDuplicateKey      = "duplicate_key"
+	LintCodeParseError        = "parse_error"
+	LintCodeUnsupportedFormat = "unsupported_format"
+)
+
+// LintIssue represents a single problem found while linting configuration
+type LintIssue struct {
+	Severity LintSeverity `json:"severity"`
+	Code     string       `json:"code"`
+	Key      string       `json:"key,omitempty"`
+	Source   string       `json:"source"`
+	Line     int          `json:"line,omitempty"`
+	Expected string       `json:"expected,omitempty"`
+	Actual   string       `json:"actual,omitempty"`
+	Message  string       `json:"message"`
+}
+
+// LintReport represents the result of linting configuration sources
+type LintReport struct {
+	Sources []string    `json:"sources"`
+	Issues  []LintIssue `json:"issues"`
+}
+
+// HasErrors reports whether the report contains error-level issues
+func (r *LintReport) HasErrors() bool {
+	return r.Count(LintSeverityError) > 0
+}
+
+// Count returns the number of issues with the given severity
+func (r *LintReport) Count(severity LintSeverity) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// WriteJSON writes the report as indented JSON for CI consumption
+func (r *LintReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteText writes the report in a human-readable form
+func (r *LintReport) WriteText(w io.Writer) error {
+	for _, issue := range r.Issues {
+		location := issue.Source
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", issue.Source, issue.Line)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: %s [%s]\n", location, issue.Severity, issue.Message, issue.Code); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d error(s), %d warning(s), %d info\n",
+		r.Count(LintSeverityError), r.Count(LintSeverityWarning), r.Count(LintSeverityInfo))
+	return err
+}
+
+// lintField describes the expected type of a configuration key
+type lintField struct {
+	typ reflect.Type
+}
+
+// lintValue records where a key was defined
+type lintValue struct {
+	source string
+	line   int
+}
+
+// Lint inspects every configuration source the loader would consider and
+// reports unknown keys, type mismatches and keys defined in more than one
+// source. Keys are checked against target, which should be a struct or a
+// pointer to one; a nil target only reports parse errors and duplicates.
+func (l *Loader) Lint(ctx context.Context, target interface{}) (*LintReport, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	schema := make(map[string]lintField)
+	if target != nil {
+		t := reflect.TypeOf(target)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("lint target must be a struct, got %T", target)
+		}
+		buildLintSchema(t, "", schema)
+	}
+
+	report := &LintReport{
+		Sources: []string{},
+		Issues:  []LintIssue{},
+	}
+	defined := make(map[string][]lintValue)
+
+	seen := make(map[string]bool)
+	for _, path := range l.configPaths {
+		for _, format := range l.configFormats {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			configFile := filepath.Join(path, fmt.Sprintf("config.%s", format))
+			if seen[configFile] {
+				continue
+			}
+			seen[configFile] = true
+
+			if _, err := os.Stat(configFile); err != nil {
+				continue
+			}
+
+			report.Sources = append(report.Sources, configFile)
+			l.lintFile(configFile, format, schema, target != nil, report, defined)
+		}
+	}
+
+	if l.envPrefix != "" {
+		report.Sources = append(report.Sources, "env:"+l.envPrefix+"_*")
+		l.lintEnv(schema, target != nil, report, defined)
+	}
+
+	keys := make([]string, 0, len(defined))
+	for key := range defined {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		values := defined[key]
+		if len(values) < 2 {
+			continue
+		}
+		sources := make([]string, len(values))
+		for i, value := range values {
+			sources[i] = value.source
+		}
+		report.Issues = append(report.Issues, LintIssue{
+			Severity: LintSeverityWarning,
+			Code:     LintCodeDuplicateKey,
+			Key:      key,
+			Source:   values[len(values)-1].source,
+			Line:     values[len(values)-1].line,
+			Message:  fmt.Sprintf("key %q is defined in multiple sources: %s", key, strings.Join(sources, ", ")),
+		})
+	}
+
+	return report, nil
+}
+
+// lintFile checks a single configuration file
+func (l *Loader) lintFile(path, format string, schema map[string]lintField, strict bool, report *LintReport, defined map[string][]lintValue) {
+	if format != "yaml" && format != "yml" && format != "json" {
+		report.Issues = append(report.Issues, LintIssue{
+			Severity: LintSeverityInfo,
+			Code:     LintCodeUnsupportedFormat,
+			Source:   path,
+			Message:  fmt.Sprintf("linting %s files is not supported", format),
+		})
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		report.Issues = append(report.Issues, LintIssue{
+			Severity: LintSeverityError,
+			Code:     LintCodeParseError,
+			Source:   path,
+			Message:  fmt.Sprintf("failed to read file: %v", err),
+		})
+		return
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		report.Issues = append(report.Issues, LintIssue{
+			Severity: LintSeverityError,
+			Code:     LintCodeParseError,
+			Source:   path,
+			Message:  fmt.Sprintf("failed to parse file: %v", err),
+		})
+		return
+	}
+
+	if len(doc.Content) == 0 {
+		return
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		report.Issues = append(report.Issues, LintIssue{
+			Severity: LintSeverityError,
+			Code:     LintCodeTypeMismatch,
+			Source:   path,
+			Line:     root.Line,
+			Expected: "mapping",
+			Actual:   nodeKindName(root),
+			Message:  "top-level configuration must be a mapping",
+		})
+		return
+	}
+
+	lintMapping(root, "", path, schema, strict, report, defined)
+}
+
+// lintMapping walks a mapping node, checking each key against the schema
+func lintMapping(node *yaml.Node, prefix, source string, schema map[string]lintField, strict bool, report *LintReport, defined map[string][]lintValue) {
+	keysInNode := make(map[string]int)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := keyNode.Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if line, ok := keysInNode[key]; ok {
+			report.Issues = append(report.Issues, LintIssue{
+				Severity: LintSeverityError,
+				Code:     LintCodeDuplicateKey,
+				Key:      key,
+				Source:   source,
+				Line:     keyNode.Line,
+				Message:  fmt.Sprintf("key %q is already defined on line %d", key, line),
+			})
+			continue
+		}
+		keysInNode[key] = keyNode.Line
+
+		field, known := schema[key]
+		if !known && strict {
+			if !hasOpenParent(key, schema) {
+				report.Issues = append(report.Issues, LintIssue{
+					Severity: LintSeverityWarning,
+					Code:     LintCodeUnknownKey,
+					Key:      key,
+					Source:   source,
+					Line:     keyNode.Line,
+					Message:  fmt.Sprintf("unknown key %q", key),
+				})
+			}
+			continue
+		}
+
+		if known && field.typ.Kind() == reflect.Struct && !isScalarType(field.typ) && valueNode.Kind == yaml.MappingNode {
+			lintMapping(valueNode, key, source, schema, strict, report, defined)
+			continue
+		}
+
+		if !known && valueNode.Kind == yaml.MappingNode {
+			lintMapping(valueNode, key, source, schema, strict, report, defined)
+			continue
+		}
+
+		defined[key] = append(defined[key], lintValue{source: source, line: keyNode.Line})
+
+		if known {
+			if expected, ok := checkNodeType(valueNode, field.typ); !ok {
+				report.Issues = append(report.Issues, LintIssue{
+					Severity: LintSeverityError,
+					Code:     LintCodeTypeMismatch,
+					Key:      key,
+					Source:   source,
+					Line:     valueNode.Line,
+					Expected: expected,
+					Actual:   nodeKindName(valueNode),
+					Message:  fmt.Sprintf("key %q expects %s, got %s", key, expected, nodeKindName(valueNode)),
+				})
+			}
+		}
+	}
+}
+
+// lintEnv checks environment variables carrying the loader's prefix
+func (l *Loader) lintEnv(schema map[string]lintField, strict bool, report *LintReport, defined map[string][]lintValue) {
+	envKeys := make(map[string]string, len(schema))
+	for key := range schema {
+		envKeys[strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))] = key
+	}
+
+	prefix := l.envPrefix + "_"
+	for _, env := range os.Environ() {
+		name, value, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		source := "env:" + name
+		key, known := envKeys[strings.TrimPrefix(name, prefix)]
+		if !known {
+			if strict {
+				report.Issues = append(report.Issues, LintIssue{
+					Severity: LintSeverityWarning,
+					Code:     LintCodeUnknownKey,
+					Key:      strings.ToLower(strings.TrimPrefix(name, prefix)),
+					Source:   source,
+					Message:  fmt.Sprintf("environment variable %s does not match any known key", name),
+				})
+			}
+			continue
+		}
+
+		defined[key] = append(defined[key], lintValue{source: source})
+
+		if expected, ok := checkStringType(value, schema[key].typ); !ok {
+			report.Issues = append(report.Issues, LintIssue{
+				Severity: LintSeverityError,
+				Code:     LintCodeTypeMismatch,
+				Key:      key,
+				Source:   source,
+				Expected: expected,
+				Actual:   value,
+				Message:  fmt.Sprintf("environment variable %s expects %s, got %q", name, expected, value),
+			})
+		}
+	}
+}
+
+// buildLintSchema flattens a struct type into dotted keys
+func buildLintSchema(t reflect.Type, prefix string, schema map[string]lintField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, squash := lintFieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if squash && fieldType.Kind() == reflect.Struct {
+			buildLintSchema(fieldType, prefix, schema)
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		schema[key] = lintField{typ: fieldType}
+
+		if fieldType.Kind() == reflect.Struct && !isScalarType(fieldType) {
+			buildLintSchema(fieldType, key, schema)
+		}
+	}
+}
+
+// lintFieldName returns the configuration key of a struct field, following
+// the mapstructure, yaml and json tags in that order
+func lintFieldName(field reflect.StructField) (string, bool) {
+	for _, tagName := range []string{"mapstructure", "yaml", "json"} {
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		squash := false
+		for _, opt := range parts[1:] {
+			if opt == "squash" || opt == "inline" {
+				squash = true
+			}
+		}
+		if parts[0] != "" || squash {
+			return parts[0], squash
+		}
+	}
+
+	if field.Anonymous {
+		return "", true
+	}
+	return strings.ToLower(field.Name), false
+}
+
+// hasOpenParent reports whether key sits below a map or interface field,
+// whose children cannot be known in advance
+func hasOpenParent(key string, schema map[string]lintField) bool {
+	for {
+		idx := strings.LastIndex(key, ".")
+		if idx < 0 {
+			return false
+		}
+		key = key[:idx]
+		if field, ok := schema[key]; ok {
+			kind := field.typ.Kind()
+			return kind == reflect.Map || kind == reflect.Interface
+		}
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+var timeType = reflect.TypeOf(time.Time{})
+
+// isScalarType reports whether a struct type is decoded from a scalar value
+func isScalarType(t reflect.Type) bool {
+	return t == timeType
+}
+
+// checkNodeType reports whether a YAML node can be decoded into t
+func checkNodeType(node *yaml.Node, t reflect.Type) (string, bool) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return "", true
+	}
+
+	if unit, ok := unitTypes[t]; ok {
+		if node.Kind == yaml.ScalarNode && unit.parse(node.Value) == nil {
+			return "", true
+		}
+		return unit.name, false
+	}
+
+	switch {
+	case t == durationType:
+		if node.Kind == yaml.ScalarNode {
+			if node.Tag == "!!int" {
+				return "", true
+			}
+			if _, err := time.ParseDuration(node.Value); err == nil {
+				return "", true
+			}
+		}
+		return "duration", false
+	case t == timeType:
+		if node.Kind == yaml.ScalarNode && (node.Tag == "!!timestamp" || node.Tag == "!!str") {
+			return "", true
+		}
+		return "timestamp", false
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return "", true
+	case reflect.String:
+		return "string", node.Kind == yaml.ScalarNode && node.Tag == "!!str"
+	case reflect.Bool:
+		return "bool", node.Kind == yaml.ScalarNode && node.Tag == "!!bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer", node.Kind == yaml.ScalarNode && node.Tag == "!!int"
+	case reflect.Float32, reflect.Float64:
+		return "number", node.Kind == yaml.ScalarNode && (node.Tag == "!!float" || node.Tag == "!!int")
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yaml.SequenceNode {
+			return "list", false
+		}
+		elem := t.Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		for _, item := range node.Content {
+			if expected, ok := checkNodeType(item, elem); !ok {
+				return "list of " + expected, false
+			}
+		}
+		return "", true
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return "mapping", false
+		}
+		elem := t.Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			if expected, ok := checkNodeType(node.Content[i], elem); !ok {
+				return "mapping of " + expected, false
+			}
+		}
+		return "", true
+	case reflect.Struct:
+		return "mapping", node.Kind == yaml.MappingNode
+	}
+
+	return t.String(), true
+}
+
+// checkStringType reports whether an environment value can be parsed as t
+func checkStringType(value string, t reflect.Type) (string, bool) {
+	if unit, ok := unitTypes[t]; ok {
+		return unit.name, unit.parse(value) == nil
+	}
+	if t == durationType {
+		_, err := time.ParseDuration(value)
+		return "duration", err == nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		_, err := strconv.ParseBool(value)
+		return "bool", err == nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err := strconv.ParseInt(value, 10, 64)
+		return "integer", err == nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err := strconv.ParseUint(value, 10, 64)
+		return "integer", err == nil
+	case reflect.Float32, reflect.Float64:
+		_, err := strconv.ParseFloat(value, 64)
+		return "number", err == nil
+	}
+
+	return "", true
+}
+
+// nodeKindName returns a readable name for the type of a YAML node
+func nodeKindName(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "list"
+	case yaml.AliasNode:
+		return "alias"
+	}
+
+	switch node.Tag {
+	case "!!str":
+		return "string"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "bool"
+	case "!!null":
+		return "null"
+	case "!!timestamp":
+		return "timestamp"
+	}
+	return strings.TrimPrefix(node.Tag, "!!")
+}
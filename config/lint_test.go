@@ -0,0 +1,141 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type lintTestConfig struct {
+	URL      string            `mapstructure:"url"`
+	Retries  int               `mapstructure:"retries"`
+	Timeout  time.Duration     `mapstructure:"timeout"`
+	Labels   map[string]string `mapstructure:"labels"`
+	Database struct {
+		Host string `mapstructure:"host"`
+		Port int    `mapstructure:"port"`
+	} `mapstructure:"database"`
+}
+
+func TestLoaderLint(t *testing.T) {
+	dir := t.TempDir()
+	override := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "config.yaml"), `
+url: https://example.com
+retries: many
+timeout: 5s
+labels:
+  team: platform
+database:
+  host: localhost
+  port: 5432
+  user: admin
+unknown: true
+`)
+	writeFile(t, filepath.Join(override, "config.json"), `{"url": "https://override.example.com", "timeout": "soon"}`)
+
+	t.Setenv("LINTTEST_RETRIES", "three")
+	t.Setenv("LINTTEST_BOGUS", "1")
+
+	loader, err := NewLoader(context.Background(), LoaderOptions{
+		EnvPrefix:     "LINTTEST",
+		ConfigFormats: []string{"yaml", "json"},
+		ConfigPaths:   []string{dir, override},
+	})
+	if err != nil {
+		t.Fatalf("NewLoader failed: %v", err)
+	}
+	defer loader.Close()
+
+	report, err := loader.Lint(context.Background(), &lintTestConfig{})
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"unknown":       LintCodeUnknownKey,
+		"database.user": LintCodeUnknownKey,
+		"bogus":         LintCodeUnknownKey,
+		"url":           LintCodeDuplicateKey,
+	}
+	for key, code := range expected {
+		if !hasLintIssue(report, key, code) {
+			t.Errorf("Expected %s issue for key %q, got %+v", code, key, report.Issues)
+		}
+	}
+
+	mismatches := 0
+	for _, issue := range report.Issues {
+		if issue.Code == LintCodeTypeMismatch {
+			mismatches++
+		}
+		if issue.Key == "labels.team" {
+			t.Errorf("Keys below map fields should not be reported: %+v", issue)
+		}
+	}
+	// retries (file), timeout (json) and retries (env)
+	if mismatches != 3 {
+		t.Errorf("Expected 3 type mismatches, got %d: %+v", mismatches, report.Issues)
+	}
+
+	if !report.HasErrors() {
+		t.Error("Expected report to have errors")
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var decoded LintReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Report is not valid JSON: %v", err)
+	}
+	if len(decoded.Issues) != len(report.Issues) {
+		t.Errorf("Expected %d issues in JSON output, got %d", len(report.Issues), len(decoded.Issues))
+	}
+}
+
+func TestLoaderLintDuplicateKeysInFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config.yaml"), "url: a\nurl: b\n")
+
+	loader, err := NewLoader(context.Background(), LoaderOptions{
+		EnvPrefix:     "LINTTEST_DUP",
+		ConfigFormats: []string{"yaml"},
+		ConfigPaths:   []string{dir},
+	})
+	if err != nil {
+		t.Fatalf("NewLoader failed: %v", err)
+	}
+	defer loader.Close()
+
+	report, err := loader.Lint(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+
+	if !hasLintIssue(report, "url", LintCodeDuplicateKey) || !report.HasErrors() {
+		t.Errorf("Expected duplicate key error, got %+v", report.Issues)
+	}
+}
+
+func hasLintIssue(report *LintReport, key, code string) bool {
+	for _, issue := range report.Issues {
+		if issue.Key == key && issue.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,420 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration that is written in configuration files with an
+// explicit unit, such as "30s", "5m", "1h30m" or "7d"
+type Duration time.Duration
+
+// ParseDuration parses a duration with units. In addition to the units
+// accepted by time.ParseDuration, leading whole weeks ("w") and days ("d")
+// are accepted. A bare number is rejected unless it is zero, since its unit
+// would be ambiguous
+func ParseDuration(s string) (Duration, error) {
+	value := strings.TrimSpace(s)
+	if value == "" {
+		return 0, fmt.Errorf("invalid duration %q: value is empty", s)
+	}
+
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		if n == 0 {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("invalid duration %q: missing unit, e.g. \"%ss\"", s, value)
+	}
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+
+	var total time.Duration
+	for _, unit := range []struct {
+		suffix byte
+		size   time.Duration
+	}{
+		{'w', 7 * 24 * time.Hour},
+		{'d', 24 * time.Hour},
+	} {
+		digits := 0
+		for digits < len(value) && value[digits] >= '0' && value[digits] <= '9' {
+			digits++
+		}
+		if digits == 0 || digits >= len(value) || value[digits] != unit.suffix {
+			continue
+		}
+
+		n, err := strconv.ParseInt(value[:digits], 10, 64)
+		if err != nil || n > int64(math.MaxInt64/unit.size) {
+			return 0, fmt.Errorf("invalid duration %q: value out of range", s)
+		}
+		total += time.Duration(n) * unit.size
+		value = value[digits+1:]
+	}
+
+	if value != "" {
+		if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: expected a number with a unit such as s, m, h or d", s)
+		}
+		total += d
+	}
+
+	if total < 0 {
+		return 0, fmt.Errorf("invalid duration %q: value out of range", s)
+	}
+	if negative {
+		total = -total
+	}
+	return Duration(total), nil
+}
+
+// Duration returns the value as a time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String returns the duration in time.Duration notation
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// UnmarshalJSON accepts a JSON string such as "30s"
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	return d.UnmarshalText(unquoteJSON(data))
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: expected a duration, got %s", node.Line, nodeKindName(node))
+	}
+	return d.UnmarshalText([]byte(node.Value))
+}
+
+// ByteSize is a number of bytes that is written in configuration files with
+// an optional unit, such as "512", "10MB" or "1Gi"
+type ByteSize int64
+
+// Byte size units. The SI units are powers of 1000 and the IEC units are
+// powers of 1024
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+)
+
+// byteSizeUnits maps lower-case unit names to their size
+var byteSizeUnits = map[string]ByteSize{
+	"": Byte, "b": Byte,
+	"k": KB, "kb": KB,
+	"m": MB, "mb": MB,
+	"g": GB, "gb": GB,
+	"t": TB, "tb": TB,
+	"p": PB, "pb": PB,
+	"ki": KiB, "kib": KiB,
+	"mi": MiB, "mib": MiB,
+	"gi": GiB, "gib": GiB,
+	"ti": TiB, "tib": TiB,
+	"pi": PiB, "pib": PiB,
+}
+
+// byteSizeFormats lists the units used by ByteSize.String, largest first
+var byteSizeFormats = []struct {
+	name string
+	size ByteSize
+}{
+	{"PiB", PiB}, {"PB", PB},
+	{"TiB", TiB}, {"TB", TB},
+	{"GiB", GiB}, {"GB", GB},
+	{"MiB", MiB}, {"MB", MB},
+	{"KiB", KiB}, {"KB", KB},
+}
+
+// ParseByteSize parses a byte count with an optional unit. Units are case
+// insensitive; "K", "KB" and "kb" are 1000 bytes while "Ki" and "KiB" are
+// 1024 bytes. A bare number is a count of bytes
+func ParseByteSize(s string) (ByteSize, error) {
+	value := strings.TrimSpace(s)
+
+	end := 0
+	for end < len(value) && (value[end] >= '0' && value[end] <= '9' || value[end] == '.') {
+		end++
+	}
+	if end == 0 {
+		return 0, fmt.Errorf("invalid byte size %q: expected a number with an optional unit such as KB, MiB or Gi", s)
+	}
+
+	n, err := strconv.ParseFloat(value[:end], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q: expected a number with an optional unit such as KB, MiB or Gi", s)
+	}
+
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(value[end:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", s, strings.TrimSpace(value[end:]))
+	}
+
+	size := math.Round(n * float64(unit))
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q: value out of range", s)
+	}
+	return ByteSize(size), nil
+}
+
+// Int64 returns the number of bytes
+func (b ByteSize) Int64() int64 {
+	return int64(b)
+}
+
+// String returns the size using the largest unit that represents it exactly,
+// such as "10MB" or "1GiB", falling back to a plain count of bytes
+func (b ByteSize) String() string {
+	if b != 0 {
+		for _, format := range byteSizeFormats {
+			if b%format.size == 0 {
+				return strconv.FormatInt(int64(b/format.size), 10) + format.name
+			}
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	parsed, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+// UnmarshalJSON accepts a JSON number of bytes or a string such as "10MB"
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	return b.UnmarshalText(unquoteJSON(data))
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (b ByteSize) MarshalYAML() (interface{}, error) {
+	return b.String(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (b *ByteSize) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: expected a byte size, got %s", node.Line, nodeKindName(node))
+	}
+	return b.UnmarshalText([]byte(node.Value))
+}
+
+// unquoteJSON returns the contents of a JSON string, or the raw value for
+// any other JSON token
+func unquoteJSON(data []byte) []byte {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return []byte(s)
+	}
+	return data
+}
+
+// unitType describes a value type parsed from a string with units
+type unitType struct {
+	name  string
+	parse func(string) error
+}
+
+var unitTypes = map[reflect.Type]unitType{
+	reflect.TypeOf(Duration(0)): {
+		name: "duration",
+		parse: func(s string) error {
+			_, err := ParseDuration(s)
+			return err
+		},
+	},
+	reflect.TypeOf(ByteSize(0)): {
+		name: "byte size",
+		parse: func(s string) error {
+			_, err := ParseByteSize(s)
+			return err
+		},
+	},
+}
+
+// UnitError reports a configuration value that could not be parsed as a
+// Duration or ByteSize
+type UnitError struct {
+	Key    string
+	Source string
+	Line   int
+	Value  string
+	Err    error
+}
+
+// Error returns the error message, prefixed with the source location and key
+func (e *UnitError) Error() string {
+	location := e.Source
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", e.Source, e.Line)
+	}
+	return fmt.Sprintf("%s: key %q: %v", location, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying parse error
+func (e *UnitError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeFile decodes a YAML or JSON configuration file into target. Every
+// Duration and ByteSize field is checked first, and all invalid values are
+// reported together as UnitErrors citing the key, file and line. Fields are
+// matched by their yaml tags for YAML files and json tags for JSON files
+func DecodeFile(path string, target interface{}) error {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return fmt.Errorf("decode target must be a pointer, got %T", target)
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if t.Kind() == reflect.Struct && len(node.Content) > 0 {
+		schema := make(map[string]lintField)
+		buildLintSchema(t, "", schema)
+
+		var errs []error
+		checkUnits(node.Content[0], "", path, schema, &errs)
+		if len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, target)
+	default:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		err = node.Decode(target)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// checkUnits walks a mapping node and records a UnitError for every value
+// that cannot be parsed into its Duration or ByteSize field
+func checkUnits(node *yaml.Node, prefix, source string, schema map[string]lintField, errs *[]error) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		valueNode := node.Content[i+1]
+		if valueNode.Kind == yaml.AliasNode && valueNode.Alias != nil {
+			valueNode = valueNode.Alias
+		}
+
+		field, known := schema[key]
+		if !known {
+			continue
+		}
+
+		unit, ok := unitTypes[field.typ]
+		if !ok {
+			checkUnits(valueNode, key, source, schema, errs)
+			continue
+		}
+
+		if valueNode.Kind == yaml.ScalarNode && valueNode.Tag == "!!null" {
+			continue
+		}
+		if valueNode.Kind != yaml.ScalarNode {
+			*errs = append(*errs, &UnitError{
+				Key:    key,
+				Source: source,
+				Line:   valueNode.Line,
+				Err:    fmt.Errorf("expected a %s, got %s", unit.name, nodeKindName(valueNode)),
+			})
+			continue
+		}
+		if err := unit.parse(valueNode.Value); err != nil {
+			*errs = append(*errs, &UnitError{
+				Key:    key,
+				Source: source,
+				Line:   valueNode.Line,
+				Value:  valueNode.Value,
+				Err:    err,
+			})
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type unitsTestConfig struct {
+	Timeout  Duration `yaml:"timeout" json:"timeout"`
+	MaxBody  ByteSize `yaml:"max_body" json:"max_body"`
+	Upstream struct {
+		Retry     Duration `yaml:"retry" json:"retry"`
+		BufferCap ByteSize `yaml:"buffer" json:"buffer"`
+	} `yaml:"upstream" json:"upstream"`
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"30s", 30 * time.Second, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"-5m", -5 * time.Minute, false},
+		{"0", 0, false},
+		{"30", 0, true},
+		{"30x", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDuration(%q): expected error %v, got %v", tt.input, tt.wantErr, err)
+			continue
+		}
+		if got.Duration() != tt.expected {
+			t.Errorf("ParseDuration(%q): expected %v, got %v", tt.input, tt.expected, got.Duration())
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ByteSize
+		wantErr  bool
+	}{
+		{"512", 512, false},
+		{"10MB", 10 * MB, false},
+		{"10mb", 10 * MB, false},
+		{"1Gi", GiB, false},
+		{"512KiB", 512 * KiB, false},
+		{"1.5 GB", 1500 * MB, false},
+		{"2k", 2 * KB, false},
+		{"10XB", 0, true},
+		{"MB", 0, true},
+		{"-1MB", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseByteSize(%q): expected error %v, got %v", tt.input, tt.wantErr, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseByteSize(%q): expected %d, got %d", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	tests := map[ByteSize]string{
+		0:         "0B",
+		100:       "100B",
+		2 * KB:    "2KB",
+		2 * KiB:   "2KiB",
+		10 * MB:   "10MB",
+		GiB:       "1GiB",
+		1500 * MB: "1500MB",
+	}
+
+	for size, expected := range tests {
+		if got := size.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+		parsed, err := ParseByteSize(size.String())
+		if err != nil || parsed != size {
+			t.Errorf("Expected %q to round-trip to %d, got %d (%v)", size.String(), size, parsed, err)
+		}
+	}
+}
+
+func TestUnitsUnmarshal(t *testing.T) {
+	var fromYAML unitsTestConfig
+	err := yaml.Unmarshal([]byte("timeout: 30s\nmax_body: 10MB\nupstream:\n  retry: 1d\n  buffer: 1Gi\n"), &fromYAML)
+	if err != nil {
+		t.Fatalf("yaml.Unmarshal failed: %v", err)
+	}
+	if fromYAML.Timeout.Duration() != 30*time.Second || fromYAML.MaxBody != 10*MB ||
+		fromYAML.Upstream.Retry.Duration() != 24*time.Hour || fromYAML.Upstream.BufferCap != GiB {
+		t.Errorf("Unexpected YAML result: %+v", fromYAML)
+	}
+
+	var fromJSON unitsTestConfig
+	if err := json.Unmarshal([]byte(`{"timeout": "2m", "max_body": 4096}`), &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if fromJSON.Timeout.Duration() != 2*time.Minute || fromJSON.MaxBody != 4096 {
+		t.Errorf("Unexpected JSON result: %+v", fromJSON)
+	}
+
+	out, err := yaml.Marshal(fromYAML)
+	if err != nil {
+		t.Fatalf("yaml.Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(out), "max_body: 10MB") || !strings.Contains(string(out), "buffer: 1GiB") {
+		t.Errorf("Expected units in marshalled YAML, got:\n%s", out)
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.yaml")
+	writeFile(t, valid, "timeout: 30s\nmax_body: 10MB\nupstream:\n  buffer: 512KiB\n")
+
+	var cfg unitsTestConfig
+	if err := DecodeFile(valid, &cfg); err != nil {
+		t.Fatalf("DecodeFile failed: %v", err)
+	}
+	if cfg.Timeout.Duration() != 30*time.Second || cfg.Upstream.BufferCap != 512*KiB {
+		t.Errorf("Unexpected result: %+v", cfg)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	writeFile(t, invalid, "timeout: 30\nmax_body: 10MB\nupstream:\n  buffer: lots\n")
+
+	err := DecodeFile(invalid, &cfg)
+	if err == nil {
+		t.Fatal("Expected an error for invalid units")
+	}
+
+	var unitErr *UnitError
+	if !errors.As(err, &unitErr) {
+		t.Fatalf("Expected a UnitError, got %T", err)
+	}
+	if unitErr.Key != "timeout" || unitErr.Source != invalid || unitErr.Line != 1 {
+		t.Errorf("Expected timeout error at %s:1, got %+v", invalid, unitErr)
+	}
+	for _, want := range []string{invalid + ":1: key \"timeout\"", invalid + ":4: key \"upstream.buffer\""} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got %q", want, err.Error())
+		}
+	}
+
+	jsonFile := filepath.Join(dir, "config.json")
+	writeFile(t, jsonFile, `{"timeout": "1h", "max_body": "2Mi"}`)
+	cfg = unitsTestConfig{}
+	if err := DecodeFile(jsonFile, &cfg); err != nil {
+		t.Fatalf("DecodeFile failed: %v", err)
+	}
+	if cfg.Timeout.Duration() != time.Hour || cfg.MaxBody != 2*MiB {
+		t.Errorf("Unexpected JSON result: %+v", cfg)
+	}
+}
+
+func TestLoaderLintUnits(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config.yaml"), "timeout: 30s\nmax_body: 10 parsecs\n")
+	t.Setenv("UNITTEST_TIMEOUT", "5")
+
+	loader, err := NewLoader(context.Background(), LoaderOptions{
+		EnvPrefix:     "UNITTEST",
+		ConfigFormats: []string{"yaml"},
+		ConfigPaths:   []string{dir},
+	})
+	if err != nil {
+		t.Fatalf("NewLoader failed: %v", err)
+	}
+	defer loader.Close()
+
+	report, err := loader.Lint(context.Background(), &unitsTestConfig{})
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+
+	mismatches := make(map[string]string)
+	for _, issue := range report.Issues {
+		if issue.Code == LintCodeTypeMismatch {
+			mismatches[issue.Key] = issue.Expected
+		}
+	}
+	if mismatches["max_body"] != "byte size" {
+		t.Errorf("Expected byte size mismatch for max_body, got %q", mismatches["max_body"])
+	}
+	if mismatches["timeout"] != "duration" {
+		t.Errorf("Expected duration mismatch for the timeout env var, got %q", mismatches["timeout"])
+	}
+}
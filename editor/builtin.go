@@ -0,0 +1,331 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// BuiltinEditor names the embedded terminal editor. It is used when no
+// editor is configured and none of fallbackEditors is installed, as in
+// minimal containers, and can be chosen with TYKCTL_EDITOR=builtin
+const BuiltinEditor = "builtin"
+
+// ErrNoEditor is returned when the embedded editor is needed but input or
+// output is not a terminal
+var ErrNoEditor = errors.New("no editor found: set EDITOR or TYKCTL_EDITOR")
+
+// builtinHelp lists the keys of the embedded editor
+const builtinHelp = "^S save · ^Q quit"
+
+// fallbackEditors are tried in order when no editor is configured
+func fallbackEditors() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vim", "vi", "nano"}
+}
+
+// findEditor returns the first fallback editor installed, or BuiltinEditor
+func findEditor() string {
+	for _, name := range fallbackEditors() {
+		if _, err := exec.LookPath(name); err == nil {
+			return name
+		}
+	}
+	return BuiltinEditor
+}
+
+// editBuiltin edits filename with the embedded editor
+func editBuiltin(ctx context.Context, filename string) error {
+	if !terminal.IsTerminal(os.Stdin.Fd()) || !terminal.IsTerminal(os.Stdout.Fd()) {
+		return ErrNoEditor
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	model := newBuiltinModel(filename, string(data))
+	options := append([]tea.ProgramOption{tea.WithContext(ctx)}, terminal.AltScreenOptions()...)
+	if _, err := tea.NewProgram(model, options...).Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return fmt.Errorf("failed to run editor: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return model.err
+}
+
+// builtinModel is the Bubble Tea model of the embedded editor, a plain
+// text area saved with ctrl+s
+type builtinModel struct {
+	filename string
+	lines    [][]rune
+
+	// row and col are the cursor, and top and left the first line and
+	// column shown
+	row, col  int
+	top, left int
+
+	width  int
+	height int
+
+	dirty bool
+	// quitting is set by ctrl+q with unsaved changes, which must be
+	// pressed again to discard them
+	quitting bool
+	message  string
+	err      error
+}
+
+// newBuiltinModel returns an editor of content, which is saved to filename
+func newBuiltinModel(filename, content string) *builtinModel {
+	m := &builtinModel{filename: filename, width: 80, height: 24}
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		m.lines = append(m.lines, []rune(line))
+	}
+	return m
+}
+
+// Init starts the editor
+func (m *builtinModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles keys and resizes
+func (m *builtinModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		cmd := m.key(msg)
+		m.follow()
+		return m, cmd
+	}
+	return m, nil
+}
+
+// key handles a key
+func (m *builtinModel) key(msg tea.KeyMsg) tea.Cmd {
+	quitting := m.quitting
+	m.quitting, m.message = false, ""
+
+	switch msg.Type {
+	case tea.KeyCtrlS:
+		m.save()
+	case tea.KeyCtrlQ, tea.KeyCtrlC:
+		if m.dirty && !quitting {
+			m.quitting = true
+			m.message = "Unsaved changes, press ^Q again to discard them"
+			return nil
+		}
+		return tea.Quit
+	case tea.KeyUp:
+		m.moveTo(m.row-1, m.col)
+	case tea.KeyDown:
+		m.moveTo(m.row+1, m.col)
+	case tea.KeyLeft:
+		if m.col > 0 {
+			m.col--
+		} else if m.row > 0 {
+			m.moveTo(m.row-1, len(m.lines[m.row-1]))
+		}
+	case tea.KeyRight:
+		if m.col < len(m.lines[m.row]) {
+			m.col++
+		} else if m.row < len(m.lines)-1 {
+			m.moveTo(m.row+1, 0)
+		}
+	case tea.KeyHome, tea.KeyCtrlA:
+		m.col = 0
+	case tea.KeyEnd, tea.KeyCtrlE:
+		m.col = len(m.lines[m.row])
+	case tea.KeyPgUp:
+		m.moveTo(m.row-m.page(), m.col)
+	case tea.KeyPgDown:
+		m.moveTo(m.row+m.page(), m.col)
+	case tea.KeyEnter:
+		m.insert([]rune{'\n'})
+	case tea.KeyTab:
+		m.insert([]rune("  "))
+	case tea.KeyBackspace:
+		m.backspace()
+	case tea.KeyDelete:
+		m.delete()
+	case tea.KeySpace:
+		m.insert([]rune{' '})
+	case tea.KeyRunes:
+		m.insert(msg.Runes)
+	}
+	return nil
+}
+
+// moveTo moves the cursor, keeping it within the text
+func (m *builtinModel) moveTo(row, col int) {
+	m.row = clamp(row, 0, len(m.lines)-1)
+	m.col = clamp(col, 0, len(m.lines[m.row]))
+}
+
+// insert types runes at the cursor, splitting lines at newlines as pasted
+// text may hold them
+func (m *builtinModel) insert(runes []rune) {
+	for _, r := range runes {
+		line := m.lines[m.row]
+		if r == '\r' {
+			continue
+		}
+		if r == '\n' {
+			rest := append([]rune(nil), line[m.col:]...)
+			m.lines[m.row] = line[:m.col]
+			m.lines = append(m.lines[:m.row+1], append([][]rune{rest}, m.lines[m.row+1:]...)...)
+			m.row, m.col = m.row+1, 0
+			continue
+		}
+		line = append(line[:m.col], append([]rune{r}, line[m.col:]...)...)
+		m.lines[m.row] = line
+		m.col++
+	}
+	m.dirty = true
+}
+
+// backspace removes the rune before the cursor, joining lines at the start
+// of one
+func (m *builtinModel) backspace() {
+	switch {
+	case m.col > 0:
+		line := m.lines[m.row]
+		m.lines[m.row] = append(line[:m.col-1], line[m.col:]...)
+		m.col--
+	case m.row > 0:
+		prev := m.lines[m.row-1]
+		m.col = len(prev)
+		m.lines[m.row-1] = append(prev, m.lines[m.row]...)
+		m.lines = append(m.lines[:m.row], m.lines[m.row+1:]...)
+		m.row--
+	default:
+		return
+	}
+	m.dirty = true
+}
+
+// delete removes the rune under the cursor, joining the next line at the
+// end of one
+func (m *builtinModel) delete() {
+	line := m.lines[m.row]
+	switch {
+	case m.col < len(line):
+		m.lines[m.row] = append(line[:m.col], line[m.col+1:]...)
+	case m.row < len(m.lines)-1:
+		m.lines[m.row] = append(line, m.lines[m.row+1]...)
+		m.lines = append(m.lines[:m.row+1], m.lines[m.row+2:]...)
+	default:
+		return
+	}
+	m.dirty = true
+}
+
+// save writes the text to the file
+func (m *builtinModel) save() {
+	if err := os.WriteFile(m.filename, []byte(m.text()), 0600); err != nil {
+		m.err = fmt.Errorf("failed to save file: %w", err)
+		m.message = m.err.Error()
+		return
+	}
+	m.dirty, m.err = false, nil
+	m.message = "Saved"
+}
+
+// text joins the lines
+func (m *builtinModel) text() string {
+	lines := make([]string, len(m.lines))
+	for i, line := range m.lines {
+		lines[i] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// page returns how many lines fit above the status line
+func (m *builtinModel) page() int {
+	if m.height > 2 {
+		return m.height - 1
+	}
+	return 1
+}
+
+// follow scrolls so the cursor stays on screen
+func (m *builtinModel) follow() {
+	if m.row < m.top {
+		m.top = m.row
+	}
+	if m.row >= m.top+m.page() {
+		m.top = m.row - m.page() + 1
+	}
+	if m.col < m.left {
+		m.left = m.col
+	}
+	if m.width > 1 && m.col >= m.left+m.width-1 {
+		m.left = m.col - m.width + 2
+	}
+}
+
+// View draws the visible lines, the cursor in reverse video, and a status
+// line
+func (m *builtinModel) View() string {
+	var b strings.Builder
+	for i := m.top; i < m.top+m.page(); i++ {
+		if i < len(m.lines) {
+			b.WriteString(m.viewLine(i))
+		} else {
+			b.WriteString("~")
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString(m.status())
+	return b.String()
+}
+
+// viewLine draws the visible part of line i
+func (m *builtinModel) viewLine(i int) string {
+	line := append([]rune(strings.ReplaceAll(string(m.lines[i]), "\t", " ")), ' ')
+	end := clamp(m.left+m.width, m.left, len(line))
+	if m.left >= len(line) {
+		return ""
+	}
+	if i != m.row || m.col < m.left || m.col >= end {
+		return strings.TrimRight(string(line[m.left:end]), " ")
+	}
+	return string(line[m.left:m.col]) + "\x1b[7m" + string(line[m.col]) + "\x1b[27m" + string(line[m.col+1:end])
+}
+
+// status shows the file, the cursor position and the keys, or the latest
+// message
+func (m *builtinModel) status() string {
+	name := m.filename
+	if m.dirty {
+		name += " [modified]"
+	}
+	status := fmt.Sprintf("%s · %d:%d · %s", name, m.row+1, m.col+1, builtinHelp)
+	if m.message != "" {
+		status = m.message
+	}
+	return terminal.New().Gray(status)
+}
+
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+	return value
+}
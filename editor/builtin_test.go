@@ -0,0 +1,133 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// typeKeys sends keys to the model as Bubble Tea would
+func typeKeys(m *builtinModel, keys ...tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+	for _, key := range keys {
+		_, cmd = m.Update(key)
+	}
+	return cmd
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func key(t tea.KeyType) tea.KeyMsg {
+	return tea.KeyMsg{Type: t}
+}
+
+func TestBuiltinModel_Edit(t *testing.T) {
+	m := newBuiltinModel("f.yaml", "name: payments\nlisten: /old")
+
+	typeKeys(m, key(tea.KeyDown), key(tea.KeyEnd), key(tea.KeyBackspace), key(tea.KeyBackspace), key(tea.KeyBackspace), runes("new"))
+	typeKeys(m, key(tea.KeyEnter), runes("active:"), key(tea.KeySpace), runes("true"))
+	if got, want := m.text(), "name: payments\nlisten: /new\nactive: true"; got != want {
+		t.Errorf("text() = %q, want %q", got, want)
+	}
+	if !m.dirty {
+		t.Error("dirty = false after typing")
+	}
+}
+
+func TestBuiltinModel_JoinLines(t *testing.T) {
+	m := newBuiltinModel("f.yaml", "ab\ncd")
+
+	typeKeys(m, key(tea.KeyDown), key(tea.KeyBackspace))
+	if got := m.text(); got != "abcd" {
+		t.Errorf("backspace at line start: text() = %q, want abcd", got)
+	}
+	if m.row != 0 || m.col != 2 {
+		t.Errorf("cursor = %d:%d, want 0:2", m.row, m.col)
+	}
+
+	typeKeys(m, key(tea.KeyEnter), key(tea.KeyLeft), key(tea.KeyDelete))
+	if got := m.text(); got != "abcd" {
+		t.Errorf("delete at line end: text() = %q, want abcd", got)
+	}
+}
+
+func TestBuiltinModel_Paste(t *testing.T) {
+	m := newBuiltinModel("f.yaml", "")
+
+	typeKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a: 1\r\nb: 2"), Paste: true})
+	if got := m.text(); got != "a: 1\nb: 2" {
+		t.Errorf("text() = %q", got)
+	}
+}
+
+func TestBuiltinModel_SaveAndQuit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.yaml")
+	m := newBuiltinModel(path, "name: payments")
+
+	typeKeys(m, key(tea.KeyEnd), runes("-v2"))
+	if cmd := typeKeys(m, key(tea.KeyCtrlQ)); cmd != nil {
+		t.Fatal("ctrl+q quit with unsaved changes")
+	}
+	typeKeys(m, key(tea.KeyCtrlS))
+	if cmd := typeKeys(m, key(tea.KeyCtrlQ)); cmd == nil {
+		t.Fatal("ctrl+q did not quit after saving")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "name: payments-v2" {
+		t.Errorf("saved %q", data)
+	}
+}
+
+func TestBuiltinModel_Discard(t *testing.T) {
+	m := newBuiltinModel(filepath.Join(t.TempDir(), "f.yaml"), "")
+
+	typeKeys(m, runes("x"))
+	typeKeys(m, key(tea.KeyCtrlQ))
+	if cmd := typeKeys(m, key(tea.KeyCtrlQ)); cmd == nil {
+		t.Fatal("second ctrl+q did not quit")
+	}
+}
+
+func TestBuiltinModel_Scroll(t *testing.T) {
+	m := newBuiltinModel("f.yaml", "1\n2\n3\n4\n5\n6")
+	m.Update(tea.WindowSizeMsg{Width: 20, Height: 4})
+
+	typeKeys(m, key(tea.KeyPgDown), key(tea.KeyPgDown))
+	if m.row != 5 || m.top != 3 {
+		t.Errorf("row = %d, top = %d, want 5 and 3", m.row, m.top)
+	}
+}
+
+func TestFindEditor_Builtin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("notepad is always installed")
+	}
+	t.Setenv("PATH", t.TempDir())
+
+	if got := findEditor(); got != BuiltinEditor {
+		t.Errorf("findEditor() = %q, want %q", got, BuiltinEditor)
+	}
+}
+
+func TestEditFile_BuiltinWithoutTerminal(t *testing.T) {
+	if terminal.IsTerminal(os.Stdin.Fd()) && terminal.IsTerminal(os.Stdout.Fd()) {
+		t.Skip("running in a terminal")
+	}
+
+	err := NewWithEditor(BuiltinEditor).EditFile(context.Background(), filepath.Join(t.TempDir(), "f.yaml"))
+	if !errors.Is(err, ErrNoEditor) {
+		t.Errorf("EditFile() error = %v, want ErrNoEditor", err)
+	}
+}
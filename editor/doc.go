@@ -7,6 +7,10 @@
 //   - File Editing: Edit existing files
 //   - Context Support: Full context.Context integration for cancellation
 //   - Timeout Support: Configurable timeouts for editor sessions
+//   - GUI Editor Profiles: Wait flags for GUI editors such as code --wait and subl -w
+//   - Built-in Fallback Editor: Embedded terminal editor when no editor is installed
+//   - Template Scaffolding: Edit rendered templates with comment lines stripped afterwards
+//   - Structured Editing: Edit objects as YAML or JSON with validation and retry
 //
 // Example:
 //   editor := editor.New()
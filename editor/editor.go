@@ -12,6 +12,8 @@ type Editor struct {
 	editor  string
 	args    []string
 	timeout time.Duration
+	// profiles override DefaultProfiles, see SetProfiles
+	profiles Profiles
 }
 
 // New creates a new editor instance
@@ -47,7 +49,11 @@ func (e *Editor) SetTimeout(timeout time.Duration) {
 
 // EditFile edits a file
 func (e *Editor) EditFile(ctx context.Context, filename string) error {
-	cmd := exec.CommandContext(ctx, e.editor, append(e.args, filename)...)
+	if e.editor == BuiltinEditor {
+		return editBuiltin(ctx, filename)
+	}
+	name, args := e.command(filename)
+	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -94,5 +100,5 @@ func getDefaultEditor() string {
 	if editor := os.Getenv("VISUAL"); editor != "" {
 		return editor
 	}
-	return "vim" // fallback
+	return findEditor()
 }
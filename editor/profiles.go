@@ -0,0 +1,82 @@
+package editor
+
+import (
+	"os"
+	"strings"
+)
+
+// DefaultProfiles holds the arguments GUI editors need to block until the
+// file is closed. Without them the editor hands the file to a running
+// window and returns at once, so edits are read before they are made
+var DefaultProfiles = map[string][]string{
+	"atom":          {"--wait"},
+	"bbedit":        {"--wait"},
+	"code":          {"--wait"},
+	"code-insiders": {"--wait"},
+	"codium":        {"--wait"},
+	"cursor":        {"--wait"},
+	"gedit":         {"-w"},
+	"gvim":          {"-f"},
+	"idea":          {"--wait"},
+	"kate":          {"--block"},
+	"mate":          {"-w"},
+	"mvim":          {"-f"},
+	"subl":          {"-w"},
+	"xed":           {"-w"},
+	"zed":           {"--wait"},
+}
+
+// Profiles maps editor names, such as "code", to the arguments they are
+// run with. It can be loaded from the editor.profiles configuration key
+type Profiles map[string][]string
+
+// SetProfiles sets editor profiles, overriding DefaultProfiles for the
+// editors they name. An empty list runs that editor without extra
+// arguments
+func (e *Editor) SetProfiles(profiles Profiles) {
+	e.profiles = profiles
+}
+
+// profileArgs returns the profile arguments for an editor command
+func (e *Editor) profileArgs(name string) []string {
+	base := strings.ToLower(name)
+	// Windows paths are split here too, as editors may be named in config
+	if i := strings.LastIndexAny(base, `/\`); i >= 0 {
+		base = base[i+1:]
+	}
+	base = strings.TrimSuffix(base, ".exe")
+	if args, ok := e.profiles[base]; ok {
+		return args
+	}
+	return DefaultProfiles[base]
+}
+
+// command returns the program and arguments that edit filename. The editor
+// may carry its own arguments, as in EDITOR="code --wait"; profile
+// arguments already given are not repeated
+func (e *Editor) command(filename string) (string, []string) {
+	name, args := e.editor, []string(nil)
+	// A path with spaces, such as on Windows, is taken as a whole
+	if _, err := os.Stat(name); err != nil {
+		if fields := strings.Fields(name); len(fields) > 0 {
+			name, args = fields[0], fields[1:]
+		}
+	}
+	args = append(args, e.args...)
+
+	for _, arg := range e.profileArgs(name) {
+		if !contains(args, arg) {
+			args = append(args, arg)
+		}
+	}
+	return name, append(args, filename)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package editor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommand_Profiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		editor   string
+		args     []string
+		profiles Profiles
+		want     []string
+	}{
+		{"terminal editor", "vim", nil, nil, []string{"vim", "f.yaml"}},
+		{"gui editor", "code", nil, nil, []string{"code", "--wait", "f.yaml"}},
+		{"gui editor path", "/usr/local/bin/subl", nil, nil, []string{"/usr/local/bin/subl", "-w", "f.yaml"}},
+		{"windows executable", `C:\Tools\Code.exe`, nil, nil, []string{`C:\Tools\Code.exe`, "--wait", "f.yaml"}},
+		{"editor with arguments", "code --wait --new-window", nil, nil, []string{"code", "--wait", "--new-window", "f.yaml"}},
+		{"set arguments", "gedit", []string{"-w"}, nil, []string{"gedit", "-w", "f.yaml"}},
+		{"user profile", "nvim-qt", nil, Profiles{"nvim-qt": {"--nofork"}}, []string{"nvim-qt", "--nofork", "f.yaml"}},
+		{"user override", "code", nil, Profiles{"code": {"-r", "--wait"}}, []string{"code", "-r", "--wait", "f.yaml"}},
+		{"user disable", "code", nil, Profiles{"code": {}}, []string{"code", "f.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewWithEditor(tt.editor)
+			e.SetArgs(tt.args)
+			e.SetProfiles(tt.profiles)
+
+			name, args := e.command("f.yaml")
+			if got := append([]string{name}, args...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("command() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package editor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/edsonmichaque/tykctl-go/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrEditAborted is returned by EditStructured when the file is saved
+// empty or without changes, and by EditWithTemplate when it is left empty
+var ErrEditAborted = errors.New("edit aborted")
+
+// editHeader explains the edit loop at the top of the file
+const editHeader = `# Please edit the object below. Lines beginning with a '#' will be ignored,
+# and an empty file will abort the edit. If an error occurs while saving this
+# file will be reopened with the relevant failures.
+#
+`
+
+// Codec serializes objects for editing
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	// Extension is the file extension, such as ".yaml", so editors pick
+	// the right syntax highlighting
+	Extension() string
+}
+
+// Codecs for EditStructured
+var (
+	YAML Codec = yamlCodec{}
+	JSON Codec = jsonCodec{}
+)
+
+type yamlCodec struct{}
+
+func (yamlCodec) Marshal(v interface{}) ([]byte, error)      { return yaml.Marshal(v) }
+func (yamlCodec) Unmarshal(data []byte, v interface{}) error { return yaml.Unmarshal(data, v) }
+func (yamlCodec) Extension() string                          { return ".yaml" }
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func (jsonCodec) Extension() string { return ".json" }
+
+// ValidateFunc checks an edited object, returning why it is invalid. Errors
+// joined with errors.Join are listed one per line
+type ValidateFunc func(ctx context.Context, obj interface{}) error
+
+// SchemaValidator validates edited objects against a JSON schema. Objects
+// are checked by their JSON encoding, so their json tags must match the
+// schema
+func SchemaValidator(validator *jsonschema.Validator) ValidateFunc {
+	return func(ctx context.Context, obj interface{}) error {
+		result, err := validator.ValidateObject(ctx, obj)
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for _, e := range result.Errors {
+			errs = append(errs, fmt.Errorf("%s: %s", e.Field, e.Description))
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// EditStructured edits obj with the default editor, see
+// Editor.EditStructured
+func EditStructured(ctx context.Context, obj interface{}, codec Codec, validate ValidateFunc) error {
+	return New().EditStructured(ctx, obj, codec, validate)
+}
+
+// EditStructured serializes obj, a non-nil pointer, with codec and opens it
+// in the editor. The saved file is decoded and validated, and reopened with
+// the failures at the top until it is valid, emptied or left unchanged. obj
+// is only updated once the edit is valid
+func (e *Editor) EditStructured(ctx context.Context, obj interface{}, codec Codec, validate ValidateFunc) error {
+	target := reflect.ValueOf(obj)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("failed to edit object: %T is not a non-nil pointer", obj)
+	}
+
+	body, err := codec.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal object: %w", err)
+	}
+
+	tmpfile, err := os.CreateTemp("", "tykctl-edit-*"+codec.Extension())
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpfile.Close()
+	defer os.Remove(tmpfile.Name())
+
+	var lastErr error
+	for {
+		shown := stripComments(body)
+		if err := os.WriteFile(tmpfile.Name(), annotate(body, lastErr), 0600); err != nil {
+			return fmt.Errorf("failed to write temp file: %w", err)
+		}
+		if err := e.EditFile(ctx, tmpfile.Name()); err != nil {
+			return fmt.Errorf("failed to run editor: %w", err)
+		}
+		edited, err := os.ReadFile(tmpfile.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read temp file: %w", err)
+		}
+
+		body = stripComments(edited)
+		if len(bytes.TrimSpace(body)) == 0 {
+			return ErrEditAborted
+		}
+		if bytes.Equal(body, shown) {
+			if lastErr != nil {
+				return fmt.Errorf("%w: %w", ErrEditAborted, lastErr)
+			}
+			return ErrEditAborted
+		}
+
+		fresh := reflect.New(target.Elem().Type())
+		if err := codec.Unmarshal(body, fresh.Interface()); err != nil {
+			lastErr = fmt.Errorf("failed to parse object: %w", err)
+			continue
+		}
+		if validate != nil {
+			if err := validate(ctx, fresh.Interface()); err != nil {
+				lastErr = err
+				continue
+			}
+		}
+
+		target.Elem().Set(fresh.Elem())
+		return nil
+	}
+}
+
+// annotate puts the header and, on a retry, the failures above body
+func annotate(body []byte, failure error) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(editHeader)
+	if failure != nil {
+		buf.WriteString("# The edited object is invalid:\n")
+		for _, line := range strings.Split(failure.Error(), "\n") {
+			buf.WriteString("#   " + line + "\n")
+		}
+		buf.WriteString("#\n")
+	}
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// stripComments drops lines starting with '#', which JSON cannot parse and
+// which hold the header and failures
+func stripComments(data []byte) []byte {
+	var buf bytes.Buffer
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		buf.WriteString(line)
+	}
+	return buf.Bytes()
+}
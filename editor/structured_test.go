@@ -0,0 +1,142 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/edsonmichaque/tykctl-go/jsonschema"
+)
+
+type api struct {
+	Name   string `yaml:"name" json:"name"`
+	Listen string `yaml:"listen" json:"listen"`
+}
+
+// scriptEditor runs script with sh, the edited file being $1
+func scriptEditor(t *testing.T, script string) *Editor {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("editor scripts need sh")
+	}
+	e := NewWithEditor("sh")
+	e.SetArgs([]string{"-c", script, "sh"})
+	return e
+}
+
+func validateListen(ctx context.Context, obj interface{}) error {
+	if !strings.HasPrefix(obj.(*api).Listen, "/") {
+		return errors.New("listen must start with /")
+	}
+	return nil
+}
+
+func TestEditStructured_YAML(t *testing.T) {
+	e := scriptEditor(t, `sed -i.bak 's|listen: /old|listen: /new|' "$1"`)
+	obj := &api{Name: "payments", Listen: "/old"}
+
+	if err := e.EditStructured(context.Background(), obj, YAML, validateListen); err != nil {
+		t.Fatalf("EditStructured() error = %v", err)
+	}
+	if obj.Listen != "/new" || obj.Name != "payments" {
+		t.Errorf("obj = %+v", obj)
+	}
+}
+
+func TestEditStructured_Retry(t *testing.T) {
+	// The first save is invalid; the second sees the failure and fixes it
+	e := scriptEditor(t, `
+if grep -q '#   listen must start with /' "$1"; then
+	sed -i.bak 's|listen: bad|listen: /fixed|' "$1"
+else
+	sed -i.bak 's|listen: /old|listen: bad|' "$1"
+fi`)
+	obj := &api{Name: "payments", Listen: "/old"}
+
+	if err := e.EditStructured(context.Background(), obj, YAML, validateListen); err != nil {
+		t.Fatalf("EditStructured() error = %v", err)
+	}
+	if obj.Listen != "/fixed" {
+		t.Errorf("Listen = %q, want /fixed", obj.Listen)
+	}
+}
+
+func TestEditStructured_Aborted(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{"unchanged", "true"},
+		{"empty", `: > "$1"`},
+		{"comments only", `printf '# nothing\n' > "$1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := scriptEditor(t, tt.script)
+			obj := &api{Name: "payments", Listen: "/old"}
+
+			err := e.EditStructured(context.Background(), obj, YAML, nil)
+			if !errors.Is(err, ErrEditAborted) {
+				t.Fatalf("EditStructured() error = %v, want ErrEditAborted", err)
+			}
+			if obj.Listen != "/old" {
+				t.Errorf("obj changed on abort: %+v", obj)
+			}
+		})
+	}
+}
+
+func TestEditStructured_AbortedAfterFailure(t *testing.T) {
+	// Saving the invalid object again gives up with the last failure
+	e := scriptEditor(t, `sed -i.bak 's|listen: /old|listen: bad|' "$1"`)
+	obj := &api{Name: "payments", Listen: "/old"}
+
+	err := e.EditStructured(context.Background(), obj, YAML, validateListen)
+	if !errors.Is(err, ErrEditAborted) || !strings.Contains(err.Error(), "listen must start with /") {
+		t.Fatalf("EditStructured() error = %v", err)
+	}
+	if obj.Listen != "/old" {
+		t.Errorf("obj changed on abort: %+v", obj)
+	}
+}
+
+func TestEditStructured_JSON(t *testing.T) {
+	// The header comments are not JSON, so they must be dropped before decoding
+	e := scriptEditor(t, `grep -q '^# Please edit' "$1" && sed -i.bak 's|"/old"|"/new"|' "$1"`)
+	obj := &api{Name: "payments", Listen: "/old"}
+
+	if err := e.EditStructured(context.Background(), obj, JSON, nil); err != nil {
+		t.Fatalf("EditStructured() error = %v", err)
+	}
+	if obj.Listen != "/new" {
+		t.Errorf("Listen = %q, want /new", obj.Listen)
+	}
+}
+
+func TestEditStructured_NotPointer(t *testing.T) {
+	if err := New().EditStructured(context.Background(), api{}, YAML, nil); err == nil {
+		t.Error("EditStructured() accepted a non-pointer")
+	}
+}
+
+func TestSchemaValidator(t *testing.T) {
+	validator, err := jsonschema.New(`{
+		"type": "object",
+		"properties": {"listen": {"type": "string", "pattern": "^/"}},
+		"required": ["name"]
+	}`)
+	if err != nil {
+		t.Fatalf("jsonschema.New() error = %v", err)
+	}
+	validate := SchemaValidator(validator)
+
+	if err := validate(context.Background(), &api{Name: "payments", Listen: "/pay"}); err != nil {
+		t.Errorf("valid object: error = %v", err)
+	}
+	err = validate(context.Background(), &api{Name: "payments", Listen: "pay"})
+	if err == nil || !strings.Contains(err.Error(), "listen") {
+		t.Errorf("invalid object: error = %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package editor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// CommentGuidance is the line templates usually end with, telling the user
+// how their text is cleaned up
+const CommentGuidance = "# Lines starting with '#' will be ignored, and an empty message aborts."
+
+// ScissorsLine marks the end of the text, as in git: it and everything
+// below it are dropped, so templates can show context, such as a diff,
+// that need not be commented out
+const ScissorsLine = "# ------------------------ >8 ------------------------"
+
+// EditWithTemplate edits a template with the default editor, see
+// Editor.EditWithTemplate
+func EditWithTemplate(ctx context.Context, tmpl string, data interface{}) (string, error) {
+	return New().EditWithTemplate(ctx, tmpl, data)
+}
+
+// EditWithTemplate renders tmpl, a text/template, with data and opens it
+// in the editor, as git does for commit messages. Lines starting with '#'
+// are removed from the result, as is everything from ScissorsLine on, and
+// blank lines are squeezed. An empty result returns ErrEditAborted
+func (e *Editor) EditWithTemplate(ctx context.Context, tmpl string, data interface{}) (string, error) {
+	parsed, err := template.New("edit").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	edited, err := e.EditString(ctx, buf.String())
+	if err != nil {
+		return "", err
+	}
+
+	text := cleanupText(edited)
+	if text == "" {
+		return "", ErrEditAborted
+	}
+	return text, nil
+}
+
+// cleanupText drops comment lines and the scissors, trims trailing spaces,
+// squeezes runs of blank lines and trims blank lines at either end, as git
+// stripspace does
+func cleanupText(text string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line == ScissorsLine {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,69 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+const changeTemplate = `
+# Describe the change to {{.Name}}.
+` + CommentGuidance + `
+` + ScissorsLine + `
+{{.Diff}}
+`
+
+func TestEditWithTemplate(t *testing.T) {
+	e := scriptEditor(t, `
+grep -q '^# Describe the change to payments.$' "$1" || exit 1
+{ printf 'Raise rate limit  \n\n\n\nThe old limit throttled batch jobs.\n'; cat "$1"; } > "$1.new" && mv "$1.new" "$1"`)
+
+	got, err := e.EditWithTemplate(context.Background(), changeTemplate, map[string]string{
+		"Name": "payments",
+		"Diff": "- rate: 10\n+ rate: 100",
+	})
+	if err != nil {
+		t.Fatalf("EditWithTemplate() error = %v", err)
+	}
+	if want := "Raise rate limit\n\nThe old limit throttled batch jobs.\n"; got != want {
+		t.Errorf("EditWithTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestEditWithTemplate_Empty(t *testing.T) {
+	// Leaving only the guidance and the diff counts as empty
+	e := scriptEditor(t, "true")
+
+	_, err := e.EditWithTemplate(context.Background(), changeTemplate, map[string]string{"Name": "payments", "Diff": "+ rate: 100"})
+	if !errors.Is(err, ErrEditAborted) {
+		t.Errorf("EditWithTemplate() error = %v, want ErrEditAborted", err)
+	}
+}
+
+func TestEditWithTemplate_BadTemplate(t *testing.T) {
+	if _, err := New().EditWithTemplate(context.Background(), "{{.Name", nil); err == nil {
+		t.Error("EditWithTemplate() accepted a bad template")
+	}
+}
+
+func TestCleanupText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"comments", "# a\ntitle\n# b\n", "title\n"},
+		{"indented hash kept", "title\n\n  # not a comment\n", "title\n\n  # not a comment\n"},
+		{"blank lines", "\n\ntitle\n\n\n\nbody\n\n", "title\n\nbody\n"},
+		{"trailing spaces", "title \t\r\nbody  ", "title\nbody\n"},
+		{"scissors", "title\n" + ScissorsLine + "\ndiff\n", "title\n"},
+		{"only comments", "# a\n\n# b\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanupText(tt.text); got != tt.want {
+				t.Errorf("cleanupText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
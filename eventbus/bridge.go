@@ -0,0 +1,179 @@
+// Package eventbus provides bridges that connect event buses across processes.
+package eventbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// MetadataOrigin is the metadata key holding the ID of the bus that first
+	// published an event. Bridges use it to avoid forwarding remote events
+	// back out and to ignore their own events echoed by the remote system.
+	MetadataOrigin = "eventbus_origin"
+
+	// MetadataBridge is the metadata key holding the name of the bridge that
+	// injected a remote event.
+	MetadataBridge = "eventbus_bridge"
+
+	// defaultBridgeBufferSize is the number of events waiting to be forwarded per bridge.
+	defaultBridgeBufferSize = 1000
+)
+
+// Bridge connects the event bus to an external messaging system so that
+// several processes can share events.
+type Bridge interface {
+	// Name identifies the bridge in logs and event metadata.
+	Name() string
+
+	// Forward sends a locally published event to the remote system.
+	Forward(ctx context.Context, event *Event) error
+
+	// Receive delivers remote events to inject until ctx is cancelled.
+	Receive(ctx context.Context, inject func(*Event)) error
+
+	// Close releases the resources held by the bridge.
+	Close() error
+}
+
+// bridgeLink forwards events to a single bridge in the background.
+type bridgeLink struct {
+	bridge Bridge
+	outbox chan *Event
+}
+
+// startBridges starts forwarding and receiving for each configured bridge.
+func (eb *eventBus) startBridges(bridges []Bridge) {
+	if len(bridges) == 0 {
+		return
+	}
+
+	eb.bridgeCtx, eb.bridgeCancel = context.WithCancel(context.Background())
+
+	for _, bridge := range bridges {
+		link := &bridgeLink{
+			bridge: bridge,
+			outbox: make(chan *Event, defaultBridgeBufferSize),
+		}
+		eb.bridges = append(eb.bridges, link)
+
+		eb.wg.Add(2)
+		go eb.forwardLoop(link)
+		go eb.receiveLoop(link)
+	}
+}
+
+// forward queues a locally published event for every bridge. Events that
+// arrived from another bus are not forwarded again.
+func (eb *eventBus) forward(event *Event) {
+	if len(eb.bridges) == 0 {
+		return
+	}
+
+	if origin, ok := event.Metadata[MetadataOrigin]; ok && origin != eb.id {
+		return
+	}
+	event.WithMetadata(MetadataOrigin, eb.id)
+
+	// Bridges encode the event while local handlers may still be using it
+	outgoing := event.Clone()
+	for _, link := range eb.bridges {
+		select {
+		case link.outbox <- outgoing:
+		default:
+			eb.logger.Error("Bridge buffer is full, dropping event",
+				zap.String("bridge", link.bridge.Name()),
+				zap.String("id", event.ID))
+		}
+	}
+}
+
+// forwardLoop sends queued events to a bridge.
+func (eb *eventBus) forwardLoop(link *bridgeLink) {
+	defer eb.wg.Done()
+
+	for {
+		select {
+		case event := <-link.outbox:
+			if err := link.bridge.Forward(eb.bridgeCtx, event); err != nil {
+				eb.logger.Error("Failed to forward event",
+					zap.String("bridge", link.bridge.Name()),
+					zap.String("id", event.ID),
+					zap.Error(err))
+			}
+		case <-eb.stopChan:
+			return
+		}
+	}
+}
+
+// receiveLoop injects remote events from a bridge into local subscribers.
+func (eb *eventBus) receiveLoop(link *bridgeLink) {
+	defer eb.wg.Done()
+
+	err := link.bridge.Receive(eb.bridgeCtx, func(event *Event) {
+		// Our own events echoed back by the remote system
+		if origin, ok := event.Metadata[MetadataOrigin]; ok && origin == eb.id {
+			return
+		}
+
+		event.WithMetadata(MetadataBridge, link.bridge.Name())
+		eb.storeEvent(event)
+		if err := eb.publishAsync(event); err != nil {
+			eb.logger.Error("Failed to inject remote event",
+				zap.String("bridge", link.bridge.Name()),
+				zap.String("id", event.ID),
+				zap.Error(err))
+		}
+	})
+	if err != nil && eb.bridgeCtx.Err() == nil {
+		eb.logger.Error("Bridge stopped receiving",
+			zap.String("bridge", link.bridge.Name()),
+			zap.Error(err))
+	}
+}
+
+// closeBridges stops receiving and closes every bridge.
+func (eb *eventBus) closeBridges() error {
+	var firstErr error
+	for _, link := range eb.bridges {
+		if err := link.bridge.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close bridge %s: %w", link.bridge.Name(), err)
+		}
+	}
+	return firstErr
+}
+
+// encodeEvent serializes an event for transport.
+func encodeEvent(event *Event) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return data, nil
+}
+
+// decodeEvent deserializes an event received from a remote system. As with
+// the durable queue, Event.Data holds the decoded JSON value.
+func decodeEvent(data []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+	if event.Metadata == nil {
+		event.Metadata = make(map[string]interface{})
+	}
+	return &event, nil
+}
+
+// generateBusID returns a random identifier for a bus instance.
+func generateBusID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,92 @@
+// Package eventbus provides a Kafka bridge for sharing events between processes.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+)
+
+// KafkaMessage is a record produced to or consumed from a Kafka topic.
+type KafkaMessage struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// KafkaClient is the subset of a Kafka client used by KafkaBridge. Adapt a
+// producer and a consumer group reader from your Kafka library to it.
+type KafkaClient interface {
+	// Produce writes a message to its topic.
+	Produce(ctx context.Context, message KafkaMessage) error
+
+	// Consume calls handler for each message on topic until ctx is cancelled.
+	Consume(ctx context.Context, topic string, handler func(KafkaMessage) error) error
+}
+
+// KafkaBridge shares events over a Kafka topic. Messages are keyed by
+// Event.Key, or by event type when no key is set, so related events land in
+// the same partition and keep their order.
+type KafkaBridge struct {
+	client KafkaClient
+	topic  string
+}
+
+// NewKafkaBridge creates a Kafka bridge. An empty topic defaults to "tykctl-events".
+func NewKafkaBridge(client KafkaClient, topic string) *KafkaBridge {
+	if topic == "" {
+		topic = "tykctl-events"
+	}
+	return &KafkaBridge{client: client, topic: topic}
+}
+
+// Name identifies the bridge.
+func (b *KafkaBridge) Name() string {
+	return "kafka"
+}
+
+// Forward produces an event to the topic.
+func (b *KafkaBridge) Forward(ctx context.Context, event *Event) error {
+	data, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	key := event.Key
+	if key == "" {
+		key = string(event.Type)
+	}
+
+	message := KafkaMessage{
+		Topic: b.topic,
+		Key:   []byte(key),
+		Value: data,
+		Headers: map[string]string{
+			"event-type": string(event.Type),
+			"event-id":   event.ID,
+		},
+	}
+
+	if err := b.client.Produce(ctx, message); err != nil {
+		return fmt.Errorf("failed to produce to %s: %w", b.topic, err)
+	}
+	return nil
+}
+
+// Receive consumes the topic until ctx is cancelled. Messages that are not
+// valid events are skipped rather than blocking the partition.
+func (b *KafkaBridge) Receive(ctx context.Context, inject func(*Event)) error {
+	return b.client.Consume(ctx, b.topic, func(message KafkaMessage) error {
+		event, err := decodeEvent(message.Value)
+		if err != nil {
+			return nil
+		}
+		inject(event)
+		return nil
+	})
+}
+
+// Close releases the bridge. The Kafka client is owned by the caller.
+func (b *KafkaBridge) Close() error {
+	return nil
+}
@@ -0,0 +1,78 @@
+// Package eventbus provides a NATS bridge for sharing events between processes.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// NATSClient is the subset of a NATS connection used by NATSBridge. Adapt a
+// *nats.Conn with a small wrapper that passes msg.Data to the handler and
+// returns the subscription's Unsubscribe method.
+type NATSClient interface {
+	// Publish sends data on a subject.
+	Publish(subject string, data []byte) error
+
+	// Subscribe calls handler for each message on subject, which may contain
+	// wildcards, and returns a function that ends the subscription.
+	Subscribe(subject string, handler func(data []byte)) (unsubscribe func() error, err error)
+}
+
+// NATSBridge shares events over NATS, publishing each event on the subject
+// "<prefix>.<event type>" and receiving every event under the prefix.
+type NATSBridge struct {
+	client NATSClient
+	prefix string
+}
+
+// NewNATSBridge creates a NATS bridge. An empty prefix defaults to "tykctl.events".
+func NewNATSBridge(client NATSClient, prefix string) *NATSBridge {
+	if prefix == "" {
+		prefix = "tykctl.events"
+	}
+	return &NATSBridge{client: client, prefix: strings.TrimSuffix(prefix, ".")}
+}
+
+// Name identifies the bridge.
+func (b *NATSBridge) Name() string {
+	return "nats"
+}
+
+// Forward publishes an event on its type's subject.
+func (b *NATSBridge) Forward(ctx context.Context, event *Event) error {
+	data, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", b.prefix, event.Type)
+	if err := b.client.Publish(subject, data); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", subject, err)
+	}
+	return nil
+}
+
+// Receive subscribes to all subjects under the prefix until ctx is cancelled.
+func (b *NATSBridge) Receive(ctx context.Context, inject func(*Event)) error {
+	subject := b.prefix + ".>"
+
+	unsubscribe, err := b.client.Subscribe(subject, func(data []byte) {
+		event, err := decodeEvent(data)
+		if err != nil {
+			return
+		}
+		inject(event)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+
+	<-ctx.Done()
+	return unsubscribe()
+}
+
+// Close releases the bridge. The NATS connection is owned by the caller.
+func (b *NATSBridge) Close() error {
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package eventbus provides a Redis Streams bridge for sharing events between processes.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RedisStreamMessage is an entry read from a Redis stream.
+type RedisStreamMessage struct {
+	ID     string
+	Values map[string]string
+}
+
+// RedisStreamClient is the subset of a Redis client used by RedisStreamBridge.
+type RedisStreamClient interface {
+	// XAdd appends an entry to a stream, trimming it to about maxLen entries
+	// when maxLen is positive, and returns the entry ID.
+	XAdd(ctx context.Context, stream string, maxLen int64, values map[string]string) (string, error)
+
+	// XRead returns up to count entries after lastID, waiting up to block
+	// for new entries. It returns no entries and no error on timeout.
+	XRead(ctx context.Context, stream, lastID string, count int64, block time.Duration) ([]RedisStreamMessage, error)
+}
+
+const (
+	// redisEventField is the stream entry field holding the encoded event.
+	redisEventField = "event"
+
+	// defaultRedisMaxLen caps the stream length so it does not grow unbounded.
+	defaultRedisMaxLen = 10000
+
+	// redisReadBlock is how long each read waits for new entries.
+	redisReadBlock = 5 * time.Second
+)
+
+// RedisStreamBridge shares events over a Redis stream. Each bridge reads
+// new entries from the point it started, so every connected process sees
+// every event.
+type RedisStreamBridge struct {
+	client RedisStreamClient
+	stream string
+	maxLen int64
+}
+
+// NewRedisStreamBridge creates a Redis Streams bridge. An empty stream
+// defaults to "tykctl:events".
+func NewRedisStreamBridge(client RedisStreamClient, stream string) *RedisStreamBridge {
+	if stream == "" {
+		stream = "tykctl:events"
+	}
+	return &RedisStreamBridge{client: client, stream: stream, maxLen: defaultRedisMaxLen}
+}
+
+// WithMaxLen sets the approximate maximum stream length; zero disables trimming.
+func (b *RedisStreamBridge) WithMaxLen(maxLen int64) *RedisStreamBridge {
+	b.maxLen = maxLen
+	return b
+}
+
+// Name identifies the bridge.
+func (b *RedisStreamBridge) Name() string {
+	return "redis"
+}
+
+// Forward appends an event to the stream.
+func (b *RedisStreamBridge) Forward(ctx context.Context, event *Event) error {
+	data, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{
+		redisEventField: string(data),
+		"type":          string(event.Type),
+	}
+	if _, err := b.client.XAdd(ctx, b.stream, b.maxLen, values); err != nil {
+		return fmt.Errorf("failed to add to stream %s: %w", b.stream, err)
+	}
+	return nil
+}
+
+// Receive reads new stream entries until ctx is cancelled.
+func (b *RedisStreamBridge) Receive(ctx context.Context, inject func(*Event)) error {
+	// "$" only delivers entries added after the first read
+	lastID := "$"
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+
+		messages, err := b.client.XRead(ctx, b.stream, lastID, 100, redisReadBlock)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read stream %s: %w", b.stream, err)
+		}
+
+		for _, message := range messages {
+			lastID = message.ID
+
+			event, err := decodeEvent([]byte(message.Values[redisEventField]))
+			if err != nil {
+				continue
+			}
+			inject(event)
+		}
+	}
+}
+
+// Close releases the bridge. The Redis client is owned by the caller.
+func (b *RedisStreamBridge) Close() error {
+	return nil
+}
@@ -0,0 +1,203 @@
+package eventbus
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNATS is an in-memory NATS server supporting the ">" wildcard.
+type fakeNATS struct {
+	mu       sync.Mutex
+	handlers map[int]natsSub
+	next     int
+}
+
+type natsSub struct {
+	subject string
+	handler func([]byte)
+}
+
+func newFakeNATS() *fakeNATS {
+	return &fakeNATS{handlers: make(map[int]natsSub)}
+}
+
+func (n *fakeNATS) Publish(subject string, data []byte) error {
+	n.mu.Lock()
+	var matched []func([]byte)
+	for _, sub := range n.handlers {
+		prefix := strings.TrimSuffix(sub.subject, ">")
+		if sub.subject == subject || (prefix != sub.subject && strings.HasPrefix(subject, prefix)) {
+			matched = append(matched, sub.handler)
+		}
+	}
+	n.mu.Unlock()
+
+	for _, handler := range matched {
+		handler(data)
+	}
+	return nil
+}
+
+func (n *fakeNATS) Subscribe(subject string, handler func([]byte)) (func() error, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	id := n.next
+	n.next++
+	n.handlers[id] = natsSub{subject: subject, handler: handler}
+
+	return func() error {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.handlers, id)
+		return nil
+	}, nil
+}
+
+// fakeRedis is an in-memory Redis stream.
+type fakeRedis struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	entries []RedisStreamMessage
+}
+
+func newFakeRedis() *fakeRedis {
+	r := &fakeRedis{}
+	r.cond = sync.NewCond(&r.mu)
+	return r
+}
+
+func (r *fakeRedis) XAdd(ctx context.Context, stream string, maxLen int64, values map[string]string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := strconv.Itoa(len(r.entries) + 1)
+	r.entries = append(r.entries, RedisStreamMessage{ID: id, Values: values})
+	r.cond.Broadcast()
+	return id, nil
+}
+
+func (r *fakeRedis) XRead(ctx context.Context, stream, lastID string, count int64, block time.Duration) ([]RedisStreamMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := len(r.entries)
+	if lastID != "$" {
+		start, _ = strconv.Atoi(lastID)
+	}
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	for len(r.entries) <= start && time.Now().Before(deadline) && ctx.Err() == nil {
+		r.mu.Unlock()
+		time.Sleep(time.Millisecond)
+		r.mu.Lock()
+	}
+
+	return append([]RedisStreamMessage(nil), r.entries[start:]...), nil
+}
+
+func TestBridge_SharesEventsBetweenBuses(t *testing.T) {
+	tests := map[string]func() (Bridge, Bridge){
+		"nats": func() (Bridge, Bridge) {
+			server := newFakeNATS()
+			return NewNATSBridge(server, ""), NewNATSBridge(server, "")
+		},
+		"redis": func() (Bridge, Bridge) {
+			server := newFakeRedis()
+			return NewRedisStreamBridge(server, ""), NewRedisStreamBridge(server, "")
+		},
+	}
+
+	for name, newBridges := range tests {
+		t.Run(name, func(t *testing.T) {
+			bridgeA, bridgeB := newBridges()
+			busA := New(WithBridge(bridgeA))
+			defer busA.Close()
+			busB := New(WithBridge(bridgeB))
+			defer busB.Close()
+
+			receivedA := make(chan *Event, 10)
+			receivedB := make(chan *Event, 10)
+			busA.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+				receivedA <- e
+				return nil
+			}))
+			busB.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+				receivedB <- e
+				return nil
+			}))
+
+			// Give the receivers time to subscribe
+			time.Sleep(20 * time.Millisecond)
+
+			event := NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": "shared"})
+			if err := busA.PublishAsync(event); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case e := <-receivedB:
+				if e.ID != event.ID {
+					t.Errorf("Expected event %s on remote bus, got %s", event.ID, e.ID)
+				}
+				if e.Metadata[MetadataBridge] != bridgeB.Name() {
+					t.Errorf("Expected bridge metadata %s, got %v", bridgeB.Name(), e.Metadata[MetadataBridge])
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Event was not delivered to the remote bus")
+			}
+
+			select {
+			case <-receivedA:
+			case <-time.After(time.Second):
+				t.Fatal("Event was not delivered to the local bus")
+			}
+
+			// Neither the echo on bus A nor a re-forward from bus B may deliver it again
+			select {
+			case e := <-receivedA:
+				t.Fatalf("Event delivered twice on the local bus: %s", e.ID)
+			case e := <-receivedB:
+				t.Fatalf("Event delivered twice on the remote bus: %s", e.ID)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestKafkaBridge_Keys(t *testing.T) {
+	var produced []KafkaMessage
+	client := &fakeKafka{produce: func(m KafkaMessage) { produced = append(produced, m) }}
+	bridge := NewKafkaBridge(client, "")
+
+	bridge.Forward(context.Background(), NewEvent(TestEventTypeAPICreate, nil).WithKey("ext-1"))
+	bridge.Forward(context.Background(), NewEvent(TestEventTypeAPICreate, nil))
+
+	if len(produced) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(produced))
+	}
+	if string(produced[0].Key) != "ext-1" || string(produced[1].Key) != string(TestEventTypeAPICreate) {
+		t.Errorf("Unexpected message keys %q and %q", produced[0].Key, produced[1].Key)
+	}
+	if produced[0].Topic != "tykctl-events" {
+		t.Errorf("Expected default topic, got %s", produced[0].Topic)
+	}
+}
+
+type fakeKafka struct {
+	produce func(KafkaMessage)
+}
+
+func (k *fakeKafka) Produce(ctx context.Context, message KafkaMessage) error {
+	k.produce(message)
+	return nil
+}
+
+func (k *fakeKafka) Consume(ctx context.Context, topic string, handler func(KafkaMessage) error) error {
+	<-ctx.Done()
+	return nil
+}
@@ -18,9 +18,22 @@ type EventBus interface {
 	// PublishAsync publishes an event asynchronously.
 	PublishAsync(event *Event) error
 
+	// PublishAfter publishes an event asynchronously once delay has elapsed.
+	PublishAfter(event *Event, delay time.Duration) error
+
+	// PublishAt publishes an event asynchronously at the given time.
+	PublishAt(event *Event, at time.Time) error
+
+	// CancelScheduled cancels a delayed or scheduled event that has not been published yet.
+	CancelScheduled(eventID string) bool
+
 	// Subscribe subscribes to events of a specific type.
 	Subscribe(eventType EventType, handler Handler) (Subscription, error)
 
+	// SubscribeGroup subscribes to events of a specific type as a member of a
+	// consumer group, so each event is handled by only one member of the group.
+	SubscribeGroup(eventType EventType, group string, handler Handler) (Subscription, error)
+
 	// Unsubscribe removes a subscription.
 	Unsubscribe(subscription Subscription) error
 
@@ -30,8 +43,31 @@ type EventBus interface {
 	// GetStats returns event bus statistics.
 	GetStats() *Stats
 
-	// SetMiddleware sets global middleware.
+	// SetMiddleware sets global middleware. It runs after the built-in
+	// middleware enabled in Config.Middleware.
 	SetMiddleware(middleware ...Middleware)
+
+	// Metrics returns the metrics collected by the built-in metrics
+	// middleware, or nil when it is disabled.
+	Metrics() map[string]interface{}
+
+	// DeadLetters returns the queue of async events that failed processing.
+	DeadLetters() DeadLetterQueue
+
+	// Schemas returns the registry of payload schemas validated on publish.
+	Schemas() *SchemaRegistry
+
+	// Replay delivers events from the event store that match filter to handler.
+	Replay(ctx context.Context, filter ReplayFilter, handler Handler) error
+
+	// Export returns a snapshot of the bus wiring: subscriptions by handler
+	// name, middleware order and rate limit and circuit breaker settings.
+	Export() *Snapshot
+
+	// Import restores wiring from a snapshot, resolving handler and
+	// middleware names through catalog. It returns the subscriptions it
+	// created.
+	Import(snapshot *Snapshot, catalog *Catalog) ([]Subscription, error)
 }
 
 // Subscription represents an event subscription.
@@ -42,6 +78,10 @@ type Subscription interface {
 	// EventType returns the subscribed event type.
 	EventType() EventType
 
+	// Group returns the consumer group, or an empty string for subscriptions
+	// that receive every event.
+	Group() string
+
 	// Handler returns the event handler.
 	Handler() Handler
 
@@ -49,63 +89,124 @@ type Subscription interface {
 	Unsubscribe() error
 }
 
-
 // Stats contains event bus statistics.
 type Stats struct {
-	EventsPublished    int64     `json:"events_published"`
-	EventsProcessed    int64     `json:"events_processed"`
-	EventsFailed       int64     `json:"events_failed"`
-	ActiveSubscriptions int64    `json:"active_subscriptions"`
-	StartTime          time.Time `json:"start_time"`
-	LastEventTime      time.Time `json:"last_event_time"`
+	EventsPublished     int64          `json:"events_published"`
+	EventsProcessed     int64          `json:"events_processed"`
+	EventsFailed        int64          `json:"events_failed"`
+	EventsRecovered     int64          `json:"events_recovered"`
+	EventsDeadLettered  int64          `json:"events_dead_lettered"`
+	EventsRejected      int64          `json:"events_rejected"`
+	QueueDepth          map[string]int `json:"queue_depth"`
+	ScheduledEvents     int            `json:"scheduled_events"`
+	ActiveSubscriptions int64          `json:"active_subscriptions"`
+	StartTime           time.Time      `json:"start_time"`
+	LastEventTime       time.Time      `json:"last_event_time"`
 }
 
 // eventBus implements the EventBus interface.
 type eventBus struct {
-	registry     *HandlerRegistry
-	middleware   []Middleware
-	stats        *Stats
-	mu           sync.RWMutex
-	logger       *zap.Logger
-	asyncWorkers int
-	asyncQueue   chan *Event
-	stopChan     chan struct{}
-	wg           sync.WaitGroup
+	registry        *HandlerRegistry
+	groups          map[groupKey]*groupHandler
+	groupsMu        sync.Mutex
+	builtin         []Middleware
+	middleware      []Middleware
+	metrics         *MetricsMiddleware
+	stats           *Stats
+	mu              sync.RWMutex
+	logger          *zap.Logger
+	asyncWorkers    int
+	queues          [priorityLevels]chan *Event
+	priorityMu      sync.Mutex
+	skipped         int
+	starvationLimit int
+	partitions      []chan *Event
+	unkeyed         chan *Event
+	durable         DurableQueue
+	store           EventStore
+	deadLetters     *deadLetterQueue
+	schemas         *SchemaRegistry
+	tracer          Tracer
+	scheduler       *timerWheel
+	id              string
+	bridges         []*bridgeLink
+	bridgeCtx       context.Context
+	bridgeCancel    context.CancelFunc
+	backlog         []*Event
+	backlogMu       sync.Mutex
+	backlogReady    chan struct{}
+	stopChan        chan struct{}
+	closeOnce       sync.Once
+	wg              sync.WaitGroup
 }
 
 // New creates a new event bus.
 func New(options ...Option) EventBus {
-	config := &Config{
-		AsyncWorkers: 10,
-		AsyncQueueSize: 1000,
-		Logger: zap.NewNop(),
-	}
+	config := DefaultConfig()
 
 	for _, option := range options {
 		option(config)
 	}
+	config.applyDeprecated()
 
 	eb := &eventBus{
-		registry:     NewHandlerRegistry(),
-		middleware:   make([]Middleware, 0),
-		stats:        &Stats{StartTime: time.Now()},
-		logger:       config.Logger,
-		asyncWorkers: config.AsyncWorkers,
-		asyncQueue:   make(chan *Event, config.AsyncQueueSize),
-		stopChan:     make(chan struct{}),
+		registry:        NewHandlerRegistry(),
+		groups:          make(map[groupKey]*groupHandler),
+		middleware:      make([]Middleware, 0),
+		stats:           &Stats{StartTime: time.Now()},
+		logger:          config.Logger,
+		asyncWorkers:    config.AsyncWorkers,
+		queues:          newPriorityQueues(config.AsyncQueueSize),
+		starvationLimit: config.StarvationLimit,
+		durable:         config.DurableQueue,
+		store:           config.EventStore,
+		tracer:          config.Tracer,
+		backlogReady:    make(chan struct{}, 1),
+		stopChan:        make(chan struct{}),
 	}
+	eb.deadLetters = newDeadLetterQueue(eb, config.DeadLetterQueueSize)
+	eb.scheduler = newTimerWheel(config.SchedulerTick)
+	eb.schemas = NewSchemaRegistry(config.StrictSchemas, config.Logger)
+	eb.id = generateBusID()
+	eb.builtin, eb.metrics = config.builtinMiddleware()
 
 	// Start async workers
-	for i := 0; i < eb.asyncWorkers; i++ {
+	if config.OrderedDelivery {
+		eb.startOrderedWorkers(config.AsyncQueueSize)
+	} else {
+		for i := 0; i < eb.asyncWorkers; i++ {
+			eb.wg.Add(1)
+			go eb.asyncWorker(i)
+		}
+	}
+
+	eb.wg.Add(1)
+	go eb.schedulerLoop()
+
+	if eb.metrics != nil && config.Middleware.Metrics.FlushInterval > 0 {
 		eb.wg.Add(1)
-		go eb.asyncWorker(i)
+		go eb.flushMetrics(config.Middleware.Metrics.FlushInterval)
 	}
 
+	if eb.durable != nil {
+		eb.wg.Add(1)
+		go eb.backlogFeeder()
+		eb.recoverPending()
+	}
+
+	eb.startBridges(config.Bridges)
+
 	return eb
 }
 
 // Publish publishes an event synchronously.
 func (eb *eventBus) Publish(event *Event) error {
+	if err := eb.validateSchema(event); err != nil {
+		return err
+	}
+	eb.forward(event)
+	eb.storeEvent(event)
+
 	eb.mu.Lock()
 	eb.stats.EventsPublished++
 	eb.stats.LastEventTime = time.Now()
@@ -123,17 +224,39 @@ func (eb *eventBus) Publish(event *Event) error {
 
 // PublishAsync publishes an event asynchronously.
 func (eb *eventBus) PublishAsync(event *Event) error {
+	if err := eb.validateSchema(event); err != nil {
+		return err
+	}
+	eb.forward(event)
+	eb.storeEvent(event)
+	return eb.publishAsync(event)
+}
+
+// publishAsync queues an event for local async processing without forwarding
+// it to bridges.
+func (eb *eventBus) publishAsync(event *Event) error {
 	eb.mu.Lock()
 	eb.stats.EventsPublished++
 	eb.stats.LastEventTime = time.Now()
 	eb.mu.Unlock()
 
-	select {
-	case eb.asyncQueue <- event:
+	if eb.durable != nil {
+		if err := eb.durable.Append(event); err != nil {
+			return fmt.Errorf("failed to persist async event: %w", err)
+		}
+	}
+
+	if eb.tryEnqueue(event) {
 		return nil
-	default:
+	}
+
+	if eb.durable == nil {
 		return fmt.Errorf("async queue is full")
 	}
+
+	// The event is already on disk, so hold it until a worker is free
+	eb.pushBacklog(event)
+	return nil
 }
 
 // Subscribe subscribes to events of a specific type.
@@ -151,7 +274,7 @@ func (eb *eventBus) Subscribe(eventType EventType, handler Handler) (Subscriptio
 	eb.stats.ActiveSubscriptions++
 	eb.mu.Unlock()
 
-	eb.logger.Info("Subscribed to event type", 
+	eb.logger.Info("Subscribed to event type",
 		zap.String("type", string(eventType)),
 		zap.String("handler", handler.GetName()))
 
@@ -160,7 +283,11 @@ func (eb *eventBus) Subscribe(eventType EventType, handler Handler) (Subscriptio
 
 // Unsubscribe removes a subscription.
 func (eb *eventBus) Unsubscribe(subscription Subscription) error {
-	eb.registry.Unregister(subscription.EventType(), subscription.ID())
+	if group := subscription.Group(); group != "" {
+		eb.unsubscribeGroup(subscription, group)
+	} else {
+		eb.registry.Unregister(subscription.EventType(), subscription.ID())
+	}
 
 	eb.mu.Lock()
 	eb.stats.ActiveSubscriptions--
@@ -174,11 +301,32 @@ func (eb *eventBus) Unsubscribe(subscription Subscription) error {
 }
 
 // Close shuts down the event bus.
+// Events still queued in memory are dropped unless a durable queue is
+// configured, in which case they are redelivered on the next start.
 func (eb *eventBus) Close() error {
-	close(eb.stopChan)
-	close(eb.asyncQueue)
-	eb.wg.Wait()
-	return nil
+	var err error
+	eb.closeOnce.Do(func() {
+		close(eb.stopChan)
+		if eb.bridgeCancel != nil {
+			eb.bridgeCancel()
+		}
+		eb.wg.Wait()
+
+		err = eb.closeBridges()
+
+		if eb.durable != nil {
+			if closeErr := eb.durable.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+
+		if eb.store != nil {
+			if closeErr := eb.store.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
 }
 
 // GetStats returns event bus statistics.
@@ -187,23 +335,62 @@ func (eb *eventBus) GetStats() *Stats {
 	defer eb.mu.RUnlock()
 
 	stats := *eb.stats
+	stats.QueueDepth = eb.queueDepths()
+	stats.ScheduledEvents = eb.scheduler.len()
 	return &stats
 }
 
 // SetMiddleware sets global middleware.
 func (eb *eventBus) SetMiddleware(middleware ...Middleware) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
 	eb.middleware = middleware
 }
 
+// Metrics returns the metrics collected by the built-in metrics middleware.
+func (eb *eventBus) Metrics() map[string]interface{} {
+	if eb.metrics == nil {
+		return nil
+	}
+	return eb.metrics.GetMetrics()
+}
+
+// flushMetrics periodically logs and resets the collected metrics.
+func (eb *eventBus) flushMetrics(interval time.Duration) {
+	defer eb.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if metrics := eb.metrics.Flush(); len(metrics) > 0 {
+				eb.logger.Debug("Event metrics", zap.Any("metrics", metrics))
+			}
+		case <-eb.stopChan:
+			return
+		}
+	}
+}
+
+// DeadLetters returns the queue of async events that failed processing.
+func (eb *eventBus) DeadLetters() DeadLetterQueue {
+	return eb.deadLetters
+}
+
 // processEvent processes an event through handlers.
 func (eb *eventBus) processEvent(ctx context.Context, event *Event, handlers []Handler) error {
+	ctx = withEventTrace(ctx, event)
+
 	// Apply middleware
 	next := func(ctx context.Context, event *Event) error {
 		return eb.executeHandlers(ctx, event, handlers)
 	}
 
-	for i := len(eb.middleware) - 1; i >= 0; i-- {
-		middleware := eb.middleware[i]
+	chain := eb.middlewareChain()
+	for i := len(chain) - 1; i >= 0; i-- {
+		middleware := chain[i]
 		next = func(mw Middleware, n func(context.Context, *Event) error) func(context.Context, *Event) error {
 			return func(ctx context.Context, event *Event) error {
 				return mw.Process(ctx, event, n)
@@ -232,7 +419,9 @@ func (eb *eventBus) executeHandlers(ctx context.Context, event *Event, handlers
 			handlerCtx, cancel := context.WithTimeout(ctx, h.GetTimeout())
 			defer cancel()
 
+			handlerCtx, endSpan := eb.startHandlerSpan(handlerCtx, event, h)
 			err := h.Handle(handlerCtx, event)
+			endSpan(err)
 			if err != nil {
 				mu.Lock()
 				errors = append(errors, fmt.Errorf("handler %s: %w", h.GetName(), err))
@@ -269,28 +458,117 @@ func (eb *eventBus) asyncWorker(workerID int) {
 
 	eb.logger.Debug("Started async worker", zap.Int("worker_id", workerID))
 
+	for {
+		event, ok := eb.dequeue()
+		if !ok {
+			eb.logger.Debug("Stopping async worker", zap.Int("worker_id", workerID))
+			return
+		}
+		eb.processAsyncEvent(workerID, event)
+	}
+}
+
+// processAsyncEvent runs the handlers for an event taken from the async queue
+// and acknowledges it in the durable queue once processing has finished.
+func (eb *eventBus) processAsyncEvent(workerID int, event *Event) {
+	handlers := eb.registry.GetHandlers(event.Type)
+	if len(handlers) == 0 {
+		eb.logger.Debug("No handlers for async event type",
+			zap.String("type", string(event.Type)),
+			zap.Int("worker_id", workerID))
+	} else {
+		ctx := context.Background()
+		err := eb.processEvent(ctx, event, handlers)
+		if err != nil {
+			eb.logger.Error("Async event processing failed",
+				zap.String("type", string(event.Type)),
+				zap.Int("worker_id", workerID),
+				zap.Error(err))
+
+			// Retry middleware has already run, so the failure is final
+			eb.deadLetter(event, err)
+		}
+	}
+
+	if eb.durable != nil {
+		if err := eb.durable.Ack(event.ID); err != nil {
+			eb.logger.Error("Failed to acknowledge async event",
+				zap.String("id", event.ID),
+				zap.Error(err))
+		}
+	}
+}
+
+// deadLetter moves an event that cannot be processed to the dead-letter queue.
+func (eb *eventBus) deadLetter(event *Event, err error) {
+	eb.deadLetters.add(event, err)
+
+	eb.mu.Lock()
+	eb.stats.EventsDeadLettered++
+	eb.mu.Unlock()
+}
+
+// recoverPending requeues events that were persisted but never acknowledged.
+func (eb *eventBus) recoverPending() {
+	events, err := eb.durable.Pending()
+	if err != nil {
+		eb.logger.Error("Failed to read pending async events", zap.Error(err))
+		return
+	}
+
+	for _, event := range events {
+		eb.pushBacklog(event)
+	}
+
+	if len(events) > 0 {
+		eb.mu.Lock()
+		eb.stats.EventsRecovered += int64(len(events))
+		eb.mu.Unlock()
+
+		eb.logger.Info("Recovered pending async events", zap.Int("count", len(events)))
+	}
+}
+
+// pushBacklog holds a persisted event until the async queue has room.
+func (eb *eventBus) pushBacklog(event *Event) {
+	eb.backlogMu.Lock()
+	eb.backlog = append(eb.backlog, event)
+	eb.backlogMu.Unlock()
+
+	select {
+	case eb.backlogReady <- struct{}{}:
+	default:
+	}
+}
+
+// backlogFeeder moves backlogged events into the async queue as workers free up.
+func (eb *eventBus) backlogFeeder() {
+	defer eb.wg.Done()
+
 	for {
 		select {
-		case event := <-eb.asyncQueue:
-			handlers := eb.registry.GetHandlers(event.Type)
-			if len(handlers) == 0 {
-				eb.logger.Debug("No handlers for async event type", 
-					zap.String("type", string(event.Type)),
-					zap.Int("worker_id", workerID))
-				continue
+		case <-eb.backlogReady:
+		case <-eb.stopChan:
+			return
+		}
+
+		for {
+			eb.backlogMu.Lock()
+			if len(eb.backlog) == 0 {
+				eb.backlogMu.Unlock()
+				break
 			}
+			event := eb.backlog[0]
+			eb.backlogMu.Unlock()
 
-			ctx := context.Background()
-			err := eb.processEvent(ctx, event, handlers)
-			if err != nil {
-				eb.logger.Error("Async event processing failed",
-					zap.String("type", string(event.Type)),
-					zap.Int("worker_id", workerID),
-					zap.Error(err))
+			if !eb.enqueue(event) {
+				return
 			}
-		case <-eb.stopChan:
-			eb.logger.Debug("Stopping async worker", zap.Int("worker_id", workerID))
-			return
+
+			eb.backlogMu.Lock()
+			eb.backlog[0] = nil
+			eb.backlog = eb.backlog[1:]
+			eb.backlogMu.Unlock()
 		}
 	}
 }
@@ -299,6 +577,7 @@ func (eb *eventBus) asyncWorker(workerID int) {
 type subscription struct {
 	id        string
 	eventType EventType
+	group     string
 	handler   Handler
 	bus       *eventBus
 }
@@ -313,6 +592,11 @@ func (s *subscription) EventType() EventType {
 	return s.eventType
 }
 
+// Group returns the consumer group of the subscription.
+func (s *subscription) Group() string {
+	return s.group
+}
+
 // Handler returns the event handler.
 func (s *subscription) Handler() Handler {
 	return s.handler
@@ -322,5 +606,3 @@ func (s *subscription) Handler() Handler {
 func (s *subscription) Unsubscribe() error {
 	return s.bus.Unsubscribe(s)
 }
-
-
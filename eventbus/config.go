@@ -16,42 +16,102 @@ type Config struct {
 	// AsyncQueueSize is the size of the async queue.
 	AsyncQueueSize int
 
+	// OrderedDelivery processes async events with the same Event.Key
+	// sequentially while events with different keys run in parallel.
+	OrderedDelivery bool
+
+	// StarvationLimit is how many higher priority async events may be
+	// processed in a row while lower priority events wait. Zero disables
+	// starvation protection.
+	StarvationLimit int
+
+	// SchedulerTick is the resolution of delayed and scheduled publishing.
+	SchedulerTick time.Duration
+
 	// Logger is the logger instance.
 	Logger *zap.Logger
 
-	// DefaultTimeout is the default timeout for event processing.
+	// DurableQueue persists async events until they are processed.
+	// When nil, async events are only held in memory.
+	DurableQueue DurableQueue
+
+	// EventStore records every published event so it can be replayed.
+	// When nil, events are not recorded.
+	EventStore EventStore
+
+	// Bridges forward events to and from external messaging systems.
+	Bridges []Bridge
+
+	// Tracer starts a span around each handler invocation. Trace context
+	// recorded in event metadata is restored in handler contexts either way.
+	Tracer Tracer
+
+	// StrictSchemas rejects events whose payload does not match the schema
+	// registered for their type. Otherwise mismatches are only logged.
+	StrictSchemas bool
+
+	// DeadLetterQueueSize is the maximum number of failed async events kept
+	// for inspection. The oldest are dropped when full; zero means unbounded.
+	DeadLetterQueueSize int
+
+	// Middleware configures the built-in middleware that New installs ahead
+	// of any middleware passed to SetMiddleware. Each middleware runs only
+	// when its Enabled field is set; all are off by default.
+	Middleware MiddlewareConfig
+
+	// DefaultTimeout enables the timeout middleware when positive.
+	//
+	// Deprecated: use Middleware.Timeout or WithDefaultTimeout.
 	DefaultTimeout time.Duration
 
-	// MaxRetries is the maximum number of retries for failed events.
+	// MaxRetries enables the retry middleware when positive.
+	//
+	// Deprecated: use Middleware.Retry or WithMaxRetries.
 	MaxRetries int
 
-	// RetryDelay is the delay between retries.
+	// RetryDelay is the delay before the first retry when positive.
+	//
+	// Deprecated: use Middleware.Retry or WithRetryDelay.
 	RetryDelay time.Duration
 
-	// EnableMetrics enables metrics collection.
+	// EnableMetrics enables the metrics middleware.
+	//
+	// Deprecated: use Middleware.Metrics.Enabled or WithMetrics.
 	EnableMetrics bool
 
-	// EnableLogging enables event logging.
+	// EnableLogging enables the logging middleware.
+	//
+	// Deprecated: use Middleware.Logging.Enabled or WithLogging.
 	EnableLogging bool
 
-	// EnableValidation enables event validation.
+	// EnableValidation enables the validation middleware.
+	//
+	// Deprecated: use Middleware.Validation.Enabled or WithValidation.
 	EnableValidation bool
 
-	// EnableRateLimit enables rate limiting.
+	// EnableRateLimit enables the rate limit middleware.
+	//
+	// Deprecated: use Middleware.RateLimit.Enabled or WithRateLimit.
 	EnableRateLimit bool
 
-	// EnableCircuitBreaker enables circuit breaker.
+	// EnableCircuitBreaker enables the circuit breaker middleware.
+	//
+	// Deprecated: use Middleware.CircuitBreaker.Enabled or WithCircuitBreaker.
 	EnableCircuitBreaker bool
 
-	// EnableSanitization enables data sanitization.
+	// EnableSanitization enables the sanitization middleware.
+	//
+	// Deprecated: use Middleware.Sanitization.Enabled or WithSanitization.
 	EnableSanitization bool
-
-	// Middleware contains middleware configuration.
-	Middleware MiddlewareConfig
 }
 
 // MiddlewareConfig contains middleware-specific configuration.
+// Enabled middleware runs in the order of the fields below, so sanitized
+// metadata is what gets logged and retries run inside the circuit breaker.
 type MiddlewareConfig struct {
+	// Sanitization configures sanitization middleware.
+	Sanitization SanitizationConfig
+
 	// Logging configures logging middleware.
 	Logging LoggingConfig
 
@@ -72,9 +132,6 @@ type MiddlewareConfig struct {
 
 	// Timeout configures timeout middleware.
 	Timeout TimeoutConfig
-
-	// Sanitization configures sanitization middleware.
-	Sanitization SanitizationConfig
 }
 
 // LoggingConfig contains logging middleware configuration.
@@ -106,7 +163,8 @@ type MetricsConfig struct {
 	// CollectErrors collects error metrics.
 	CollectErrors bool
 
-	// FlushInterval is the interval for flushing metrics.
+	// FlushInterval is how often the collected metrics are logged at debug
+	// level and reset. Zero keeps metrics until the bus is closed.
 	FlushInterval time.Duration
 }
 
@@ -115,10 +173,11 @@ type ValidationConfig struct {
 	// Enabled enables validation middleware.
 	Enabled bool
 
-	// StrictMode enables strict validation.
+	// StrictMode rejects events with required fields whose data is not an
+	// object, instead of letting them through unchecked.
 	StrictMode bool
 
-	// RequiredFields are fields that must be present.
+	// RequiredFields are top-level data fields that must be present.
 	RequiredFields map[EventType][]string
 
 	// CustomValidators are custom validation functions.
@@ -142,8 +201,8 @@ type RateLimitConfig struct {
 
 // RateLimit represents a rate limit configuration.
 type RateLimit struct {
-	Rate int
-	Per  time.Duration
+	Rate int           `json:"rate" yaml:"rate"`
+	Per  time.Duration `json:"per" yaml:"per"`
 }
 
 // CircuitBreakerConfig contains circuit breaker middleware configuration.
@@ -163,8 +222,8 @@ type CircuitBreakerConfig struct {
 
 // CircuitBreakerSettings represents circuit breaker settings.
 type CircuitBreakerSettings struct {
-	FailureThreshold int
-	Timeout          time.Duration
+	FailureThreshold int           `json:"failure_threshold" yaml:"failure_threshold"`
+	Timeout          time.Duration `json:"timeout" yaml:"timeout"`
 }
 
 // RetryConfig contains retry middleware configuration.
@@ -212,52 +271,58 @@ type SanitizationConfig struct {
 	CustomSanitizers map[string]func(interface{}) interface{}
 }
 
-// DefaultConfig returns the default configuration.
+// DefaultConfig returns the default configuration used by New.
+//
+// Every built-in middleware is disabled. Earlier versions of DefaultConfig
+// enabled sanitization, logging and metrics, and set a 30 second timeout
+// with 3 retries, although New did not use DefaultConfig and installed none
+// of them; callers that relied on those values must now opt in with the
+// matching With* options.
 func DefaultConfig() *Config {
 	return &Config{
-		AsyncWorkers:   10,
-		AsyncQueueSize: 1000,
-		Logger:         zap.NewNop(),
-		DefaultTimeout: 30 * time.Second,
-		MaxRetries:     3,
-		RetryDelay:     1 * time.Second,
-		EnableMetrics:  true,
-		EnableLogging:  true,
-		EnableValidation: false,
-		EnableRateLimit: false,
-		EnableCircuitBreaker: false,
-		EnableSanitization: true,
+		AsyncWorkers:        10,
+		AsyncQueueSize:      1000,
+		Logger:              zap.NewNop(),
+		DeadLetterQueueSize: 1000,
+		StarvationLimit:     defaultStarvationLimit,
+		SchedulerTick:       defaultSchedulerTick,
 		Middleware: MiddlewareConfig{
+			Sanitization: SanitizationConfig{
+				Enabled:              false,
+				SensitiveFields:      []string{"password", "token", "secret", "api_key"},
+				SanitizationFunction: func(s string) string { return "[REDACTED]" },
+				CustomSanitizers:     DefaultSanitizers(),
+			},
 			Logging: LoggingConfig{
-				Enabled:         true,
+				Enabled:         false,
 				Level:           zap.InfoLevel,
 				IncludeData:     false,
 				IncludeMetadata: true,
 			},
 			Metrics: MetricsConfig{
-				Enabled:         true,
+				Enabled:          false,
 				CollectDurations: true,
-				CollectCounts:   true,
-				CollectErrors:   true,
-				FlushInterval:   1 * time.Minute,
+				CollectCounts:    true,
+				CollectErrors:    true,
+				FlushInterval:    1 * time.Minute,
 			},
 			Validation: ValidationConfig{
-				Enabled:         false,
-				StrictMode:      false,
-				RequiredFields:  make(map[EventType][]string),
+				Enabled:          false,
+				StrictMode:       false,
+				RequiredFields:   make(map[EventType][]string),
 				CustomValidators: make(map[EventType]func(*Event) error),
 			},
 			RateLimit: RateLimitConfig{
-				Enabled:     false,
-				DefaultRate: 100,
-				DefaultPer:  1 * time.Minute,
+				Enabled:      false,
+				DefaultRate:  100,
+				DefaultPer:   1 * time.Minute,
 				PerEventType: make(map[EventType]RateLimit),
 			},
 			CircuitBreaker: CircuitBreakerConfig{
-				Enabled:                false,
+				Enabled:                 false,
 				DefaultFailureThreshold: 5,
-				DefaultTimeout:         30 * time.Second,
-				PerEventType:           make(map[EventType]CircuitBreakerSettings),
+				DefaultTimeout:          30 * time.Second,
+				PerEventType:            make(map[EventType]CircuitBreakerSettings),
 			},
 			Retry: RetryConfig{
 				Enabled:           false,
@@ -271,16 +336,70 @@ func DefaultConfig() *Config {
 				DefaultTimeout: 30 * time.Second,
 				PerEventType:   make(map[EventType]time.Duration),
 			},
-			Sanitization: SanitizationConfig{
-				Enabled:              true,
-				SensitiveFields:      []string{"password", "token", "secret", "api_key"},
-				SanitizationFunction: func(s string) string { return "[REDACTED]" },
-				CustomSanitizers:     DefaultSanitizers(),
-			},
 		},
 	}
 }
 
+// applyDeprecated maps the deprecated top-level fields onto Middleware, so
+// options setting them keep working. They can only enable middleware and
+// override settings, never disable what Middleware enables.
+func (c *Config) applyDeprecated() {
+	mc := &c.Middleware
+	if c.DefaultTimeout > 0 {
+		mc.Timeout.DefaultTimeout = c.DefaultTimeout
+		mc.Timeout.Enabled = true
+	}
+	if c.MaxRetries > 0 {
+		mc.Retry.MaxRetries = c.MaxRetries
+		mc.Retry.Enabled = true
+	}
+	if c.RetryDelay > 0 {
+		mc.Retry.RetryDelay = c.RetryDelay
+	}
+	mc.Metrics.Enabled = mc.Metrics.Enabled || c.EnableMetrics
+	mc.Logging.Enabled = mc.Logging.Enabled || c.EnableLogging
+	mc.Validation.Enabled = mc.Validation.Enabled || c.EnableValidation
+	mc.RateLimit.Enabled = mc.RateLimit.Enabled || c.EnableRateLimit
+	mc.CircuitBreaker.Enabled = mc.CircuitBreaker.Enabled || c.EnableCircuitBreaker
+	mc.Sanitization.Enabled = mc.Sanitization.Enabled || c.EnableSanitization
+}
+
+// builtinMiddleware returns the enabled built-in middleware in the order
+// documented on MiddlewareConfig, along with the metrics middleware when
+// metrics are enabled.
+func (c *Config) builtinMiddleware() ([]Middleware, *MetricsMiddleware) {
+	var middleware []Middleware
+	var metrics *MetricsMiddleware
+
+	mc := c.Middleware
+	if mc.Sanitization.Enabled {
+		middleware = append(middleware, newSanitizationMiddlewareFromConfig(mc.Sanitization))
+	}
+	if mc.Logging.Enabled {
+		middleware = append(middleware, newLoggingMiddlewareFromConfig(c.Logger, mc.Logging))
+	}
+	if mc.Metrics.Enabled {
+		metrics = newMetricsMiddlewareFromConfig(mc.Metrics)
+		middleware = append(middleware, metrics)
+	}
+	if mc.Validation.Enabled {
+		middleware = append(middleware, newValidationMiddlewareFromConfig(mc.Validation))
+	}
+	if mc.RateLimit.Enabled {
+		middleware = append(middleware, newRateLimitMiddlewareFromConfig(mc.RateLimit))
+	}
+	if mc.CircuitBreaker.Enabled {
+		middleware = append(middleware, newCircuitBreakerMiddlewareFromConfig(mc.CircuitBreaker))
+	}
+	if mc.Retry.Enabled {
+		middleware = append(middleware, newRetryMiddlewareFromConfig(mc.Retry))
+	}
+	if mc.Timeout.Enabled {
+		middleware = append(middleware, newTimeoutMiddlewareFromConfig(mc.Timeout))
+	}
+	return middleware, metrics
+}
+
 // Option is a function that configures the event bus.
 type Option func(*Config)
 
@@ -298,6 +417,27 @@ func WithAsyncQueueSize(size int) Option {
 	}
 }
 
+// WithOrderedDelivery enables sequential processing of async events that share a key.
+func WithOrderedDelivery(enabled bool) Option {
+	return func(c *Config) {
+		c.OrderedDelivery = enabled
+	}
+}
+
+// WithStarvationLimit sets how many higher priority events may jump ahead of waiting lower priority events.
+func WithStarvationLimit(limit int) Option {
+	return func(c *Config) {
+		c.StarvationLimit = limit
+	}
+}
+
+// WithSchedulerTick sets the resolution of delayed and scheduled publishing.
+func WithSchedulerTick(tick time.Duration) Option {
+	return func(c *Config) {
+		c.SchedulerTick = tick
+	}
+}
+
 // WithLogger sets the logger.
 func WithLogger(logger *zap.Logger) Option {
 	return func(c *Config) {
@@ -305,66 +445,112 @@ func WithLogger(logger *zap.Logger) Option {
 	}
 }
 
-// WithDefaultTimeout sets the default timeout.
+// WithDurableQueue persists async events so they survive a full queue or a crash.
+func WithDurableQueue(queue DurableQueue) Option {
+	return func(c *Config) {
+		c.DurableQueue = queue
+	}
+}
+
+// WithEventStore records published events so they can be replayed.
+func WithEventStore(store EventStore) Option {
+	return func(c *Config) {
+		c.EventStore = store
+	}
+}
+
+// WithBridge connects the event bus to an external messaging system.
+func WithBridge(bridge Bridge) Option {
+	return func(c *Config) {
+		c.Bridges = append(c.Bridges, bridge)
+	}
+}
+
+// WithTracer starts a span around each handler invocation.
+func WithTracer(tracer Tracer) Option {
+	return func(c *Config) {
+		c.Tracer = tracer
+	}
+}
+
+// WithStrictSchemas rejects published events that do not match their registered schema.
+func WithStrictSchemas(enabled bool) Option {
+	return func(c *Config) {
+		c.StrictSchemas = enabled
+	}
+}
+
+// WithDeadLetterQueueSize sets how many failed async events are kept.
+func WithDeadLetterQueueSize(size int) Option {
+	return func(c *Config) {
+		c.DeadLetterQueueSize = size
+	}
+}
+
+// WithDefaultTimeout enables the timeout middleware with the given timeout.
+// A zero timeout disables it.
 func WithDefaultTimeout(timeout time.Duration) Option {
 	return func(c *Config) {
-		c.DefaultTimeout = timeout
+		c.Middleware.Timeout.DefaultTimeout = timeout
+		c.Middleware.Timeout.Enabled = timeout > 0
 	}
 }
 
-// WithMaxRetries sets the maximum number of retries.
+// WithMaxRetries enables the retry middleware with the given number of
+// retries. Zero disables it.
 func WithMaxRetries(maxRetries int) Option {
 	return func(c *Config) {
-		c.MaxRetries = maxRetries
+		c.Middleware.Retry.MaxRetries = maxRetries
+		c.Middleware.Retry.Enabled = maxRetries > 0
 	}
 }
 
-// WithRetryDelay sets the retry delay.
+// WithRetryDelay sets the delay before the first retry.
 func WithRetryDelay(delay time.Duration) Option {
 	return func(c *Config) {
-		c.RetryDelay = delay
+		c.Middleware.Retry.RetryDelay = delay
 	}
 }
 
 // WithMetrics enables or disables metrics.
 func WithMetrics(enabled bool) Option {
 	return func(c *Config) {
-		c.EnableMetrics = enabled
+		c.Middleware.Metrics.Enabled = enabled
 	}
 }
 
 // WithLogging enables or disables logging.
 func WithLogging(enabled bool) Option {
 	return func(c *Config) {
-		c.EnableLogging = enabled
+		c.Middleware.Logging.Enabled = enabled
 	}
 }
 
 // WithValidation enables or disables validation.
 func WithValidation(enabled bool) Option {
 	return func(c *Config) {
-		c.EnableValidation = enabled
+		c.Middleware.Validation.Enabled = enabled
 	}
 }
 
 // WithRateLimit enables or disables rate limiting.
 func WithRateLimit(enabled bool) Option {
 	return func(c *Config) {
-		c.EnableRateLimit = enabled
+		c.Middleware.RateLimit.Enabled = enabled
 	}
 }
 
 // WithCircuitBreaker enables or disables circuit breaker.
 func WithCircuitBreaker(enabled bool) Option {
 	return func(c *Config) {
-		c.EnableCircuitBreaker = enabled
+		c.Middleware.CircuitBreaker.Enabled = enabled
 	}
 }
 
 // WithSanitization enables or disables sanitization.
 func WithSanitization(enabled bool) Option {
 	return func(c *Config) {
-		c.EnableSanitization = enabled
+		c.Middleware.Sanitization.Enabled = enabled
 	}
 }
 
@@ -422,4 +608,4 @@ func WithSanitizationConfig(config SanitizationConfig) Option {
 	return func(c *Config) {
 		c.Middleware.Sanitization = config
 	}
-}
\ No newline at end of file
+}
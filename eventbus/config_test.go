@@ -0,0 +1,164 @@
+package eventbus
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestDefaultConfig_MiddlewareOptIn(t *testing.T) {
+	middleware := DefaultConfig().Middleware
+	if middleware.Sanitization.Enabled || middleware.Logging.Enabled || middleware.Metrics.Enabled {
+		t.Error("Expected sanitization, logging and metrics to be off by default")
+	}
+	if middleware.Validation.Enabled || middleware.RateLimit.Enabled || middleware.CircuitBreaker.Enabled {
+		t.Error("Expected validation, rate limit and circuit breaker to be off by default")
+	}
+	if middleware.Logging.Level != zap.InfoLevel {
+		t.Errorf("Expected logging at info level once enabled, got %v", middleware.Logging.Level)
+	}
+
+	bus := New()
+	defer bus.Close()
+
+	var seen interface{}
+	_, err := bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, event *Event) error {
+		seen = event.Metadata["password"]
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(NewEvent(TestEventTypeAPICreate, nil).WithMetadata("password", "hunter2")); err != nil {
+		t.Fatal(err)
+	}
+	if seen != "hunter2" {
+		t.Errorf("Expected metadata to pass through unsanitized by default, got %v", seen)
+	}
+}
+
+func TestNew_BuiltinMiddleware(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+
+	if bus.Metrics() != nil {
+		t.Error("Expected built-in middleware to be off by default")
+	}
+
+	bus = New(WithSanitization(true), WithMetrics(true))
+	defer bus.Close()
+
+	var seen interface{}
+	_, err := bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, event *Event) error {
+		seen = event.Metadata["password"]
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := NewEvent(TestEventTypeAPICreate, nil).WithMetadata("password", "hunter2")
+	if err := bus.Publish(event); err != nil {
+		t.Fatal(err)
+	}
+
+	if seen != "[REDACTED]" {
+		t.Errorf("Expected sanitization to redact password, got %v", seen)
+	}
+	if password := event.Metadata["password"]; password != "hunter2" {
+		t.Errorf("Expected the publisher's metadata to be left alone, got %v", password)
+	}
+	if count := bus.Metrics()["events.api.create.count"]; count != int64(1) {
+		t.Errorf("Expected metrics to count 1 event, got %v", count)
+	}
+}
+
+func TestNew_DeprecatedConfig(t *testing.T) {
+	bus := New(func(c *Config) {
+		c.EnableSanitization = true
+		c.EnableMetrics = true
+		c.MaxRetries = 1
+		c.RetryDelay = time.Millisecond
+	})
+	defer bus.Close()
+
+	if bus.Metrics() == nil {
+		t.Error("Expected EnableMetrics to enable metrics")
+	}
+
+	var calls int32
+	var seen interface{}
+	_, err := bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, event *Event) error {
+		seen = event.Metadata["password"]
+		if atomic.AddInt32(&calls, 1) < 2 {
+			return context.DeadlineExceeded
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Publish(NewEvent(TestEventTypeAPICreate, nil).WithMetadata("password", "hunter2")); err != nil {
+		t.Fatalf("Expected MaxRetries to retry the handler, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 handler calls, got %d", calls)
+	}
+	if seen != "[REDACTED]" {
+		t.Errorf("Expected EnableSanitization to redact password, got %v", seen)
+	}
+}
+
+func TestNew_MiddlewareOptions(t *testing.T) {
+	validation := DefaultConfig().Middleware.Validation
+	validation.Enabled = true
+	validation.RequiredFields[TestEventTypeAPICreate] = []string{"api_id"}
+
+	bus := New(
+		WithMetrics(false),
+		WithSanitization(false),
+		WithValidationConfig(validation),
+		WithMaxRetries(2),
+		WithRetryDelay(0),
+	)
+	defer bus.Close()
+
+	if bus.Metrics() != nil {
+		t.Error("Expected no metrics when metrics are disabled")
+	}
+
+	var calls int32
+	var seen interface{}
+	_, err := bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, event *Event) error {
+		seen = event.Metadata["password"]
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return context.DeadlineExceeded
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Publish(NewEvent(TestEventTypeAPICreate, map[string]interface{}{"name": "test"})); err == nil {
+		t.Error("Expected an event missing a required field to be rejected")
+	}
+	if calls != 0 {
+		t.Errorf("Expected rejected event not to reach handlers, got %d calls", calls)
+	}
+
+	event := NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": "1"}).WithMetadata("password", "hunter2")
+	if err := bus.Publish(event); err != nil {
+		t.Fatalf("Expected retries to succeed, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 handler calls, got %d", calls)
+	}
+	if seen != "hunter2" {
+		t.Errorf("Expected metadata to be left alone with sanitization disabled, got %v", seen)
+	}
+}
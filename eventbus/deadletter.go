@@ -0,0 +1,186 @@
+// Package eventbus provides the dead-letter queue for failed async events.
+package eventbus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DeadLetter is an async event whose processing failed after all retries.
+type DeadLetter struct {
+	// Event is the event that failed.
+	Event *Event `json:"event"`
+
+	// Error is the error returned by the final processing attempt.
+	Error string `json:"error"`
+
+	// FailedAt is when the event was dead-lettered.
+	FailedAt time.Time `json:"failed_at"`
+
+	// Requeues is how many times the event has been requeued from the dead-letter queue.
+	Requeues int `json:"requeues"`
+}
+
+// DeadLetterQueue holds failed async events for inspection and recovery.
+type DeadLetterQueue interface {
+	// List returns all dead letters, oldest first.
+	List() []*DeadLetter
+
+	// Get returns the dead letter for an event ID.
+	Get(eventID string) (*DeadLetter, bool)
+
+	// Len returns the number of dead letters.
+	Len() int
+
+	// Requeue removes a dead letter and publishes its event asynchronously again.
+	Requeue(eventID string) error
+
+	// RequeueAll requeues every dead letter and returns how many were requeued.
+	RequeueAll() (int, error)
+
+	// Purge removes a dead letter without reprocessing it.
+	Purge(eventID string) error
+
+	// PurgeAll removes every dead letter and returns how many were removed.
+	PurgeAll() int
+}
+
+// deadLetterQueue is the in-memory DeadLetterQueue used by the event bus.
+type deadLetterQueue struct {
+	bus      *eventBus
+	maxSize  int
+	letters  []*DeadLetter
+	requeues map[string]int
+	mu       sync.Mutex
+}
+
+// newDeadLetterQueue creates a dead-letter queue that keeps at most maxSize
+// letters, dropping the oldest when full. A non-positive maxSize means unbounded.
+func newDeadLetterQueue(bus *eventBus, maxSize int) *deadLetterQueue {
+	return &deadLetterQueue{
+		bus:      bus,
+		maxSize:  maxSize,
+		requeues: make(map[string]int),
+	}
+}
+
+// add records a failed event.
+func (q *deadLetterQueue) add(event *Event, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letter := &DeadLetter{
+		Event:    event,
+		Error:    err.Error(),
+		FailedAt: time.Now(),
+		Requeues: q.requeues[event.ID],
+	}
+	delete(q.requeues, event.ID)
+
+	q.letters = append(q.letters, letter)
+	if q.maxSize > 0 && len(q.letters) > q.maxSize {
+		q.letters[0] = nil
+		q.letters = q.letters[1:]
+	}
+}
+
+// List returns all dead letters, oldest first.
+func (q *deadLetterQueue) List() []*DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result := make([]*DeadLetter, len(q.letters))
+	for i, letter := range q.letters {
+		copied := *letter
+		result[i] = &copied
+	}
+	return result
+}
+
+// Get returns the dead letter for an event ID.
+func (q *deadLetterQueue) Get(eventID string) (*DeadLetter, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if i := q.indexOf(eventID); i >= 0 {
+		copied := *q.letters[i]
+		return &copied, true
+	}
+	return nil, false
+}
+
+// Len returns the number of dead letters.
+func (q *deadLetterQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.letters)
+}
+
+// Requeue removes a dead letter and publishes its event asynchronously again.
+func (q *deadLetterQueue) Requeue(eventID string) error {
+	q.mu.Lock()
+	i := q.indexOf(eventID)
+	if i < 0 {
+		q.mu.Unlock()
+		return fmt.Errorf("dead letter not found: %s", eventID)
+	}
+	letter := q.letters[i]
+	q.letters = append(q.letters[:i], q.letters[i+1:]...)
+	q.requeues[eventID] = letter.Requeues + 1
+	q.mu.Unlock()
+
+	if err := q.bus.publishAsync(letter.Event); err != nil {
+		q.mu.Lock()
+		q.letters = append(q.letters, letter)
+		delete(q.requeues, eventID)
+		q.mu.Unlock()
+		return fmt.Errorf("failed to requeue event %s: %w", eventID, err)
+	}
+	return nil
+}
+
+// RequeueAll requeues every dead letter and returns how many were requeued.
+func (q *deadLetterQueue) RequeueAll() (int, error) {
+	count := 0
+	for _, letter := range q.List() {
+		if err := q.Requeue(letter.Event.ID); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Purge removes a dead letter without reprocessing it.
+func (q *deadLetterQueue) Purge(eventID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.indexOf(eventID)
+	if i < 0 {
+		return fmt.Errorf("dead letter not found: %s", eventID)
+	}
+	q.letters = append(q.letters[:i], q.letters[i+1:]...)
+	return nil
+}
+
+// PurgeAll removes every dead letter and returns how many were removed.
+func (q *deadLetterQueue) PurgeAll() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	count := len(q.letters)
+	q.letters = nil
+	return count
+}
+
+// indexOf returns the position of an event's dead letter, or -1.
+func (q *deadLetterQueue) indexOf(eventID string) int {
+	for i, letter := range q.letters {
+		if letter.Event.ID == eventID {
+			return i
+		}
+	}
+	return -1
+}
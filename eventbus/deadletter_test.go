@@ -0,0 +1,125 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitForDeadLetters(t *testing.T, bus EventBus, count int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for bus.DeadLetters().Len() < count {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d dead letters, got %d", count, bus.DeadLetters().Len())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDeadLetters_RequeueAndPurge(t *testing.T) {
+	bus := New(WithAsyncWorkers(1))
+	defer bus.Close()
+
+	var fail atomic.Bool
+	fail.Store(true)
+	processed := make(chan string, 2)
+
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		if fail.Load() {
+			return errors.New("backend unavailable")
+		}
+		processed <- e.ID
+		return nil
+	}))
+
+	first := NewEvent(TestEventTypeAPICreate, "first")
+	second := NewEvent(TestEventTypeAPICreate, "second")
+	bus.PublishAsync(first)
+	bus.PublishAsync(second)
+
+	waitForDeadLetters(t, bus, 2)
+
+	letter, ok := bus.DeadLetters().Get(first.ID)
+	if !ok {
+		t.Fatal("Expected first event to be dead-lettered")
+	}
+	if letter.Error == "" || letter.FailedAt.IsZero() {
+		t.Errorf("Expected failure details, got %+v", letter)
+	}
+	if stats := bus.GetStats(); stats.EventsDeadLettered != 2 {
+		t.Errorf("Expected 2 dead-lettered events, got %d", stats.EventsDeadLettered)
+	}
+
+	if err := bus.DeadLetters().Purge(second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.DeadLetters().Purge(second.ID); err == nil {
+		t.Error("Expected error purging an unknown dead letter")
+	}
+
+	fail.Store(false)
+	if err := bus.DeadLetters().Requeue(first.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case id := <-processed:
+		if id != first.ID {
+			t.Errorf("Expected requeued event %s, got %s", first.ID, id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Requeued event was not processed")
+	}
+
+	if n := bus.DeadLetters().Len(); n != 0 {
+		t.Errorf("Expected empty dead-letter queue, got %d", n)
+	}
+}
+
+func TestDeadLetters_RequeueCountAndLimit(t *testing.T) {
+	bus := New(WithAsyncWorkers(1), WithDeadLetterQueueSize(2))
+	defer bus.Close()
+
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		return errors.New("always fails")
+	}))
+
+	event := NewEvent(TestEventTypeAPICreate, nil)
+	bus.PublishAsync(event)
+	waitForDeadLetters(t, bus, 1)
+
+	if err := bus.DeadLetters().Requeue(event.ID); err != nil {
+		t.Fatal(err)
+	}
+	waitForDeadLetters(t, bus, 1)
+
+	letter, ok := bus.DeadLetters().Get(event.ID)
+	if !ok || letter.Requeues != 1 {
+		t.Errorf("Expected requeue count 1, got %+v", letter)
+	}
+
+	for i := 0; i < 3; i++ {
+		bus.PublishAsync(NewEvent(TestEventTypeAPICreate, i))
+	}
+	deadline := time.Now().Add(time.Second)
+	for bus.GetStats().EventsDeadLettered < 5 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for events to be dead-lettered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if n := bus.DeadLetters().Len(); n != 2 {
+		t.Errorf("Expected dead-letter queue capped at 2, got %d", n)
+	}
+	if _, ok := bus.DeadLetters().Get(event.ID); ok {
+		t.Error("Expected oldest dead letter to be dropped")
+	}
+	if n := bus.DeadLetters().PurgeAll(); n != 2 {
+		t.Errorf("Expected to purge 2 dead letters, got %d", n)
+	}
+}
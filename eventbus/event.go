@@ -42,6 +42,13 @@ type Event struct {
 
 	// ParentID links to a parent event.
 	ParentID string `json:"parent_id,omitempty"`
+
+	// Key partitions async events. With ordered delivery enabled, events with
+	// the same key are processed one at a time in publish order.
+	Key string `json:"key,omitempty"`
+
+	// Priority determines how soon a queued async event is processed.
+	Priority Priority `json:"priority,omitempty"`
 }
 
 // NewEvent creates a new event with the given type and data.
@@ -101,6 +108,18 @@ func (e *Event) WithParentID(parentID string) *Event {
 	return e
 }
 
+// WithKey sets the partition key used for ordered delivery.
+func (e *Event) WithKey(key string) *Event {
+	e.Key = key
+	return e
+}
+
+// WithPriority sets the async processing priority.
+func (e *Event) WithPriority(priority Priority) *Event {
+	e.Priority = priority
+	return e
+}
+
 // Clone creates a deep copy of the event.
 func (e *Event) Clone() *Event {
 	clone := &Event{
@@ -112,6 +131,8 @@ func (e *Event) Clone() *Event {
 		Version:       e.Version,
 		CorrelationID: e.CorrelationID,
 		ParentID:      e.ParentID,
+		Key:           e.Key,
+		Priority:      e.Priority,
 	}
 
 	if e.Metadata != nil {
@@ -0,0 +1,200 @@
+// Package eventbus provides consumer groups that share events between handlers.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// groupMember is a handler subscribed to a consumer group.
+type groupMember struct {
+	id      string
+	handler Handler
+}
+
+// groupHandler delivers each event to exactly one member of a consumer group,
+// rotating between members so work is shared evenly. It is registered with
+// the handler registry like any other handler, so the group as a whole still
+// receives every event alongside broadcast subscribers.
+type groupHandler struct {
+	name    string
+	members []*groupMember
+	next    int
+	mu      sync.Mutex
+}
+
+// Handle delivers the event to the next member that can handle it.
+func (g *groupHandler) Handle(ctx context.Context, event *Event) error {
+	member := g.pick(event.Type)
+	if member == nil {
+		return fmt.Errorf("no member of group %s can handle event type %s", g.name, event.Type)
+	}
+
+	handlerCtx, cancel := context.WithTimeout(ctx, member.handler.GetTimeout())
+	defer cancel()
+
+	return member.handler.Handle(handlerCtx, event)
+}
+
+// pick returns the next member in rotation that can handle eventType.
+func (g *groupHandler) pick(eventType EventType) *groupMember {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i := 0; i < len(g.members); i++ {
+		member := g.members[(g.next+i)%len(g.members)]
+		if member.handler.CanHandle(eventType) {
+			g.next = (g.next + i + 1) % len(g.members)
+			return member
+		}
+	}
+	return nil
+}
+
+// CanHandle returns true if any member can handle the event type.
+func (g *groupHandler) CanHandle(eventType EventType) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, member := range g.members {
+		if member.handler.CanHandle(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetName returns the group handler name.
+func (g *groupHandler) GetName() string {
+	return groupHandlerName(g.name)
+}
+
+// GetPriority returns the priority of the group's first member.
+func (g *groupHandler) GetPriority() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.members) == 0 {
+		return 0
+	}
+	return g.members[0].handler.GetPriority()
+}
+
+// GetTimeout returns the longest timeout of the group's members; each member's
+// own timeout is applied when it handles an event.
+func (g *groupHandler) GetTimeout() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var timeout time.Duration
+	for _, member := range g.members {
+		if t := member.handler.GetTimeout(); t > timeout {
+			timeout = t
+		}
+	}
+	return timeout
+}
+
+// add adds a member to the group.
+func (g *groupHandler) add(member *groupMember) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.members = append(g.members, member)
+}
+
+// remove removes a member and returns how many members remain.
+func (g *groupHandler) remove(id string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, member := range g.members {
+		if member.id == id {
+			g.members = append(g.members[:i], g.members[i+1:]...)
+			if g.next > i {
+				g.next--
+			}
+			break
+		}
+	}
+	if len(g.members) > 0 {
+		g.next %= len(g.members)
+	} else {
+		g.next = 0
+	}
+	return len(g.members)
+}
+
+// groupHandlerName returns the registry name of a consumer group's handler.
+func groupHandlerName(group string) string {
+	return "group:" + group
+}
+
+// groupKey identifies a consumer group for an event type.
+type groupKey struct {
+	eventType EventType
+	group     string
+}
+
+// SubscribeGroup subscribes a handler to a named consumer group. Each event
+// is delivered to exactly one member of the group, while every group and
+// every ordinary subscriber still receives its own copy.
+func (eb *eventBus) SubscribeGroup(eventType EventType, group string, handler Handler) (Subscription, error) {
+	if group == "" {
+		return nil, fmt.Errorf("group name is required")
+	}
+
+	subscription := &subscription{
+		id:        fmt.Sprintf("%s-%s-%d", string(eventType), group, time.Now().UnixNano()),
+		eventType: eventType,
+		group:     group,
+		handler:   handler,
+		bus:       eb,
+	}
+
+	key := groupKey{eventType: eventType, group: group}
+
+	eb.groupsMu.Lock()
+	gh, exists := eb.groups[key]
+	if !exists {
+		gh = &groupHandler{name: group}
+		eb.groups[key] = gh
+	}
+	gh.add(&groupMember{id: subscription.id, handler: handler})
+	if !exists {
+		eb.registry.Register(eventType, gh)
+	}
+	eb.groupsMu.Unlock()
+
+	eb.mu.Lock()
+	eb.stats.ActiveSubscriptions++
+	eb.mu.Unlock()
+
+	eb.logger.Info("Subscribed to event type in group",
+		zap.String("type", string(eventType)),
+		zap.String("group", group),
+		zap.String("handler", handler.GetName()))
+
+	return subscription, nil
+}
+
+// unsubscribeGroup removes a member from its consumer group, removing the
+// group once it has no members left.
+func (eb *eventBus) unsubscribeGroup(subscription Subscription, group string) {
+	key := groupKey{eventType: subscription.EventType(), group: group}
+
+	eb.groupsMu.Lock()
+	defer eb.groupsMu.Unlock()
+
+	gh, exists := eb.groups[key]
+	if !exists {
+		return
+	}
+	if gh.remove(subscription.ID()) == 0 {
+		delete(eb.groups, key)
+		eb.registry.Unregister(subscription.EventType(), gh.GetName())
+	}
+}
@@ -0,0 +1,124 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEventBus_SubscribeGroupSharesEvents(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+
+	const events = 30
+
+	var members [3]int64
+	for i := range members {
+		i := i
+		if _, err := bus.SubscribeGroup(TestEventTypeAPICreate, "workers", HandlerFunc(func(ctx context.Context, e *Event) error {
+			atomic.AddInt64(&members[i], 1)
+			return nil
+		})); err != nil {
+			t.Fatalf("Failed to subscribe to group: %v", err)
+		}
+	}
+
+	var broadcast int64
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		atomic.AddInt64(&broadcast, 1)
+		return nil
+	}))
+
+	for i := 0; i < events; i++ {
+		if err := bus.Publish(NewEvent(TestEventTypeAPICreate, i)); err != nil {
+			t.Fatalf("Failed to publish event: %v", err)
+		}
+	}
+
+	total := int64(0)
+	for i, count := range members {
+		if count != events/int64(len(members)) {
+			t.Errorf("Expected member %d to handle %d events, got %d", i, events/len(members), count)
+		}
+		total += count
+	}
+	if total != events {
+		t.Errorf("Expected group to handle %d events, got %d", events, total)
+	}
+	if broadcast != events {
+		t.Errorf("Expected broadcast subscriber to handle %d events, got %d", events, broadcast)
+	}
+}
+
+func TestEventBus_SubscribeGroupSeparateGroups(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+
+	var mu sync.Mutex
+	received := make(map[string]int)
+	for _, group := range []string{"audit", "audit", "sync"} {
+		group := group
+		bus.SubscribeGroup(TestEventTypeAPICreate, group, HandlerFunc(func(ctx context.Context, e *Event) error {
+			mu.Lock()
+			received[group]++
+			mu.Unlock()
+			return nil
+		}))
+	}
+
+	bus.Publish(NewEvent(TestEventTypeAPICreate, nil))
+
+	if received["audit"] != 1 || received["sync"] != 1 {
+		t.Errorf("Expected each group to handle the event once, got %v", received)
+	}
+}
+
+func TestEventBus_UnsubscribeGroupMember(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+
+	var first, second int64
+	sub1, _ := bus.SubscribeGroup(TestEventTypeAPICreate, "workers", HandlerFunc(func(ctx context.Context, e *Event) error {
+		atomic.AddInt64(&first, 1)
+		return nil
+	}))
+	sub2, _ := bus.SubscribeGroup(TestEventTypeAPICreate, "workers", HandlerFunc(func(ctx context.Context, e *Event) error {
+		atomic.AddInt64(&second, 1)
+		return nil
+	}))
+
+	if sub1.Group() != "workers" {
+		t.Errorf("Expected group workers, got %s", sub1.Group())
+	}
+
+	if err := sub1.Unsubscribe(); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		bus.Publish(NewEvent(TestEventTypeAPICreate, i))
+	}
+	if first != 0 || second != 4 {
+		t.Errorf("Expected remaining member to handle all 4 events, got %d and %d", first, second)
+	}
+
+	sub2.Unsubscribe()
+	bus.Publish(NewEvent(TestEventTypeAPICreate, nil))
+	if second != 4 {
+		t.Errorf("Expected no delivery after the group emptied, got %d", second)
+	}
+	if stats := bus.GetStats(); stats.ActiveSubscriptions != 0 {
+		t.Errorf("Expected 0 active subscriptions, got %d", stats.ActiveSubscriptions)
+	}
+}
+
+func TestEventBus_SubscribeGroupRequiresName(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+
+	if _, err := bus.SubscribeGroup(TestEventTypeAPICreate, "", HandlerFunc(func(ctx context.Context, e *Event) error {
+		return nil
+	})); err == nil {
+		t.Error("Expected error for empty group name")
+	}
+}
@@ -4,10 +4,12 @@ package eventbus
 import (
 	"context"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Middleware defines the interface for event processing middleware.
@@ -43,23 +45,46 @@ func ChainMiddleware(middleware ...Middleware) Middleware {
 
 // LoggingMiddleware logs all events and their processing.
 type LoggingMiddleware struct {
-	logger *zap.Logger
+	logger          *zap.Logger
+	level           zapcore.Level
+	includeData     bool
+	includeMetadata bool
 }
 
 // NewLoggingMiddleware creates a new logging middleware.
 func NewLoggingMiddleware(logger *zap.Logger) *LoggingMiddleware {
-	return &LoggingMiddleware{logger: logger}
+	return &LoggingMiddleware{logger: logger, level: zap.InfoLevel}
+}
+
+// newLoggingMiddlewareFromConfig creates a logging middleware from configuration.
+func newLoggingMiddlewareFromConfig(logger *zap.Logger, config LoggingConfig) *LoggingMiddleware {
+	return &LoggingMiddleware{
+		logger:          logger,
+		level:           config.Level,
+		includeData:     config.IncludeData,
+		includeMetadata: config.IncludeMetadata,
+	}
 }
 
 // Process logs the event processing.
 func (m *LoggingMiddleware) Process(ctx context.Context, event *Event, next func(context.Context, *Event) error) error {
 	start := time.Now()
-	
-	m.logger.Info("Processing event",
-		zap.String("id", event.ID),
-		zap.String("type", string(event.Type)),
-		zap.String("source", event.Source),
-		zap.Time("timestamp", event.Timestamp))
+
+	if entry := m.logger.Check(m.level, "Processing event"); entry != nil {
+		fields := []zap.Field{
+			zap.String("id", event.ID),
+			zap.String("type", string(event.Type)),
+			zap.String("source", event.Source),
+			zap.Time("timestamp", event.Timestamp),
+		}
+		if m.includeData {
+			fields = append(fields, zap.Any("data", event.Data))
+		}
+		if m.includeMetadata && len(event.Metadata) > 0 {
+			fields = append(fields, zap.Any("metadata", event.Metadata))
+		}
+		entry.Write(fields...)
+	}
 
 	err := next(ctx, event)
 	duration := time.Since(start)
@@ -82,14 +107,30 @@ func (m *LoggingMiddleware) Process(ctx context.Context, event *Event, next func
 
 // MetricsMiddleware collects metrics for events.
 type MetricsMiddleware struct {
-	metrics map[string]interface{}
-	mu      sync.RWMutex
+	metrics          map[string]interface{}
+	collectCounts    bool
+	collectDurations bool
+	collectErrors    bool
+	mu               sync.RWMutex
 }
 
 // NewMetricsMiddleware creates a new metrics middleware.
 func NewMetricsMiddleware() *MetricsMiddleware {
 	return &MetricsMiddleware{
-		metrics: make(map[string]interface{}),
+		metrics:          make(map[string]interface{}),
+		collectCounts:    true,
+		collectDurations: true,
+		collectErrors:    true,
+	}
+}
+
+// newMetricsMiddlewareFromConfig creates a metrics middleware from configuration.
+func newMetricsMiddlewareFromConfig(config MetricsConfig) *MetricsMiddleware {
+	return &MetricsMiddleware{
+		metrics:          make(map[string]interface{}),
+		collectCounts:    config.CollectCounts,
+		collectDurations: config.CollectDurations,
+		collectErrors:    config.CollectErrors,
 	}
 }
 
@@ -103,25 +144,29 @@ func (m *MetricsMiddleware) Process(ctx context.Context, event *Event, next func
 	defer m.mu.Unlock()
 
 	// Update event type counters
-	eventTypeKey := fmt.Sprintf("events.%s.count", event.Type)
-	if count, exists := m.metrics[eventTypeKey]; exists {
-		m.metrics[eventTypeKey] = count.(int64) + 1
-	} else {
-		m.metrics[eventTypeKey] = int64(1)
+	if m.collectCounts {
+		eventTypeKey := fmt.Sprintf("events.%s.count", event.Type)
+		if count, exists := m.metrics[eventTypeKey]; exists {
+			m.metrics[eventTypeKey] = count.(int64) + 1
+		} else {
+			m.metrics[eventTypeKey] = int64(1)
+		}
 	}
 
 	// Update duration metrics
-	durationKey := fmt.Sprintf("events.%s.duration", event.Type)
-	if durations, exists := m.metrics[durationKey]; exists {
-		if durSlice, ok := durations.([]time.Duration); ok {
-			m.metrics[durationKey] = append(durSlice, duration)
+	if m.collectDurations {
+		durationKey := fmt.Sprintf("events.%s.duration", event.Type)
+		if durations, exists := m.metrics[durationKey]; exists {
+			if durSlice, ok := durations.([]time.Duration); ok {
+				m.metrics[durationKey] = append(durSlice, duration)
+			}
+		} else {
+			m.metrics[durationKey] = []time.Duration{duration}
 		}
-	} else {
-		m.metrics[durationKey] = []time.Duration{duration}
 	}
 
 	// Update error counters
-	if err != nil {
+	if err != nil && m.collectErrors {
 		errorKey := fmt.Sprintf("events.%s.errors", event.Type)
 		if count, exists := m.metrics[errorKey]; exists {
 			m.metrics[errorKey] = count.(int64) + 1
@@ -145,17 +190,43 @@ func (m *MetricsMiddleware) GetMetrics() map[string]interface{} {
 	return result
 }
 
+// Flush returns the collected metrics and resets them.
+func (m *MetricsMiddleware) Flush() map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := m.metrics
+	m.metrics = make(map[string]interface{})
+	return result
+}
+
 // ValidationMiddleware validates events before processing.
 type ValidationMiddleware struct {
-	validators map[EventType]func(*Event) error
-	mu         sync.RWMutex
+	validators     map[EventType]func(*Event) error
+	requiredFields map[EventType][]string
+	strict         bool
+	mu             sync.RWMutex
 }
 
 // NewValidationMiddleware creates a new validation middleware.
 func NewValidationMiddleware() *ValidationMiddleware {
 	return &ValidationMiddleware{
-		validators: make(map[EventType]func(*Event) error),
+		validators:     make(map[EventType]func(*Event) error),
+		requiredFields: make(map[EventType][]string),
+	}
+}
+
+// newValidationMiddlewareFromConfig creates a validation middleware from configuration.
+func newValidationMiddlewareFromConfig(config ValidationConfig) *ValidationMiddleware {
+	m := NewValidationMiddleware()
+	m.strict = config.StrictMode
+	for eventType, validator := range config.CustomValidators {
+		m.AddValidator(eventType, validator)
+	}
+	for eventType, fields := range config.RequiredFields {
+		m.RequireFields(eventType, fields...)
 	}
+	return m
 }
 
 // AddValidator adds a validator for an event type.
@@ -165,12 +236,45 @@ func (m *ValidationMiddleware) AddValidator(eventType EventType, validator func(
 	m.validators[eventType] = validator
 }
 
+// RequireFields requires events of a type to carry the given top-level
+// fields in their data. Events whose data is not a map are only rejected in
+// strict mode.
+func (m *ValidationMiddleware) RequireFields(eventType EventType, fields ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requiredFields[eventType] = append(m.requiredFields[eventType], fields...)
+}
+
+// SetStrictMode sets whether events whose data cannot be checked for
+// required fields are rejected.
+func (m *ValidationMiddleware) SetStrictMode(strict bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.strict = strict
+}
+
 // Process validates the event before processing.
 func (m *ValidationMiddleware) Process(ctx context.Context, event *Event, next func(context.Context, *Event) error) error {
 	m.mu.RLock()
 	validator, exists := m.validators[event.Type]
+	required := m.requiredFields[event.Type]
+	strict := m.strict
 	m.mu.RUnlock()
 
+	if len(required) > 0 {
+		data, ok := event.Data.(map[string]interface{})
+		if !ok && strict {
+			return fmt.Errorf("event validation failed: %s event data is %T, not an object", event.Type, event.Data)
+		}
+		if ok {
+			for _, field := range required {
+				if _, present := data[field]; !present {
+					return fmt.Errorf("event validation failed: missing required field %q", field)
+				}
+			}
+		}
+	}
+
 	if exists && validator != nil {
 		if err := validator(event); err != nil {
 			return fmt.Errorf("event validation failed: %w", err)
@@ -182,14 +286,18 @@ func (m *ValidationMiddleware) Process(ctx context.Context, event *Event, next f
 
 // RateLimitMiddleware limits the rate of event processing.
 type RateLimitMiddleware struct {
-	limiters map[EventType]*RateLimiter
-	mu       sync.RWMutex
+	limiters    map[EventType]*RateLimiter
+	limits      map[EventType]RateLimit
+	defaultRate int
+	defaultPer  time.Duration
+	mu          sync.RWMutex
 }
 
 // NewRateLimitMiddleware creates a new rate limit middleware.
 func NewRateLimitMiddleware() *RateLimitMiddleware {
 	return &RateLimitMiddleware{
 		limiters: make(map[EventType]*RateLimiter),
+		limits:   make(map[EventType]RateLimit),
 	}
 }
 
@@ -198,6 +306,26 @@ func (m *RateLimitMiddleware) SetRateLimit(eventType EventType, rate int, per ti
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.limiters[eventType] = NewRateLimiter(rate, per)
+	m.limits[eventType] = RateLimit{Rate: rate, Per: per}
+}
+
+// SetDefaultRateLimit sets the rate limit for event types without their own
+// limit. Each event type is limited separately.
+func (m *RateLimitMiddleware) SetDefaultRateLimit(rate int, per time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultRate = rate
+	m.defaultPer = per
+}
+
+// newRateLimitMiddlewareFromConfig creates a rate limit middleware from configuration.
+func newRateLimitMiddlewareFromConfig(config RateLimitConfig) *RateLimitMiddleware {
+	m := NewRateLimitMiddleware()
+	m.SetDefaultRateLimit(config.DefaultRate, config.DefaultPer)
+	for eventType, limit := range config.PerEventType {
+		m.SetRateLimit(eventType, limit.Rate, limit.Per)
+	}
+	return m
 }
 
 // Process applies rate limiting to the event.
@@ -206,6 +334,17 @@ func (m *RateLimitMiddleware) Process(ctx context.Context, event *Event, next fu
 	limiter, exists := m.limiters[event.Type]
 	m.mu.RUnlock()
 
+	if !exists {
+		m.mu.Lock()
+		limiter, exists = m.limiters[event.Type]
+		if !exists && m.defaultRate > 0 && m.defaultPer > 0 {
+			limiter = NewRateLimiter(m.defaultRate, m.defaultPer)
+			m.limiters[event.Type] = limiter
+			exists = true
+		}
+		m.mu.Unlock()
+	}
+
 	if exists && limiter != nil {
 		if !limiter.Allow() {
 			return fmt.Errorf("rate limit exceeded for event type %s", event.Type)
@@ -217,14 +356,18 @@ func (m *RateLimitMiddleware) Process(ctx context.Context, event *Event, next fu
 
 // CircuitBreakerMiddleware implements circuit breaker pattern for event processing.
 type CircuitBreakerMiddleware struct {
-	breakers map[EventType]*CircuitBreaker
-	mu       sync.RWMutex
+	breakers         map[EventType]*CircuitBreaker
+	settings         map[EventType]CircuitBreakerSettings
+	defaultThreshold int
+	defaultTimeout   time.Duration
+	mu               sync.RWMutex
 }
 
 // NewCircuitBreakerMiddleware creates a new circuit breaker middleware.
 func NewCircuitBreakerMiddleware() *CircuitBreakerMiddleware {
 	return &CircuitBreakerMiddleware{
 		breakers: make(map[EventType]*CircuitBreaker),
+		settings: make(map[EventType]CircuitBreakerSettings),
 	}
 }
 
@@ -233,6 +376,26 @@ func (m *CircuitBreakerMiddleware) SetCircuitBreaker(eventType EventType, failur
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.breakers[eventType] = NewCircuitBreaker(failureThreshold, timeout)
+	m.settings[eventType] = CircuitBreakerSettings{FailureThreshold: failureThreshold, Timeout: timeout}
+}
+
+// SetDefaultCircuitBreaker sets the circuit breaker settings for event types
+// without their own. Each event type has a separate breaker.
+func (m *CircuitBreakerMiddleware) SetDefaultCircuitBreaker(failureThreshold int, timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultThreshold = failureThreshold
+	m.defaultTimeout = timeout
+}
+
+// newCircuitBreakerMiddlewareFromConfig creates a circuit breaker middleware from configuration.
+func newCircuitBreakerMiddlewareFromConfig(config CircuitBreakerConfig) *CircuitBreakerMiddleware {
+	m := NewCircuitBreakerMiddleware()
+	m.SetDefaultCircuitBreaker(config.DefaultFailureThreshold, config.DefaultTimeout)
+	for eventType, settings := range config.PerEventType {
+		m.SetCircuitBreaker(eventType, settings.FailureThreshold, settings.Timeout)
+	}
+	return m
 }
 
 // Process applies circuit breaker logic to the event.
@@ -241,6 +404,17 @@ func (m *CircuitBreakerMiddleware) Process(ctx context.Context, event *Event, ne
 	breaker, exists := m.breakers[event.Type]
 	m.mu.RUnlock()
 
+	if !exists {
+		m.mu.Lock()
+		breaker, exists = m.breakers[event.Type]
+		if !exists && m.defaultThreshold > 0 {
+			breaker = NewCircuitBreaker(m.defaultThreshold, m.defaultTimeout)
+			m.breakers[event.Type] = breaker
+			exists = true
+		}
+		m.mu.Unlock()
+	}
+
 	if exists && breaker != nil {
 		if !breaker.Allow() {
 			return fmt.Errorf("circuit breaker open for event type %s", event.Type)
@@ -256,8 +430,8 @@ func (m *CircuitBreakerMiddleware) Process(ctx context.Context, event *Event, ne
 
 // RetryMiddleware retries failed event processing.
 type RetryMiddleware struct {
-	maxRetries int
-	retryDelay time.Duration
+	maxRetries  int
+	retryDelay  time.Duration
 	backoffFunc func(int) time.Duration
 }
 
@@ -272,6 +446,28 @@ func NewRetryMiddleware(maxRetries int, retryDelay time.Duration) *RetryMiddlewa
 	}
 }
 
+// SetBackoff makes the delay grow by multiplier after each attempt, capped
+// at maxDelay when it is positive.
+func (m *RetryMiddleware) SetBackoff(multiplier float64, maxDelay time.Duration) {
+	retryDelay := m.retryDelay
+	m.backoffFunc = func(attempt int) time.Duration {
+		delay := time.Duration(float64(retryDelay) * math.Pow(multiplier, float64(attempt-1)))
+		if maxDelay > 0 && (delay > maxDelay || delay < 0) {
+			delay = maxDelay
+		}
+		return delay
+	}
+}
+
+// newRetryMiddlewareFromConfig creates a retry middleware from configuration.
+func newRetryMiddlewareFromConfig(config RetryConfig) *RetryMiddleware {
+	m := NewRetryMiddleware(config.MaxRetries, config.RetryDelay)
+	if config.BackoffMultiplier > 0 {
+		m.SetBackoff(config.BackoffMultiplier, config.MaxRetryDelay)
+	}
+	return m
+}
+
 // Process retries failed event processing.
 func (m *RetryMiddleware) Process(ctx context.Context, event *Event, next func(context.Context, *Event) error) error {
 	var lastErr error
@@ -299,17 +495,48 @@ func (m *RetryMiddleware) Process(ctx context.Context, event *Event, next func(c
 
 // TimeoutMiddleware adds timeout to event processing.
 type TimeoutMiddleware struct {
-	timeout time.Duration
+	timeout      time.Duration
+	perEventType map[EventType]time.Duration
+	mu           sync.RWMutex
 }
 
 // NewTimeoutMiddleware creates a new timeout middleware.
 func NewTimeoutMiddleware(timeout time.Duration) *TimeoutMiddleware {
-	return &TimeoutMiddleware{timeout: timeout}
+	return &TimeoutMiddleware{
+		timeout:      timeout,
+		perEventType: make(map[EventType]time.Duration),
+	}
+}
+
+// SetTimeout sets the timeout for an event type.
+func (m *TimeoutMiddleware) SetTimeout(eventType EventType, timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.perEventType[eventType] = timeout
+}
+
+// newTimeoutMiddlewareFromConfig creates a timeout middleware from configuration.
+func newTimeoutMiddlewareFromConfig(config TimeoutConfig) *TimeoutMiddleware {
+	m := NewTimeoutMiddleware(config.DefaultTimeout)
+	for eventType, timeout := range config.PerEventType {
+		m.SetTimeout(eventType, timeout)
+	}
+	return m
 }
 
 // Process adds timeout to event processing.
 func (m *TimeoutMiddleware) Process(ctx context.Context, event *Event, next func(context.Context, *Event) error) error {
-	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	m.mu.RLock()
+	timeout, exists := m.perEventType[event.Type]
+	m.mu.RUnlock()
+	if !exists {
+		timeout = m.timeout
+	}
+	if timeout <= 0 {
+		return next(ctx, event)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	done := make(chan error, 1)
@@ -321,7 +548,7 @@ func (m *TimeoutMiddleware) Process(ctx context.Context, event *Event, next func
 	case err := <-done:
 		return err
 	case <-ctx.Done():
-		return fmt.Errorf("event processing timeout after %v: %w", m.timeout, ctx.Err())
+		return fmt.Errorf("event processing timeout after %v: %w", timeout, ctx.Err())
 	}
 }
 
@@ -363,35 +590,65 @@ func (m *SanitizationMiddleware) AddSanitizer(field string, sanitizer func(inter
 	m.sanitizers[field] = sanitizer
 }
 
-// Process sanitizes event data.
+// newSanitizationMiddlewareFromConfig creates a sanitization middleware from
+// configuration. Custom sanitizers take precedence over SanitizationFunction
+// for the same field.
+func newSanitizationMiddlewareFromConfig(config SanitizationConfig) *SanitizationMiddleware {
+	m := NewSanitizationMiddleware()
+	if config.SanitizationFunction != nil {
+		sanitize := config.SanitizationFunction
+		for _, field := range config.SensitiveFields {
+			m.AddSanitizer(field, func(v interface{}) interface{} {
+				if s, ok := v.(string); ok {
+					return sanitize(s)
+				}
+				return sanitize(fmt.Sprint(v))
+			})
+		}
+	}
+	for field, sanitizer := range config.CustomSanitizers {
+		m.AddSanitizer(field, sanitizer)
+	}
+	return m
+}
+
+// Process passes on a copy of the event with its metadata sanitized. The
+// publisher's event and metadata map are left untouched.
 func (m *SanitizationMiddleware) Process(ctx context.Context, event *Event, next func(context.Context, *Event) error) error {
 	m.mu.RLock()
-	sanitizers := make(map[string]func(interface{}) interface{})
-	for k, v := range m.sanitizers {
-		sanitizers[k] = v
+	var metadata map[string]interface{}
+	for key, value := range event.Metadata {
+		sanitizer, exists := m.sanitizers[key]
+		if !exists {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]interface{}, len(event.Metadata))
+			for k, v := range event.Metadata {
+				metadata[k] = v
+			}
+		}
+		metadata[key] = sanitizer(value)
 	}
 	m.mu.RUnlock()
 
-	// Sanitize metadata
-	if event.Metadata != nil {
-		for key, value := range event.Metadata {
-			if sanitizer, exists := sanitizers[key]; exists {
-				event.Metadata[key] = sanitizer(value)
-			}
-		}
+	if metadata == nil {
+		return next(ctx, event)
 	}
 
-	return next(ctx, event)
+	sanitized := *event
+	sanitized.Metadata = metadata
+	return next(ctx, &sanitized)
 }
 
 // DefaultSanitizers returns common sanitizers for sensitive data.
 func DefaultSanitizers() map[string]func(interface{}) interface{} {
 	return map[string]func(interface{}) interface{}{
-		"password":     func(v interface{}) interface{} { return "[REDACTED]" },
-		"token":        func(v interface{}) interface{} { return "[REDACTED]" },
-		"secret":       func(v interface{}) interface{} { return "[REDACTED]" },
-		"api_key":      func(v interface{}) interface{} { return "[REDACTED]" },
-		"access_token": func(v interface{}) interface{} { return "[REDACTED]" },
+		"password":      func(v interface{}) interface{} { return "[REDACTED]" },
+		"token":         func(v interface{}) interface{} { return "[REDACTED]" },
+		"secret":        func(v interface{}) interface{} { return "[REDACTED]" },
+		"api_key":       func(v interface{}) interface{} { return "[REDACTED]" },
+		"access_token":  func(v interface{}) interface{} { return "[REDACTED]" },
 		"refresh_token": func(v interface{}) interface{} { return "[REDACTED]" },
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,87 @@
+// Package eventbus provides ordered, per-key delivery of async events.
+package eventbus
+
+import (
+	"hash/fnv"
+
+	"go.uber.org/zap"
+)
+
+// startOrderedWorkers starts one worker per partition plus a dispatcher that
+// routes events from the priority queues. Events with the same key always go to
+// the same worker, so they are processed sequentially in publish order while
+// different keys are processed in parallel. Events without a key are shared
+// between all workers.
+func (eb *eventBus) startOrderedWorkers(queueSize int) {
+	if eb.asyncWorkers < 1 {
+		eb.asyncWorkers = 1
+	}
+
+	bufferSize := queueSize / eb.asyncWorkers
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	eb.partitions = make([]chan *Event, eb.asyncWorkers)
+	for i := range eb.partitions {
+		eb.partitions[i] = make(chan *Event, bufferSize)
+	}
+	eb.unkeyed = make(chan *Event, bufferSize)
+
+	for i := 0; i < eb.asyncWorkers; i++ {
+		eb.wg.Add(1)
+		go eb.partitionWorker(i)
+	}
+
+	eb.wg.Add(1)
+	go eb.partitionDispatcher()
+}
+
+// partitionDispatcher moves events from the async queue to their partition.
+func (eb *eventBus) partitionDispatcher() {
+	defer eb.wg.Done()
+
+	for {
+		event, ok := eb.dequeue()
+		if !ok {
+			return
+		}
+
+		target := eb.unkeyed
+		if event.Key != "" {
+			target = eb.partitions[eb.partitionFor(event.Key)]
+		}
+
+		select {
+		case target <- event:
+		case <-eb.stopChan:
+			return
+		}
+	}
+}
+
+// partitionWorker processes events for a single partition and unkeyed events.
+func (eb *eventBus) partitionWorker(workerID int) {
+	defer eb.wg.Done()
+
+	eb.logger.Debug("Started ordered async worker", zap.Int("worker_id", workerID))
+
+	for {
+		select {
+		case event := <-eb.partitions[workerID]:
+			eb.processAsyncEvent(workerID, event)
+		case event := <-eb.unkeyed:
+			eb.processAsyncEvent(workerID, event)
+		case <-eb.stopChan:
+			eb.logger.Debug("Stopping ordered async worker", zap.Int("worker_id", workerID))
+			return
+		}
+	}
+}
+
+// partitionFor returns the partition index for a key.
+func (eb *eventBus) partitionFor(key string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % uint32(len(eb.partitions)))
+}
@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventBus_OrderedDeliveryPerKey(t *testing.T) {
+	bus := New(WithAsyncWorkers(4), WithOrderedDelivery(true))
+	defer bus.Close()
+
+	const keys = 3
+	const perKey = 50
+
+	var mu sync.Mutex
+	received := make(map[string][]int)
+	active := make(map[string]bool)
+	var wg sync.WaitGroup
+	wg.Add(keys * perKey)
+
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		defer wg.Done()
+
+		mu.Lock()
+		if active[e.Key] {
+			t.Errorf("Events for key %s processed concurrently", e.Key)
+		}
+		active[e.Key] = true
+		mu.Unlock()
+
+		time.Sleep(100 * time.Microsecond)
+
+		mu.Lock()
+		active[e.Key] = false
+		received[e.Key] = append(received[e.Key], e.Data.(int))
+		mu.Unlock()
+		return nil
+	}))
+
+	for i := 0; i < perKey; i++ {
+		for k := 0; k < keys; k++ {
+			event := NewEvent(TestEventTypeAPICreate, i).WithKey(fmt.Sprintf("ext-%d", k))
+			if err := bus.PublishAsync(event); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for ordered events")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for key, values := range received {
+		for i, value := range values {
+			if value != i {
+				t.Fatalf("Expected events for key %s in publish order, got %v", key, values)
+			}
+		}
+	}
+}
+
+func TestEventBus_OrderedDeliveryUnkeyed(t *testing.T) {
+	bus := New(WithAsyncWorkers(2), WithOrderedDelivery(true))
+	defer bus.Close()
+
+	received := make(chan *Event, 10)
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		received <- e
+		return nil
+	}))
+
+	for i := 0; i < 10; i++ {
+		bus.PublishAsync(NewEvent(TestEventTypeAPICreate, i))
+	}
+
+	for i := 0; i < 10; i++ {
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected 10 unkeyed events, got %d", i)
+		}
+	}
+}
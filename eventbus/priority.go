@@ -0,0 +1,197 @@
+// Package eventbus provides priority queues for async events.
+package eventbus
+
+import (
+	"fmt"
+)
+
+// Priority determines the order in which queued async events are processed.
+type Priority int
+
+const (
+	// PriorityLow is for bulk events that can wait, such as telemetry.
+	PriorityLow Priority = -1
+
+	// PriorityNormal is the default priority.
+	PriorityNormal Priority = 0
+
+	// PriorityHigh is for events that should be processed ahead of normal traffic.
+	PriorityHigh Priority = 1
+
+	// PriorityCritical is for events that must be handled as soon as possible,
+	// such as auth expiry or detected corruption.
+	PriorityCritical Priority = 2
+)
+
+// priorityLevels is the number of distinct priorities.
+const priorityLevels = int(PriorityCritical-PriorityLow) + 1
+
+// defaultStarvationLimit is how many higher priority events may be processed
+// in a row while lower priority events are waiting.
+const defaultStarvationLimit = 10
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	switch p.normalize() {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// ParsePriority parses a priority name.
+func ParsePriority(s string) (Priority, error) {
+	switch s {
+	case "low":
+		return PriorityLow, nil
+	case "", "normal":
+		return PriorityNormal, nil
+	case "high":
+		return PriorityHigh, nil
+	case "critical":
+		return PriorityCritical, nil
+	default:
+		return PriorityNormal, fmt.Errorf("invalid priority: %s", s)
+	}
+}
+
+// normalize clamps a priority to the supported range.
+func (p Priority) normalize() Priority {
+	if p < PriorityLow {
+		return PriorityLow
+	}
+	if p > PriorityCritical {
+		return PriorityCritical
+	}
+	return p
+}
+
+// index returns the queue index for a priority, with the lowest priority at 0.
+func (p Priority) index() int {
+	return int(p.normalize() - PriorityLow)
+}
+
+// newPriorityQueues creates one async queue per priority.
+func newPriorityQueues(size int) [priorityLevels]chan *Event {
+	var queues [priorityLevels]chan *Event
+	for i := range queues {
+		queues[i] = make(chan *Event, size)
+	}
+	return queues
+}
+
+// tryEnqueue adds an event to its priority queue without blocking.
+func (eb *eventBus) tryEnqueue(event *Event) bool {
+	select {
+	case eb.queues[event.Priority.index()] <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// enqueue adds an event to its priority queue, blocking until there is room
+// or the bus is closed.
+func (eb *eventBus) enqueue(event *Event) bool {
+	select {
+	case eb.queues[event.Priority.index()] <- event:
+		return true
+	case <-eb.stopChan:
+		return false
+	}
+}
+
+// dequeue returns the next event to process, blocking until one is available
+// or the bus is closed. Higher priorities are served first, but after
+// starvationLimit consecutive events have jumped ahead of waiting lower
+// priority events, the oldest lower priority event is served instead.
+func (eb *eventBus) dequeue() (*Event, bool) {
+	for {
+		if event := eb.pollQueues(); event != nil {
+			return event, true
+		}
+
+		select {
+		case event := <-eb.queues[3]:
+			eb.markServed(event)
+			return event, true
+		case event := <-eb.queues[2]:
+			eb.markServed(event)
+			return event, true
+		case event := <-eb.queues[1]:
+			eb.markServed(event)
+			return event, true
+		case event := <-eb.queues[0]:
+			eb.markServed(event)
+			return event, true
+		case <-eb.stopChan:
+			return nil, false
+		}
+	}
+}
+
+// pollQueues takes an event from the queues without blocking, honoring
+// priority and starvation protection.
+func (eb *eventBus) pollQueues() *Event {
+	eb.priorityMu.Lock()
+	starving := eb.starvationLimit > 0 && eb.skipped >= eb.starvationLimit
+	eb.priorityMu.Unlock()
+
+	if starving {
+		for i := 0; i < priorityLevels; i++ {
+			select {
+			case event := <-eb.queues[i]:
+				eb.priorityMu.Lock()
+				eb.skipped = 0
+				eb.priorityMu.Unlock()
+				return event
+			default:
+			}
+		}
+	}
+
+	for i := priorityLevels - 1; i >= 0; i-- {
+		select {
+		case event := <-eb.queues[i]:
+			eb.markServed(event)
+			return event
+		default:
+		}
+	}
+	return nil
+}
+
+// markServed updates starvation tracking after an event is taken.
+func (eb *eventBus) markServed(event *Event) {
+	index := event.Priority.index()
+
+	waiting := false
+	for i := 0; i < index; i++ {
+		if len(eb.queues[i]) > 0 {
+			waiting = true
+			break
+		}
+	}
+
+	eb.priorityMu.Lock()
+	if waiting {
+		eb.skipped++
+	} else {
+		eb.skipped = 0
+	}
+	eb.priorityMu.Unlock()
+}
+
+// queueDepths returns the number of queued events per priority.
+func (eb *eventBus) queueDepths() map[string]int {
+	depths := make(map[string]int, priorityLevels)
+	for i, queue := range eb.queues {
+		depths[(Priority(i) + PriorityLow).String()] = len(queue)
+	}
+	return depths
+}
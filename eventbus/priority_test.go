@@ -0,0 +1,102 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// publishBlocked queues events while the only worker is busy, then releases it.
+func publishBlocked(t *testing.T, bus EventBus, events []*Event) []*Event {
+	t.Helper()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	processed := make(chan *Event, len(events)+1)
+
+	blocker := NewEvent(TestEventTypeAPICreate, "blocker")
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		if e.ID == blocker.ID {
+			close(started)
+			<-release
+			return nil
+		}
+		processed <- e
+		return nil
+	}))
+
+	bus.PublishAsync(blocker)
+	<-started
+
+	for _, event := range events {
+		if err := bus.PublishAsync(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(release)
+
+	var order []*Event
+	for range events {
+		select {
+		case e := <-processed:
+			order = append(order, e)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out after %d events", len(order))
+		}
+	}
+	return order
+}
+
+func TestEventBus_PriorityOrder(t *testing.T) {
+	bus := New(WithAsyncWorkers(1))
+	defer bus.Close()
+
+	low := NewEvent(TestEventTypeAPICreate, "low").WithPriority(PriorityLow)
+	normal := NewEvent(TestEventTypeAPICreate, "normal")
+	critical := NewEvent(TestEventTypeAPICreate, "critical").WithPriority(PriorityCritical)
+
+	order := publishBlocked(t, bus, []*Event{low, normal, critical})
+
+	expected := []string{"critical", "normal", "low"}
+	for i, e := range order {
+		if e.Data != expected[i] {
+			t.Fatalf("Expected order %v, got event %v at position %d", expected, e.Data, i)
+		}
+	}
+}
+
+func TestEventBus_PriorityStarvationProtection(t *testing.T) {
+	bus := New(WithAsyncWorkers(1), WithStarvationLimit(3))
+	defer bus.Close()
+
+	events := []*Event{NewEvent(TestEventTypeAPICreate, "low").WithPriority(PriorityLow)}
+	for i := 0; i < 10; i++ {
+		events = append(events, NewEvent(TestEventTypeAPICreate, i).WithPriority(PriorityHigh))
+	}
+
+	order := publishBlocked(t, bus, events)
+
+	for i, e := range order {
+		if e.Data == "low" {
+			if i > 3 {
+				t.Errorf("Expected low priority event within the first 4, got position %d", i)
+			}
+			return
+		}
+	}
+	t.Fatal("Low priority event was not processed")
+}
+
+func TestEventBus_QueueDepthStats(t *testing.T) {
+	bus := New(WithAsyncWorkers(0))
+	defer bus.Close()
+
+	bus.PublishAsync(NewEvent(TestEventTypeAPICreate, nil).WithPriority(PriorityCritical))
+	bus.PublishAsync(NewEvent(TestEventTypeAPICreate, nil).WithPriority(PriorityLow))
+	bus.PublishAsync(NewEvent(TestEventTypeAPICreate, nil).WithPriority(PriorityLow))
+
+	depth := bus.GetStats().QueueDepth
+	if depth["critical"] != 1 || depth["low"] != 2 || depth["normal"] != 0 {
+		t.Errorf("Unexpected queue depth %v", depth)
+	}
+}
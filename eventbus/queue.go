@@ -0,0 +1,262 @@
+// Package eventbus provides durable queue implementations for async events.
+package eventbus
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DurableQueue persists async events until their processing is acknowledged.
+// Events that were appended but never acknowledged are redelivered when the
+// event bus starts, giving at-least-once delivery across crashes.
+type DurableQueue interface {
+	// Append persists an event before it is queued for processing.
+	Append(event *Event) error
+
+	// Ack marks an event as processed so it is not redelivered.
+	Ack(eventID string) error
+
+	// Pending returns all events that have not been acknowledged, in append order.
+	Pending() ([]*Event, error)
+
+	// Close releases the resources held by the queue.
+	Close() error
+}
+
+// queueRecord is a single line in the file queue log.
+type queueRecord struct {
+	Op    string `json:"op"`
+	ID    string `json:"id,omitempty"`
+	Event *Event `json:"event,omitempty"`
+}
+
+const (
+	queueOpAppend = "append"
+	queueOpAck    = "ack"
+
+	// defaultCompactThreshold is the number of acknowledged records after
+	// which the log is rewritten to contain only pending events.
+	defaultCompactThreshold = 1000
+)
+
+// FileQueue is a DurableQueue backed by an append-only JSON lines file.
+// Event data is stored as JSON, so after a restart Event.Data holds the
+// decoded JSON value rather than the original Go type.
+type FileQueue struct {
+	path             string
+	file             *os.File
+	pending          map[string]*Event
+	order            []string
+	acked            int
+	compactThreshold int
+	mu               sync.Mutex
+}
+
+// NewFileQueue opens or creates a file-backed durable queue at path.
+func NewFileQueue(path string) (*FileQueue, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	q := &FileQueue{
+		path:             path,
+		pending:          make(map[string]*Event),
+		compactThreshold: defaultCompactThreshold,
+	}
+
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open queue file: %w", err)
+	}
+	q.file = file
+
+	return q, nil
+}
+
+// Append persists an event before it is queued for processing.
+func (q *FileQueue) Append(event *Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.write(queueRecord{Op: queueOpAppend, Event: event}); err != nil {
+		return err
+	}
+
+	if _, exists := q.pending[event.ID]; !exists {
+		q.order = append(q.order, event.ID)
+	}
+	q.pending[event.ID] = event
+	return nil
+}
+
+// Ack marks an event as processed so it is not redelivered.
+func (q *FileQueue) Ack(eventID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, exists := q.pending[eventID]; !exists {
+		return nil
+	}
+
+	if err := q.write(queueRecord{Op: queueOpAck, ID: eventID}); err != nil {
+		return err
+	}
+
+	delete(q.pending, eventID)
+	q.acked++
+
+	if q.acked >= q.compactThreshold && q.acked > len(q.pending) {
+		return q.compact()
+	}
+	return nil
+}
+
+// Pending returns all events that have not been acknowledged, in append order.
+func (q *FileQueue) Pending() ([]*Event, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	events := make([]*Event, 0, len(q.pending))
+	for _, id := range q.order {
+		if event, exists := q.pending[id]; exists {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// Close releases the resources held by the queue.
+func (q *FileQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.file == nil {
+		return nil
+	}
+	err := q.file.Close()
+	q.file = nil
+	return err
+}
+
+// load rebuilds the pending set from the log file.
+func (q *FileQueue) load() error {
+	file, err := os.Open(q.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open queue file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record queueRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// A torn write from a crash leaves a partial last line; skip it
+			continue
+		}
+
+		switch record.Op {
+		case queueOpAppend:
+			if record.Event == nil {
+				continue
+			}
+			if _, exists := q.pending[record.Event.ID]; !exists {
+				q.order = append(q.order, record.Event.ID)
+			}
+			q.pending[record.Event.ID] = record.Event
+		case queueOpAck:
+			if _, exists := q.pending[record.ID]; exists {
+				delete(q.pending, record.ID)
+				q.acked++
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read queue file: %w", err)
+	}
+	return nil
+}
+
+// write appends a record to the log and syncs it to disk.
+func (q *FileQueue) write(record queueRecord) error {
+	if q.file == nil {
+		return fmt.Errorf("queue is closed")
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queue record: %w", err)
+	}
+	data = append(data, '\n')
+
+	if _, err := q.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write queue record: %w", err)
+	}
+	return q.file.Sync()
+}
+
+// compact rewrites the log so it only contains pending events.
+func (q *FileQueue) compact() error {
+	tmpPath := q.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create compacted queue file: %w", err)
+	}
+
+	order := make([]string, 0, len(q.pending))
+	writer := bufio.NewWriter(tmp)
+	for _, id := range q.order {
+		event, exists := q.pending[id]
+		if !exists {
+			continue
+		}
+		data, err := json.Marshal(queueRecord{Op: queueOpAppend, Event: event})
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to marshal queue record: %w", err)
+		}
+		writer.Write(append(data, '\n'))
+		order = append(order, id)
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write compacted queue file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync compacted queue file: %w", err)
+	}
+	tmp.Close()
+
+	q.file.Close()
+	if err := os.Rename(tmpPath, q.path); err != nil {
+		return fmt.Errorf("failed to replace queue file: %w", err)
+	}
+
+	file, err := os.OpenFile(q.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		q.file = nil
+		return fmt.Errorf("failed to reopen queue file: %w", err)
+	}
+
+	q.file = file
+	q.order = order
+	q.acked = 0
+	return nil
+}
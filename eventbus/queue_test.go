@@ -0,0 +1,162 @@
+package eventbus
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileQueue_PendingAndAck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	queue, err := NewFileQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": "first"})
+	second := NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": "second"})
+
+	if err := queue.Append(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Append(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Ack(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	queue.Close()
+
+	// Reopen to simulate a restart
+	queue, err = NewFileQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+
+	pending, err := queue.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 1 || pending[0].ID != second.ID {
+		t.Fatalf("Expected only the second event to be pending, got %v", pending)
+	}
+
+	data, ok := pending[0].Data.(map[string]interface{})
+	if !ok || data["api_id"] != "second" {
+		t.Errorf("Expected event data to survive restart, got %v", pending[0].Data)
+	}
+}
+
+func TestFileQueue_Compaction(t *testing.T) {
+	queue, err := NewFileQueue(filepath.Join(t.TempDir(), "queue.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+	queue.compactThreshold = 5
+
+	var last *Event
+	for i := 0; i < 10; i++ {
+		last = NewEvent(TestEventTypeAPICreate, i)
+		queue.Append(last)
+		if i < 9 {
+			queue.Ack(last.ID)
+		}
+	}
+
+	pending, _ := queue.Pending()
+	if len(pending) != 1 || pending[0].ID != last.ID {
+		t.Fatalf("Expected the last event to remain pending after compaction, got %v", pending)
+	}
+}
+
+func TestEventBus_DurableQueueRecovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	// Simulate a crash: the event was persisted but never processed
+	queue, err := NewFileQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": "recovered"})
+	queue.Append(event)
+	queue.Close()
+
+	queue, err = NewFileQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan *Event, 1)
+	bus := New(WithDurableQueue(queue), WithAsyncWorkers(1))
+
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		received <- e
+		return nil
+	}))
+
+	select {
+	case e := <-received:
+		if e.ID != event.ID {
+			t.Errorf("Expected recovered event %s, got %s", event.ID, e.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Recovered event was not delivered")
+	}
+
+	bus.Close()
+
+	if stats := bus.GetStats(); stats.EventsRecovered != 1 {
+		t.Errorf("Expected 1 recovered event, got %d", stats.EventsRecovered)
+	}
+
+	queue, err = NewFileQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+
+	if pending, _ := queue.Pending(); len(pending) != 0 {
+		t.Errorf("Expected no pending events after processing, got %d", len(pending))
+	}
+}
+
+func TestEventBus_DurableQueueOverflow(t *testing.T) {
+	queue, err := NewFileQueue(filepath.Join(t.TempDir(), "queue.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus := New(WithDurableQueue(queue), WithAsyncWorkers(1), WithAsyncQueueSize(1))
+	defer bus.Close()
+
+	var mu sync.Mutex
+	count := 0
+	done := make(chan struct{})
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		if count == 20 {
+			close(done)
+		}
+		return nil
+	}))
+
+	for i := 0; i < 20; i++ {
+		if err := bus.PublishAsync(NewEvent(TestEventTypeAPICreate, i)); err != nil {
+			t.Fatalf("Expected durable publish to succeed when the queue is full, got %v", err)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Not all overflowed events were delivered")
+	}
+}
@@ -0,0 +1,195 @@
+// Package eventbus provides delayed and scheduled publishing of async events.
+package eventbus
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultSchedulerTick is the resolution of the scheduler's timer wheel.
+	defaultSchedulerTick = 50 * time.Millisecond
+
+	// timerWheelSlots is the number of slots in the timer wheel. Delays longer
+	// than one revolution wrap around and wait for additional rounds.
+	timerWheelSlots = 512
+)
+
+// timerEntry is an event waiting in the timer wheel.
+type timerEntry struct {
+	event  *Event
+	due    time.Time
+	slot   int
+	rounds int
+}
+
+// timerWheel is a hashed timing wheel. Each tick advances to the next slot and
+// releases the entries in it whose remaining rounds have reached zero, so
+// scheduling and cancelling are O(1) regardless of how many events are waiting.
+type timerWheel struct {
+	tick    time.Duration
+	slots   []map[string]*timerEntry
+	entries map[string]*timerEntry
+	pos     int
+	mu      sync.Mutex
+}
+
+// newTimerWheel creates a timer wheel with the given tick resolution.
+func newTimerWheel(tick time.Duration) *timerWheel {
+	if tick <= 0 {
+		tick = defaultSchedulerTick
+	}
+
+	slots := make([]map[string]*timerEntry, timerWheelSlots)
+	for i := range slots {
+		slots[i] = make(map[string]*timerEntry)
+	}
+
+	return &timerWheel{
+		tick:    tick,
+		slots:   slots,
+		entries: make(map[string]*timerEntry),
+	}
+}
+
+// add schedules an event to be released after delay, rounded up to the next
+// tick. Scheduling an event ID that is already waiting replaces it.
+func (w *timerWheel) add(event *Event, delay time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.removeLocked(event.ID)
+
+	ticks := int((delay + w.tick - 1) / w.tick)
+	if ticks < 1 {
+		ticks = 1
+	}
+
+	entry := &timerEntry{
+		event:  event,
+		due:    time.Now().Add(delay),
+		slot:   (w.pos + ticks) % len(w.slots),
+		rounds: (ticks - 1) / len(w.slots),
+	}
+
+	w.slots[entry.slot][event.ID] = entry
+	w.entries[event.ID] = entry
+}
+
+// remove cancels a scheduled event and reports whether it was waiting.
+func (w *timerWheel) remove(eventID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.removeLocked(eventID)
+}
+
+// removeLocked cancels a scheduled event; the caller must hold w.mu.
+func (w *timerWheel) removeLocked(eventID string) bool {
+	entry, exists := w.entries[eventID]
+	if !exists {
+		return false
+	}
+	delete(w.slots[entry.slot], eventID)
+	delete(w.entries, eventID)
+	return true
+}
+
+// advance moves the wheel forward one tick and returns the events that are due,
+// earliest first.
+func (w *timerWheel) advance() []*Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pos = (w.pos + 1) % len(w.slots)
+
+	var due []*timerEntry
+	for id, entry := range w.slots[w.pos] {
+		if entry.rounds > 0 {
+			entry.rounds--
+			continue
+		}
+		due = append(due, entry)
+		delete(w.slots[w.pos], id)
+		delete(w.entries, id)
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].due.Before(due[j].due)
+	})
+
+	events := make([]*Event, len(due))
+	for i, entry := range due {
+		events[i] = entry.event
+	}
+	return events
+}
+
+// len returns the number of scheduled events.
+func (w *timerWheel) len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.entries)
+}
+
+// PublishAfter publishes an event asynchronously once delay has elapsed.
+func (eb *eventBus) PublishAfter(event *Event, delay time.Duration) error {
+	select {
+	case <-eb.stopChan:
+		return fmt.Errorf("event bus is closed")
+	default:
+	}
+
+	if err := eb.validateSchema(event); err != nil {
+		return err
+	}
+
+	eb.scheduler.add(event, delay)
+
+	eb.logger.Debug("Scheduled async event",
+		zap.String("id", event.ID),
+		zap.String("type", string(event.Type)),
+		zap.Duration("delay", delay))
+
+	return nil
+}
+
+// PublishAt publishes an event asynchronously at the given time. Times in the
+// past publish on the next scheduler tick.
+func (eb *eventBus) PublishAt(event *Event, at time.Time) error {
+	return eb.PublishAfter(event, time.Until(at))
+}
+
+// CancelScheduled cancels a delayed or scheduled event that has not been
+// published yet and reports whether it was found.
+func (eb *eventBus) CancelScheduled(eventID string) bool {
+	return eb.scheduler.remove(eventID)
+}
+
+// schedulerLoop advances the timer wheel and publishes events as they come due.
+func (eb *eventBus) schedulerLoop() {
+	defer eb.wg.Done()
+
+	ticker := time.NewTicker(eb.scheduler.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, event := range eb.scheduler.advance() {
+				if err := eb.PublishAsync(event); err != nil {
+					eb.logger.Error("Failed to publish scheduled event",
+						zap.String("id", event.ID),
+						zap.String("type", string(event.Type)),
+						zap.Error(err))
+					eb.deadLetter(event, err)
+				}
+			}
+		case <-eb.stopChan:
+			return
+		}
+	}
+}
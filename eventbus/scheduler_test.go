@@ -0,0 +1,104 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimerWheel_Rounds(t *testing.T) {
+	wheel := newTimerWheel(time.Millisecond)
+
+	event := NewEvent(TestEventTypeAPICreate, nil)
+	wheel.add(event, time.Duration(timerWheelSlots+2)*time.Millisecond)
+
+	for i := 0; i < timerWheelSlots+1; i++ {
+		if due := wheel.advance(); len(due) != 0 {
+			t.Fatalf("Expected event to wait a full revolution, released at tick %d", i+1)
+		}
+	}
+
+	if due := wheel.advance(); len(due) != 1 || due[0].ID != event.ID {
+		t.Fatalf("Expected event to be released, got %v", due)
+	}
+	if wheel.len() != 0 {
+		t.Errorf("Expected empty wheel, got %d entries", wheel.len())
+	}
+}
+
+func TestEventBus_PublishAfter(t *testing.T) {
+	bus := New(WithSchedulerTick(5 * time.Millisecond))
+	defer bus.Close()
+
+	received := make(chan time.Time, 1)
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		received <- time.Now()
+		return nil
+	}))
+
+	start := time.Now()
+	if err := bus.PublishAfter(NewEvent(TestEventTypeAPICreate, nil), 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	if scheduled := bus.GetStats().ScheduledEvents; scheduled != 1 {
+		t.Errorf("Expected 1 scheduled event, got %d", scheduled)
+	}
+
+	select {
+	case at := <-received:
+		if elapsed := at.Sub(start); elapsed < 50*time.Millisecond {
+			t.Errorf("Expected event after 50ms, got %v", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Delayed event was not published")
+	}
+}
+
+func TestEventBus_PublishAtAndCancel(t *testing.T) {
+	bus := New(WithSchedulerTick(5 * time.Millisecond))
+	defer bus.Close()
+
+	received := make(chan *Event, 2)
+	bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, e *Event) error {
+		received <- e
+		return nil
+	}))
+
+	cancelled := NewEvent(TestEventTypeAPICreate, "cancelled")
+	kept := NewEvent(TestEventTypeAPICreate, "kept")
+
+	bus.PublishAt(cancelled, time.Now().Add(30*time.Millisecond))
+	bus.PublishAt(kept, time.Now().Add(30*time.Millisecond))
+
+	if !bus.CancelScheduled(cancelled.ID) {
+		t.Fatal("Expected scheduled event to be cancelled")
+	}
+	if bus.CancelScheduled(cancelled.ID) {
+		t.Error("Expected second cancel to report not found")
+	}
+
+	select {
+	case e := <-received:
+		if e.ID != kept.ID {
+			t.Fatalf("Expected kept event, got %v", e.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Scheduled event was not published")
+	}
+
+	select {
+	case e := <-received:
+		t.Fatalf("Cancelled event was published: %v", e.Data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEventBus_PublishAfterClosed(t *testing.T) {
+	bus := New()
+	bus.Close()
+
+	if err := bus.PublishAfter(NewEvent(TestEventTypeAPICreate, nil), time.Millisecond); err == nil {
+		t.Error("Expected error scheduling on a closed bus")
+	}
+}
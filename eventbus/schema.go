@@ -0,0 +1,222 @@
+// Package eventbus provides a JSON Schema registry for event payloads.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/edsonmichaque/tykctl-go/jsonschema"
+	"go.uber.org/zap"
+)
+
+// MetadataSchemaVersion is the metadata key holding the schema version an
+// event's payload conforms to. Publishers set it to pin a version; events
+// without it are validated against, and stamped with, the latest version.
+const MetadataSchemaVersion = "eventbus_schema_version"
+
+// SchemaValidationError is returned when an event payload does not match its schema.
+type SchemaValidationError struct {
+	// EventType is the type of the rejected event.
+	EventType EventType
+
+	// Version is the schema version the payload was validated against.
+	Version int
+
+	// Errors are the individual schema violations.
+	Errors []jsonschema.ValidationError
+}
+
+// Error implements the error interface.
+func (e *SchemaValidationError) Error() string {
+	violations := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		violations[i] = fmt.Sprintf("%s: %s", err.Field, err.Description)
+	}
+	return fmt.Sprintf("event %s does not match schema version %d: %s",
+		e.EventType, e.Version, strings.Join(violations, "; "))
+}
+
+// SchemaRegistry holds the JSON Schemas for event payloads, by event type and version.
+type SchemaRegistry struct {
+	schemas map[EventType]map[int]*jsonschema.Validator
+	strict  bool
+	logger  *zap.Logger
+	mu      sync.RWMutex
+}
+
+// NewSchemaRegistry creates an empty schema registry. In strict mode events
+// that do not match their schema, or that request an unknown schema version,
+// are rejected; otherwise they are logged and published anyway.
+func NewSchemaRegistry(strict bool, logger *zap.Logger) *SchemaRegistry {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &SchemaRegistry{
+		schemas: make(map[EventType]map[int]*jsonschema.Validator),
+		strict:  strict,
+		logger:  logger,
+	}
+}
+
+// Register adds a JSON Schema for a version of an event type's payload.
+// Registering an existing version replaces it.
+func (r *SchemaRegistry) Register(eventType EventType, version int, schema string) error {
+	if version < 1 {
+		return fmt.Errorf("invalid schema version %d for event type %s", version, eventType)
+	}
+
+	validator, err := jsonschema.New(schema)
+	if err != nil {
+		return fmt.Errorf("failed to register schema for event type %s: %w", eventType, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.schemas[eventType] == nil {
+		r.schemas[eventType] = make(map[int]*jsonschema.Validator)
+	}
+	r.schemas[eventType][version] = validator
+	return nil
+}
+
+// Unregister removes a schema version for an event type.
+func (r *SchemaRegistry) Unregister(eventType EventType, version int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.schemas[eventType], version)
+	if len(r.schemas[eventType]) == 0 {
+		delete(r.schemas, eventType)
+	}
+}
+
+// Versions returns the registered schema versions for an event type, in ascending order.
+func (r *SchemaRegistry) Versions(eventType EventType) []int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]int, 0, len(r.schemas[eventType]))
+	for version := range r.schemas[eventType] {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// Latest returns the newest registered schema version for an event type.
+func (r *SchemaRegistry) Latest(eventType EventType) (int, bool) {
+	versions := r.Versions(eventType)
+	if len(versions) == 0 {
+		return 0, false
+	}
+	return versions[len(versions)-1], true
+}
+
+// Validate checks an event's payload against its schema. Events whose type
+// has no registered schema are always valid. The version is taken from the
+// event's MetadataSchemaVersion, defaulting to the latest registered
+// version, which is then recorded in the event's metadata.
+func (r *SchemaRegistry) Validate(ctx context.Context, event *Event) error {
+	err := r.validate(ctx, event)
+	if err == nil || r.strict {
+		return err
+	}
+
+	r.logger.Warn("Event failed schema validation",
+		zap.String("id", event.ID),
+		zap.String("type", string(event.Type)),
+		zap.Error(err))
+	return nil
+}
+
+// validate checks an event's payload regardless of strict mode.
+func (r *SchemaRegistry) validate(ctx context.Context, event *Event) error {
+	r.mu.RLock()
+	versions := r.schemas[event.Type]
+	if len(versions) == 0 {
+		r.mu.RUnlock()
+		return nil
+	}
+
+	version, requested, err := requestedSchemaVersion(event)
+	if err != nil {
+		r.mu.RUnlock()
+		return err
+	}
+	if !requested {
+		for v := range versions {
+			if v > version {
+				version = v
+			}
+		}
+	}
+	validator, exists := versions[version]
+	r.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("unknown schema version %d for event type %s", version, event.Type)
+	}
+	if !requested {
+		event.WithMetadata(MetadataSchemaVersion, version)
+	}
+
+	result, err := validator.ValidateObject(ctx, event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to validate event %s: %w", event.ID, err)
+	}
+	if !result.Valid {
+		return &SchemaValidationError{
+			EventType: event.Type,
+			Version:   version,
+			Errors:    result.Errors,
+		}
+	}
+	return nil
+}
+
+// requestedSchemaVersion returns the schema version pinned in an event's
+// metadata. Versions may arrive as numbers or strings, and as float64 after
+// passing through JSON.
+func requestedSchemaVersion(event *Event) (int, bool, error) {
+	value, exists := event.Metadata[MetadataSchemaVersion]
+	if !exists {
+		return 0, false, nil
+	}
+
+	switch v := value.(type) {
+	case int:
+		return v, true, nil
+	case int64:
+		return int(v), true, nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), true, nil
+		}
+	case string:
+		if version, err := strconv.Atoi(strings.TrimPrefix(v, "v")); err == nil {
+			return version, true, nil
+		}
+	}
+	return 0, false, fmt.Errorf("invalid schema version %v for event type %s", value, event.Type)
+}
+
+// Schemas returns the registry of payload schemas validated on publish.
+func (eb *eventBus) Schemas() *SchemaRegistry {
+	return eb.schemas
+}
+
+// validateSchema validates an event before it is published.
+func (eb *eventBus) validateSchema(event *Event) error {
+	if err := eb.schemas.Validate(context.Background(), event); err != nil {
+		eb.mu.Lock()
+		eb.stats.EventsRejected++
+		eb.mu.Unlock()
+		return err
+	}
+	return nil
+}
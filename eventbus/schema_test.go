@@ -0,0 +1,112 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+const testAPISchemaV1 = `{
+	"type": "object",
+	"properties": {"api_id": {"type": "string"}},
+	"required": ["api_id"]
+}`
+
+const testAPISchemaV2 = `{
+	"type": "object",
+	"properties": {"api_id": {"type": "string"}, "name": {"type": "string"}},
+	"required": ["api_id", "name"]
+}`
+
+func TestSchemaRegistry_StrictRejectsInvalidEvents(t *testing.T) {
+	bus := New(WithStrictSchemas(true))
+	defer bus.Close()
+
+	if err := bus.Schemas().Register(TestEventTypeAPICreate, 1, testAPISchemaV1); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	valid := NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": "123"})
+	if err := bus.Publish(valid); err != nil {
+		t.Errorf("Expected valid event to publish, got %v", err)
+	}
+	if valid.Metadata[MetadataSchemaVersion] != 1 {
+		t.Errorf("Expected schema version 1 in metadata, got %v", valid.Metadata[MetadataSchemaVersion])
+	}
+
+	invalid := NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": 123})
+	err := bus.PublishAsync(invalid)
+	var schemaErr *SchemaValidationError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected SchemaValidationError, got %v", err)
+	}
+	if schemaErr.Version != 1 || len(schemaErr.Errors) == 0 {
+		t.Errorf("Unexpected validation error: %v", schemaErr)
+	}
+
+	if stats := bus.GetStats(); stats.EventsRejected != 1 {
+		t.Errorf("Expected 1 rejected event, got %d", stats.EventsRejected)
+	}
+
+	// Event types without a schema are not validated
+	if err := bus.Publish(NewEvent(TestEventTypeCommandStart, "anything")); err != nil {
+		t.Errorf("Expected event without schema to publish, got %v", err)
+	}
+}
+
+func TestSchemaRegistry_LenientLogsInvalidEvents(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+
+	bus.Schemas().Register(TestEventTypeAPICreate, 1, testAPISchemaV1)
+
+	if err := bus.Publish(NewEvent(TestEventTypeAPICreate, map[string]interface{}{})); err != nil {
+		t.Errorf("Expected invalid event to publish outside strict mode, got %v", err)
+	}
+}
+
+func TestSchemaRegistry_VersionNegotiation(t *testing.T) {
+	registry := NewSchemaRegistry(true, nil)
+	registry.Register(TestEventTypeAPICreate, 1, testAPISchemaV1)
+	registry.Register(TestEventTypeAPICreate, 2, testAPISchemaV2)
+
+	if latest, _ := registry.Latest(TestEventTypeAPICreate); latest != 2 {
+		t.Errorf("Expected latest version 2, got %d", latest)
+	}
+
+	data := map[string]interface{}{"api_id": "123"}
+
+	// Validated against the latest version by default
+	if err := registry.Validate(context.Background(), NewEvent(TestEventTypeAPICreate, data)); err == nil {
+		t.Error("Expected event to fail the latest schema")
+	}
+
+	// Publishers can pin an older version, including after a JSON round trip
+	for _, version := range []interface{}{1, float64(1), "1", "v1"} {
+		event := NewEvent(TestEventTypeAPICreate, data).WithMetadata(MetadataSchemaVersion, version)
+		if err := registry.Validate(context.Background(), event); err != nil {
+			t.Errorf("Expected event pinned to version %v to be valid, got %v", version, err)
+		}
+	}
+
+	unknown := NewEvent(TestEventTypeAPICreate, data).WithMetadata(MetadataSchemaVersion, 3)
+	if err := registry.Validate(context.Background(), unknown); err == nil {
+		t.Error("Expected unknown schema version to be rejected")
+	}
+
+	registry.Unregister(TestEventTypeAPICreate, 2)
+	if versions := registry.Versions(TestEventTypeAPICreate); len(versions) != 1 || versions[0] != 1 {
+		t.Errorf("Expected versions [1], got %v", versions)
+	}
+}
+
+func TestSchemaRegistry_RegisterInvalidSchema(t *testing.T) {
+	registry := NewSchemaRegistry(false, nil)
+
+	if err := registry.Register(TestEventTypeAPICreate, 1, `{"type": 5}`); err == nil {
+		t.Error("Expected invalid schema to be rejected")
+	}
+	if err := registry.Register(TestEventTypeAPICreate, 0, testAPISchemaV1); err == nil {
+		t.Error("Expected invalid version to be rejected")
+	}
+}
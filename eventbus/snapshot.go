@@ -0,0 +1,415 @@
+// Package eventbus provides snapshots of the event bus wiring.
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Names of the built-in middleware in snapshots.
+const (
+	MiddlewareSanitization   = "sanitization"
+	MiddlewareLogging        = "logging"
+	MiddlewareMetrics        = "metrics"
+	MiddlewareValidation     = "validation"
+	MiddlewareRateLimit      = "rate_limit"
+	MiddlewareCircuitBreaker = "circuit_breaker"
+	MiddlewareRetry          = "retry"
+	MiddlewareTimeout        = "timeout"
+)
+
+// NamedMiddleware is middleware with a stable name that identifies it in
+// snapshots.
+type NamedMiddleware interface {
+	Middleware
+
+	// Name returns the middleware name.
+	Name() string
+}
+
+// MiddlewareName returns the name identifying middleware in snapshots: the
+// built-in name, the name of a NamedMiddleware, or the Go type otherwise.
+func MiddlewareName(middleware Middleware) string {
+	switch m := middleware.(type) {
+	case NamedMiddleware:
+		return m.Name()
+	case *SanitizationMiddleware:
+		return MiddlewareSanitization
+	case *LoggingMiddleware:
+		return MiddlewareLogging
+	case *MetricsMiddleware:
+		return MiddlewareMetrics
+	case *ValidationMiddleware:
+		return MiddlewareValidation
+	case *RateLimitMiddleware:
+		return MiddlewareRateLimit
+	case *CircuitBreakerMiddleware:
+		return MiddlewareCircuitBreaker
+	case *RetryMiddleware:
+		return MiddlewareRetry
+	case *TimeoutMiddleware:
+		return MiddlewareTimeout
+	}
+	return fmt.Sprintf("%T", middleware)
+}
+
+// Snapshot describes how an event bus is wired, so the wiring can be
+// persisted and restored, or compared to detect configuration drift.
+type Snapshot struct {
+	// Subscriptions lists every subscription, sorted by event type, group
+	// and handler name.
+	Subscriptions []SubscriptionSnapshot `json:"subscriptions" yaml:"subscriptions"`
+
+	// Middleware lists the middleware names in the order they run.
+	Middleware []string `json:"middleware" yaml:"middleware"`
+
+	// RateLimit holds the rate limit settings when rate limiting is in the
+	// middleware chain.
+	RateLimit *RateLimitSnapshot `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+
+	// CircuitBreaker holds the circuit breaker settings when circuit
+	// breaking is in the middleware chain.
+	CircuitBreaker *CircuitBreakerSnapshot `json:"circuit_breaker,omitempty" yaml:"circuit_breaker,omitempty"`
+}
+
+// SubscriptionSnapshot identifies a subscription by handler name.
+type SubscriptionSnapshot struct {
+	EventType EventType `json:"event_type" yaml:"event_type"`
+	Group     string    `json:"group,omitempty" yaml:"group,omitempty"`
+	Handler   string    `json:"handler" yaml:"handler"`
+}
+
+// String returns the subscription as type[/group]:handler.
+func (s SubscriptionSnapshot) String() string {
+	if s.Group != "" {
+		return fmt.Sprintf("%s/%s:%s", s.EventType, s.Group, s.Handler)
+	}
+	return fmt.Sprintf("%s:%s", s.EventType, s.Handler)
+}
+
+// RateLimitSnapshot holds rate limit middleware settings.
+type RateLimitSnapshot struct {
+	DefaultRate  int                     `json:"default_rate" yaml:"default_rate"`
+	DefaultPer   time.Duration           `json:"default_per" yaml:"default_per"`
+	PerEventType map[EventType]RateLimit `json:"per_event_type,omitempty" yaml:"per_event_type,omitempty"`
+}
+
+// CircuitBreakerSnapshot holds circuit breaker middleware settings.
+type CircuitBreakerSnapshot struct {
+	DefaultFailureThreshold int                                  `json:"default_failure_threshold" yaml:"default_failure_threshold"`
+	DefaultTimeout          time.Duration                        `json:"default_timeout" yaml:"default_timeout"`
+	PerEventType            map[EventType]CircuitBreakerSettings `json:"per_event_type,omitempty" yaml:"per_event_type,omitempty"`
+}
+
+// Diff lists the differences from s to other, one per line, or nothing
+// when they describe the same wiring.
+func (s *Snapshot) Diff(other *Snapshot) []string {
+	var diff []string
+
+	theirs := make(map[SubscriptionSnapshot]bool, len(other.Subscriptions))
+	for _, sub := range other.Subscriptions {
+		theirs[sub] = true
+	}
+	ours := make(map[SubscriptionSnapshot]bool, len(s.Subscriptions))
+	for _, sub := range s.Subscriptions {
+		ours[sub] = true
+		if !theirs[sub] {
+			diff = append(diff, "- subscription "+sub.String())
+		}
+	}
+	for _, sub := range other.Subscriptions {
+		if !ours[sub] {
+			diff = append(diff, "+ subscription "+sub.String())
+		}
+	}
+
+	if strings.Join(s.Middleware, ",") != strings.Join(other.Middleware, ",") {
+		diff = append(diff, fmt.Sprintf("~ middleware [%s] -> [%s]",
+			strings.Join(s.Middleware, " "), strings.Join(other.Middleware, " ")))
+	}
+	if !reflect.DeepEqual(s.RateLimit, other.RateLimit) {
+		diff = append(diff, fmt.Sprintf("~ rate_limit %s -> %s", formatSettings(s.RateLimit), formatSettings(other.RateLimit)))
+	}
+	if !reflect.DeepEqual(s.CircuitBreaker, other.CircuitBreaker) {
+		diff = append(diff, fmt.Sprintf("~ circuit_breaker %s -> %s", formatSettings(s.CircuitBreaker), formatSettings(other.CircuitBreaker)))
+	}
+
+	return diff
+}
+
+// formatSettings formats optional middleware settings for Diff.
+func formatSettings(settings interface{}) string {
+	if reflect.ValueOf(settings).IsNil() {
+		return "none"
+	}
+	return fmt.Sprintf("%+v", reflect.ValueOf(settings).Elem().Interface())
+}
+
+// Catalog maps the names used in snapshots to handlers and middleware.
+type Catalog struct {
+	handlers   map[string]Handler
+	middleware map[string]Middleware
+}
+
+// NewCatalog creates an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		handlers:   make(map[string]Handler),
+		middleware: make(map[string]Middleware),
+	}
+}
+
+// AddHandler adds handlers under the names returned by GetName.
+func (c *Catalog) AddHandler(handlers ...Handler) *Catalog {
+	for _, handler := range handlers {
+		c.handlers[handler.GetName()] = handler
+	}
+	return c
+}
+
+// AddMiddleware adds middleware under the names returned by MiddlewareName.
+func (c *Catalog) AddMiddleware(middleware ...Middleware) *Catalog {
+	for _, m := range middleware {
+		c.middleware[MiddlewareName(m)] = m
+	}
+	return c
+}
+
+// Export returns a snapshot of the bus wiring.
+func (eb *eventBus) Export() *Snapshot {
+	snapshot := &Snapshot{
+		Subscriptions: []SubscriptionSnapshot{},
+		Middleware:    []string{},
+	}
+
+	eb.registry.Walk(func(eventType EventType, handlers []Handler) bool {
+		for _, handler := range handlers {
+			if gh, ok := handler.(*groupHandler); ok {
+				for _, member := range gh.handlers() {
+					snapshot.Subscriptions = append(snapshot.Subscriptions, SubscriptionSnapshot{
+						EventType: eventType,
+						Group:     gh.name,
+						Handler:   member.GetName(),
+					})
+				}
+				continue
+			}
+			snapshot.Subscriptions = append(snapshot.Subscriptions, SubscriptionSnapshot{
+				EventType: eventType,
+				Handler:   handler.GetName(),
+			})
+		}
+		return true
+	})
+	sort.Slice(snapshot.Subscriptions, func(i, j int) bool {
+		return snapshot.Subscriptions[i].String() < snapshot.Subscriptions[j].String()
+	})
+
+	for _, middleware := range eb.middlewareChain() {
+		snapshot.Middleware = append(snapshot.Middleware, MiddlewareName(middleware))
+		switch m := middleware.(type) {
+		case *RateLimitMiddleware:
+			snapshot.RateLimit = m.snapshot()
+		case *CircuitBreakerMiddleware:
+			snapshot.CircuitBreaker = m.snapshot()
+		}
+	}
+
+	return snapshot
+}
+
+// Import restores the wiring described by snapshot. The middleware chain is
+// replaced by the snapshot's, reusing the bus's own middleware where the
+// names match and taking the rest from catalog; rate limiting and circuit
+// breaking are created from the snapshot settings when missing. Middleware
+// set later with SetMiddleware runs after the imported chain. Subscriptions
+// missing from the bus are added with handlers from catalog, and existing
+// subscriptions are kept. Nothing changes if any name cannot be resolved.
+func (eb *eventBus) Import(snapshot *Snapshot, catalog *Catalog) ([]Subscription, error) {
+	if catalog == nil {
+		catalog = NewCatalog()
+	}
+
+	var errs []error
+
+	chain, err := eb.resolveMiddleware(snapshot, catalog)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	handlers := make([]Handler, len(snapshot.Subscriptions))
+	for i, sub := range snapshot.Subscriptions {
+		handler, ok := catalog.handlers[sub.Handler]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown handler %q for subscription %s", sub.Handler, sub))
+			continue
+		}
+		handlers[i] = handler
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("failed to import snapshot: %w", err)
+	}
+
+	for _, middleware := range chain {
+		switch m := middleware.(type) {
+		case *RateLimitMiddleware:
+			if snapshot.RateLimit != nil {
+				m.restore(snapshot.RateLimit)
+			}
+		case *CircuitBreakerMiddleware:
+			if snapshot.CircuitBreaker != nil {
+				m.restore(snapshot.CircuitBreaker)
+			}
+		}
+	}
+
+	eb.mu.Lock()
+	eb.builtin = chain
+	eb.middleware = nil
+	eb.mu.Unlock()
+
+	existing := make(map[SubscriptionSnapshot]bool)
+	for _, sub := range eb.Export().Subscriptions {
+		existing[sub] = true
+	}
+
+	var subscriptions []Subscription
+	for i, sub := range snapshot.Subscriptions {
+		if existing[sub] {
+			continue
+		}
+		existing[sub] = true
+
+		var subscription Subscription
+		if sub.Group != "" {
+			subscription, err = eb.SubscribeGroup(sub.EventType, sub.Group, handlers[i])
+		} else {
+			subscription, err = eb.Subscribe(sub.EventType, handlers[i])
+		}
+		if err != nil {
+			return subscriptions, fmt.Errorf("failed to subscribe %s: %w", sub, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+// resolveMiddleware returns the middleware named by the snapshot, in order.
+func (eb *eventBus) resolveMiddleware(snapshot *Snapshot, catalog *Catalog) ([]Middleware, error) {
+	current := make(map[string][]Middleware)
+	for _, m := range eb.middlewareChain() {
+		name := MiddlewareName(m)
+		current[name] = append(current[name], m)
+	}
+
+	var chain []Middleware
+	var errs []error
+	for _, name := range snapshot.Middleware {
+		if ms := current[name]; len(ms) > 0 {
+			chain = append(chain, ms[0])
+			current[name] = ms[1:]
+			continue
+		}
+		if m, ok := catalog.middleware[name]; ok {
+			chain = append(chain, m)
+			continue
+		}
+
+		switch {
+		case name == MiddlewareRateLimit && snapshot.RateLimit != nil:
+			chain = append(chain, NewRateLimitMiddleware())
+		case name == MiddlewareCircuitBreaker && snapshot.CircuitBreaker != nil:
+			chain = append(chain, NewCircuitBreakerMiddleware())
+		default:
+			errs = append(errs, fmt.Errorf("unknown middleware %q", name))
+		}
+	}
+
+	return chain, errors.Join(errs...)
+}
+
+// middlewareChain returns the built-in middleware followed by the global
+// middleware, in the order they run.
+func (eb *eventBus) middlewareChain() []Middleware {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	return append(append([]Middleware{}, eb.builtin...), eb.middleware...)
+}
+
+// handlers returns the handlers of the group's members.
+func (g *groupHandler) handlers() []Handler {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	handlers := make([]Handler, len(g.members))
+	for i, member := range g.members {
+		handlers[i] = member.handler
+	}
+	return handlers
+}
+
+// snapshot returns the configured rate limits.
+func (m *RateLimitMiddleware) snapshot() *RateLimitSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := &RateLimitSnapshot{DefaultRate: m.defaultRate, DefaultPer: m.defaultPer}
+	if len(m.limits) > 0 {
+		snapshot.PerEventType = make(map[EventType]RateLimit, len(m.limits))
+		for eventType, limit := range m.limits {
+			snapshot.PerEventType[eventType] = limit
+		}
+	}
+	return snapshot
+}
+
+// restore replaces the rate limits with the snapshot's, resetting every limiter.
+func (m *RateLimitMiddleware) restore(snapshot *RateLimitSnapshot) {
+	m.mu.Lock()
+	m.limiters = make(map[EventType]*RateLimiter)
+	m.limits = make(map[EventType]RateLimit)
+	m.mu.Unlock()
+
+	m.SetDefaultRateLimit(snapshot.DefaultRate, snapshot.DefaultPer)
+	for eventType, limit := range snapshot.PerEventType {
+		m.SetRateLimit(eventType, limit.Rate, limit.Per)
+	}
+}
+
+// snapshot returns the configured circuit breaker settings.
+func (m *CircuitBreakerMiddleware) snapshot() *CircuitBreakerSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := &CircuitBreakerSnapshot{
+		DefaultFailureThreshold: m.defaultThreshold,
+		DefaultTimeout:          m.defaultTimeout,
+	}
+	if len(m.settings) > 0 {
+		snapshot.PerEventType = make(map[EventType]CircuitBreakerSettings, len(m.settings))
+		for eventType, settings := range m.settings {
+			snapshot.PerEventType[eventType] = settings
+		}
+	}
+	return snapshot
+}
+
+// restore replaces the circuit breaker settings with the snapshot's,
+// closing every breaker.
+func (m *CircuitBreakerMiddleware) restore(snapshot *CircuitBreakerSnapshot) {
+	m.mu.Lock()
+	m.breakers = make(map[EventType]*CircuitBreaker)
+	m.settings = make(map[EventType]CircuitBreakerSettings)
+	m.mu.Unlock()
+
+	m.SetDefaultCircuitBreaker(snapshot.DefaultFailureThreshold, snapshot.DefaultTimeout)
+	for eventType, settings := range snapshot.PerEventType {
+		m.SetCircuitBreaker(eventType, settings.FailureThreshold, settings.Timeout)
+	}
+}
@@ -0,0 +1,149 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// namedMiddleware is user middleware with a stable snapshot name.
+type namedMiddleware struct {
+	name  string
+	calls *[]string
+}
+
+func (m *namedMiddleware) Name() string { return m.name }
+
+func (m *namedMiddleware) Process(ctx context.Context, event *Event, next func(context.Context, *Event) error) error {
+	*m.calls = append(*m.calls, m.name)
+	return next(ctx, event)
+}
+
+func newSnapshotBus() EventBus {
+	rateLimit := DefaultConfig().Middleware.RateLimit
+	rateLimit.Enabled = true
+	rateLimit.PerEventType[TestEventTypeAPICreate] = RateLimit{Rate: 10, Per: time.Second}
+
+	return New(WithRateLimitConfig(rateLimit))
+}
+
+func TestSnapshot_ExportImport(t *testing.T) {
+	audit := NewBaseHandler("audit", 0, time.Second)
+	worker := NewBaseHandler("worker", 0, time.Second)
+	var calls []string
+	tracing := &namedMiddleware{name: "tracing", calls: &calls}
+
+	source := newSnapshotBus()
+	defer source.Close()
+	source.SetMiddleware(tracing)
+	if _, err := source.Subscribe(TestEventTypeAPICreate, audit); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.SubscribeGroup(TestEventTypeCommandStart, "workers", worker); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(source.Export())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []SubscriptionSnapshot{
+		{EventType: TestEventTypeAPICreate, Handler: "audit"},
+		{EventType: TestEventTypeCommandStart, Group: "workers", Handler: "worker"},
+	}
+	if !reflect.DeepEqual(snapshot.Subscriptions, expected) {
+		t.Errorf("Expected subscriptions %v, got %v", expected, snapshot.Subscriptions)
+	}
+	if last := snapshot.Middleware[len(snapshot.Middleware)-1]; last != "tracing" {
+		t.Errorf("Expected tracing middleware last, got %v", snapshot.Middleware)
+	}
+	if snapshot.RateLimit == nil || snapshot.RateLimit.PerEventType[TestEventTypeAPICreate].Rate != 10 {
+		t.Errorf("Expected rate limit settings in snapshot, got %+v", snapshot.RateLimit)
+	}
+
+	target := New()
+	defer target.Close()
+
+	catalog := NewCatalog().AddHandler(audit, worker).AddMiddleware(tracing)
+	subscriptions, err := target.Import(&snapshot, catalog)
+	if err != nil {
+		t.Fatalf("Failed to import snapshot: %v", err)
+	}
+	if len(subscriptions) != 2 {
+		t.Errorf("Expected 2 subscriptions, got %d", len(subscriptions))
+	}
+
+	if diff := snapshot.Diff(target.Export()); len(diff) != 0 {
+		t.Errorf("Expected no drift after import, got %v", diff)
+	}
+
+	if err := target.Publish(NewEvent(TestEventTypeAPICreate, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("Expected imported middleware to run once, got %d", len(calls))
+	}
+
+	// Importing again is idempotent
+	subscriptions, err = target.Import(&snapshot, catalog)
+	if err != nil || len(subscriptions) != 0 {
+		t.Errorf("Expected no new subscriptions on reimport, got %d (%v)", len(subscriptions), err)
+	}
+}
+
+func TestSnapshot_ImportUnknownNames(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+	before := bus.Export()
+
+	snapshot := &Snapshot{
+		Subscriptions: []SubscriptionSnapshot{{EventType: TestEventTypeAPICreate, Handler: "missing"}},
+		Middleware:    []string{"unknown"},
+	}
+
+	_, err := bus.Import(snapshot, NewCatalog())
+	if err == nil {
+		t.Fatal("Expected import to fail")
+	}
+	if !strings.Contains(err.Error(), `"missing"`) || !strings.Contains(err.Error(), `"unknown"`) {
+		t.Errorf("Expected both unknown names in error, got %v", err)
+	}
+
+	if diff := before.Diff(bus.Export()); len(diff) != 0 {
+		t.Errorf("Expected failed import to change nothing, got %v", diff)
+	}
+}
+
+func TestSnapshot_Diff(t *testing.T) {
+	bus := newSnapshotBus()
+	defer bus.Close()
+	baseline := bus.Export()
+
+	if _, err := bus.Subscribe(TestEventTypeAPICreate, NewBaseHandler("late", 0, time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	changed := *baseline.RateLimit
+	changed.DefaultRate = 1
+	if _, err := bus.Import(&Snapshot{Middleware: baseline.Middleware, RateLimit: &changed}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	diff := baseline.Diff(bus.Export())
+	if len(diff) != 2 {
+		t.Fatalf("Expected 2 differences, got %v", diff)
+	}
+	if diff[0] != "+ subscription api.create:late" {
+		t.Errorf("Expected added subscription, got %q", diff[0])
+	}
+	if !strings.HasPrefix(diff[1], "~ rate_limit") {
+		t.Errorf("Expected rate limit change, got %q", diff[1])
+	}
+}
@@ -0,0 +1,232 @@
+// Package eventbus provides event stores for replaying published events.
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MetadataReplayed is the metadata key set on events delivered by Replay.
+const MetadataReplayed = "eventbus_replayed"
+
+// EventStore persists published events so they can be replayed later.
+type EventStore interface {
+	// Append records a published event.
+	Append(event *Event) error
+
+	// Read calls fn for each stored event matching filter, in publish order,
+	// stopping at the first error.
+	Read(ctx context.Context, filter ReplayFilter, fn func(*Event) error) error
+
+	// Close releases the resources held by the store.
+	Close() error
+}
+
+// ReplayFilter selects which stored events are replayed. Zero values match
+// every event.
+type ReplayFilter struct {
+	// Types limits replay to these event types.
+	Types []EventType
+
+	// Source limits replay to events from this source.
+	Source string
+
+	// Key limits replay to events with this key.
+	Key string
+
+	// CorrelationID limits replay to events in this correlation chain.
+	CorrelationID string
+
+	// Since excludes events that occurred before this time.
+	Since time.Time
+
+	// Until excludes events that occurred after this time.
+	Until time.Time
+
+	// Limit is the maximum number of events to replay.
+	Limit int
+}
+
+// Match reports whether an event passes the filter, ignoring Limit.
+func (f ReplayFilter) Match(event *Event) bool {
+	if len(f.Types) > 0 {
+		found := false
+		for _, eventType := range f.Types {
+			if event.Type == eventType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.Source != "" && event.Source != f.Source {
+		return false
+	}
+	if f.Key != "" && event.Key != f.Key {
+		return false
+	}
+	if f.CorrelationID != "" && event.CorrelationID != f.CorrelationID {
+		return false
+	}
+	if !f.Since.IsZero() && event.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.Timestamp.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// FileEventStore is an EventStore backed by an append-only JSON lines file.
+// As with FileQueue, replayed events hold their data as decoded JSON values
+// rather than the original Go types.
+type FileEventStore struct {
+	path string
+	file *os.File
+	mu   sync.Mutex
+}
+
+// NewFileEventStore opens or creates a file-backed event store at path.
+func NewFileEventStore(path string) (*FileEventStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create event store directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event store file: %w", err)
+	}
+
+	return &FileEventStore{path: path, file: file}, nil
+}
+
+// Append records a published event.
+func (s *FileEventStore) Append(event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("event store is closed")
+	}
+	if _, err := s.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return s.file.Sync()
+}
+
+// Read calls fn for each stored event matching filter, in publish order,
+// stopping at the first error.
+func (s *FileEventStore) Read(ctx context.Context, filter ReplayFilter, fn func(*Event) error) error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open event store file: %w", err)
+	}
+	defer file.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		event, err := decodeEvent(scanner.Bytes())
+		if err != nil {
+			// A torn write from a crash leaves a partial last line; skip it
+			continue
+		}
+		if !filter.Match(event) {
+			continue
+		}
+
+		if err := fn(event); err != nil {
+			return err
+		}
+
+		count++
+		if filter.Limit > 0 && count >= filter.Limit {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event store file: %w", err)
+	}
+	return nil
+}
+
+// Close releases the resources held by the store.
+func (s *FileEventStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// Replay delivers stored events matching filter to handler, in publish order,
+// without publishing them to subscribers. Replayed events carry
+// MetadataReplayed so handlers can tell them apart from live events.
+func (eb *eventBus) Replay(ctx context.Context, filter ReplayFilter, handler Handler) error {
+	if eb.store == nil {
+		return fmt.Errorf("no event store configured")
+	}
+
+	return eb.store.Read(ctx, filter, func(event *Event) error {
+		if !handler.CanHandle(event.Type) {
+			return nil
+		}
+
+		event.WithMetadata(MetadataReplayed, true)
+
+		handlerCtx, cancel := context.WithTimeout(withEventTrace(ctx, event), handler.GetTimeout())
+		defer cancel()
+
+		handlerCtx, endSpan := eb.startHandlerSpan(handlerCtx, event, handler)
+		err := handler.Handle(handlerCtx, event)
+		endSpan(err)
+		if err != nil {
+			return fmt.Errorf("failed to replay event %s: %w", event.ID, err)
+		}
+		return nil
+	})
+}
+
+// storeEvent records a published event in the event store.
+func (eb *eventBus) storeEvent(event *Event) {
+	if eb.store == nil {
+		return
+	}
+
+	if err := eb.store.Append(event); err != nil {
+		eb.logger.Error("Failed to store event",
+			zap.String("id", event.ID),
+			zap.String("type", string(event.Type)),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,160 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileEventStore_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	store, err := NewFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus := New(WithEventStore(store))
+	bus.Publish(NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": "first"}))
+	bus.Publish(NewEvent(TestEventTypeCommandStart, nil))
+	bus.PublishAsync(NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": "second"}).WithKey("k"))
+	bus.Close()
+
+	// Reopen to simulate a later run
+	store, err = NewFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus = New(WithEventStore(store))
+	defer bus.Close()
+
+	var replayed []*Event
+	err = bus.Replay(context.Background(), ReplayFilter{Types: []EventType{TestEventTypeAPICreate}},
+		HandlerFunc(func(ctx context.Context, e *Event) error {
+			replayed = append(replayed, e)
+			return nil
+		}))
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	if len(replayed) != 2 {
+		t.Fatalf("Expected 2 replayed events, got %d", len(replayed))
+	}
+	data, _ := replayed[0].Data.(map[string]interface{})
+	if data["api_id"] != "first" {
+		t.Errorf("Expected first event in publish order, got %v", replayed[0].Data)
+	}
+	if replayed[1].Key != "k" {
+		t.Errorf("Expected key k, got %s", replayed[1].Key)
+	}
+	if replayed[0].Metadata[MetadataReplayed] != true {
+		t.Error("Expected replayed events to be marked")
+	}
+}
+
+func TestFileEventStore_Filter(t *testing.T) {
+	store, err := NewFileEventStore(filepath.Join(t.TempDir(), "events.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		event := NewEvent(TestEventTypeAPICreate, i)
+		event.Timestamp = start.Add(time.Duration(i) * time.Minute)
+		event.Source = "cli"
+		if i%2 == 1 {
+			event.Source = "daemon"
+		}
+		store.Append(event)
+	}
+
+	count := func(filter ReplayFilter) int {
+		n := 0
+		store.Read(context.Background(), filter, func(*Event) error {
+			n++
+			return nil
+		})
+		return n
+	}
+
+	if n := count(ReplayFilter{Source: "cli"}); n != 3 {
+		t.Errorf("Expected 3 events from cli, got %d", n)
+	}
+	if n := count(ReplayFilter{Since: start.Add(2 * time.Minute)}); n != 3 {
+		t.Errorf("Expected 3 events since minute 2, got %d", n)
+	}
+	if n := count(ReplayFilter{Until: start.Add(time.Minute)}); n != 2 {
+		t.Errorf("Expected 2 events until minute 1, got %d", n)
+	}
+	if n := count(ReplayFilter{Limit: 2}); n != 2 {
+		t.Errorf("Expected limit of 2 events, got %d", n)
+	}
+}
+
+func TestFileEventStore_SkipsTornWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	store, err := NewFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Append(NewEvent(TestEventTypeAPICreate, nil))
+	store.Close()
+
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	file.WriteString(`{"id":"torn","ty`)
+	file.Close()
+
+	store, _ = NewFileEventStore(path)
+	defer store.Close()
+
+	n := 0
+	if err := store.Read(context.Background(), ReplayFilter{}, func(*Event) error {
+		n++
+		return nil
+	}); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 event, got %d", n)
+	}
+}
+
+func TestEventBus_ReplayErrors(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+
+	handler := HandlerFunc(func(ctx context.Context, e *Event) error { return nil })
+	if err := bus.Replay(context.Background(), ReplayFilter{}, handler); err == nil {
+		t.Error("Expected error without an event store")
+	}
+
+	store, err := NewFileEventStore(filepath.Join(t.TempDir(), "events.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus = New(WithEventStore(store))
+	defer bus.Close()
+
+	bus.Publish(NewEvent(TestEventTypeAPICreate, nil))
+	bus.Publish(NewEvent(TestEventTypeAPICreate, nil))
+
+	failure := errors.New("rebuild failed")
+	calls := 0
+	err = bus.Replay(context.Background(), ReplayFilter{}, HandlerFunc(func(ctx context.Context, e *Event) error {
+		calls++
+		return failure
+	}))
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected handler error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected replay to stop after the first error, got %d calls", calls)
+	}
+}
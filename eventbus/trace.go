@@ -0,0 +1,184 @@
+// Package eventbus provides trace context propagation for events.
+package eventbus
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Metadata keys carrying W3C trace context, so traces survive async
+// queues, durable queues, bridges and the event store.
+const (
+	MetadataTraceParent = "traceparent"
+	MetadataTraceState  = "tracestate"
+)
+
+// SpanContext identifies a span in a distributed trace, following the W3C
+// Trace Context format used by OpenTelemetry.
+type SpanContext struct {
+	// TraceID is the 32 character lowercase hex trace ID.
+	TraceID string
+
+	// SpanID is the 16 character lowercase hex span ID.
+	SpanID string
+
+	// Flags are the W3C trace flags; bit 0 marks the trace as sampled.
+	Flags byte
+
+	// TraceState is the vendor-specific tracestate header value.
+	TraceState string
+}
+
+// IsValid reports whether the span context has a well-formed, non-zero
+// trace ID and span ID.
+func (sc SpanContext) IsValid() bool {
+	return isTraceHex(sc.TraceID, 32) && isTraceHex(sc.SpanID, 16)
+}
+
+// IsSampled reports whether the sampled flag is set.
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&0x01 != 0
+}
+
+// TraceParent returns the span context as a W3C traceparent header value.
+func (sc SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// ParseTraceParent parses a W3C traceparent header value.
+func ParseTraceParent(traceParent string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", traceParent)
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return SpanContext{}, fmt.Errorf("invalid traceparent flags %q", parts[3])
+	}
+
+	sc := SpanContext{TraceID: parts[1], SpanID: parts[2], Flags: flags[0]}
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", traceParent)
+	}
+	return sc, nil
+}
+
+// isTraceHex reports whether s is a non-zero lowercase hex string of length n.
+func isTraceHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	nonZero := false
+	for _, r := range s {
+		switch {
+		case r >= '1' && r <= '9', r >= 'a' && r <= 'f':
+			nonZero = true
+		case r == '0':
+		default:
+			return false
+		}
+	}
+	return nonZero
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a copy of ctx carrying the span context.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context carried by ctx, if any.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// WithTraceContext records the span context carried by ctx in the event
+// metadata, so handlers run in the same trace even on async workers or other
+// processes. Events without a span context in ctx are left unchanged.
+func (e *Event) WithTraceContext(ctx context.Context) *Event {
+	sc, ok := SpanContextFromContext(ctx)
+	if !ok {
+		return e
+	}
+
+	e.WithMetadata(MetadataTraceParent, sc.TraceParent())
+	if sc.TraceState != "" {
+		e.WithMetadata(MetadataTraceState, sc.TraceState)
+	}
+	return e
+}
+
+// TraceContext returns the span context recorded in the event metadata.
+func (e *Event) TraceContext() (SpanContext, bool) {
+	traceParent, ok := e.Metadata[MetadataTraceParent].(string)
+	if !ok {
+		return SpanContext{}, false
+	}
+
+	sc, err := ParseTraceParent(traceParent)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	sc.TraceState, _ = e.Metadata[MetadataTraceState].(string)
+	return sc, true
+}
+
+// Tracer starts spans around event handlers. It mirrors the shape of an
+// OpenTelemetry tracer so an adapter only has to convert SpanContext to and
+// from the OpenTelemetry types.
+type Tracer interface {
+	// Start starts a span as a child of the span context carried by ctx,
+	// if any.
+	Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SpanContext returns the identity of the span.
+	SpanContext() SpanContext
+
+	// RecordError marks the span as failed.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+}
+
+// withEventTrace restores the trace context recorded in an event.
+func withEventTrace(ctx context.Context, event *Event) context.Context {
+	if sc, ok := event.TraceContext(); ok {
+		return ContextWithSpanContext(ctx, sc)
+	}
+	return ctx
+}
+
+// startHandlerSpan starts a span for a handler processing an event. The
+// returned context carries the new span context so events published by the
+// handler with WithTraceContext continue the trace.
+func (eb *eventBus) startHandlerSpan(ctx context.Context, event *Event, handler Handler) (context.Context, func(error)) {
+	if eb.tracer == nil {
+		return ctx, func(error) {}
+	}
+
+	ctx, span := eb.tracer.Start(ctx, "eventbus.handle "+string(event.Type), map[string]string{
+		"eventbus.event.id":     event.ID,
+		"eventbus.event.type":   string(event.Type),
+		"eventbus.event.source": event.Source,
+		"eventbus.handler":      handler.GetName(),
+	})
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctx = ContextWithSpanContext(ctx, sc)
+	}
+
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,163 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSpan records a span started by fakeTracer.
+type fakeSpan struct {
+	name   string
+	parent SpanContext
+	sc     SpanContext
+	err    error
+	ended  chan struct{}
+}
+
+func (s *fakeSpan) SpanContext() SpanContext { return s.sc }
+func (s *fakeSpan) RecordError(err error)    { s.err = err }
+func (s *fakeSpan) End()                     { close(s.ended) }
+
+// fakeTracer hands out sequential span IDs within the parent's trace.
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parent, _ := SpanContextFromContext(ctx)
+	traceID := parent.TraceID
+	if traceID == "" {
+		traceID = "0af7651916cd43dd8448eb211c80319c"
+	}
+
+	span := &fakeSpan{
+		name:   name,
+		parent: parent,
+		sc:     SpanContext{TraceID: traceID, SpanID: fmt.Sprintf("%016x", len(t.spans)+1), Flags: 1},
+		ended:  make(chan struct{}),
+	}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (t *fakeTracer) recorded() []*fakeSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*fakeSpan(nil), t.spans...)
+}
+
+func TestParseTraceParent(t *testing.T) {
+	sc, err := ParseTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	if err != nil {
+		t.Fatalf("ParseTraceParent failed: %v", err)
+	}
+	if sc.TraceID != "0af7651916cd43dd8448eb211c80319c" || sc.SpanID != "b7ad6b7169203331" || !sc.IsSampled() {
+		t.Errorf("Unexpected span context: %+v", sc)
+	}
+	if got := sc.TraceParent(); got != "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01" {
+		t.Errorf("Expected traceparent to round-trip, got %s", got)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra",
+	} {
+		if _, err := ParseTraceParent(invalid); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestEventBus_TracePropagation(t *testing.T) {
+	tracer := &fakeTracer{}
+	bus := New(WithTracer(tracer))
+	defer bus.Close()
+
+	root := SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Flags: 1, TraceState: "vendor=1"}
+
+	followUps := make(chan SpanContext, 1)
+	_, err := bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, event *Event) error {
+		followUp := NewEvent(TestEventTypeCommandStart, nil).WithTraceContext(ctx)
+		sc, _ := followUp.TraceContext()
+		followUps <- sc
+		return errors.New("boom")
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := NewEvent(TestEventTypeAPICreate, nil).WithTraceContext(ContextWithSpanContext(context.Background(), root))
+	if event.Metadata[MetadataTraceState] != "vendor=1" {
+		t.Errorf("Expected tracestate in metadata, got %v", event.Metadata[MetadataTraceState])
+	}
+	if err := bus.PublishAsync(event); err != nil {
+		t.Fatal(err)
+	}
+
+	var followUp SpanContext
+	select {
+	case followUp = <-followUps:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for handler")
+	}
+
+	spans := tracer.recorded()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 handler span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.name != "eventbus.handle api.create" {
+		t.Errorf("Unexpected span name %q", span.name)
+	}
+	if span.parent.TraceID != root.TraceID || span.parent.SpanID != root.SpanID {
+		t.Errorf("Expected handler span to be a child of the publisher span, got parent %+v", span.parent)
+	}
+	if followUp.TraceID != root.TraceID || followUp.SpanID != span.sc.SpanID {
+		t.Errorf("Expected follow-up event to continue from the handler span, got %+v", followUp)
+	}
+
+	select {
+	case <-span.ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for handler span to end")
+	}
+	if span.err == nil {
+		t.Error("Expected handler span to record the handler error")
+	}
+}
+
+func TestEventBus_TraceWithoutTracer(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+
+	root := SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Flags: 1}
+
+	var seen SpanContext
+	_, err := bus.Subscribe(TestEventTypeAPICreate, HandlerFunc(func(ctx context.Context, event *Event) error {
+		seen, _ = SpanContextFromContext(ctx)
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := NewEvent(TestEventTypeAPICreate, nil).WithTraceContext(ContextWithSpanContext(context.Background(), root))
+	if err := bus.Publish(event); err != nil {
+		t.Fatal(err)
+	}
+	if seen.TraceID != root.TraceID || seen.SpanID != root.SpanID {
+		t.Errorf("Expected handler context to carry the event trace, got %+v", seen)
+	}
+}
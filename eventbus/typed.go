@@ -0,0 +1,67 @@
+// Package eventbus provides typed publish and subscribe helpers.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// SubscribeTyped subscribes to an event type with a handler that receives
+// the event data as T instead of interface{}. See DecodeData for how the
+// data is converted.
+func SubscribeTyped[T any](bus EventBus, eventType EventType, fn func(ctx context.Context, data T) error) (Subscription, error) {
+	return bus.Subscribe(eventType, TypedHandler(fn))
+}
+
+// SubscribeTypedGroup is like SubscribeTyped, but joins a consumer group.
+func SubscribeTypedGroup[T any](bus EventBus, eventType EventType, group string, fn func(ctx context.Context, data T) error) (Subscription, error) {
+	return bus.SubscribeGroup(eventType, group, TypedHandler(fn))
+}
+
+// TypedHandler adapts a function that takes typed event data to a Handler.
+func TypedHandler[T any](fn func(ctx context.Context, data T) error) Handler {
+	return HandlerFunc(func(ctx context.Context, event *Event) error {
+		data, err := DecodeData[T](event)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, data)
+	})
+}
+
+// PublishTyped publishes an event with typed data synchronously.
+func PublishTyped[T any](bus EventBus, eventType EventType, data T) error {
+	return bus.Publish(NewEvent(eventType, data))
+}
+
+// PublishTypedAsync publishes an event with typed data asynchronously.
+func PublishTypedAsync[T any](bus EventBus, eventType EventType, data T) error {
+	return bus.PublishAsync(NewEvent(eventType, data))
+}
+
+// DecodeData returns an event's data as T. Data that is already a T (or a
+// *T) is returned as is; anything else, such as the decoded JSON values of
+// events that came from a durable queue, bridge or event store, is converted
+// through JSON.
+func DecodeData[T any](event *Event) (T, error) {
+	var result T
+
+	switch data := event.Data.(type) {
+	case T:
+		return data, nil
+	case *T:
+		if data != nil {
+			return *data, nil
+		}
+	}
+
+	encoded, err := json.Marshal(event.Data)
+	if err != nil {
+		return result, fmt.Errorf("failed to encode %s event data: %w", event.Type, err)
+	}
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return result, fmt.Errorf("failed to decode %s event data as %T: %w", event.Type, result, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+)
+
+type testAPIData struct {
+	APIID string `json:"api_id"`
+	Port  int    `json:"port"`
+}
+
+func TestSubscribeTyped(t *testing.T) {
+	bus := New()
+	defer bus.Close()
+
+	var received []testAPIData
+	_, err := SubscribeTyped(bus, TestEventTypeAPICreate, func(ctx context.Context, data testAPIData) error {
+		received = append(received, data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SubscribeTyped failed: %v", err)
+	}
+
+	if err := PublishTyped(bus, TestEventTypeAPICreate, testAPIData{APIID: "typed", Port: 8080}); err != nil {
+		t.Fatalf("PublishTyped failed: %v", err)
+	}
+	if err := PublishTyped(bus, TestEventTypeAPICreate, &testAPIData{APIID: "pointer"}); err != nil {
+		t.Fatalf("PublishTyped failed: %v", err)
+	}
+
+	// Data decoded from JSON, as delivered by bridges and durable queues
+	bus.Publish(NewEvent(TestEventTypeAPICreate, map[string]interface{}{"api_id": "json", "port": float64(9090)}))
+
+	if len(received) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(received))
+	}
+	if received[0] != (testAPIData{APIID: "typed", Port: 8080}) {
+		t.Errorf("Unexpected typed data %+v", received[0])
+	}
+	if received[1].APIID != "pointer" {
+		t.Errorf("Unexpected pointer data %+v", received[1])
+	}
+	if received[2] != (testAPIData{APIID: "json", Port: 9090}) {
+		t.Errorf("Unexpected JSON data %+v", received[2])
+	}
+}
+
+func TestDecodeDataMismatch(t *testing.T) {
+	event := NewEvent(TestEventTypeAPICreate, map[string]interface{}{"port": "not a number"})
+
+	if _, err := DecodeData[testAPIData](event); err == nil {
+		t.Error("Expected error decoding mismatched data")
+	}
+}
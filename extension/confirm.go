@@ -0,0 +1,137 @@
+package extension
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/edsonmichaque/tykctl-go/terminal"
+	"github.com/spf13/cobra"
+)
+
+// YesFlag is the name of the flag used to skip the install confirmation
+const YesFlag = "yes"
+
+// ErrInstallDeclined is returned when the user declines an install
+var ErrInstallDeclined = errors.New("installation cancelled")
+
+// ErrConfirmationRequired is returned when an install needs confirmation but
+// there is no terminal to ask on
+var ErrConfirmationRequired = errors.New("installation requires confirmation: rerun with --yes to install non-interactively")
+
+// Confirmer asks the user to confirm an action. *prompt.Prompt implements it
+type Confirmer interface {
+	IsInteractive() bool
+	AskConfirmationWithDefault(question string, defaultValue bool) (bool, error)
+}
+
+// AddYesFlag registers the --yes flag on a command
+func AddYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP(YesFlag, "y", false, "Install without asking for confirmation")
+}
+
+// YesFromFlags reads the --yes flag registered by AddYesFlag
+func YesFromFlags(cmd *cobra.Command) (bool, error) {
+	return cmd.Flags().GetBool(YesFlag)
+}
+
+// WriteManifestPreview writes a summary of the manifest being installed. When
+// current is not nil, the summary is a diff against the installed manifest:
+// added entries are green, removed entries red and changed values yellow
+func WriteManifestPreview(w io.Writer, term *terminal.Terminal, repository string, current, next *Manifest) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Extension:   %s (%s)\n", next.Name, repository)
+	if next.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", next.Description)
+	}
+
+	writeField := func(label, previous, value string) {
+		if current != nil && previous != value {
+			fmt.Fprintf(&b, "%-13s%s\n", label+":", term.Yellow(fmt.Sprintf("%s -> %s", previous, value)))
+			return
+		}
+		fmt.Fprintf(&b, "%-13s%s\n", label+":", value)
+	}
+
+	var previous Manifest
+	if current != nil {
+		previous = *current
+	}
+	writeField("Version", previous.Version, next.Version)
+	writeField("Publisher", previous.Publisher, next.Publisher)
+	writeList(&b, term, "Permissions", current != nil, previous.Permissions, next.Permissions)
+	writeList(&b, term, "Hooks", current != nil, previous.Hooks, next.Hooks)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeList writes a list field, marking entries added or removed since the
+// previous manifest when diff is set
+func writeList(b *strings.Builder, term *terminal.Terminal, label string, diff bool, previous, next []string) {
+	if len(previous) == 0 && len(next) == 0 {
+		fmt.Fprintf(b, "%-13snone\n", label+":")
+		return
+	}
+
+	fmt.Fprintf(b, "%s:\n", label)
+	kept := make(map[string]bool, len(next))
+	for _, entry := range next {
+		kept[entry] = true
+		if diff && !containsString(previous, entry) {
+			fmt.Fprintf(b, "  %s\n", term.Green("+ "+entry))
+		} else {
+			fmt.Fprintf(b, "    %s\n", entry)
+		}
+	}
+	if !diff {
+		return
+	}
+	for _, entry := range previous {
+		if !kept[entry] {
+			fmt.Fprintf(b, "  %s\n", term.Red("- "+entry))
+		}
+	}
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmInstall shows the manifest preview and asks the user to confirm,
+// unless the installer was told to assume yes
+func (i *Installer) confirmInstall(repository string, current, next *Manifest) error {
+	if i.assumeYes {
+		return nil
+	}
+	if i.prompt == nil || !i.prompt.IsInteractive() {
+		return ErrConfirmationRequired
+	}
+
+	if err := WriteManifestPreview(i.out, i.term, repository, current, next); err != nil {
+		return fmt.Errorf("failed to write manifest preview: %w", err)
+	}
+
+	question := fmt.Sprintf("Install %s v%s?", next.Name, strings.TrimPrefix(next.Version, "v"))
+	if current != nil {
+		question = fmt.Sprintf("Upgrade %s from v%s to v%s?", next.Name,
+			strings.TrimPrefix(current.Version, "v"), strings.TrimPrefix(next.Version, "v"))
+	}
+
+	confirmed, err := i.prompt.AskConfirmationWithDefault(question, false)
+	if err != nil {
+		return fmt.Errorf("failed to confirm installation: %w", err)
+	}
+	if !confirmed {
+		return ErrInstallDeclined
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package extension
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// fakeConfirmer answers confirmation questions without a terminal
+type fakeConfirmer struct {
+	interactive bool
+	answer      bool
+	questions   []string
+}
+
+func (f *fakeConfirmer) IsInteractive() bool {
+	return f.interactive
+}
+
+func (f *fakeConfirmer) AskConfirmationWithDefault(question string, defaultValue bool) (bool, error) {
+	f.questions = append(f.questions, question)
+	return f.answer, nil
+}
+
+func TestWriteManifestPreview_Install(t *testing.T) {
+	manifest := &Manifest{
+		Name:        "portal",
+		Version:     "1.2.0",
+		Publisher:   "acme",
+		Description: "Manage developer portals",
+		Permissions: []string{"network"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteManifestPreview(&buf, &terminal.Terminal{}, "acme/portal", nil, manifest); err != nil {
+		t.Fatalf("WriteManifestPreview failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"Extension:   portal (acme/portal)",
+		"Description: Manage developer portals",
+		"Version:     1.2.0",
+		"Publisher:   acme",
+		"Permissions:\n    network",
+		"Hooks:       none",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "+ ") {
+		t.Errorf("Expected no diff markers for a fresh install, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteManifestPreview_Upgrade(t *testing.T) {
+	current := &Manifest{Name: "portal", Version: "1.0.0", Publisher: "acme", Permissions: []string{"network", "fs:write"}}
+	next := &Manifest{Name: "portal", Version: "1.2.0", Publisher: "acme", Permissions: []string{"network", "env:read"}, Hooks: []string{"extension-before-run"}}
+
+	var buf bytes.Buffer
+	if err := WriteManifestPreview(&buf, &terminal.Terminal{}, "acme/portal", current, next); err != nil {
+		t.Fatalf("WriteManifestPreview failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"Version:     1.0.0 -> 1.2.0",
+		"Publisher:   acme",
+		"    network",
+		"  + env:read",
+		"  - fs:write",
+		"  + extension-before-run",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestInstaller_ConfirmInstall(t *testing.T) {
+	manifest := &Manifest{Name: "portal", Version: "1.2.0", Publisher: "acme"}
+	current := &Manifest{Name: "portal", Version: "1.0.0", Publisher: "acme"}
+
+	confirmer := &fakeConfirmer{interactive: true, answer: true}
+	var buf bytes.Buffer
+	installer := NewInstaller(t.TempDir(), WithPrompt(confirmer), WithOutput(&buf))
+	installer.term = &terminal.Terminal{}
+
+	if err := installer.confirmInstall("acme/portal", current, manifest); err != nil {
+		t.Fatalf("Expected confirmed upgrade to proceed, got %v", err)
+	}
+	if len(confirmer.questions) != 1 || confirmer.questions[0] != "Upgrade portal from v1.0.0 to v1.2.0?" {
+		t.Errorf("Unexpected questions: %v", confirmer.questions)
+	}
+	if !strings.Contains(buf.String(), "1.0.0 -> 1.2.0") {
+		t.Errorf("Expected preview to be written, got:\n%s", buf.String())
+	}
+
+	confirmer.answer = false
+	if err := installer.confirmInstall("acme/portal", nil, manifest); !errors.Is(err, ErrInstallDeclined) {
+		t.Errorf("Expected ErrInstallDeclined, got %v", err)
+	}
+	if confirmer.questions[1] != "Install portal v1.2.0?" {
+		t.Errorf("Unexpected question: %s", confirmer.questions[1])
+	}
+
+	confirmer.interactive = false
+	if err := installer.confirmInstall("acme/portal", nil, manifest); !errors.Is(err, ErrConfirmationRequired) {
+		t.Errorf("Expected ErrConfirmationRequired, got %v", err)
+	}
+
+	installer.assumeYes = true
+	if err := installer.confirmInstall("acme/portal", nil, manifest); err != nil {
+		t.Errorf("Expected --yes to skip confirmation, got %v", err)
+	}
+	if len(confirmer.questions) != 2 {
+		t.Errorf("Expected no question with --yes, got %v", confirmer.questions)
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	manifest, err := ParseManifest([]byte("name: portal\nversion: 1.2.0\npublisher: acme\npermissions: [network]\nhooks: [extension-before-run]\n"))
+	if err != nil {
+		t.Fatalf("ParseManifest failed: %v", err)
+	}
+	if manifest.Name != "portal" || manifest.Version != "1.2.0" || len(manifest.Permissions) != 1 || len(manifest.Hooks) != 1 {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+
+	if _, err := ParseManifest([]byte("name: [")); err == nil {
+		t.Error("Expected an error for invalid YAML")
+	}
+}
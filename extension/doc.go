@@ -4,6 +4,8 @@
 //   - Extension Discovery: Search and discover Tyk CLI extensions from GitHub
 //   - Extension Installation: Install extensions from GitHub repositories
 //   - Extension Management: List, remove, and manage installed extensions
+//   - Install Scopes: Per-user or system-wide installs with user-first discovery
+//   - Install Confirmation: Manifest preview and upgrade diff before installing
 //   - GitHub Integration: Full GitHub API integration for extension discovery
 //   - Hook Integration: Built-in hook system for extension lifecycle events
 //   - Configuration Management: Persistent configuration and metadata storage
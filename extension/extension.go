@@ -3,12 +3,15 @@ package extension
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/adrg/xdg"
 	"github.com/edsonmichaque/tykctl-go/hook"
+	"github.com/edsonmichaque/tykctl-go/logger"
+	"github.com/edsonmichaque/tykctl-go/prompt"
+	"github.com/edsonmichaque/tykctl-go/terminal"
 	"github.com/google/go-github/v75/github"
 	"go.uber.org/zap"
 	yaml "gopkg.in/yaml.v3"
@@ -38,6 +41,8 @@ type Installed struct {
 	Repository  string    `yaml:"repository"`
 	InstalledAt time.Time `yaml:"installed_at"`
 	Path        string    `yaml:"path"`
+	Scope       Scope     `yaml:"scope,omitempty"`
+	Manifest    *Manifest `yaml:"manifest,omitempty"`
 }
 
 // Installer manages tykctl extensions
@@ -46,6 +51,11 @@ type Installer struct {
 	client    *github.Client
 	logger    *zap.Logger
 	hooks     *hook.BuiltinProcessor
+	scope     Scope
+	prompt    Confirmer
+	assumeYes bool
+	out       io.Writer
+	term      *terminal.Terminal
 }
 
 // InstallerOption defines a functional option for configuring an Installer
@@ -72,6 +82,34 @@ func WithHooks(hooks *hook.BuiltinProcessor) InstallerOption {
 	}
 }
 
+// WithScope sets whether extensions are installed for the current user or system-wide
+func WithScope(scope Scope) InstallerOption {
+	return func(i *Installer) {
+		i.scope = scope
+	}
+}
+
+// WithPrompt sets the prompt used to confirm installs
+func WithPrompt(p Confirmer) InstallerOption {
+	return func(i *Installer) {
+		i.prompt = p
+	}
+}
+
+// WithAssumeYes skips the install confirmation, as with --yes
+func WithAssumeYes(yes bool) InstallerOption {
+	return func(i *Installer) {
+		i.assumeYes = yes
+	}
+}
+
+// WithOutput sets where the manifest preview is written
+func WithOutput(w io.Writer) InstallerOption {
+	return func(i *Installer) {
+		i.out = w
+	}
+}
+
 // NewInstaller creates a new extension installer with the given config directory and options
 func NewInstaller(configDir string, opts ...InstallerOption) *Installer {
 	// Create default GitHub client
@@ -86,6 +124,10 @@ func NewInstaller(configDir string, opts ...InstallerOption) *Installer {
 		client:    client,
 		logger:    logger,
 		hooks:     hooks,
+		scope:     ScopeUser,
+		prompt:    prompt.New(),
+		out:       os.Stderr,
+		term:      terminal.New(),
 	}
 
 	// Apply options
@@ -132,23 +174,54 @@ func (i *Installer) SearchExtensions(ctx context.Context, query string, limit in
 	return extensions, nil
 }
 
-// InstallExtension installs an extension
+// InstallExtension installs an extension. The extension's manifest is shown
+// and the user asked to confirm first, unless WithAssumeYes is set
 func (i *Installer) InstallExtension(ctx context.Context, owner, repo string) error {
 	i.logger.Info("Installing extension",
 		zap.String("owner", owner),
-		zap.String("repo", repo))
+		zap.String("repo", repo),
+		zap.String("scope", string(i.scope)))
+
+	// Fail early rather than after hooks have run
+	if err := CheckScopePermission(i.scope); err != nil {
+		return err
+	}
+
+	manifest, err := i.fetchManifest(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	extensions, err := i.loadExtensions(ctx)
+	if err != nil {
+		return err
+	}
+	var current *Manifest
+	if existing, ok := extensions[repo]; ok {
+		current = existing.Manifest
+		if current == nil {
+			current = &Manifest{Name: existing.Name, Version: existing.Version, Publisher: owner}
+		}
+	}
+
+	repository := fmt.Sprintf("%s/%s", owner, repo)
+	if err := i.confirmInstall(repository, current, manifest); err != nil {
+		return err
+	}
 
 	// Execute before install hooks
 	hookData := hook.NewData(HookTypeBeforeInstall, repo).
 		WithMetadata("owner", owner).
-		WithMetadata("repo", repo)
+		WithMetadata("repo", repo).
+		WithMetadata("version", manifest.Version).
+		WithMetadata("scope", string(i.scope))
 
 	if err := i.hooks.Execute(ctx, HookTypeBeforeInstall, hookData); err != nil {
 		i.logger.Error("Before install hook failed", zap.Error(err))
 		return fmt.Errorf("before install hook failed: %w", err)
 	}
 
-	extensionsDir := filepath.Join(xdg.DataHome, "tykctl", "extensions")
+	extensionsDir := ExtensionsDir(i.scope)
 	extDir := filepath.Join(extensionsDir, fmt.Sprintf("tykctl-%s", repo))
 
 	// Create extension directory
@@ -172,10 +245,12 @@ func (i *Installer) InstallExtension(ctx context.Context, owner, repo string) er
 
 	ext := Installed{
 		Name:        repo,
-		Version:     "1.0.0",
-		Repository:  fmt.Sprintf("https://github.com/%s/%s", owner, repo),
+		Version:     manifest.Version,
+		Repository:  fmt.Sprintf("https://github.com/%s", repository),
 		InstalledAt: time.Now(),
 		Path:        binaryPath,
+		Scope:       i.scope,
+		Manifest:    manifest,
 	}
 
 	if err := i.saveExtension(ctx, &ext); err != nil {
@@ -188,12 +263,19 @@ func (i *Installer) InstallExtension(ctx context.Context, owner, repo string) er
 	i.logger.Info("Extension installed successfully",
 		zap.String("name", repo),
 		zap.String("path", binaryPath))
+	logger.Audit("extension.install", append(logger.ContextFields(ctx),
+		zap.String("name", repo),
+		zap.String("repository", repository),
+		zap.String("version", manifest.Version),
+		zap.String("scope", string(i.scope)),
+		zap.String("path", binaryPath))...)
 
 	// Execute after install hooks
 	afterHookData := hook.NewData(HookTypeAfterInstall, repo).
 		WithMetadata("owner", owner).
 		WithMetadata("repo", repo).
-		WithMetadata("path", binaryPath)
+		WithMetadata("path", binaryPath).
+		WithMetadata("scope", string(i.scope))
 	if err := i.hooks.Execute(ctx, HookTypeAfterInstall, afterHookData); err != nil {
 		i.logger.Error("After install hook failed", zap.Error(err))
 		// Don't fail the installation if after hooks fail
@@ -216,6 +298,10 @@ func (i *Installer) RemoveExtension(ctx context.Context, name string) error {
 		return fmt.Errorf("extension %s not found", name)
 	}
 
+	if err := CheckScopePermission(i.scope); err != nil {
+		return err
+	}
+
 	// Execute before uninstall hooks
 	hookData := hook.NewData(HookTypeBeforeUninstall, name).
 		WithMetadata("version", ext.Version).
@@ -241,6 +327,11 @@ func (i *Installer) RemoveExtension(ctx context.Context, name string) error {
 	}
 
 	i.logger.Info("Extension removed successfully", zap.String("name", name))
+	logger.Audit("extension.remove", append(logger.ContextFields(ctx),
+		zap.String("name", name),
+		zap.String("version", ext.Version),
+		zap.String("scope", string(i.scope)),
+		zap.String("path", ext.Path))...)
 
 	// Execute after uninstall hooks
 	afterHookData := hook.NewData(HookTypeAfterUninstall, name).
@@ -254,7 +345,7 @@ func (i *Installer) RemoveExtension(ctx context.Context, name string) error {
 	return nil
 }
 
-// ListInstalledExtensions lists all installed extensions
+// ListInstalledExtensions lists the extensions installed in the installer's scope
 func (i *Installer) ListInstalledExtensions(ctx context.Context) ([]Installed, error) {
 	extensions, err := i.loadExtensions(ctx)
 	if err != nil {
@@ -271,7 +362,7 @@ func (i *Installer) ListInstalledExtensions(ctx context.Context) ([]Installed, e
 
 // loadExtensions loads the extensions registry
 func (i *Installer) loadExtensions(ctx context.Context) (map[string]Installed, error) {
-	extFile := filepath.Join(i.configDir, "extensions.yaml")
+	extFile := registryFile(i.scope, i.configDir)
 
 	if _, err := os.Stat(extFile); os.IsNotExist(err) {
 		return make(map[string]Installed), nil
@@ -303,7 +394,7 @@ func (i *Installer) saveExtension(ctx context.Context, ext *Installed) error {
 
 // saveExtensions saves the extensions registry
 func (i *Installer) saveExtensions(ctx context.Context, extensions map[string]Installed) error {
-	extFile := filepath.Join(i.configDir, "extensions.yaml")
+	extFile := registryFile(i.scope, i.configDir)
 
 	data, err := yaml.Marshal(extensions)
 	if err != nil {
@@ -7,7 +7,6 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/adrg/xdg"
 	"github.com/edsonmichaque/tykctl-go/fs"
 	"github.com/google/go-github/v75/github"
 	"go.uber.org/zap"
@@ -20,6 +19,7 @@ type Manager struct {
 	configDir string
 	fs        *fs.FS
 	client    *github.Client
+	scope     Scope
 }
 
 // NewManager creates a new extension manager
@@ -35,6 +35,7 @@ func NewManager(configDir string) *Manager {
 		configDir: configDir,
 		fs:        fsInstance,
 		client:    client,
+		scope:     ScopeUser,
 	}
 }
 
@@ -51,9 +52,17 @@ func NewManagerWithAuth(configDir, token string) *Manager {
 		configDir: configDir,
 		fs:        fsInstance,
 		client:    client,
+		scope:     ScopeUser,
 	}
 }
 
+// NewManagerWithScope creates a new extension manager that installs into the given scope
+func NewManagerWithScope(configDir string, scope Scope) *Manager {
+	manager := NewManager(configDir)
+	manager.scope = scope
+	return manager
+}
+
 // SearchExtensions searches for extensions
 func (em *Manager) SearchExtensions(query string, limit int) ([]Info, error) {
 	logger := zap.L().Sugar()
@@ -107,9 +116,14 @@ func (em *Manager) InstallExtension(ctx context.Context, owner, repo string) err
 
 	logger.Infow("Installing extension",
 		"owner", owner,
-		"repo", repo)
+		"repo", repo,
+		"scope", em.scope)
+
+	if err := CheckScopePermission(em.scope); err != nil {
+		return err
+	}
 
-	extensionsDir := em.fs.JoinPath(xdg.DataHome, "tykctl", "extensions")
+	extensionsDir := ExtensionsDir(em.scope)
 	extDir := em.fs.JoinPath(extensionsDir, fmt.Sprintf("tykctl-%s", repo))
 
 	if err := em.fs.EnsureDir(ctx, extDir, 0o755); err != nil {
@@ -136,6 +150,7 @@ func (em *Manager) InstallExtension(ctx context.Context, owner, repo string) err
 		Repository:  fmt.Sprintf("https://github.com/%s/%s", owner, repo),
 		InstalledAt: time.Now(),
 		Path:        binaryPath,
+		Scope:       em.scope,
 	}
 
 	if err := em.saveExtension(ctx, &ext); err != nil {
@@ -170,6 +185,10 @@ func (em *Manager) RemoveExtension(ctx context.Context, name string) error {
 		return fmt.Errorf("extension %s not found", name)
 	}
 
+	if err := CheckScopePermission(em.scope); err != nil {
+		return err
+	}
+
 	if err := em.fs.RemoveAllIfExists(ctx, em.fs.JoinPath(filepath.Dir(ext.Path))); err != nil {
 		logger.Errorw("Failed to remove extension directory",
 			"path", ext.Path,
@@ -189,7 +208,7 @@ func (em *Manager) RemoveExtension(ctx context.Context, name string) error {
 	return nil
 }
 
-// ListInstalledExtensions lists all installed extensions
+// ListInstalledExtensions lists the extensions installed in the manager's scope
 func (em *Manager) ListInstalledExtensions(ctx context.Context) ([]Installed, error) {
 	extensions, err := em.loadExtensions(ctx)
 	if err != nil {
@@ -206,7 +225,7 @@ func (em *Manager) ListInstalledExtensions(ctx context.Context) ([]Installed, er
 
 // loadExtensions loads the extensions registry
 func (em *Manager) loadExtensions(ctx context.Context) (map[string]Installed, error) {
-	extFile := em.fs.JoinPath(em.configDir, "extensions.yaml")
+	extFile := registryFile(em.scope, em.configDir)
 
 	exists, err := em.fs.Exists(ctx, extFile)
 	if err != nil {
@@ -242,7 +261,7 @@ func (em *Manager) saveExtension(ctx context.Context, ext *Installed) error {
 
 // saveExtensions saves the extensions registry
 func (em *Manager) saveExtensions(ctx context.Context, extensions map[string]Installed) error {
-	extFile := em.fs.JoinPath(em.configDir, "extensions.yaml")
+	extFile := registryFile(em.scope, em.configDir)
 
 	data, err := yaml.Marshal(extensions)
 	if err != nil {
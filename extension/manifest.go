@@ -0,0 +1,67 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the manifest at the root of an extension repository
+const ManifestFile = "tykctl-extension.yaml"
+
+// defaultVersion is the version assumed for extensions without a manifest
+const defaultVersion = "1.0.0"
+
+// Manifest describes what an extension is and what it needs
+type Manifest struct {
+	Name        string   `yaml:"name"`
+	Version     string   `yaml:"version"`
+	Publisher   string   `yaml:"publisher"`
+	Description string   `yaml:"description,omitempty"`
+	Permissions []string `yaml:"permissions,omitempty"`
+	Hooks       []string `yaml:"hooks,omitempty"`
+}
+
+// ParseManifest parses an extension manifest
+func ParseManifest(data []byte) (*Manifest, error) {
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse extension manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// fetchManifest reads the manifest from an extension repository. Repositories
+// without one get a manifest naming the repository, its owner as publisher
+// and the default version, with no permissions or hooks
+func (i *Installer) fetchManifest(ctx context.Context, owner, repo string) (*Manifest, error) {
+	manifest := &Manifest{}
+
+	file, _, resp, err := i.client.Repositories.GetContents(ctx, owner, repo, ManifestFile, nil)
+	switch {
+	case resp != nil && resp.StatusCode == http.StatusNotFound:
+	case err != nil:
+		return nil, fmt.Errorf("failed to fetch extension manifest: %w", err)
+	case file != nil:
+		content, err := file.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode extension manifest: %w", err)
+		}
+		if manifest, err = ParseManifest([]byte(content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if manifest.Name == "" {
+		manifest.Name = repo
+	}
+	if manifest.Version == "" {
+		manifest.Version = defaultVersion
+	}
+	if manifest.Publisher == "" {
+		manifest.Publisher = owner
+	}
+	return manifest, nil
+}
@@ -6,8 +6,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
-	"github.com/adrg/xdg"
 	"github.com/edsonmichaque/tykctl-go/hook"
 	"go.uber.org/zap"
 )
@@ -157,67 +157,53 @@ func (r *Runner) IsExtensionAvailable(extensionName string) bool {
 	return r.findExtension(extensionName) != ""
 }
 
-// ListAvailableExtensions returns a list of all available extensions
+// ListAvailableExtensions returns a list of all available extensions across
+// the user, legacy and system directories
 func (r *Runner) ListAvailableExtensions() ([]string, error) {
 	var extensions []string
+	seen := make(map[string]bool)
 
-	// Check XDG data directory
-	dataDir := filepath.Join(xdg.DataHome, "tykctl", "extensions")
-	if entries, err := os.ReadDir(dataDir); err == nil {
-		for _, entry := range entries {
-			if entry.IsDir() {
-				extName := entry.Name()
-				if len(extName) > 8 && extName[:8] == "tykctl-" {
-					extensions = append(extensions, extName[8:])
-				}
-			}
+	for _, dir := range DiscoveryDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
 		}
-	}
-
-	// Check legacy config directory
-	configDir := filepath.Join(xdg.ConfigHome, "tykctl", "extensions")
-	if entries, err := os.ReadDir(configDir); err == nil {
 		for _, entry := range entries {
-			if entry.IsDir() {
-				extName := entry.Name()
-				if len(extName) > 8 && extName[:8] == "tykctl-" {
-					// Avoid duplicates
-					found := false
-					for _, existing := range extensions {
-						if existing == extName[8:] {
-							found = true
-							break
-						}
-					}
-					if !found {
-						extensions = append(extensions, extName[8:])
-					}
-				}
+			if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "tykctl-") {
+				continue
+			}
+			extName := strings.TrimPrefix(entry.Name(), "tykctl-")
+			// Avoid duplicates; earlier directories take precedence
+			if extName == "" || seen[extName] {
+				continue
 			}
+			seen[extName] = true
+			extensions = append(extensions, extName)
 		}
 	}
 
 	return extensions, nil
 }
 
-// findExtension finds an extension binary
+// findExtension finds an extension binary. User installs shadow system
+// installs, which in turn shadow binaries on PATH.
 func (r *Runner) findExtension(name string) string {
-	// Check XDG data directory
-	dataDir := filepath.Join(xdg.DataHome, "tykctl", "extensions")
-	xdgPath := filepath.Join(dataDir, fmt.Sprintf("tykctl-%s", name))
-	if _, err := os.Stat(xdgPath); err == nil {
-		return xdgPath
-	}
-
-	// Check legacy config directory
-	configDir := filepath.Join(xdg.ConfigHome, "tykctl", "extensions")
-	legacyPath := filepath.Join(configDir, fmt.Sprintf("tykctl-%s", name))
-	if _, err := os.Stat(legacyPath); err == nil {
-		return legacyPath
+	binaryName := fmt.Sprintf("tykctl-%s", name)
+
+	for _, dir := range DiscoveryDirs() {
+		// Installers place the binary in a directory of the same name
+		for _, candidate := range []string{
+			filepath.Join(dir, binaryName, binaryName),
+			filepath.Join(dir, binaryName),
+		} {
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate
+			}
+		}
 	}
 
 	// Check PATH
-	path, err := exec.LookPath(fmt.Sprintf("tykctl-%s", name))
+	path, err := exec.LookPath(binaryName)
 	if err == nil {
 		return path
 	}
@@ -0,0 +1,116 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+)
+
+// Scope determines where an extension is installed
+type Scope string
+
+const (
+	// ScopeUser installs extensions for the current user in the XDG data directory
+	ScopeUser Scope = "user"
+	// ScopeSystem installs extensions for all users on the machine
+	ScopeSystem Scope = "system"
+)
+
+// ScopeFlag is the name of the flag used to select an install scope
+const ScopeFlag = "scope"
+
+// SystemDir is the root directory for system-wide extensions. It can be
+// overridden with the TYKCTL_SYSTEM_DIR environment variable.
+var SystemDir = "/usr/local/lib/tykctl"
+
+// ParseScope parses a scope name, defaulting to ScopeUser when empty
+func ParseScope(s string) (Scope, error) {
+	switch Scope(strings.ToLower(strings.TrimSpace(s))) {
+	case "", ScopeUser:
+		return ScopeUser, nil
+	case ScopeSystem:
+		return ScopeSystem, nil
+	default:
+		return "", fmt.Errorf("invalid scope %q: must be %q or %q", s, ScopeUser, ScopeSystem)
+	}
+}
+
+// AddScopeFlag registers the --scope flag on a command
+func AddScopeFlag(cmd *cobra.Command) {
+	cmd.Flags().String(ScopeFlag, string(ScopeUser), "Install scope (user, system)")
+}
+
+// ScopeFromFlags reads the --scope flag registered by AddScopeFlag
+func ScopeFromFlags(cmd *cobra.Command) (Scope, error) {
+	value, err := cmd.Flags().GetString(ScopeFlag)
+	if err != nil {
+		return "", err
+	}
+	return ParseScope(value)
+}
+
+// ExtensionsDir returns the directory extensions are installed into for a scope
+func ExtensionsDir(scope Scope) string {
+	if scope == ScopeSystem {
+		return filepath.Join(systemDir(), "extensions")
+	}
+	return filepath.Join(xdg.DataHome, "tykctl", "extensions")
+}
+
+// DiscoveryDirs returns the directories searched for extensions, in order of
+// precedence. User installs take precedence over system installs so users
+// can override an extension preinstalled by an administrator.
+func DiscoveryDirs() []string {
+	return []string{
+		ExtensionsDir(ScopeUser),
+		filepath.Join(xdg.ConfigHome, "tykctl", "extensions"),
+		ExtensionsDir(ScopeSystem),
+	}
+}
+
+// CheckScopePermission verifies that the current user can install into a scope
+func CheckScopePermission(scope Scope) error {
+	dir := ExtensionsDir(scope)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return scopePermissionError(scope, dir, err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".tykctl-write-check-*")
+	if err != nil {
+		return scopePermissionError(scope, dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return nil
+}
+
+// registryFile returns the path of the installed extensions registry for a scope
+func registryFile(scope Scope, configDir string) string {
+	if scope == ScopeSystem {
+		return filepath.Join(systemDir(), "extensions.yaml")
+	}
+	return filepath.Join(configDir, "extensions.yaml")
+}
+
+// systemDir returns the system-wide root directory
+func systemDir() string {
+	if dir := os.Getenv("TYKCTL_SYSTEM_DIR"); dir != "" {
+		return dir
+	}
+	return SystemDir
+}
+
+// scopePermissionError wraps a permission failure with a hint for the user
+func scopePermissionError(scope Scope, dir string, err error) error {
+	if scope == ScopeSystem && os.IsPermission(err) {
+		return fmt.Errorf("insufficient permissions to install system-wide extensions in %s (run as an administrator or use --%s %s): %w",
+			dir, ScopeFlag, ScopeUser, err)
+	}
+	return fmt.Errorf("cannot write to extensions directory %s: %w", dir, err)
+}
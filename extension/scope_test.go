@@ -0,0 +1,102 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+func TestParseScope(t *testing.T) {
+	tests := map[string]Scope{
+		"":       ScopeUser,
+		"user":   ScopeUser,
+		"System": ScopeSystem,
+	}
+	for input, expected := range tests {
+		scope, err := ParseScope(input)
+		if err != nil {
+			t.Fatalf("ParseScope(%q) failed: %v", input, err)
+		}
+		if scope != expected {
+			t.Fatalf("Expected scope %s for %q, got %s", expected, input, scope)
+		}
+	}
+
+	if _, err := ParseScope("global"); err == nil {
+		t.Fatal("Expected error for invalid scope")
+	}
+}
+
+func TestExtensionsDir_System(t *testing.T) {
+	systemRoot := t.TempDir()
+	t.Setenv("TYKCTL_SYSTEM_DIR", systemRoot)
+
+	expected := filepath.Join(systemRoot, "extensions")
+	if dir := ExtensionsDir(ScopeSystem); dir != expected {
+		t.Fatalf("Expected system dir %s, got %s", expected, dir)
+	}
+
+	if err := CheckScopePermission(ScopeSystem); err != nil {
+		t.Fatalf("CheckScopePermission failed: %v", err)
+	}
+}
+
+func TestCheckScopePermission_ReadOnly(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+
+	systemRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(systemRoot, "extensions"), 0o555); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TYKCTL_SYSTEM_DIR", systemRoot)
+
+	if err := CheckScopePermission(ScopeSystem); err == nil {
+		t.Fatal("Expected permission error for read-only system directory")
+	}
+}
+
+func TestRunner_FindExtensionPrecedence(t *testing.T) {
+	dataHome := t.TempDir()
+	systemRoot := t.TempDir()
+	t.Setenv("TYKCTL_SYSTEM_DIR", systemRoot)
+
+	originalDataHome := xdg.DataHome
+	xdg.DataHome = dataHome
+	defer func() { xdg.DataHome = originalDataHome }()
+
+	writeExtension := func(dir string) string {
+		extDir := filepath.Join(dir, "tykctl-demo")
+		if err := os.MkdirAll(extDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(extDir, "tykctl-demo")
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	runner := NewRunner(t.TempDir())
+
+	systemPath := writeExtension(ExtensionsDir(ScopeSystem))
+	if found := runner.findExtension("demo"); found != systemPath {
+		t.Fatalf("Expected system extension %s, got %s", systemPath, found)
+	}
+
+	userPath := writeExtension(ExtensionsDir(ScopeUser))
+	if found := runner.findExtension("demo"); found != userPath {
+		t.Fatalf("Expected user extension %s to take precedence, got %s", userPath, found)
+	}
+
+	extensions, err := runner.ListAvailableExtensions()
+	if err != nil {
+		t.Fatalf("ListAvailableExtensions failed: %v", err)
+	}
+	if len(extensions) != 1 || extensions[0] != "demo" {
+		t.Fatalf("Expected [demo], got %v", extensions)
+	}
+}
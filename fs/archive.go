@@ -0,0 +1,585 @@
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// ArchiveFormat is a supported archive format
+type ArchiveFormat int
+
+const (
+	// FormatTarGz is a gzip-compressed tar archive
+	FormatTarGz ArchiveFormat = iota
+	// FormatZip is a zip archive
+	FormatZip
+)
+
+// DefaultMaxArchiveSize caps the bytes extracted from an archive unless
+// ArchiveOptions.MaxSize says otherwise, guarding against archive bombs
+const DefaultMaxArchiveSize = 1 << 30
+
+// DefaultMaxArchiveEntries caps the entries extracted from an archive
+// unless ArchiveOptions.MaxEntries says otherwise
+const DefaultMaxArchiveEntries = 100000
+
+var (
+	// ErrUnsafeArchivePath is returned when an archive entry, or the target
+	// of a symlink in it, would land outside the destination ("zip slip")
+	ErrUnsafeArchivePath = errors.New("archive entry escapes destination")
+	// ErrArchiveTooLarge is returned when extraction exceeds a size or
+	// entry limit
+	ErrArchiveTooLarge = errors.New("archive exceeds limit")
+)
+
+// ArchiveOptions configures packing and unpacking. Globs are matched like
+// those of CopyOptions, against slash-separated paths inside the archive
+type ArchiveOptions struct {
+	// Include selects the files to pack or unpack; empty selects all
+	Include []string
+	// Exclude skips matching files and directories
+	Exclude []string
+	// MaxSize caps the total bytes extracted; zero means
+	// DefaultMaxArchiveSize and a negative value means no limit
+	MaxSize int64
+	// MaxFileSize caps the bytes extracted for any single file; zero means
+	// no limit beyond MaxSize
+	MaxFileSize int64
+	// MaxEntries caps the entries extracted; zero means
+	// DefaultMaxArchiveEntries and a negative value means no limit
+	MaxEntries int
+	// Overwrite replaces existing files when extracting instead of failing
+	Overwrite bool
+	// Progress is called with the number of file bytes packed or unpacked
+	// as work proceeds, matching the update function of
+	// progress.Bar.WithContext
+	Progress func(n int64)
+}
+
+// ArchiveFormatFromName detects the format of an archive from its file
+// name: .tar.gz, .tgz or .zip
+func ArchiveFormatFromName(name string) (ArchiveFormat, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return FormatTarGz, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return FormatZip, nil
+	}
+	return 0, fmt.Errorf("unsupported archive format: %s", name)
+}
+
+// Pack archives the directory src into the file archive, picking the
+// format from its name. A partially written archive is removed on failure
+func (fs *FS) Pack(ctx context.Context, src, archive string, options ArchiveOptions) (err error) {
+	format, err := ArchiveFormatFromName(archive)
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(ctx, filepath.Dir(archive), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(archive), err)
+	}
+
+	file, err := fs.fs.OpenFile(archive, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", archive, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write %s: %w", archive, closeErr)
+		}
+		if err != nil {
+			fs.fs.Remove(archive)
+		}
+	}()
+
+	// Never pack the archive into itself
+	options.Exclude = append(append([]string(nil), options.Exclude...), archiveSelfPattern(src, archive)...)
+
+	if format == FormatZip {
+		return fs.CreateZip(ctx, src, file, options)
+	}
+	return fs.CreateTarGz(ctx, src, file, options)
+}
+
+// Unpack extracts the file archive into dst, picking the format from its
+// name
+func (fs *FS) Unpack(ctx context.Context, archive, dst string, options ArchiveOptions) error {
+	format, err := ArchiveFormatFromName(archive)
+	if err != nil {
+		return err
+	}
+
+	file, err := fs.fs.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", archive, err)
+	}
+	defer file.Close()
+
+	if format == FormatZip {
+		info, err := file.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", archive, err)
+		}
+		return fs.ExtractZip(ctx, file, info.Size(), dst, options)
+	}
+	return fs.ExtractTarGz(ctx, file, dst, options)
+}
+
+// CreateTarGz writes the tree at src to w as a gzip-compressed tar archive
+func (fs *FS) CreateTarGz(ctx context.Context, src string, w io.Writer, options ArchiveOptions) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err := fs.walkArchive(ctx, src, options, func(entry archiveEntry) error {
+		header, err := tar.FileInfoHeader(entry.info, entry.link)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", entry.name, err)
+		}
+		header.Name = entry.name
+		if entry.info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to add %s: %w", entry.name, err)
+		}
+		if !entry.info.Mode().IsRegular() {
+			return nil
+		}
+		return fs.packFile(ctx, tw, entry, options.Progress)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// CreateZip writes the tree at src to w as a zip archive
+func (fs *FS) CreateZip(ctx context.Context, src string, w io.Writer, options ArchiveOptions) error {
+	zw := zip.NewWriter(w)
+
+	err := fs.walkArchive(ctx, src, options, func(entry archiveEntry) error {
+		header, err := zip.FileInfoHeader(entry.info)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", entry.name, err)
+		}
+		header.Name = entry.name
+		if entry.info.IsDir() {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+
+		out, err := zw.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", entry.name, err)
+		}
+		switch {
+		case entry.link != "":
+			// Zip stores a symlink as a file holding its target
+			_, err = io.WriteString(out, entry.link)
+			return err
+		case entry.info.Mode().IsRegular():
+			return fs.packFile(ctx, out, entry, options.Progress)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// ExtractTarGz extracts a gzip-compressed tar archive into dst. Entries
+// that would land outside dst, absolute paths and symlinks pointing out
+// of dst are refused with ErrUnsafeArchivePath
+func (fs *FS) ExtractTarGz(ctx context.Context, r io.Reader, dst string, options ArchiveOptions) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	extractor, err := fs.newExtractor(dst, options)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		mode := header.FileInfo().Mode()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = extractor.dir(ctx, header.Name, mode)
+		case tar.TypeReg:
+			err = extractor.file(ctx, header.Name, tr, mode, header.ModTime)
+		case tar.TypeSymlink:
+			err = extractor.symlink(ctx, header.Name, header.Linkname)
+		case tar.TypeLink:
+			err = fmt.Errorf("failed to extract %s: hard links are not supported", header.Name)
+		default:
+			// Devices, FIFOs and metadata-only entries are skipped
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ExtractZip extracts a zip archive of the given size into dst, with the
+// same protections as ExtractTarGz
+func (fs *FS) ExtractZip(ctx context.Context, r io.ReaderAt, size int64, dst string, options ArchiveOptions) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	extractor, err := fs.newExtractor(dst, options)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range zr.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := extractor.zipEntry(ctx, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveEntry is a file, directory or symlink to pack
+type archiveEntry struct {
+	path string
+	name string
+	info os.FileInfo
+	link string
+}
+
+// walkArchive calls fn for each entry under src selected by options, with
+// slash-separated names relative to src
+func (fs *FS) walkArchive(ctx context.Context, src string, options ArchiveOptions, fn func(archiveEntry) error) error {
+	info, err := fs.Stat(ctx, src)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", src)
+	}
+
+	return afero.Walk(fs.fs, src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if path == src {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if matchAnyGlob(options.Exclude, name) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		entry := archiveEntry{path: path, name: name, info: info}
+		switch {
+		case info.IsDir():
+			// Directories are kept even when empty, unless filtering by
+			// include, where only the files chosen bring theirs along
+			if len(options.Include) > 0 {
+				return nil
+			}
+		case len(options.Include) > 0 && !matchAnyGlob(options.Include, name):
+			return nil
+		case info.Mode()&os.ModeSymlink != 0:
+			linker, ok := fs.fs.(afero.LinkReader)
+			if !ok {
+				return nil
+			}
+			if entry.link, err = linker.ReadlinkIfPossible(path); err != nil {
+				return fmt.Errorf("failed to read link %s: %w", path, err)
+			}
+		}
+		return fn(entry)
+	})
+}
+
+// packFile copies the contents of a file into an archive
+func (fs *FS) packFile(ctx context.Context, w io.Writer, entry archiveEntry, progress func(int64)) error {
+	in, err := fs.fs.Open(entry.path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", entry.path, err)
+	}
+	defer in.Close()
+
+	if err := copyContents(ctx, w, in, progress); err != nil {
+		return fmt.Errorf("failed to add %s: %w", entry.name, err)
+	}
+	return nil
+}
+
+// archiveSelfPattern returns an exclude glob for archive when it lies
+// inside src
+func archiveSelfPattern(src, archive string) []string {
+	rel, err := filepath.Rel(src, archive)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	return []string{filepath.ToSlash(rel)}
+}
+
+// extractor writes archive entries below a root, enforcing the limits of
+// its options
+type extractor struct {
+	fs      *FS
+	root    *RootFS
+	options ArchiveOptions
+	written int64
+	entries int
+}
+
+func (fs *FS) newExtractor(dst string, options ArchiveOptions) (*extractor, error) {
+	if err := fs.fs.MkdirAll(dst, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	root, err := fs.Root(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.MaxSize == 0 {
+		options.MaxSize = DefaultMaxArchiveSize
+	}
+	if options.MaxEntries == 0 {
+		options.MaxEntries = DefaultMaxArchiveEntries
+	}
+	return &extractor{fs: fs, root: root, options: options}, nil
+}
+
+// zipEntry extracts one entry of a zip archive
+func (e *extractor) zipEntry(ctx context.Context, file *zip.File) error {
+	mode := file.Mode()
+	switch {
+	case mode.IsDir():
+		return e.dir(ctx, file.Name, mode)
+	case mode&os.ModeSymlink != 0:
+		in, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+		}
+		defer in.Close()
+
+		target, err := io.ReadAll(io.LimitReader(in, 4096))
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+		}
+		return e.symlink(ctx, file.Name, string(target))
+	case mode.IsRegular():
+		in, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+		}
+		defer in.Close()
+		return e.file(ctx, file.Name, in, mode, file.Modified)
+	}
+	return nil
+}
+
+// entry validates the name of an entry, counts it, and reports whether
+// the filters select it
+func (e *extractor) entry(name string, dir bool) (string, bool, error) {
+	clean, err := archivePath(name)
+	if err != nil {
+		return "", false, err
+	}
+
+	e.entries++
+	if e.options.MaxEntries > 0 && e.entries > e.options.MaxEntries {
+		return "", false, fmt.Errorf("more than %d entries: %w", e.options.MaxEntries, ErrArchiveTooLarge)
+	}
+
+	for dir := clean; dir != "."; dir = path.Dir(dir) {
+		if matchAnyGlob(e.options.Exclude, dir) {
+			return clean, false, nil
+		}
+	}
+	if !dir && len(e.options.Include) > 0 && !matchAnyGlob(e.options.Include, clean) {
+		return clean, false, nil
+	}
+	return clean, clean != ".", nil
+}
+
+func (e *extractor) dir(ctx context.Context, name string, mode os.FileMode) error {
+	clean, ok, err := e.entry(name, true)
+	if err != nil || !ok || len(e.options.Include) > 0 {
+		// With include filters, directories come from the files chosen
+		return err
+	}
+	return e.root.MkdirAll(ctx, filepath.FromSlash(clean), mode.Perm()|0700)
+}
+
+func (e *extractor) file(ctx context.Context, name string, r io.Reader, mode os.FileMode, modTime time.Time) error {
+	clean, ok, err := e.entry(name, false)
+	if err != nil || !ok {
+		return err
+	}
+
+	target, err := e.root.Path(filepath.FromSlash(clean))
+	if err != nil {
+		return err
+	}
+	if err := e.fs.fs.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !e.options.Overwrite {
+		flags |= os.O_EXCL
+	}
+	out, err := e.fs.fs.OpenFile(target, flags, mode.Perm())
+	if os.IsExist(err) {
+		return fmt.Errorf("failed to extract %s: %w", name, ErrDestinationExists)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+
+	if err := copyContents(ctx, out, e.limit(r, name), e.options.Progress); err != nil {
+		out.Close()
+		e.fs.fs.Remove(target)
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	if err := out.Close(); err != nil {
+		e.fs.fs.Remove(target)
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+
+	// OpenFile applies the umask; setuid and similar bits are dropped
+	if err := e.fs.fs.Chmod(target, mode.Perm()); err != nil {
+		return fmt.Errorf("failed to set mode of %s: %w", target, err)
+	}
+	if !modTime.IsZero() {
+		e.fs.fs.Chtimes(target, modTime, modTime)
+	}
+	return nil
+}
+
+func (e *extractor) symlink(ctx context.Context, name, target string) error {
+	clean, ok, err := e.entry(name, false)
+	if err != nil || !ok {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// The target must stay inside the destination, judged from the
+	// directory holding the link
+	if filepath.IsAbs(target) || path.IsAbs(target) {
+		return fmt.Errorf("%s -> %s: %w", name, target, ErrUnsafeArchivePath)
+	}
+	if _, err := archivePath(path.Join(path.Dir(clean), target)); err != nil {
+		return fmt.Errorf("%s -> %s: %w", name, target, ErrUnsafeArchivePath)
+	}
+
+	linkPath, err := e.root.resolve(filepath.FromSlash(clean), false)
+	if err != nil {
+		return err
+	}
+	if err := e.fs.fs.MkdirAll(filepath.Dir(linkPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(linkPath), err)
+	}
+	return e.fs.createSymlink(filepath.FromSlash(target), linkPath, e.options.Overwrite)
+}
+
+// limit wraps r to fail once the file or archive limits are exceeded
+func (e *extractor) limit(r io.Reader, name string) io.Reader {
+	return &limitedReader{extractor: e, r: r, name: name}
+}
+
+// limitedReader counts bytes as they are extracted, since the sizes
+// recorded in archive headers cannot be trusted
+type limitedReader struct {
+	extractor *extractor
+	r         io.Reader
+	name      string
+	read      int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	l.extractor.written += int64(n)
+
+	options := l.extractor.options
+	if options.MaxFileSize > 0 && l.read > options.MaxFileSize {
+		return n, fmt.Errorf("%s is larger than %d bytes: %w", l.name, options.MaxFileSize, ErrArchiveTooLarge)
+	}
+	if options.MaxSize > 0 && l.extractor.written > options.MaxSize {
+		return n, fmt.Errorf("more than %d bytes: %w", options.MaxSize, ErrArchiveTooLarge)
+	}
+	return n, err
+}
+
+// archivePath validates an entry name and returns it cleaned. Absolute
+// names, drive letters and names climbing out with ".." are refused
+func archivePath(name string) (string, error) {
+	slashed := strings.ReplaceAll(name, "\\", "/")
+	if path.IsAbs(slashed) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" ||
+		(len(slashed) > 1 && slashed[1] == ':') {
+		return "", fmt.Errorf("%s: %w", name, ErrUnsafeArchivePath)
+	}
+
+	clean := path.Clean(slashed)
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%s: %w", name, ErrUnsafeArchivePath)
+	}
+	return clean, nil
+}
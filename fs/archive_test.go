@@ -0,0 +1,189 @@
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFS_PackUnpack(t *testing.T) {
+	for _, name := range []string{"/out/ext.tar.gz", "/out/ext.zip"} {
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			fs := NewMem()
+			ctx := context.Background()
+
+			fs.WriteFile(ctx, "/src/manifest.yaml", []byte("name: ext"), 0644)
+			fs.WriteFile(ctx, "/src/bin/ext", []byte("binary"), 0755)
+			fs.WriteFile(ctx, "/src/.git/config", []byte("git"), 0644)
+			fs.MkdirAll(ctx, "/src/empty", 0755)
+
+			var packed, unpacked int64
+			err := fs.Pack(ctx, "/src", name, ArchiveOptions{
+				Exclude:  []string{".git"},
+				Progress: func(n int64) { packed += n },
+			})
+			if err != nil {
+				t.Fatalf("Pack failed: %v", err)
+			}
+			if packed != 15 {
+				t.Errorf("Expected 15 bytes packed, got %d", packed)
+			}
+
+			err = fs.Unpack(ctx, name, "/dst", ArchiveOptions{
+				Progress: func(n int64) { unpacked += n },
+			})
+			if err != nil {
+				t.Fatalf("Unpack failed: %v", err)
+			}
+			if unpacked != packed {
+				t.Errorf("Expected %d bytes unpacked, got %d", packed, unpacked)
+			}
+
+			data, err := fs.ReadFile(ctx, "/dst/bin/ext")
+			if err != nil || string(data) != "binary" {
+				t.Fatalf("Expected unpacked binary, got %q (%v)", data, err)
+			}
+			if info, _ := fs.Stat(ctx, "/dst/bin/ext"); info.Mode().Perm() != 0755 {
+				t.Errorf("Expected mode 0755, got %v", info.Mode().Perm())
+			}
+			if exists, _ := fs.Exists(ctx, "/dst/empty"); !exists {
+				t.Error("Expected empty directory to be kept")
+			}
+			if exists, _ := fs.Exists(ctx, "/dst/.git"); exists {
+				t.Error("Expected excluded directory to be left out")
+			}
+
+			err = fs.Unpack(ctx, name, "/dst", ArchiveOptions{})
+			if !errors.Is(err, ErrDestinationExists) {
+				t.Errorf("Expected ErrDestinationExists, got %v", err)
+			}
+			if err := fs.Unpack(ctx, name, "/dst", ArchiveOptions{Overwrite: true}); err != nil {
+				t.Errorf("Expected overwrite to succeed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFS_ExtractTarGz_Unsafe(t *testing.T) {
+	tests := map[string]tar.Header{
+		"parent":          {Name: "../evil", Typeflag: tar.TypeReg},
+		"nested parent":   {Name: "a/../../evil", Typeflag: tar.TypeReg},
+		"absolute":        {Name: "/etc/evil", Typeflag: tar.TypeReg},
+		"symlink outside": {Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../../etc"},
+		"symlink abs":     {Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc"},
+	}
+
+	for name, header := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gz)
+			header.Mode = 0644
+			tw.WriteHeader(&header)
+			tw.Close()
+			gz.Close()
+
+			fs := NewMem()
+			err := fs.ExtractTarGz(context.Background(), &buf, "/dst", ArchiveOptions{})
+			if !errors.Is(err, ErrUnsafeArchivePath) {
+				t.Errorf("Expected ErrUnsafeArchivePath, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFS_ExtractZip_Unsafe(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("../../evil.txt")
+	w.Write([]byte("evil"))
+	zw.Close()
+
+	fs := NewMem()
+	err := fs.ExtractZip(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()), "/dst/app", ArchiveOptions{})
+	if !errors.Is(err, ErrUnsafeArchivePath) {
+		t.Errorf("Expected ErrUnsafeArchivePath, got %v", err)
+	}
+	if exists, _ := fs.Exists(context.Background(), "/evil.txt"); exists {
+		t.Error("Expected nothing to be written outside the destination")
+	}
+}
+
+func TestFS_ExtractTarGz_Limits(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+	fs.WriteFile(ctx, "/src/a", bytes.Repeat([]byte("a"), 1000), 0644)
+	fs.WriteFile(ctx, "/src/b", bytes.Repeat([]byte("b"), 1000), 0644)
+
+	var buf bytes.Buffer
+	if err := fs.CreateTarGz(ctx, "/src", &buf, ArchiveOptions{}); err != nil {
+		t.Fatalf("CreateTarGz failed: %v", err)
+	}
+	archive := buf.Bytes()
+
+	for name, options := range map[string]ArchiveOptions{
+		"total":   {MaxSize: 1500},
+		"file":    {MaxFileSize: 500},
+		"entries": {MaxEntries: 1},
+	} {
+		err := fs.ExtractTarGz(ctx, bytes.NewReader(archive), "/dst-"+name, options)
+		if !errors.Is(err, ErrArchiveTooLarge) {
+			t.Errorf("%s: expected ErrArchiveTooLarge, got %v", name, err)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := fs.ExtractTarGz(cancelled, bytes.NewReader(archive), "/dst-cancel", ArchiveOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFS_PackUnpack_Symlinks(t *testing.T) {
+	fs := New()
+	ctx := context.Background()
+	base := t.TempDir()
+	src := filepath.Join(base, "src")
+
+	fs.WriteFile(ctx, filepath.Join(src, "bin", "ext"), []byte("binary"), 0755)
+	if err := os.Symlink(filepath.Join("bin", "ext"), filepath.Join(src, "ext")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	for _, name := range []string{"ext.tar.gz", "ext.zip"} {
+		archive := filepath.Join(base, name)
+		if err := fs.Pack(ctx, src, archive, ArchiveOptions{}); err != nil {
+			t.Fatalf("Pack %s failed: %v", name, err)
+		}
+
+		dst := filepath.Join(base, "dst-"+name)
+		if err := fs.Unpack(ctx, archive, dst, ArchiveOptions{}); err != nil {
+			t.Fatalf("Unpack %s failed: %v", name, err)
+		}
+		target, err := os.Readlink(filepath.Join(dst, "ext"))
+		if err != nil || target != filepath.Join("bin", "ext") {
+			t.Errorf("%s: expected link to bin/ext, got %q (%v)", name, target, err)
+		}
+	}
+}
+
+func TestArchiveFormatFromName(t *testing.T) {
+	for name, want := range map[string]ArchiveFormat{
+		"ext.tar.gz": FormatTarGz,
+		"ext.TGZ":    FormatTarGz,
+		"ext.zip":    FormatZip,
+	} {
+		if got, err := ArchiveFormatFromName(name); err != nil || got != want {
+			t.Errorf("ArchiveFormatFromName(%s) = %v, %v", name, got, err)
+		}
+	}
+	if _, err := ArchiveFormatFromName("ext.rar"); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,409 @@
+package fs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/afero"
+)
+
+// CleanupReport describes what Cleanup did with an owner's paths
+type CleanupReport struct {
+	// Owner is the owner that was cleaned up
+	Owner string `json:"owner"`
+	// Removed lists paths that were deleted
+	Removed []string `json:"removed"`
+	// Shared lists paths left in place because another owner also tracks
+	// them or something beneath them
+	Shared []string `json:"shared"`
+	// Missing lists tracked paths that no longer existed
+	Missing []string `json:"missing"`
+}
+
+// registryFile is the on-disk format of the ownership registry
+type registryFile struct {
+	Owners map[string][]string `json:"owners"`
+}
+
+// Registry records the files and directories created on behalf of each
+// owner, so an owner can be removed completely and anything left behind
+// by untracked code can be found. Owners are free-form strings; by
+// convention they name the subsystem that created the paths, such as
+// "extension/<name>", "plugin/<name>" or "cache"
+type Registry struct {
+	fs   *FS
+	path string
+}
+
+// registryMu serializes access to registry files across Registry values
+var registryMu sync.Mutex
+
+// DefaultRegistryPath returns the location of the ownership registry state file
+func DefaultRegistryPath() string {
+	return filepath.Join(xdg.StateHome, "tykctl", "owned.json")
+}
+
+// NewRegistry creates an ownership registry stored at path on fsys, using
+// DefaultRegistryPath when path is empty
+func NewRegistry(fsys *FS, path string) *Registry {
+	if path == "" {
+		path = DefaultRegistryPath()
+	}
+	return &Registry{fs: fsys, path: path}
+}
+
+// Path returns the location of the state file
+func (r *Registry) Path() string {
+	return r.path
+}
+
+// Track records that owner created path. Tracking a directory covers
+// everything beneath it
+func (r *Registry) Track(ctx context.Context, owner, path string) error {
+	if owner == "" {
+		return fmt.Errorf("owner must not be empty")
+	}
+	path, err := normalizePath(path)
+	if err != nil {
+		return err
+	}
+
+	return r.update(ctx, func(file *registryFile) error {
+		for _, existing := range file.Owners[owner] {
+			if existing == path {
+				return nil
+			}
+		}
+		file.Owners[owner] = append(file.Owners[owner], path)
+		sort.Strings(file.Owners[owner])
+		return nil
+	})
+}
+
+// Untrack forgets that owner created path, without removing it
+func (r *Registry) Untrack(ctx context.Context, owner, path string) error {
+	path, err := normalizePath(path)
+	if err != nil {
+		return err
+	}
+
+	return r.update(ctx, func(file *registryFile) error {
+		file.Owners[owner] = removeString(file.Owners[owner], path)
+		if len(file.Owners[owner]) == 0 {
+			delete(file.Owners, owner)
+		}
+		return nil
+	})
+}
+
+// MkdirAll creates a directory and tracks it for owner
+func (r *Registry) MkdirAll(ctx context.Context, owner, path string, perm os.FileMode) error {
+	if err := r.fs.MkdirAll(ctx, path, perm); err != nil {
+		return err
+	}
+	return r.Track(ctx, owner, path)
+}
+
+// WriteFile writes a file and tracks it for owner
+func (r *Registry) WriteFile(ctx context.Context, owner, path string, data []byte, perm os.FileMode) error {
+	if err := r.fs.WriteFile(ctx, path, data, perm); err != nil {
+		return err
+	}
+	return r.Track(ctx, owner, path)
+}
+
+// Owners returns the sorted names of every owner with tracked paths
+func (r *Registry) Owners(ctx context.Context) ([]string, error) {
+	file, err := r.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make([]string, 0, len(file.Owners))
+	for owner := range file.Owners {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	return owners, nil
+}
+
+// Owned returns the sorted paths tracked for owner
+func (r *Registry) Owned(ctx context.Context, owner string) ([]string, error) {
+	file, err := r.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), file.Owners[owner]...), nil
+}
+
+// Cleanup removes every path tracked for owner and forgets the owner.
+// Paths that another owner also depends on are untracked but left in place
+func (r *Registry) Cleanup(ctx context.Context, owner string) (*CleanupReport, error) {
+	report := &CleanupReport{
+		Owner:   owner,
+		Removed: []string{},
+		Shared:  []string{},
+		Missing: []string{},
+	}
+
+	err := r.update(ctx, func(file *registryFile) error {
+		paths := append([]string(nil), file.Owners[owner]...)
+		// Remove the deepest paths first so directories are emptied last
+		sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+
+		for _, path := range paths {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if sharedWithOthers(file, owner, path) {
+				report.Shared = append(report.Shared, path)
+			} else if exists, err := afero.Exists(r.fs.fs, path); err != nil {
+				return fmt.Errorf("failed to check %s: %w", path, err)
+			} else if !exists {
+				report.Missing = append(report.Missing, path)
+			} else {
+				if err := r.fs.fs.RemoveAll(path); err != nil {
+					return fmt.Errorf("failed to remove %s: %w", path, err)
+				}
+				report.Removed = append(report.Removed, path)
+			}
+
+			file.Owners[owner] = removeString(file.Owners[owner], path)
+		}
+
+		delete(file.Owners, owner)
+		return nil
+	})
+
+	sort.Strings(report.Removed)
+	sort.Strings(report.Shared)
+	sort.Strings(report.Missing)
+	return report, err
+}
+
+// Orphans scans roots for files and directories that no owner tracks.
+// Directories that only contain tracked paths are descended into; any
+// other untracked entry is reported once, without listing its contents
+func (r *Registry) Orphans(ctx context.Context, roots ...string) ([]string, error) {
+	file, err := r.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracked []string
+	for _, paths := range file.Owners {
+		tracked = append(tracked, paths...)
+	}
+	tracked = append(tracked, r.path)
+
+	var orphans []string
+	for _, root := range roots {
+		root, err := normalizePath(root)
+		if err != nil {
+			return nil, err
+		}
+		if exists, err := afero.DirExists(r.fs.fs, root); err != nil || !exists {
+			continue
+		}
+
+		err = afero.Walk(r.fs.fs, root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if path == root {
+				return nil
+			}
+
+			switch {
+			case coveredBy(path, tracked):
+			case info.IsDir() && containsTracked(path, tracked):
+				return nil
+			default:
+				orphans = append(orphans, path)
+			}
+
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	sort.Strings(orphans)
+	return orphans, nil
+}
+
+// read loads the registry file, returning an empty registry if it does not exist
+func (r *Registry) read(ctx context.Context) (*registryFile, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return r.load(ctx)
+}
+
+// update applies fn to the registry file and saves the result, even when
+// fn fails part way, so completed removals are not tracked any more
+func (r *Registry) update(ctx context.Context, fn func(*registryFile) error) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	file, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	fnErr := fn(file)
+	if err := r.save(file); err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// load reads the registry file without locking
+func (r *Registry) load(ctx context.Context) (*registryFile, error) {
+	file := &registryFile{Owners: make(map[string][]string)}
+
+	data, err := r.fs.ReadFile(ctx, r.path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ownership registry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse ownership registry: %w", err)
+	}
+	if file.Owners == nil {
+		file.Owners = make(map[string][]string)
+	}
+	return file, nil
+}
+
+// save writes the registry file atomically without locking
+func (r *Registry) save(file *registryFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ownership registry: %w", err)
+	}
+
+	if err := r.fs.fs.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create ownership registry directory: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := afero.WriteFile(r.fs.fs, tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write ownership registry: %w", err)
+	}
+	if err := r.fs.fs.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to write ownership registry: %w", err)
+	}
+	return nil
+}
+
+// normalizePath returns a clean absolute path
+func normalizePath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("path must not be empty")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	return abs, nil
+}
+
+// isWithin reports whether path is dir or lies beneath it
+func isWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// coveredBy reports whether path is tracked directly or through a tracked parent
+func coveredBy(path string, tracked []string) bool {
+	for _, t := range tracked {
+		if isWithin(path, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTracked reports whether any tracked path lies beneath dir
+func containsTracked(dir string, tracked []string) bool {
+	for _, t := range tracked {
+		if isWithin(t, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedWithOthers reports whether an owner other than owner tracks path,
+// a parent of it or something beneath it
+func sharedWithOthers(file *registryFile, owner, path string) bool {
+	for other, paths := range file.Owners {
+		if other == owner {
+			continue
+		}
+		for _, p := range paths {
+			if isWithin(p, path) || isWithin(path, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeString returns values without s
+func removeString(values []string, s string) []string {
+	result := values[:0]
+	for _, v := range values {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Global ownership registry instance
+var defaultRegistry *Registry
+var registryOnce sync.Once
+
+// DefaultRegistry returns the ownership registry for the real filesystem,
+// stored at DefaultRegistryPath
+func DefaultRegistry() *Registry {
+	registryOnce.Do(func() {
+		defaultRegistry = NewRegistry(New(), "")
+	})
+	return defaultRegistry
+}
+
+// TrackOwned records in the default registry that owner created path
+func TrackOwned(ctx context.Context, owner, path string) error {
+	return DefaultRegistry().Track(ctx, owner, path)
+}
+
+// CleanupOwner removes every path the default registry tracks for owner,
+// such as when an extension or plugin is uninstalled
+func CleanupOwner(ctx context.Context, owner string) (*CleanupReport, error) {
+	return DefaultRegistry().Cleanup(ctx, owner)
+}
+
+// ScanOrphans lists untracked files and directories under roots using the
+// default registry
+func ScanOrphans(ctx context.Context, roots ...string) ([]string, error) {
+	return DefaultRegistry().Orphans(ctx, roots...)
+}
@@ -0,0 +1,124 @@
+package fs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry_Cleanup(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+	registry := NewRegistry(fs, "/state/owned.json")
+
+	if err := registry.MkdirAll(ctx, "extension/foo", "/data/extensions/foo", 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := registry.WriteFile(ctx, "extension/foo", "/data/extensions/foo/bin", []byte("binary"), 0755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := registry.WriteFile(ctx, "extension/foo", "/cache/foo.json", []byte("{}"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := registry.MkdirAll(ctx, "extension/foo", "/data/shared", 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := registry.WriteFile(ctx, "plugin/bar", "/data/shared/bar.so", []byte("plugin"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := registry.Track(ctx, "extension/foo", "/data/gone"); err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+
+	owners, err := registry.Owners(ctx)
+	if err != nil {
+		t.Fatalf("Owners failed: %v", err)
+	}
+	if !reflect.DeepEqual(owners, []string{"extension/foo", "plugin/bar"}) {
+		t.Errorf("Unexpected owners: %v", owners)
+	}
+
+	report, err := registry.Cleanup(ctx, "extension/foo")
+	if err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+
+	expectedRemoved := []string{"/cache/foo.json", "/data/extensions/foo", "/data/extensions/foo/bin"}
+	if !reflect.DeepEqual(report.Removed, expectedRemoved) {
+		t.Errorf("Expected removed %v, got %v", expectedRemoved, report.Removed)
+	}
+	if !reflect.DeepEqual(report.Shared, []string{"/data/shared"}) {
+		t.Errorf("Expected /data/shared to be shared, got %v", report.Shared)
+	}
+	if !reflect.DeepEqual(report.Missing, []string{"/data/gone"}) {
+		t.Errorf("Expected /data/gone to be missing, got %v", report.Missing)
+	}
+
+	for path, want := range map[string]bool{
+		"/data/extensions/foo": false,
+		"/cache/foo.json":      false,
+		"/data/shared/bar.so":  true,
+	} {
+		if exists, _ := fs.Exists(ctx, path); exists != want {
+			t.Errorf("Expected %s exists=%v, got %v", path, want, exists)
+		}
+	}
+
+	owned, err := registry.Owned(ctx, "extension/foo")
+	if err != nil {
+		t.Fatalf("Owned failed: %v", err)
+	}
+	if len(owned) != 0 {
+		t.Errorf("Expected no paths tracked after cleanup, got %v", owned)
+	}
+
+	// The registry survives being reopened
+	owned, err = NewRegistry(fs, "/state/owned.json").Owned(ctx, "plugin/bar")
+	if err != nil {
+		t.Fatalf("Owned failed: %v", err)
+	}
+	if !reflect.DeepEqual(owned, []string{"/data/shared/bar.so"}) {
+		t.Errorf("Unexpected paths for plugin/bar: %v", owned)
+	}
+}
+
+func TestRegistry_Orphans(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+	registry := NewRegistry(fs, "/data/owned.json")
+
+	if err := registry.MkdirAll(ctx, "extension/foo", "/data/extensions/foo", 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := fs.WriteFile(ctx, "/data/extensions/foo/bin", []byte("binary"), 0755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := fs.WriteFile(ctx, "/data/extensions/old/bin", []byte("stale"), 0755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := fs.WriteFile(ctx, "/data/leftover.log", []byte("log"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	orphans, err := registry.Orphans(ctx, "/data", "/missing")
+	if err != nil {
+		t.Fatalf("Orphans failed: %v", err)
+	}
+
+	expected := []string{"/data/extensions/old", "/data/leftover.log"}
+	if !reflect.DeepEqual(orphans, expected) {
+		t.Errorf("Expected orphans %v, got %v", expected, orphans)
+	}
+}
+
+func TestRegistry_TrackValidation(t *testing.T) {
+	registry := NewRegistry(NewMem(), "/state/owned.json")
+	ctx := context.Background()
+
+	if err := registry.Track(ctx, "", "/data/file"); err == nil {
+		t.Error("Expected an error for an empty owner")
+	}
+	if err := registry.Track(ctx, "cache", ""); err == nil {
+		t.Error("Expected an error for an empty path")
+	}
+}
@@ -0,0 +1,553 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// SymlinkPolicy decides how CopyDir and MoveDir treat symbolic links
+type SymlinkPolicy int
+
+const (
+	// SymlinkPreserve recreates links as links with the same target
+	SymlinkPreserve SymlinkPolicy = iota
+	// SymlinkFollow copies what links point to
+	SymlinkFollow
+	// SymlinkSkip leaves links out of the copy
+	SymlinkSkip
+)
+
+// ErrDestinationExists is returned when a copy would overwrite a file and
+// CopyOptions.Overwrite is not set
+var ErrDestinationExists = errors.New("destination already exists")
+
+// CopyOptions configures CopyDir and MoveDir. Globs use forward slashes
+// and are matched against paths relative to the source, with "**"
+// matching any number of directories. Globs without a slash match the
+// name at any depth
+type CopyOptions struct {
+	// Include selects the files to copy; empty copies every file
+	Include []string
+	// Exclude skips matching files and directories, even when included
+	Exclude []string
+	// Symlinks decides how symbolic links are handled
+	Symlinks SymlinkPolicy
+	// PreserveMode keeps permission bits; otherwise files are created
+	// 0644 and directories 0755
+	PreserveMode bool
+	// PreserveTimes keeps modification times
+	PreserveTimes bool
+	// PreserveOwner keeps the owning user and group, which usually needs
+	// elevated privileges
+	PreserveOwner bool
+	// PreserveXattrs keeps extended attributes, where supported
+	PreserveXattrs bool
+	// Overwrite replaces existing files instead of failing
+	Overwrite bool
+	// Progress is called with the number of bytes written as the copy
+	// proceeds, matching the update function of progress.Bar.WithContext.
+	// Use CopySize to get the total up front
+	Progress func(written int64)
+}
+
+// copyBufferSize is the chunk size between cancellation checks and
+// progress updates
+const copyBufferSize = 32 * 1024
+
+// CopyFile copies a single file, keeping its permission bits
+func (fs *FS) CopyFile(ctx context.Context, src, dst string) error {
+	info, err := fs.Stat(ctx, src)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", src)
+	}
+	if err := fs.MkdirAll(ctx, filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+	}
+
+	options := CopyOptions{PreserveMode: true, Overwrite: true}
+	return fs.copyFile(ctx, src, dst, info, &options, &CopyReport{})
+}
+
+// MoveFile moves a single file, copying it when a rename is not possible,
+// such as across devices
+func (fs *FS) MoveFile(ctx context.Context, src, dst string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(ctx, filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+	}
+	if err := fs.fs.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	if err := fs.CopyFile(ctx, src, dst); err != nil {
+		return err
+	}
+	return fs.fs.Remove(src)
+}
+
+// CopySize returns the number of bytes CopyDir would copy from src with
+// the given options, for sizing a progress bar
+func (fs *FS) CopySize(ctx context.Context, src string, options CopyOptions) (int64, error) {
+	entries, err := fs.planCopy(ctx, src, options)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		if entry.info.Mode().IsRegular() {
+			total += entry.info.Size()
+		}
+	}
+	return total, nil
+}
+
+// CopyDir copies the tree at src to dst, creating dst if needed. Files
+// are written to a temporary name and renamed into place, so a cancelled
+// copy never leaves a partially written file behind
+func (fs *FS) CopyDir(ctx context.Context, src, dst string, options CopyOptions) error {
+	_, err := fs.CopyDirReport(ctx, src, dst, options)
+	return err
+}
+
+// CopyDirReport is CopyDir returning a report of what was copied and of
+// any metadata that could not be preserved, such as ownership when not
+// running as root
+func (fs *FS) CopyDirReport(ctx context.Context, src, dst string, options CopyOptions) (*CopyReport, error) {
+	entries, err := fs.planCopy(ctx, src, options)
+	if err != nil {
+		return nil, err
+	}
+	if isWithin(filepath.Clean(dst), filepath.Clean(src)) {
+		return nil, fmt.Errorf("cannot copy %s into itself", src)
+	}
+
+	if err := fs.fs.MkdirAll(dst, fs.dirMode(src, &options)); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	report := &CopyReport{Unpreserved: []MetadataIssue{}}
+
+	// Directory metadata is applied last, as writing files into
+	// directories changes their times and may need their permissions
+	var dirs []copyEntry
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		target := filepath.Join(dst, filepath.FromSlash(entry.rel))
+		switch {
+		case entry.info.IsDir():
+			if err := fs.fs.MkdirAll(target, 0700|fs.dirMode(entry.path, &options)); err != nil {
+				return nil, fmt.Errorf("failed to create %s: %w", target, err)
+			}
+			dirs = append(dirs, entry)
+			report.Dirs++
+		case entry.info.Mode()&os.ModeSymlink != 0:
+			if err := fs.copySymlink(entry.path, target, &options); err != nil {
+				return nil, err
+			}
+			fs.preserveMetadata(entry.path, target, entry.info, &options, report)
+			report.Symlinks++
+		default:
+			if err := fs.copyFile(ctx, entry.path, target, entry.info, &options, report); err != nil {
+				return nil, err
+			}
+			report.Files++
+			report.Bytes += entry.info.Size()
+		}
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		target := filepath.Join(dst, filepath.FromSlash(dirs[i].rel))
+		fs.preserveMetadata(dirs[i].path, target, dirs[i].info, &options, report)
+	}
+	return report, nil
+}
+
+// MoveDir moves the tree at src to dst. Without filters, and when dst does
+// not exist, the tree is renamed in one step; otherwise the selected files
+// are copied and then removed from src, along with directories left empty
+func (fs *FS) MoveDir(ctx context.Context, src, dst string, options CopyOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(options.Include) == 0 && len(options.Exclude) == 0 {
+		if _, err := fs.fs.Stat(dst); os.IsNotExist(err) {
+			if err := fs.fs.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+			}
+			if err := fs.fs.Rename(src, dst); err == nil {
+				if options.Progress != nil {
+					if size, err := fs.CopySize(ctx, dst, options); err == nil {
+						options.Progress(size)
+					}
+				}
+				return nil
+			}
+		}
+	}
+
+	entries, err := fs.planCopy(ctx, src, options)
+	if err != nil {
+		return err
+	}
+	if err := fs.CopyDir(ctx, src, dst, options); err != nil {
+		return err
+	}
+
+	// Remove files first, then directories deepest first if now empty
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.info.IsDir() {
+			continue
+		}
+		if err := fs.fs.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", entry.path, err)
+		}
+	}
+	fs.removeEmptyDirs(src)
+	return nil
+}
+
+// copyEntry is a path selected for copying
+type copyEntry struct {
+	path string
+	rel  string
+	info os.FileInfo
+}
+
+// planCopy walks src and returns the entries to copy in walk order, each
+// directory before its contents
+func (fs *FS) planCopy(ctx context.Context, src string, options CopyOptions) ([]copyEntry, error) {
+	info, err := fs.Stat(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", src)
+	}
+	for _, pattern := range append(append([]string(nil), options.Include...), options.Exclude...) {
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+
+	var entries []copyEntry
+	visited := map[string]bool{}
+	if err := fs.walkCopy(ctx, src, "", options, visited, &entries); err != nil {
+		return nil, err
+	}
+
+	// Drop directories that end up with nothing selected in them, so
+	// include filters do not recreate the whole directory skeleton
+	if len(options.Include) == 0 {
+		return entries, nil
+	}
+	used := map[string]bool{}
+	for _, entry := range entries {
+		if entry.info.IsDir() {
+			continue
+		}
+		for dir := path.Dir(entry.rel); dir != "." && !used[dir]; dir = path.Dir(dir) {
+			used[dir] = true
+		}
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		if !entry.info.IsDir() || used[entry.rel] {
+			kept = append(kept, entry)
+		}
+	}
+	return kept, nil
+}
+
+// walkCopy appends the entries under dir, whose path relative to the copy
+// root is rel, following directory links when the policy asks to
+func (fs *FS) walkCopy(ctx context.Context, dir, rel string, options CopyOptions, visited map[string]bool, entries *[]copyEntry) error {
+	// Only followed links can lead back to a directory being walked
+	if _, ok := fs.fs.(*afero.OsFs); ok && options.Symlinks == SymlinkFollow {
+		real, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", dir, err)
+		}
+		if visited[real] {
+			return fmt.Errorf("symlink loop at %s", dir)
+		}
+		visited[real] = true
+		defer delete(visited, real)
+	}
+
+	infos, err := afero.ReadDir(fs.fs, dir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+
+	for _, info := range infos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entryPath := filepath.Join(dir, info.Name())
+		entryRel := path.Join(rel, info.Name())
+		if rel == "" {
+			entryRel = info.Name()
+		}
+		if matchAnyGlob(options.Exclude, entryRel) {
+			continue
+		}
+
+		info, err := fs.lstat(entryPath)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", entryPath, err)
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			switch options.Symlinks {
+			case SymlinkSkip:
+				continue
+			case SymlinkFollow:
+				target, err := fs.fs.Stat(entryPath)
+				if err != nil {
+					return fmt.Errorf("failed to follow %s: %w", entryPath, err)
+				}
+				info = target
+			}
+		}
+
+		if info.IsDir() {
+			*entries = append(*entries, copyEntry{path: entryPath, rel: entryRel, info: info})
+			if err := fs.walkCopy(ctx, entryPath, entryRel, options, visited, entries); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(options.Include) > 0 && !matchAnyGlob(options.Include, entryRel) {
+			continue
+		}
+		*entries = append(*entries, copyEntry{path: entryPath, rel: entryRel, info: info})
+	}
+	return nil
+}
+
+// lstat returns file info without following links when the filesystem
+// supports it
+func (fs *FS) lstat(name string) (os.FileInfo, error) {
+	if lstater, ok := fs.fs.(afero.Lstater); ok {
+		info, _, err := lstater.LstatIfPossible(name)
+		return info, err
+	}
+	return fs.fs.Stat(name)
+}
+
+// copyFile copies one regular file through a temporary file in the
+// destination directory
+func (fs *FS) copyFile(ctx context.Context, src, dst string, info os.FileInfo, options *CopyOptions, report *CopyReport) error {
+	if !options.Overwrite {
+		if _, err := fs.lstat(dst); err == nil {
+			return fmt.Errorf("failed to copy %s: %s: %w", src, dst, ErrDestinationExists)
+		}
+	}
+
+	mode := os.FileMode(0644)
+	if options.PreserveMode {
+		mode = info.Mode().Perm()
+	}
+
+	in, err := fs.fs.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp-copy"
+	out, err := fs.fs.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	if err := copyContents(ctx, out, in, options.Progress); err != nil {
+		out.Close()
+		fs.fs.Remove(tmp)
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		fs.fs.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+
+	// OpenFile applies the umask, so set the mode explicitly
+	if !options.PreserveMode {
+		if err := fs.fs.Chmod(tmp, mode); err != nil {
+			fs.fs.Remove(tmp)
+			return fmt.Errorf("failed to set mode of %s: %w", dst, err)
+		}
+	}
+	fs.preserveMetadata(src, tmp, info, options, report)
+	if err := fs.fs.Rename(tmp, dst); err != nil {
+		fs.fs.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
+
+// copySymlink recreates a link at dst with the target of the link at src
+func (fs *FS) copySymlink(src, dst string, options *CopyOptions) error {
+	linker, ok := fs.fs.(afero.LinkReader)
+	if !ok {
+		return fmt.Errorf("filesystem does not support symlinks: %s", src)
+	}
+
+	target, err := linker.ReadlinkIfPossible(src)
+	if err != nil {
+		return fmt.Errorf("failed to read link %s: %w", src, err)
+	}
+	return fs.createSymlink(target, dst, options.Overwrite)
+}
+
+// createSymlink creates a link at dst pointing to target
+func (fs *FS) createSymlink(target, dst string, overwrite bool) error {
+	linker, ok := fs.fs.(afero.Symlinker)
+	if !ok {
+		return fmt.Errorf("filesystem does not support symlinks: %s", dst)
+	}
+
+	if _, err := fs.lstat(dst); err == nil {
+		if !overwrite {
+			return fmt.Errorf("failed to create link %s: %w", dst, ErrDestinationExists)
+		}
+		if err := fs.fs.Remove(dst); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", dst, err)
+		}
+	}
+
+	if err := linker.SymlinkIfPossible(target, dst); err != nil {
+		return fmt.Errorf("failed to create link %s: %w", dst, err)
+	}
+	return nil
+}
+
+// dirMode returns the mode to create a copy of the directory at src with
+func (fs *FS) dirMode(src string, options *CopyOptions) os.FileMode {
+	if options.PreserveMode {
+		if info, err := fs.fs.Stat(src); err == nil {
+			return info.Mode().Perm()
+		}
+	}
+	return 0755
+}
+
+// removeEmptyDirs removes the empty directories under and including dir
+func (fs *FS) removeEmptyDirs(dir string) bool {
+	infos, err := afero.ReadDir(fs.fs, dir)
+	if err != nil {
+		return false
+	}
+
+	empty := true
+	for _, info := range infos {
+		child := filepath.Join(dir, info.Name())
+		if !info.IsDir() {
+			empty = false
+			continue
+		}
+		if linkInfo, err := fs.lstat(child); err == nil && linkInfo.Mode()&os.ModeSymlink != 0 {
+			empty = false
+			continue
+		}
+		if !fs.removeEmptyDirs(child) {
+			empty = false
+		}
+	}
+
+	if empty {
+		return fs.fs.Remove(dir) == nil
+	}
+	return false
+}
+
+// copyContents copies in to out in chunks, checking for cancellation and
+// reporting progress after each one
+func copyContents(ctx context.Context, out io.Writer, in io.Reader, progress func(int64)) error {
+	buf := make([]byte, copyBufferSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, readErr := in.Read(buf)
+		if n > 0 {
+			if _, err := out.Write(buf[:n]); err != nil {
+				return err
+			}
+			if progress != nil {
+				progress(int64(n))
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+func matchAnyGlob(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash-separated relative path against a glob
+func matchGlob(pattern, rel string) bool {
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(rel))
+		return matched
+	}
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchGlobSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// "**" matches zero or more directories
+			for i := 0; i <= len(segments); i++ {
+				if matchGlobSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
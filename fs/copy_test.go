@@ -0,0 +1,288 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFS_CopyDir(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+
+	fs.WriteFile(ctx, "/src/manifest.yaml", []byte("name: ext"), 0644)
+	fs.WriteFile(ctx, "/src/bin/ext", []byte("binary"), 0755)
+	fs.WriteFile(ctx, "/src/docs/guide.md", []byte("guide"), 0644)
+
+	var written int64
+	options := CopyOptions{
+		PreserveMode: true,
+		Progress:     func(n int64) { written += n },
+	}
+
+	total, err := fs.CopySize(ctx, "/src", options)
+	if err != nil {
+		t.Fatalf("CopySize failed: %v", err)
+	}
+	if total != 20 {
+		t.Errorf("Expected total of 20 bytes, got %d", total)
+	}
+
+	if err := fs.CopyDir(ctx, "/src", "/dst", options); err != nil {
+		t.Fatalf("CopyDir failed: %v", err)
+	}
+	if written != total {
+		t.Errorf("Expected progress to report %d bytes, got %d", total, written)
+	}
+
+	data, err := fs.ReadFile(ctx, "/dst/bin/ext")
+	if err != nil || string(data) != "binary" {
+		t.Fatalf("Expected copied binary, got %q (%v)", data, err)
+	}
+	info, _ := fs.Stat(ctx, "/dst/bin/ext")
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("Expected mode 0755, got %v", info.Mode().Perm())
+	}
+
+	if err := fs.CopyDir(ctx, "/src", "/dst", CopyOptions{}); !errors.Is(err, ErrDestinationExists) {
+		t.Errorf("Expected ErrDestinationExists, got %v", err)
+	}
+	if err := fs.CopyDir(ctx, "/src", "/dst", CopyOptions{Overwrite: true}); err != nil {
+		t.Errorf("Expected overwrite to succeed, got %v", err)
+	}
+	if err := fs.CopyDir(ctx, "/src", "/src/nested", CopyOptions{}); err == nil {
+		t.Error("Expected copying a directory into itself to fail")
+	}
+}
+
+func TestFS_CopyDir_Filters(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+
+	fs.WriteFile(ctx, "/src/manifest.yaml", []byte("name: ext"), 0644)
+	fs.WriteFile(ctx, "/src/bin/ext", []byte("binary"), 0755)
+	fs.WriteFile(ctx, "/src/docs/guide.md", []byte("guide"), 0644)
+	fs.WriteFile(ctx, "/src/docs/api/ref.md", []byte("ref"), 0644)
+	fs.WriteFile(ctx, "/src/.git/config", []byte("git"), 0644)
+
+	options := CopyOptions{
+		Include: []string{"*.md", "manifest.yaml"},
+		Exclude: []string{".git", "docs/api/**"},
+	}
+	if err := fs.CopyDir(ctx, "/src", "/dst", options); err != nil {
+		t.Fatalf("CopyDir failed: %v", err)
+	}
+
+	for path, want := range map[string]bool{
+		"/dst/manifest.yaml":   true,
+		"/dst/docs/guide.md":   true,
+		"/dst/docs/api/ref.md": false,
+		"/dst/bin":             false,
+		"/dst/.git":            false,
+	} {
+		exists, _ := fs.Exists(ctx, path)
+		if exists != want {
+			t.Errorf("Expected %s to exist: %v, got %v", path, want, exists)
+		}
+	}
+}
+
+func TestFS_CopyDir_PreserveTimes(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	fs.WriteFile(ctx, "/src/file.txt", []byte("data"), 0644)
+	fs.fs.Chtimes("/src/file.txt", mtime, mtime)
+
+	if err := fs.CopyDir(ctx, "/src", "/dst", CopyOptions{PreserveTimes: true}); err != nil {
+		t.Fatalf("CopyDir failed: %v", err)
+	}
+	info, _ := fs.Stat(ctx, "/dst/file.txt")
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("Expected mtime %v, got %v", mtime, info.ModTime())
+	}
+}
+
+func TestFS_CopyDirReport(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+
+	fs.WriteFile(ctx, "/src/a.txt", []byte("aaa"), 0600)
+	fs.WriteFile(ctx, "/src/sub/b.txt", []byte("bb"), 0640)
+	fs.fs.Chmod("/src/sub", 0750)
+
+	options := CopyOptions{PreserveMode: true, PreserveOwner: true, PreserveXattrs: true}
+	report, err := fs.CopyDirReport(ctx, "/src", "/dst", options)
+	if err != nil {
+		t.Fatalf("CopyDirReport failed: %v", err)
+	}
+	if report.Files != 2 || report.Dirs != 1 || report.Bytes != 5 {
+		t.Errorf("Expected 2 files, 1 dir and 5 bytes, got %+v", report)
+	}
+
+	info, _ := fs.Stat(ctx, "/dst/sub")
+	if info.Mode().Perm() != 0750 {
+		t.Errorf("Expected dir mode 0750, got %v", info.Mode().Perm())
+	}
+
+	// The memory filesystem has no owners or xattrs, which is reported
+	// rather than failing the copy; xattrs are reported only once
+	if report.Complete() {
+		t.Fatal("Expected unpreserved metadata to be reported")
+	}
+	counts := map[string]int{}
+	for _, issue := range report.Unpreserved {
+		counts[issue.Attribute]++
+	}
+	if counts[MetadataOwner] != 3 || counts[MetadataXattrs] != 1 || counts[MetadataMode] != 0 {
+		t.Errorf("Unexpected issues: %+v", report.Unpreserved)
+	}
+}
+
+func TestFS_CopyDirReport_OS(t *testing.T) {
+	fs := New()
+	ctx := context.Background()
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	os.MkdirAll(filepath.Join(src, "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "sub", "file.txt"), []byte("data"), 0600)
+	os.Chtimes(filepath.Join(src, "sub"), mtime, mtime)
+
+	// Files already belong to this user, so keeping the owner needs no
+	// privileges
+	options := CopyOptions{PreserveMode: true, PreserveTimes: true, PreserveOwner: true}
+	report, err := fs.CopyDirReport(ctx, src, filepath.Join(dir, "dst"), options)
+	if err != nil {
+		t.Fatalf("CopyDirReport failed: %v", err)
+	}
+	if _, _, ok := fileOwner(mustStat(t, src)); ok && !report.Complete() {
+		t.Errorf("Expected all metadata preserved, got %+v", report.Unpreserved)
+	}
+
+	info := mustStat(t, filepath.Join(dir, "dst", "sub"))
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("Expected dir mtime %v, got %v", mtime, info.ModTime())
+	}
+	if info := mustStat(t, filepath.Join(dir, "dst", "sub", "file.txt")); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected file mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func mustStat(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	return info
+}
+
+func TestFS_CopyDir_Cancelled(t *testing.T) {
+	fs := NewMem()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fs.WriteFile(context.Background(), "/src/file.txt", []byte("data"), 0644)
+
+	if err := fs.CopyDir(ctx, "/src", "/dst", CopyOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if exists, _ := fs.Exists(context.Background(), "/dst/file.txt"); exists {
+		t.Error("Expected nothing to be copied after cancellation")
+	}
+}
+
+func TestFS_CopyDir_Symlinks(t *testing.T) {
+	fs := New()
+	ctx := context.Background()
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+
+	fs.WriteFile(ctx, filepath.Join(src, "bin", "ext"), []byte("binary"), 0755)
+	if err := os.Symlink(filepath.Join("bin", "ext"), filepath.Join(src, "ext")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	preserved := filepath.Join(root, "preserve")
+	if err := fs.CopyDir(ctx, src, preserved, CopyOptions{}); err != nil {
+		t.Fatalf("CopyDir failed: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(preserved, "ext")); err != nil || target != filepath.Join("bin", "ext") {
+		t.Errorf("Expected link to bin/ext, got %q (%v)", target, err)
+	}
+
+	followed := filepath.Join(root, "follow")
+	if err := fs.CopyDir(ctx, src, followed, CopyOptions{Symlinks: SymlinkFollow}); err != nil {
+		t.Fatalf("CopyDir failed: %v", err)
+	}
+	info, err := os.Lstat(filepath.Join(followed, "ext"))
+	if err != nil || !info.Mode().IsRegular() {
+		t.Errorf("Expected a regular file, got %v (%v)", info, err)
+	}
+
+	skipped := filepath.Join(root, "skip")
+	if err := fs.CopyDir(ctx, src, skipped, CopyOptions{Symlinks: SymlinkSkip}); err != nil {
+		t.Fatalf("CopyDir failed: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(skipped, "ext")); !os.IsNotExist(err) {
+		t.Errorf("Expected link to be skipped, got %v", err)
+	}
+}
+
+func TestFS_MoveDir(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+
+	fs.WriteFile(ctx, "/src/bin/ext", []byte("binary"), 0755)
+	fs.WriteFile(ctx, "/src/notes.txt", []byte("notes"), 0644)
+
+	if err := fs.MoveDir(ctx, "/src", "/dst", CopyOptions{}); err != nil {
+		t.Fatalf("MoveDir failed: %v", err)
+	}
+	if exists, _ := fs.Exists(ctx, "/src"); exists {
+		t.Error("Expected source to be gone after move")
+	}
+	if data, _ := fs.ReadFile(ctx, "/dst/bin/ext"); string(data) != "binary" {
+		t.Errorf("Expected moved binary, got %q", data)
+	}
+
+	if err := fs.MoveDir(ctx, "/dst", "/out", CopyOptions{Exclude: []string{"*.txt"}}); err != nil {
+		t.Fatalf("MoveDir failed: %v", err)
+	}
+	if exists, _ := fs.Exists(ctx, "/dst/bin"); exists {
+		t.Error("Expected emptied directories to be removed")
+	}
+	if exists, _ := fs.Exists(ctx, "/dst/notes.txt"); !exists {
+		t.Error("Expected excluded file to stay behind")
+	}
+	if exists, _ := fs.Exists(ctx, "/out/bin/ext"); !exists {
+		t.Error("Expected included file to be moved")
+	}
+}
+
+func TestFS_CopyFile(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+
+	fs.WriteFile(ctx, "/a.txt", []byte("data"), 0600)
+
+	if err := fs.CopyFile(ctx, "/a.txt", "/dir/b.txt"); err != nil {
+		t.Fatalf("CopyFile failed: %v", err)
+	}
+	if data, _ := fs.ReadFile(ctx, "/dir/b.txt"); string(data) != "data" {
+		t.Errorf("Expected copied data, got %q", data)
+	}
+
+	if err := fs.MoveFile(ctx, "/dir/b.txt", "/c.txt"); err != nil {
+		t.Fatalf("MoveFile failed: %v", err)
+	}
+	if exists, _ := fs.Exists(ctx, "/dir/b.txt"); exists {
+		t.Error("Expected moved file to be gone")
+	}
+}
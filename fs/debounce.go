@@ -0,0 +1,138 @@
+package fs
+
+import (
+	"os"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WithDebounce coalesces the events of each path into one logical change,
+// delivered once the path has been quiet for d. Editors save files with
+// bursts of CREATE, WRITE, RENAME and CHMOD events; handlers instead see a
+// single event whose Op reflects the final state:
+//
+//   - Create when the path did not exist before the burst and does now
+//   - Write when it existed before and still does
+//   - Remove or Rename when it existed before and is gone
+//   - Chmod when only permissions changed
+//
+// Paths created and removed within one burst, such as editor swap files,
+// produce no event at all
+func WithDebounce(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.debounce = d
+	}
+}
+
+// debouncer collects events per path until each has been quiet for delay.
+// It is only used from the watch loop, so it needs no locking
+type debouncer struct {
+	delay   time.Duration
+	pending map[string]*pendingChange
+	// order keeps paths in the order their bursts started
+	order []string
+	timer *time.Timer
+}
+
+// pendingChange is the burst of events seen for one path
+type pendingChange struct {
+	first fsnotify.Op
+	ops   fsnotify.Op
+	last  time.Time
+}
+
+func newDebouncer(delay time.Duration) *debouncer {
+	timer := time.NewTimer(delay)
+	timer.Stop()
+
+	return &debouncer{
+		delay:   delay,
+		pending: make(map[string]*pendingChange),
+		timer:   timer,
+	}
+}
+
+// C returns the channel that fires when a burst may have ended, or nil
+// when debouncing is off
+func (d *debouncer) C() <-chan time.Time {
+	if d == nil {
+		return nil
+	}
+	return d.timer.C
+}
+
+// add records an event, extending the burst of its path
+func (d *debouncer) add(event fsnotify.Event, now time.Time) {
+	change, ok := d.pending[event.Name]
+	if !ok {
+		change = &pendingChange{first: event.Op}
+		d.pending[event.Name] = change
+		d.order = append(d.order, event.Name)
+	}
+	change.ops |= event.Op
+	change.last = now
+
+	d.schedule(now)
+}
+
+// due returns the coalesced events of the paths quiet since now-delay
+func (d *debouncer) due(now time.Time) []WatchEvent {
+	var events []WatchEvent
+	remaining := d.order[:0]
+	for _, name := range d.order {
+		change := d.pending[name]
+		if now.Sub(change.last) < d.delay {
+			remaining = append(remaining, name)
+			continue
+		}
+
+		delete(d.pending, name)
+		if op, ok := change.finalOp(name); ok {
+			events = append(events, WatchEvent{Name: name, Op: op, Timestamp: change.last})
+		}
+	}
+	d.order = remaining
+
+	d.schedule(now)
+	return events
+}
+
+// schedule arms the timer for the earliest burst to end
+func (d *debouncer) schedule(now time.Time) {
+	if len(d.order) == 0 {
+		d.timer.Stop()
+		return
+	}
+
+	next := d.pending[d.order[0]].last
+	for _, name := range d.order[1:] {
+		if last := d.pending[name].last; last.Before(next) {
+			next = last
+		}
+	}
+	d.timer.Reset(next.Add(d.delay).Sub(now))
+}
+
+// finalOp works out the logical change of a burst from the state of the
+// path now. It returns false when the burst left nothing changed
+func (c *pendingChange) finalOp(name string) (fsnotify.Op, bool) {
+	existedBefore := c.first&fsnotify.Create == 0
+	_, err := os.Lstat(name)
+	existsNow := err == nil
+
+	switch {
+	case existsNow && !existedBefore:
+		return fsnotify.Create, true
+	case existsNow && c.ops&^fsnotify.Chmod == 0:
+		return fsnotify.Chmod, true
+	case existsNow:
+		return fsnotify.Write, true
+	case !existedBefore:
+		return 0, false
+	case c.ops&fsnotify.Remove == 0 && c.ops&fsnotify.Rename != 0:
+		return fsnotify.Rename, true
+	default:
+		return fsnotify.Remove, true
+	}
+}
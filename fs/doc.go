@@ -3,10 +3,23 @@
 // Features:
 //   - File Operations: Create, read, write, and manage files
 //   - File Watching: Monitor file system changes with fsnotify integration
+//   - Debounced Watching: Coalesce bursts of events into one change per path
+//   - Recursive Watching: Whole-tree watches filtered by include and exclude globs
+//   - Polling Fallback: Poll-based watching for network filesystems and missing fsnotify
+//   - Rooted Filesystem: Symlink-aware sandboxing of paths to a directory
+//   - Archives: tar.gz and zip packing and unpacking with zip-slip protection
 //   - Directory Operations: Recursive directory operations and management
+//   - Symlink-Following Walk: Tree walks through symlinks with cycle detection
+//   - Disk Usage: Concurrent directory sizes and portable free-space checks
 //   - Path Utilities: Cross-platform path handling and manipulation
 //   - Context Support: Full context.Context integration for cancellation
 //   - Event Handling: Rich event system for file system changes
+//   - Tree Verification: Parallel checksum verification against a manifest
+//   - Tree Hashing: Deterministic directory digests and manifest diffs
+//   - Ownership Registry: Owner-tagged paths for complete uninstall and orphan scans
+//   - Managed Temp Files: Per-process temp namespaces with cleanup and orphan sweeps
+//   - Recursive Copy: CopyDir and MoveDir with glob filters, symlink policies and progress
+//   - Metadata Preservation: Owner, mode, time and xattr copying with a report of gaps
 //
 // Example:
 //   watcher := fs.NewWatcher()
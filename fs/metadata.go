@@ -0,0 +1,109 @@
+package fs
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/afero"
+)
+
+// Metadata attributes named in a MetadataIssue
+const (
+	MetadataOwner  = "owner"
+	MetadataMode   = "mode"
+	MetadataTimes  = "times"
+	MetadataXattrs = "xattrs"
+)
+
+// errXattrsUnsupported is reported when extended attributes cannot be
+// copied on this platform or filesystem
+var errXattrsUnsupported = errors.New("extended attributes are not supported here")
+
+// MetadataIssue records an attribute a copy could not preserve
+type MetadataIssue struct {
+	// Path is the source path whose attribute was not preserved
+	Path string `json:"path"`
+	// Attribute is one of MetadataOwner, MetadataMode, MetadataTimes or
+	// MetadataXattrs
+	Attribute string `json:"attribute"`
+	// Reason explains what went wrong
+	Reason string `json:"reason"`
+}
+
+// CopyReport summarizes a copy. Metadata that could not be preserved does
+// not fail the copy; it is listed in Unpreserved instead
+type CopyReport struct {
+	Files       int             `json:"files"`
+	Dirs        int             `json:"dirs"`
+	Symlinks    int             `json:"symlinks"`
+	Bytes       int64           `json:"bytes"`
+	Unpreserved []MetadataIssue `json:"unpreserved"`
+
+	xattrsUnsupported bool
+}
+
+// Complete reports whether every requested attribute was preserved
+func (r *CopyReport) Complete() bool {
+	return len(r.Unpreserved) == 0
+}
+
+func (r *CopyReport) unpreserved(path, attribute string, err error) {
+	if errors.Is(err, errXattrsUnsupported) {
+		// Once is enough to say the platform cannot do it
+		if r.xattrsUnsupported {
+			return
+		}
+		r.xattrsUnsupported = true
+	}
+	r.Unpreserved = append(r.Unpreserved, MetadataIssue{Path: path, Attribute: attribute, Reason: err.Error()})
+}
+
+// preserveMetadata applies the metadata of src, described by info, to dst
+// as options ask, recording what could not be applied. Symlinks only get
+// their owner preserved, as the others cannot be set on links portably
+func (fs *FS) preserveMetadata(src, dst string, info os.FileInfo, options *CopyOptions, report *CopyReport) {
+	link := info.Mode()&os.ModeSymlink != 0
+	_, osFs := fs.fs.(*afero.OsFs)
+
+	// Ownership goes first, as changing it can clear setuid bits
+	if options.PreserveOwner {
+		uid, gid, ok := fileOwner(info)
+		var err error
+		switch {
+		case !ok:
+			err = errors.New("ownership is not available for the source")
+		case link && osFs:
+			err = os.Lchown(dst, uid, gid)
+		case link:
+			err = errors.New("cannot change the owner of a symlink here")
+		default:
+			err = fs.fs.Chown(dst, uid, gid)
+		}
+		if err != nil {
+			report.unpreserved(src, MetadataOwner, err)
+		}
+	}
+	if link {
+		return
+	}
+
+	if options.PreserveMode {
+		if err := fs.fs.Chmod(dst, info.Mode().Perm()); err != nil {
+			report.unpreserved(src, MetadataMode, err)
+		}
+	}
+	if options.PreserveXattrs {
+		err := errXattrsUnsupported
+		if osFs {
+			err = copyXattrs(src, dst)
+		}
+		if err != nil {
+			report.unpreserved(src, MetadataXattrs, err)
+		}
+	}
+	if options.PreserveTimes {
+		if err := fs.fs.Chtimes(dst, info.ModTime(), info.ModTime()); err != nil {
+			report.unpreserved(src, MetadataTimes, err)
+		}
+	}
+}
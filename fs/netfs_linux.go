@@ -0,0 +1,26 @@
+//go:build linux
+
+package fs
+
+import "syscall"
+
+// Filesystem magic numbers from statfs(2) on which inotify misses changes
+// made by other hosts
+var networkFilesystems = map[uint32]bool{
+	0x6969:     true, // NFS
+	0xff534d42: true, // CIFS
+	0xfe534d42: true, // SMB2
+	0x517b:     true, // SMB
+	0x01021997: true, // 9P, used for host mounts by WSL and some VMs
+	0x65735546: true, // FUSE, as used by sshfs and many container mounts
+}
+
+// isNetworkFS reports whether path is on a filesystem where notifications
+// are unreliable
+func isNetworkFS(path string) bool {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return false
+	}
+	return networkFilesystems[uint32(stat.Type)]
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package fs
+
+// isNetworkFS reports whether path is on a filesystem where notifications
+// are unreliable. Detection is only implemented on Linux
+func isNetworkFS(path string) bool {
+	return false
+}
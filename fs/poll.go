@@ -0,0 +1,290 @@
+package fs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultPollInterval is how often the polling watcher scans by default
+const DefaultPollInterval = time.Second
+
+// PollCompare selects how the polling watcher detects changed files
+type PollCompare int
+
+const (
+	// PollModTime compares modification time and size
+	PollModTime PollCompare = iota
+	// PollChecksum compares content checksums, for filesystems whose
+	// modification times are coarse or unreliable. Every file is read on
+	// each scan, so keep watched trees small
+	PollChecksum
+)
+
+// WithPolling makes the watcher scan watched paths every interval instead
+// of relying on OS notifications. Polling is also used automatically when
+// fsnotify is unavailable, and for paths on network filesystems such as
+// NFS or SMB, where notifications are not delivered reliably
+func WithPolling(interval time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.pollForced = true
+		if interval > 0 {
+			w.pollInterval = interval
+		}
+	}
+}
+
+// WithPollInterval sets the scan interval used whenever the watcher
+// polls, without forcing polling
+func WithPollInterval(interval time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if interval > 0 {
+			w.pollInterval = interval
+		}
+	}
+}
+
+// WithPollCompare sets how the polling watcher detects changed files
+func WithPollCompare(compare PollCompare) WatcherOption {
+	return func(w *Watcher) {
+		w.pollCompare = compare
+	}
+}
+
+// poller emits fsnotify-style events by scanning watched paths. Like
+// fsnotify, a watched directory reports changes to its direct entries
+type poller struct {
+	interval time.Duration
+	compare  PollCompare
+	events   chan fsnotify.Event
+	errors   chan error
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	mu sync.Mutex
+	// paths maps each watched path to the state of it and its entries
+	paths   map[string]map[string]fileState
+	started sync.Once
+	closed  sync.Once
+}
+
+// fileState is what the poller remembers of a file between scans
+type fileState struct {
+	dir     bool
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+	sum     []byte
+}
+
+func newPoller(interval time.Duration, compare PollCompare) *poller {
+	return &poller{
+		interval: interval,
+		compare:  compare,
+		events:   make(chan fsnotify.Event, 64),
+		errors:   make(chan error, 1),
+		done:     make(chan struct{}),
+		paths:    make(map[string]map[string]fileState),
+	}
+}
+
+// Add starts polling a file or directory
+func (p *poller) Add(path string) error {
+	snapshot, err := p.scan(path)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.paths[path] = snapshot
+	p.mu.Unlock()
+
+	// Scanning only starts once there is something to scan
+	p.started.Do(func() {
+		p.wg.Add(1)
+		go p.loop()
+	})
+	return nil
+}
+
+// Close stops polling and closes the event channels
+func (p *poller) Close() error {
+	p.closed.Do(func() {
+		close(p.done)
+		p.wg.Wait()
+		close(p.events)
+		close(p.errors)
+	})
+	return nil
+}
+
+func (p *poller) loop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.poll()
+		}
+	}
+}
+
+// poll rescans every watched path and emits the differences
+func (p *poller) poll() {
+	p.mu.Lock()
+	paths := make([]string, 0, len(p.paths))
+	for path := range p.paths {
+		paths = append(paths, path)
+	}
+	p.mu.Unlock()
+
+	for _, path := range paths {
+		p.mu.Lock()
+		previous, ok := p.paths[path]
+		p.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		current, err := p.scan(path)
+		if err != nil && !os.IsNotExist(err) {
+			p.sendError(err)
+			continue
+		}
+
+		for _, event := range diffStates(previous, current) {
+			if !p.send(event) {
+				return
+			}
+		}
+
+		p.mu.Lock()
+		if os.IsNotExist(err) {
+			// The watched path is gone, as fsnotify drops removed watches
+			delete(p.paths, path)
+		} else {
+			p.paths[path] = current
+		}
+		p.mu.Unlock()
+	}
+}
+
+// scan records the state of path and, for a directory, its entries
+func (p *poller) scan(path string) (map[string]fileState, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]fileState{path: p.state(path, info)}
+	if !info.IsDir() {
+		return snapshot, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := filepath.Join(path, entry.Name())
+		info, err := os.Lstat(name)
+		if err != nil {
+			// Removed between listing and stat; the next scan reports it
+			continue
+		}
+		snapshot[name] = p.state(name, info)
+	}
+	return snapshot, nil
+}
+
+func (p *poller) state(path string, info os.FileInfo) fileState {
+	state := fileState{
+		dir:     info.IsDir(),
+		size:    info.Size(),
+		mode:    info.Mode(),
+		modTime: info.ModTime(),
+	}
+	if p.compare == PollChecksum && info.Mode().IsRegular() {
+		state.sum = checksum(path)
+	}
+	return state
+}
+
+func (p *poller) send(event fsnotify.Event) bool {
+	select {
+	case p.events <- event:
+		return true
+	case <-p.done:
+		return false
+	}
+}
+
+func (p *poller) sendError(err error) {
+	select {
+	case p.errors <- err:
+	default:
+	}
+}
+
+// diffStates returns the events turning previous into current
+func diffStates(previous, current map[string]fileState) []fsnotify.Event {
+	var events []fsnotify.Event
+	for name, before := range previous {
+		after, ok := current[name]
+		switch {
+		case !ok:
+			events = append(events, fsnotify.Event{Name: name, Op: fsnotify.Remove})
+		case before.dir != after.dir:
+			events = append(events,
+				fsnotify.Event{Name: name, Op: fsnotify.Remove},
+				fsnotify.Event{Name: name, Op: fsnotify.Create})
+		case before.changed(after):
+			events = append(events, fsnotify.Event{Name: name, Op: fsnotify.Write})
+		case before.mode != after.mode:
+			events = append(events, fsnotify.Event{Name: name, Op: fsnotify.Chmod})
+		}
+	}
+	for name := range current {
+		if _, ok := previous[name]; !ok {
+			events = append(events, fsnotify.Event{Name: name, Op: fsnotify.Create})
+		}
+	}
+	return events
+}
+
+// changed reports whether the contents of a file changed
+func (s fileState) changed(other fileState) bool {
+	if s.dir {
+		return false
+	}
+	if s.sum != nil || other.sum != nil {
+		return !bytes.Equal(s.sum, other.sum)
+	}
+	return s.size != other.size || !s.modTime.Equal(other.modTime)
+}
+
+// checksum returns the SHA-256 of a file, or nil when it cannot be read
+func checksum(path string) []byte {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil
+	}
+	return hash.Sum(nil)
+}
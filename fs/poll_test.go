@@ -0,0 +1,139 @@
+package fs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestWatcher_Polling(t *testing.T) {
+	dir := t.TempDir()
+
+	watcher, err := NewWatcher(WithPolling(20 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	defer watcher.Stop()
+
+	if watcher.watcher != nil {
+		t.Fatal("Expected forced polling not to use fsnotify")
+	}
+
+	var events []WatchEvent
+	var mu sync.Mutex
+	watcher.AddHandlerFunc("*.yaml", func(ctx context.Context, event WatchEvent) error {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+		return nil
+	})
+
+	if err := watcher.Watch(dir); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	watcher.Start()
+
+	file := filepath.Join(dir, "config.yaml")
+	wait := func(op fsnotify.Op) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			for _, event := range events {
+				if event.Name == file && event.Op == op {
+					events = nil
+					mu.Unlock()
+					return
+				}
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("Timed out waiting for %s event", op)
+	}
+
+	os.WriteFile(file, []byte("v1"), 0644)
+	wait(fsnotify.Create)
+
+	os.WriteFile(file, []byte("version 2"), 0644)
+	wait(fsnotify.Write)
+
+	os.Remove(file)
+	wait(fsnotify.Remove)
+}
+
+func TestPoller_Checksum(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "data")
+	mtime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	os.WriteFile(file, []byte("aaaa"), 0644)
+	os.Chtimes(file, mtime, mtime)
+
+	for _, tt := range []struct {
+		compare PollCompare
+		want    int
+	}{
+		{PollModTime, 0},
+		{PollChecksum, 1},
+	} {
+		p := newPoller(time.Hour, tt.compare)
+		before, err := p.scan(dir)
+		if err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+
+		// Same size and modification time, different contents
+		os.WriteFile(file, []byte("bbbb"), 0644)
+		os.Chtimes(file, mtime, mtime)
+
+		after, _ := p.scan(dir)
+		if events := diffStates(before, after); len(events) != tt.want {
+			t.Errorf("compare %d: expected %d events, got %+v", tt.compare, tt.want, events)
+		}
+		p.Close()
+
+		os.WriteFile(file, []byte("aaaa"), 0644)
+		os.Chtimes(file, mtime, mtime)
+	}
+}
+
+func TestDiffStates(t *testing.T) {
+	now := time.Now()
+	previous := map[string]fileState{
+		"/d/kept":    {size: 1, mode: 0644, modTime: now},
+		"/d/written": {size: 1, mode: 0644, modTime: now},
+		"/d/chmod":   {size: 1, mode: 0644, modTime: now},
+		"/d/removed": {size: 1, mode: 0644, modTime: now},
+	}
+	current := map[string]fileState{
+		"/d/kept":    {size: 1, mode: 0644, modTime: now},
+		"/d/written": {size: 2, mode: 0644, modTime: now.Add(time.Second)},
+		"/d/chmod":   {size: 1, mode: 0600, modTime: now},
+		"/d/created": {size: 1, mode: 0644, modTime: now},
+	}
+
+	got := make(map[string]fsnotify.Op)
+	for _, event := range diffStates(previous, current) {
+		got[event.Name] = event.Op
+	}
+	want := map[string]fsnotify.Op{
+		"/d/written": fsnotify.Write,
+		"/d/chmod":   fsnotify.Chmod,
+		"/d/removed": fsnotify.Remove,
+		"/d/created": fsnotify.Create,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for name, op := range want {
+		if got[name] != op {
+			t.Errorf("Expected %s for %s, got %s", op, name, got[name])
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package fs
+
+import "os"
+
+// processAlive reports whether a process with the given ID is running. On
+// Windows, FindProcess fails for processes that have exited
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
+
+// reraise terminates the process, as sig would have without a handler
+func reraise(sig os.Signal) {
+	os.Exit(1)
+}
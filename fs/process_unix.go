@@ -0,0 +1,26 @@
+//go:build unix
+
+package fs
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// processAlive reports whether a process with the given ID is running
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// reraise delivers sig again with its default behaviour, normally
+// terminating the process
+func reraise(sig os.Signal) {
+	signal.Reset(sig)
+	if s, ok := sig.(syscall.Signal); ok {
+		syscall.Kill(os.Getpid(), s)
+		return
+	}
+	os.Exit(1)
+}
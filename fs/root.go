@@ -0,0 +1,245 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ErrOutsideRoot is returned when a path given to a RootFS resolves outside
+// its root, through ".." components, an absolute path or a symlink
+var ErrOutsideRoot = errors.New("path escapes root")
+
+// maxSymlinkHops bounds symlink resolution, as the kernel does with ELOOP
+const maxSymlinkHops = 40
+
+// RootFS confines filesystem operations to a directory. Paths are taken
+// relative to the root, and symlinks are resolved component by component
+// so none can lead outside it. It guards against untrusted paths, such as
+// those provided by extensions, not against concurrent changes to the tree
+// between resolving a path and using it
+type RootFS struct {
+	fs  *FS
+	dir string
+}
+
+// Root returns a handle confined to dir on the OS filesystem
+func Root(dir string) (*RootFS, error) {
+	return New().Root(dir)
+}
+
+// Root returns a handle confined to dir, which must be an existing
+// directory
+func (fs *FS) Root(dir string) (*RootFS, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root %s: %w", dir, err)
+	}
+	// Resolve links in the root itself, such as /tmp on macOS, so paths
+	// resolved below it compare against the real location
+	if _, ok := fs.fs.(*afero.OsFs); ok {
+		if abs, err = filepath.EvalSymlinks(abs); err != nil {
+			return nil, fmt.Errorf("failed to resolve root %s: %w", dir, err)
+		}
+	}
+
+	info, err := fs.fs.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open root %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("root %s is not a directory", dir)
+	}
+	return &RootFS{fs: fs, dir: abs}, nil
+}
+
+// Dir returns the absolute path of the root
+func (r *RootFS) Dir() string {
+	return r.dir
+}
+
+// Path resolves name to an absolute path inside the root, following
+// symlinks, or returns an error wrapping ErrOutsideRoot
+func (r *RootFS) Path(name string) (string, error) {
+	return r.resolve(name, true)
+}
+
+// ReadFile reads a file inside the root
+func (r *RootFS) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	path, err := r.resolve(name, true)
+	if err != nil {
+		return nil, err
+	}
+	return r.fs.ReadFile(ctx, path)
+}
+
+// WriteFile writes a file inside the root
+func (r *RootFS) WriteFile(ctx context.Context, name string, data []byte, perm os.FileMode) error {
+	path, err := r.resolve(name, true)
+	if err != nil {
+		return err
+	}
+	return r.fs.WriteFile(ctx, path, data, perm)
+}
+
+// MkdirAll creates a directory inside the root along with its parents
+func (r *RootFS) MkdirAll(ctx context.Context, name string, perm os.FileMode) error {
+	path, err := r.resolve(name, true)
+	if err != nil {
+		return err
+	}
+	return r.fs.MkdirAll(ctx, path, perm)
+}
+
+// Stat returns file info for a path inside the root
+func (r *RootFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	path, err := r.resolve(name, true)
+	if err != nil {
+		return nil, err
+	}
+	return r.fs.Stat(ctx, path)
+}
+
+// Exists checks if a path inside the root exists
+func (r *RootFS) Exists(ctx context.Context, name string) (bool, error) {
+	path, err := r.resolve(name, true)
+	if err != nil {
+		return false, err
+	}
+	return r.fs.Exists(ctx, path)
+}
+
+// Remove removes a file or empty directory inside the root. A symlink is
+// removed itself, not its target
+func (r *RootFS) Remove(ctx context.Context, name string) error {
+	path, err := r.resolve(name, false)
+	if err != nil {
+		return err
+	}
+	if path == r.dir {
+		return fmt.Errorf("refusing to remove root %s", r.dir)
+	}
+	return r.fs.Remove(ctx, path)
+}
+
+// RemoveAll removes a path inside the root and all children. Symlinks
+// within it are removed, never followed
+func (r *RootFS) RemoveAll(ctx context.Context, name string) error {
+	path, err := r.resolve(name, false)
+	if err != nil {
+		return err
+	}
+	if path == r.dir {
+		return fmt.Errorf("refusing to remove root %s", r.dir)
+	}
+	return r.fs.RemoveAll(ctx, path)
+}
+
+// Walk walks the tree at name inside the root, calling fn with paths
+// relative to the root. Symlinks are reported but not followed
+func (r *RootFS) Walk(ctx context.Context, name string, fn filepath.WalkFunc) error {
+	start, err := r.resolve(name, true)
+	if err != nil {
+		return err
+	}
+
+	return afero.Walk(r.fs.fs, start, func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		rel, relErr := filepath.Rel(r.dir, path)
+		if relErr != nil {
+			return relErr
+		}
+		return fn(rel, info, err)
+	})
+}
+
+// resolve maps name to an absolute path inside the root. Each existing
+// component is checked for symlinks, whose targets must stay inside the
+// root; components that do not exist yet are joined as they are. When
+// followLast is false a symlink in the final component is left as is
+func (r *RootFS) resolve(name string, followLast bool) (string, error) {
+	joined := filepath.Clean(name)
+	if !filepath.IsAbs(joined) {
+		joined = filepath.Join(r.dir, joined)
+	}
+	if !isWithin(joined, r.dir) {
+		return "", fmt.Errorf("%s: %w", name, ErrOutsideRoot)
+	}
+
+	rel, err := filepath.Rel(r.dir, joined)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, ErrOutsideRoot)
+	}
+	parts := splitPath(rel)
+
+	current := r.dir
+	hops := 0
+	for i := 0; i < len(parts); i++ {
+		next := filepath.Join(current, parts[i])
+		last := i == len(parts)-1
+		if last && !followLast {
+			return next, nil
+		}
+
+		info, err := r.fs.lstat(next)
+		if os.IsNotExist(err) {
+			return filepath.Join(append([]string{next}, parts[i+1:]...)...), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			current = next
+			continue
+		}
+
+		hops++
+		if hops > maxSymlinkHops {
+			return "", fmt.Errorf("failed to resolve %s: too many levels of symbolic links", name)
+		}
+		target, err := r.readlink(next)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(current, target)
+		}
+		target = filepath.Clean(target)
+		if !isWithin(target, r.dir) {
+			return "", fmt.Errorf("%s: %w", name, ErrOutsideRoot)
+		}
+
+		// Continue from the root with the target and what remains
+		targetRel, _ := filepath.Rel(r.dir, target)
+		parts = append(splitPath(targetRel), parts[i+1:]...)
+		current = r.dir
+		i = -1
+	}
+	return current, nil
+}
+
+func (r *RootFS) readlink(name string) (string, error) {
+	reader, ok := r.fs.fs.(afero.LinkReader)
+	if !ok {
+		return "", fmt.Errorf("filesystem does not support symlinks: %s", name)
+	}
+	return reader.ReadlinkIfPossible(name)
+}
+
+// splitPath splits a relative path into its components, dropping "."
+func splitPath(rel string) []string {
+	var parts []string
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if part != "" && part != "." {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,123 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestRootFS_Traversal(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+	fs.MkdirAll(ctx, "/ext/config", 0755)
+	fs.WriteFile(ctx, "/secret", []byte("secret"), 0600)
+
+	root, err := fs.Root("/ext")
+	if err != nil {
+		t.Fatalf("Root failed: %v", err)
+	}
+
+	if err := root.WriteFile(ctx, "config/settings.yaml", []byte("a: 1"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, err := root.ReadFile(ctx, "./config/../config/settings.yaml")
+	if err != nil || string(data) != "a: 1" {
+		t.Fatalf("Expected settings, got %q (%v)", data, err)
+	}
+
+	for _, name := range []string{"../secret", "config/../../secret", "/secret"} {
+		if _, err := root.ReadFile(ctx, name); !errors.Is(err, ErrOutsideRoot) {
+			t.Errorf("ReadFile(%q): expected ErrOutsideRoot, got %v", name, err)
+		}
+		if err := root.WriteFile(ctx, name, []byte("x"), 0644); !errors.Is(err, ErrOutsideRoot) {
+			t.Errorf("WriteFile(%q): expected ErrOutsideRoot, got %v", name, err)
+		}
+		if err := root.RemoveAll(ctx, name); !errors.Is(err, ErrOutsideRoot) {
+			t.Errorf("RemoveAll(%q): expected ErrOutsideRoot, got %v", name, err)
+		}
+	}
+
+	// Absolute paths inside the root are accepted
+	if exists, err := root.Exists(ctx, "/ext/config/settings.yaml"); err != nil || !exists {
+		t.Errorf("Expected absolute path inside root to resolve, got %v (%v)", exists, err)
+	}
+	if err := root.RemoveAll(ctx, "."); err == nil {
+		t.Error("Expected removing the root itself to fail")
+	}
+}
+
+func TestRootFS_Symlinks(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	dir := filepath.Join(base, "ext")
+	os.MkdirAll(filepath.Join(dir, "data"), 0755)
+	os.WriteFile(filepath.Join(dir, "data", "file.txt"), []byte("inside"), 0644)
+	os.WriteFile(filepath.Join(base, "secret"), []byte("outside"), 0600)
+
+	if err := os.Symlink("data", filepath.Join(dir, "alias")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	os.Symlink(filepath.Join(base, "secret"), filepath.Join(dir, "escape"))
+	os.Symlink("..", filepath.Join(dir, "data", "up"))
+	os.Symlink("loop", filepath.Join(dir, "loop"))
+
+	root, err := Root(dir)
+	if err != nil {
+		t.Fatalf("Root failed: %v", err)
+	}
+
+	data, err := root.ReadFile(ctx, "alias/file.txt")
+	if err != nil || string(data) != "inside" {
+		t.Errorf("Expected link inside root to be followed, got %q (%v)", data, err)
+	}
+	if data, err := root.ReadFile(ctx, "data/up/data/file.txt"); err != nil || string(data) != "inside" {
+		t.Errorf("Expected relative link staying inside root to work, got %q (%v)", data, err)
+	}
+
+	if _, err := root.ReadFile(ctx, "escape"); !errors.Is(err, ErrOutsideRoot) {
+		t.Errorf("Expected ErrOutsideRoot reading through link, got %v", err)
+	}
+	if err := root.WriteFile(ctx, "escape", []byte("x"), 0644); !errors.Is(err, ErrOutsideRoot) {
+		t.Errorf("Expected ErrOutsideRoot writing through link, got %v", err)
+	}
+	if _, err := root.ReadFile(ctx, "data/up/escape"); !errors.Is(err, ErrOutsideRoot) {
+		t.Errorf("Expected ErrOutsideRoot through chained links, got %v", err)
+	}
+	if _, err := root.ReadFile(ctx, "loop"); err == nil {
+		t.Error("Expected symlink loop to fail")
+	}
+
+	// Removing a link removes the link, leaving its target alone
+	if err := root.Remove(ctx, "escape"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "secret")); err != nil {
+		t.Errorf("Expected link target to survive, got %v", err)
+	}
+
+	var walked []string
+	err = root.Walk(ctx, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	sort.Strings(walked)
+	want := []string{".", "alias", "data", filepath.Join("data", "file.txt"), filepath.Join("data", "up"), "loop"}
+	if len(walked) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, walked)
+	}
+	for i := range want {
+		if walked[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, walked)
+			break
+		}
+	}
+}
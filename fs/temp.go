@@ -0,0 +1,233 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/afero"
+)
+
+// DefaultTempTTL is how old an orphaned temp directory must be before a
+// sweep removes it
+const DefaultTempTTL = 24 * time.Hour
+
+// DefaultTempDir returns the directory holding managed temp namespaces
+func DefaultTempDir() string {
+	return filepath.Join(xdg.CacheHome, "tykctl", "tmp")
+}
+
+// TempOptions configures NewTempManager
+type TempOptions struct {
+	// Dir holds the namespaces; empty means DefaultTempDir
+	Dir string
+	// TTL is how old a directory left by another process must be before it
+	// is swept; zero means DefaultTempTTL and a negative value disables
+	// the sweep on startup
+	TTL time.Duration
+	// CleanupOnSignal removes this process's temp files on interrupt or
+	// termination, then lets the signal end the process as it would have
+	CleanupOnSignal bool
+}
+
+// TempManager creates temp files and directories for one process under a
+// namespace, such as "extension-install", and removes them all on Close.
+// Each process works in its own directory named after its PID, so entries
+// left behind by a crash can be told apart from those still in use and
+// swept by a later run
+type TempManager struct {
+	fs   *FS
+	base string
+	dir  string
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	closed  bool
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+// NewTempManager creates a manager for namespace, first sweeping
+// orphaned directories of the namespace older than the TTL
+func NewTempManager(ctx context.Context, fsys *FS, namespace string, options TempOptions) (*TempManager, error) {
+	if namespace == "" || namespace == "." || namespace == ".." || strings.ContainsAny(namespace, `/\`) {
+		return nil, fmt.Errorf("invalid temp namespace %q", namespace)
+	}
+	if options.Dir == "" {
+		options.Dir = DefaultTempDir()
+	}
+	if options.TTL == 0 {
+		options.TTL = DefaultTempTTL
+	}
+
+	base := filepath.Join(options.Dir, namespace)
+	if err := fsys.MkdirAll(ctx, base, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory %s: %w", base, err)
+	}
+
+	m := &TempManager{fs: fsys, base: base, ttl: options.TTL}
+	if options.TTL > 0 {
+		if _, err := m.Sweep(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	dir, err := afero.TempDir(fsys.fs, base, strconv.Itoa(os.Getpid())+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory in %s: %w", base, err)
+	}
+	m.dir = dir
+
+	if options.CleanupOnSignal {
+		m.handleSignals()
+	}
+	return m, nil
+}
+
+// Dir returns the directory holding this process's temp entries
+func (m *TempManager) Dir() string {
+	return m.dir
+}
+
+// TempDir creates a new directory whose name starts with pattern, as
+// os.MkdirTemp does
+func (m *TempManager) TempDir(ctx context.Context, pattern string) (string, error) {
+	if err := m.check(ctx); err != nil {
+		return "", err
+	}
+
+	dir, err := afero.TempDir(m.fs.fs, m.dir, pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	return dir, nil
+}
+
+// TempFile creates and opens a new file whose name follows pattern, as
+// os.CreateTemp does. The caller closes the file; Close removes it
+func (m *TempManager) TempFile(ctx context.Context, pattern string) (afero.File, error) {
+	if err := m.check(ctx); err != nil {
+		return nil, err
+	}
+
+	file, err := afero.TempFile(m.fs.fs, m.dir, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	return file, nil
+}
+
+// Remove deletes a temp entry before Close, such as once a download has
+// been installed
+func (m *TempManager) Remove(ctx context.Context, path string) error {
+	if !isWithin(filepath.Clean(path), m.dir) || filepath.Clean(path) == m.dir {
+		return fmt.Errorf("%s is not managed by this temp manager", path)
+	}
+	return m.fs.RemoveAll(ctx, path)
+}
+
+// Close removes every temp entry of this process. It is safe to call more
+// than once
+func (m *TempManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+
+	if m.signals != nil {
+		signal.Stop(m.signals)
+		close(m.done)
+	}
+	if err := m.fs.fs.RemoveAll(m.dir); err != nil {
+		return fmt.Errorf("failed to remove temp directory %s: %w", m.dir, err)
+	}
+	return nil
+}
+
+// Sweep removes directories of the namespace left by processes that are no
+// longer running and last modified longer ago than the TTL, returning the
+// paths removed. Entries whose owner cannot be told are removed on age
+// alone
+func (m *TempManager) Sweep(ctx context.Context) ([]string, error) {
+	infos, err := afero.ReadDir(m.fs.fs, m.base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read temp directory %s: %w", m.base, err)
+	}
+
+	removed := []string{}
+	cutoff := time.Now().Add(-m.ttl)
+	for _, info := range infos {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+
+		path := filepath.Join(m.base, info.Name())
+		if path == m.dir || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if pid, ok := tempOwner(info.Name()); ok && processAlive(pid) {
+			continue
+		}
+
+		if err := m.fs.fs.RemoveAll(path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		removed = append(removed, path)
+	}
+
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// check fails once the manager is closed or ctx is done
+func (m *TempManager) check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return fmt.Errorf("temp manager is closed")
+	}
+	return nil
+}
+
+// handleSignals closes the manager on interrupt or termination and then
+// lets the signal take its usual effect
+func (m *TempManager) handleSignals() {
+	m.signals = make(chan os.Signal, 1)
+	m.done = make(chan struct{})
+	signal.Notify(m.signals, os.Interrupt, syscall.SIGTERM)
+
+	go func(signals chan os.Signal, done chan struct{}) {
+		select {
+		case sig := <-signals:
+			m.Close()
+			reraise(sig)
+		case <-done:
+		}
+	}(m.signals, m.done)
+}
+
+// tempOwner returns the PID a process directory is named after
+func tempOwner(name string) (int, bool) {
+	prefix, _, found := strings.Cut(name, "-")
+	if !found {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(prefix)
+	return pid, err == nil && pid > 0
+}
@@ -0,0 +1,100 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTempManager(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+
+	manager, err := NewTempManager(ctx, fs, "install", TempOptions{Dir: "/cache/tmp"})
+	if err != nil {
+		t.Fatalf("NewTempManager failed: %v", err)
+	}
+
+	if filepath.Dir(manager.Dir()) != "/cache/tmp/install" {
+		t.Errorf("Expected process directory under the namespace, got %s", manager.Dir())
+	}
+
+	dir, err := manager.TempDir(ctx, "download-")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	file, err := manager.TempFile(ctx, "archive-*.zip")
+	if err != nil {
+		t.Fatalf("TempFile failed: %v", err)
+	}
+	file.Close()
+	if filepath.Ext(file.Name()) != ".zip" {
+		t.Errorf("Expected pattern to be followed, got %s", file.Name())
+	}
+
+	if err := manager.Remove(ctx, dir); err != nil {
+		t.Errorf("Remove failed: %v", err)
+	}
+	if exists, _ := fs.Exists(ctx, dir); exists {
+		t.Error("Expected removed directory to be gone")
+	}
+	if err := manager.Remove(ctx, "/etc"); err == nil {
+		t.Error("Expected removing an unmanaged path to fail")
+	}
+
+	if err := manager.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if exists, _ := fs.Exists(ctx, manager.Dir()); exists {
+		t.Error("Expected Close to remove the process directory")
+	}
+	if err := manager.Close(); err != nil {
+		t.Errorf("Expected second Close to succeed, got %v", err)
+	}
+	if _, err := manager.TempDir(ctx, "late-"); err == nil {
+		t.Error("Expected TempDir to fail after Close")
+	}
+}
+
+func TestTempManager_Sweep(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+	base := "/cache/tmp/install"
+	old := time.Now().Add(-2 * time.Hour)
+
+	orphan := filepath.Join(base, "orphan")
+	live := filepath.Join(base, fmt.Sprintf("%d-live", os.Getpid()))
+	recent := filepath.Join(base, "recent")
+	for _, dir := range []string{orphan, live, recent} {
+		fs.MkdirAll(ctx, dir, 0700)
+	}
+	fs.fs.Chtimes(orphan, old, old)
+	fs.fs.Chtimes(live, old, old)
+
+	manager, err := NewTempManager(ctx, fs, "install", TempOptions{Dir: "/cache/tmp", TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("NewTempManager failed: %v", err)
+	}
+	defer manager.Close()
+
+	if exists, _ := fs.Exists(ctx, orphan); exists {
+		t.Error("Expected old orphan to be swept")
+	}
+	if exists, _ := fs.Exists(ctx, live); !exists {
+		t.Error("Expected directory of a running process to be kept")
+	}
+	if exists, _ := fs.Exists(ctx, recent); !exists {
+		t.Error("Expected recent directory to be kept")
+	}
+}
+
+func TestNewTempManager_InvalidNamespace(t *testing.T) {
+	for _, namespace := range []string{"", "..", "a/b"} {
+		if _, err := NewTempManager(context.Background(), NewMem(), namespace, TempOptions{Dir: "/tmp"}); err == nil {
+			t.Errorf("Expected namespace %q to be rejected", namespace)
+		}
+	}
+}
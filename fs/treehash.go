@@ -0,0 +1,116 @@
+package fs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+)
+
+// HashOptions configures HashTree. Globs are matched like those of
+// CopyOptions, against slash-separated paths relative to the tree root
+type HashOptions struct {
+	// Include selects the files to hash; empty hashes every file
+	Include []string
+	// Exclude skips matching files and directories, such as ".git"
+	Exclude []string
+}
+
+// TreeHash is the digest of a directory tree and the manifest of file
+// checksums it was computed from
+type TreeHash struct {
+	// Digest is the hex-encoded SHA-256 of the sorted manifest. It only
+	// depends on file paths and contents, not on timestamps, permissions
+	// or the order files were read in
+	Digest string `json:"digest"`
+	// Files holds the checksum of every file hashed
+	Files Manifest `json:"files"`
+}
+
+// TreeDiff lists the paths that differ between two manifests
+type TreeDiff struct {
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Modified []string `json:"modified"`
+}
+
+// Empty reports whether the manifests were identical
+func (d *TreeDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// HashTree computes a deterministic digest of the regular files under
+// dir, hashing them in parallel. Comparing digests tells whether a tree
+// changed, for cache invalidation; DiffTrees on the manifests tells what
+// changed
+func (fs *FS) HashTree(ctx context.Context, dir string, options HashOptions) (*TreeHash, error) {
+	files, err := fs.listFilesFiltered(ctx, dir, options.Include, options.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := make(Manifest, len(files))
+	var mu sync.Mutex
+
+	err = fs.hashFiles(ctx, dir, files, func(rel, sum string) {
+		mu.Lock()
+		manifest[rel] = sum
+		mu.Unlock()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TreeHash{Digest: manifest.Digest(), Files: manifest}, nil
+}
+
+// Digest returns the hex-encoded SHA-256 of the manifest entries in path
+// order
+func (m Manifest) Digest() string {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	for _, path := range paths {
+		// NUL cannot appear in paths, so entries cannot run together
+		hash.Write([]byte(path))
+		hash.Write([]byte{0})
+		hash.Write([]byte(m[path]))
+		hash.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// DiffTrees returns the paths added, removed and modified going from
+// before to after, each sorted
+func DiffTrees(before, after Manifest) *TreeDiff {
+	diff := &TreeDiff{
+		Added:    []string{},
+		Removed:  []string{},
+		Modified: []string{},
+	}
+
+	for path, sum := range after {
+		previous, ok := before[path]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, path)
+		case previous != sum:
+			diff.Modified = append(diff.Modified, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			diff.Removed = append(diff.Removed, path)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Modified)
+	return diff
+}
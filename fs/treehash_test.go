@@ -0,0 +1,74 @@
+package fs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFS_HashTree(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+
+	fs.WriteFile(ctx, "/a/api.yaml", []byte("api"), 0644)
+	fs.WriteFile(ctx, "/a/policies/p.yaml", []byte("policy"), 0644)
+	fs.WriteFile(ctx, "/a/.git/HEAD", []byte("ref"), 0644)
+
+	// Same contents written in another order and with other modes
+	fs.WriteFile(ctx, "/b/policies/p.yaml", []byte("policy"), 0600)
+	fs.WriteFile(ctx, "/b/api.yaml", []byte("api"), 0755)
+
+	options := HashOptions{Exclude: []string{".git"}}
+	a, err := fs.HashTree(ctx, "/a", options)
+	if err != nil {
+		t.Fatalf("HashTree failed: %v", err)
+	}
+	b, err := fs.HashTree(ctx, "/b", options)
+	if err != nil {
+		t.Fatalf("HashTree failed: %v", err)
+	}
+	if a.Digest != b.Digest {
+		t.Errorf("Expected equal digests, got %s and %s", a.Digest, b.Digest)
+	}
+	if len(a.Files) != 2 {
+		t.Errorf("Expected 2 files hashed, got %v", a.Files)
+	}
+
+	fs.WriteFile(ctx, "/b/api.yaml", []byte("changed"), 0644)
+	changed, _ := fs.HashTree(ctx, "/b", options)
+	if changed.Digest == a.Digest {
+		t.Error("Expected digest to change with file contents")
+	}
+
+	yaml, _ := fs.HashTree(ctx, "/a", HashOptions{Include: []string{"policies/*.yaml"}})
+	if len(yaml.Files) != 1 || yaml.Files["policies/p.yaml"] == "" {
+		t.Errorf("Expected only policies/p.yaml, got %v", yaml.Files)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := fs.HashTree(cancelled, "/a", options); err == nil {
+		t.Error("Expected error for cancelled context")
+	}
+}
+
+func TestDiffTrees(t *testing.T) {
+	before := Manifest{"kept": "1", "changed": "2", "removed": "3"}
+	after := Manifest{"kept": "1", "changed": "4", "added": "5"}
+
+	diff := DiffTrees(before, after)
+	want := &TreeDiff{
+		Added:    []string{"added"},
+		Removed:  []string{"removed"},
+		Modified: []string{"changed"},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Expected %+v, got %+v", want, diff)
+	}
+	if diff.Empty() {
+		t.Error("Expected diff not to be empty")
+	}
+	if !DiffTrees(before, before).Empty() {
+		t.Error("Expected identical manifests to have an empty diff")
+	}
+}
@@ -0,0 +1,147 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/spf13/afero"
+)
+
+// ErrInsufficientSpace is returned by EnsureFreeSpace when a filesystem
+// has less room than needed
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+// DirSize returns the total size in bytes of the regular files under
+// path, reading directories in parallel. Symlinks are not followed
+func (fs *FS) DirSize(ctx context.Context, path string) (int64, error) {
+	info, err := fs.lstat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		if info.Mode().IsRegular() {
+			return info.Size(), nil
+		}
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		total    int64
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		// sem bounds the directories read at once
+		sem = make(chan struct{}, runtime.NumCPU()*2)
+	)
+
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	var visit func(dir string)
+	visit = func(dir string) {
+		defer wg.Done()
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		infos, err := afero.ReadDir(fs.fs, dir)
+		<-sem
+		if err != nil {
+			fail(fmt.Errorf("failed to read %s: %w", dir, err))
+			return
+		}
+
+		for _, info := range infos {
+			switch {
+			case info.IsDir():
+				wg.Add(1)
+				go visit(filepath.Join(dir, info.Name()))
+			case info.Mode().IsRegular():
+				atomic.AddInt64(&total, info.Size())
+			}
+		}
+	}
+
+	wg.Add(1)
+	visit(path)
+	wg.Wait()
+
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// DiskSpace describes the capacity of the filesystem holding a path
+type DiskSpace struct {
+	// Total is the size of the filesystem in bytes
+	Total uint64 `json:"total"`
+	// Free is the number of free bytes, including those reserved for the
+	// superuser
+	Free uint64 `json:"free"`
+	// Available is the number of bytes the current user can write
+	Available uint64 `json:"available"`
+}
+
+// FreeSpace returns the bytes available to the current user on the
+// filesystem holding path
+func FreeSpace(path string) (uint64, error) {
+	space, err := GetDiskSpace(path)
+	if err != nil {
+		return 0, err
+	}
+	return space.Available, nil
+}
+
+// GetDiskSpace returns the capacity of the filesystem holding path
+func GetDiskSpace(path string) (*DiskSpace, error) {
+	space, err := diskSpace(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk space of %s: %w", path, err)
+	}
+	return space, nil
+}
+
+// EnsureFreeSpace fails with ErrInsufficientSpace unless the filesystem
+// holding path has at least need bytes available, as a check before a
+// download or extraction. path may not exist yet; its nearest existing
+// parent is checked
+func EnsureFreeSpace(path string, need uint64) error {
+	for {
+		if _, err := os.Stat(path); err == nil || !os.IsNotExist(err) {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+
+	available, err := FreeSpace(path)
+	if err != nil {
+		return err
+	}
+	if available < need {
+		return fmt.Errorf("need %d bytes in %s, %d available: %w", need, path, available, ErrInsufficientSpace)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package fs
+
+import (
+	"errors"
+	"runtime"
+)
+
+func diskSpace(path string) (*DiskSpace, error) {
+	return nil, errors.New("disk space is not supported on " + runtime.GOOS)
+}
@@ -0,0 +1,72 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestFS_DirSize(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+
+	var want int64
+	for i := 0; i < 20; i++ {
+		data := make([]byte, i*10)
+		fs.WriteFile(ctx, fmt.Sprintf("/cache/d%d/sub/f%d", i%4, i), data, 0644)
+		want += int64(len(data))
+	}
+
+	size, err := fs.DirSize(ctx, "/cache")
+	if err != nil {
+		t.Fatalf("DirSize failed: %v", err)
+	}
+	if size != want {
+		t.Errorf("Expected %d bytes, got %d", want, size)
+	}
+
+	if size, _ := fs.DirSize(ctx, "/cache/d1/sub/f1"); size != 10 {
+		t.Errorf("Expected the size of a single file, got %d", size)
+	}
+	if _, err := fs.DirSize(ctx, "/missing"); err == nil {
+		t.Error("Expected an error for a missing path")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := fs.DirSize(cancelled, "/cache"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFreeSpace(t *testing.T) {
+	dir := t.TempDir()
+
+	space, err := GetDiskSpace(dir)
+	if err != nil {
+		t.Skipf("disk space not supported: %v", err)
+	}
+	if space.Total == 0 || space.Available > space.Total {
+		t.Errorf("Unexpected disk space %+v", space)
+	}
+
+	available, err := FreeSpace(dir)
+	if err != nil {
+		t.Fatalf("FreeSpace failed: %v", err)
+	}
+	if available == 0 {
+		t.Error("Expected some free space in a temp directory")
+	}
+
+	// Paths that do not exist yet are checked through their parent
+	target := filepath.Join(dir, "not", "yet", "created")
+	if err := EnsureFreeSpace(target, 1); err != nil {
+		t.Errorf("Expected 1 byte to be available, got %v", err)
+	}
+	if err := EnsureFreeSpace(target, math.MaxUint64); !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("Expected ErrInsufficientSpace, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build linux || darwin || freebsd
+
+package fs
+
+import "syscall"
+
+func diskSpace(path string) (*DiskSpace, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return nil, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return &DiskSpace{
+		Total:     uint64(stat.Blocks) * blockSize,
+		Free:      uint64(stat.Bfree) * blockSize,
+		Available: uint64(stat.Bavail) * blockSize,
+	}, nil
+}
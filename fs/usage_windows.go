@@ -0,0 +1,29 @@
+//go:build windows
+
+package fs
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+func diskSpace(path string) (*DiskSpace, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var available, total, free uint64
+	ok, _, err := procGetDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(name)),
+		uintptr(unsafe.Pointer(&available)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&free)),
+	)
+	if ok == 0 {
+		return nil, err
+	}
+	return &DiskSpace{Total: total, Free: free, Available: available}, nil
+}
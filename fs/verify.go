@@ -0,0 +1,219 @@
+package fs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// Manifest maps slash-separated paths relative to a tree root to the
+// hex-encoded SHA-256 checksum of the file contents
+type Manifest map[string]string
+
+// VerifyReport describes how a directory tree differs from a manifest
+type VerifyReport struct {
+	// Missing lists files present in the manifest but not in the tree
+	Missing []string `json:"missing"`
+	// Modified lists files whose checksum does not match the manifest
+	Modified []string `json:"modified"`
+	// Extra lists files present in the tree but not in the manifest
+	Extra []string `json:"extra"`
+}
+
+// OK reports whether the tree matches the manifest exactly
+func (r *VerifyReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Modified) == 0 && len(r.Extra) == 0
+}
+
+// BuildManifest computes a manifest for every regular file under root
+func (fs *FS) BuildManifest(ctx context.Context, root string) (Manifest, error) {
+	files, err := fs.listFiles(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := make(Manifest, len(files))
+	var mu sync.Mutex
+
+	err = fs.hashFiles(ctx, root, files, func(rel, sum string) {
+		mu.Lock()
+		manifest[rel] = sum
+		mu.Unlock()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// VerifyTree compares the files under root against a manifest of expected
+// checksums, hashing files in parallel
+func (fs *FS) VerifyTree(ctx context.Context, root string, manifest Manifest) (*VerifyReport, error) {
+	files, err := fs.listFiles(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &VerifyReport{
+		Missing:  []string{},
+		Modified: []string{},
+		Extra:    []string{},
+	}
+
+	present := make(map[string]bool, len(files))
+	toHash := make([]string, 0, len(files))
+	for _, rel := range files {
+		present[rel] = true
+		if _, expected := manifest[rel]; expected {
+			toHash = append(toHash, rel)
+		} else {
+			report.Extra = append(report.Extra, rel)
+		}
+	}
+
+	for rel := range manifest {
+		if !present[rel] {
+			report.Missing = append(report.Missing, rel)
+		}
+	}
+
+	var mu sync.Mutex
+	err = fs.hashFiles(ctx, root, toHash, func(rel, sum string) {
+		if !strings.EqualFold(sum, manifest[rel]) {
+			mu.Lock()
+			report.Modified = append(report.Modified, rel)
+			mu.Unlock()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(report.Missing)
+	sort.Strings(report.Modified)
+	sort.Strings(report.Extra)
+
+	return report, nil
+}
+
+// listFiles returns the slash-separated relative paths of regular files under root
+func (fs *FS) listFiles(ctx context.Context, root string) ([]string, error) {
+	return fs.listFilesFiltered(ctx, root, nil, nil)
+}
+
+// listFilesFiltered is listFiles keeping only files matching include, if
+// any, and skipping those under paths matching exclude
+func (fs *FS) listFilesFiltered(ctx context.Context, root string, include, exclude []string) ([]string, error) {
+	var files []string
+
+	err := afero.Walk(fs.fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." && matchAnyGlob(exclude, rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if len(include) > 0 && !matchAnyGlob(include, rel) {
+			return nil
+		}
+
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	return files, nil
+}
+
+// hashFiles hashes files with a worker pool, calling fn with each result
+func (fs *FS) hashFiles(ctx context.Context, root string, files []string, fn func(rel, sum string)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	workers := runtime.NumCPU()
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rel := range jobs {
+				sum, err := fs.hashFile(filepath.Join(root, filepath.FromSlash(rel)))
+				if err != nil {
+					once.Do(func() {
+						firstErr = fmt.Errorf("failed to hash %s: %w", rel, err)
+						cancel()
+					})
+					continue
+				}
+				fn(rel, sum)
+			}
+		}()
+	}
+
+feed:
+	for _, rel := range files {
+		select {
+		case jobs <- rel:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// hashFile returns the hex-encoded SHA-256 checksum of a file
+func (fs *FS) hashFile(path string) (string, error) {
+	file, err := fs.fs.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
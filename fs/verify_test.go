@@ -0,0 +1,64 @@
+package fs
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestFS_VerifyTree(t *testing.T) {
+	fs := NewMem()
+	ctx := context.Background()
+	root := "/ext"
+
+	fs.MkdirAll(ctx, filepath.Join(root, "bin"), 0755)
+	fs.WriteFile(ctx, filepath.Join(root, "manifest.yaml"), []byte("name: ext"), 0644)
+	fs.WriteFile(ctx, filepath.Join(root, "bin", "ext"), []byte("binary"), 0755)
+	fs.WriteFile(ctx, filepath.Join(root, "README.md"), []byte("readme"), 0644)
+
+	manifest, err := fs.BuildManifest(ctx, root)
+	if err != nil {
+		t.Fatalf("BuildManifest failed: %v", err)
+	}
+	if len(manifest) != 3 {
+		t.Fatalf("Expected 3 manifest entries, got %d", len(manifest))
+	}
+
+	report, err := fs.VerifyTree(ctx, root, manifest)
+	if err != nil {
+		t.Fatalf("VerifyTree failed: %v", err)
+	}
+	if !report.OK() {
+		t.Fatalf("Expected unchanged tree to verify, got %+v", report)
+	}
+
+	fs.WriteFile(ctx, filepath.Join(root, "bin", "ext"), []byte("tampered"), 0755)
+	fs.Remove(ctx, filepath.Join(root, "README.md"))
+	fs.WriteFile(ctx, filepath.Join(root, "extra.txt"), []byte("extra"), 0644)
+
+	report, err = fs.VerifyTree(ctx, root, manifest)
+	if err != nil {
+		t.Fatalf("VerifyTree failed: %v", err)
+	}
+
+	if len(report.Modified) != 1 || report.Modified[0] != "bin/ext" {
+		t.Errorf("Expected bin/ext to be modified, got %v", report.Modified)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "README.md" {
+		t.Errorf("Expected README.md to be missing, got %v", report.Missing)
+	}
+	if len(report.Extra) != 1 || report.Extra[0] != "extra.txt" {
+		t.Errorf("Expected extra.txt to be extra, got %v", report.Extra)
+	}
+}
+
+func TestFS_VerifyTreeCancelled(t *testing.T) {
+	fs := NewMem()
+	ctx, cancel := context.WithCancel(context.Background())
+	fs.WriteFile(context.Background(), "/tree/file", []byte("data"), 0644)
+	cancel()
+
+	if _, err := fs.VerifyTree(ctx, "/tree", Manifest{}); err == nil {
+		t.Error("Expected error for cancelled context")
+	}
+}
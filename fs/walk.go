@@ -0,0 +1,152 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+)
+
+// WalkEntry describes a path visited by WalkDirFollow
+type WalkEntry struct {
+	// Path is the path as reached from the root, which may run through
+	// symlinks
+	Path string
+	// Target is the real path with every symlink resolved
+	Target string
+	// Info describes the target, or the link itself when it is broken
+	Info os.FileInfo
+	// Depth is the number of directories between the root and Path, with
+	// the root at depth 0
+	Depth int
+	// Symlink reports whether Path itself is a symlink
+	Symlink bool
+	// Cycle reports a directory that is already being walked higher up,
+	// reached again through a symlink. It is not descended into
+	Cycle bool
+}
+
+// WalkFollowFunc is called for each entry visited by WalkDirFollow. err is
+// set when the entry could not be resolved or read, such as for a broken
+// symlink. Returning filepath.SkipDir for a directory skips its contents,
+// which is how a caller declines to follow a particular symlink;
+// returning filepath.SkipAll or any other error stops the walk
+type WalkFollowFunc func(entry WalkEntry, err error) error
+
+// WalkDirFollow walks the tree at root in lexical order, following
+// symlinks to directories and files. A directory reached again through a
+// symlink while it is still being walked is reported with Cycle set
+// instead of being walked forever
+func (fs *FS) WalkDirFollow(ctx context.Context, root string, fn WalkFollowFunc) error {
+	info, err := fs.fs.Stat(root)
+	entry := WalkEntry{Path: root, Info: info}
+	if err != nil {
+		return skipAll(fn(entry, err))
+	}
+	if entry.Target, err = fs.realPath(root); err != nil {
+		return skipAll(fn(entry, err))
+	}
+	if linkInfo, err := fs.lstat(root); err == nil {
+		entry.Symlink = linkInfo.Mode()&os.ModeSymlink != 0
+	}
+
+	return skipAll(fs.walkFollow(ctx, entry, map[string]bool{}, fn))
+}
+
+// walkFollow visits entry and, for a directory, its contents. ancestors
+// holds the real paths of the directories being walked
+func (fs *FS) walkFollow(ctx context.Context, entry WalkEntry, ancestors map[string]bool, fn WalkFollowFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	isDir := entry.Info.IsDir()
+	if isDir && ancestors[entry.Target] {
+		entry.Cycle = true
+	}
+
+	if err := fn(entry, nil); err != nil {
+		if err == filepath.SkipDir && isDir {
+			return nil
+		}
+		return err
+	}
+	if !isDir || entry.Cycle {
+		return nil
+	}
+
+	infos, err := afero.ReadDir(fs.fs, entry.Path)
+	if err != nil {
+		if err := fn(entry, fmt.Errorf("failed to read %s: %w", entry.Path, err)); err != nil && err != filepath.SkipDir {
+			return err
+		}
+		return nil
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+
+	ancestors[entry.Target] = true
+	defer delete(ancestors, entry.Target)
+
+	for _, info := range infos {
+		child := WalkEntry{
+			Path:  filepath.Join(entry.Path, info.Name()),
+			Info:  info,
+			Depth: entry.Depth + 1,
+		}
+
+		if linkInfo, err := fs.lstat(child.Path); err == nil {
+			child.Info = linkInfo
+			child.Symlink = linkInfo.Mode()&os.ModeSymlink != 0
+		}
+
+		if child.Symlink {
+			target, err := fs.fs.Stat(child.Path)
+			if err != nil {
+				if err := fn(child, fmt.Errorf("failed to follow %s: %w", child.Path, err)); err != nil && err != filepath.SkipDir {
+					return err
+				}
+				continue
+			}
+			child.Info = target
+		}
+
+		real, err := fs.realPath(child.Path)
+		if err != nil {
+			if err := fn(child, fmt.Errorf("failed to resolve %s: %w", child.Path, err)); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+		child.Target = real
+
+		if err := fs.walkFollow(ctx, child, ancestors, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// realPath resolves every symlink in path. Filesystems without symlinks
+// only clean it
+func (fs *FS) realPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := fs.fs.(*afero.OsFs); ok {
+		return filepath.EvalSymlinks(abs)
+	}
+	return abs, nil
+}
+
+// skipAll turns filepath.SkipAll, and SkipDir returned for the root, into
+// a clean stop
+func skipAll(err error) error {
+	if err == filepath.SkipAll || err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
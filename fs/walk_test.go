@@ -0,0 +1,107 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFS_WalkDirFollow(t *testing.T) {
+	fs := New()
+	ctx := context.Background()
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	dev := filepath.Join(base, "devtree")
+
+	fs.WriteFile(ctx, filepath.Join(root, "plugins", "a"), []byte("a"), 0755)
+	fs.WriteFile(ctx, filepath.Join(dev, "hook.sh"), []byte("hook"), 0755)
+	if err := os.Symlink(dev, filepath.Join(root, "dev")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	os.Symlink(".", filepath.Join(root, "loop"))
+	os.Symlink(root, filepath.Join(dev, "back"))
+	os.Symlink("missing", filepath.Join(dev, "broken"))
+
+	type visit struct {
+		depth   int
+		symlink bool
+		cycle   bool
+		failed  bool
+	}
+	visits := make(map[string]visit)
+	err := fs.WalkDirFollow(ctx, root, func(entry WalkEntry, err error) error {
+		rel, _ := filepath.Rel(root, entry.Path)
+		visits[filepath.ToSlash(rel)] = visit{entry.Depth, entry.Symlink, entry.Cycle, err != nil}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDirFollow failed: %v", err)
+	}
+
+	want := map[string]visit{
+		".":           {depth: 0},
+		"dev":         {depth: 1, symlink: true},
+		"dev/back":    {depth: 2, symlink: true, cycle: true},
+		"dev/broken":  {depth: 2, symlink: true, failed: true},
+		"dev/hook.sh": {depth: 2},
+		"loop":        {depth: 1, symlink: true, cycle: true},
+		"plugins":     {depth: 1},
+		"plugins/a":   {depth: 2},
+	}
+	if len(visits) != len(want) {
+		t.Errorf("Expected %d entries, got %v", len(want), visits)
+	}
+	for path, expected := range want {
+		if got, ok := visits[path]; !ok || got != expected {
+			t.Errorf("%s: expected %+v, got %+v (visited: %v)", path, expected, got, ok)
+		}
+	}
+}
+
+func TestFS_WalkDirFollow_Skip(t *testing.T) {
+	fs := New()
+	ctx := context.Background()
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	shared := filepath.Join(base, "shared")
+
+	fs.WriteFile(ctx, filepath.Join(root, "local", "hook"), []byte("hook"), 0755)
+	fs.WriteFile(ctx, filepath.Join(shared, "hook"), []byte("hook"), 0755)
+	if err := os.Symlink(shared, filepath.Join(root, "shared")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	var targets []string
+	err := fs.WalkDirFollow(ctx, root, func(entry WalkEntry, err error) error {
+		if entry.Symlink && entry.Info.IsDir() {
+			return filepath.SkipDir
+		}
+		if !entry.Info.IsDir() {
+			targets = append(targets, entry.Target)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDirFollow failed: %v", err)
+	}
+	realRoot, _ := filepath.EvalSymlinks(root)
+	if len(targets) != 1 || targets[0] != filepath.Join(realRoot, "local", "hook") {
+		t.Errorf("Expected only the local hook, got %v", targets)
+	}
+
+	stop := errors.New("stop")
+	if err := fs.WalkDirFollow(ctx, root, func(WalkEntry, error) error { return stop }); err != stop {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+	if err := fs.WalkDirFollow(ctx, root, func(WalkEntry, error) error { return filepath.SkipAll }); err != nil {
+		t.Errorf("Expected SkipAll to stop cleanly, got %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := fs.WalkDirFollow(cancelled, root, func(WalkEntry, error) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package fs
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// DefaultWatchExcludes are directories rarely worth watching, for use with
+// WithExclude
+var DefaultWatchExcludes = []string{".git", "node_modules"}
+
+// WithRecursive makes Watch register every subdirectory of the watched
+// path, along with directories created under it while watching
+func WithRecursive() WatcherOption {
+	return func(w *Watcher) {
+		w.recursive = true
+	}
+}
+
+// WithInclude only delivers events for paths matching one of the globs.
+// Globs use forward slashes and are matched against the path relative to
+// the watched root, with "**" matching any number of directories, as in
+// "**/*.yaml". Globs without a slash match the name at any depth
+func WithInclude(patterns ...string) WatcherOption {
+	return func(w *Watcher) {
+		w.include = append(w.include, patterns...)
+	}
+}
+
+// WithExclude drops events for paths matching one of the globs, or lying
+// under a directory that does. Matching directories are not watched at
+// all, which keeps event volume down for trees such as .git
+func WithExclude(patterns ...string) WatcherOption {
+	return func(w *Watcher) {
+		w.exclude = append(w.exclude, patterns...)
+	}
+}
+
+// addTree watches root and its subdirectories, skipping excluded ones. It
+// returns the files found, so events can be raised for files created in a
+// new directory before it was watched
+func (w *Watcher) addTree(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && w.excluded(path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			return nil
+		}
+
+		if err := w.add(path); err != nil {
+			w.logger.Warn("Failed to watch directory",
+				zap.String("path", path),
+				zap.Error(err))
+		} else {
+			w.logger.Debug("Watching directory", zap.String("path", path))
+		}
+		return nil
+	})
+	return files, err
+}
+
+// watchNewDirectory watches a directory created under a recursive root and
+// returns Create events for what it already holds
+func (w *Watcher) watchNewDirectory(event fsnotify.Event) []fsnotify.Event {
+	if event.Op&fsnotify.Create == 0 {
+		return nil
+	}
+	if _, recursive, ok := w.root(event.Name); !ok || !recursive || w.excluded(event.Name) {
+		return nil
+	}
+	info, err := os.Stat(event.Name)
+	if err != nil || !info.IsDir() {
+		return nil
+	}
+
+	files, err := w.addTree(event.Name)
+	if err != nil {
+		w.logger.Warn("Failed to watch new directory",
+			zap.String("path", event.Name),
+			zap.Error(err))
+	}
+
+	events := make([]fsnotify.Event, 0, len(files))
+	for _, file := range files {
+		events = append(events, fsnotify.Event{Name: file, Op: fsnotify.Create})
+	}
+	return events
+}
+
+// root returns the watched root holding name, preferring the deepest one,
+// and whether it is watched recursively
+func (w *Watcher) root(name string) (string, bool, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var (
+		best      string
+		recursive bool
+		found     bool
+	)
+	for root, isRecursive := range w.roots {
+		if isWithin(name, root) && (!found || len(root) > len(best)) {
+			best, recursive, found = root, isRecursive, true
+		}
+	}
+	return best, recursive, found
+}
+
+// relPath returns name relative to its watched root, with forward slashes
+func (w *Watcher) relPath(name string) string {
+	root, _, ok := w.root(name)
+	if !ok || root == name {
+		return filepath.Base(name)
+	}
+	rel, err := filepath.Rel(root, name)
+	if err != nil {
+		return filepath.Base(name)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// excluded reports whether name or one of its parents below the root
+// matches an exclude glob
+func (w *Watcher) excluded(name string) bool {
+	if len(w.exclude) == 0 {
+		return false
+	}
+	for rel := w.relPath(name); rel != "." && rel != "/"; rel = path.Dir(rel) {
+		if matchAnyGlob(w.exclude, rel) {
+			return true
+		}
+		if !strings.Contains(rel, "/") {
+			break
+		}
+	}
+	return false
+}
+
+// filtered reports whether an event should be dropped by the include and
+// exclude globs
+func (w *Watcher) filtered(name string) bool {
+	if w.excluded(name) {
+		return true
+	}
+	if len(w.include) == 0 {
+		return false
+	}
+	return !matchAnyGlob(w.include, w.relPath(name))
+}
@@ -42,29 +42,57 @@ type Watcher struct {
 	ctx      context.Context
 	cancel   context.CancelFunc
 	wg       sync.WaitGroup
+	debounce time.Duration
+
+	// recursive makes Watch register subdirectories
+	recursive bool
+	include   []string
+	exclude   []string
+	// roots maps each watched path to whether it is watched recursively
+	roots map[string]bool
+
+	// poller watches paths fsnotify cannot; watcher is nil when fsnotify
+	// is unavailable or polling is forced
+	poller       *poller
+	pollForced   bool
+	pollInterval time.Duration
+	pollCompare  PollCompare
 }
 
-// NewWatcher creates a new file watcher
-func NewWatcher() (*Watcher, error) {
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create file watcher")
-	}
+// WatcherOption configures a Watcher
+type WatcherOption func(*Watcher)
 
+// NewWatcher creates a new file watcher. It falls back to polling when
+// fsnotify is unavailable, such as when inotify limits are exhausted
+func NewWatcher(options ...WatcherOption) (*Watcher, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Watcher{
-		watcher:  watcher,
-		handlers: make(map[string][]WatchHandler),
-		logger:   zap.NewNop(), // Default to no-op logger
-		ctx:      ctx,
-		cancel:   cancel,
-	}, nil
+	w := &Watcher{
+		handlers:     make(map[string][]WatchHandler),
+		logger:       zap.NewNop(), // Default to no-op logger
+		ctx:          ctx,
+		cancel:       cancel,
+		roots:        make(map[string]bool),
+		pollInterval: DefaultPollInterval,
+	}
+	for _, option := range options {
+		option(w)
+	}
+	w.poller = newPoller(w.pollInterval, w.pollCompare)
+
+	if !w.pollForced {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			w.logger.Warn("File notifications unavailable, polling instead", zap.Error(err))
+		}
+		w.watcher = watcher
+	}
+	return w, nil
 }
 
 // NewWatcherWithLogger creates a new file watcher with a logger
-func NewWatcherWithLogger(logger *zap.Logger) (*Watcher, error) {
-	w, err := NewWatcher()
+func NewWatcherWithLogger(logger *zap.Logger, options ...WatcherOption) (*Watcher, error) {
+	w, err := NewWatcher(options...)
 	if err != nil {
 		return nil, err
 	}
@@ -88,47 +116,41 @@ func (w *Watcher) AddHandlerFunc(pathPattern string, handlerFunc func(ctx contex
 	w.AddHandler(pathPattern, WatchHandlerFunc(handlerFunc))
 }
 
-// Watch starts watching a directory or file
+// Watch starts watching a directory or file. With WithRecursive, every
+// subdirectory is watched too, including ones created later
 func (w *Watcher) Watch(path string) error {
+	if info, err := os.Stat(path); err == nil && info.IsDir() && w.recursive {
+		return w.WatchRecursive(path)
+	}
+
 	// Convert to absolute path
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return errors.Wrap(err, "failed to get absolute path")
 	}
 
-	if err := w.watcher.Add(absPath); err != nil {
+	if err := w.add(absPath); err != nil {
 		return errors.Wrapf(err, "failed to watch path: %s", absPath)
 	}
+	w.addRoot(absPath, false)
 
 	w.logger.Info("Started watching path", zap.String("path", absPath))
 	return nil
 }
 
-// WatchRecursive watches a directory and all its subdirectories
+// WatchRecursive watches a directory and all its subdirectories, including
+// ones created later. Directories matching WithExclude are skipped
 func (w *Watcher) WatchRecursive(rootPath string) error {
 	absPath, err := filepath.Abs(rootPath)
 	if err != nil {
 		return errors.Wrap(err, "failed to get absolute path")
 	}
 
-	// Walk the directory tree and add all directories
-	err = filepath.Walk(absPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			if err := w.watcher.Add(path); err != nil {
-				w.logger.Warn("Failed to watch directory",
-					zap.String("path", path),
-					zap.Error(err))
-			} else {
-				w.logger.Debug("Watching directory", zap.String("path", path))
-			}
-		}
-		return nil
-	})
+	// Register the root first so excludes are matched relative to it
+	w.addRoot(absPath, true)
 
-	if err != nil {
+	// Walk the directory tree and add all directories
+	if _, err := w.addTree(absPath); err != nil {
 		return errors.Wrapf(err, "failed to walk directory tree: %s", absPath)
 	}
 
@@ -136,6 +158,14 @@ func (w *Watcher) WatchRecursive(rootPath string) error {
 	return nil
 }
 
+// addRoot records a watched path
+func (w *Watcher) addRoot(path string, recursive bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.roots[path] = w.roots[path] || recursive
+}
+
 // Start begins the file watching loop
 func (w *Watcher) Start() {
 	w.wg.Add(1)
@@ -146,46 +176,105 @@ func (w *Watcher) Start() {
 func (w *Watcher) Stop() {
 	w.cancel()
 	w.wg.Wait()
-	w.watcher.Close()
+	if w.watcher != nil {
+		w.watcher.Close()
+	}
+	w.poller.Close()
+}
+
+// add watches a single path, polling it when fsnotify is unavailable or
+// the path is on a network filesystem
+func (w *Watcher) add(path string) error {
+	if w.watcher == nil || isNetworkFS(path) {
+		return w.poller.Add(path)
+	}
+	return w.watcher.Add(path)
 }
 
 // watchLoop is the main watching loop
 func (w *Watcher) watchLoop() {
 	defer w.wg.Done()
 
+	// Without debouncing, events are handled as they arrive
+	var debouncer *debouncer
+	if w.debounce > 0 {
+		debouncer = newDebouncer(w.debounce)
+		defer debouncer.timer.Stop()
+	}
+
+	// Polled paths are reported alongside fsnotify events
+	var notifyEvents <-chan fsnotify.Event
+	var notifyErrors <-chan error
+	if w.watcher != nil {
+		notifyEvents, notifyErrors = w.watcher.Events, w.watcher.Errors
+	}
+
 	for {
 		select {
 		case <-w.ctx.Done():
 			w.logger.Info("File watcher stopped")
 			return
-		case event, ok := <-w.watcher.Events:
+		case event, ok := <-notifyEvents:
 			if !ok {
 				w.logger.Warn("File watcher events channel closed")
 				return
 			}
-			w.handleEvent(event)
-		case err, ok := <-w.watcher.Errors:
+			w.receive(event, debouncer)
+		case event, ok := <-w.poller.events:
+			if !ok {
+				return
+			}
+			w.receive(event, debouncer)
+		case now := <-debouncer.C():
+			for _, event := range debouncer.due(now) {
+				w.dispatch(event)
+			}
+		case err, ok := <-notifyErrors:
 			if !ok {
 				w.logger.Warn("File watcher errors channel closed")
 				return
 			}
 			w.logger.Error("File watcher error", zap.Error(err))
+		case err, ok := <-w.poller.errors:
+			if !ok {
+				return
+			}
+			w.logger.Error("File poller error", zap.Error(err))
 		}
 	}
 }
 
-// handleEvent processes a file system event
-func (w *Watcher) handleEvent(event fsnotify.Event) {
-	watchEvent := WatchEvent{
-		Name:      event.Name,
-		Op:        event.Op,
-		Timestamp: time.Now(),
+// receive filters a raw event and passes it on, directly or through the
+// debouncer
+func (w *Watcher) receive(event fsnotify.Event, debouncer *debouncer) {
+	w.logEvent(event)
+
+	events := append([]fsnotify.Event{event}, w.watchNewDirectory(event)...)
+	for _, event := range events {
+		if w.filtered(event.Name) {
+			continue
+		}
+		if debouncer != nil {
+			debouncer.add(event, time.Now())
+			continue
+		}
+		w.dispatch(WatchEvent{
+			Name:      event.Name,
+			Op:        event.Op,
+			Timestamp: time.Now(),
+		})
 	}
+}
 
+// logEvent logs a raw file system event
+func (w *Watcher) logEvent(event fsnotify.Event) {
 	w.logger.Debug("File system event",
 		zap.String("name", event.Name),
 		zap.String("op", event.Op.String()))
+}
 
+// dispatch passes an event to the handlers matching its path
+func (w *Watcher) dispatch(event WatchEvent) {
 	// Find matching handlers
 	w.mu.RLock()
 	handlers := w.findMatchingHandlers(event.Name)
@@ -193,7 +282,7 @@ func (w *Watcher) handleEvent(event fsnotify.Event) {
 
 	// Execute handlers
 	for _, handler := range handlers {
-		if err := handler.HandleEvent(w.ctx, watchEvent); err != nil {
+		if err := handler.HandleEvent(w.ctx, event); err != nil {
 			w.logger.Error("Handler error",
 				zap.String("path", event.Name),
 				zap.Error(err))
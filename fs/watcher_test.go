@@ -2,6 +2,7 @@ package fs
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -283,3 +284,168 @@ func TestWatchHandlerFunc(t *testing.T) {
 		t.Fatal("Handler function should have been called")
 	}
 }
+
+func TestWatcher_Debounce(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("v1"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	watcher, err := NewWatcher(WithDebounce(100 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	defer watcher.Stop()
+
+	var events []WatchEvent
+	var mu sync.Mutex
+	watcher.AddHandlerFunc("*", func(ctx context.Context, event WatchEvent) error {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+		return nil
+	})
+
+	if err := watcher.Watch(dir); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	watcher.Start()
+
+	// A burst of writes to one file and a short-lived swap file
+	for i := 0; i < 5; i++ {
+		os.WriteFile(file, []byte(fmt.Sprintf("v%d", i+2)), 0644)
+		time.Sleep(10 * time.Millisecond)
+	}
+	swap := filepath.Join(dir, ".config.yaml.swp")
+	os.WriteFile(swap, []byte("swap"), 0644)
+	os.Remove(swap)
+
+	time.Sleep(400 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 coalesced event, got %d: %+v", len(events), events)
+	}
+	if events[0].Name != file || events[0].Op != fsnotify.Write {
+		t.Errorf("Expected Write of %s, got %+v", file, events[0])
+	}
+}
+
+func TestDebouncer_FinalState(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing")
+	created := filepath.Join(dir, "created")
+	os.WriteFile(existing, []byte("data"), 0644)
+	os.WriteFile(created, []byte("data"), 0644)
+
+	d := newDebouncer(50 * time.Millisecond)
+	defer d.timer.Stop()
+	start := time.Now()
+
+	d.add(fsnotify.Event{Name: created, Op: fsnotify.Create}, start)
+	d.add(fsnotify.Event{Name: created, Op: fsnotify.Write}, start)
+	d.add(fsnotify.Event{Name: existing, Op: fsnotify.Chmod}, start)
+	d.add(fsnotify.Event{Name: filepath.Join(dir, "renamed"), Op: fsnotify.Rename}, start)
+	d.add(fsnotify.Event{Name: filepath.Join(dir, "removed"), Op: fsnotify.Write}, start)
+	d.add(fsnotify.Event{Name: filepath.Join(dir, "removed"), Op: fsnotify.Remove}, start)
+
+	if events := d.due(start.Add(10 * time.Millisecond)); len(events) != 0 {
+		t.Fatalf("Expected no events before the quiet period, got %+v", events)
+	}
+
+	events := d.due(start.Add(50 * time.Millisecond))
+	want := []fsnotify.Op{fsnotify.Create, fsnotify.Chmod, fsnotify.Rename, fsnotify.Remove}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+	for i, op := range want {
+		if events[i].Op != op {
+			t.Errorf("Expected %s for %s, got %s", op, events[i].Name, events[i].Op)
+		}
+	}
+}
+
+func TestWatcher_RecursiveFilters(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "apis", "v1"), 0755)
+	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+
+	watcher, err := NewWatcher(
+		WithRecursive(),
+		WithInclude("**/*.yaml"),
+		WithExclude(DefaultWatchExcludes...),
+	)
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	defer watcher.Stop()
+
+	seen := make(map[string]bool)
+	var mu sync.Mutex
+	watcher.AddHandlerFunc("*", func(ctx context.Context, event WatchEvent) error {
+		mu.Lock()
+		seen[event.Name] = true
+		mu.Unlock()
+		return nil
+	})
+
+	if err := watcher.Watch(dir); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	watcher.Start()
+
+	nested := filepath.Join(dir, "apis", "v1", "api.yaml")
+	ignored := filepath.Join(dir, "apis", "v1", "notes.txt")
+	gitFile := filepath.Join(dir, ".git", "config.yaml")
+	os.WriteFile(nested, []byte("a"), 0644)
+	os.WriteFile(ignored, []byte("b"), 0644)
+	os.WriteFile(gitFile, []byte("c"), 0644)
+
+	// Directories created while watching are picked up, along with files
+	// written into them before their watch was added
+	created := filepath.Join(dir, "policies", "nested")
+	os.MkdirAll(created, 0755)
+	newFile := filepath.Join(created, "policy.yaml")
+	os.WriteFile(newFile, []byte("d"), 0644)
+
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !seen[nested] {
+		t.Errorf("Expected event for %s", nested)
+	}
+	if !seen[newFile] {
+		t.Errorf("Expected event for %s in a new directory", newFile)
+	}
+	if seen[ignored] {
+		t.Errorf("Expected %s to be filtered by include", ignored)
+	}
+	if seen[gitFile] {
+		t.Errorf("Expected %s to be excluded", gitFile)
+	}
+}
+
+func TestWatcher_Excluded(t *testing.T) {
+	watcher, err := NewWatcher(WithExclude("node_modules", "build/**"))
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	defer watcher.Stop()
+	watcher.addRoot("/project", true)
+
+	tests := map[string]bool{
+		"/project/node_modules":          true,
+		"/project/web/node_modules/x.js": true,
+		"/project/build/out/app":         true,
+		"/project/src/build.go":          false,
+		"/project/config.yaml":           false,
+	}
+	for name, want := range tests {
+		if got := watcher.excluded(name); got != want {
+			t.Errorf("excluded(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+//go:build linux
+
+package fs
+
+import (
+	"bytes"
+	"fmt"
+	"syscall"
+)
+
+// copyXattrs copies the extended attributes of src to dst
+func copyXattrs(src, dst string) error {
+	size, err := syscall.Listxattr(src, nil)
+	if err == syscall.ENOTSUP {
+		return errXattrsUnsupported
+	}
+	if err != nil || size == 0 {
+		return err
+	}
+
+	list := make([]byte, size)
+	if size, err = syscall.Listxattr(src, list); err != nil {
+		return err
+	}
+
+	for _, name := range bytes.Split(list[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		attr := string(name)
+
+		size, err := syscall.Getxattr(src, attr, nil)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", attr, err)
+		}
+		value := make([]byte, size)
+		if size, err = syscall.Getxattr(src, attr, value); err != nil {
+			return fmt.Errorf("failed to read %s: %w", attr, err)
+		}
+		if err := syscall.Setxattr(dst, attr, value[:size], 0); err != nil {
+			return fmt.Errorf("failed to set %s: %w", attr, err)
+		}
+	}
+	return nil
+}
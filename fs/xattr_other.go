@@ -0,0 +1,9 @@
+//go:build !linux
+
+package fs
+
+// copyXattrs copies extended attributes, which is only implemented on
+// Linux
+func copyXattrs(src, dst string) error {
+	return errXattrsUnsupported
+}
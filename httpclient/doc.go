@@ -5,6 +5,7 @@
 //   - Context Support: Full context.Context integration for cancellation and timeouts
 //   - Header Management: Easy header setting and management
 //   - JSON Support: Built-in JSON marshaling and unmarshaling
+//   - JSON Streaming: Element-by-element decoding of large JSON arrays
 //   - Response Handling: Rich response objects with status code checking
 //   - Error Handling: Comprehensive error handling with HTTP status codes
 //   - Base URL Support: Configurable base URLs for API endpoints
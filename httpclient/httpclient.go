@@ -433,6 +433,51 @@ func (c *Client) PostResponseWithContext(ctx context.Context, path string, data
 	return c.doRequestWithResponse(req)
 }
 
+// PutResponse makes a PUT request and returns the full response
+func (c *Client) PutResponse(path string, data []byte) (*Response, error) {
+	return c.PutResponseWithContext(context.Background(), path, data)
+}
+
+// PutResponseWithContext makes a PUT request with context and returns the full response
+func (c *Client) PutResponseWithContext(ctx context.Context, path string, data []byte) (*Response, error) {
+	req, err := c.newRequest(ctx, "PUT", path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doRequestWithResponse(req)
+}
+
+// DeleteResponse makes a DELETE request and returns the full response
+func (c *Client) DeleteResponse(path string) (*Response, error) {
+	return c.DeleteResponseWithContext(context.Background(), path)
+}
+
+// DeleteResponseWithContext makes a DELETE request with context and returns the full response
+func (c *Client) DeleteResponseWithContext(ctx context.Context, path string) (*Response, error) {
+	req, err := c.newRequest(ctx, "DELETE", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doRequestWithResponse(req)
+}
+
+// PatchResponse makes a PATCH request and returns the full response
+func (c *Client) PatchResponse(path string, data []byte) (*Response, error) {
+	return c.PatchResponseWithContext(context.Background(), path, data)
+}
+
+// PatchResponseWithContext makes a PATCH request with context and returns the full response
+func (c *Client) PatchResponseWithContext(ctx context.Context, path string, data []byte) (*Response, error) {
+	req, err := c.newRequest(ctx, "PATCH", path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doRequestWithResponse(req)
+}
+
 // doRequestWithResponse executes an HTTP request and returns the full response
 func (c *Client) doRequestWithResponse(req *http.Request) (*Response, error) {
 	resp, err := c.httpClient.Do(req)
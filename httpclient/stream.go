@@ -0,0 +1,114 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBodySize limits how much of an error response is included in the error
+const maxErrorBodySize = 64 * 1024
+
+// GetJSONStream makes a GET request for a JSON array and calls fn with each
+// element as it is decoded, so large responses are never buffered in full.
+// Streaming stops at the first error returned by fn. The client timeout
+// covers the whole response, so use ctx rather than a short timeout to bound
+// long exports.
+func (c *Client) GetJSONStream(ctx context.Context, path string, fn func(json.RawMessage) error) error {
+	return c.GetJSONStreamField(ctx, path, "", fn)
+}
+
+// GetJSONStreamField is like GetJSONStream for responses that wrap the array
+// in an object, such as {"apis": [...], "pages": 3}. Elements of the array
+// under the top-level key field are streamed; an empty field expects the
+// response itself to be an array.
+func (c *Client) GetJSONStreamField(ctx context.Context, path, field string, fn func(json.RawMessage) error) error {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	return decodeJSONStream(resp.Body, field, fn)
+}
+
+// decodeJSONStream decodes the elements of a JSON array one at a time
+func decodeJSONStream(r io.Reader, field string, fn func(json.RawMessage) error) error {
+	decoder := json.NewDecoder(r)
+
+	if field != "" {
+		if err := seekField(decoder, field); err != nil {
+			return err
+		}
+	}
+
+	if err := expectDelim(decoder, '['); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		var element json.RawMessage
+		if err := decoder.Decode(&element); err != nil {
+			return fmt.Errorf("failed to decode array element: %w", err)
+		}
+		if err := fn(element); err != nil {
+			return err
+		}
+	}
+
+	if err := expectDelim(decoder, ']'); err != nil {
+		return err
+	}
+	return nil
+}
+
+// seekField advances the decoder to the value of a top-level object key,
+// skipping over the values of other keys
+func seekField(decoder *json.Decoder, field string) error {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode JSON stream: %w", err)
+		}
+		if key, ok := token.(string); ok && key == field {
+			return nil
+		}
+
+		var skipped json.RawMessage
+		if err := decoder.Decode(&skipped); err != nil {
+			return fmt.Errorf("failed to decode JSON stream: %w", err)
+		}
+	}
+	return fmt.Errorf("field %q not found in response", field)
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode JSON stream: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("expected %q in JSON stream, got %v", delim, token)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetJSONStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/apis":
+			fmt.Fprint(w, `[{"api_id":"1"},{"api_id":"2"},{"api_id":"3"}]`)
+		case "/wrapped":
+			fmt.Fprint(w, `{"pages":1,"meta":{"skip":[1,2]},"apis":[{"api_id":"a"},{"api_id":"b"}]}`)
+		case "/object":
+			fmt.Fprint(w, `{"api_id":"1"}`)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL(server.URL)
+	ctx := context.Background()
+
+	collect := func(path, field string) ([]string, error) {
+		var ids []string
+		err := client.GetJSONStreamField(ctx, path, field, func(raw json.RawMessage) error {
+			var api struct {
+				APIID string `json:"api_id"`
+			}
+			if err := json.Unmarshal(raw, &api); err != nil {
+				return err
+			}
+			ids = append(ids, api.APIID)
+			return nil
+		})
+		return ids, err
+	}
+
+	ids, err := collect("/apis", "")
+	if err != nil {
+		t.Fatalf("GetJSONStream failed: %v", err)
+	}
+	if strings.Join(ids, ",") != "1,2,3" {
+		t.Errorf("Expected 1,2,3, got %v", ids)
+	}
+
+	ids, err = collect("/wrapped", "apis")
+	if err != nil {
+		t.Fatalf("GetJSONStreamField failed: %v", err)
+	}
+	if strings.Join(ids, ",") != "a,b" {
+		t.Errorf("Expected a,b, got %v", ids)
+	}
+
+	if _, err := collect("/wrapped", "missing"); err == nil {
+		t.Error("Expected error for missing field")
+	}
+	if _, err := collect("/object", ""); err == nil {
+		t.Error("Expected error for non-array response")
+	}
+	if _, err := collect("/unknown", ""); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("Expected HTTP 404 error, got %v", err)
+	}
+}
+
+func TestGetJSONStreamStopsOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[1,2,3,4]`)
+	}))
+	defer server.Close()
+
+	stop := errors.New("stop")
+	calls := 0
+	err := NewWithBaseURL(server.URL).GetJSONStream(context.Background(), "/", func(json.RawMessage) error {
+		calls++
+		if calls == 2 {
+			return stop
+		}
+		return nil
+	})
+
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
@@ -0,0 +1,133 @@
+package jq
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+// Option configures how a JQ program is run
+type Option func(*processConfig)
+
+type processConfig struct {
+	stream    bool
+	nullInput bool
+	slurp     bool
+	rawOutput bool
+	compact   bool
+	indent    *string
+	color     *bool
+	names     []string
+	values    map[string]interface{}
+	err       error
+}
+
+func newProcessConfig(options []Option) *processConfig {
+	config := &processConfig{values: make(map[string]interface{})}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// WithArg binds $name to a string, like jq --arg. The value is never
+// interpolated into the program, so it needs no quoting or escaping
+func WithArg(name, value string) Option {
+	return func(c *processConfig) {
+		c.bind(name, value)
+	}
+}
+
+// WithArgJSON binds $name to a JSON text, like jq --argjson
+func WithArgJSON(name, text string) Option {
+	return func(c *processConfig) {
+		var value interface{}
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			c.fail(fmt.Errorf("invalid JSON for $%s: %w", strings.TrimPrefix(name, "$"), err))
+			return
+		}
+		c.bind(name, value)
+	}
+}
+
+// WithVar binds $name to a Go value, converted through JSON so maps,
+// slices and structs are seen as the program would see them in the input
+func WithVar(name string, value interface{}) Option {
+	return func(c *processConfig) {
+		data, err := json.Marshal(value)
+		if err != nil {
+			c.fail(fmt.Errorf("failed to marshal $%s: %w", strings.TrimPrefix(name, "$"), err))
+			return
+		}
+		var converted interface{}
+		if err := json.Unmarshal(data, &converted); err != nil {
+			c.fail(fmt.Errorf("failed to convert $%s: %w", strings.TrimPrefix(name, "$"), err))
+			return
+		}
+		c.bind(name, converted)
+	}
+}
+
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// bind sets a variable, replacing any earlier value of the same name
+func (c *processConfig) bind(name string, value interface{}) {
+	name = strings.TrimPrefix(name, "$")
+	if !variableName.MatchString(name) {
+		c.fail(fmt.Errorf("invalid variable name %q", name))
+		return
+	}
+	if name == "ENV" || name == "__loc__" || name == "ARGS" {
+		c.fail(fmt.Errorf("variable name $%s is reserved", name))
+		return
+	}
+
+	if _, exists := c.values[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.values[name] = value
+}
+
+// fail records the first option error, reported when the program runs
+func (c *processConfig) fail(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// compile parses and compiles program with the variables of config,
+// returning the values to run it with. $ARGS.named holds every variable,
+// as in jq
+func compile(program string, config *processConfig, options ...gojq.CompilerOption) (*gojq.Code, []interface{}, error) {
+	if config.err != nil {
+		return nil, nil, config.err
+	}
+
+	query, err := gojq.Parse(program)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse jq program: %w", err)
+	}
+
+	names := make([]string, 0, len(config.names)+1)
+	values := make([]interface{}, 0, len(config.names)+1)
+	named := make(map[string]interface{}, len(config.names))
+	for _, name := range config.names {
+		names = append(names, "$"+name)
+		values = append(values, config.values[name])
+		named[name] = config.values[name]
+	}
+	names = append(names, "$ARGS")
+	values = append(values, map[string]interface{}{
+		"named":      named,
+		"positional": []interface{}{},
+	})
+
+	code, err := gojq.Compile(query, append(options, gojq.WithVariables(names))...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compile jq program: %w", err)
+	}
+	return code, values, nil
+}
@@ -7,9 +7,15 @@
 //   - Complex Queries: Full jq language support for advanced JSON manipulation
 //   - Error Handling: Comprehensive error handling with Go error wrapping
 //   - Cross-platform: Works consistently across all platforms
+//   - Pretty Printing: Indented, colorized output that is disabled when piped
+//   - Table Projection: ToTable evaluates one expression per column for the table package
+//   - Output Modes: Run writes results like the jq binary, with raw, slurp, compact, tab and indent options
+//   - Program Arguments: WithArg and WithArgJSON bind variables like jq --arg and --argjson
+//   - Streaming Input: ProcessReader handles NDJSON and --stream style events with bounded memory
 //
 // Example:
 //   result, err := jq.ProcessString(jsonData, ".users[0].name")
 //   data, err := jq.Process(jsonBytes, ".field")
 //   obj, err := jq.ProcessObject(myObject, ".property")
+//   err = jq.PrettyPrint(os.Stdout, result)
 package jq
\ No newline at end of file
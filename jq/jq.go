@@ -3,20 +3,19 @@ package jq
 import (
 	"encoding/json"
 	"fmt"
-
-	"github.com/itchyny/gojq"
 )
 
-// Process processes JSON data with a JQ program
-func Process(data []byte, program string) ([]byte, error) {
+// Process processes JSON data with a JQ program. Options such as WithArg
+// bind variables the program can use
+func Process(data []byte, program string, options ...Option) ([]byte, error) {
 	if program == "" {
 		return data, nil
 	}
 
-	// Parse the jq program
-	query, err := gojq.Parse(program)
+	// Parse the jq program and bind its variables
+	code, values, err := compile(program, newProcessConfig(options))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse jq program: %w", err)
+		return nil, err
 	}
 
 	// Parse input JSON
@@ -26,7 +25,7 @@ func Process(data []byte, program string) ([]byte, error) {
 	}
 
 	// Execute the query
-	iter := query.Run(input)
+	iter := code.Run(input, values...)
 	var results []interface{}
 	for {
 		v, ok := iter.Next()
@@ -49,8 +48,8 @@ func Process(data []byte, program string) ([]byte, error) {
 }
 
 // ProcessString processes a JSON string with a JQ program
-func ProcessString(data string, program string) (string, error) {
-	result, err := Process([]byte(data), program)
+func ProcessString(data string, program string, options ...Option) (string, error) {
+	result, err := Process([]byte(data), program, options...)
 	if err != nil {
 		return "", err
 	}
@@ -58,13 +57,13 @@ func ProcessString(data string, program string) (string, error) {
 }
 
 // ProcessObject processes a JSON object with a JQ program
-func ProcessObject(data interface{}, program string) (interface{}, error) {
+func ProcessObject(data interface{}, program string, options ...Option) (interface{}, error) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	result, err := Process(jsonData, program)
+	result, err := Process(jsonData, program, options...)
 	if err != nil {
 		return nil, err
 	}
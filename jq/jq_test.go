@@ -1,8 +1,11 @@
 package jq
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -135,4 +138,303 @@ func TestInvalidJSON(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for invalid JSON")
 	}
-}
\ No newline at end of file
+}
+
+func collect(t *testing.T, input, program string, options ...Option) []interface{} {
+	t.Helper()
+
+	var results []interface{}
+	err := ProcessReader(context.Background(), strings.NewReader(input), program, func(v interface{}) error {
+		results = append(results, v)
+		return nil
+	}, options...)
+	if err != nil {
+		t.Fatalf("ProcessReader failed: %v", err)
+	}
+	return results
+}
+
+func TestProcessReader(t *testing.T) {
+	input := `{"name": "John", "age": 30}
+{"name": "Jane", "age": 25}
+{"name": "Bob", "age": 40}`
+
+	results := collect(t, input, "select(.age > 28) | .name")
+	if !reflect.DeepEqual(results, []interface{}{"John", "Bob"}) {
+		t.Errorf("Unexpected results: %v", results)
+	}
+
+	results = collect(t, "1 2 3 4", "[., input]")
+	if !reflect.DeepEqual(normalize(t, results), normalize(t, []interface{}{[]interface{}{1, 2}, []interface{}{3, 4}})) {
+		t.Errorf("Expected input to consume the next value, got %v", results)
+	}
+
+	results = collect(t, input, "[inputs | .age] | add", WithNullInput())
+	if !reflect.DeepEqual(results, []interface{}{float64(95)}) {
+		t.Errorf("Unexpected results: %v", results)
+	}
+
+	results = collect(t, input, ".name | if . == \"Jane\" then halt else . end")
+	if !reflect.DeepEqual(results, []interface{}{"John"}) {
+		t.Errorf("Expected halt to stop processing, got %v", results)
+	}
+}
+
+func TestProcessReaderStream(t *testing.T) {
+	documents := []string{
+		`{"a": [1, {"b": 2}], "c": {}, "d": [], "e": null}`,
+		`[[1, 2], [], [{"x": "y"}]]`,
+		`"scalar"`,
+		`[]`,
+		`{}`,
+	}
+
+	for _, document := range documents {
+		var value interface{}
+		if err := json.Unmarshal([]byte(document), &value); err != nil {
+			t.Fatalf("Invalid test document: %v", err)
+		}
+		expected, err := ProcessObject(value, "[tostream]")
+		if err != nil {
+			t.Fatalf("ProcessObject failed: %v", err)
+		}
+
+		events := collect(t, document, ".", WithStream())
+		if !reflect.DeepEqual(normalize(t, events), expected) {
+			t.Errorf("Stream of %s:\nexpected %v\ngot      %v", document, expected, events)
+		}
+
+		rebuilt := collect(t, document, "fromstream(inputs)", WithStream(), WithNullInput())
+		if len(rebuilt) != 1 || !reflect.DeepEqual(rebuilt[0], value) {
+			t.Errorf("Expected fromstream to rebuild %s, got %v", document, rebuilt)
+		}
+	}
+
+	results := collect(t, `{"items": [{"id": 1}, {"id": 2}]}`, `fromstream(2 | truncate_stream(inputs | select(.[0][0] == "items"))) | .id`, WithStream(), WithNullInput())
+	if !reflect.DeepEqual(results, []interface{}{float64(1), float64(2)}) {
+		t.Errorf("Unexpected streamed items: %v", results)
+	}
+}
+
+// normalize round-trips values through JSON so ints compare equal to the
+// float64 numbers of ProcessObject
+func normalize(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	return out
+}
+
+func TestProcessReaderErrors(t *testing.T) {
+	emit := func(v interface{}) error { return nil }
+	ctx := context.Background()
+
+	if err := ProcessReader(ctx, strings.NewReader(`{}`), ".[", emit); err == nil {
+		t.Error("Expected error for invalid program")
+	}
+	if err := ProcessReader(ctx, strings.NewReader(`{"a": 1} {"a":`), ".a", emit); err == nil {
+		t.Error("Expected error for malformed input")
+	}
+	if err := ProcessReader(ctx, strings.NewReader(`{"a": [1`), ".", emit, WithStream()); err == nil {
+		t.Error("Expected error for truncated streamed input")
+	}
+	if err := ProcessReader(ctx, strings.NewReader(`"a"`), ".foo", emit); err == nil {
+		t.Error("Expected jq execution error")
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := ProcessReader(ctx, strings.NewReader(`1 2 3 4`), ".", func(v interface{}) error {
+		calls++
+		if calls == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || calls != 2 {
+		t.Errorf("Expected emit error after 2 values, got %v after %d", err, calls)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := ProcessReader(cancelled, strings.NewReader(`1 2 3`), ".", emit); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestProcessWithArgs(t *testing.T) {
+	data := []byte(`{"users": [{"name": "John", "age": 30}, {"name": "Jane", "age": 25}]}`)
+
+	result, err := Process(data, `[.users[] | select(.name == $name) | .age]`, WithArg("name", `Jane" or true or "`))
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if string(result) != "[]" {
+		t.Errorf("Expected the argument to be bound as a string, got %s", result)
+	}
+
+	result, err = Process(data, `[.users[] | select(.age >= $min) | .name]`, WithArgJSON("min", "26"))
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if string(result) != `["John"]` {
+		t.Errorf("Unexpected result: %s", result)
+	}
+
+	output, err := ProcessString(`{}`, `$ARGS.named`, WithArg("a", "x"), WithVar("$b", map[string]int{"n": 1}), WithArg("a", "y"))
+	if err != nil {
+		t.Fatalf("ProcessString failed: %v", err)
+	}
+	if output != `{"a":"y","b":{"n":1}}` {
+		t.Errorf("Unexpected $ARGS.named: %s", output)
+	}
+
+	var results []interface{}
+	err = ProcessReader(context.Background(), strings.NewReader(`1 2 3`), `. * $factor`, func(v interface{}) error {
+		results = append(results, v)
+		return nil
+	}, WithArgJSON("factor", "10"))
+	if err != nil {
+		t.Fatalf("ProcessReader failed: %v", err)
+	}
+	if !reflect.DeepEqual(normalize(t, results), normalize(t, []interface{}{10, 20, 30})) {
+		t.Errorf("Unexpected results: %v", results)
+	}
+}
+
+func TestProcessWithArgsErrors(t *testing.T) {
+	data := []byte(`{}`)
+	tests := map[string][]Option{
+		"invalid JSON":  {WithArgJSON("x", "{")},
+		"invalid name":  {WithArg("not-valid", "x")},
+		"reserved name": {WithArg("ENV", "x")},
+		"unmarshalable": {WithVar("x", func() {})},
+	}
+	for name, options := range tests {
+		if _, err := Process(data, `$x`, options...); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if _, err := Process(data, `$undefined`); err == nil {
+		t.Error("Expected error for undefined variable")
+	}
+}
+
+func TestRunOutputModes(t *testing.T) {
+	input := `{"name": "John", "tags": ["a", "b"]}
+{"name": "Jane", "tags": []}`
+
+	tests := []struct {
+		name     string
+		program  string
+		options  []Option
+		expected string
+	}{
+		{"default", ".name", nil, "\"John\"\n\"Jane\"\n"},
+		{"raw", ".name", []Option{WithRawOutput()}, "John\nJane\n"},
+		{"raw non-string", ".tags", []Option{WithRawOutput(), WithCompact()}, "[\"a\",\"b\"]\n[]\n"},
+		{"compact", ".", []Option{WithCompact()}, `{"name":"John","tags":["a","b"]}` + "\n" + `{"name":"Jane","tags":[]}` + "\n"},
+		{"indent", "{name}", []Option{WithIndent(4)}, "{\n    \"name\": \"John\"\n}\n{\n    \"name\": \"Jane\"\n}\n"},
+		{"indent zero", ".tags", []Option{WithIndent(0)}, "[\"a\",\"b\"]\n[]\n"},
+		{"tab", "{name}", []Option{WithTab()}, "{\n\t\"name\": \"John\"\n}\n{\n\t\"name\": \"Jane\"\n}\n"},
+		{"slurp", "map(.name)", []Option{WithSlurp(), WithCompact()}, "[\"John\",\"Jane\"]\n"},
+		{"slurp length", "length", []Option{WithSlurp()}, "2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := Run(context.Background(), strings.NewReader(input), &buf, tt.program, tt.options...); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestRunColor(t *testing.T) {
+	var buf strings.Builder
+	if err := Run(context.Background(), strings.NewReader(`{"a": 1}`), &buf, ".", WithColor(true), WithCompact()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("Expected colored output, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := Run(context.Background(), strings.NewReader(`{"a": 1}`), &buf, ".", WithColor(false), WithCompact()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if buf.String() != `{"a":1}`+"\n" {
+		t.Errorf("Expected plain output, got %q", buf.String())
+	}
+}
+
+func TestToTable(t *testing.T) {
+	data := []byte(`[
+		{"name": "Users", "api_id": "a1", "active": true, "version": 2, "tags": ["x", "y"], "proxy": {"listen_path": "/users/"}},
+		{"name": "Orders", "api_id": "b2", "active": false, "version": 1.5, "tags": [], "proxy": null}
+	]`)
+
+	columns, err := ParseColumns(`NAME=.name,api_id,active,VERSION=.version,TAGS=.tags[],PATH=.proxy.listen_path,PAIR=[.name, .api_id] | join(":")`)
+	if err != nil {
+		t.Fatalf("ParseColumns failed: %v", err)
+	}
+
+	headers, rows, err := ToTable(data, columns)
+	if err != nil {
+		t.Fatalf("ToTable failed: %v", err)
+	}
+
+	expectedHeaders := []string{"NAME", "API_ID", "ACTIVE", "VERSION", "TAGS", "PATH", "PAIR"}
+	if !reflect.DeepEqual(headers, expectedHeaders) {
+		t.Errorf("Expected headers %v, got %v", expectedHeaders, headers)
+	}
+	expectedRows := [][]string{
+		{"Users", "a1", "true", "2", "x, y", "/users/", "Users:a1"},
+		{"Orders", "b2", "false", "1.5", "", "", "Orders:b2"},
+	}
+	if !reflect.DeepEqual(rows, expectedRows) {
+		t.Errorf("Expected rows %v, got %v", expectedRows, rows)
+	}
+
+	headers, rows, err = ToTable([]byte(`{"name": "single", "meta": {"a": 1}}`),
+		[]Column{{Header: "N", Expression: ".name + $suffix"}, {Header: "META", Expression: ".meta"}},
+		WithArg("suffix", "!"))
+	if err != nil {
+		t.Fatalf("ToTable failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0][0] != "single!" || rows[0][1] != `{"a":1}` {
+		t.Errorf("Unexpected rows for single object: %v %v", headers, rows)
+	}
+}
+
+func TestToTableErrors(t *testing.T) {
+	if _, _, err := ToTable([]byte(`[{}]`), nil); err == nil {
+		t.Error("Expected error for no columns")
+	}
+	if _, _, err := ToTable([]byte(`[`), []Column{{Header: "A", Expression: ".a"}}); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+	if _, _, err := ToTable([]byte(`[{}]`), []Column{{Header: "A", Expression: ".["}}); err == nil {
+		t.Error("Expected error for invalid expression")
+	}
+	if _, _, err := ToTable([]byte(`["x"]`), []Column{{Header: "A", Expression: ".a"}}); err == nil {
+		t.Error("Expected error for expression failing on a record")
+	}
+
+	for _, spec := range []string{"", " , ", ".a | .b", "A="} {
+		if _, err := ParseColumns(spec); err == nil {
+			t.Errorf("Expected error for columns %q", spec)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package jq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WithRawOutput writes string results without quotes, like jq -r
+func WithRawOutput() Option {
+	return func(c *processConfig) {
+		c.rawOutput = true
+	}
+}
+
+// WithSlurp reads every input value into one array and runs the program
+// once on it, like jq -s. It only applies to ProcessReader and Run
+func WithSlurp() Option {
+	return func(c *processConfig) {
+		c.slurp = true
+	}
+}
+
+// WithCompact writes each result on a single line, like jq -c
+func WithCompact() Option {
+	return func(c *processConfig) {
+		c.compact = true
+	}
+}
+
+// WithTab indents results with a tab per level, like jq --tab
+func WithTab() Option {
+	return func(c *processConfig) {
+		indent := "\t"
+		c.indent = &indent
+	}
+}
+
+// WithIndent indents results with n spaces per level, like jq --indent.
+// Zero writes compact output, as jq does
+func WithIndent(n int) Option {
+	return func(c *processConfig) {
+		if n < 0 {
+			n = 0
+		}
+		indent := strings.Repeat(" ", n)
+		c.indent = &indent
+	}
+}
+
+// WithColor forces syntax highlighting on or off, like jq -C and -M,
+// instead of enabling it only for terminals
+func WithColor(enabled bool) Option {
+	return func(c *processConfig) {
+		c.color = &enabled
+	}
+}
+
+// Run runs a JQ program over the JSON values read from r and writes each
+// result to w the way the jq binary does: one per line, indented and
+// colorized when w is a terminal unless output options say otherwise
+func Run(ctx context.Context, r io.Reader, w io.Writer, program string, options ...Option) error {
+	config := newProcessConfig(options)
+	printer := newOutputPrinter(w, config)
+
+	return ProcessReader(ctx, r, program, func(v interface{}) error {
+		return writeResult(w, printer, config, v)
+	}, options...)
+}
+
+// newOutputPrinter returns the printer for the output options of config
+func newOutputPrinter(w io.Writer, config *processConfig) *Printer {
+	printer := NewPrinter(w)
+	if config.indent != nil {
+		printer.Indent = *config.indent
+		printer.Compact = *config.indent == ""
+	}
+	if config.compact {
+		printer.Compact = true
+	}
+	if config.color != nil {
+		printer.Color = *config.color
+	}
+	return printer
+}
+
+// writeResult writes one result followed by a newline
+func writeResult(w io.Writer, printer *Printer, config *processConfig, v interface{}) error {
+	if text, ok := v.(string); ok && config.rawOutput {
+		_, err := io.WriteString(w, text+"\n")
+		return err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	return printer.Fprint(w, buf.Bytes())
+}
+
+// slurpIter yields every value of another iterator as a single array
+type slurpIter struct {
+	source inputIter
+	done   bool
+}
+
+func (it *slurpIter) Next() (interface{}, bool) {
+	if it.done {
+		return nil, false
+	}
+	it.done = true
+
+	values := []interface{}{}
+	for {
+		v, ok := it.source.Next()
+		if !ok {
+			return values, true
+		}
+		if err, ok := v.(error); ok {
+			return err, true
+		}
+		values = append(values, v)
+	}
+}
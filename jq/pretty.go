@@ -0,0 +1,246 @@
+package jq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// Colors holds the ANSI escape sequences used to highlight each kind of JSON token.
+// An empty sequence leaves the token uncolored.
+type Colors struct {
+	Null      string
+	False     string
+	True      string
+	Number    string
+	String    string
+	Array     string
+	Object    string
+	ObjectKey string
+}
+
+// DefaultColors returns the color theme built from the terminal palette,
+// overridden by the JQ_COLORS environment variable when it is set
+func DefaultColors() Colors {
+	colors := Colors{
+		Null:      terminal.ColorGray,
+		False:     terminal.ColorYellow,
+		True:      terminal.ColorYellow,
+		Number:    terminal.ColorCyan,
+		String:    terminal.ColorGreen,
+		ObjectKey: terminal.ColorBlue,
+	}
+
+	if spec := os.Getenv("JQ_COLORS"); spec != "" {
+		colors = ParseColors(spec, colors)
+	}
+	return colors
+}
+
+// ParseColors applies a jq style color specification to base. The
+// specification is a colon separated list of SGR parameters in the order
+// null:false:true:numbers:strings:arrays:objects:objkeys, as accepted by the
+// jq binary's JQ_COLORS variable. Omitted entries keep their base color.
+func ParseColors(spec string, base Colors) Colors {
+	fields := []*string{
+		&base.Null,
+		&base.False,
+		&base.True,
+		&base.Number,
+		&base.String,
+		&base.Array,
+		&base.Object,
+		&base.ObjectKey,
+	}
+
+	for i, param := range strings.Split(spec, ":") {
+		if i >= len(fields) {
+			break
+		}
+		if param == "" || strings.Trim(param, "0123456789;") != "" {
+			continue
+		}
+		*fields[i] = "\x1b[" + param + "m"
+	}
+	return base
+}
+
+// Printer pretty-prints JSON with optional syntax highlighting
+type Printer struct {
+	// Indent is the indentation used for each nesting level
+	Indent string
+
+	// Compact writes each value on a single line without spaces, like
+	// jq -c
+	Compact bool
+
+	// Color enables syntax highlighting
+	Color bool
+
+	// Colors is the theme used when Color is enabled
+	Colors Colors
+}
+
+// NewPrinter creates a printer for w, enabling color only when w is a
+// terminal that supports it. Color is disabled when output is piped or
+// redirected, unless FORCE_COLOR or CLICOLOR_FORCE is set, and when NO_COLOR
+// is set.
+func NewPrinter(w io.Writer) *Printer {
+	return &Printer{
+		Indent: "  ",
+		Color:  colorEnabled(w),
+		Colors: DefaultColors(),
+	}
+}
+
+// PrettyPrint writes one or more JSON values to w, one per line, indented and
+// colorized as appropriate for w
+func PrettyPrint(w io.Writer, data []byte) error {
+	return NewPrinter(w).Fprint(w, data)
+}
+
+// Fprint writes one or more JSON values to w, one per line
+func (p *Printer) Fprint(w io.Writer, data []byte) error {
+	formatted, err := p.Format(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// Format returns one or more JSON values formatted one per line
+func (p *Printer) Format(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var buf bytes.Buffer
+	for {
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+
+		valueDecoder := json.NewDecoder(bytes.NewReader(value))
+		valueDecoder.UseNumber()
+		if err := p.writeValue(&buf, valueDecoder, 0); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// writeValue writes the next value from decoder at the given nesting depth
+func (p *Printer) writeValue(buf *bytes.Buffer, decoder *json.Decoder, depth int) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		return p.writeContainer(buf, decoder, v, depth)
+	case nil:
+		p.write(buf, "null", p.Colors.Null)
+	case bool:
+		if v {
+			p.write(buf, "true", p.Colors.True)
+		} else {
+			p.write(buf, "false", p.Colors.False)
+		}
+	case json.Number:
+		p.write(buf, v.String(), p.Colors.Number)
+	case string:
+		p.write(buf, quote(v), p.Colors.String)
+	}
+	return nil
+}
+
+// writeContainer writes an array or object whose opening delimiter has been read
+func (p *Printer) writeContainer(buf *bytes.Buffer, decoder *json.Decoder, open json.Delim, depth int) error {
+	color, closing := p.Colors.Array, "]"
+	if open == '{' {
+		color, closing = p.Colors.Object, "}"
+	}
+
+	p.write(buf, open.String(), color)
+
+	first := true
+	for decoder.More() {
+		if !first {
+			p.write(buf, ",", color)
+		}
+		first = false
+		p.newline(buf, depth+1)
+
+		if open == '{' {
+			token, err := decoder.Token()
+			if err != nil {
+				return fmt.Errorf("failed to parse JSON: %w", err)
+			}
+			key, _ := token.(string)
+			p.write(buf, quote(key), p.Colors.ObjectKey)
+			p.write(buf, ":", color)
+			if !p.Compact {
+				buf.WriteByte(' ')
+			}
+		}
+
+		if err := p.writeValue(buf, decoder, depth+1); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing delimiter
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	if !first {
+		p.newline(buf, depth)
+	}
+	p.write(buf, closing, color)
+	return nil
+}
+
+// write appends text, wrapped in color when highlighting is enabled
+func (p *Printer) write(buf *bytes.Buffer, text, color string) {
+	if p.Color && color != "" {
+		buf.WriteString(color)
+		buf.WriteString(text)
+		buf.WriteString(terminal.ColorReset)
+		return
+	}
+	buf.WriteString(text)
+}
+
+// newline starts a new line indented to depth
+func (p *Printer) newline(buf *bytes.Buffer, depth int) {
+	if p.Compact {
+		return
+	}
+	buf.WriteByte('\n')
+	buf.WriteString(strings.Repeat(p.Indent, depth))
+}
+
+// quote encodes a string as JSON without escaping HTML characters
+func quote(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// colorEnabled reports whether output written to w should be colorized
+func colorEnabled(w io.Writer) bool {
+	return terminal.ColorLevelOf(w) != terminal.ColorNone
+}
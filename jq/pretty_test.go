@@ -0,0 +1,119 @@
+package jq
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+func TestPrinterFormat(t *testing.T) {
+	printer := &Printer{Indent: "  "}
+
+	result, err := printer.Format([]byte(`{"name":"<api>","tags":[],"meta":{},"port":8080.50,"ok":true,"x":null}`))
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	expected := `{
+  "name": "<api>",
+  "tags": [],
+  "meta": {},
+  "port": 8080.50,
+  "ok": true,
+  "x": null
+}
+`
+	if string(result) != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestPrinterFormatMultipleValues(t *testing.T) {
+	printer := &Printer{Indent: "  "}
+
+	result, err := printer.Format([]byte(`1 "two" [3]`))
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	expected := "1\n\"two\"\n[\n  3\n]\n"
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestPrinterFormatColor(t *testing.T) {
+	printer := &Printer{Indent: "  ", Color: true, Colors: Colors{
+		Number:    terminal.ColorCyan,
+		String:    terminal.ColorGreen,
+		ObjectKey: terminal.ColorBlue,
+	}}
+
+	result, err := printer.Format([]byte(`{"a":"b","c":1}`))
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	reset := terminal.ColorReset
+	expected := "{\n" +
+		"  " + terminal.ColorBlue + `"a"` + reset + ": " + terminal.ColorGreen + `"b"` + reset + ",\n" +
+		"  " + terminal.ColorBlue + `"c"` + reset + ": " + terminal.ColorCyan + "1" + reset + "\n" +
+		"}\n"
+	if string(result) != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestPrinterFormatInvalid(t *testing.T) {
+	printer := &Printer{Indent: "  "}
+
+	if _, err := printer.Format([]byte(`{"a":`)); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+func TestParseColors(t *testing.T) {
+	colors := ParseColors("0;90::1;31:bad", Colors{False: "keep", Number: "keep"})
+
+	if colors.Null != "\x1b[0;90m" {
+		t.Errorf("Expected null color to be set, got %q", colors.Null)
+	}
+	if colors.False != "keep" {
+		t.Errorf("Expected empty entry to keep base color, got %q", colors.False)
+	}
+	if colors.True != "\x1b[1;31m" {
+		t.Errorf("Expected true color to be set, got %q", colors.True)
+	}
+	if colors.Number != "keep" {
+		t.Errorf("Expected invalid entry to keep base color, got %q", colors.Number)
+	}
+}
+
+func TestNewPrinterDisablesColorWhenPiped(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("TYKCTL_FORCE_TTY", "")
+
+	if NewPrinter(&bytes.Buffer{}).Color {
+		t.Error("Expected color to be disabled for non-terminal output")
+	}
+
+	t.Setenv("CLICOLOR_FORCE", "1")
+	if !NewPrinter(&bytes.Buffer{}).Color {
+		t.Error("Expected CLICOLOR_FORCE to enable color")
+	}
+
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("FORCE_COLOR", "1")
+	if !NewPrinter(&bytes.Buffer{}).Color {
+		t.Error("Expected FORCE_COLOR to enable color")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if NewPrinter(&bytes.Buffer{}).Color {
+		t.Error("Expected NO_COLOR to disable color")
+	}
+}
@@ -0,0 +1,263 @@
+package jq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/itchyny/gojq"
+)
+
+// WithStream feeds the input to the program as a stream of [path, leaf]
+// events, like jq --stream, so documents larger than memory can be
+// processed. Each array and object ends with a closing [path] event. It
+// only applies to ProcessReader
+func WithStream() Option {
+	return func(c *processConfig) {
+		c.stream = true
+	}
+}
+
+// WithNullInput runs the program once with null as input, like jq -n. The
+// program reads the input values itself with input and inputs, such as
+// fromstream(inputs) together with WithStream. It only applies to
+// ProcessReader
+func WithNullInput() Option {
+	return func(c *processConfig) {
+		c.nullInput = true
+	}
+}
+
+// ProcessReader runs a JQ program over the JSON values read from r, which
+// may be a single document or a sequence of documents such as NDJSON,
+// calling emit with each result as soon as it is produced. Values are
+// decoded one at a time rather than reading the whole input into memory.
+// An error returned by emit stops processing and is returned as is
+func ProcessReader(ctx context.Context, r io.Reader, program string, emit func(interface{}) error, options ...Option) error {
+	config := newProcessConfig(options)
+
+	decoder := json.NewDecoder(r)
+	var inputs inputIter
+	if config.stream {
+		inputs = &streamIter{ctx: ctx, decoder: decoder}
+	} else {
+		inputs = &valueIter{ctx: ctx, decoder: decoder}
+	}
+	if config.slurp {
+		inputs = &slurpIter{source: inputs}
+	}
+
+	code, values, err := compile(program, config, gojq.WithInputIter(inputs))
+	if err != nil {
+		return err
+	}
+
+	if config.nullInput {
+		return ignoreHalt(run(ctx, code, nil, values, emit))
+	}
+
+	for {
+		input, ok := inputs.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := input.(error); ok {
+			return err
+		}
+		if err := run(ctx, code, input, values, emit); err != nil {
+			return ignoreHalt(err)
+		}
+	}
+}
+
+// errHalted stops processing after the program called halt
+var errHalted = errors.New("halted")
+
+func ignoreHalt(err error) error {
+	if err == errHalted {
+		return nil
+	}
+	return err
+}
+
+// run executes code on one input, passing each result to emit
+func run(ctx context.Context, code *gojq.Code, input interface{}, values []interface{}, emit func(interface{}) error) error {
+	iter := code.RunWithContext(ctx, input, values...)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := v.(error); ok {
+			var halt *gojq.HaltError
+			if errors.As(err, &halt) && halt.Value() == nil {
+				return errHalted
+			}
+			return fmt.Errorf("jq execution error: %w", err)
+		}
+		if err := emit(v); err != nil {
+			return err
+		}
+	}
+}
+
+// inputIter yields input values, or an error, as gojq iterators do
+type inputIter interface {
+	Next() (interface{}, bool)
+}
+
+// valueIter yields each JSON value of the input
+type valueIter struct {
+	ctx     context.Context
+	decoder *json.Decoder
+	err     error
+}
+
+func (it *valueIter) Next() (interface{}, bool) {
+	if it.err != nil {
+		return nil, false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return err, true
+	}
+
+	var value interface{}
+	if err := it.decoder.Decode(&value); err != nil {
+		if err == io.EOF {
+			it.err = err
+			return nil, false
+		}
+		it.err = fmt.Errorf("failed to parse input JSON: %w", err)
+		return it.err, true
+	}
+	return value, true
+}
+
+// streamIter yields the [path, leaf] and closing [path] events of each
+// JSON value of the input, reading one token at a time
+type streamIter struct {
+	ctx     context.Context
+	decoder *json.Decoder
+	frames  []streamFrame
+	pending []interface{}
+	err     error
+}
+
+// streamFrame is an array or object being streamed
+type streamFrame struct {
+	array bool
+	// index is the position of the current element of an array
+	index int
+	// key is the current key of an object
+	key       string
+	hasKey    bool
+	expectKey bool
+}
+
+func (it *streamIter) Next() (interface{}, bool) {
+	for len(it.pending) == 0 {
+		if it.err != nil {
+			return nil, false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return err, true
+		}
+		if err := it.advance(); err != nil {
+			if err == io.EOF && len(it.frames) == 0 {
+				it.err = err
+				return nil, false
+			}
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			it.err = fmt.Errorf("failed to parse input JSON: %w", err)
+			return it.err, true
+		}
+	}
+
+	event := it.pending[0]
+	it.pending = it.pending[1:]
+	return event, true
+}
+
+// advance reads one token, queueing the events it completes
+func (it *streamIter) advance() error {
+	token, err := it.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if top := it.top(); top != nil && top.expectKey {
+		if key, ok := token.(string); ok {
+			top.key, top.hasKey, top.expectKey = key, true, false
+			return nil
+		}
+	}
+
+	switch token {
+	case json.Delim('['), json.Delim('{'):
+		it.frames = append(it.frames, streamFrame{
+			array:     token == json.Delim('['),
+			expectKey: token == json.Delim('{'),
+		})
+	case json.Delim(']'), json.Delim('}'):
+		frame := it.frames[len(it.frames)-1]
+		it.frames = it.frames[:len(it.frames)-1]
+
+		switch {
+		case frame.array && frame.index == 0:
+			it.emit([]interface{}{it.path(), []interface{}{}})
+		case frame.array:
+			it.emit([]interface{}{append(it.path(), frame.index-1)})
+		case !frame.hasKey:
+			it.emit([]interface{}{it.path(), map[string]interface{}{}})
+		default:
+			it.emit([]interface{}{append(it.path(), frame.key)})
+		}
+		it.valueDone()
+	default:
+		it.emit([]interface{}{it.path(), token})
+		it.valueDone()
+	}
+	return nil
+}
+
+func (it *streamIter) top() *streamFrame {
+	if len(it.frames) == 0 {
+		return nil
+	}
+	return &it.frames[len(it.frames)-1]
+}
+
+// path returns a new slice with the path of the current value
+func (it *streamIter) path() []interface{} {
+	path := make([]interface{}, 0, len(it.frames)+1)
+	for _, frame := range it.frames {
+		if frame.array {
+			path = append(path, frame.index)
+		} else {
+			path = append(path, frame.key)
+		}
+	}
+	return path
+}
+
+// valueDone moves the enclosing container past a completed value
+func (it *streamIter) valueDone() {
+	top := it.top()
+	switch {
+	case top == nil:
+	case top.array:
+		top.index++
+	default:
+		top.expectKey = true
+	}
+}
+
+func (it *streamIter) emit(event interface{}) {
+	it.pending = append(it.pending, event)
+}
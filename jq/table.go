@@ -0,0 +1,198 @@
+package jq
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/itchyny/gojq"
+)
+
+// Column is a table column computed by a JQ expression on each record
+type Column struct {
+	Header     string
+	Expression string
+}
+
+// ToTable evaluates one JQ expression per column over the records in data,
+// an array of objects or a single object, and returns headers and rows
+// ready for the table package. Strings are shown as is, null as an empty
+// cell, arrays and objects as compact JSON, and several results of one
+// expression are joined with ", "
+func ToTable(data []byte, columns []Column, options ...Option) ([]string, [][]string, error) {
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("no columns given")
+	}
+
+	var input interface{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse input JSON: %w", err)
+	}
+	records, ok := input.([]interface{})
+	if !ok {
+		records = []interface{}{input}
+	}
+
+	config := newProcessConfig(options)
+	headers := make([]string, len(columns))
+	codes := make([]*gojq.Code, len(columns))
+	var values []interface{}
+	for i, column := range columns {
+		code, columnValues, err := compile(column.Expression, config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("column %s: %w", column.Header, err)
+		}
+		headers[i], codes[i], values = column.Header, code, columnValues
+	}
+
+	rows := make([][]string, 0, len(records))
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, code := range codes {
+			cell, err := evaluateCell(code, record, values)
+			if err != nil {
+				return nil, nil, fmt.Errorf("column %s: %w", columns[i].Header, err)
+			}
+			row[i] = cell
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows, nil
+}
+
+// ParseColumns parses a --columns flag such as "NAME=.name,ID=.api_id,active".
+// A bare field name is shown under its upper-cased name. Commas inside
+// brackets, parentheses, braces or strings do not separate columns
+func ParseColumns(spec string) ([]Column, error) {
+	var columns []Column
+	for _, part := range splitTopLevel(spec) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		header, expression, found := strings.Cut(part, "=")
+		// "==" belongs to the expression, not the header separator
+		if found && (strings.HasPrefix(expression, "=") || !isHeader(header)) {
+			found = false
+		}
+
+		if !found {
+			field := strings.TrimPrefix(part, ".")
+			if !isIdentifier(field) {
+				return nil, fmt.Errorf("column %q needs a header, as in HEADER=%s", part, part)
+			}
+			columns = append(columns, Column{Header: strings.ToUpper(field), Expression: "." + field})
+			continue
+		}
+
+		header, expression = strings.TrimSpace(header), strings.TrimSpace(expression)
+		if expression == "" {
+			return nil, fmt.Errorf("column %q has no expression", header)
+		}
+		columns = append(columns, Column{Header: header, Expression: expression})
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+	return columns, nil
+}
+
+// evaluateCell runs code on a record and renders its results as one cell
+func evaluateCell(code *gojq.Code, record interface{}, values []interface{}) (string, error) {
+	var parts []string
+	iter := code.Run(record, values...)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return "", fmt.Errorf("jq execution error: %w", err)
+		}
+		if v == nil {
+			continue
+		}
+		parts = append(parts, formatCell(v))
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// formatCell renders a single value for a table cell
+func formatCell(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case int:
+		return strconv.Itoa(value)
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+			return strconv.FormatInt(int64(value), 10)
+		}
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// splitTopLevel splits spec on commas that are not nested in brackets,
+// parentheses, braces or strings
+func splitTopLevel(spec string) []string {
+	var (
+		parts    []string
+		depth    int
+		inString bool
+		escaped  bool
+		start    int
+	)
+	for i, r := range spec {
+		switch {
+		case inString && escaped:
+			escaped = false
+		case inString && r == '\\':
+			escaped = true
+		case inString && r == '"':
+			inString = false
+		case inString:
+		case r == '"':
+			inString = true
+		case r == '[' || r == '(' || r == '{':
+			depth++
+		case r == ']' || r == ')' || r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, spec[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, spec[start:])
+}
+
+// isHeader reports whether s can be a column header rather than part of
+// an expression
+func isHeader(s string) bool {
+	s = strings.TrimSpace(s)
+	return s != "" && !strings.ContainsAny(s, ".|[](){}\"$")
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
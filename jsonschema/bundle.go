@@ -0,0 +1,379 @@
+package jsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrNetworkDisabled is returned when resolving a $ref would need network
+// access and the bundler is offline
+var ErrNetworkDisabled = errors.New("network access is disabled")
+
+// Option configures how schemas are bundled
+type Option func(*Bundler)
+
+// WithBaseURI sets the URI relative $refs of the root schema are resolved
+// against. It defaults to a file in the working directory
+func WithBaseURI(uri string) Option {
+	return func(b *Bundler) {
+		b.baseURI = uri
+	}
+}
+
+// WithOffline forbids fetching $refs over the network. Remote $refs must
+// then be mapped to local files with WithRefMapping
+func WithOffline() Option {
+	return func(b *Bundler) {
+		b.offline = true
+	}
+}
+
+// WithRefMapping resolves $refs starting with prefix from files under dir
+// instead of fetching them, such as mapping "https://schemas.example.com/"
+// to a vendored copy
+func WithRefMapping(prefix, dir string) Option {
+	return func(b *Bundler) {
+		b.mappings[prefix] = dir
+	}
+}
+
+// WithHTTPClient sets the client used to fetch remote $refs
+func WithHTTPClient(client *http.Client) Option {
+	return func(b *Bundler) {
+		b.client = client
+	}
+}
+
+// Bundler resolves the external $refs of a schema ahead of time, embedding
+// every referenced document under "definitions" and rewriting the $refs to
+// point at them, so the bundled schema is self-contained and validates
+// without loading anything
+type Bundler struct {
+	baseURI  string
+	offline  bool
+	mappings map[string]string
+	client   *http.Client
+}
+
+// NewBundler creates a bundler
+func NewBundler(options ...Option) *Bundler {
+	b := &Bundler{
+		mappings: make(map[string]string),
+		client:   http.DefaultClient,
+	}
+	for _, option := range options {
+		option(b)
+	}
+	return b
+}
+
+// Bundle returns schema with its external $refs resolved
+func (b *Bundler) Bundle(ctx context.Context, schema []byte) ([]byte, error) {
+	var root interface{}
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	rootMap, ok := root.(map[string]interface{})
+	if !ok {
+		// Boolean schemas have no $refs
+		return schema, nil
+	}
+
+	base, err := b.rootURI()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &bundleState{
+		bundler: b,
+		ctx:     ctx,
+		roots:   map[string]bool{documentURI(base): true},
+		names:   make(map[string]string),
+	}
+	if id, ok := rootMap["$id"].(string); ok {
+		if idURI, err := base.Parse(id); err == nil {
+			base = idURI
+			state.roots[documentURI(idURI)] = true
+		}
+	}
+
+	state.definitions, _ = rootMap["definitions"].(map[string]interface{})
+	if state.definitions == nil {
+		state.definitions = make(map[string]interface{})
+	}
+
+	if err := state.rewrite(rootMap, base, documentURI(base), ""); err != nil {
+		return nil, err
+	}
+	if len(state.definitions) > 0 {
+		rootMap["definitions"] = state.definitions
+	}
+
+	bundled, err := json.MarshalIndent(rootMap, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundled schema: %w", err)
+	}
+	return bundled, nil
+}
+
+// BundleFile bundles the schema in path, resolving relative $refs against
+// the file unless a base URI was given
+func (b *Bundler) BundleFile(ctx context.Context, schemaPath string) ([]byte, error) {
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	bundler := *b
+	if bundler.baseURI == "" {
+		abs, err := filepath.Abs(schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve schema path: %w", err)
+		}
+		bundler.baseURI = fileURI(abs)
+	}
+	return bundler.Bundle(ctx, data)
+}
+
+// rootURI returns the URI of the root schema
+func (b *Bundler) rootURI() (*url.URL, error) {
+	if b.baseURI != "" {
+		base, err := url.Parse(b.baseURI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URI %q: %w", b.baseURI, err)
+		}
+		return base, nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return url.Parse(fileURI(filepath.Join(wd, "schema.json")))
+}
+
+// fetch loads a referenced document from a mapped directory, the local
+// filesystem or, unless offline, the network
+func (b *Bundler) fetch(ctx context.Context, uri *url.URL) ([]byte, error) {
+	location := uri.String()
+
+	prefixes := make([]string, 0, len(b.mappings))
+	for prefix := range b.mappings {
+		prefixes = append(prefixes, prefix)
+	}
+	// Prefer the most specific mapping
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	for _, prefix := range prefixes {
+		if rest, ok := strings.CutPrefix(location, prefix); ok {
+			return os.ReadFile(filepath.Join(b.mappings[prefix], filepath.FromSlash(rest)))
+		}
+	}
+
+	switch uri.Scheme {
+	case "file":
+		return os.ReadFile(filePath(uri))
+	case "http", "https":
+		if b.offline {
+			return nil, fmt.Errorf("cannot fetch %s: %w", location, ErrNetworkDisabled)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := b.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", location, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch %s: HTTP %d", location, resp.StatusCode)
+		}
+		return io.ReadAll(resp.Body)
+	default:
+		return nil, fmt.Errorf("unsupported $ref scheme %q in %s", uri.Scheme, location)
+	}
+}
+
+// bundleState tracks the documents embedded while bundling one schema
+type bundleState struct {
+	bundler     *Bundler
+	ctx         context.Context
+	roots       map[string]bool
+	definitions map[string]interface{}
+	names       map[string]string
+}
+
+// nonSchemaKeywords hold instance data rather than subschemas, so a "$ref"
+// key inside them is not a reference
+var nonSchemaKeywords = map[string]bool{
+	"enum":     true,
+	"const":    true,
+	"default":  true,
+	"examples": true,
+}
+
+// rewrite resolves the $refs in node, which belongs to the document docURI
+// embedded at the JSON pointer prefix within the bundle
+func (s *bundleState) rewrite(node interface{}, base *url.URL, docURI, prefix string) error {
+	switch value := node.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if err := s.rewrite(item, base, docURI, prefix); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok {
+			rewritten, err := s.resolve(ref, base, docURI, prefix)
+			if err != nil {
+				return err
+			}
+			value["$ref"] = rewritten
+		}
+
+		// Visit keys in order so embedded names are deterministic
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if key == "$ref" || nonSchemaKeywords[key] {
+				continue
+			}
+			if err := s.rewrite(value[key], base, docURI, prefix); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the bundle-internal form of ref, embedding the document
+// it points to when needed
+func (s *bundleState) resolve(ref string, base *url.URL, docURI, prefix string) (string, error) {
+	target, err := base.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid $ref %q: %w", ref, err)
+	}
+
+	fragment := target.EscapedFragment()
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return "", fmt.Errorf("unsupported $ref %q: only JSON pointer fragments can be bundled", ref)
+	}
+
+	targetDoc := documentURI(target)
+	switch {
+	case s.roots[targetDoc]:
+		return "#" + fragment, nil
+	case targetDoc == docURI:
+		return "#" + prefix + fragment, nil
+	}
+
+	name, err := s.embed(target)
+	if err != nil {
+		return "", err
+	}
+	return "#/definitions/" + name + fragment, nil
+}
+
+// embed adds the document at target to the definitions, once, and returns
+// its name there
+func (s *bundleState) embed(target *url.URL) (string, error) {
+	docURI := documentURI(target)
+	if name, ok := s.names[docURI]; ok {
+		return name, nil
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		return "", err
+	}
+
+	docURL := *target
+	docURL.Fragment, docURL.RawFragment = "", ""
+	data, err := s.bundler.fetch(s.ctx, &docURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve $ref %s: %w", docURI, err)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", docURI, err)
+	}
+
+	name := s.uniqueName(docURI)
+	s.names[docURI] = name
+	s.definitions[name] = doc
+
+	if docMap, ok := doc.(map[string]interface{}); ok {
+		// The embedded document is addressed by pointer from now on
+		delete(docMap, "$schema")
+		delete(docMap, "$id")
+	}
+	if err := s.rewrite(doc, &docURL, docURI, "/definitions/"+name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// uniqueName derives an unused definitions name from a document URI
+func (s *bundleState) uniqueName(docURI string) string {
+	parsed, _ := url.Parse(docURI)
+	base := path.Base(parsed.Path)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	base = unsafeNameChars.ReplaceAllString(base, "_")
+	if base == "" || base == "." || base == "/" {
+		base = "schema"
+	}
+
+	name := base
+	for i := 2; ; i++ {
+		if _, taken := s.definitions[name]; !taken {
+			return name
+		}
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+}
+
+// documentURI returns uri without its fragment
+func documentURI(uri *url.URL) string {
+	doc := *uri
+	doc.Fragment, doc.RawFragment = "", ""
+	return doc.String()
+}
+
+// fileURI returns the file URI of an absolute path
+func fileURI(path string) string {
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		// Windows paths such as C:/schemas
+		slashed = "/" + slashed
+	}
+	return (&url.URL{Scheme: "file", Path: slashed}).String()
+}
+
+// filePath returns the local path of a file URI
+func filePath(uri *url.URL) string {
+	p := uri.Path
+	if len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		// Windows paths such as /C:/schemas
+		p = p[1:]
+	}
+	return filepath.FromSlash(p)
+}
@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// DefaultCacheSize is the number of compiled schemas kept by default
+const DefaultCacheSize = 128
+
+// CacheStats reports how the compiled schema cache is performing
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Size   int    `json:"size"`
+}
+
+// cacheEntry is a compiled schema, or the compilation in progress
+type cacheEntry struct {
+	key    [sha256.Size]byte
+	once   sync.Once
+	schema *gojsonschema.Schema
+	err    error
+}
+
+// schemaCache keeps compiled schemas keyed by the SHA-256 of their content,
+// evicting the least recently used beyond its capacity
+type schemaCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[[sha256.Size]byte]*list.Element
+	order    *list.List
+	hits     uint64
+	misses   uint64
+}
+
+var cache = &schemaCache{
+	capacity: DefaultCacheSize,
+	entries:  make(map[[sha256.Size]byte]*list.Element),
+	order:    list.New(),
+}
+
+// SetCacheSize sets how many compiled schemas are kept. Zero disables the
+// cache, so every New compiles its schema
+func SetCacheSize(size int) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if size < 0 {
+		size = 0
+	}
+	cache.capacity = size
+	cache.evict()
+}
+
+// ClearCache removes every compiled schema from the cache
+func ClearCache() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.entries = make(map[[sha256.Size]byte]*list.Element)
+	cache.order.Init()
+	cache.hits, cache.misses = 0, 0
+}
+
+// GetCacheStats returns the compiled schema cache statistics
+func GetCacheStats() CacheStats {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return CacheStats{Hits: cache.hits, Misses: cache.misses, Size: cache.order.Len()}
+}
+
+// compile returns the compiled schema for content, compiling it at most
+// once while it stays cached, even when requested by several goroutines
+func compile(schema string) (*gojsonschema.Schema, error) {
+	cache.mu.Lock()
+	if cache.capacity == 0 {
+		cache.misses++
+		cache.mu.Unlock()
+		return gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema))
+	}
+
+	key := sha256.Sum256([]byte(schema))
+	var entry *cacheEntry
+	if element, ok := cache.entries[key]; ok {
+		cache.hits++
+		cache.order.MoveToFront(element)
+		entry = element.Value.(*cacheEntry)
+	} else {
+		cache.misses++
+		entry = &cacheEntry{key: key}
+		cache.entries[key] = cache.order.PushFront(entry)
+		cache.evict()
+	}
+	cache.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.schema, entry.err = gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema))
+	})
+
+	if entry.err != nil {
+		// Don't keep failures, such as a $ref that could not be fetched,
+		// so the next call compiles the schema again
+		cache.mu.Lock()
+		if element, ok := cache.entries[key]; ok && element.Value.(*cacheEntry) == entry {
+			cache.order.Remove(element)
+			delete(cache.entries, key)
+		}
+		cache.mu.Unlock()
+	}
+	return entry.schema, entry.err
+}
+
+// evict removes the least recently used entries beyond capacity without
+// locking
+func (c *schemaCache) evict() {
+	for c.order.Len() > c.capacity {
+		element := c.order.Back()
+		c.order.Remove(element)
+		delete(c.entries, element.Value.(*cacheEntry).key)
+	}
+}
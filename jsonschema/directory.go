@@ -0,0 +1,255 @@
+package jsonschema
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultInclude is the glob used when DirectoryOptions has no Include
+const DefaultInclude = "**/*.json"
+
+// DirectoryOptions configures directory validation. Globs use forward
+// slashes and are matched against paths relative to the directory, with
+// "**" matching any number of directories. Globs without a slash match
+// the file name at any depth
+type DirectoryOptions struct {
+	// Include selects the files to validate, defaulting to DefaultInclude
+	Include []string
+	// Exclude skips matching files and directories, even when included
+	Exclude []string
+	// Workers is the number of files validated concurrently, defaulting
+	// to one per CPU
+	Workers int
+}
+
+// FileResult is the outcome of validating one file
+type FileResult struct {
+	Path   string            `json:"path"`
+	Valid  bool              `json:"valid"`
+	Errors []ValidationError `json:"errors,omitempty"`
+	// Error is set when the file could not be read or parsed
+	Error string `json:"error,omitempty"`
+}
+
+// ErrorCount returns the number of validation errors of the file, counting
+// an unreadable file as one
+func (r FileResult) ErrorCount() int {
+	if r.Error != "" {
+		return 1
+	}
+	return len(r.Errors)
+}
+
+// DirectoryReport aggregates the results of validating a directory
+type DirectoryReport struct {
+	Root     string        `json:"root"`
+	Files    []FileResult  `json:"files"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Errors   int           `json:"errors"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Valid reports whether every file passed
+func (r *DirectoryReport) Valid() bool {
+	return r.Failed == 0
+}
+
+// Headers returns the column headers for rendering the report with the
+// table package
+func (r *DirectoryReport) Headers() []string {
+	return []string{"FILE", "STATUS", "ERRORS"}
+}
+
+// Rows returns one row per file, sorted by path, for rendering the report
+// with the table package
+func (r *DirectoryReport) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Files))
+	for _, file := range r.Files {
+		status := "pass"
+		if !file.Valid {
+			status = "fail"
+		}
+		rows = append(rows, []string{file.Path, status, strconv.Itoa(file.ErrorCount())})
+	}
+	return rows
+}
+
+// ValidateDirectory validates the files of a directory, recursively,
+// using a pool of workers. Files that cannot be read or parsed are
+// reported as failed rather than stopping the run
+func (v *Validator) ValidateDirectory(ctx context.Context, dirPath string, options DirectoryOptions) (*DirectoryReport, error) {
+	start := time.Now()
+
+	paths, err := findFiles(ctx, dirPath, options)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	files := make([]FileResult, len(paths))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				files[index] = v.validateDirectoryFile(ctx, paths[index])
+			}
+		}()
+	}
+
+feed:
+	for i := range paths {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &DirectoryReport{Root: dirPath, Files: files}
+	for _, file := range files {
+		if file.Valid {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Errors += file.ErrorCount()
+	}
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+func (v *Validator) validateDirectoryFile(ctx context.Context, filePath string) FileResult {
+	result, err := v.ValidateFile(ctx, filePath)
+	if err != nil {
+		return FileResult{Path: filePath, Error: err.Error()}
+	}
+	return FileResult{Path: filePath, Valid: result.Valid, Errors: result.Errors}
+}
+
+// ValidateDirectoryReport validates the files of a directory against a
+// schema string and returns a per-file report
+func ValidateDirectoryReport(ctx context.Context, dirPath string, schema string, options DirectoryOptions) (*DirectoryReport, error) {
+	validator, err := New(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return validator.ValidateDirectory(ctx, dirPath, options)
+}
+
+// findFiles returns the sorted paths under dirPath selected by options
+func findFiles(ctx context.Context, dirPath string, options DirectoryOptions) ([]string, error) {
+	include := options.Include
+	if len(include) == 0 {
+		include = []string{DefaultInclude}
+	}
+	for _, pattern := range append(append([]string(nil), include...), options.Exclude...) {
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+
+	var paths []string
+	err := filepath.WalkDir(dirPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Check if context is cancelled
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		rel, err := filepath.Rel(dirPath, filePath)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if matchAny(options.Exclude, rel) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.IsDir() && matchAny(include, rel) {
+			paths = append(paths, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash-separated relative path against a glob
+func matchGlob(pattern, rel string) bool {
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(rel))
+		return matched
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// "**" matches zero or more directories
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
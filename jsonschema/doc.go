@@ -6,8 +6,13 @@
 //   - Multiple Input Sources: Support for strings, files, URLs, and Go objects
 //   - Detailed Error Reporting: Comprehensive validation error information
 //   - Schema Management: Extract metadata from schemas (version, title, description)
-//   - Directory Validation: Validate all JSON files in a directory
+//   - Directory Validation: Validate directory trees concurrently with include/exclude globs and a per-file report
 //   - External Library Integration: Uses gojsonschema for robust validation
+//   - Compiled Schema Cache: Schemas compiled once per content hash, shared across validators
+//   - Concurrent Validation: Validators are safe for concurrent use; ValidateMany uses a worker pool
+//   - Human-Friendly Errors: FormatResult renders JSON pointers, expected vs actual values and enum suggestions
+//   - Streaming Validation: ValidateStream checks NDJSON and large JSON arrays record by record with bounded memory
+//   - Offline $ref Bundling: Bundler embeds external $refs into a self-contained schema for deterministic CI runs
 //
 // Example:
 //   validator, err := jsonschema.New(schemaString)
@@ -0,0 +1,276 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// newValidationError converts a gojsonschema error, keeping the error type,
+// the JSON pointer of the offending value and the details needed to
+// explain it
+func newValidationError(resultErr gojsonschema.ResultError) ValidationError {
+	validationErr := ValidationError{
+		Field:       resultErr.Field(),
+		Description: resultErr.Description(),
+		Context:     resultErr.Context().String(),
+		Type:        resultErr.Type(),
+		Pointer:     contextPointer(resultErr.Context()),
+	}
+
+	details := make(map[string]string)
+	for key, value := range resultErr.Details() {
+		// field and context duplicate the fields above
+		if key == "field" || key == "context" {
+			continue
+		}
+		details[key] = fmt.Sprint(value)
+	}
+	if len(details) > 0 {
+		validationErr.Details = details
+	}
+
+	// Objects and arrays are the parent of the problem, not the problem
+	switch value := resultErr.Value().(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		validationErr.Value = value
+	}
+
+	switch validationErr.Type {
+	case "required", "additional_property_not_allowed":
+		if property, ok := validationErr.Details["property"]; ok {
+			validationErr.Pointer += "/" + escapePointer(property)
+		}
+	}
+
+	return validationErr
+}
+
+// contextPointer returns the JSON pointer of a gojsonschema context, such
+// as /servers/0/name for (root).servers.0.name
+func contextPointer(context *gojsonschema.JsonContext) string {
+	if context == nil {
+		return ""
+	}
+
+	// Property names may contain dots, so split on a byte JSON keys rarely
+	// contain instead
+	const separator = "\x00"
+	segments := strings.Split(context.String(separator), separator)
+
+	var pointer strings.Builder
+	for _, segment := range segments[1:] {
+		pointer.WriteString("/" + escapePointer(segment))
+	}
+	return pointer.String()
+}
+
+// escapePointer escapes a JSON pointer reference token
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// Expected describes what the schema requires at the error location, or
+// returns an empty string when there is nothing more specific than the
+// description
+func (e ValidationError) Expected() string {
+	detail := func(key string) string {
+		return e.Details[key]
+	}
+
+	switch e.Type {
+	case "invalid_type":
+		return detail("expected")
+	case "enum":
+		return "one of " + detail("allowed")
+	case "const":
+		return detail("allowed")
+	case "required":
+		return "property to be present"
+	case "additional_property_not_allowed":
+		return "no additional properties"
+	case "number_gte":
+		return ">= " + detail("min")
+	case "number_gt":
+		return "> " + detail("min")
+	case "number_lte":
+		return "<= " + detail("max")
+	case "number_lt":
+		return "< " + detail("max")
+	case "multiple_of":
+		return "multiple of " + detail("multiple")
+	case "string_gte":
+		return "at least " + detail("min") + " characters"
+	case "string_lte":
+		return "at most " + detail("max") + " characters"
+	case "array_min_items":
+		return "at least " + detail("min") + " items"
+	case "array_max_items":
+		return "at most " + detail("max") + " items"
+	case "array_min_properties":
+		return "at least " + detail("min") + " properties"
+	case "array_max_properties":
+		return "at most " + detail("max") + " properties"
+	case "pattern":
+		return "match for pattern " + detail("pattern")
+	case "format":
+		return "valid " + detail("format")
+	}
+	return ""
+}
+
+// Actual describes the offending value, or returns an empty string when it
+// is unknown
+func (e ValidationError) Actual() string {
+	switch e.Type {
+	case "required":
+		return "missing"
+	case "invalid_type":
+		if given, ok := e.Details["given"]; ok {
+			if e.Value == nil {
+				return given
+			}
+			return fmt.Sprintf("%s (%s)", given, formatValue(e.Value))
+		}
+	}
+
+	if e.Value == nil {
+		return ""
+	}
+	if text, ok := e.Value.(string); ok {
+		switch e.Type {
+		case "string_gte", "string_lte":
+			return fmt.Sprintf("%s (%d characters)", formatValue(text), len([]rune(text)))
+		}
+	}
+	return formatValue(e.Value)
+}
+
+// Suggestion returns the allowed enum value closest to a mistyped string,
+// or an empty string when no value is close enough
+func (e ValidationError) Suggestion() string {
+	if e.Type != "enum" {
+		return ""
+	}
+	actual, ok := e.Value.(string)
+	if !ok {
+		return ""
+	}
+	allowedList := e.Details["allowed"]
+
+	// The allowed values are JSON encoded and joined by ", "
+	var allowed []interface{}
+	if err := json.Unmarshal([]byte("["+allowedList+"]"), &allowed); err != nil {
+		return ""
+	}
+
+	best, bestDistance := "", -1
+	for _, candidate := range allowed {
+		text, ok := candidate.(string)
+		if !ok {
+			continue
+		}
+		distance := editDistance(strings.ToLower(actual), strings.ToLower(text))
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = text, distance
+		}
+	}
+
+	// Allow roughly one typo per three characters
+	limit := len([]rune(best)) / 3
+	if limit < 2 {
+		limit = 2
+	}
+	if bestDistance < 0 || bestDistance > limit {
+		return ""
+	}
+	return best
+}
+
+// FormatResult renders a validation result for display in a terminal
+func FormatResult(result *ValidationResult) string {
+	var builder strings.Builder
+	_ = WriteResult(&builder, result)
+	return builder.String()
+}
+
+// WriteResult writes a validation result as annotated text, one block per
+// error with its JSON pointer, what was expected, what was found and, for
+// mistyped enum values, the closest allowed value
+func WriteResult(w io.Writer, result *ValidationResult) error {
+	if result == nil || result.Valid || len(result.Errors) == 0 {
+		_, err := fmt.Fprintln(w, "Document is valid")
+		return err
+	}
+
+	errs := append([]ValidationError(nil), result.Errors...)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Pointer < errs[j].Pointer })
+
+	noun := "errors"
+	if len(errs) == 1 {
+		noun = "error"
+	}
+	if _, err := fmt.Fprintf(w, "Document is invalid: %d validation %s\n", len(errs), noun); err != nil {
+		return err
+	}
+
+	for _, validationErr := range errs {
+		pointer := validationErr.Pointer
+		if pointer == "" {
+			pointer = "(root)"
+		}
+
+		lines := []string{"", "  " + pointer, "    " + validationErr.Description}
+		if expected := validationErr.Expected(); expected != "" {
+			lines = append(lines, "    expected: "+expected)
+		}
+		if actual := validationErr.Actual(); actual != "" {
+			lines = append(lines, "    actual:   "+actual)
+		}
+		if suggestion := validationErr.Suggestion(); suggestion != "" {
+			lines = append(lines, fmt.Sprintf("    hint:     did you mean %q?", suggestion))
+		}
+
+		if _, err := fmt.Fprintln(w, strings.Join(lines, "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatValue renders a value as JSON, falling back to Go formatting
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
@@ -7,21 +7,30 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"runtime"
+	"sync"
 
 	"github.com/xeipuuv/gojsonschema"
 )
 
-// Validator represents a JSON Schema validator
+// Validator represents a JSON Schema validator. A Validator is safe for
+// concurrent use, so one instance can be shared across goroutines
 type Validator struct {
 	schema *gojsonschema.Schema
 }
 
-// ValidationError represents a validation error
+// ValidationError represents a validation error. Type is the gojsonschema
+// error type, such as "enum" or "invalid_type", Pointer is the JSON pointer
+// of the offending value and Details holds the schema constraint, such as
+// "min" or "allowed"
 type ValidationError struct {
-	Field       string `json:"field"`
-	Description string `json:"description"`
-	Context     string `json:"context"`
+	Field       string            `json:"field"`
+	Description string            `json:"description"`
+	Context     string            `json:"context"`
+	Type        string            `json:"type,omitempty"`
+	Pointer     string            `json:"pointer,omitempty"`
+	Value       interface{}       `json:"value,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
 }
 
 // ValidationResult represents the result of validation
@@ -30,10 +39,21 @@ type ValidationResult struct {
 	Errors []ValidationError `json:"errors,omitempty"`
 }
 
-// New creates a new validator from a schema string
-func New(schema string) (*Validator, error) {
-	schemaLoader := gojsonschema.NewStringLoader(schema)
-	schemaObj, err := gojsonschema.NewSchema(schemaLoader)
+// New creates a new validator from a schema string. Compiled schemas are
+// cached by content, so validators for the same schema share one compiled
+// copy and New is cheap to call from every call site. With options, the
+// schema's external $refs are bundled first, so WithOffline guarantees
+// validation never touches the network
+func New(schema string, options ...Option) (*Validator, error) {
+	if len(options) > 0 {
+		bundled, err := NewBundler(options...).Bundle(context.Background(), []byte(schema))
+		if err != nil {
+			return nil, fmt.Errorf("failed to bundle schema: %w", err)
+		}
+		schema = string(bundled)
+	}
+
+	schemaObj, err := compile(schema)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create schema: %w", err)
 	}
@@ -41,8 +61,17 @@ func New(schema string) (*Validator, error) {
 	return &Validator{schema: schemaObj}, nil
 }
 
-// NewFromFile creates a new validator from a schema file
-func NewFromFile(schemaPath string) (*Validator, error) {
+// NewFromFile creates a new validator from a schema file. With options,
+// relative $refs are resolved against the file
+func NewFromFile(schemaPath string, options ...Option) (*Validator, error) {
+	if len(options) > 0 {
+		bundled, err := NewBundler(options...).BundleFile(context.Background(), schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bundle schema: %w", err)
+		}
+		return New(string(bundled))
+	}
+
 	schemaBytes, err := os.ReadFile(schemaPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read schema file: %w", err)
@@ -51,8 +80,9 @@ func NewFromFile(schemaPath string) (*Validator, error) {
 	return New(string(schemaBytes))
 }
 
-// NewFromURL creates a new validator from a schema URL
-func NewFromURL(schemaURL string) (*Validator, error) {
+// NewFromURL creates a new validator from a schema URL. With options,
+// relative $refs are resolved against the URL
+func NewFromURL(schemaURL string, options ...Option) (*Validator, error) {
 	resp, err := http.Get(schemaURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch schema from URL: %w", err)
@@ -68,7 +98,10 @@ func NewFromURL(schemaURL string) (*Validator, error) {
 		return nil, fmt.Errorf("failed to read schema response: %w", err)
 	}
 
-	return New(string(schemaBytes))
+	if len(options) > 0 {
+		options = append([]Option{WithBaseURI(schemaURL)}, options...)
+	}
+	return New(string(schemaBytes), options...)
 }
 
 // Validate validates JSON data against the schema
@@ -93,11 +126,7 @@ func (v *Validator) Validate(ctx context.Context, data []byte) (*ValidationResul
 
 	if !result.Valid() {
 		for _, err := range result.Errors() {
-			validationResult.Errors = append(validationResult.Errors, ValidationError{
-				Field:       err.Field(),
-				Description: err.Description(),
-				Context:     err.Context().String(),
-			})
+			validationResult.Errors = append(validationResult.Errors, newValidationError(err))
 		}
 	}
 
@@ -168,6 +197,66 @@ func (v *Validator) ValidateURL(ctx context.Context, url string) (*ValidationRes
 	return v.Validate(ctx, data)
 }
 
+// ValidateMany validates several JSON documents concurrently using up to
+// workers goroutines, or one per CPU when workers is not positive. Results
+// are returned in the order of documents
+func (v *Validator) ValidateMany(ctx context.Context, documents [][]byte, workers int) ([]*ValidationResult, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(documents) {
+		workers = len(documents)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*ValidationResult, len(documents))
+	indexes := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				result, err := v.Validate(ctx, documents[index])
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("failed to validate document %d: %w", index, err)
+						cancel()
+					})
+					continue
+				}
+				results[index] = result
+			}
+		}()
+	}
+
+feed:
+	for i := range documents {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 // Convenience functions for common use cases
 
 // Validate validates JSON data against a schema string
@@ -289,41 +378,21 @@ func GetSchemaDescription(schema string) (string, error) {
 	return "", fmt.Errorf("schema description not found")
 }
 
-// ValidateDirectory validates all JSON files in a directory against a schema
+// ValidateDirectory validates all JSON files in a directory, recursively
+// and concurrently, against a schema. Use ValidateDirectoryReport for globs
+// and a per-file report
 func ValidateDirectory(ctx context.Context, dirPath string, schema string) (map[string]*ValidationResult, error) {
-	validator, err := New(schema)
+	report, err := ValidateDirectoryReport(ctx, dirPath, schema, DirectoryOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	results := make(map[string]*ValidationResult)
-
-	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Check if context is cancelled
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		if !info.IsDir() && filepath.Ext(path) == ".json" {
-			result, err := validator.ValidateFile(ctx, path)
-			if err != nil {
-				return fmt.Errorf("failed to validate %s: %w", path, err)
-			}
-			results[path] = result
+	results := make(map[string]*ValidationResult, len(report.Files))
+	for _, file := range report.Files {
+		if file.Error != "" {
+			return nil, fmt.Errorf("failed to validate %s: %s", file.Path, file.Error)
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
+		results[file.Path] = &ValidationResult{Valid: file.Valid, Errors: file.Errors}
 	}
-
 	return results, nil
-}
\ No newline at end of file
+}
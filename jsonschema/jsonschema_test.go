@@ -1,10 +1,17 @@
 package jsonschema
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -432,4 +439,563 @@ func TestValidationError(t *testing.T) {
 			t.Error("Expected non-empty description")
 		}
 	}
-}
\ No newline at end of file
+}
+func TestSchemaCache(t *testing.T) {
+	ClearCache()
+	defer ClearCache()
+
+	first, err := New(validSchema)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	second, err := New(validSchema)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	if first.schema != second.schema {
+		t.Error("Expected validators for the same schema to share the compiled schema")
+	}
+	if stats := GetCacheStats(); stats.Hits != 1 || stats.Misses != 1 || stats.Size != 1 {
+		t.Errorf("Expected 1 hit, 1 miss and 1 entry, got %+v", stats)
+	}
+
+	if _, err := New(invalidSchema); err == nil {
+		t.Error("Expected error for invalid schema")
+	}
+	if stats := GetCacheStats(); stats.Size != 1 {
+		t.Errorf("Expected invalid schema not to be cached, got %d entries", stats.Size)
+	}
+
+	SetCacheSize(1)
+	defer SetCacheSize(DefaultCacheSize)
+	if _, err := New(`{"type": "string"}`); err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	third, _ := New(validSchema)
+	if third.schema == first.schema {
+		t.Error("Expected least recently used schema to be evicted")
+	}
+
+	SetCacheSize(0)
+	fourth, _ := New(validSchema)
+	if fourth.schema == third.schema {
+		t.Error("Expected a fresh compile with the cache disabled")
+	}
+}
+
+func TestValidateMany(t *testing.T) {
+	validator, err := New(validSchema)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	documents := make([][]byte, 50)
+	for i := range documents {
+		if i%2 == 0 {
+			documents[i] = []byte(fmt.Sprintf(`{"name": "user%d", "age": %d}`, i, i))
+		} else {
+			documents[i] = []byte(`{"name": ""}`)
+		}
+	}
+
+	results, err := validator.ValidateMany(context.Background(), documents, 4)
+	if err != nil {
+		t.Fatalf("Failed to validate documents: %v", err)
+	}
+	for i, result := range results {
+		if result.Valid != (i%2 == 0) {
+			t.Errorf("Expected document %d valid=%v, got %v", i, i%2 == 0, result.Valid)
+		}
+	}
+
+	documents[7] = []byte(`not json`)
+	if _, err := validator.ValidateMany(context.Background(), documents, 0); err == nil {
+		t.Error("Expected error for malformed document")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := validator.ValidateMany(ctx, documents, 4); err == nil {
+		t.Error("Expected error for cancelled context")
+	}
+}
+
+func TestValidatorConcurrentNew(t *testing.T) {
+	ClearCache()
+	defer ClearCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			validator, err := New(validSchema)
+			if err != nil {
+				t.Errorf("Failed to create validator: %v", err)
+				return
+			}
+			if _, err := validator.ValidateString(context.Background(), `{"name": "a", "age": 1}`); err != nil {
+				t.Errorf("Failed to validate: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats := GetCacheStats(); stats.Misses != 1 {
+		t.Errorf("Expected the schema to be compiled once, got %d misses", stats.Misses)
+	}
+}
+
+func TestBundleFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	writeFile("root.json", `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"address": {"$ref": "address.json"},
+			"street": {"$ref": "address.json#/properties/street"}
+		},
+		"required": ["address"]
+	}`)
+	writeFile("address.json", `{
+		"$id": "address.json",
+		"type": "object",
+		"properties": {
+			"street": {"type": "string"},
+			"next": {"$ref": "#"}
+		},
+		"required": ["street"]
+	}`)
+
+	bundled, err := NewBundler().BundleFile(context.Background(), filepath.Join(dir, "root.json"))
+	if err != nil {
+		t.Fatalf("Failed to bundle schema: %v", err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(bundled, &schema); err != nil {
+		t.Fatalf("Bundled schema is not valid JSON: %v", err)
+	}
+	properties := schema["properties"].(map[string]interface{})
+	if ref := properties["address"].(map[string]interface{})["$ref"]; ref != "#/definitions/address" {
+		t.Errorf("Expected address $ref to be rewritten, got %v", ref)
+	}
+	if ref := properties["street"].(map[string]interface{})["$ref"]; ref != "#/definitions/address/properties/street" {
+		t.Errorf("Expected street $ref to be rewritten, got %v", ref)
+	}
+
+	address := schema["definitions"].(map[string]interface{})["address"].(map[string]interface{})
+	if _, ok := address["$id"]; ok {
+		t.Error("Expected $id to be removed from the embedded schema")
+	}
+	next := address["properties"].(map[string]interface{})["next"].(map[string]interface{})
+	if ref := next["$ref"]; ref != "#/definitions/address" {
+		t.Errorf("Expected self $ref to point at the embedded schema, got %v", ref)
+	}
+
+	validator, err := NewFromFile(filepath.Join(dir, "root.json"), WithOffline())
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	result, err := validator.ValidateString(context.Background(), `{"address": {"street": "Main"}}`)
+	if err != nil || !result.Valid {
+		t.Errorf("Expected valid document, got %v %v", result, err)
+	}
+	result, err = validator.ValidateString(context.Background(), `{"address": {}}`)
+	if err != nil || result.Valid {
+		t.Errorf("Expected invalid document, got %v %v", result, err)
+	}
+}
+
+func TestBundleRemote(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/name.json":
+			fmt.Fprint(w, `{"type": "string", "minLength": 1}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	schema := fmt.Sprintf(`{
+		"type": "object",
+		"properties": {
+			"first": {"$ref": "%[1]s/name.json"},
+			"last": {"$ref": "%[1]s/name.json"}
+		}
+	}`, server.URL)
+
+	bundled, err := NewBundler().Bundle(context.Background(), []byte(schema))
+	if err != nil {
+		t.Fatalf("Failed to bundle schema: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the remote schema to be fetched once, got %d requests", requests)
+	}
+
+	validator, err := New(string(bundled))
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	result, err := validator.ValidateString(context.Background(), `{"first": ""}`)
+	if err != nil || result.Valid {
+		t.Errorf("Expected invalid document, got %v %v", result, err)
+	}
+
+	if _, err := NewBundler().Bundle(context.Background(), []byte(`{"$ref": "`+server.URL+`/missing.json"}`)); err == nil {
+		t.Error("Expected error for missing remote schema")
+	}
+}
+
+func TestBundleOffline(t *testing.T) {
+	schema := `{"properties": {"name": {"$ref": "https://schemas.example.com/common/name.json"}}}`
+
+	_, err := New(schema, WithOffline())
+	if !errors.Is(err, ErrNetworkDisabled) {
+		t.Fatalf("Expected ErrNetworkDisabled, got %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "common"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "common", "name.json"), []byte(`{"type": "string"}`), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	validator, err := New(schema, WithOffline(), WithRefMapping("https://schemas.example.com/", dir))
+	if err != nil {
+		t.Fatalf("Failed to create validator with mapping: %v", err)
+	}
+	result, err := validator.ValidateString(context.Background(), `{"name": 42}`)
+	if err != nil || result.Valid {
+		t.Errorf("Expected invalid document, got %v %v", result, err)
+	}
+}
+
+func TestBundleDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(`{"type": "string", "enum": [{"$ref": "ignored.json"}]}`), 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+	schema := `{"properties": {"b": {"$ref": "b.json"}, "a": {"$ref": "a.json"}}}`
+
+	bundler := NewBundler(WithBaseURI(fileURI(filepath.Join(dir, "root.json"))))
+	first, err := bundler.Bundle(context.Background(), []byte(schema))
+	if err != nil {
+		t.Fatalf("Failed to bundle schema: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		again, err := bundler.Bundle(context.Background(), []byte(schema))
+		if err != nil {
+			t.Fatalf("Failed to bundle schema: %v", err)
+		}
+		if string(again) != string(first) {
+			t.Fatal("Expected bundling to be deterministic")
+		}
+	}
+}
+
+func TestValidateStreamNDJSON(t *testing.T) {
+	input := "\n" +
+		`{"name": "John", "age": 30}` + "\n" +
+		`{"name": "Jane"}` + "\n" +
+		"\n" +
+		`{"name": ` + "\r\n" +
+		`{"name": "Bob", "age": 5}`
+
+	var records []StreamRecord
+	summary, err := ValidateStream(context.Background(), strings.NewReader(input), validSchema, func(record StreamRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to validate stream: %v", err)
+	}
+
+	if summary.Records != 4 || summary.Valid != 2 || summary.Invalid != 2 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+
+	expected := []struct {
+		line  int
+		valid bool
+	}{{2, true}, {3, false}, {5, false}, {6, true}}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %d", len(expected), len(records))
+	}
+	for i, want := range expected {
+		if records[i].Index != i+1 || records[i].Line != want.line || records[i].Result.Valid != want.valid {
+			t.Errorf("Record %d: expected line %d valid %v, got %+v", i, want.line, want.valid, records[i])
+		}
+	}
+	if !strings.Contains(records[2].Result.Errors[0].Description, "invalid JSON") {
+		t.Errorf("Expected malformed record to be reported, got %v", records[2].Result.Errors)
+	}
+}
+
+func TestValidateStreamArray(t *testing.T) {
+	validator, err := New(validSchema)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	input := ` [{"name": "John", "age": 30}, {"age": -1}, {"name": "Jane", "age": 25}]`
+	var invalid []int
+	summary, err := validator.ValidateStream(context.Background(), strings.NewReader(input), func(record StreamRecord) error {
+		if !record.Result.Valid {
+			invalid = append(invalid, record.Index)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to validate stream: %v", err)
+	}
+	if summary.Records != 3 || summary.Valid != 2 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if len(invalid) != 1 || invalid[0] != 2 {
+		t.Errorf("Expected record 2 to be invalid, got %v", invalid)
+	}
+
+	if _, err := validator.ValidateStream(context.Background(), strings.NewReader(`[{"name": "John"}, {`), nil); err == nil {
+		t.Error("Expected error for malformed array")
+	}
+
+	summary, err = validator.ValidateStream(context.Background(), strings.NewReader("  \n"), nil)
+	if err != nil || summary.Records != 0 {
+		t.Errorf("Expected empty summary for empty stream, got %+v %v", summary, err)
+	}
+}
+
+func TestValidateStreamStops(t *testing.T) {
+	validator, err := New(validSchema)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	input := strings.Repeat(`{"name": "John", "age": 30}`+"\n", 10)
+
+	stop := errors.New("stop")
+	calls := 0
+	_, err = validator.ValidateStream(context.Background(), strings.NewReader(input), func(record StreamRecord) error {
+		calls++
+		if record.Index == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || calls != 3 {
+		t.Errorf("Expected the handler error after 3 records, got %v after %d", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := validator.ValidateStream(ctx, strings.NewReader(input), nil); err == nil {
+		t.Error("Expected error for cancelled context")
+	}
+}
+
+func TestReadRecordLine(t *testing.T) {
+	reader := bufio.NewReaderSize(strings.NewReader(strings.Repeat("x", 100)+"\nshort\n"), 16)
+
+	data, tooLong, err := readRecordLine(reader, 32)
+	if err != nil || !tooLong || data != nil {
+		t.Errorf("Expected long line to be skipped, got %q %v %v", data, tooLong, err)
+	}
+
+	data, tooLong, err = readRecordLine(reader, 32)
+	if err != nil || tooLong || string(data) != "short" {
+		t.Errorf("Expected the next line, got %q %v %v", data, tooLong, err)
+	}
+}
+
+func TestFormatResult(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"status": {"enum": ["active", "inactive", "pending"]},
+			"age": {"type": "integer", "minimum": 0},
+			"name": {"type": "string", "minLength": 3},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"a/b": {"type": "boolean"}
+		},
+		"required": ["id"]
+	}`
+	validator, err := New(schema)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	result, err := validator.ValidateString(context.Background(),
+		`{"status": "actve", "age": -1, "name": "Al", "tags": ["ok", 7], "a/b": "yes"}`)
+	if err != nil {
+		t.Fatalf("Failed to validate: %v", err)
+	}
+
+	byPointer := make(map[string]ValidationError)
+	for _, validationErr := range result.Errors {
+		byPointer[validationErr.Pointer] = validationErr
+	}
+
+	tests := []struct {
+		pointer    string
+		expected   string
+		actual     string
+		suggestion string
+	}{
+		{"/status", `one of "active", "inactive", "pending"`, `"actve"`, "active"},
+		{"/age", ">= 0", "-1", ""},
+		{"/name", "at least 3 characters", `"Al" (2 characters)`, ""},
+		{"/tags/1", "string", `integer (7)`, ""},
+		{"/a~1b", "boolean", `string ("yes")`, ""},
+		{"/id", "property to be present", "missing", ""},
+	}
+	for _, tt := range tests {
+		validationErr, ok := byPointer[tt.pointer]
+		if !ok {
+			t.Errorf("Expected an error at %s, got %v", tt.pointer, result.Errors)
+			continue
+		}
+		if got := validationErr.Expected(); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.pointer, tt.expected, got)
+		}
+		if got := validationErr.Actual(); got != tt.actual {
+			t.Errorf("%s: expected actual %q, got %q", tt.pointer, tt.actual, got)
+		}
+		if got := validationErr.Suggestion(); got != tt.suggestion {
+			t.Errorf("%s: expected suggestion %q, got %q", tt.pointer, tt.suggestion, got)
+		}
+	}
+
+	output := FormatResult(result)
+	for _, want := range []string{
+		"Document is invalid: 6 validation errors",
+		"  /status\n",
+		"    expected: >= 0\n",
+		`    hint:     did you mean "active"?`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	if output := FormatResult(&ValidationResult{Valid: true}); output != "Document is valid\n" {
+		t.Errorf("Unexpected output for valid result: %q", output)
+	}
+}
+
+func TestSuggestionTooFar(t *testing.T) {
+	validationErr := ValidationError{
+		Type:    "enum",
+		Value:   "completely-different",
+		Details: map[string]string{"allowed": `"active", "inactive"`},
+	}
+	if suggestion := validationErr.Suggestion(); suggestion != "" {
+		t.Errorf("Expected no suggestion, got %q", suggestion)
+	}
+}
+
+func TestValidateDirectoryReport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.json":                     `{"name": "A", "age": 1}`,
+		"nested/b.json":              `{"name": "B", "age": -1}`,
+		"nested/deep/c.json":         `{"name": "C", "age": 3}`,
+		"nested/broken.json":         `{"name": `,
+		"vendor/d.json":              `{"name": "D", "age": 4}`,
+		"notes.txt":                  `not json`,
+		"nested/deep/skip.test.json": `{}`,
+	}
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	report, err := ValidateDirectoryReport(context.Background(), dir, validSchema, DirectoryOptions{
+		Exclude: []string{"vendor", "*.test.json"},
+		Workers: 3,
+	})
+	if err != nil {
+		t.Fatalf("Failed to validate directory: %v", err)
+	}
+
+	if len(report.Files) != 4 || report.Passed != 2 || report.Failed != 2 || report.Valid() {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if report.Errors < 2 {
+		t.Errorf("Expected at least 2 errors, got %d", report.Errors)
+	}
+
+	rows := report.Rows()
+	expected := [][]string{
+		{filepath.Join(dir, "a.json"), "pass"},
+		{filepath.Join(dir, "nested", "b.json"), "fail"},
+		{filepath.Join(dir, "nested", "broken.json"), "fail"},
+		{filepath.Join(dir, "nested", "deep", "c.json"), "pass"},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %v", len(expected), rows)
+	}
+	for i, want := range expected {
+		if rows[i][0] != want[0] || rows[i][1] != want[1] || len(rows[i]) != len(report.Headers()) {
+			t.Errorf("Row %d: expected %v, got %v", i, want, rows[i])
+		}
+	}
+	if report.Files[2].Error == "" {
+		t.Error("Expected the malformed file to carry an error")
+	}
+
+	report, err = ValidateDirectoryReport(context.Background(), dir, validSchema, DirectoryOptions{
+		Include: []string{"nested/**/*.json"},
+		Exclude: []string{"nested/deep/**"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to validate directory: %v", err)
+	}
+	if len(report.Files) != 2 {
+		t.Errorf("Expected 2 files, got %+v", report.Files)
+	}
+
+	if _, err := ValidateDirectoryReport(context.Background(), dir, validSchema, DirectoryOptions{Include: []string{"["}}); err == nil {
+		t.Error("Expected error for invalid glob")
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.json", "a.json", true},
+		{"*.json", "x/y/a.json", true},
+		{"**/*.json", "a.json", true},
+		{"**/*.json", "x/y/a.json", true},
+		{"x/*.json", "x/y/a.json", false},
+		{"x/**", "x/y/a.json", true},
+		{"x/**/a.json", "x/a.json", true},
+		{"y/**", "x/y/a.json", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package jsonschema
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxRecordSize is the largest NDJSON record ValidateStream reads.
+// Longer lines are reported as invalid records without being held in memory
+const DefaultMaxRecordSize = 16 << 20
+
+// StreamRecord is the result of validating one record of a stream
+type StreamRecord struct {
+	// Index is the 1-based position of the record in the stream
+	Index int `json:"index"`
+	// Line is the line the record starts on for NDJSON streams, and 0 for
+	// JSON arrays
+	Line   int               `json:"line,omitempty"`
+	Result *ValidationResult `json:"result"`
+}
+
+// StreamHandler is called with each record as soon as it is validated.
+// Returning an error stops the stream
+type StreamHandler func(record StreamRecord) error
+
+// StreamSummary counts the records of a validated stream
+type StreamSummary struct {
+	Records int `json:"records"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+}
+
+// ValidateStream validates the records of r one at a time, calling handler
+// with each result, so memory use is bounded by the largest record rather
+// than the stream. A stream whose first non-space character is "[" is read
+// as a JSON array of records; anything else is read as newline-delimited
+// JSON, where blank lines are skipped and malformed lines are reported as
+// invalid records. handler may be nil when only the summary is needed
+func (v *Validator) ValidateStream(ctx context.Context, r io.Reader, handler StreamHandler) (*StreamSummary, error) {
+	reader := bufio.NewReader(r)
+
+	// Skip leading whitespace to detect the format, counting the lines
+	line := 1
+	for {
+		b, err := reader.ReadByte()
+		if err == io.EOF {
+			return &StreamSummary{}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stream: %w", err)
+		}
+		if b == '\n' {
+			line++
+		}
+		if !isJSONSpace(b) {
+			_ = reader.UnreadByte()
+			break
+		}
+	}
+
+	stream := &recordStream{validator: v, handler: handler, summary: &StreamSummary{}}
+
+	first, _ := reader.Peek(1)
+	var err error
+	if first[0] == '[' {
+		err = stream.array(ctx, reader)
+	} else {
+		err = stream.lines(ctx, reader, line)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stream.summary, nil
+}
+
+// ValidateStream validates the records of a JSON array or NDJSON stream
+// against a schema string
+func ValidateStream(ctx context.Context, r io.Reader, schema string, handler StreamHandler) (*StreamSummary, error) {
+	validator, err := New(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return validator.ValidateStream(ctx, r, handler)
+}
+
+// recordStream validates records and reports them to a handler
+type recordStream struct {
+	validator *Validator
+	handler   StreamHandler
+	summary   *StreamSummary
+}
+
+// lines reads newline-delimited records starting on line
+func (s *recordStream) lines(ctx context.Context, reader *bufio.Reader, line int) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		data, tooLong, readErr := readRecordLine(reader, DefaultMaxRecordSize)
+		if readErr != nil && readErr != io.EOF {
+			return fmt.Errorf("failed to read stream: %w", readErr)
+		}
+
+		record := bytes.TrimSpace(data)
+		if len(record) > 0 || tooLong {
+			var result *ValidationResult
+			switch {
+			case tooLong:
+				result = invalidRecord(fmt.Sprintf("record exceeds %d bytes", DefaultMaxRecordSize))
+			case !json.Valid(record):
+				var discard interface{}
+				result = invalidRecord(fmt.Sprintf("invalid JSON: %v", json.Unmarshal(record, &discard)))
+			default:
+				var err error
+				result, err = s.validator.Validate(ctx, record)
+				if err != nil {
+					return fmt.Errorf("failed to validate record on line %d: %w", line, err)
+				}
+			}
+
+			if err := s.report(StreamRecord{Line: line, Result: result}); err != nil {
+				return err
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+		line++
+	}
+}
+
+// array reads the elements of a JSON array as records
+func (s *recordStream) array(ctx context.Context, reader *bufio.Reader) error {
+	decoder := json.NewDecoder(reader)
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	for decoder.More() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var record json.RawMessage
+		if err := decoder.Decode(&record); err != nil {
+			// A malformed array cannot be resynchronised
+			return fmt.Errorf("failed to decode record %d: %w", s.summary.Records+1, err)
+		}
+
+		result, err := s.validator.Validate(ctx, record)
+		if err != nil {
+			return fmt.Errorf("failed to validate record %d: %w", s.summary.Records+1, err)
+		}
+		if err := s.report(StreamRecord{Result: result}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
+
+// report counts a record and passes it to the handler
+func (s *recordStream) report(record StreamRecord) error {
+	s.summary.Records++
+	record.Index = s.summary.Records
+	if record.Result.Valid {
+		s.summary.Valid++
+	} else {
+		s.summary.Invalid++
+	}
+
+	if s.handler == nil {
+		return nil
+	}
+	return s.handler(record)
+}
+
+// readRecordLine reads one line without its newline. Lines longer than max
+// are consumed but not returned, and tooLong is set
+func readRecordLine(reader *bufio.Reader, max int) (data []byte, tooLong bool, err error) {
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if !tooLong {
+			if len(data)+len(chunk) > max+1 {
+				data, tooLong = nil, true
+			} else {
+				data = append(data, chunk...)
+			}
+		}
+
+		switch {
+		case err == nil:
+			return bytes.TrimSuffix(data, []byte("\n")), tooLong, nil
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		default:
+			return data, tooLong, err
+		}
+	}
+}
+
+// invalidRecord returns the result for a record that could not be validated
+func invalidRecord(description string) *ValidationResult {
+	return &ValidationResult{
+		Valid: false,
+		Errors: []ValidationError{{
+			Field:       "(root)",
+			Description: description,
+		}},
+	}
+}
+
+func isJSONSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
@@ -0,0 +1,324 @@
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// awsBackend stores secrets in AWS Secrets Manager, one secret per service
+// and user named <prefix><service>/<user>
+type awsBackend struct {
+	client   *http.Client
+	endpoint string
+	region   string
+	prefix   string
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+
+	now func() time.Time
+}
+
+// awsError is the error body returned by the Secrets Manager API
+type awsError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// newAWSBackend creates the AWS Secrets Manager backend. Options: "region"
+// (or AWS_REGION, AWS_DEFAULT_REGION), "access_key_id",
+// "secret_access_key" and "session_token" (or the matching AWS_*
+// variables), "endpoint" to override the regional endpoint and "prefix"
+// (defaults to "tykctl/"). Credentials are only read from options and the
+// environment; shared config files and instance roles are not supported
+func newAWSBackend(options map[string]string) (Backend, error) {
+	region := option(options, "region", "AWS_REGION", "AWS_DEFAULT_REGION")
+	if region == "" {
+		return nil, fmt.Errorf("an AWS region is required")
+	}
+
+	accessKeyID := option(options, "access_key_id", "AWS_ACCESS_KEY_ID")
+	secretAccessKey := option(options, "secret_access_key", "AWS_SECRET_ACCESS_KEY")
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("AWS credentials are required")
+	}
+
+	endpoint := option(options, "endpoint")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+	prefix := option(options, "prefix")
+	if prefix == "" {
+		prefix = "tykctl/"
+	}
+
+	return &awsBackend{
+		client:          &http.Client{Timeout: 30 * time.Second},
+		endpoint:        endpoint,
+		region:          region,
+		prefix:          prefix,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    option(options, "session_token", "AWS_SESSION_TOKEN"),
+		now:             time.Now,
+	}, nil
+}
+
+func (b *awsBackend) Name() string {
+	return BackendAWS
+}
+
+func (b *awsBackend) Set(ctx context.Context, service, user, password string) error {
+	name := b.secretName(service, user)
+
+	err := b.call(ctx, "PutSecretValue", map[string]string{
+		"SecretId":     name,
+		"SecretString": password,
+	}, nil)
+	if err != ErrNotFound {
+		return err
+	}
+
+	return b.call(ctx, "CreateSecret", map[string]string{
+		"Name":         name,
+		"SecretString": password,
+	}, nil)
+}
+
+func (b *awsBackend) Get(ctx context.Context, service, user string) (string, error) {
+	var output struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := b.call(ctx, "GetSecretValue", map[string]string{
+		"SecretId": b.secretName(service, user),
+	}, &output); err != nil {
+		return "", err
+	}
+	return output.SecretString, nil
+}
+
+// Delete removes the secret immediately, without a recovery window, so it
+// can be stored again under the same name
+func (b *awsBackend) Delete(ctx context.Context, service, user string) error {
+	return b.deleteSecret(ctx, b.secretName(service, user))
+}
+
+// List returns the sorted users with a secret for service
+func (b *awsBackend) List(ctx context.Context, service string) ([]string, error) {
+	prefix := b.secretName(service, "")
+	users := []string{}
+
+	var token string
+	for {
+		input := map[string]interface{}{
+			"Filters": []map[string]interface{}{
+				{"Key": "name", "Values": []string{prefix}},
+			},
+		}
+		if token != "" {
+			input["NextToken"] = token
+		}
+
+		var output struct {
+			SecretList []struct {
+				Name string `json:"Name"`
+			} `json:"SecretList"`
+			NextToken string `json:"NextToken"`
+		}
+		if err := b.call(ctx, "ListSecrets", input, &output); err != nil {
+			return nil, err
+		}
+
+		for _, secret := range output.SecretList {
+			// The name filter matches prefixes of words, so check it
+			// exactly and skip secrets of nested services
+			user, ok := strings.CutPrefix(secret.Name, prefix)
+			if !ok || user == "" || strings.Contains(user, "/") {
+				continue
+			}
+			users = append(users, user)
+		}
+
+		if output.NextToken == "" {
+			break
+		}
+		token = output.NextToken
+	}
+
+	sort.Strings(users)
+	return users, nil
+}
+
+// Purge removes every secret of service
+func (b *awsBackend) Purge(ctx context.Context, service string) error {
+	users, err := b.List(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := b.Delete(ctx, service, user); err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// secretName returns the name of the secret of a service and user
+func (b *awsBackend) secretName(service, user string) string {
+	return b.prefix + service + "/" + user
+}
+
+func (b *awsBackend) deleteSecret(ctx context.Context, name string) error {
+	return b.call(ctx, "DeleteSecret", map[string]interface{}{
+		"SecretId":                   name,
+		"ForceDeleteWithoutRecovery": true,
+	}, nil)
+}
+
+// call invokes a Secrets Manager action, decoding the response into output
+// when it is not nil. ResourceNotFoundException is returned as ErrNotFound
+func (b *awsBackend) call(ctx context.Context, action string, input, output interface{}) error {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", action, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", action, err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager."+action)
+	b.sign(req, payload)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach AWS Secrets Manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", action, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr awsError
+		_ = json.Unmarshal(data, &apiErr)
+		if strings.HasSuffix(apiErr.Type, "ResourceNotFoundException") {
+			return ErrNotFound
+		}
+		return fmt.Errorf("%s failed with status %d: %s %s", action, resp.StatusCode, apiErr.Type, apiErr.Message)
+	}
+
+	if output == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, output); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", action, err)
+	}
+	return nil
+}
+
+// sign adds AWS Signature Version 4 headers to req
+func (b *awsBackend) sign(req *http.Request, payload []byte) {
+	const service = "secretsmanager"
+
+	now := b.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if b.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", b.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(payload),
+	}, "\n")
+
+	scope := date + "/" + b.region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+b.secretAccessKey), date)
+	key = hmacSHA256(key, b.region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		b.accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query string sorted and encoded as SigV4 expects
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+	return strings.ReplaceAll(strings.Join(pairs, "&"), "+", "%20")
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
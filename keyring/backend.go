@@ -0,0 +1,423 @@
+package keyring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Backend stores secrets identified by a service and a user
+type Backend interface {
+	// Name returns the name the backend is registered under
+	Name() string
+	// Set stores a secret, replacing any existing one
+	Set(ctx context.Context, service, user, password string) error
+	// Get retrieves a secret, returning ErrNotFound if there is none
+	Get(ctx context.Context, service, user string) (string, error)
+	// Delete removes a secret, returning ErrNotFound if there is none
+	Delete(ctx context.Context, service, user string) error
+}
+
+// Purger is implemented by backends that can list and remove every secret
+// of a service
+type Purger interface {
+	Purge(ctx context.Context, service string) error
+}
+
+// Lister is implemented by backends that can list the users with a secret
+// for a service
+type Lister interface {
+	List(ctx context.Context, service string) ([]string, error)
+}
+
+// ErrListUnsupported is returned by List when the current backend cannot
+// list secrets
+var ErrListUnsupported = errors.New("keyring backend does not support listing secrets")
+
+// BackendFactory creates a backend from backend-specific options
+type BackendFactory func(options map[string]string) (Backend, error)
+
+// Names of the built-in backends
+const (
+	BackendSystem = "system"
+	BackendFile   = "file"
+	BackendVault  = "vault"
+	BackendAWS    = "aws"
+)
+
+// EnvBackend is the environment variable selecting the backend. Backend
+// options are read from variables named EnvOptionPrefix followed by the
+// upper-cased option name, such as TYKCTL_KEYRING_ADDRESS
+const (
+	EnvBackend      = "TYKCTL_KEYRING_BACKEND"
+	EnvOptionPrefix = "TYKCTL_KEYRING_"
+)
+
+// Config selects a backend and its options
+type Config struct {
+	Backend string            `yaml:"backend" json:"backend"`
+	Options map[string]string `yaml:"options,omitempty" json:"options,omitempty"`
+}
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]BackendFactory{}
+
+	currentMu sync.Mutex
+	current   Backend
+)
+
+func init() {
+	RegisterBackend(BackendSystem, newSystemBackend)
+	RegisterBackend(BackendFile, newFileBackend)
+	RegisterBackend(BackendVault, newVaultBackend)
+	RegisterBackend(BackendAWS, newAWSBackend)
+	RegisterBackend(BackendMock, func(options map[string]string) (Backend, error) {
+		return NewMock(), nil
+	})
+}
+
+// RegisterBackend makes a backend available under name, replacing any
+// backend registered under the same name
+func RegisterBackend(name string, factory BackendFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[strings.ToLower(name)] = factory
+}
+
+// Backends returns the sorted names of the registered backends
+func Backends() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewBackend creates the backend registered under name. An empty name
+// selects the system keyring
+func NewBackend(name string, options map[string]string) (Backend, error) {
+	if name == "" {
+		name = BackendSystem
+	}
+
+	factoriesMu.RLock()
+	factory, ok := factories[strings.ToLower(name)]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown keyring backend %q (available: %s)", name, strings.Join(Backends(), ", "))
+	}
+
+	if options == nil {
+		options = map[string]string{}
+	}
+	backend, err := factory(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s keyring backend: %w", name, err)
+	}
+	return backend, nil
+}
+
+// Open creates the backend selected by config
+func Open(config Config) (Backend, error) {
+	return NewBackend(config.Backend, config.Options)
+}
+
+// ConfigFromEnv reads the backend and its options from the environment
+func ConfigFromEnv() Config {
+	config := Config{
+		Backend: os.Getenv(EnvBackend),
+		Options: map[string]string{},
+	}
+
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, EnvOptionPrefix) || key == EnvBackend {
+			continue
+		}
+		config.Options[strings.ToLower(strings.TrimPrefix(key, EnvOptionPrefix))] = value
+	}
+	return config
+}
+
+// UseBackend makes backend the one used by Set, Get, Delete and Purge
+func UseBackend(backend Backend) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = backend
+}
+
+// CurrentBackend returns the backend used by the package-level functions.
+// Unless UseBackend was called, it is selected from the environment on
+// first use, defaulting to the system keyring
+func CurrentBackend() (Backend, error) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+
+	if current != nil {
+		return current, nil
+	}
+
+	backend, err := Open(ConfigFromEnv())
+	if err != nil {
+		return nil, err
+	}
+	current = backend
+	return current, nil
+}
+
+// option returns the first non-empty value among an option and a list of
+// fallback environment variables
+func option(options map[string]string, name string, env ...string) string {
+	if value := options[name]; value != "" {
+		return value
+	}
+	for _, key := range env {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// systemIndexUser is the reserved user under which the system backend keeps
+// the users of each service, since the system keyring cannot list them
+const systemIndexUser = "tykctl-keyring-index"
+
+// systemBackend stores secrets in the operating system keyring
+type systemBackend struct {
+	mu sync.Mutex
+}
+
+func newSystemBackend(options map[string]string) (Backend, error) {
+	return &systemBackend{}, nil
+}
+
+func (b *systemBackend) Name() string {
+	return BackendSystem
+}
+
+func (b *systemBackend) Set(ctx context.Context, service, user, password string) error {
+	// zalando/go-keyring doesn't support context, so only check for
+	// cancellation before calling it
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if user == systemIndexUser {
+		return fmt.Errorf("user %q is reserved", user)
+	}
+	if err := keyring.Set(service, user, password); err != nil {
+		return err
+	}
+	return b.updateIndex(service, func(users []string) []string {
+		for _, existing := range users {
+			if existing == user {
+				return users
+			}
+		}
+		return append(users, user)
+	})
+}
+
+func (b *systemBackend) Get(ctx context.Context, service, user string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return keyring.Get(service, user)
+}
+
+func (b *systemBackend) Delete(ctx context.Context, service, user string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := keyring.Delete(service, user); err != nil {
+		return err
+	}
+	return b.updateIndex(service, func(users []string) []string {
+		result := users[:0]
+		for _, existing := range users {
+			if existing != user {
+				result = append(result, existing)
+			}
+		}
+		return result
+	})
+}
+
+// SetMany stores secrets, restoring earlier values if one fails, and
+// updates the index of each service once rather than once per secret
+func (b *systemBackend) SetMany(ctx context.Context, secrets []Secret) error {
+	for _, secret := range secrets {
+		if secret.User == systemIndexUser {
+			return fmt.Errorf("user %q is reserved", secret.User)
+		}
+	}
+	if err := setEach(ctx, systemOps, secrets); err != nil {
+		return err
+	}
+	return b.indexBatch(secrets, true)
+}
+
+// GetMany retrieves secrets one at a time, as the system keyring has no
+// batch reads
+func (b *systemBackend) GetMany(ctx context.Context, keys []Secret) ([]Secret, error) {
+	return getEach(ctx, systemOps, keys)
+}
+
+// DeleteMany removes secrets, restoring them if one fails, and updates the
+// index of each service once rather than once per secret
+func (b *systemBackend) DeleteMany(ctx context.Context, keys []Secret) error {
+	if err := deleteEach(ctx, systemOps, keys); err != nil {
+		return err
+	}
+	return b.indexBatch(keys, false)
+}
+
+// systemOps access the system keyring directly, bypassing the index
+var systemOps = secretOps{
+	get: func(ctx context.Context, service, user string) (string, error) {
+		return keyring.Get(service, user)
+	},
+	set: func(ctx context.Context, service, user, password string) error {
+		return keyring.Set(service, user, password)
+	},
+	delete: func(ctx context.Context, service, user string) error {
+		return keyring.Delete(service, user)
+	},
+}
+
+// indexBatch adds or removes the users of secrets from the index of each
+// of their services
+func (b *systemBackend) indexBatch(secrets []Secret, add bool) error {
+	byService := map[string]map[string]bool{}
+	var services []string
+	for _, secret := range secrets {
+		if byService[secret.Service] == nil {
+			byService[secret.Service] = map[string]bool{}
+			services = append(services, secret.Service)
+		}
+		byService[secret.Service][secret.User] = true
+	}
+
+	for _, service := range services {
+		users := byService[service]
+		err := b.updateIndex(service, func(indexed []string) []string {
+			result := indexed[:0]
+			for _, user := range indexed {
+				if !users[user] {
+					result = append(result, user)
+				}
+			}
+			if add {
+				for user := range users {
+					result = append(result, user)
+				}
+			}
+			return result
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the users stored through this backend. Secrets stored by
+// other programs, or by earlier versions, are not listed
+func (b *systemBackend) List(ctx context.Context, service string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	users, err := b.readIndex(service)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+// Purge removes the secrets of every indexed user of service. Secrets
+// stored before the index existed are only removed for common users
+func (b *systemBackend) Purge(ctx context.Context, service string) error {
+	users, err := b.List(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	secrets := make([]Secret, 0, len(users))
+	for _, user := range users {
+		if err := keyring.Delete(service, user); err != nil && err != ErrNotFound {
+			return fmt.Errorf("failed to delete secret for user %q: %w", user, err)
+		}
+		secrets = append(secrets, Secret{Service: service, User: user})
+	}
+	if err := b.indexBatch(secrets, false); err != nil {
+		return err
+	}
+
+	for _, user := range commonUsers {
+		_ = keyring.Delete(service, user)
+	}
+	return nil
+}
+
+// readIndex returns the indexed users of service without locking
+func (b *systemBackend) readIndex(service string) ([]string, error) {
+	data, err := keyring.Get(service, systemIndexUser)
+	if err == ErrNotFound {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var users []string
+	if err := json.Unmarshal([]byte(data), &users); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring index: %w", err)
+	}
+	return users, nil
+}
+
+// updateIndex applies fn to the indexed users of service, removing the
+// index when no users remain
+func (b *systemBackend) updateIndex(service string, fn func([]string) []string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	users, err := b.readIndex(service)
+	if err != nil {
+		return err
+	}
+	users = fn(users)
+
+	if len(users) == 0 {
+		if err := keyring.Delete(service, systemIndexUser); err != nil && err != ErrNotFound {
+			return fmt.Errorf("failed to update keyring index: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(users)
+	if err != nil {
+		return fmt.Errorf("failed to encode keyring index: %w", err)
+	}
+	if err := keyring.Set(service, systemIndexUser, string(data)); err != nil {
+		return fmt.Errorf("failed to update keyring index: %w", err)
+	}
+	return nil
+}
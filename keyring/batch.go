@@ -0,0 +1,205 @@
+package keyring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Secret identifies a secret by service and user. Password is the value to
+// store for SetMany and the value read by GetMany
+type Secret struct {
+	Service  string
+	User     string
+	Password string
+}
+
+// Batcher is implemented by backends that apply batches of secrets in one
+// step, all or nothing
+type Batcher interface {
+	SetMany(ctx context.Context, secrets []Secret) error
+	GetMany(ctx context.Context, keys []Secret) ([]Secret, error)
+	DeleteMany(ctx context.Context, keys []Secret) error
+}
+
+// SetMany stores several secrets using the current backend. Either every
+// secret is stored or, on failure, the secrets already written are
+// restored to their previous values
+func SetMany(ctx context.Context, secrets []Secret) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		backend, err := CurrentBackend()
+		if err != nil {
+			return err
+		}
+		return setMany(ctx, backend, secrets)
+	}
+}
+
+// GetMany retrieves several secrets using the current backend, in the order
+// of keys. It fails with ErrNotFound if any of them is missing
+func GetMany(ctx context.Context, keys []Secret) ([]Secret, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		backend, err := CurrentBackend()
+		if err != nil {
+			return nil, err
+		}
+		return getMany(ctx, backend, keys)
+	}
+}
+
+// DeleteMany removes several secrets using the current backend. It fails
+// with ErrNotFound, deleting nothing, if any of them is missing, and
+// restores the secrets already deleted if a deletion fails
+func DeleteMany(ctx context.Context, keys []Secret) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		backend, err := CurrentBackend()
+		if err != nil {
+			return err
+		}
+		return deleteMany(ctx, backend, keys)
+	}
+}
+
+func setMany(ctx context.Context, backend Backend, secrets []Secret) error {
+	if batcher, ok := backend.(Batcher); ok {
+		return batcher.SetMany(ctx, secrets)
+	}
+	return setEach(ctx, backendOps(backend), secrets)
+}
+
+func getMany(ctx context.Context, backend Backend, keys []Secret) ([]Secret, error) {
+	if batcher, ok := backend.(Batcher); ok {
+		return batcher.GetMany(ctx, keys)
+	}
+	return getEach(ctx, backendOps(backend), keys)
+}
+
+func deleteMany(ctx context.Context, backend Backend, keys []Secret) error {
+	if batcher, ok := backend.(Batcher); ok {
+		return batcher.DeleteMany(ctx, keys)
+	}
+	return deleteEach(ctx, backendOps(backend), keys)
+}
+
+// secretOps are the single-secret operations a batch is built from
+type secretOps struct {
+	get    func(ctx context.Context, service, user string) (string, error)
+	set    func(ctx context.Context, service, user, password string) error
+	delete func(ctx context.Context, service, user string) error
+}
+
+func backendOps(backend Backend) secretOps {
+	return secretOps{get: backend.Get, set: backend.Set, delete: backend.Delete}
+}
+
+// setEach stores secrets one at a time, restoring the previous value of
+// every secret already written when one fails
+func setEach(ctx context.Context, ops secretOps, secrets []Secret) error {
+	var done []previousSecret
+
+	for _, secret := range secrets {
+		if err := ctx.Err(); err != nil {
+			return rollback(ctx, ops, done, err)
+		}
+
+		prev, err := readPrevious(ctx, ops, secret)
+		if err != nil {
+			return rollback(ctx, ops, done, err)
+		}
+		if err := ops.set(ctx, secret.Service, secret.User, secret.Password); err != nil {
+			return rollback(ctx, ops, done, fmt.Errorf("failed to set %s/%s: %w", secret.Service, secret.User, err))
+		}
+		done = append(done, prev)
+	}
+	return nil
+}
+
+// getEach retrieves secrets one at a time
+func getEach(ctx context.Context, ops secretOps, keys []Secret) ([]Secret, error) {
+	result := make([]Secret, 0, len(keys))
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		password, err := ops.get(ctx, key.Service, key.User)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s/%s: %w", key.Service, key.User, err)
+		}
+		result = append(result, Secret{Service: key.Service, User: key.User, Password: password})
+	}
+	return result, nil
+}
+
+// deleteEach reads every secret first, so nothing is deleted when one is
+// missing, then deletes them one at a time, restoring the deleted secrets
+// when one fails
+func deleteEach(ctx context.Context, ops secretOps, keys []Secret) error {
+	existing, err := getEach(ctx, ops, keys)
+	if err != nil {
+		return err
+	}
+
+	var done []previousSecret
+	for _, secret := range existing {
+		if err := ops.delete(ctx, secret.Service, secret.User); err != nil {
+			return rollback(ctx, ops, done, fmt.Errorf("failed to delete %s/%s: %w", secret.Service, secret.User, err))
+		}
+		done = append(done, previousSecret{Secret: secret, existed: true})
+	}
+	return nil
+}
+
+// previousSecret is the state of a secret before a batch changed it
+type previousSecret struct {
+	Secret
+	existed bool
+}
+
+func readPrevious(ctx context.Context, ops secretOps, secret Secret) (previousSecret, error) {
+	prev := previousSecret{Secret: Secret{Service: secret.Service, User: secret.User}}
+
+	password, err := ops.get(ctx, secret.Service, secret.User)
+	switch {
+	case err == nil:
+		prev.Password, prev.existed = password, true
+	case err != ErrNotFound:
+		return prev, fmt.Errorf("failed to get %s/%s: %w", secret.Service, secret.User, err)
+	}
+	return prev, nil
+}
+
+// rollback restores secrets to their previous state in reverse order and
+// returns cause, joined with any error hit while restoring. Restoring
+// ignores cancellation of ctx so a cancelled batch is still undone
+func rollback(ctx context.Context, ops secretOps, done []previousSecret, cause error) error {
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for i := len(done) - 1; i >= 0; i-- {
+		prev := done[i]
+		var err error
+		if prev.existed {
+			err = ops.set(ctx, prev.Service, prev.User, prev.Password)
+		} else {
+			err = ops.delete(ctx, prev.Service, prev.User)
+		}
+		if err != nil && err != ErrNotFound {
+			errs = append(errs, fmt.Errorf("failed to roll back %s/%s: %w", prev.Service, prev.User, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return cause
+	}
+	return errors.Join(append([]error{cause}, errs...)...)
+}
@@ -1,7 +1,7 @@
-// Package keyring provides a simple wrapper around the zalando/go-keyring library
-// for storing and retrieving secrets from the system keyring on different platforms.
+// Package keyring provides storage and retrieval of secrets through
+// pluggable backends, defaulting to the system keyring on different platforms.
 //
-// The keyring package supports:
+// The system keyring backend supports:
 //   - macOS: Uses the macOS Keychain via the security command-line tool
 //   - Linux/Unix: Uses the Secret Service API via D-Bus
 //   - Windows: Uses the Windows Credential Manager
@@ -55,5 +55,35 @@
 // keyring implementation. If the platform is not supported, operations will
 // return ErrUnsupportedPlatform.
 //
-// This package is a simple wrapper around github.com/zalando/go-keyring.
+// Backends:
+//
+// Set, Get, Delete and Purge use the backend selected by TYKCTL_KEYRING_BACKEND,
+// or the one passed to UseBackend. The built-in backends are "system" (the
+// default), "file" (a passphrase-encrypted file), "vault" (HashiCorp Vault KV
+// version 2) and "aws" (AWS Secrets Manager). Backend options are read from
+// TYKCTL_KEYRING_<OPTION> variables, or passed to Open in a Config:
+//
+//	backend, err := keyring.Open(keyring.Config{
+//		Backend: keyring.BackendVault,
+//		Options: map[string]string{"address": "https://vault.example.com"},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	keyring.UseBackend(backend)
+//
+// Further backends can be added with RegisterBackend. Tests can use NewMock,
+// an in-memory backend with failure injection, through UseBackend.
+//
+// List returns the users with a secret for a service, and a Namespace scopes
+// service names per extension or context:
+//
+//	ns := keyring.ExtensionNamespace("sync")
+//	err := ns.Set(ctx, "api", "default", token) // service "extension/sync/api"
+//	users, err := ns.List(ctx, "api")
+//
+// SetMany, GetMany and DeleteMany work on several secrets at once, all or
+// nothing, in a single step on backends that implement Batcher.
+//
+// The system backend wraps github.com/zalando/go-keyring.
 package keyring
\ No newline at end of file
@@ -0,0 +1,329 @@
+package keyring
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/adrg/xdg"
+)
+
+// fileKeyIterations is the number of PBKDF2 iterations used to derive the
+// encryption key from the passphrase
+const fileKeyIterations = 600000
+
+// ErrWrongPassphrase is returned when the encrypted file cannot be opened
+// with the configured passphrase
+var ErrWrongPassphrase = errors.New("failed to decrypt keyring file: wrong passphrase or corrupted file")
+
+// DefaultFilePath returns the default location of the encrypted keyring file
+func DefaultFilePath() string {
+	return filepath.Join(xdg.DataHome, "tykctl", "keyring.enc")
+}
+
+// fileEnvelope is the on-disk format of the encrypted keyring file. Data is
+// the AES-256-GCM encrypted JSON of every secret
+type fileEnvelope struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// fileBackend stores secrets in a passphrase-encrypted file, for machines
+// without a system keyring
+type fileBackend struct {
+	path       string
+	passphrase string
+
+	mu   sync.Mutex
+	salt []byte
+	key  []byte
+}
+
+// newFileBackend creates the encrypted file backend. Options: "path"
+// (defaults to DefaultFilePath) and "passphrase" (required)
+func newFileBackend(options map[string]string) (Backend, error) {
+	passphrase := option(options, "passphrase")
+	if passphrase == "" {
+		return nil, fmt.Errorf("a passphrase is required")
+	}
+
+	path := option(options, "path")
+	if path == "" {
+		path = DefaultFilePath()
+	}
+	return &fileBackend{path: path, passphrase: passphrase}, nil
+}
+
+func (b *fileBackend) Name() string {
+	return BackendFile
+}
+
+func (b *fileBackend) Set(ctx context.Context, service, user, password string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.update(func(secrets map[string]map[string]string) error {
+		if secrets[service] == nil {
+			secrets[service] = map[string]string{}
+		}
+		secrets[service][user] = password
+		return nil
+	})
+}
+
+func (b *fileBackend) Get(ctx context.Context, service, user string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	secrets, err := b.load()
+	if err != nil {
+		return "", err
+	}
+	password, ok := secrets[service][user]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return password, nil
+}
+
+func (b *fileBackend) Delete(ctx context.Context, service, user string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.update(func(secrets map[string]map[string]string) error {
+		if _, ok := secrets[service][user]; !ok {
+			return ErrNotFound
+		}
+		delete(secrets[service], user)
+		if len(secrets[service]) == 0 {
+			delete(secrets, service)
+		}
+		return nil
+	})
+}
+
+// Purge removes every secret of service
+func (b *fileBackend) Purge(ctx context.Context, service string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.update(func(secrets map[string]map[string]string) error {
+		delete(secrets, service)
+		return nil
+	})
+}
+
+// List returns the sorted users with a secret for service
+func (b *fileBackend) List(ctx context.Context, service string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	secrets, err := b.load()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]string, 0, len(secrets[service]))
+	for user := range secrets[service] {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+// SetMany stores secrets in a single write of the file
+func (b *fileBackend) SetMany(ctx context.Context, secrets []Secret) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.update(func(stored map[string]map[string]string) error {
+		for _, secret := range secrets {
+			if stored[secret.Service] == nil {
+				stored[secret.Service] = map[string]string{}
+			}
+			stored[secret.Service][secret.User] = secret.Password
+		}
+		return nil
+	})
+}
+
+// GetMany retrieves secrets with a single read of the file
+func (b *fileBackend) GetMany(ctx context.Context, keys []Secret) ([]Secret, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stored, err := b.load()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Secret, 0, len(keys))
+	for _, key := range keys {
+		password, ok := stored[key.Service][key.User]
+		if !ok {
+			return nil, fmt.Errorf("failed to get %s/%s: %w", key.Service, key.User, ErrNotFound)
+		}
+		result = append(result, Secret{Service: key.Service, User: key.User, Password: password})
+	}
+	return result, nil
+}
+
+// DeleteMany removes secrets in a single write of the file, or none if any
+// of them is missing
+func (b *fileBackend) DeleteMany(ctx context.Context, keys []Secret) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.update(func(stored map[string]map[string]string) error {
+		for _, key := range keys {
+			if _, ok := stored[key.Service][key.User]; !ok {
+				return fmt.Errorf("failed to delete %s/%s: %w", key.Service, key.User, ErrNotFound)
+			}
+		}
+		for _, key := range keys {
+			delete(stored[key.Service], key.User)
+			if len(stored[key.Service]) == 0 {
+				delete(stored, key.Service)
+			}
+		}
+		return nil
+	})
+}
+
+// update applies fn to the secrets and saves them if fn succeeds
+func (b *fileBackend) update(fn func(map[string]map[string]string) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	secrets, err := b.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(secrets); err != nil {
+		return err
+	}
+	return b.save(secrets)
+}
+
+// load decrypts the keyring file without locking, returning no secrets if
+// it does not exist
+func (b *fileBackend) load() (map[string]map[string]string, error) {
+	secrets := map[string]map[string]string{}
+
+	data, err := os.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring file: %w", err)
+	}
+
+	var envelope fileEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring file: %w", err)
+	}
+
+	gcm, err := b.cipher(envelope.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != gcm.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Data, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring file: %w", err)
+	}
+	return secrets, nil
+}
+
+// save encrypts the secrets and writes the keyring file atomically without
+// locking
+func (b *fileBackend) save(secrets map[string]map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to encode keyring file: %w", err)
+	}
+
+	salt := b.salt
+	if salt == nil {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+	gcm, err := b.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	data, err := json.Marshal(&fileEnvelope{
+		Version: 1,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode keyring file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0700); err != nil {
+		return fmt.Errorf("failed to create keyring directory: %w", err)
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write keyring file: %w", err)
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		return fmt.Errorf("failed to write keyring file: %w", err)
+	}
+	return nil
+}
+
+// cipher returns the AES-GCM cipher for salt, deriving the key only when
+// the salt changes since derivation is deliberately slow
+func (b *fileBackend) cipher(salt []byte) (cipher.AEAD, error) {
+	if b.key == nil || string(b.salt) != string(salt) {
+		key, err := pbkdf2.Key(sha256.New, b.passphrase, salt, fileKeyIterations, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive keyring key: %w", err)
+		}
+		b.salt, b.key = salt, key
+	}
+
+	block, err := aes.NewCipher(b.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -5,53 +5,100 @@ import (
 	"github.com/zalando/go-keyring"
 )
 
-// Set stores a secret in the system keyring
+// Set stores a secret using the current backend
 func Set(ctx context.Context, service, user, password string) error {
-	// Note: zalando/go-keyring doesn't support context, so we can't pass it through
-	// but we can still accept it for future compatibility and to check for cancellation
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
-		return keyring.Set(service, user, password)
+		backend, err := CurrentBackend()
+		if err != nil {
+			return err
+		}
+		return backend.Set(ctx, service, user, password)
 	}
 }
 
-// Get retrieves a secret from the system keyring
+// Get retrieves a secret using the current backend
 func Get(ctx context.Context, service, user string) (string, error) {
 	select {
 	case <-ctx.Done():
 		return "", ctx.Err()
 	default:
-		return keyring.Get(service, user)
+		backend, err := CurrentBackend()
+		if err != nil {
+			return "", err
+		}
+		return backend.Get(ctx, service, user)
 	}
 }
 
-// Delete removes a secret from the system keyring
+// Delete removes a secret using the current backend
 func Delete(ctx context.Context, service, user string) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
-		return keyring.Delete(service, user)
+		backend, err := CurrentBackend()
+		if err != nil {
+			return err
+		}
+		return backend.Delete(ctx, service, user)
 	}
 }
 
-// Purge removes all secrets for a given service from the system keyring
-// Note: This implementation attempts to delete common user patterns since the
-// underlying zalando/go-keyring doesn't support bulk deletion
+// List returns the sorted users with a secret for a given service using the
+// current backend, or ErrListUnsupported if the backend cannot list them
+func List(ctx context.Context, service string) ([]string, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		backend, err := CurrentBackend()
+		if err != nil {
+			return nil, err
+		}
+		lister, ok := backend.(Lister)
+		if !ok {
+			return nil, ErrListUnsupported
+		}
+		return lister.List(ctx, service)
+	}
+}
+
+// Purge removes all secrets for a given service using the current backend.
+// Backends that can neither purge nor list secrets only have common user
+// patterns deleted
 func Purge(ctx context.Context, service string) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
-		// Since zalando/go-keyring doesn't have bulk deletion, we'll try to delete
-		// some common user patterns, but this is not guaranteed to delete all
-		commonUsers := []string{"user", "admin", "root", "default", "test", "demo"}
-		
+		backend, err := CurrentBackend()
+		if err != nil {
+			return err
+		}
+		if purger, ok := backend.(Purger); ok {
+			return purger.Purge(ctx, service)
+		}
+		if lister, ok := backend.(Lister); ok {
+			users, err := lister.List(ctx, service)
+			if err != nil {
+				return err
+			}
+			for _, user := range users {
+				if err := backend.Delete(ctx, service, user); err != nil && err != ErrNotFound {
+					return err
+				}
+			}
+			return nil
+		}
+
+		// Without bulk deletion, we'll try to delete some common user
+		// patterns, but this is not guaranteed to delete all
 		for _, user := range commonUsers {
 			// Try to delete each common user, ignore errors
-			_ = keyring.Delete(service, user)
+			_ = backend.Delete(ctx, service, user)
 		}
 		
 		// Return success since we've attempted cleanup
@@ -59,6 +106,9 @@ func Purge(ctx context.Context, service string) error {
 	}
 }
 
+// commonUsers are tried by Purge when a backend cannot list its secrets
+var commonUsers = []string{"user", "admin", "root", "default", "test", "demo"}
+
 // Re-export the error types from the underlying library for convenience
 var (
 	ErrNotFound         = keyring.ErrNotFound
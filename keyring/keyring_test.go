@@ -2,10 +2,19 @@ package keyring
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/zalando/go-keyring"
 )
 
 const (
@@ -162,15 +171,10 @@ func TestPurge(t *testing.T) {
 		t.Errorf("Purge should not fail, got: %s", err)
 	}
 
-	// Clean up manually since Purge only tries common patterns
-	err = Delete(ctx, service, user)
-	if err != nil {
-		t.Errorf("Should not fail, got: %s", err)
-	}
-
-	err = Delete(ctx, service, user+"2")
-	if err != nil {
-		t.Errorf("Should not fail, got: %s", err)
+	for _, u := range []string{user, user + "2"} {
+		if _, err := Get(ctx, service, u); err != ErrNotFound {
+			t.Errorf("Expected error ErrNotFound for %s after purge, got %v", u, err)
+		}
 	}
 }
 
@@ -235,3 +239,519 @@ func TestContextTimeout(t *testing.T) {
 		t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
 	}
 }
+
+
+// testBackend stores, reads, deletes and purges secrets through backend.
+func testBackend(t *testing.T, backend Backend) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := backend.Set(ctx, service, user, password); err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	if err := backend.Set(ctx, service, user+"2", password+"2"); err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+
+	pw, err := backend.Get(ctx, service, user)
+	if err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	if pw != password {
+		t.Errorf("Expected password %s, got %s", password, pw)
+	}
+
+	if err := backend.Delete(ctx, service, user); err != nil {
+		t.Errorf("Should not fail, got: %s", err)
+	}
+	if _, err := backend.Get(ctx, service, user); err != ErrNotFound {
+		t.Errorf("Expected error ErrNotFound, got %v", err)
+	}
+	if err := backend.Delete(ctx, service, user); err != ErrNotFound {
+		t.Errorf("Expected error ErrNotFound, got %v", err)
+	}
+
+	if err := backend.(Purger).Purge(ctx, service); err != nil {
+		t.Errorf("Purge should not fail, got: %s", err)
+	}
+	if _, err := backend.Get(ctx, service, user+"2"); err != ErrNotFound {
+		t.Errorf("Expected error ErrNotFound after purge, got %v", err)
+	}
+}
+
+// TestFileBackend tests the encrypted file backend.
+func TestFileBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.enc")
+	backend, err := NewBackend(BackendFile, map[string]string{"path": path, "passphrase": "secret"})
+	if err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	testBackend(t, backend)
+
+	ctx := context.Background()
+	if err := backend.Set(ctx, service, user, password); err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+
+	wrong, _ := NewBackend(BackendFile, map[string]string{"path": path, "passphrase": "wrong"})
+	if _, err := wrong.Get(ctx, service, user); err != ErrWrongPassphrase {
+		t.Errorf("Expected error ErrWrongPassphrase, got %v", err)
+	}
+
+	if _, err := NewBackend(BackendFile, map[string]string{"path": path}); err == nil {
+		t.Errorf("Expected error without a passphrase")
+	}
+}
+
+// fakeVault serves the parts of the Vault KV v2 API used by the backend.
+func fakeVault(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	secrets := map[string]string{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		path := r.URL.EscapedPath()
+		key := strings.TrimPrefix(strings.TrimPrefix(path, "/v1/kv/data/"), "/v1/kv/metadata/")
+		switch {
+		case r.Method == http.MethodPost:
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			secrets[key] = body.Data["password"]
+		case r.Method == "LIST":
+			var keys []string
+			for k := range secrets {
+				if strings.HasPrefix(k, key+"/") {
+					keys = append(keys, strings.TrimPrefix(k, key+"/"))
+				}
+			}
+			if len(keys) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+		case r.Method == http.MethodDelete:
+			delete(secrets, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			value, ok := secrets[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"data": map[string]string{"password": value}},
+			})
+		}
+	}))
+}
+
+// TestVaultBackend tests the Vault backend against a fake server.
+func TestVaultBackend(t *testing.T) {
+	server := fakeVault(t)
+	defer server.Close()
+
+	backend, err := NewBackend(BackendVault, map[string]string{
+		"address": server.URL,
+		"token":   "token",
+		"mount":   "kv",
+	})
+	if err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	testBackend(t, backend)
+}
+
+// fakeSecretsManager serves the parts of the Secrets Manager API used by
+// the backend.
+func fakeSecretsManager(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	secrets := map[string]string{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		data, _ := io.ReadAll(r.Body)
+		var input struct {
+			Name         string
+			SecretId     string
+			SecretString string
+			Filters      []struct{ Values []string }
+		}
+		json.Unmarshal(data, &input)
+
+		notFound := func() {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"__type": "ResourceNotFoundException"})
+		}
+
+		switch strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "secretsmanager.") {
+		case "CreateSecret":
+			secrets[input.Name] = input.SecretString
+		case "PutSecretValue":
+			if _, ok := secrets[input.SecretId]; !ok {
+				notFound()
+				return
+			}
+			secrets[input.SecretId] = input.SecretString
+		case "GetSecretValue":
+			value, ok := secrets[input.SecretId]
+			if !ok {
+				notFound()
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"SecretString": value})
+		case "DeleteSecret":
+			if _, ok := secrets[input.SecretId]; !ok {
+				notFound()
+				return
+			}
+			delete(secrets, input.SecretId)
+		case "ListSecrets":
+			var list []map[string]string
+			for name := range secrets {
+				if strings.HasPrefix(name, input.Filters[0].Values[0]) {
+					list = append(list, map[string]string{"Name": name})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"SecretList": list})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+// TestAWSBackend tests the AWS Secrets Manager backend against a fake server.
+func TestAWSBackend(t *testing.T) {
+	server := fakeSecretsManager(t)
+	defer server.Close()
+
+	backend, err := NewBackend(BackendAWS, map[string]string{
+		"region":            "us-east-1",
+		"endpoint":          server.URL,
+		"access_key_id":     "AKID",
+		"secret_access_key": "secret",
+	})
+	if err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	testBackend(t, backend)
+}
+
+// TestBackendSelection tests selecting backends by name and environment.
+func TestBackendSelection(t *testing.T) {
+	if _, err := NewBackend("unknown", nil); err == nil {
+		t.Errorf("Expected error for an unknown backend")
+	}
+
+	t.Setenv(EnvBackend, BackendFile)
+	t.Setenv(EnvOptionPrefix+"PASSPHRASE", "secret")
+	t.Setenv(EnvOptionPrefix+"PATH", filepath.Join(t.TempDir(), "keyring.enc"))
+
+	config := ConfigFromEnv()
+	if config.Backend != BackendFile || config.Options["passphrase"] != "secret" {
+		t.Errorf("Expected file backend with a passphrase, got %+v", config)
+	}
+
+	backend, err := Open(config)
+	if err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	UseBackend(backend)
+	defer UseBackend(nil)
+
+	ctx := context.Background()
+	if err := Set(ctx, service, user, password); err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	if err := Purge(ctx, service); err != nil {
+		t.Errorf("Purge should not fail, got: %s", err)
+	}
+	if _, err := Get(ctx, service, user); err != ErrNotFound {
+		t.Errorf("Expected error ErrNotFound after purge, got %v", err)
+	}
+}
+
+// TestList tests listing secrets through each listing backend.
+func TestList(t *testing.T) {
+	vault := fakeVault(t)
+	defer vault.Close()
+	aws := fakeSecretsManager(t)
+	defer aws.Close()
+
+	backends := map[string]map[string]string{
+		BackendFile:  {"path": filepath.Join(t.TempDir(), "keyring.enc"), "passphrase": "secret"},
+		BackendVault: {"address": vault.URL, "token": "token", "mount": "kv"},
+		BackendAWS:   {"region": "us-east-1", "endpoint": aws.URL, "access_key_id": "AKID", "secret_access_key": "secret"},
+	}
+
+	ctx := context.Background()
+	for name, options := range backends {
+		t.Run(name, func(t *testing.T) {
+			backend, err := NewBackend(name, options)
+			if err != nil {
+				t.Fatalf("Should not fail, got: %s", err)
+			}
+
+			users, err := backend.(Lister).List(ctx, service)
+			if err != nil || len(users) != 0 {
+				t.Errorf("Expected no users, got %v (%v)", users, err)
+			}
+
+			backend.Set(ctx, service, "b", password)
+			backend.Set(ctx, service, "a", password)
+			backend.Set(ctx, service+"/nested", "c", password)
+
+			users, err = backend.(Lister).List(ctx, service)
+			if err != nil {
+				t.Fatalf("Should not fail, got: %s", err)
+			}
+			if strings.Join(users, ",") != "a,b" {
+				t.Errorf("Expected users a,b, got %v", users)
+			}
+		})
+	}
+}
+
+// TestNamespace tests namespaced service names.
+func TestNamespace(t *testing.T) {
+	backend, err := NewBackend(BackendFile, map[string]string{
+		"path":       filepath.Join(t.TempDir(), "keyring.enc"),
+		"passphrase": "secret",
+	})
+	if err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+
+	ns := ExtensionNamespace("sync").WithBackend(backend)
+	if ns.Service("api") != "extension/sync/api" {
+		t.Errorf("Expected service extension/sync/api, got %s", ns.Service("api"))
+	}
+	if child := ns.Child("/prod/"); child.Prefix() != "extension/sync/prod" {
+		t.Errorf("Expected prefix extension/sync/prod, got %s", child.Prefix())
+	}
+
+	ctx := context.Background()
+	if err := ns.Set(ctx, "api", user, password); err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	if _, err := backend.Get(ctx, "api", user); err != ErrNotFound {
+		t.Errorf("Expected error ErrNotFound outside the namespace, got %v", err)
+	}
+
+	pw, err := backend.Get(ctx, "extension/sync/api", user)
+	if err != nil || pw != password {
+		t.Errorf("Expected password %s, got %s (%v)", password, pw, err)
+	}
+
+	users, err := ns.List(ctx, "api")
+	if err != nil || len(users) != 1 || users[0] != user {
+		t.Errorf("Expected users [%s], got %v (%v)", user, users, err)
+	}
+
+	if err := ns.Purge(ctx, "api"); err != nil {
+		t.Errorf("Purge should not fail, got: %s", err)
+	}
+	if _, err := ns.Get(ctx, "api", user); err != ErrNotFound {
+		t.Errorf("Expected error ErrNotFound after purge, got %v", err)
+	}
+}
+
+// TestMock tests the mock backend and its failure injection.
+func TestMock(t *testing.T) {
+	mock := NewMock()
+	testBackend(t, mock)
+
+	UseBackend(mock)
+	defer UseBackend(nil)
+	mock.Reset()
+
+	ctx := context.Background()
+	injected := errors.New("injected")
+
+	mock.FailNext(OpSet, injected)
+	if err := Set(ctx, service, user, password); err != injected {
+		t.Errorf("Expected injected error, got %v", err)
+	}
+	if err := Set(ctx, service, user, password); err != nil {
+		t.Errorf("Should not fail, got: %s", err)
+	}
+
+	mock.FailAlways("", injected)
+	if _, err := Get(ctx, service, user); err != injected {
+		t.Errorf("Expected injected error, got %v", err)
+	}
+	if _, err := List(ctx, service); err != injected {
+		t.Errorf("Expected injected error, got %v", err)
+	}
+	mock.ClearFailures()
+
+	mock.SetMaxSize(10)
+	if err := Set(ctx, service, user, password); err != ErrSetDataTooBig {
+		t.Errorf("Expected error ErrSetDataTooBig, got %v", err)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 5 || calls[0] != (MockCall{Op: OpSet, Service: service, User: user}) {
+		t.Errorf("Expected 5 calls starting with a set, got %+v", calls)
+	}
+	if secrets := mock.Secrets(); secrets[service][user] != password {
+		t.Errorf("Expected stored password %s, got %v", password, secrets)
+	}
+}
+
+// TestBatch tests batch operations on batching and non-batching backends.
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	secrets := []Secret{
+		{Service: service, User: "a", Password: "new"},
+		{Service: service, User: "b", Password: "short"},
+		{Service: service, User: "c", Password: strings.Repeat("x", 100)},
+	}
+
+	// A backend without batch support is rolled back one secret at a time
+	mock := NewMock()
+	plain := struct{ Backend }{mock}
+	mock.Set(ctx, service, "a", "old")
+	mock.SetMaxSize(50)
+	if err := setMany(ctx, plain, secrets); !errors.Is(err, ErrSetDataTooBig) {
+		t.Errorf("Expected error ErrSetDataTooBig, got %v", err)
+	}
+	if pw, _ := mock.Get(ctx, service, "a"); pw != "old" {
+		t.Errorf("Expected rolled back password old, got %s", pw)
+	}
+	if _, err := mock.Get(ctx, service, "b"); err != ErrNotFound {
+		t.Errorf("Expected error ErrNotFound after rollback, got %v", err)
+	}
+
+	// The mock applies batches all at once
+	mock.FailNext(OpSet, errors.New("injected"))
+	if err := mock.SetMany(ctx, secrets[:2]); err == nil {
+		t.Errorf("Expected injected error")
+	}
+	if pw, _ := mock.Get(ctx, service, "a"); pw != "old" {
+		t.Errorf("Expected unchanged password old, got %s", pw)
+	}
+
+	file, err := NewBackend(BackendFile, map[string]string{
+		"path":       filepath.Join(t.TempDir(), "keyring.enc"),
+		"passphrase": "secret",
+	})
+	if err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+
+	for name, backend := range map[string]Backend{"mock": NewMock(), "file": file, "plain": struct{ Backend }{NewMock()}} {
+		t.Run(name, func(t *testing.T) {
+			if err := setMany(ctx, backend, secrets); err != nil {
+				t.Fatalf("Should not fail, got: %s", err)
+			}
+
+			got, err := getMany(ctx, backend, []Secret{{Service: service, User: "b"}, {Service: service, User: "a"}})
+			if err != nil {
+				t.Fatalf("Should not fail, got: %s", err)
+			}
+			if len(got) != 2 || got[0].Password != "short" || got[1].Password != "new" {
+				t.Errorf("Expected passwords short and new, got %+v", got)
+			}
+
+			if _, err := getMany(ctx, backend, []Secret{{Service: service, User: "a"}, {Service: service, User: "missing"}}); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected error ErrNotFound, got %v", err)
+			}
+
+			err = deleteMany(ctx, backend, []Secret{{Service: service, User: "a"}, {Service: service, User: "missing"}})
+			if !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected error ErrNotFound, got %v", err)
+			}
+			if _, err := backend.Get(ctx, service, "a"); err != nil {
+				t.Errorf("Expected a to survive a failed batch delete, got %v", err)
+			}
+
+			if err := deleteMany(ctx, backend, secrets); err != nil {
+				t.Errorf("Should not fail, got: %s", err)
+			}
+			if _, err := backend.Get(ctx, service, "c"); err != ErrNotFound {
+				t.Errorf("Expected error ErrNotFound, got %v", err)
+			}
+		})
+	}
+
+	ns := ExtensionNamespace("sync").WithBackend(NewMock())
+	if err := ns.SetMany(ctx, secrets[:1]); err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	got, err := ns.GetMany(ctx, secrets[:1])
+	if err != nil || got[0].Service != service || got[0].Password != "new" {
+		t.Errorf("Expected namespaced secret for %s, got %+v (%v)", service, got, err)
+	}
+}
+
+// TestPurgeListedUsers tests that Purge removes users outside the common
+// patterns when the backend can list them.
+func TestPurgeListedUsers(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMock()
+	UseBackend(struct {
+		Backend
+		Lister
+	}{mock, mock})
+	defer UseBackend(nil)
+
+	mock.Set(ctx, service, "deploy-bot", password)
+	mock.Set(ctx, "other-service", "deploy-bot", password)
+
+	if err := Purge(ctx, service); err != nil {
+		t.Fatalf("Purge should not fail, got: %s", err)
+	}
+	if _, err := mock.Get(ctx, service, "deploy-bot"); err != ErrNotFound {
+		t.Errorf("Expected error ErrNotFound after purge, got %v", err)
+	}
+	if _, err := mock.Get(ctx, "other-service", "deploy-bot"); err != nil {
+		t.Errorf("Expected other services to be kept, got %v", err)
+	}
+}
+
+// TestSystemBackendPurge tests that the system backend purges indexed users.
+// It swaps in the in-memory provider of go-keyring for the rest of the run,
+// so it must stay the last test that touches the system keyring.
+func TestSystemBackendPurge(t *testing.T) {
+	keyring.MockInit()
+
+	ctx := context.Background()
+	backend := &systemBackend{}
+	UseBackend(backend)
+	defer UseBackend(nil)
+
+	if err := backend.Set(ctx, service, "deploy-bot", password); err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+	if err := backend.Set(ctx, service, "user", password); err != nil {
+		t.Fatalf("Should not fail, got: %s", err)
+	}
+
+	if err := Purge(ctx, service); err != nil {
+		t.Fatalf("Purge should not fail, got: %s", err)
+	}
+	for _, u := range []string{"deploy-bot", "user"} {
+		if _, err := backend.Get(ctx, service, u); err != ErrNotFound {
+			t.Errorf("Expected error ErrNotFound for %s after purge, got %v", u, err)
+		}
+	}
+	if users, err := backend.List(ctx, service); err != nil || len(users) != 0 {
+		t.Errorf("Expected an empty index after purge, got %v (%v)", users, err)
+	}
+}
@@ -0,0 +1,285 @@
+package keyring
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// BackendMock is the name Mock reports as its backend name
+const BackendMock = "mock"
+
+// Operations recorded by Mock and used to target injected failures
+const (
+	OpSet    = "set"
+	OpGet    = "get"
+	OpDelete = "delete"
+	OpList   = "list"
+	OpPurge  = "purge"
+)
+
+// MockCall records a call made to a Mock
+type MockCall struct {
+	Op      string
+	Service string
+	User    string
+}
+
+// Mock is an in-memory backend for tests. It behaves like the other
+// backends, listing users in sorted order, and lets tests inject failures.
+// Use it with UseBackend so code calling Set, Get and friends never
+// touches the OS keychain:
+//
+//	mock := keyring.NewMock()
+//	keyring.UseBackend(mock)
+//	defer keyring.UseBackend(nil)
+type Mock struct {
+	mu      sync.Mutex
+	secrets map[string]map[string]string
+	next    map[string][]error
+	always  map[string]error
+	maxSize int
+	calls   []MockCall
+}
+
+// NewMock creates an empty mock backend
+func NewMock() *Mock {
+	return &Mock{
+		secrets: make(map[string]map[string]string),
+		next:    make(map[string][]error),
+		always:  make(map[string]error),
+	}
+}
+
+func (m *Mock) Name() string {
+	return BackendMock
+}
+
+func (m *Mock) Set(ctx context.Context, service, user, password string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.begin(ctx, OpSet, service, user); err != nil {
+		return err
+	}
+	if m.maxSize > 0 && len(service)+len(user)+len(password) > m.maxSize {
+		return ErrSetDataTooBig
+	}
+	if m.secrets[service] == nil {
+		m.secrets[service] = make(map[string]string)
+	}
+	m.secrets[service][user] = password
+	return nil
+}
+
+func (m *Mock) Get(ctx context.Context, service, user string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.begin(ctx, OpGet, service, user); err != nil {
+		return "", err
+	}
+	password, ok := m.secrets[service][user]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return password, nil
+}
+
+func (m *Mock) Delete(ctx context.Context, service, user string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.begin(ctx, OpDelete, service, user); err != nil {
+		return err
+	}
+	if _, ok := m.secrets[service][user]; !ok {
+		return ErrNotFound
+	}
+	delete(m.secrets[service], user)
+	if len(m.secrets[service]) == 0 {
+		delete(m.secrets, service)
+	}
+	return nil
+}
+
+// List returns the sorted users with a secret for service
+func (m *Mock) List(ctx context.Context, service string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.begin(ctx, OpList, service, ""); err != nil {
+		return nil, err
+	}
+	users := make([]string, 0, len(m.secrets[service]))
+	for user := range m.secrets[service] {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+// Purge removes every secret of service
+func (m *Mock) Purge(ctx context.Context, service string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.begin(ctx, OpPurge, service, ""); err != nil {
+		return err
+	}
+	delete(m.secrets, service)
+	return nil
+}
+
+// SetMany stores secrets all at once. An injected OpSet failure for any of
+// them leaves every secret unchanged
+func (m *Mock) SetMany(ctx context.Context, secrets []Secret) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, secret := range secrets {
+		if err := m.begin(ctx, OpSet, secret.Service, secret.User); err != nil {
+			return err
+		}
+		if m.maxSize > 0 && len(secret.Service)+len(secret.User)+len(secret.Password) > m.maxSize {
+			return ErrSetDataTooBig
+		}
+	}
+	for _, secret := range secrets {
+		if m.secrets[secret.Service] == nil {
+			m.secrets[secret.Service] = make(map[string]string)
+		}
+		m.secrets[secret.Service][secret.User] = secret.Password
+	}
+	return nil
+}
+
+// GetMany retrieves secrets in the order of keys
+func (m *Mock) GetMany(ctx context.Context, keys []Secret) ([]Secret, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]Secret, 0, len(keys))
+	for _, key := range keys {
+		if err := m.begin(ctx, OpGet, key.Service, key.User); err != nil {
+			return nil, err
+		}
+		password, ok := m.secrets[key.Service][key.User]
+		if !ok {
+			return nil, fmt.Errorf("failed to get %s/%s: %w", key.Service, key.User, ErrNotFound)
+		}
+		result = append(result, Secret{Service: key.Service, User: key.User, Password: password})
+	}
+	return result, nil
+}
+
+// DeleteMany removes secrets all at once, or none if any of them is missing
+// or has an injected OpDelete failure
+func (m *Mock) DeleteMany(ctx context.Context, keys []Secret) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if err := m.begin(ctx, OpDelete, key.Service, key.User); err != nil {
+			return err
+		}
+		if _, ok := m.secrets[key.Service][key.User]; !ok {
+			return fmt.Errorf("failed to delete %s/%s: %w", key.Service, key.User, ErrNotFound)
+		}
+	}
+	for _, key := range keys {
+		delete(m.secrets[key.Service], key.User)
+		if len(m.secrets[key.Service]) == 0 {
+			delete(m.secrets, key.Service)
+		}
+	}
+	return nil
+}
+
+// FailNext makes the next call of op return err. Several failures for the
+// same op are returned in order. An empty op matches any operation
+func (m *Mock) FailNext(op string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.next[op] = append(m.next[op], err)
+}
+
+// FailAlways makes every call of op return err until ClearFailures. An
+// empty op matches any operation
+func (m *Mock) FailAlways(op string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.always[op] = err
+}
+
+// ClearFailures removes every injected failure
+func (m *Mock) ClearFailures() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.next = make(map[string][]error)
+	m.always = make(map[string]error)
+}
+
+// SetMaxSize makes Set return ErrSetDataTooBig when the service, user and
+// password together exceed size bytes, like the macOS and Windows
+// keychains. Zero removes the limit
+func (m *Mock) SetMaxSize(size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxSize = size
+}
+
+// Calls returns every call made so far, in order
+func (m *Mock) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+// Secrets returns a copy of the stored secrets, by service and user
+func (m *Mock) Secrets() map[string]map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	secrets := make(map[string]map[string]string, len(m.secrets))
+	for service, users := range m.secrets {
+		secrets[service] = make(map[string]string, len(users))
+		for user, password := range users {
+			secrets[service][user] = password
+		}
+	}
+	return secrets
+}
+
+// Reset removes every secret, recorded call and injected failure
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets = make(map[string]map[string]string)
+	m.next = make(map[string][]error)
+	m.always = make(map[string]error)
+	m.calls = nil
+}
+
+// begin records a call and returns the error it should fail with, if any,
+// without locking
+func (m *Mock) begin(ctx context.Context, op, service, user string) error {
+	m.calls = append(m.calls, MockCall{Op: op, Service: service, User: user})
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, key := range []string{op, ""} {
+		if errs := m.next[key]; len(errs) > 0 {
+			m.next[key] = errs[1:]
+			return errs[0]
+		}
+	}
+	for _, key := range []string{op, ""} {
+		if err := m.always[key]; err != nil {
+			return err
+		}
+	}
+	return nil
+}
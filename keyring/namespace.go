@@ -0,0 +1,172 @@
+package keyring
+
+import (
+	"context"
+	"strings"
+)
+
+// NamespaceSeparator separates the parts of a namespaced service name
+const NamespaceSeparator = "/"
+
+// Namespace scopes service names, so extensions and contexts can store
+// secrets under the same service and user names without clashing. A
+// namespace "extension/sync" stores the service "api" as "extension/sync/api"
+type Namespace struct {
+	prefix  string
+	backend Backend
+}
+
+// NewNamespace creates a namespace from its parts, using the current backend
+func NewNamespace(parts ...string) Namespace {
+	return Namespace{prefix: joinNamespace(parts)}
+}
+
+// ExtensionNamespace returns the namespace of an extension's secrets
+func ExtensionNamespace(name string) Namespace {
+	return NewNamespace("extension", name)
+}
+
+// ContextNamespace returns the namespace of a context's secrets
+func ContextNamespace(name string) Namespace {
+	return NewNamespace("context", name)
+}
+
+// WithBackend returns a copy of the namespace that uses backend instead of
+// the current backend
+func (n Namespace) WithBackend(backend Backend) Namespace {
+	n.backend = backend
+	return n
+}
+
+// Child returns a namespace nested within n
+func (n Namespace) Child(parts ...string) Namespace {
+	return Namespace{
+		prefix:  joinNamespace(append([]string{n.prefix}, parts...)),
+		backend: n.backend,
+	}
+}
+
+// Prefix returns the namespace as it is prepended to service names
+func (n Namespace) Prefix() string {
+	return n.prefix
+}
+
+// Service returns the full service name of service within the namespace
+func (n Namespace) Service(service string) string {
+	return joinNamespace([]string{n.prefix, service})
+}
+
+// Set stores a secret for service within the namespace
+func (n Namespace) Set(ctx context.Context, service, user, password string) error {
+	if n.backend == nil {
+		return Set(ctx, n.Service(service), user, password)
+	}
+	return n.backend.Set(ctx, n.Service(service), user, password)
+}
+
+// Get retrieves a secret for service within the namespace
+func (n Namespace) Get(ctx context.Context, service, user string) (string, error) {
+	if n.backend == nil {
+		return Get(ctx, n.Service(service), user)
+	}
+	return n.backend.Get(ctx, n.Service(service), user)
+}
+
+// Delete removes a secret for service within the namespace
+func (n Namespace) Delete(ctx context.Context, service, user string) error {
+	if n.backend == nil {
+		return Delete(ctx, n.Service(service), user)
+	}
+	return n.backend.Delete(ctx, n.Service(service), user)
+}
+
+// List returns the users with a secret for service within the namespace
+func (n Namespace) List(ctx context.Context, service string) ([]string, error) {
+	if n.backend == nil {
+		return List(ctx, n.Service(service))
+	}
+	lister, ok := n.backend.(Lister)
+	if !ok {
+		return nil, ErrListUnsupported
+	}
+	return lister.List(ctx, n.Service(service))
+}
+
+// Purge removes every secret for service within the namespace
+func (n Namespace) Purge(ctx context.Context, service string) error {
+	if n.backend == nil {
+		return Purge(ctx, n.Service(service))
+	}
+	if purger, ok := n.backend.(Purger); ok {
+		return purger.Purge(ctx, n.Service(service))
+	}
+
+	users, err := n.List(ctx, service)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err := n.backend.Delete(ctx, n.Service(service), user); err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetMany stores secrets whose services are within the namespace
+func (n Namespace) SetMany(ctx context.Context, secrets []Secret) error {
+	if n.backend == nil {
+		return SetMany(ctx, n.secrets(secrets))
+	}
+	return setMany(ctx, n.backend, n.secrets(secrets))
+}
+
+// GetMany retrieves secrets whose services are within the namespace. The
+// returned secrets carry the services as given, without the namespace
+func (n Namespace) GetMany(ctx context.Context, keys []Secret) ([]Secret, error) {
+	var result []Secret
+	var err error
+	if n.backend == nil {
+		result, err = GetMany(ctx, n.secrets(keys))
+	} else {
+		result, err = getMany(ctx, n.backend, n.secrets(keys))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].Service = keys[i].Service
+	}
+	return result, nil
+}
+
+// DeleteMany removes secrets whose services are within the namespace
+func (n Namespace) DeleteMany(ctx context.Context, keys []Secret) error {
+	if n.backend == nil {
+		return DeleteMany(ctx, n.secrets(keys))
+	}
+	return deleteMany(ctx, n.backend, n.secrets(keys))
+}
+
+// secrets returns a copy of secrets with their services in the namespace
+func (n Namespace) secrets(secrets []Secret) []Secret {
+	result := make([]Secret, len(secrets))
+	for i, secret := range secrets {
+		secret.Service = n.Service(secret.Service)
+		result[i] = secret
+	}
+	return result
+}
+
+// joinNamespace joins non-empty parts with NamespaceSeparator
+func joinNamespace(parts []string) string {
+	var kept []string
+	for _, part := range parts {
+		part = strings.Trim(part, NamespaceSeparator)
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, NamespaceSeparator)
+}
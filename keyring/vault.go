@@ -0,0 +1,208 @@
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// vaultBackend stores secrets in a HashiCorp Vault KV version 2 secrets
+// engine, one secret per service and user under a common prefix
+type vaultBackend struct {
+	client    *http.Client
+	address   string
+	token     string
+	namespace string
+	mount     string
+	prefix    string
+}
+
+// newVaultBackend creates the Vault backend. Options: "address" (or
+// VAULT_ADDR), "token" (or VAULT_TOKEN), "namespace" (or VAULT_NAMESPACE),
+// "mount" (defaults to "secret") and "prefix" (defaults to "tykctl")
+func newVaultBackend(options map[string]string) (Backend, error) {
+	address := option(options, "address", "VAULT_ADDR")
+	if address == "" {
+		return nil, fmt.Errorf("a Vault address is required")
+	}
+	token := option(options, "token", "VAULT_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("a Vault token is required")
+	}
+
+	mount := option(options, "mount")
+	if mount == "" {
+		mount = "secret"
+	}
+	prefix := option(options, "prefix")
+	if prefix == "" {
+		prefix = "tykctl"
+	}
+
+	return &vaultBackend{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		address:   strings.TrimSuffix(address, "/"),
+		token:     token,
+		namespace: option(options, "namespace", "VAULT_NAMESPACE"),
+		mount:     strings.Trim(mount, "/"),
+		prefix:    strings.Trim(prefix, "/"),
+	}, nil
+}
+
+func (b *vaultBackend) Name() string {
+	return BackendVault
+}
+
+func (b *vaultBackend) Set(ctx context.Context, service, user, password string) error {
+	body := map[string]interface{}{
+		"data": map[string]string{"password": password},
+	}
+	_, err := b.do(ctx, http.MethodPost, b.url("data", service, user), body)
+	return err
+}
+
+func (b *vaultBackend) Get(ctx context.Context, service, user string) (string, error) {
+	data, err := b.do(ctx, http.MethodGet, b.url("data", service, user), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", fmt.Errorf("failed to parse Vault response: %w", err)
+	}
+
+	password, ok := response.Data.Data["password"]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return password, nil
+}
+
+// Delete removes every version of the secret. Vault deletes missing
+// secrets silently, so the secret is looked up first
+func (b *vaultBackend) Delete(ctx context.Context, service, user string) error {
+	if _, err := b.do(ctx, http.MethodGet, b.url("metadata", service, user), nil); err != nil {
+		return err
+	}
+	_, err := b.do(ctx, http.MethodDelete, b.url("metadata", service, user), nil)
+	return err
+}
+
+// List returns the sorted users with a secret for service
+func (b *vaultBackend) List(ctx context.Context, service string) ([]string, error) {
+	data, err := b.do(ctx, "LIST", b.url("metadata", service), nil)
+	if err == ErrNotFound {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Vault response: %w", err)
+	}
+
+	users := []string{}
+	for _, key := range response.Data.Keys {
+		// Keys ending in a slash are folders, not secrets stored by Set
+		if strings.HasSuffix(key, "/") {
+			continue
+		}
+		user, err := url.PathUnescape(key)
+		if err != nil {
+			user = key
+		}
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+// Purge removes every secret of service
+func (b *vaultBackend) Purge(ctx context.Context, service string) error {
+	users, err := b.List(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := b.Delete(ctx, service, user); err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// url returns the API URL of a KV path under the backend prefix
+func (b *vaultBackend) url(kind string, segments ...string) string {
+	path := b.prefix
+	for _, segment := range segments {
+		path += "/" + url.PathEscape(segment)
+	}
+	return fmt.Sprintf("%s/v1/%s/%s/%s", b.address, b.mount, kind, path)
+}
+
+// do sends a request to Vault and returns the response body. A 404
+// response is returned as ErrNotFound
+func (b *vaultBackend) do(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode Vault request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", b.token)
+	if b.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", b.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		var response struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(data, &response)
+		return nil, fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.Join(response.Errors, "; "))
+	}
+	return data, nil
+}
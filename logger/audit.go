@@ -0,0 +1,373 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Fields every audit entry carries. Entries are chained: each holds the
+// hash of the one before it, and its own hash covers everything else in
+// the entry, so editing or removing an entry breaks the chain
+const (
+	AuditFieldTime     = "time"
+	AuditFieldEvent    = "event"
+	AuditFieldActor    = "actor"
+	AuditFieldHost     = "host"
+	AuditFieldSeq      = "seq"
+	AuditFieldPrevHash = "prev_hash"
+	AuditFieldHash     = "hash"
+)
+
+// AuditLoggerName names audit entries sent to sinks
+const AuditLoggerName = "audit"
+
+// ErrAuditTampered reports an audit log whose hash chain is broken
+var ErrAuditTampered = errors.New("audit log tampered")
+
+// AuditConfig says where audit entries go. They are kept apart from the
+// other logs and are never sampled or filtered by level
+type AuditConfig struct {
+	// File is the append-only audit log, one JSON entry per line
+	File string
+	// Sinks receive every audit entry as well, such as a central syslog
+	Sinks []SinkConfig
+}
+
+// Auditor writes audit entries recording who did what and when. Several
+// auditors, in this or other processes, may share the audit log: each
+// entry is appended under a lock on the file, chained to the entry last
+// written by any of them
+type Auditor struct {
+	mu    sync.Mutex
+	path  string
+	file  *os.File
+	sinks []zapcore.Core
+	close []io.Closer
+
+	actor string
+	host  string
+	seq   int64
+	hash  string
+	// size is the size of the audit log when seq and hash were read
+	size int64
+
+	// now returns the current time, replaced by tests
+	now func() time.Time
+}
+
+// auditHashSuffixLen is the length of the hash appended to the JSON body
+// of an entry: ,"hash":"<64 hex digits>"}
+var auditHashSuffixLen = len(`,"hash":""}`) + sha256.Size*2
+
+// NewAuditor opens the audit log in config.File, continuing its hash chain,
+// and the audit sinks
+func NewAuditor(config AuditConfig) (*Auditor, error) {
+	if config.File == "" {
+		return nil, fmt.Errorf("audit log needs a file")
+	}
+	if err := os.MkdirAll(filepath.Dir(config.File), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(config.File, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	a := &Auditor{path: config.File, file: file, actor: auditActor(), size: -1, now: time.Now}
+	a.host, _ = os.Hostname()
+
+	if err := a.withLock(a.refresh); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	for _, sinkConfig := range config.Sinks {
+		core, closer, err := newSink(sinkConfig)
+		if err != nil {
+			a.Close()
+			return nil, fmt.Errorf("failed to open audit sink: %w", err)
+		}
+		a.sinks = append(a.sinks, core)
+		a.close = append(a.close, closer)
+	}
+	return a, nil
+}
+
+// Audit appends an entry for event, such as "extension.install", with
+// fields saying what it applied to
+func (a *Auditor) Audit(event string, fields ...zap.Field) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	err := a.withLock(func() error {
+		if err := a.refresh(); err != nil {
+			return err
+		}
+		return a.append(now, event, fields)
+	})
+	if err != nil {
+		return err
+	}
+
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Time: now, LoggerName: AuditLoggerName, Message: event}
+	sinkFields := append(fields[:len(fields):len(fields)],
+		zap.String(AuditFieldActor, a.actor),
+		zap.Int64(AuditFieldSeq, a.seq),
+		zap.String(AuditFieldHash, a.hash))
+	var errs []error
+	for _, sink := range a.sinks {
+		errs = append(errs, sink.Write(entry, sinkFields))
+	}
+	return errors.Join(errs...)
+}
+
+// withLock runs fn holding the lock on the audit log
+func (a *Auditor) withLock(fn func() error) error {
+	if err := lockFile(a.file); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	err := fn()
+	if unlockErr := unlockFile(a.file); unlockErr != nil && err == nil {
+		err = fmt.Errorf("failed to unlock audit log: %w", unlockErr)
+	}
+	return err
+}
+
+// refresh rereads the sequence number and hash of the last entry when the
+// audit log changed since this auditor last read or wrote it, such as when
+// another auditor appended to it. The file must be locked
+func (a *Auditor) refresh() error {
+	info, err := a.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	if info.Size() == a.size {
+		return nil
+	}
+
+	seq, hash, err := lastAuditEntry(a.path)
+	if err != nil {
+		return err
+	}
+	a.seq, a.hash, a.size = seq, hash, info.Size()
+	return nil
+}
+
+// append writes the entry following the last one. The file must be locked
+func (a *Auditor) append(now time.Time, event string, fields []zap.Field) error {
+	record := fieldMap(fields)
+	record[AuditFieldTime] = now.UTC().Format(time.RFC3339Nano)
+	record[AuditFieldEvent] = event
+	record[AuditFieldActor] = a.actor
+	record[AuditFieldHost] = a.host
+	record[AuditFieldSeq] = a.seq + 1
+	record[AuditFieldPrevHash] = a.hash
+	delete(record, AuditFieldHash)
+
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+
+	line := make([]byte, 0, len(body)+auditHashSuffixLen+1)
+	line = append(line, body[:len(body)-1]...)
+	line = append(line, `,"hash":"`...)
+	line = append(line, hash...)
+	line = append(line, "\"}\n"...)
+
+	if _, err := a.file.Write(line); err != nil {
+		// Reread the log next time, whatever was written of the entry
+		a.size = -1
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	a.seq++
+	a.hash = hash
+	a.size += int64(len(line))
+
+	if err := a.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+	return nil
+}
+
+// Close flushes the sinks and closes the audit log
+func (a *Auditor) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var errs []error
+	for _, closer := range a.close {
+		errs = append(errs, closer.Close())
+	}
+	if a.file != nil {
+		errs = append(errs, a.file.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// VerifyAudit checks the hash chain of the audit log at path and returns
+// how many entries it holds. A broken chain is reported as
+// ErrAuditTampered with the line it breaks at
+func VerifyAudit(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var count int
+	var prev string
+	err = scanAudit(file, func(line int, seq int64, prevHash, hash string, valid bool) error {
+		if !valid || prevHash != prev || seq != int64(line) {
+			return fmt.Errorf("%w at line %d", ErrAuditTampered, line)
+		}
+		prev = hash
+		count = line
+		return nil
+	})
+	return count, err
+}
+
+// lastAuditEntry returns the sequence number and hash of the last entry in
+// the audit log at path, or zeroes when there is none
+func lastAuditEntry(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var seq int64
+	var last string
+	err = scanAudit(file, func(_ int, s int64, _, hash string, _ bool) error {
+		seq, last = s, hash
+		return nil
+	})
+	return seq, last, err
+}
+
+// scanAudit calls fn for each entry of an audit log with its sequence
+// number, the hashes it holds and whether its own hash matches its body
+func scanAudit(r io.Reader, fn func(line int, seq int64, prevHash, hash string, valid bool) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Bytes()
+		if len(text) < auditHashSuffixLen+2 {
+			return fmt.Errorf("%w at line %d", ErrAuditTampered, line)
+		}
+
+		split := len(text) - auditHashSuffixLen
+		body := append(append([]byte{}, text[:split]...), '}')
+		if !bytes.HasPrefix(text[split:], []byte(`,"hash":"`)) {
+			return fmt.Errorf("%w at line %d", ErrAuditTampered, line)
+		}
+		hash := string(text[split+len(`,"hash":"`) : len(text)-2])
+
+		var entry struct {
+			Seq      int64  `json:"seq"`
+			PrevHash string `json:"prev_hash"`
+		}
+		if err := json.Unmarshal(body, &entry); err != nil {
+			return fmt.Errorf("%w at line %d", ErrAuditTampered, line)
+		}
+
+		sum := sha256.Sum256(body)
+		if err := fn(line, entry.Seq, entry.PrevHash, hash, hex.EncodeToString(sum[:]) == hash); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return nil
+}
+
+// auditActor names the user running tykctl
+func auditActor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	for _, env := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(env); name != "" {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// Global auditor used by Audit
+var (
+	auditMu sync.Mutex
+	audit   *Auditor
+)
+
+// InitAudit opens the global auditor used by Audit, closing any previous
+// one
+func InitAudit(config AuditConfig) error {
+	a, err := NewAuditor(config)
+	if err != nil {
+		return err
+	}
+
+	auditMu.Lock()
+	previous := audit
+	audit = a
+	auditMu.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+	return nil
+}
+
+// CloseAudit closes the global auditor
+func CloseAudit() error {
+	auditMu.Lock()
+	a := audit
+	audit = nil
+	auditMu.Unlock()
+
+	if a == nil {
+		return nil
+	}
+	return a.Close()
+}
+
+// Audit records event with the global auditor. It does nothing until
+// InitAudit is called, and failures are logged with the global logger
+// rather than failing the action being audited
+func Audit(event string, fields ...zap.Field) {
+	auditMu.Lock()
+	a := audit
+	auditMu.Unlock()
+
+	if a == nil {
+		return
+	}
+	if err := a.Audit(event, fields...); err != nil {
+		GetGlobal().Warn("Failed to write audit entry", zap.String(AuditFieldEvent, event), zap.Error(err))
+	}
+}
@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestAuditor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+
+	auditor, err := NewAuditor(AuditConfig{File: path})
+	if err != nil {
+		t.Fatalf("NewAuditor failed: %v", err)
+	}
+	auditor.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	if err := auditor.Audit("extension.install", zap.String("name", "foo"), zap.String("version", "1.2")); err != nil {
+		t.Fatalf("Audit failed: %v", err)
+	}
+	if err := auditor.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Reopening continues the chain
+	auditor, err = NewAuditor(AuditConfig{File: path})
+	if err != nil {
+		t.Fatalf("NewAuditor failed: %v", err)
+	}
+	auditor.Audit("plugin.execute", zap.Strings("args", []string{"list"}))
+	auditor.Close()
+
+	if n, err := VerifyAudit(path); err != nil || n != 2 {
+		t.Fatalf("Expected 2 verified entries, got %d (%v)", n, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var first, second map[string]interface{}
+	json.Unmarshal([]byte(lines[0]), &first)
+	json.Unmarshal([]byte(lines[1]), &second)
+
+	if first[AuditFieldEvent] != "extension.install" || first["name"] != "foo" || first[AuditFieldTime] != "2024-01-02T03:04:05Z" {
+		t.Errorf("Unexpected first entry: %v", first)
+	}
+	if first[AuditFieldActor] == "" || first[AuditFieldPrevHash] != "" || first[AuditFieldSeq] != float64(1) {
+		t.Errorf("Expected actor, no previous hash and seq 1, got %v", first)
+	}
+	if second[AuditFieldPrevHash] != first[AuditFieldHash] || second[AuditFieldSeq] != float64(2) {
+		t.Errorf("Expected the second entry to chain to the first, got %v", second)
+	}
+	if info, err := os.Stat(path); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestAuditor_SharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	first, err := NewAuditor(AuditConfig{File: path})
+	if err != nil {
+		t.Fatalf("NewAuditor failed: %v", err)
+	}
+	defer first.Close()
+	second, err := NewAuditor(AuditConfig{File: path})
+	if err != nil {
+		t.Fatalf("NewAuditor failed: %v", err)
+	}
+	defer second.Close()
+
+	// Alternate, so each auditor's cached entry goes stale
+	for i := 0; i < 3; i++ {
+		if err := first.Audit("extension.install"); err != nil {
+			t.Fatalf("Audit failed: %v", err)
+		}
+		if err := second.Audit("plugin.execute"); err != nil {
+			t.Fatalf("Audit failed: %v", err)
+		}
+	}
+
+	// And write concurrently, relying on the file lock
+	var wg sync.WaitGroup
+	for _, auditor := range []*Auditor{first, second} {
+		wg.Add(1)
+		go func(auditor *Auditor) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if err := auditor.Audit("extension.update"); err != nil {
+					t.Errorf("Audit failed: %v", err)
+				}
+			}
+		}(auditor)
+	}
+	wg.Wait()
+
+	if n, err := VerifyAudit(path); err != nil || n != 46 {
+		t.Fatalf("Expected 46 verified entries, got %d (%v)", n, err)
+	}
+}
+
+func TestVerifyAudit_Tampered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := NewAuditor(AuditConfig{File: path})
+	if err != nil {
+		t.Fatalf("NewAuditor failed: %v", err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		auditor.Audit("extension.install", zap.String("name", name))
+	}
+	auditor.Close()
+
+	data, _ := os.ReadFile(path)
+	lines := bytes.SplitAfter(data, []byte("\n"))
+
+	tests := map[string]struct {
+		data []byte
+		line string
+	}{
+		"edited":  {data: bytes.Replace(data, []byte(`"name":"b"`), []byte(`"name":"x"`), 1), line: "line 2"},
+		"removed": {data: append(append([]byte{}, lines[0]...), lines[2]...), line: "line 2"},
+		"garbage": {data: append(append([]byte{}, data...), "oops\n"...), line: "line 4"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tampered := filepath.Join(t.TempDir(), "audit.log")
+			os.WriteFile(tampered, tt.data, 0600)
+
+			_, err := VerifyAudit(tampered)
+			if !errors.Is(err, ErrAuditTampered) || !strings.Contains(err.Error(), tt.line) {
+				t.Errorf("Expected tampering at %s, got %v", tt.line, err)
+			}
+		})
+	}
+}
+
+func TestAudit_Global(t *testing.T) {
+	// Nothing is written before InitAudit
+	Audit("extension.install")
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := InitAudit(AuditConfig{File: path}); err != nil {
+		t.Fatalf("InitAudit failed: %v", err)
+	}
+	defer CloseAudit()
+
+	Audit("extension.remove", zap.String("name", "foo"))
+	if err := CloseAudit(); err != nil {
+		t.Fatalf("CloseAudit failed: %v", err)
+	}
+	Audit("extension.remove", zap.String("name", "bar"))
+
+	if n, err := VerifyAudit(path); err != nil || n != 1 {
+		t.Errorf("Expected 1 entry, got %d (%v)", n, err)
+	}
+}
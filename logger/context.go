@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// Fields added by FromContext for the correlation IDs carried by a context
+const (
+	FieldRequestID = "request_id"
+	FieldCommandID = "command_id"
+)
+
+// Environment variables passing correlation IDs to plugins and other
+// subprocesses
+const (
+	RequestIDEnv = "TYKCTL_REQUEST_ID"
+	CommandIDEnv = "TYKCTL_COMMAND_ID"
+)
+
+type loggerKey struct{}
+
+type requestIDKey struct{}
+
+type commandIDKey struct{}
+
+// WithContext returns a copy of ctx carrying log for FromContext
+func WithContext(ctx context.Context, log *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// FromContext returns the logger carried by ctx, or the global logger if
+// there is none, with the request and command IDs carried by ctx added as
+// fields
+func FromContext(ctx context.Context) *Logger {
+	log, ok := ctx.Value(loggerKey{}).(*Logger)
+	if !ok || log == nil {
+		log = GetGlobal()
+	}
+
+	fields := ContextFields(ctx)
+	if len(fields) == 0 {
+		return log
+	}
+	return log.with(fields...)
+}
+
+// ContextFields returns the correlation IDs carried by ctx as fields
+func ContextFields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if id, ok := RequestIDFromContext(ctx); ok {
+		fields = append(fields, zap.String(FieldRequestID, id))
+	}
+	if id, ok := CommandIDFromContext(ctx); ok {
+		fields = append(fields, zap.String(FieldCommandID, id))
+	}
+	return fields
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of an API request
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithCommandID returns a copy of ctx carrying the ID of a command run
+func WithCommandID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, commandIDKey{}, id)
+}
+
+// CommandIDFromContext returns the command ID carried by ctx, if any
+func CommandIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(commandIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// NewID returns a random 16-character hex ID for requests and commands
+func NewID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ContextEnv returns environment entries passing the correlation IDs
+// carried by ctx to a subprocess, such as a plugin
+func ContextEnv(ctx context.Context) []string {
+	var env []string
+	if id, ok := RequestIDFromContext(ctx); ok {
+		env = append(env, RequestIDEnv+"="+id)
+	}
+	if id, ok := CommandIDFromContext(ctx); ok {
+		env = append(env, CommandIDEnv+"="+id)
+	}
+	return env
+}
+
+// ContextFromEnv returns a copy of ctx carrying the correlation IDs passed
+// to this process by ContextEnv, so a plugin logs with its host's IDs
+func ContextFromEnv(ctx context.Context) context.Context {
+	if id := os.Getenv(RequestIDEnv); id != "" {
+		ctx = WithRequestID(ctx, id)
+	}
+	if id := os.Getenv(CommandIDEnv); id != "" {
+		ctx = WithCommandID(ctx, id)
+	}
+	return ctx
+}
+
+// with returns a copy of the logger with fields added, keeping its module
+// level and log file
+func (l *Logger) with(fields ...zap.Field) *Logger {
+	return &Logger{Logger: l.Logger.With(fields...), file: l.file, sinks: l.sinks, levels: l.levels, module: l.module}
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFromContext(t *testing.T) {
+	log, logs := observed(zapcore.InfoLevel)
+	ctx := WithContext(context.Background(), log.Named("api"))
+	ctx = WithCommandID(ctx, "cmd-1")
+	ctx = WithRequestID(ctx, "req-1")
+
+	FromContext(ctx).Info("request sent")
+	FromContext(ctx).Debug("hidden by the module level")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields[FieldRequestID] != "req-1" || fields[FieldCommandID] != "cmd-1" {
+		t.Errorf("Expected correlation fields, got %v", fields)
+	}
+	if entries[0].LoggerName != "api" {
+		t.Errorf("Expected the logger from the context, got %q", entries[0].LoggerName)
+	}
+}
+
+func TestFromContext_Global(t *testing.T) {
+	if log := FromContext(context.Background()); log != GetGlobal() {
+		t.Error("Expected the global logger when ctx carries none")
+	}
+}
+
+func TestContextEnv(t *testing.T) {
+	ctx := WithCommandID(context.Background(), "cmd-2")
+	env := ContextEnv(ctx)
+	if len(env) != 1 || env[0] != CommandIDEnv+"=cmd-2" {
+		t.Fatalf("Unexpected env %v", env)
+	}
+
+	t.Setenv(CommandIDEnv, "cmd-2")
+	t.Setenv(RequestIDEnv, "")
+	restored := ContextFromEnv(context.Background())
+	if id, ok := CommandIDFromContext(restored); !ok || id != "cmd-2" {
+		t.Errorf("Expected command ID from env, got %q", id)
+	}
+	if _, ok := RequestIDFromContext(restored); ok {
+		t.Error("Expected no request ID from an empty env var")
+	}
+}
+
+func TestNewID(t *testing.T) {
+	a, b := NewID(), NewID()
+	if len(a) != 16 || a == b {
+		t.Errorf("Expected distinct 16-character IDs, got %q and %q", a, b)
+	}
+}
@@ -4,8 +4,14 @@
 //   - Structured Logging: JSON-formatted logs with structured fields
 //   - Zap Integration: Built on the high-performance Zap logging library
 //   - Multiple Levels: Debug, Info, Warn, Error, Fatal, Panic levels
-//   - Context Support: Integration with context.Context for request tracing
+//   - Module Levels: Named sub-loggers with runtime levels from the API, TYKCTL_LOG or SIGHUP
+//   - Context Support: Loggers and request/command correlation IDs carried by context.Context
 //   - Configurable Output: Console and file output options
+//   - Extra Sinks: syslog, journald and OTLP log export
+//   - Sampling: Sampled entries and per-message rate limits for hot loops
+//   - Audit Log: Append-only, hash-chained audit entries with their own file and sinks
+//   - Pretty Console: Colored, aligned output selected by TYKCTL_LOG_FORMAT
+//   - Log Rotation: Size-based file rotation with backup and age limits and compression
 //   - Performance Optimized: High-performance logging with minimal allocation
 //
 // Example:
@@ -0,0 +1,232 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelEnv names the environment variable holding per-module levels, such
+// as TYKCTL_LOG=extension=debug,api=warn. A bare level, as in
+// TYKCTL_LOG=info,api=debug, sets the default for every other module
+const LevelEnv = "TYKCTL_LOG"
+
+// Levels holds the level of each module, shared by a logger and every
+// sub-logger created from it with Named. A module without its own level
+// takes that of its closest parent, so "extension" covers
+// "extension.installer", and otherwise the default
+type Levels struct {
+	mu       sync.RWMutex
+	initial  zapcore.Level
+	fallback zapcore.Level
+	modules  map[string]zapcore.Level
+}
+
+// newLevels returns levels defaulting to level
+func newLevels(level zapcore.Level) *Levels {
+	return &Levels{initial: level, fallback: level, modules: map[string]zapcore.Level{}}
+}
+
+// Level returns the level in effect for module
+func (l *Levels) Level(module string) zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for name := module; name != ""; {
+		if level, ok := l.modules[name]; ok {
+			return level
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return l.fallback
+}
+
+// Set sets the level of module and the modules below it that have no
+// level of their own. An empty module sets the default
+func (l *Levels) Set(module string, level zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if module == "" {
+		l.fallback = level
+		return
+	}
+	l.modules[module] = level
+}
+
+// Unset removes the level of module, so it follows its parent again
+func (l *Levels) Unset(module string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.modules, module)
+}
+
+// Apply replaces every module level with those in spec, a comma-separated
+// list of module=level pairs and at most one bare default level. An empty
+// spec restores the configured default. Nothing changes when spec is
+// invalid
+func (l *Levels) Apply(spec string) error {
+	fallback := l.initial
+	modules := map[string]zapcore.Level{}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		module, text, found := strings.Cut(entry, "=")
+		if !found {
+			module, text = "", entry
+		}
+		module = strings.TrimSpace(module)
+
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(text))); err != nil {
+			return fmt.Errorf("invalid log level %q for %q: %w", text, entry, err)
+		}
+		if module == "" {
+			fallback = level
+			continue
+		}
+		modules[module] = level
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fallback = fallback
+	l.modules = modules
+	return nil
+}
+
+// String describes the levels in the form Apply accepts
+func (l *Levels) String() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := []string{l.fallback.String()}
+	names := make([]string, 0, len(l.modules))
+	for name := range l.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entries = append(entries, name+"="+l.modules[name].String())
+	}
+	return strings.Join(entries, ",")
+}
+
+// Named returns a sub-logger for module, such as "extension.installer",
+// whose level follows the module's entry in Levels. Naming a sub-logger
+// nests the names, as zap does
+func (l *Logger) Named(module string) *Logger {
+	if l.levels == nil {
+		return &Logger{Logger: l.Logger.Named(module), file: l.file, sinks: l.sinks}
+	}
+
+	name := module
+	if l.module != "" {
+		name = l.module + "." + module
+	}
+	named := l.Logger.Named(module).WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if mc, ok := core.(*moduleCore); ok {
+			return &moduleCore{Core: mc.Core, module: name, levels: mc.levels}
+		}
+		return core
+	}))
+	return &Logger{Logger: named, file: l.file, sinks: l.sinks, levels: l.levels, module: name}
+}
+
+// Levels returns the module levels, for changing them at runtime
+func (l *Logger) Levels() *Levels {
+	return l.levels
+}
+
+// SetLevel sets the level of a module at runtime; an empty module sets the
+// default
+func (l *Logger) SetLevel(module string, level zapcore.Level) {
+	if l.levels != nil {
+		l.levels.Set(module, level)
+	}
+}
+
+// HandleSIGHUP reloads module levels from reload and rotates the log file,
+// if any, each time the process receives SIGHUP, until ctx is done. A nil
+// reload reads LevelEnv, which only changes if the process changes it
+func (l *Logger) HandleSIGHUP(ctx context.Context, reload func() (string, error)) {
+	if reload == nil {
+		reload = func() (string, error) { return os.Getenv(LevelEnv), nil }
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				l.reload(reload)
+			}
+		}
+	}()
+}
+
+// reload applies the spec from reload and rotates the log file, logging
+// what goes wrong
+func (l *Logger) reload(reload func() (string, error)) {
+	if l.levels != nil {
+		spec, err := reload()
+		if err == nil {
+			err = l.levels.Apply(spec)
+		}
+		if err != nil {
+			l.Warn("Failed to reload log levels", zap.Error(err))
+		} else {
+			l.Info("Reloaded log levels", zap.String("levels", l.levels.String()))
+		}
+	}
+	if err := l.Rotate(); err != nil {
+		l.Warn("Failed to rotate log file", zap.Error(err))
+	}
+}
+
+// moduleCore filters entries by the level of its module in Levels
+type moduleCore struct {
+	zapcore.Core
+	module string
+	levels *Levels
+}
+
+// Enabled reports whether the module logs at level
+func (c *moduleCore) Enabled(level zapcore.Level) bool {
+	return level >= c.levels.Level(c.module)
+}
+
+// With adds fields, keeping the module filter
+func (c *moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	return &moduleCore{Core: c.Core.With(fields), module: c.module, levels: c.levels}
+}
+
+// Check passes the entry to the wrapped core when the module logs at its
+// level, so samplers and rate limits below only see entries that are kept
+func (c *moduleCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
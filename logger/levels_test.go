@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observed returns a logger at level recording its entries
+func observed(level zapcore.Level) (*Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	levels := newLevels(level)
+	return &Logger{Logger: zap.New(&moduleCore{Core: core, levels: levels}), levels: levels}, logs
+}
+
+func TestLevels_Apply(t *testing.T) {
+	levels := newLevels(zapcore.WarnLevel)
+	if err := levels.Apply("info, extension=debug,api=error"); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	tests := map[string]zapcore.Level{
+		"":                    zapcore.InfoLevel,
+		"config":              zapcore.InfoLevel,
+		"extension":           zapcore.DebugLevel,
+		"extension.installer": zapcore.DebugLevel,
+		"extensions":          zapcore.InfoLevel,
+		"api.client":          zapcore.ErrorLevel,
+	}
+	for module, want := range tests {
+		if got := levels.Level(module); got != want {
+			t.Errorf("Level(%q) = %v, want %v", module, got, want)
+		}
+	}
+	if got := levels.String(); got != "info,api=error,extension=debug" {
+		t.Errorf("Unexpected String() %q", got)
+	}
+
+	if err := levels.Apply("extension=loud"); err == nil {
+		t.Error("Expected an invalid level to fail")
+	}
+	if got := levels.Level("extension"); got != zapcore.DebugLevel {
+		t.Errorf("Expected a failed Apply to change nothing, got %v", got)
+	}
+
+	levels.Apply("")
+	if got := levels.Level("extension"); got != zapcore.WarnLevel {
+		t.Errorf("Expected an empty spec to restore the default, got %v", got)
+	}
+}
+
+func TestLogger_Named(t *testing.T) {
+	log, logs := observed(zapcore.WarnLevel)
+	installer := log.Named("extension").Named("installer")
+
+	installer.Debug("hidden")
+	log.SetLevel("extension", zapcore.DebugLevel)
+	installer.Debug("shown")
+	installer.With(zap.String("id", "x")).Debug("with fields")
+	log.Info("root stays at warn")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Message != "shown" || entries[0].LoggerName != "extension.installer" {
+		t.Errorf("Unexpected entry %+v", entries[0])
+	}
+
+	log.Levels().Unset("extension")
+	installer.Debug("hidden again")
+	if logs.Len() != 2 {
+		t.Errorf("Expected Unset to restore the default level, got %d entries", logs.Len())
+	}
+}
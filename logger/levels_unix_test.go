@@ -0,0 +1,41 @@
+//go:build unix
+
+package logger
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogger_HandleSIGHUP(t *testing.T) {
+	log, logs := observed(zapcore.WarnLevel)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloaded := make(chan struct{}, 1)
+	log.HandleSIGHUP(ctx, func() (string, error) {
+		defer func() { reloaded <- struct{}{} }()
+		return "debug", nil
+	})
+
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected SIGHUP to reload levels")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for log.Levels().Level("") != zapcore.DebugLevel && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	log.Named("api").Debug("now shown")
+	if logs.FilterMessage("now shown").Len() != 1 {
+		t.Error("Expected debug entries after reload")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package logger
+
+import "os"
+
+// lockFile does nothing where files can't be locked
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// unlockFile does nothing where files can't be locked
+func unlockFile(file *os.File) error {
+	return nil
+}
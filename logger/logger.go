@@ -2,6 +2,9 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 
 	"go.uber.org/zap"
@@ -11,6 +14,15 @@ import (
 // Logger wraps zap.Logger with additional functionality
 type Logger struct {
 	*zap.Logger
+
+	// file is the rotating log file, when logging to one
+	file *RotatingWriter
+	// sinks are the extra destinations from Config.Sinks
+	sinks []io.Closer
+	// levels holds the module levels shared with sub-loggers, and module
+	// is the name given to Named
+	levels *Levels
+	module string
 }
 
 // Config represents logger configuration
@@ -18,6 +30,19 @@ type Config struct {
 	Debug   bool
 	Verbose bool
 	NoColor bool
+	// Format is FormatPretty or FormatJSON for stderr; FormatEnv overrides
+	// it and empty keeps zap's console layout
+	Format string
+	// File, when set, writes JSON logs to this file instead of stderr,
+	// rotated as Rotation says
+	File     string
+	Rotation Rotation
+	// Sampling caps repeated entries; nil uses DefaultSampling in
+	// production mode and logs everything with Debug or Verbose
+	Sampling *Sampling
+	// Sinks are extra destinations, such as syslog, journald or an OTLP
+	// collector, that logs are sent to as well
+	Sinks []SinkConfig
 }
 
 // New creates a new logger with the given configuration
@@ -33,14 +58,19 @@ func New(config Config) *Logger {
 	}
 
 	// Set log level
+	level := zap.WarnLevel
 	if config.Debug {
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+		level = zap.DebugLevel
 	} else if config.Verbose {
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-	} else {
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
+		level = zap.InfoLevel
 	}
 
+	// Cores pass every level, leaving the choice to the module levels so
+	// they can be lowered at runtime
+	levels := newLevels(level)
+	levelsErr := levels.Apply(os.Getenv(LevelEnv))
+	zapConfig.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+
 	// Disable colors if requested
 	if config.NoColor || os.Getenv("NO_COLOR") != "" {
 		zapConfig.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
@@ -54,14 +84,93 @@ func New(config Config) *Logger {
 	zapConfig.OutputPaths = []string{"stderr"}
 	zapConfig.ErrorOutputPaths = []string{"stderr"}
 
+	// Sampling is applied with the module levels below, so it also covers
+	// log files and sinks
+	sampling := config.Sampling
+	if sampling == nil && zapConfig.Sampling != nil {
+		sampling = &DefaultSampling
+	}
+	zapConfig.Sampling = nil
+
+	format := config.Format
+	if env := os.Getenv(FormatEnv); env != "" {
+		format = env
+	}
+
+	var file *RotatingWriter
+	var options []zap.Option
+	var formatErr error
+	switch format {
+	case "":
+	case FormatJSON:
+		zapConfig.Encoding = "json"
+		zapConfig.EncoderConfig = zap.NewProductionEncoderConfig()
+		zapConfig.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		zapConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	case FormatPretty:
+		core := zapcore.NewCore(newPrettyEncoder(prettyColor(config.NoColor)), zapcore.Lock(os.Stderr), zapConfig.Level)
+		options = append(options, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return core
+		}))
+	default:
+		formatErr = fmt.Errorf("unknown log format %q", format)
+	}
+
+	if config.File != "" {
+		file = NewRotatingWriter(config.File, config.Rotation)
+
+		// Files get plain JSON with the usual keys, as colors and the
+		// development layout only help on a terminal
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(file), zapConfig.Level)
+		options = append(options, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return core
+		}))
+	}
+
+	// A sink that cannot be opened is reported once the logger is built,
+	// rather than failing the command that wanted to log
+	var sinks []io.Closer
+	var sinkCores []zapcore.Core
+	var sinkErrs []error
+	for _, sinkConfig := range config.Sinks {
+		core, closer, err := newSink(sinkConfig)
+		if err != nil {
+			sinkErrs = append(sinkErrs, err)
+			continue
+		}
+		sinkCores = append(sinkCores, core)
+		sinks = append(sinks, closer)
+	}
+
+	options = append(options, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		core = zapcore.NewTee(append([]zapcore.Core{core}, sinkCores...)...)
+		if sampling != nil {
+			core = sampling.core(core)
+		}
+		return &moduleCore{Core: core, levels: levels}
+	}))
+
 	// Build the logger
-	zapLogger, err := zapConfig.Build()
+	zapLogger, err := zapConfig.Build(options...)
 	if err != nil {
 		// Fallback to a basic logger if config fails
 		zapLogger, _ = zap.NewProduction()
+		return &Logger{Logger: zapLogger, file: file, sinks: sinks}
 	}
 
-	return &Logger{Logger: zapLogger}
+	if levelsErr != nil {
+		zapLogger.Warn("Ignoring "+LevelEnv, zap.Error(levelsErr))
+	}
+	if formatErr != nil {
+		zapLogger.Warn("Ignoring log format", zap.Error(formatErr))
+	}
+	for _, err := range sinkErrs {
+		zapLogger.Warn("Failed to open log sink", zap.Error(err))
+	}
+	return &Logger{Logger: zapLogger, file: file, sinks: sinks, levels: levels}
 }
 
 // Sync flushes any buffered log entries
@@ -71,6 +180,30 @@ func (l *Logger) Sync() {
 	}
 }
 
+// Close flushes buffered entries and closes the log file and sinks, if
+// any
+func (l *Logger) Close() error {
+	l.Sync()
+
+	var errs []error
+	if l.file != nil {
+		errs = append(errs, l.file.Close())
+	}
+	for _, sink := range l.sinks {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Rotate starts a new log file, such as on SIGHUP. It does nothing when
+// not logging to a file
+func (l *Logger) Rotate() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Rotate()
+}
+
 // Global logger instance for backward compatibility
 var global *Logger
 
@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/edsonmichaque/tykctl-go/terminal"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// FormatEnv names the environment variable choosing the log format, which
+// overrides Config.Format
+const FormatEnv = "TYKCTL_LOG_FORMAT"
+
+// Log formats for Config.Format and FormatEnv
+const (
+	// FormatPretty is a colored, aligned format for people reading logs
+	// in a terminal
+	FormatPretty = "pretty"
+	// FormatJSON is one JSON object per line, for machines
+	FormatJSON = "json"
+)
+
+// prettyTimeFormat keeps timestamps short, as pretty logs are read as they
+// happen
+const prettyTimeFormat = "15:04:05.000"
+
+var prettyPool = buffer.NewPool()
+
+// prettyEncoder writes entries for people:
+//
+//	12:04:05.123 INFO  extension.installer  Installed  name=foo version=1.2  install.go:42
+//
+// Values spanning lines, such as verbose errors and stack traces, are
+// written below the entry, indented
+type prettyEncoder struct {
+	*zapcore.MapObjectEncoder
+	color bool
+}
+
+// newPrettyEncoder returns a pretty encoder, coloring levels when color is
+// set
+func newPrettyEncoder(color bool) zapcore.Encoder {
+	return &prettyEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder(), color: color}
+}
+
+// prettyColor reports whether pretty logs on stderr can be colored
+func prettyColor(noColor bool) bool {
+	return !noColor && terminal.ColorLevelOf(os.Stderr) != terminal.ColorNone
+}
+
+// Clone copies the encoder with its fields
+func (e *prettyEncoder) Clone() zapcore.Encoder {
+	clone := &prettyEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder(), color: e.color}
+	for key, value := range e.Fields {
+		clone.Fields[key] = value
+	}
+	return clone
+}
+
+// EncodeEntry formats an entry with the encoder's fields and its own
+func (e *prettyEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	values := zapcore.NewMapObjectEncoder()
+	for key, value := range e.Fields {
+		values.Fields[key] = value
+	}
+	for _, field := range fields {
+		field.AddTo(values)
+	}
+
+	buf := prettyPool.Get()
+	buf.AppendString(e.paint(terminal.ColorGray, entry.Time.Format(prettyTimeFormat)))
+	buf.AppendByte(' ')
+	buf.AppendString(e.paint(levelColor(entry.Level), fmt.Sprintf("%-5s", entry.Level.CapitalString())))
+	if entry.LoggerName != "" {
+		buf.AppendByte(' ')
+		buf.AppendString(e.paint(terminal.ColorPurple, entry.LoggerName))
+	}
+	buf.AppendByte(' ')
+	buf.AppendString(entry.Message)
+
+	// Single-line values follow the message; the rest go below it
+	var blocks []string
+	first := true
+	for _, key := range sortedKeys(values.Fields) {
+		text := prettyValue(values.Fields[key])
+		if strings.Contains(text, "\n") {
+			blocks = append(blocks, e.paint(terminal.ColorGray, key+":")+"\n"+indent(text))
+			continue
+		}
+		if first {
+			buf.AppendByte(' ')
+			first = false
+		}
+		buf.AppendByte(' ')
+		buf.AppendString(e.paint(terminal.ColorCyan, key+"="))
+		buf.AppendString(text)
+	}
+
+	if entry.Caller.Defined {
+		buf.AppendString("  ")
+		buf.AppendString(e.paint(terminal.ColorGray, shortCaller(entry.Caller)))
+	}
+	buf.AppendByte('\n')
+
+	for _, block := range blocks {
+		buf.AppendString(block)
+		buf.AppendByte('\n')
+	}
+	if entry.Stack != "" {
+		buf.AppendString(indent(entry.Stack))
+		buf.AppendByte('\n')
+	}
+	return buf, nil
+}
+
+// paint colors text when color is enabled
+func (e *prettyEncoder) paint(color, text string) string {
+	if !e.color {
+		return text
+	}
+	return color + text + terminal.ColorReset
+}
+
+// levelColor picks the color of a level
+func levelColor(level zapcore.Level) string {
+	switch level {
+	case zapcore.DebugLevel:
+		return terminal.ColorGray
+	case zapcore.InfoLevel:
+		return terminal.ColorBlue
+	case zapcore.WarnLevel:
+		return terminal.ColorYellow
+	default:
+		return terminal.ColorRed
+	}
+}
+
+// prettyValue formats a field value, quoting strings with spaces so
+// key=value pairs stay readable
+func prettyValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if strings.ContainsAny(v, " \t\"=") && !strings.Contains(v, "\n") {
+			return fmt.Sprintf("%q", v)
+		}
+		return v
+	case fmt.Stringer:
+		return v.String()
+	case map[string]interface{}, []interface{}:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// shortCaller keeps the file name and line of the caller, as the package
+// is usually clear from the logger name
+func shortCaller(caller zapcore.EntryCaller) string {
+	file := caller.File
+	if i := strings.LastIndexAny(file, `/\`); i >= 0 {
+		file = file[i+1:]
+	}
+	return fmt.Sprintf("%s:%d", file, caller.Line)
+}
+
+// indent prefixes every line of text
+func indent(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// verboseError formats with %+v over several lines, as errors with stack
+// traces do
+type verboseError struct{}
+
+func (verboseError) Error() string { return "install failed" }
+
+func (e verboseError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprint(s, "install failed\nmain.install\n\tinstall.go:42")
+		return
+	}
+	fmt.Fprint(s, e.Error())
+}
+
+func TestPrettyEncoder(t *testing.T) {
+	encoder := newPrettyEncoder(false)
+	encoder.AddString("name", "foo")
+
+	entry := zapcore.Entry{
+		Level:      zapcore.InfoLevel,
+		Time:       time.Date(2024, 1, 2, 12, 4, 5, 123e6, time.UTC),
+		LoggerName: "extension.installer",
+		Message:    "Installed",
+		Caller:     zapcore.NewEntryCaller(0, "/src/tykctl/extension/install.go", 42, true),
+	}
+	buf, err := encoder.EncodeEntry(entry, []zapcore.Field{
+		zap.String("version", "1.2"),
+		zap.String("path", "/tmp/my ext"),
+		zap.Int("size", 3),
+	})
+	if err != nil {
+		t.Fatalf("EncodeEntry failed: %v", err)
+	}
+
+	want := `12:04:05.123 INFO  extension.installer Installed  name=foo path="/tmp/my ext" size=3 version=1.2  install.go:42` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Expected\n%q\ngot\n%q", want, got)
+	}
+}
+
+func TestPrettyEncoder_Multiline(t *testing.T) {
+	encoder := newPrettyEncoder(false)
+	entry := zapcore.Entry{Level: zapcore.ErrorLevel, Message: "Failed", Stack: "goroutine 1\nmain.main()"}
+	buf, err := encoder.EncodeEntry(entry, []zapcore.Field{zap.Error(verboseError{})})
+	if err != nil {
+		t.Fatalf("EncodeEntry failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{
+		`00:00:00.000 ERROR Failed  error="install failed"`,
+		"errorVerbose:",
+		"    install failed",
+		"    main.install",
+		"    \tinstall.go:42",
+		"    goroutine 1",
+		"    main.main()",
+	}
+	// The zero time depends on the local zone, so only the rest is compared
+	if !strings.HasSuffix(lines[0], want[0][len("00:00:00.000"):]) {
+		t.Errorf("Expected first line ending %q, got %q", want[0][len("00:00:00.000"):], lines[0])
+	}
+	if strings.Join(lines[1:], "\n") != strings.Join(want[1:], "\n") {
+		t.Errorf("Expected blocks\n%s\ngot\n%s", strings.Join(want[1:], "\n"), strings.Join(lines[1:], "\n"))
+	}
+}
+
+func TestPrettyEncoder_Color(t *testing.T) {
+	encoder := newPrettyEncoder(true)
+	buf, err := encoder.EncodeEntry(zapcore.Entry{Level: zapcore.WarnLevel, Message: "Slow"}, nil)
+	if err != nil {
+		t.Fatalf("EncodeEntry failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\x1b[33mWARN \x1b[0m") {
+		t.Errorf("Expected a yellow level, got %q", buf.String())
+	}
+}
+
+func TestPrettyEncoder_Clone(t *testing.T) {
+	encoder := newPrettyEncoder(false)
+	encoder.AddString("a", "1")
+	clone := encoder.Clone()
+	clone.AddString("b", "2")
+
+	buf, _ := encoder.EncodeEntry(zapcore.Entry{Message: "m"}, nil)
+	if strings.Contains(buf.String(), "b=2") {
+		t.Errorf("Expected clone fields to stay on the clone, got %q", buf.String())
+	}
+	buf, _ = clone.EncodeEntry(zapcore.Entry{Message: "m"}, nil)
+	if !strings.Contains(buf.String(), "a=1 b=2") {
+		t.Errorf("Expected clone to keep fields, got %q", buf.String())
+	}
+}
+
+// captureStderr runs fn with os.Stderr going to a file and returns what
+// was written
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer f.Close()
+
+	stderr := os.Stderr
+	os.Stderr = f
+	defer func() { os.Stderr = stderr }()
+	fn()
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	return string(data)
+}
+
+func TestNew_Format(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		env    string
+		want   string
+	}{
+		{name: "pretty", config: Config{Verbose: true, NoColor: true}, env: FormatPretty, want: "INFO  Installed  name=foo"},
+		{name: "json", config: Config{Verbose: true, Format: FormatJSON}, want: `"msg":"Installed","name":"foo"`},
+		{name: "env overrides", config: Config{Verbose: true, Format: FormatJSON, NoColor: true}, env: FormatPretty, want: "INFO  Installed  name=foo"},
+		{name: "unknown", env: "yaml", want: "Ignoring log format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(FormatEnv, tt.env)
+			got := captureStderr(t, func() {
+				log := New(tt.config)
+				log.Info("Installed", zap.String("name", "foo"))
+				log.Sync()
+			})
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Expected output containing %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxSize is the size a log file reaches before it is rotated,
+// unless Rotation.MaxSize is set
+const DefaultMaxSize = 100 << 20
+
+// backupTimeFormat stamps rotated files, such as
+// daemon-2024-01-02T15-04-05.000.log
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Rotation configures how a log file is rotated and how many old files are
+// kept. Zero values keep every backup forever
+type Rotation struct {
+	// MaxSize is the size in bytes a file reaches before it is rotated;
+	// zero means DefaultMaxSize
+	MaxSize int64
+	// MaxBackups is how many rotated files to keep; zero keeps them all
+	MaxBackups int
+	// MaxAge is how long to keep rotated files; zero keeps them regardless
+	// of age
+	MaxAge time.Duration
+	// Compress gzips rotated files
+	Compress bool
+}
+
+// RotatingWriter is an io.WriteCloser writing to a file that is rotated
+// once it reaches a maximum size, removing and compressing old files in
+// the background. It is safe for concurrent use
+type RotatingWriter struct {
+	filename string
+	rotation Rotation
+	now      func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	millMu sync.Mutex
+	wg     sync.WaitGroup
+}
+
+// NewRotatingWriter returns a writer for filename. The file and its
+// directory are created on the first write
+func NewRotatingWriter(filename string, rotation Rotation) *RotatingWriter {
+	if rotation.MaxSize <= 0 {
+		rotation.MaxSize = DefaultMaxSize
+	}
+	return &RotatingWriter{filename: filename, rotation: rotation, now: time.Now}
+}
+
+// Write appends p to the file, rotating it first when p would take it
+// past the maximum size
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if int64(len(p)) > w.rotation.MaxSize {
+		return 0, fmt.Errorf("log entry of %d bytes exceeds the maximum file size of %d bytes", len(p), w.rotation.MaxSize)
+	}
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.size+int64(len(p)) > w.rotation.MaxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate closes the current file, renames it to a timestamped backup and
+// starts a new one, such as on SIGHUP
+func (w *RotatingWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotate()
+}
+
+// Sync flushes the current file to disk
+func (w *RotatingWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Close closes the current file and waits for background cleanup to
+// finish
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	return err
+}
+
+// open appends to an existing file, or creates a new one
+func (w *RotatingWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.filename), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(w.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file aside and opens a new one
+func (w *RotatingWriter) rotate() error {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return fmt.Errorf("failed to close log file: %w", err)
+		}
+		w.file = nil
+	}
+
+	now := w.now()
+	if _, err := os.Stat(w.filename); err == nil {
+		if err := os.Rename(w.filename, w.backupName(now)); err != nil {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.mill(now)
+	}()
+	return nil
+}
+
+// backupName returns the name of a backup rotated at t
+func (w *RotatingWriter) backupName(t time.Time) string {
+	prefix, ext := w.nameParts()
+	return prefix + t.UTC().Format(backupTimeFormat) + ext
+}
+
+// nameParts splits the file name into the part before a backup timestamp,
+// including its directory, and the extension after it
+func (w *RotatingWriter) nameParts() (string, string) {
+	ext := filepath.Ext(w.filename)
+	return strings.TrimSuffix(w.filename, ext) + "-", ext
+}
+
+// logBackup is a rotated file and when it was rotated
+type logBackup struct {
+	path    string
+	rotated time.Time
+}
+
+// backups lists rotated files, newest first
+func (w *RotatingWriter) backups() ([]logBackup, error) {
+	prefix, ext := w.nameParts()
+	entries, err := os.ReadDir(filepath.Dir(w.filename))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []logBackup
+	base := filepath.Base(prefix)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, base) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, base), ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		rotated, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, logBackup{path: filepath.Join(filepath.Dir(w.filename), name), rotated: rotated})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].rotated.After(backups[j].rotated) })
+	return backups, nil
+}
+
+// mill removes backups beyond MaxBackups or older than MaxAge at now and
+// compresses the rest when asked. Errors are ignored, as there is nowhere
+// to log them and the next rotation tries again
+func (w *RotatingWriter) mill(now time.Time) {
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	backups, err := w.backups()
+	if err != nil {
+		return
+	}
+
+	cutoff := now.Add(-w.rotation.MaxAge)
+	for i, backup := range backups {
+		if (w.rotation.MaxBackups > 0 && i >= w.rotation.MaxBackups) ||
+			(w.rotation.MaxAge > 0 && backup.rotated.Before(cutoff)) {
+			os.Remove(backup.path)
+			continue
+		}
+		if w.rotation.Compress && !strings.HasSuffix(backup.path, ".gz") {
+			compressFile(backup.path)
+		}
+	}
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	in.Close()
+	return os.Remove(path)
+}
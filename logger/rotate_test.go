@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clock returns a time source advancing a second per call
+func clock(start time.Time) func() time.Time {
+	now := start
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingWriter_Size(t *testing.T) {
+	dir := t.TempDir()
+	w := NewRotatingWriter(filepath.Join(dir, "logs", "daemon.log"), Rotation{MaxSize: 10})
+	w.now = clock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	for _, line := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	names := listDir(t, filepath.Join(dir, "logs"))
+	want := []string{
+		"daemon-2024-01-02T03-04-06.000.log",
+		"daemon-2024-01-02T03-04-07.000.log",
+		"daemon.log",
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected %v, got %v", want, names)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "logs", "daemon.log"))
+	if string(data) != "cccccc\n" {
+		t.Errorf("Expected newest entry in the current file, got %q", data)
+	}
+
+	if _, err := w.Write([]byte(strings.Repeat("x", 11))); err == nil {
+		t.Error("Expected an entry larger than MaxSize to fail")
+	}
+}
+
+func TestRotatingWriter_Retention(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "daemon.log")
+	w := NewRotatingWriter(path, Rotation{MaxSize: 1 << 10, MaxBackups: 2, Compress: true})
+	w.now = clock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	for i := 0; i < 4; i++ {
+		w.Write([]byte("entry\n"))
+		if err := w.Rotate(); err != nil {
+			t.Fatalf("Rotate failed: %v", err)
+		}
+	}
+	w.Close()
+
+	names := listDir(t, dir)
+	want := []string{
+		"daemon-2024-01-02T03-04-08.000.log.gz",
+		"daemon-2024-01-02T03-04-09.000.log.gz",
+		"daemon.log",
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected %v, got %v", want, names)
+	}
+
+	file, _ := os.Open(filepath.Join(dir, want[1]))
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Expected a gzip backup: %v", err)
+	}
+	if data, _ := io.ReadAll(gz); string(data) != "entry\n" {
+		t.Errorf("Expected backup contents, got %q", data)
+	}
+}
+
+func TestRotatingWriter_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "daemon.log")
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	os.WriteFile(filepath.Join(dir, "daemon-"+old.Format(backupTimeFormat)+".log"), []byte("old\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "unrelated.log"), []byte("keep\n"), 0644)
+
+	w := NewRotatingWriter(path, Rotation{MaxAge: 24 * time.Hour})
+	w.now = clock(old.Add(48 * time.Hour))
+	w.Write([]byte("entry\n"))
+	w.Rotate()
+	w.Close()
+
+	names := listDir(t, dir)
+	want := []string{"daemon-2024-01-03T00-00-01.000.log", "daemon.log", "unrelated.log"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+}
+
+func TestNewWithFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tykctl.log")
+	log := New(Config{Verbose: true, File: path})
+	log.Info("started")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected log file: %v", err)
+	}
+	if !strings.Contains(string(data), `"msg":"started"`) || !strings.Contains(string(data), `"level":"INFO"`) {
+		t.Errorf("Expected JSON entry, got %q", data)
+	}
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldSuppressed counts the entries RateLimit dropped since the last one
+// it let through
+const FieldSuppressed = "suppressed"
+
+// Sampling caps repeated entries: in each Tick, the first Initial entries
+// with the same level and message are logged, then every Thereafter-th
+type Sampling struct {
+	Initial    int
+	Thereafter int
+	// Tick defaults to a second
+	Tick time.Duration
+}
+
+// DefaultSampling is used in production mode when Config.Sampling is not
+// set, as zap does
+var DefaultSampling = Sampling{Initial: 100, Thereafter: 100, Tick: time.Second}
+
+// core wraps c in a sampler
+func (s Sampling) core(c zapcore.Core) zapcore.Core {
+	tick := s.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return zapcore.NewSamplerWithOptions(c, tick, s.Initial, s.Thereafter)
+}
+
+// RateLimit returns a logger writing entries at level or below at most once
+// per interval for each message, such as for watchers and event handlers
+// that log in hot loops. The next entry written carries the number dropped
+// in FieldSuppressed
+func (l *Logger) RateLimit(level zapcore.Level, per time.Duration) *Logger {
+	limiter := &rateLimiter{level: level, per: per, last: map[rateKey]time.Time{}, dropped: map[rateKey]int{}}
+	limited := l.Logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		// The limit goes below the module filter, so only entries the
+		// module logs are counted and Named keeps working
+		if mc, ok := core.(*moduleCore); ok {
+			return &moduleCore{Core: &rateCore{Core: mc.Core, limiter: limiter}, module: mc.module, levels: mc.levels}
+		}
+		return &rateCore{Core: core, limiter: limiter}
+	}))
+	return &Logger{Logger: limited, file: l.file, sinks: l.sinks, levels: l.levels, module: l.module}
+}
+
+// rateKey identifies entries limited together
+type rateKey struct {
+	level   zapcore.Level
+	name    string
+	message string
+}
+
+// rateLimiter holds the state shared by a rate-limited logger and the
+// loggers derived from it
+type rateLimiter struct {
+	level zapcore.Level
+	per   time.Duration
+
+	mu      sync.Mutex
+	last    map[rateKey]time.Time
+	dropped map[rateKey]int
+}
+
+// allow reports whether entry may be written, and how many like it were
+// dropped before it
+func (r *rateLimiter) allow(entry zapcore.Entry) (bool, int) {
+	if entry.Level > r.level {
+		return true, 0
+	}
+
+	key := rateKey{level: entry.Level, name: entry.LoggerName, message: entry.Message}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if last, ok := r.last[key]; ok && entry.Time.Sub(last) < r.per {
+		r.dropped[key]++
+		return false, 0
+	}
+	r.last[key] = entry.Time
+	dropped := r.dropped[key]
+	delete(r.dropped, key)
+	return true, dropped
+}
+
+// rateCore drops entries its limiter does not allow
+type rateCore struct {
+	zapcore.Core
+	limiter *rateLimiter
+}
+
+// With adds fields, keeping the limiter
+func (c *rateCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+// Check passes allowed entries to the wrapped core
+func (c *rateCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	ok, dropped := c.limiter.allow(entry)
+	if !ok {
+		return ce
+	}
+	if dropped > 0 {
+		return c.Core.With([]zapcore.Field{zap.Int(FieldSuppressed, dropped)}).Check(entry, ce)
+	}
+	return c.Core.Check(entry, ce)
+}
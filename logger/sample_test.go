@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestConfig_Sampling(t *testing.T) {
+	out := captureStderr(t, func() {
+		log := New(Config{Verbose: true, Format: FormatJSON, Sampling: &Sampling{Initial: 2, Thereafter: 3, Tick: time.Minute}})
+		for i := 0; i < 8; i++ {
+			log.Info("hot", zap.Int("i", i))
+		}
+		log.Info("cold")
+		log.Sync()
+	})
+
+	// Entries 1 and 2, then every third: 5 and 8
+	if got := strings.Count(out, `"msg":"hot"`); got != 4 {
+		t.Errorf("Expected 4 sampled entries, got %d in %q", got, out)
+	}
+	if !strings.Contains(out, `"msg":"cold"`) {
+		t.Errorf("Expected other messages to be sampled apart, got %q", out)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	log, logs := observed(zapcore.DebugLevel)
+	limited := log.RateLimit(zapcore.InfoLevel, time.Hour).Named("watcher")
+	for i := 0; i < 5; i++ {
+		limited.Info("changed")
+		limited.Warn("slow")
+	}
+	limited.Debug("hidden")
+	log.Info("changed")
+
+	var infos, warns int
+	for _, entry := range logs.All() {
+		switch {
+		case entry.Message == "changed" && entry.LoggerName == "watcher":
+			infos++
+		case entry.Message == "slow":
+			warns++
+		}
+	}
+	if infos != 1 {
+		t.Errorf("Expected one rate-limited entry, got %d", infos)
+	}
+	if warns != 5 {
+		t.Errorf("Expected levels above the limit to pass, got %d", warns)
+	}
+	if got := logs.FilterMessage("hidden").Len(); got != 1 {
+		t.Errorf("Expected other messages to be limited apart, got %d", got)
+	}
+	if got := logs.FilterMessage("changed").Len(); got != 2 {
+		t.Errorf("Expected the parent logger not to be limited, got %d", got)
+	}
+}
+
+func TestRateLimit_Suppressed(t *testing.T) {
+	log, logs := observed(zapcore.InfoLevel)
+	limiter := &rateLimiter{level: zapcore.InfoLevel, per: time.Second, last: map[rateKey]time.Time{}, dropped: map[rateKey]int{}}
+	core := &moduleCore{Core: &rateCore{Core: log.Core().(*moduleCore).Core, limiter: limiter}, levels: log.levels}
+
+	start := time.Now()
+	for _, offset := range []time.Duration{0, 100 * time.Millisecond, 500 * time.Millisecond, 1200 * time.Millisecond} {
+		entry := zapcore.Entry{Level: zapcore.InfoLevel, Message: "tick", Time: start.Add(offset)}
+		if ce := core.Check(entry, nil); ce != nil {
+			ce.Write()
+		}
+	}
+	// Filtered by the module level before the limit counts it
+	core.Check(zapcore.Entry{Level: zapcore.DebugLevel, Message: "tick", Time: start.Add(1300 * time.Millisecond)}, nil)
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if _, ok := entries[0].ContextMap()[FieldSuppressed]; ok {
+		t.Error("Expected no suppressed count on the first entry")
+	}
+	if got := entries[1].ContextMap()[FieldSuppressed]; got != int64(2) {
+		t.Errorf("Expected 2 suppressed, got %v", got)
+	}
+}
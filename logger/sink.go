@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Sink types for SinkConfig
+const (
+	SinkSyslog   = "syslog"
+	SinkJournald = "journald"
+	SinkOTLP     = "otlp"
+)
+
+// DefaultSinkTag identifies tykctl in syslog, journald and OTLP
+const DefaultSinkTag = "tykctl"
+
+// SinkConfig adds a destination logs are sent to as well as the main
+// output, so deployments can centralize CLI logs
+type SinkConfig struct {
+	// Type is SinkSyslog, SinkJournald or SinkOTLP
+	Type string
+	// Address is where to send logs. For syslog it is a URL such as
+	// udp://logs.example.com:514, or empty for the local daemon. For OTLP
+	// it is the collector's HTTP endpoint, such as http://localhost:4318.
+	// journald always uses the local journal
+	Address string
+	// Tag identifies the program: the syslog tag, the journald
+	// SYSLOG_IDENTIFIER or the OTLP service.name. It defaults to
+	// DefaultSinkTag
+	Tag string
+	// Headers are sent with every OTLP export, such as for authentication
+	Headers map[string]string
+}
+
+// newSink opens the sink described by config
+func newSink(config SinkConfig) (zapcore.Core, io.Closer, error) {
+	if config.Tag == "" {
+		config.Tag = DefaultSinkTag
+	}
+
+	switch config.Type {
+	case SinkSyslog:
+		return newSyslogSink(config)
+	case SinkJournald:
+		return newJournaldSink(config)
+	case SinkOTLP:
+		return newOTLPSink(config)
+	default:
+		return nil, nil, fmt.Errorf("unknown log sink type %q", config.Type)
+	}
+}
+
+// sinkCore adapts a function writing one entry at a time to a
+// zapcore.Core. It passes every level, leaving filtering to the module
+// levels
+type sinkCore struct {
+	fields []zapcore.Field
+	write  func(entry zapcore.Entry, fields []zapcore.Field) error
+	sync   func() error
+}
+
+// Enabled reports true for every level
+func (c *sinkCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With returns a core adding fields to every entry
+func (c *sinkCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field{}, c.fields...), fields...)
+	return &clone
+}
+
+// Check adds the core to ce
+func (c *sinkCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(entry, c)
+}
+
+// Write sends the entry with the core's fields and its own
+func (c *sinkCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := fields
+	if len(c.fields) > 0 {
+		all = append(append([]zapcore.Field{}, c.fields...), fields...)
+	}
+	return c.write(entry, all)
+}
+
+// Sync flushes buffered entries
+func (c *sinkCore) Sync() error {
+	if c.sync == nil {
+		return nil
+	}
+	return c.sync()
+}
+
+// fieldMap encodes fields into a map of plain values
+func fieldMap(fields []zapcore.Field) map[string]interface{} {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return encoder.Fields
+}
+
+// sinkMessage formats an entry as one line for sinks that record the time
+// and level themselves: the logger name, the message and the fields as
+// JSON
+func sinkMessage(entry zapcore.Entry, fields []zapcore.Field) string {
+	var b strings.Builder
+	if entry.LoggerName != "" {
+		b.WriteString(entry.LoggerName)
+		b.WriteString(": ")
+	}
+	b.WriteString(entry.Message)
+
+	if values := fieldMap(fields); len(values) > 0 {
+		if data, err := json.Marshal(values); err == nil {
+			b.WriteString(" ")
+			b.Write(data)
+		}
+	}
+	return b.String()
+}
+
+// sortedKeys returns the keys of values in order, for stable output
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
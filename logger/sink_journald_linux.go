@@ -0,0 +1,109 @@
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// journalSocket is where journald receives entries in its native protocol
+const journalSocket = "/run/systemd/journal/socket"
+
+// newJournaldSink sends entries to the local journal, with fields as
+// journal fields so they can be filtered with journalctl
+func newJournaldSink(config SinkConfig) (zapcore.Core, io.Closer, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to journald: %w", err)
+	}
+
+	core := &sinkCore{
+		write: func(entry zapcore.Entry, fields []zapcore.Field) error {
+			_, err := conn.Write(journalMessage(config.Tag, entry, fields))
+			return err
+		},
+	}
+	return core, conn, nil
+}
+
+// journalMessage encodes an entry in the journald native protocol
+func journalMessage(tag string, entry zapcore.Entry, fields []zapcore.Field) []byte {
+	var b bytes.Buffer
+	journalField(&b, "MESSAGE", entry.Message)
+	journalField(&b, "PRIORITY", strconv.Itoa(journalPriority(entry.Level)))
+	journalField(&b, "SYSLOG_IDENTIFIER", tag)
+	if entry.LoggerName != "" {
+		journalField(&b, "LOGGER", entry.LoggerName)
+	}
+
+	values := fieldMap(fields)
+	for _, key := range sortedKeys(values) {
+		name := journalFieldName(key)
+		if name == "" {
+			continue
+		}
+		journalField(&b, name, fmt.Sprint(values[key]))
+	}
+	return b.Bytes()
+}
+
+// journalField writes one field, using the length-prefixed form for
+// values spanning lines
+func journalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// journalFieldName turns a zap key into a journal field name, which may
+// only hold upper-case letters, digits and underscores and must not start
+// with an underscore or a digit
+func journalFieldName(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(key) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	name := strings.TrimLeft(b.String(), "_0123456789")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// journalPriority maps a level to a syslog priority
+func journalPriority(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	default:
+		return 2
+	}
+}
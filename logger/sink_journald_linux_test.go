@@ -0,0 +1,27 @@
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestJournalMessage(t *testing.T) {
+	entry := zapcore.Entry{Level: zapcore.WarnLevel, LoggerName: "api", Message: "slow\nresponse"}
+	got := journalMessage("tykctl", entry, []zapcore.Field{zap.String("request-id", "abc"), zap.Int("_1ms", 3)})
+
+	var want bytes.Buffer
+	want.WriteString("MESSAGE\n")
+	binary.Write(&want, binary.LittleEndian, uint64(len("slow\nresponse")))
+	want.WriteString("slow\nresponse\n")
+	want.WriteString("PRIORITY=4\nSYSLOG_IDENTIFIER=tykctl\nLOGGER=api\nMS=3\nREQUEST_ID=abc\n")
+
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("Expected %q, got %q", want.String(), got)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package logger
+
+import (
+	"fmt"
+	"io"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newJournaldSink fails, as journald only runs on Linux
+func newJournaldSink(config SinkConfig) (zapcore.Core, io.Closer, error) {
+	return nil, nil, fmt.Errorf("journald is only supported on Linux")
+}
@@ -0,0 +1,262 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// otlpBatchSize is how many records are buffered before an export
+	otlpBatchSize = 512
+	// otlpFlushInterval is how often buffered records are exported
+	otlpFlushInterval = 5 * time.Second
+	// otlpTimeout bounds each export
+	otlpTimeout = 10 * time.Second
+)
+
+// otlpExporter batches records and sends them to an OTLP collector over
+// HTTP with the JSON encoding
+type otlpExporter struct {
+	endpoint string
+	headers  map[string]string
+	resource []otlpAttribute
+	client   *http.Client
+
+	mu      sync.Mutex
+	records []otlpRecord
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// newOTLPSink exports entries to the collector at config.Address
+func newOTLPSink(config SinkConfig) (zapcore.Core, io.Closer, error) {
+	if config.Address == "" {
+		return nil, nil, fmt.Errorf("OTLP sink needs the collector address")
+	}
+
+	endpoint := strings.TrimSuffix(config.Address, "/")
+	if !strings.HasSuffix(endpoint, "/v1/logs") {
+		endpoint += "/v1/logs"
+	}
+
+	resource := []otlpAttribute{otlpAttr("service.name", config.Tag)}
+	if host, err := os.Hostname(); err == nil {
+		resource = append(resource, otlpAttr("host.name", host))
+	}
+
+	exporter := &otlpExporter{
+		endpoint: endpoint,
+		headers:  config.Headers,
+		resource: resource,
+		client:   &http.Client{Timeout: otlpTimeout},
+		done:     make(chan struct{}),
+	}
+	exporter.wg.Add(1)
+	go exporter.loop()
+
+	core := &sinkCore{write: exporter.write, sync: exporter.Flush}
+	return core, exporter, nil
+}
+
+// write buffers a record, exporting when the batch is full
+func (e *otlpExporter) write(entry zapcore.Entry, fields []zapcore.Field) error {
+	record := otlpRecord{
+		TimeUnixNano:   strconv.FormatInt(entry.Time.UnixNano(), 10),
+		SeverityNumber: otlpSeverity(entry.Level),
+		SeverityText:   entry.Level.CapitalString(),
+		Body:           otlpValue{StringValue: &entry.Message},
+	}
+	if entry.LoggerName != "" {
+		record.Attributes = append(record.Attributes, otlpAttr("logger", entry.LoggerName))
+	}
+	values := fieldMap(fields)
+	for _, key := range sortedKeys(values) {
+		record.Attributes = append(record.Attributes, otlpAttribute{Key: key, Value: otlpAnyValue(values[key])})
+	}
+
+	e.mu.Lock()
+	e.records = append(e.records, record)
+	full := len(e.records) >= otlpBatchSize
+	e.mu.Unlock()
+
+	if full {
+		return e.Flush()
+	}
+	return nil
+}
+
+// Flush exports the buffered records
+func (e *otlpExporter) Flush() error {
+	e.mu.Lock()
+	records := e.records
+	e.records = nil
+	e.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+	return e.export(records)
+}
+
+// Close stops the background exports and flushes what is left
+func (e *otlpExporter) Close() error {
+	e.once.Do(func() { close(e.done) })
+	e.wg.Wait()
+	return e.Flush()
+}
+
+// loop flushes on an interval until closed
+func (e *otlpExporter) loop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+			e.Flush()
+		}
+	}
+}
+
+// export posts records to the collector
+func (e *otlpExporter) export(records []otlpRecord) error {
+	body, err := json.Marshal(otlpRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource: otlpResource{Attributes: e.resource},
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      otlpScope{Name: DefaultSinkTag},
+			LogRecords: records,
+		}},
+	}}})
+	if err != nil {
+		return fmt.Errorf("failed to encode OTLP logs: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), otlpTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export OTLP logs: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to export OTLP logs: collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// otlpSeverity maps a level to an OTLP severity number
+func otlpSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 5
+	case zapcore.InfoLevel:
+		return 9
+	case zapcore.WarnLevel:
+		return 13
+	case zapcore.ErrorLevel, zapcore.DPanicLevel:
+		return 17
+	default:
+		return 21
+	}
+}
+
+// otlpAnyValue converts an encoded field value to an OTLP value
+func otlpAnyValue(value interface{}) otlpValue {
+	switch v := value.(type) {
+	case string:
+		return otlpValue{StringValue: &v}
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s := fmt.Sprint(v)
+		return otlpValue{IntValue: &s}
+	case float32:
+		f := float64(v)
+		return otlpValue{DoubleValue: &f}
+	case float64:
+		return otlpValue{DoubleValue: &v}
+	default:
+		s := fmt.Sprint(v)
+		if data, err := json.Marshal(v); err == nil {
+			s = string(data)
+		}
+		return otlpValue{StringValue: &s}
+	}
+}
+
+// otlpAttr returns a string attribute
+func otlpAttr(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+// The types below follow the OTLP JSON encoding of ExportLogsServiceRequest
+
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope    `json:"scope"`
+	LogRecords []otlpRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano   string          `json:"timeUnixNano"`
+	SeverityNumber int             `json:"severityNumber"`
+	SeverityText   string          `json:"severityText"`
+	Body           otlpValue       `json:"body"`
+	Attributes     []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
@@ -0,0 +1,52 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"net/url"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newSyslogSink connects to the syslog daemon at config.Address, or the
+// local one when it is empty
+func newSyslogSink(config SinkConfig) (zapcore.Core, io.Closer, error) {
+	network, address := "", ""
+	if config.Address != "" {
+		u, err := url.Parse(config.Address)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid syslog address %q: %w", config.Address, err)
+		}
+		network, address = u.Scheme, u.Host
+		if network == "unix" || network == "unixgram" {
+			address = u.Path
+		}
+	}
+
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_USER, config.Tag)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+
+	core := &sinkCore{
+		write: func(entry zapcore.Entry, fields []zapcore.Field) error {
+			message := sinkMessage(entry, fields)
+			switch entry.Level {
+			case zapcore.DebugLevel:
+				return writer.Debug(message)
+			case zapcore.InfoLevel:
+				return writer.Info(message)
+			case zapcore.WarnLevel:
+				return writer.Warning(message)
+			case zapcore.ErrorLevel:
+				return writer.Err(message)
+			default:
+				return writer.Crit(message)
+			}
+		},
+	}
+	return core, writer, nil
+}
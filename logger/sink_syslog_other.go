@@ -0,0 +1,15 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"fmt"
+	"io"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newSyslogSink fails, as syslog is not available on this platform
+func newSyslogSink(config SinkConfig) (zapcore.Core, io.Closer, error) {
+	return nil, nil, fmt.Errorf("syslog is not supported on this platform")
+}
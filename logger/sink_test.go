@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestOTLPSink(t *testing.T) {
+	var mu sync.Mutex
+	var requests []otlpRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req otlpRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("Invalid OTLP body: %v", err)
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		auth = r.Header.Get("Authorization")
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	log := New(Config{
+		Verbose: true,
+		Sinks: []SinkConfig{{
+			Type:    SinkOTLP,
+			Address: server.URL,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		}},
+	})
+	log.Named("api").Info("request sent", zap.String("path", "/apis"), zap.Int("status", 200))
+	log.Debug("hidden")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 || auth != "Bearer token" {
+		t.Fatalf("Expected one authenticated export, got %d (%q)", len(requests), auth)
+	}
+	resource := requests[0].ResourceLogs[0]
+	if got := *resource.Resource.Attributes[0].Value.StringValue; got != DefaultSinkTag {
+		t.Errorf("Expected service.name %q, got %q", DefaultSinkTag, got)
+	}
+
+	records := resource.ScopeLogs[0].LogRecords
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if *record.Body.StringValue != "request sent" || record.SeverityNumber != 9 || record.SeverityText != "INFO" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	attributes := map[string]otlpValue{}
+	for _, attribute := range record.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	if *attributes["logger"].StringValue != "api" || *attributes["path"].StringValue != "/apis" || *attributes["status"].IntValue != "200" {
+		t.Errorf("Unexpected attributes %+v", record.Attributes)
+	}
+}
+
+func TestNewSink_Invalid(t *testing.T) {
+	if _, _, err := newSink(SinkConfig{Type: "carrier-pigeon"}); err == nil {
+		t.Error("Expected an unknown sink type to fail")
+	}
+	if _, _, err := newSink(SinkConfig{Type: SinkOTLP}); err == nil {
+		t.Error("Expected an OTLP sink without an address to fail")
+	}
+}
+
+func TestSinkMessage(t *testing.T) {
+	entry := zapcore.Entry{LoggerName: "extension", Message: "installed"}
+	got := sinkMessage(entry, []zapcore.Field{zap.String("name", "foo"), zap.Bool("cached", true)})
+	if got != `extension: installed {"cached":true,"name":"foo"}` {
+		t.Errorf("Unexpected message %q", got)
+	}
+}
@@ -0,0 +1,281 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/edsonmichaque/tykctl-go/config"
+)
+
+const (
+	// DefaultCrashStderrLimit is the default number of trailing stderr bytes kept in a crash record
+	DefaultCrashStderrLimit = 64 * 1024
+	// DefaultMaxCrashes is the default number of crash records kept per extension
+	DefaultMaxCrashes = 50
+)
+
+// CrashDumpOptions configures crash dump collection for failed plugin runs
+type CrashDumpOptions struct {
+	Enabled     bool   // Collect crash records (also enabled by TYKCTL_PLUGIN_CRASH_DUMPS=true)
+	Dir         string // Directory for crash records, defaults to the extension state dir
+	StderrLimit int    // Trailing stderr bytes to keep, defaults to DefaultCrashStderrLimit
+	MaxCrashes  int    // Records to keep before pruning the oldest, defaults to DefaultMaxCrashes
+}
+
+// CrashRecord describes a plugin run that exited non-zero or was killed by a signal
+type CrashRecord struct {
+	ID              string            `json:"id"`
+	Plugin          string            `json:"plugin"`
+	Extension       string            `json:"extension"`
+	Path            string            `json:"path"`
+	Args            []string          `json:"args"`
+	ExitCode        int               `json:"exit_code"`
+	Signal          string            `json:"signal,omitempty"`
+	TimedOut        bool              `json:"timed_out,omitempty"`
+	StartedAt       time.Time         `json:"started_at"`
+	FinishedAt      time.Time         `json:"finished_at"`
+	Duration        string            `json:"duration"`
+	Stderr          string            `json:"stderr"`
+	StderrTruncated bool              `json:"stderr_truncated,omitempty"`
+	Environment     map[string]string `json:"environment"`
+	OS              string            `json:"os"`
+	Arch            string            `json:"arch"`
+}
+
+// CrashDir returns the directory crash records are stored in
+func (m *Manager) CrashDir() string {
+	if m.CrashDumps.Dir != "" {
+		return m.CrashDumps.Dir
+	}
+	return filepath.Join(xdg.StateHome, "tykctl", m.extension, "crashes")
+}
+
+// ListCrashes returns all crash records, newest first
+func (m *Manager) ListCrashes(ctx context.Context) ([]CrashRecord, error) {
+	entries, err := os.ReadDir(m.CrashDir())
+	if os.IsNotExist(err) {
+		return []CrashRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crash directory: %w", err)
+	}
+
+	crashes := make([]CrashRecord, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		record, err := m.readCrash(filepath.Join(m.CrashDir(), entry.Name()))
+		if err != nil {
+			// Skip partially written or foreign files
+			continue
+		}
+		crashes = append(crashes, *record)
+	}
+
+	sort.Slice(crashes, func(i, j int) bool {
+		return crashes[i].StartedAt.After(crashes[j].StartedAt)
+	})
+
+	return crashes, nil
+}
+
+// ShowCrash returns a single crash record by ID
+func (m *Manager) ShowCrash(ctx context.Context, id string) (*CrashRecord, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return nil, fmt.Errorf("invalid crash id: %s", id)
+	}
+
+	record, err := m.readCrash(filepath.Join(m.CrashDir(), id+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("crash %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// crashDumpsEnabled reports whether crash records should be collected
+func (m *Manager) crashDumpsEnabled() bool {
+	if m.CrashDumps.Enabled {
+		return true
+	}
+
+	extensionUpper := strings.ToUpper(m.extension)
+	for _, key := range []string{fmt.Sprintf("TYKCTL_%s_PLUGIN_CRASH_DUMPS", extensionUpper), "TYKCTL_PLUGIN_CRASH_DUMPS"} {
+		if value := os.Getenv(key); value != "" {
+			return value == "true" || value == "1"
+		}
+	}
+	return false
+}
+
+// stderrLimit returns the configured stderr tail size
+func (m *Manager) stderrLimit() int {
+	if m.CrashDumps.StderrLimit > 0 {
+		return m.CrashDumps.StderrLimit
+	}
+	return DefaultCrashStderrLimit
+}
+
+// recordCrash writes a crash record for a failed plugin run
+func (m *Manager) recordCrash(pluginPath string, args, pluginEnv []string, started time.Time, stderr *tailBuffer, runErr error, timedOut bool) (*CrashRecord, error) {
+	finished := time.Now()
+
+	pluginName := strings.TrimSuffix(filepath.Base(pluginPath), filepath.Ext(pluginPath))
+	pluginName = strings.TrimPrefix(pluginName, fmt.Sprintf("tykctl-%s-", m.extension))
+
+	record := &CrashRecord{
+		ID:          fmt.Sprintf("%s-%s", started.UTC().Format("20060102T150405.000000000"), pluginName),
+		Plugin:      pluginName,
+		Extension:   m.extension,
+		Path:        pluginPath,
+		Args:        args,
+		ExitCode:    -1,
+		TimedOut:    timedOut,
+		StartedAt:   started,
+		FinishedAt:  finished,
+		Duration:    finished.Sub(started).String(),
+		Environment: m.crashEnvironment(pluginEnv),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+	}
+
+	if stderr != nil {
+		record.Stderr, record.StderrTruncated = stderr.Tail()
+	}
+
+	if exitError, ok := runErr.(*exec.ExitError); ok {
+		record.ExitCode = exitError.ExitCode()
+		if status, ok := exitError.Sys().(interface {
+			Signaled() bool
+			Signal() syscall.Signal
+		}); ok && status.Signaled() {
+			record.Signal = status.Signal().String()
+		}
+	}
+
+	dir := m.CrashDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create crash directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal crash record: %w", err)
+	}
+
+	// Crash records may contain stderr output, so keep them private
+	if err := os.WriteFile(filepath.Join(dir, record.ID+".json"), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write crash record: %w", err)
+	}
+
+	m.pruneCrashes(dir)
+	return record, nil
+}
+
+// crashEnvironment summarizes the tykctl-related environment, redacting secrets
+func (m *Manager) crashEnvironment(pluginEnv []string) map[string]string {
+	summary := make(map[string]string)
+
+	for _, entry := range append(os.Environ(), pluginEnv...) {
+		key, value, found := strings.Cut(entry, "=")
+		if !found || !(strings.HasPrefix(key, "TYKCTL_") || strings.HasPrefix(key, "TYK_")) {
+			continue
+		}
+
+		summary[key] = config.RedactEnv(key, value)
+	}
+
+	return summary
+}
+
+// pruneCrashes removes the oldest crash records beyond the configured limit
+func (m *Manager) pruneCrashes(dir string) {
+	maxCrashes := m.CrashDumps.MaxCrashes
+	if maxCrashes <= 0 {
+		maxCrashes = DefaultMaxCrashes
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+			names = append(names, entry.Name())
+		}
+	}
+
+	// IDs start with a UTC timestamp, so lexical order is chronological
+	sort.Strings(names)
+	for len(names) > maxCrashes {
+		os.Remove(filepath.Join(dir, names[0]))
+		names = names[1:]
+	}
+}
+
+// readCrash loads a crash record from disk
+func (m *Manager) readCrash(path string) (*CrashRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var record CrashRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse crash record: %w", err)
+	}
+	return &record, nil
+}
+
+// tailBuffer keeps the last limit bytes written to it
+type tailBuffer struct {
+	limit     int
+	buf       []byte
+	truncated bool
+	mu        sync.Mutex
+}
+
+// newTailBuffer creates a buffer that retains at most limit bytes
+func newTailBuffer(limit int) *tailBuffer {
+	return &tailBuffer{limit: limit}
+}
+
+// Write appends p, discarding the oldest bytes beyond the limit
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - t.limit; over > 0 {
+		t.buf = append(t.buf[:0], t.buf[over:]...)
+		t.truncated = true
+	}
+	return len(p), nil
+}
+
+// Tail returns the retained output and whether earlier output was dropped
+func (t *tailBuffer) Tail() (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf), t.truncated
+}
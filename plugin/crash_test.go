@@ -0,0 +1,244 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCrashHelperProcess is run as a crashing plugin by TestRecordCrash
+func TestCrashHelperProcess(t *testing.T) {
+	switch os.Getenv("TYKCTL_TEST_CRASH_HELPER") {
+	case "exit":
+		fmt.Fprint(os.Stderr, "boom")
+		os.Exit(3)
+	case "hang":
+		fmt.Fprint(os.Stderr, "hanging")
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		writes        []string
+		wantTail      string
+		wantTruncated bool
+	}{
+		{"under limit", 8, []string{"abc", "def"}, "abcdef", false},
+		{"at limit", 6, []string{"abc", "def"}, "abcdef", false},
+		{"over limit across writes", 4, []string{"abc", "def"}, "cdef", true},
+		{"single write over limit", 3, []string{"abcdef"}, "def", true},
+		{"last KB", 1024, []string{strings.Repeat("a", 2048), strings.Repeat("b", 1024)}, strings.Repeat("b", 1024), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := newTailBuffer(tt.limit)
+			for _, w := range tt.writes {
+				if n, err := buf.Write([]byte(w)); err != nil || n != len(w) {
+					t.Fatalf("Write returned %d, %v", n, err)
+				}
+			}
+
+			tail, truncated := buf.Tail()
+			if tail != tt.wantTail {
+				t.Errorf("Expected tail %q, got %q", tt.wantTail, tail)
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("Expected truncated %v, got %v", tt.wantTruncated, truncated)
+			}
+		})
+	}
+}
+
+func TestCrashEnvironment(t *testing.T) {
+	t.Setenv("TYKCTL_HOME", "/opt/tykctl")
+	t.Setenv("TYKCTL_API_TOKEN", "s3cr3t")
+	t.Setenv("TYK_DB_PASSWORD", "s3cr3t")
+	t.Setenv("UNRELATED_VALUE", "ignored")
+
+	m := NewManager("demo", nil)
+	env := m.crashEnvironment([]string{"TYKCTL_DEMO_SECRET=s3cr3t", "TYKCTL_DEMO_MODE=debug", "MALFORMED"})
+
+	tests := []struct {
+		key   string
+		value string
+		found bool
+	}{
+		{"TYKCTL_HOME", "/opt/tykctl", true},
+		{"TYKCTL_API_TOKEN", "[REDACTED]", true},
+		{"TYK_DB_PASSWORD", "[REDACTED]", true},
+		{"TYKCTL_DEMO_SECRET", "[REDACTED]", true},
+		{"TYKCTL_DEMO_MODE", "debug", true},
+		{"UNRELATED_VALUE", "", false},
+		{"MALFORMED", "", false},
+	}
+
+	for _, tt := range tests {
+		value, found := env[tt.key]
+		if found != tt.found || value != tt.value {
+			t.Errorf("Expected %s = %q (present %v), got %q (present %v)", tt.key, tt.value, tt.found, value, found)
+		}
+	}
+}
+
+func TestPruneCrashes(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxCrashes int
+		records    int
+		wantKept   []string
+	}{
+		{"under limit", 5, 3, []string{"20240101T000000-a.json", "20240102T000000-a.json", "20240103T000000-a.json"}},
+		{"over limit keeps newest", 2, 4, []string{"20240103T000000-a.json", "20240104T000000-a.json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for i := tt.records; i >= 1; i-- {
+				name := fmt.Sprintf("202401%02dT000000-a.json", i)
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Files that are not records are left alone
+			os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0600)
+			os.Mkdir(filepath.Join(dir, "00000000T000000-dir.json"), 0700)
+
+			m := NewManager("demo", nil)
+			m.CrashDumps.MaxCrashes = tt.maxCrashes
+			m.pruneCrashes(dir)
+
+			var kept []string
+			entries, _ := os.ReadDir(dir)
+			for _, entry := range entries {
+				if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+					kept = append(kept, entry.Name())
+				}
+			}
+			if strings.Join(kept, ",") != strings.Join(tt.wantKept, ",") {
+				t.Errorf("Expected %v to be kept, got %v", tt.wantKept, kept)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+				t.Errorf("Expected other files to be kept: %v", err)
+			}
+		})
+	}
+}
+
+func TestShowCrash(t *testing.T) {
+	m := NewManager("demo", nil)
+	m.CrashDumps.Dir = t.TempDir()
+	os.WriteFile(filepath.Join(m.CrashDumps.Dir, "20240101T000000-valid.json"), []byte(`{"id":"20240101T000000-valid","plugin":"valid"}`), 0600)
+	os.WriteFile(filepath.Join(m.CrashDumps.Dir, "20240101T000000-broken.json"), []byte(`{`), 0600)
+	os.WriteFile(filepath.Join(filepath.Dir(m.CrashDumps.Dir), "outside.json"), []byte(`{"id":"outside"}`), 0600)
+
+	tests := []struct {
+		id      string
+		wantErr string
+	}{
+		{"20240101T000000-valid", ""},
+		{"", "invalid crash id"},
+		{"../outside", "invalid crash id"},
+		{"sub/record", "invalid crash id"},
+		{`sub\record`, "invalid crash id"},
+		{"..", "invalid crash id"},
+		{"20240101T000000-missing", "not found"},
+		{"20240101T000000-broken", "failed to parse crash record"},
+	}
+
+	for _, tt := range tests {
+		record, err := m.ShowCrash(context.Background(), tt.id)
+		if tt.wantErr == "" {
+			if err != nil || record.Plugin != "valid" {
+				t.Errorf("ShowCrash(%q) = %v, %v, expected the valid record", tt.id, record, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ShowCrash(%q) error = %v, expected %q", tt.id, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRecordCrash(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         string
+		wantExitCode int
+		wantSignal   string
+		wantStderr   string
+	}{
+		{"non-zero exit", "exit", 3, "", "boom"},
+		{"killed", "hang", -1, "killed", "hanging"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantSignal != "" && runtime.GOOS == "windows" {
+				t.Skip("signals are not reported on Windows")
+			}
+
+			m := NewManager("demo", nil)
+			m.CrashDumps.Dir = t.TempDir()
+
+			stderr := newTailBuffer(m.stderrLimit())
+			cmd := exec.Command(os.Args[0], "-test.run=^TestCrashHelperProcess$")
+			cmd.Env = append(os.Environ(), "TYKCTL_TEST_CRASH_HELPER="+tt.mode)
+			cmd.Stderr = stderr
+
+			started := time.Now()
+			if err := cmd.Start(); err != nil {
+				t.Fatal(err)
+			}
+			if tt.mode == "hang" {
+				// Wait for the helper to be running before killing it
+				for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+					if tail, _ := stderr.Tail(); tail != "" {
+						break
+					}
+				}
+				cmd.Process.Kill()
+			}
+			runErr := cmd.Wait()
+			if runErr == nil {
+				t.Fatal("Expected the helper process to fail")
+			}
+
+			record, err := m.recordCrash("/plugins/tykctl-demo-crasher", []string{"run"}, nil, started, stderr, runErr, false)
+			if err != nil {
+				t.Fatalf("recordCrash failed: %v", err)
+			}
+
+			saved, err := m.ShowCrash(context.Background(), record.ID)
+			if err != nil {
+				t.Fatalf("ShowCrash failed: %v", err)
+			}
+			if saved.Plugin != "crasher" || saved.Extension != "demo" {
+				t.Errorf("Expected plugin crasher of extension demo, got %s of %s", saved.Plugin, saved.Extension)
+			}
+			if saved.ExitCode != tt.wantExitCode {
+				t.Errorf("Expected exit code %d, got %d", tt.wantExitCode, saved.ExitCode)
+			}
+			if saved.Signal != tt.wantSignal {
+				t.Errorf("Expected signal %q, got %q", tt.wantSignal, saved.Signal)
+			}
+			if !strings.Contains(saved.Stderr, tt.wantStderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.wantStderr, saved.Stderr)
+			}
+			if info, err := os.Stat(filepath.Join(m.CrashDumps.Dir, record.ID+".json")); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+				t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+			}
+		})
+	}
+}
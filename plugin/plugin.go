@@ -3,6 +3,7 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,6 +12,8 @@ import (
 	"time"
 
 	"github.com/edsonmichaque/tykctl-go/config"
+	"github.com/edsonmichaque/tykctl-go/logger"
+	"go.uber.org/zap"
 )
 
 // Plugin represents a plugin executable
@@ -25,6 +28,7 @@ type Manager struct {
 	extension string
 	config    ConfigProvider
 	DefaultTimeout time.Duration  // Default timeout for plugin execution
+	CrashDumps CrashDumpOptions   // Crash dump collection for failed plugin runs
 }
 
 // ConfigProvider provides configuration access for plugins
@@ -178,6 +182,16 @@ func (m *Manager) GetConfiguredTimeout() time.Duration {
 
 // ExecuteWithTimeout executes a plugin with a specific timeout
 func (m *Manager) ExecuteWithTimeout(ctx context.Context, pluginPath string, args []string, timeout time.Duration) error {
+	err := m.run(ctx, pluginPath, args, timeout, os.Stdin, os.Stdout, os.Stderr)
+	if exitError, ok := err.(*exec.ExitError); ok {
+		os.Exit(exitError.ExitCode())
+	}
+	return err
+}
+
+// run executes a plugin with the given streams. A plugin exiting with a
+// non-zero status is reported as the raw *exec.ExitError
+func (m *Manager) run(ctx context.Context, pluginPath string, args []string, timeout time.Duration, stdin io.Reader, stdout, stderr io.Writer) error {
 	var execCtx context.Context
 	var cancel context.CancelFunc
 
@@ -193,23 +207,42 @@ func (m *Manager) ExecuteWithTimeout(ctx context.Context, pluginPath string, arg
 
 	// Create command to execute the plugin
 	cmd := exec.CommandContext(execCtx, pluginPath, args...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
 	// Set up environment variables for the plugin
 	pluginEnv := m.setupPluginEnvironment(ctx, pluginPath)
 	cmd.Env = append(os.Environ(), pluginEnv...)
 
+	// Keep the tail of stderr for crash records
+	var stderrTail *tailBuffer
+	if m.crashDumpsEnabled() {
+		stderrTail = newTailBuffer(m.stderrLimit())
+		cmd.Stderr = io.MultiWriter(stderr, stderrTail)
+	}
+
 	// Execute the plugin
+	started := time.Now()
 	err := cmd.Run()
+	m.audit(ctx, pluginPath, args, started, err)
 	if err != nil {
+		timedOut := timeout > 0 && execCtx.Err() == context.DeadlineExceeded
+
+		if stderrTail != nil {
+			if record, crashErr := m.recordCrash(pluginPath, args, pluginEnv, started, stderrTail, err, timedOut); crashErr != nil {
+				fmt.Fprintf(stderr, "Warning: failed to record plugin crash: %v\n", crashErr)
+			} else {
+				fmt.Fprintf(stderr, "Plugin crash recorded as %s\n", record.ID)
+			}
+		}
+
 		// Check if the error is due to timeout (only if timeout was set)
-		if timeout > 0 && execCtx.Err() == context.DeadlineExceeded {
+		if timedOut {
 			return fmt.Errorf("plugin execution timed out after %v: %w", timeout, err)
 		}
-		if exitError, ok := err.(*exec.ExitError); ok {
-			os.Exit(exitError.ExitCode())
+		if _, ok := err.(*exec.ExitError); ok {
+			return err
 		}
 		return fmt.Errorf("failed to execute plugin: %w", err)
 	}
@@ -217,6 +250,21 @@ func (m *Manager) ExecuteWithTimeout(ctx context.Context, pluginPath string, arg
 	return nil
 }
 
+// audit records a plugin run in the audit log, if one is set up
+func (m *Manager) audit(ctx context.Context, pluginPath string, args []string, started time.Time, runErr error) {
+	fields := append(logger.ContextFields(ctx),
+		zap.String("extension", m.extension),
+		zap.String("path", pluginPath),
+		zap.Strings("args", args),
+		zap.Duration("duration", time.Since(started)))
+	if exitError, ok := runErr.(*exec.ExitError); ok {
+		fields = append(fields, zap.Int("exit_code", exitError.ExitCode()))
+	} else if runErr != nil {
+		fields = append(fields, zap.Error(runErr))
+	}
+	logger.Audit("plugin.execute", fields...)
+}
+
 // DiscoverPlugins discovers all plugins for the extension
 func (m *Manager) DiscoverPlugins(ctx context.Context) ([]Plugin, error) {
 	var plugins []Plugin
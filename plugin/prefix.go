@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// prefixColors are cycled through to tell concurrent plugins apart
+var prefixColors = []string{
+	terminal.ColorCyan,
+	terminal.ColorYellow,
+	terminal.ColorGreen,
+	terminal.ColorPurple,
+	terminal.ColorBlue,
+	terminal.ColorRed,
+}
+
+// PrefixWriter prefixes every line written to it with a label, optionally
+// colored and timestamped. Each complete line reaches the underlying writer
+// in a single Write, so lines from several PrefixWriters sharing a
+// terminal.SyncWriter never interleave
+type PrefixWriter struct {
+	mu         sync.Mutex
+	w          io.Writer
+	prefix     string
+	timestamps bool
+	now        func() time.Time
+	buf        []byte
+}
+
+// NewPrefixWriter creates a PrefixWriter labelling lines with prefix. color
+// is an ANSI color sequence such as terminal.ColorCyan, or empty for none
+func NewPrefixWriter(w io.Writer, prefix, color string, timestamps bool) *PrefixWriter {
+	label := prefix + " |"
+	if color != "" {
+		label = color + label + terminal.ColorReset
+	}
+	return &PrefixWriter{
+		w:          w,
+		prefix:     label,
+		timestamps: timestamps,
+		now:        time.Now,
+	}
+}
+
+// Write buffers p and writes out every complete line with its prefix
+func (p *PrefixWriter) Write(data []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.buf = append(p.buf, data...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := p.writeLine(p.buf[:i]); err != nil {
+			return len(data), err
+		}
+		p.buf = p.buf[i+1:]
+	}
+	return len(data), nil
+}
+
+// Flush writes out a trailing partial line, if any
+func (p *PrefixWriter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.buf) == 0 {
+		return nil
+	}
+	err := p.writeLine(p.buf)
+	p.buf = nil
+	return err
+}
+
+// writeLine writes a single prefixed line
+func (p *PrefixWriter) writeLine(line []byte) error {
+	var b bytes.Buffer
+	b.WriteString(p.prefix)
+	b.WriteByte(' ')
+	if p.timestamps {
+		b.WriteString(p.now().Format(time.RFC3339))
+		b.WriteByte(' ')
+	}
+	b.Write(bytes.TrimSuffix(line, []byte("\r")))
+	b.WriteByte('\n')
+
+	_, err := p.w.Write(b.Bytes())
+	return err
+}
+
+// Run describes a plugin invocation for ExecuteConcurrently
+type Run struct {
+	Name string   // Label used to prefix the plugin's output
+	Path string   // Path to the plugin executable
+	Args []string // Arguments passed to the plugin
+}
+
+// ConcurrentOptions configures ExecuteConcurrently
+type ConcurrentOptions struct {
+	Timestamps bool          // Prefix every line with an RFC 3339 timestamp
+	NoColor    bool          // Disable colored prefixes
+	Timeout    time.Duration // Per plugin timeout; 0 uses GetConfiguredTimeout
+	Stdout     io.Writer     // Defaults to terminal.Stdout()
+	Stderr     io.Writer     // Defaults to terminal.Stderr()
+}
+
+// ExecuteConcurrently runs several plugins at once, interleaving their
+// stdout and stderr line by line with a colored prefix per plugin, in the
+// style of docker-compose. Plugins do not read stdin. It waits for every
+// plugin and returns the joined errors of those that failed
+func (m *Manager) ExecuteConcurrently(ctx context.Context, runs []Run, opts ConcurrentOptions) error {
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil {
+		stdout = terminal.Stdout()
+	}
+	if stderr == nil {
+		stderr = terminal.Stderr()
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = m.GetConfiguredTimeout()
+	}
+
+	useColor := !opts.NoColor && terminal.New().SupportsColor()
+
+	width := 0
+	for _, run := range runs {
+		if len(run.Name) > width {
+			width = len(run.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(runs))
+	for i, run := range runs {
+		color := ""
+		if useColor {
+			color = prefixColors[i%len(prefixColors)]
+		}
+		label := fmt.Sprintf("%-*s", width, run.Name)
+		outWriter := NewPrefixWriter(stdout, label, color, opts.Timestamps)
+		errWriter := NewPrefixWriter(stderr, label, color, opts.Timestamps)
+
+		wg.Add(1)
+		go func(i int, run Run) {
+			defer wg.Done()
+
+			err := m.run(ctx, run.Path, run.Args, timeout, nil, outWriter, errWriter)
+			if flushErr := outWriter.Flush(); err == nil {
+				err = flushErr
+			}
+			if flushErr := errWriter.Flush(); err == nil {
+				err = flushErr
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("plugin %s: %w", run.Name, err)
+			}
+		}(i, run)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
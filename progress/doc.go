@@ -3,9 +3,13 @@
 // Features:
 //   - Spinner Support: Animated spinners for long-running operations
 //   - Progress Bars: Visual progress bars with percentage and status
+//   - Step Checklists: Multi-phase checklists with durations and failure marking
+//   - Nested Tasks: Sub-task hierarchies with weighted percentage roll-up
 //   - Context Support: Full context.Context integration for cancellation
+//   - Non-TTY Fallback: Plain-text progress lines when stdout is not a terminal
 //   - Bubble Tea Integration: Built on the Bubble Tea TUI framework
 //   - Customizable: Configurable messages, characters, and styling
+//   - Themes and Templates: Brandable characters, colors and text/template lines, with an ASCII theme
 //   - Thread-safe: Safe for concurrent use
 //
 // Example:
@@ -0,0 +1,198 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// DefaultPlainInterval is how often plain-text progress prints a line
+// when progress has not crossed the next 10% step
+const DefaultPlainInterval = 10 * time.Second
+
+// plainStep is the percentage between plain-text progress lines
+const plainStep = 10
+
+// WithPlain forces plain-text progress lines on or off, instead of choosing
+// them when stdout is not a terminal
+func (b *Bar) WithPlain(plain bool) *Bar {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.plain = &plain
+	return b
+}
+
+// WithOutput sets where plain-text progress lines are written. It defaults
+// to the shared synchronized stdout of the terminal package
+func (b *Bar) WithOutput(w io.Writer) *Bar {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.output = w
+	return b
+}
+
+// WithInterval sets how often plain-text progress prints a line when it has
+// not crossed the next 10% step
+func (b *Bar) WithInterval(interval time.Duration) *Bar {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.interval = interval
+	return b
+}
+
+// usePlain reports whether to print plain-text lines instead of drawing a
+// bar, which needs a terminal
+func (b *Bar) usePlain() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.plain != nil {
+		return *b.plain
+	}
+	return !terminal.New().IsTTY()
+}
+
+// withPlain runs fn printing a line every 10% of total, and at least once
+// per interval so long steps still show signs of life in CI logs
+func (b *Bar) withPlain(ctx context.Context, fn func(update func(int64)) error) error {
+	b.mu.Lock()
+	reporter := &plainReporter{
+		w:       b.output,
+		message: b.message,
+		total:   b.total,
+		start:   time.Now(),
+	}
+	interval := b.interval
+	if tmpl := b.template; tmpl != nil {
+		reporter.format = func(current int64) string {
+			return executeTemplate(tmpl, b.data(reporter.message, current, reporter.total, reporter.start))
+		}
+	}
+	b.mu.Unlock()
+
+	if reporter.w == nil {
+		reporter.w = terminal.Stdout()
+	}
+	if interval <= 0 {
+		interval = DefaultPlainInterval
+	}
+	reporter.last = reporter.start
+	reporter.update(0, true)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- fn(func(inc int64) {
+			b.Add(inc)
+			reporter.update(b.currentValue(), false)
+		})
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-errChan:
+			reporter.finish(b.currentValue(), err)
+			return err
+		case <-ctx.Done():
+			reporter.finish(b.currentValue(), ctx.Err())
+			return ctx.Err()
+		case now := <-ticker.C:
+			if now.Sub(reporter.lastTime()) >= interval {
+				reporter.update(b.currentValue(), true)
+			}
+		}
+	}
+}
+
+// currentValue returns the progress so far
+func (b *Bar) currentValue() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current
+}
+
+// plainReporter prints plain-text progress lines without control sequences
+type plainReporter struct {
+	w       io.Writer
+	message string
+	total   int64
+	start   time.Time
+	// format, when set, renders progress lines instead of the message and
+	// status
+	format func(current int64) string
+
+	mu   sync.Mutex
+	last time.Time
+	step int64
+	done bool
+}
+
+// update prints a line when current crosses the next step, or always when
+// force is set
+func (r *plainReporter) update(current int64, force bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done {
+		return
+	}
+	if r.total > 0 {
+		step := current * 100 / r.total / plainStep
+		if step <= r.step && !force {
+			return
+		}
+		if step > r.step {
+			r.step = step
+		}
+	} else if !force {
+		return
+	}
+
+	r.last = time.Now()
+	if r.format != nil {
+		fmt.Fprintln(r.w, r.format(current))
+		return
+	}
+	fmt.Fprintf(r.w, "%s %s\n", r.message, r.status(current))
+}
+
+// finish prints the final line, once
+func (r *plainReporter) finish(current int64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done {
+		return
+	}
+	r.done = true
+
+	elapsed := time.Since(r.start).Round(100 * time.Millisecond)
+	switch {
+	case err == context.Canceled || err == context.DeadlineExceeded:
+		fmt.Fprintf(r.w, "%s cancelled at %s\n", r.message, r.status(current))
+	case err != nil:
+		fmt.Fprintf(r.w, "%s failed at %s\n", r.message, r.status(current))
+	default:
+		fmt.Fprintf(r.w, "%s done in %s\n", r.message, elapsed)
+	}
+}
+
+// lastTime returns when the last line was printed
+func (r *plainReporter) lastTime() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// status describes current against the total, when one is known
+func (r *plainReporter) status(current int64) string {
+	if r.total <= 0 {
+		return fmt.Sprintf("%d", current)
+	}
+	return fmt.Sprintf("%d%% (%d/%d)", current*100/r.total, current, r.total)
+}
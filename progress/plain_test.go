@@ -0,0 +1,64 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBarPlainOutput(t *testing.T) {
+	var out bytes.Buffer
+	bar := NewBar(100).WithPlain(true).WithOutput(&out)
+
+	err := bar.WithContext(context.Background(), "Downloading", 100, func(update func(int64)) error {
+		for i := 0; i < 20; i++ {
+			update(5)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	// A line at the start, one per 10% and the final line
+	if len(lines) != 12 {
+		t.Fatalf("Expected 12 lines, got %d:\n%s", len(lines), out.String())
+	}
+	if lines[0] != "Downloading 0% (0/100)" || lines[5] != "Downloading 50% (50/100)" {
+		t.Errorf("Unexpected lines:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[11], "Downloading done in ") {
+		t.Errorf("Expected a done line, got %q", lines[11])
+	}
+	if strings.Contains(out.String(), "\x1b") {
+		t.Error("Expected no control sequences in plain output")
+	}
+}
+
+func TestBarPlainInterval(t *testing.T) {
+	var out bytes.Buffer
+	bar := NewBar(100).WithPlain(true).WithOutput(&out).WithInterval(10 * time.Millisecond)
+
+	expectedErr := errors.New("boom")
+	err := bar.WithContext(context.Background(), "Installing", 100, func(update func(int64)) error {
+		update(3)
+		time.Sleep(50 * time.Millisecond)
+		return expectedErr
+	})
+	if err != expectedErr {
+		t.Fatalf("Expected error %v, got %v", expectedErr, err)
+	}
+
+	output := out.String()
+	// Stalled progress still prints lines on the interval
+	if strings.Count(output, "Installing 3% (3/100)") < 2 {
+		t.Errorf("Expected periodic lines while stalled, got:\n%s", output)
+	}
+	if !strings.HasSuffix(output, "Installing failed at 3% (3/100)\n") {
+		t.Errorf("Expected a failure line, got:\n%s", output)
+	}
+}
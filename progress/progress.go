@@ -2,10 +2,13 @@ package progress
 
 import (
 	"context"
+	"io"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/edsonmichaque/tykctl-go/terminal"
 	"github.com/vbauerster/mpb/v8"
 	"github.com/vbauerster/mpb/v8/decor"
 )
@@ -23,15 +26,22 @@ type Bar struct {
 	total   int64
 	current int64
 	mu      sync.Mutex
+
+	// plain, when set, overrides choosing plain-text output by whether
+	// stdout is a terminal
+	plain    *bool
+	output   io.Writer
+	interval time.Duration
+	theme    Theme
+	template *template.Template
 }
 
 // New creates a new spinner
 func New() *Spinner {
-	// Custom spinner frames: ⠋⠙⠹⠸⠼
-	customFrames := []string{"⠋", "⠙", "⠹", "⠸", "⠼"}
-	s := spinner.New(customFrames, 100*time.Millisecond)
+	theme := DefaultTheme()
+	s := spinner.New(theme.SpinnerFrames, theme.SpinnerInterval)
 	s.Suffix = " "
-	s.FinalMSG = "✓ Complete!\n"
+	s.FinalMSG = theme.CompleteMessage
 	return &Spinner{
 		spinner: s,
 	}
@@ -41,7 +51,45 @@ func New() *Spinner {
 func NewBar(total int64) *Bar {
 	return &Bar{
 		total: total,
+		theme: DefaultTheme(),
+	}
+}
+
+// WithTheme sets the spinner frames, speed, color and completion message
+func (s *Spinner) WithTheme(theme Theme) *Spinner {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	theme = theme.withDefaults()
+	if s.spinner != nil {
+		term := terminal.New()
+		frames := make([]string, len(theme.SpinnerFrames))
+		for i, frame := range theme.SpinnerFrames {
+			frames[i] = paint(term, theme.ActiveColor, frame)
+		}
+		s.spinner.UpdateCharSet(frames)
+		s.spinner.UpdateSpeed(theme.SpinnerInterval)
+		s.spinner.FinalMSG = theme.CompleteMessage
 	}
+	return s
+}
+
+// WithTheme sets the characters and width the bar is drawn with
+func (b *Bar) WithTheme(theme Theme) *Bar {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.theme = theme.withDefaults()
+	return b
+}
+
+// WithTemplate renders each progress line with tmpl, executed with a
+// BarData, instead of the message, bar and percentage. It is used for
+// plain-text lines too
+func (b *Bar) WithTemplate(tmpl *template.Template) *Bar {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.template = tmpl
+	return b
 }
 
 // WithMessage sets the spinner message
@@ -122,7 +170,9 @@ func (b *Bar) SetCurrent(current int64) {
 	}
 }
 
-// WithContext runs a function with a progress bar
+// WithContext runs a function with a progress bar. When stdout is not a
+// terminal, such as in CI logs or a pipe, it prints plain-text progress
+// lines instead
 func (b *Bar) WithContext(ctx context.Context, message string, total int64, fn func(update func(int64)) error) error {
 	b.mu.Lock()
 	b.message = message
@@ -130,21 +180,42 @@ func (b *Bar) WithContext(ctx context.Context, message string, total int64, fn f
 	b.current = 0
 	b.mu.Unlock()
 
+	if b.usePlain() {
+		return b.withPlain(ctx, fn)
+	}
+
+	b.mu.Lock()
+	theme := b.theme
+	tmpl := b.template
+	b.mu.Unlock()
+
 	// Create progress container
-	p := mpb.New(mpb.WithWidth(64), mpb.WithRefreshRate(50*time.Millisecond))
+	p := mpb.New(mpb.WithWidth(theme.BarWidth), mpb.WithRefreshRate(50*time.Millisecond))
 
 	// Create progress bar
-	bar := p.AddBar(total,
-		mpb.PrependDecorators(
-			decor.Name(message, decor.WC{W: len(message) + 1, C: decor.DindentRight}),
-		),
-		mpb.AppendDecorators(
-			decor.Percentage(decor.WC{W: 5}),
-			decor.OnComplete(
-				decor.EwmaETA(decor.ET_STYLE_GO, 60, decor.WC{W: 4}), "done",
+	var bar *mpb.Bar
+	if tmpl != nil {
+		started := time.Now()
+		bar = p.New(total, mpb.NopStyle(),
+			mpb.PrependDecorators(
+				decor.Any(func(stat decor.Statistics) string {
+					return executeTemplate(tmpl, b.data(message, stat.Current, stat.Total, started))
+				}),
+			),
+		)
+	} else {
+		bar = p.New(total, theme.barStyle(),
+			mpb.PrependDecorators(
+				decor.Name(message, decor.WC{W: len(message) + 1, C: decor.DindentRight}),
 			),
-		),
-	)
+			mpb.AppendDecorators(
+				decor.Percentage(decor.WC{W: 5}),
+				decor.OnComplete(
+					decor.EwmaETA(decor.ET_STYLE_GO, 60, decor.WC{W: 4}), "done",
+				),
+			),
+		)
+	}
 
 	b.mu.Lock()
 	b.bar = bar
@@ -168,3 +239,20 @@ func (b *Bar) WithContext(ctx context.Context, message string, total int64, fn f
 		return ctx.Err()
 	}
 }
+
+// data builds the template data for the bar at current
+func (b *Bar) data(message string, current, total int64, started time.Time) BarData {
+	data := BarData{
+		Message: message,
+		Current: current,
+		Total:   total,
+		Elapsed: time.Since(started),
+	}
+	if total > 0 {
+		data.Percent = float64(current) * 100 / float64(total)
+	}
+	b.mu.Lock()
+	data.Bar = b.theme.drawBar(data.Percent)
+	b.mu.Unlock()
+	return data
+}
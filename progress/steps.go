@@ -0,0 +1,333 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// StepStatus is the state of a step in a Steps checklist
+type StepStatus int
+
+const (
+	// StepPending has not started yet
+	StepPending StepStatus = iota
+	// StepRunning is in progress
+	StepRunning
+	// StepDone finished successfully
+	StepDone
+	// StepFailed finished with an error
+	StepFailed
+	// StepSkipped was not needed
+	StepSkipped
+)
+
+// String returns the status name
+func (s StepStatus) String() string {
+	switch s {
+	case StepRunning:
+		return "running"
+	case StepDone:
+		return "done"
+	case StepFailed:
+		return "failed"
+	case StepSkipped:
+		return "skipped"
+	default:
+		return "pending"
+	}
+}
+
+// Step is one phase of a multi-phase operation
+type Step struct {
+	Name     string
+	Status   StepStatus
+	Duration time.Duration
+	Err      error
+
+	started time.Time
+}
+
+// Steps renders a checklist of phases, such as downloading, verifying and
+// installing an extension, marking each as it runs, finishes or fails:
+//
+//	✓ downloaded (1.2s)
+//	✓ verified (0.1s)
+//	→ installing
+//	  configuring
+//
+// On a terminal the list is redrawn in place; otherwise each change is
+// printed as a plain line
+type Steps struct {
+	mu     sync.Mutex
+	steps  []*Step
+	output io.Writer
+	plain  *bool
+	term   *terminal.Terminal
+	drawn  int
+
+	theme    Theme
+	template *template.Template
+}
+
+// NewSteps creates a checklist with the named steps, all pending
+func NewSteps(names ...string) *Steps {
+	s := &Steps{term: terminal.New(), theme: DefaultTheme()}
+	for _, name := range names {
+		s.steps = append(s.steps, &Step{Name: name})
+	}
+	return s
+}
+
+// WithOutput sets where the checklist is written. It defaults to the shared
+// synchronized stdout of the terminal package
+func (s *Steps) WithOutput(w io.Writer) *Steps {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.output = w
+	return s
+}
+
+// WithPlain forces plain-text lines on or off, instead of choosing them
+// when stdout is not a terminal
+func (s *Steps) WithPlain(plain bool) *Steps {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.plain = &plain
+	return s
+}
+
+// WithTheme sets the markers and colors steps are drawn with
+func (s *Steps) WithTheme(theme Theme) *Steps {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.theme = theme.withDefaults()
+	return s
+}
+
+// WithTemplate renders each step line with tmpl, executed with a StepData,
+// instead of the marker, name, error and duration
+func (s *Steps) WithTemplate(tmpl *template.Template) *Steps {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.template = tmpl
+	return s
+}
+
+// Add appends a pending step
+func (s *Steps) Add(name string) *Steps {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = append(s.steps, &Step{Name: name})
+	s.redraw()
+	return s
+}
+
+// Start marks a step as running, adding it if it is not in the list
+func (s *Steps) Start(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	step := s.step(name)
+	step.Status = StepRunning
+	step.started = time.Now()
+	step.Err = nil
+	s.changed(step)
+}
+
+// Done marks a step as finished, recording how long it ran
+func (s *Steps) Done(name string) {
+	s.finish(name, StepDone, nil)
+}
+
+// Fail marks a step as failed with err
+func (s *Steps) Fail(name string, err error) {
+	s.finish(name, StepFailed, err)
+}
+
+// Skip marks a step as not needed
+func (s *Steps) Skip(name string) {
+	s.finish(name, StepSkipped, nil)
+}
+
+// Run runs fn as the named step, marking it done or failed by its result.
+// A cancelled ctx fails the step without running fn
+func (s *Steps) Run(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		s.Fail(name, err)
+		return err
+	}
+
+	s.Start(name)
+	if err := fn(ctx); err != nil {
+		s.Fail(name, err)
+		return err
+	}
+	s.Done(name)
+	return nil
+}
+
+// Steps returns a snapshot of the steps in order
+func (s *Steps) Steps() []Step {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	steps := make([]Step, len(s.steps))
+	for i, step := range s.steps {
+		steps[i] = *step
+	}
+	return steps
+}
+
+// Failed reports whether any step failed
+func (s *Steps) Failed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, step := range s.steps {
+		if step.Status == StepFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the checklist without control sequences
+func (s *Steps) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	for _, step := range s.steps {
+		b.WriteString(s.line(step, false))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// finish records the end of a step
+func (s *Steps) finish(name string, status StepStatus, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	step := s.step(name)
+	if step.Status == StepRunning {
+		step.Duration = time.Since(step.started)
+	}
+	step.Status = status
+	step.Err = err
+	s.changed(step)
+}
+
+// step returns the named step, appending it when missing
+func (s *Steps) step(name string) *Step {
+	for _, step := range s.steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	step := &Step{Name: name}
+	s.steps = append(s.steps, step)
+	return step
+}
+
+// changed writes a changed step: the single line in plain mode, or the
+// whole list redrawn on a terminal
+func (s *Steps) changed(step *Step) {
+	if s.usePlain() {
+		fmt.Fprintln(s.writer(), s.line(step, false))
+		return
+	}
+	s.redraw()
+}
+
+// redraw rewrites the list over the one drawn before. It does nothing in
+// plain mode, where only changes are printed
+func (s *Steps) redraw() {
+	if s.usePlain() {
+		return
+	}
+
+	var b strings.Builder
+	if s.drawn > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", s.drawn)
+	}
+	for _, step := range s.steps {
+		b.WriteString("\r\x1b[2K")
+		b.WriteString(clip(s.line(step, true)))
+		b.WriteString("\n")
+	}
+	s.drawn = len(s.steps)
+	io.WriteString(s.writer(), b.String())
+}
+
+// line formats a step, colouring its marker when color is set
+func (s *Steps) line(step *Step, color bool) string {
+	marker, markerColor := s.theme.StepPending, ""
+	switch step.Status {
+	case StepRunning:
+		marker, markerColor = s.theme.StepRunning, s.theme.ActiveColor
+	case StepDone:
+		marker, markerColor = s.theme.StepDone, s.theme.SuccessColor
+	case StepFailed:
+		marker, markerColor = s.theme.StepFailed, s.theme.ErrorColor
+	case StepSkipped:
+		marker, markerColor = s.theme.StepSkipped, s.theme.MutedColor
+	}
+
+	if s.template != nil {
+		return executeTemplate(s.template, StepData{
+			Marker:   marker,
+			Name:     step.Name,
+			Status:   step.Status,
+			Duration: step.Duration,
+			Err:      step.Err,
+		})
+	}
+
+	if color {
+		marker = paint(s.term, markerColor, marker)
+	}
+	line := marker + " " + step.Name
+	if step.Err != nil {
+		line += ": " + step.Err.Error()
+	}
+	if step.Status == StepDone || step.Status == StepFailed {
+		line += fmt.Sprintf(" (%s)", formatStepDuration(step.Duration))
+	}
+	return line
+}
+
+// usePlain reports whether to print plain lines instead of redrawing
+func (s *Steps) usePlain() bool {
+	if s.plain != nil {
+		return *s.plain
+	}
+	return !s.term.IsTTY()
+}
+
+// writer returns the configured output or the shared stdout
+func (s *Steps) writer() io.Writer {
+	if s.output != nil {
+		return s.output
+	}
+	return terminal.Stdout()
+}
+
+// formatStepDuration rounds a duration for display
+func formatStepDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
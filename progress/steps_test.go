@@ -0,0 +1,113 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestStepsRun(t *testing.T) {
+	var out bytes.Buffer
+	steps := NewSteps("downloaded", "verified", "installing", "configuring").
+		WithPlain(true).
+		WithOutput(&out)
+	ctx := context.Background()
+
+	if err := steps.Run(ctx, "downloaded", func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	steps.Skip("verified")
+	expectedErr := errors.New("disk full")
+	if err := steps.Run(ctx, "installing", func(ctx context.Context) error { return expectedErr }); err != expectedErr {
+		t.Fatalf("Expected %v, got %v", expectedErr, err)
+	}
+
+	got := steps.Steps()
+	want := []StepStatus{StepDone, StepSkipped, StepFailed, StepPending}
+	for i, status := range want {
+		if got[i].Status != status {
+			t.Errorf("Step %s: expected %s, got %s", got[i].Name, status, got[i].Status)
+		}
+	}
+	if !steps.Failed() || got[2].Err != expectedErr {
+		t.Errorf("Expected installing to fail with %v, got %v", expectedErr, got[2].Err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 plain lines, got %d:\n%s", len(lines), out.String())
+	}
+	if lines[0] != "→ downloaded" || !strings.HasPrefix(lines[1], "✓ downloaded (") {
+		t.Errorf("Unexpected download lines:\n%s", out.String())
+	}
+	if lines[2] != "- verified" || !strings.HasPrefix(lines[4], "✗ installing: disk full (") {
+		t.Errorf("Unexpected lines:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "\x1b") {
+		t.Error("Expected no control sequences in plain output")
+	}
+
+	if !strings.HasSuffix(steps.String(), "\n  configuring\n") {
+		t.Errorf("Expected pending step in the checklist, got:\n%s", steps.String())
+	}
+}
+
+func TestStepsRedraw(t *testing.T) {
+	var out bytes.Buffer
+	steps := NewSteps("one", "two").WithPlain(false).WithOutput(&out)
+
+	steps.Start("one")
+	steps.Done("one")
+	steps.Start("three")
+
+	output := out.String()
+	// Every redraw after the first moves back up over the previous list
+	if strings.Count(output, "\x1b[2A") != 2 || strings.Count(output, "\x1b[3A") != 0 {
+		t.Errorf("Unexpected cursor movement in %q", output)
+	}
+	if len(steps.Steps()) != 3 {
+		t.Errorf("Expected unknown step to be appended, got %d steps", len(steps.Steps()))
+	}
+}
+
+func TestStepsRedrawClipsToWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "20")
+	var out bytes.Buffer
+	steps := NewSteps("downloading the extension archive").WithPlain(false).WithOutput(&out)
+
+	steps.Start("downloading the extension archive")
+
+	// A wrapped line would throw off the cursor movement of the next redraw
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		line = line[strings.LastIndex(line, "\x1b[2K")+len("\x1b[2K"):]
+		if width := ansi.StringWidth(line); width > 19 {
+			t.Errorf("line %q is %d columns wide, want at most 19", line, width)
+		}
+	}
+	if !strings.Contains(out.String(), "…") {
+		t.Errorf("expected a clipped line in %q", out.String())
+	}
+}
+
+func TestStepsCancelled(t *testing.T) {
+	var out bytes.Buffer
+	steps := NewSteps("install").WithPlain(true).WithOutput(&out)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ran := false
+	err := steps.Run(ctx, "install", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || ran {
+		t.Errorf("Expected cancelled step not to run, got %v (ran %v)", err, ran)
+	}
+	if !steps.Failed() {
+		t.Error("Expected cancelled step to be failed")
+	}
+}
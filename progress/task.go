@@ -0,0 +1,409 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// taskRefreshRate is how often a task tree is redrawn on a terminal
+const taskRefreshRate = 100 * time.Millisecond
+
+// Task is a unit of progress that can be split into sub-tasks, such as an
+// install made of download, verify and extract. A task with sub-tasks
+// takes its percentage from theirs, weighted by WithWeight, and renders
+// them indented below it:
+//
+//	install    45%
+//	  download done
+//	  verify   35%
+//	  extract  0%
+type Task struct {
+	tree     *taskTree
+	parent   *Task
+	children []*Task
+
+	name    string
+	total   int64
+	current int64
+	weight  float64
+	done    bool
+	err     error
+}
+
+// taskTree holds what the tasks of one hierarchy share
+type taskTree struct {
+	mu       sync.Mutex
+	output   io.Writer
+	plain    *bool
+	interval time.Duration
+	term     *terminal.Terminal
+	theme    Theme
+
+	drawn   int
+	dirty   bool
+	printed map[*Task]int64
+	last    time.Time
+}
+
+// NewTask creates a top-level task counting up to total
+func NewTask(name string, total int64) *Task {
+	tree := &taskTree{term: terminal.New(), theme: DefaultTheme(), printed: map[*Task]int64{}}
+	return &Task{tree: tree, name: name, total: total, weight: 1}
+}
+
+// Sub attaches a sub-task counting up to total
+func (t *Task) Sub(name string, total int64) *Task {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+
+	child := &Task{tree: t.tree, parent: t, name: name, total: total, weight: 1}
+	t.children = append(t.children, child)
+	t.tree.dirty = true
+	return child
+}
+
+// WithWeight sets how much the task counts towards its parent relative to
+// its siblings. Every task weighs 1 by default
+func (t *Task) WithWeight(weight float64) *Task {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	if weight >= 0 {
+		t.weight = weight
+	}
+	return t
+}
+
+// WithTheme sets the colors the task tree is drawn with
+func (t *Task) WithTheme(theme Theme) *Task {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	t.tree.theme = theme.withDefaults()
+	return t
+}
+
+// WithOutput sets where the task tree is written. It defaults to the shared
+// synchronized stdout of the terminal package
+func (t *Task) WithOutput(w io.Writer) *Task {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	t.tree.output = w
+	return t
+}
+
+// WithPlain forces plain-text lines on or off, instead of choosing them
+// when stdout is not a terminal
+func (t *Task) WithPlain(plain bool) *Task {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	t.tree.plain = &plain
+	return t
+}
+
+// WithInterval sets how often plain-text output prints the top-level task
+// when it has not crossed the next 10% step
+func (t *Task) WithInterval(interval time.Duration) *Task {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	t.tree.interval = interval
+	return t
+}
+
+// Add adds to the current progress
+func (t *Task) Add(inc int64) {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	t.setCurrent(t.current + inc)
+}
+
+// SetCurrent sets the current progress
+func (t *Task) SetCurrent(current int64) {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	t.setCurrent(current)
+}
+
+// Done marks the task complete
+func (t *Task) Done() {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	t.done = true
+	t.tree.changed(t)
+}
+
+// Fail marks the task failed with err
+func (t *Task) Fail(err error) {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	t.err = err
+	t.tree.changed(t)
+}
+
+// Percent returns the progress as a percentage from 0 to 100, rolled up
+// from the sub-tasks when there are any
+func (t *Task) Percent() float64 {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	return t.percent()
+}
+
+// String renders the task and its sub-tasks without control sequences
+func (t *Task) String() string {
+	t.tree.mu.Lock()
+	defer t.tree.mu.Unlock()
+	return t.render(false)
+}
+
+// WithContext runs fn while rendering the tree, marking the task done or
+// failed by its result. On a terminal the tree is redrawn in place;
+// otherwise a line is printed each time a task crosses another 10%
+func (t *Task) WithContext(ctx context.Context, fn func(task *Task) error) error {
+	tree := t.tree
+	tree.mu.Lock()
+	interval := tree.interval
+	if interval <= 0 {
+		interval = DefaultPlainInterval
+	}
+	plain := tree.usePlain()
+	tree.last = time.Now()
+	if plain {
+		tree.print(t)
+	}
+	tree.mu.Unlock()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- fn(t)
+	}()
+
+	refresh := taskRefreshRate
+	if plain {
+		refresh = interval
+	}
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+
+	// Redraw at once when the terminal is resized, clipped to the new width
+	var resized <-chan terminal.Size
+	if !plain {
+		resizeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		resized = terminal.Resize(resizeCtx)
+	}
+
+	for {
+		select {
+		case err := <-errChan:
+			t.finish(err)
+			return err
+		case <-ctx.Done():
+			t.finish(ctx.Err())
+			return ctx.Err()
+		case _, ok := <-resized:
+			if !ok {
+				resized = nil
+				continue
+			}
+			tree.mu.Lock()
+			tree.redraw(t)
+			tree.mu.Unlock()
+		case now := <-ticker.C:
+			tree.mu.Lock()
+			switch {
+			case !plain && tree.dirty:
+				tree.redraw(t)
+			case plain && now.Sub(tree.last) >= interval:
+				tree.print(t)
+			}
+			tree.mu.Unlock()
+		}
+	}
+}
+
+// finish marks the task by the result of WithContext and writes the final
+// state
+func (t *Task) finish(err error) {
+	tree := t.tree
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+
+	if err != nil {
+		t.err = err
+	} else {
+		t.done = true
+	}
+	if tree.usePlain() {
+		if tree.printed[t] != -1 {
+			tree.printed[t] = -1
+			tree.print(t)
+		}
+		return
+	}
+	tree.redraw(t)
+}
+
+// setCurrent clamps and records progress
+func (t *Task) setCurrent(current int64) {
+	if current < 0 {
+		current = 0
+	}
+	if t.total > 0 && current > t.total {
+		current = t.total
+	}
+	t.current = current
+	t.tree.changed(t)
+}
+
+// percent is Percent with the lock held
+func (t *Task) percent() float64 {
+	if t.done {
+		return 100
+	}
+	if len(t.children) > 0 {
+		var sum, weights float64
+		for _, child := range t.children {
+			sum += child.weight * child.percent()
+			weights += child.weight
+		}
+		if weights == 0 {
+			return 0
+		}
+		return sum / weights
+	}
+	if t.total <= 0 {
+		return 0
+	}
+	return float64(t.current) * 100 / float64(t.total)
+}
+
+// depth is how many ancestors the task has
+func (t *Task) depth() int {
+	depth := 0
+	for parent := t.parent; parent != nil; parent = parent.parent {
+		depth++
+	}
+	return depth
+}
+
+// path names the task through its ancestors, such as "install › download"
+func (t *Task) path() string {
+	if t.parent == nil {
+		return t.name
+	}
+	return t.parent.path() + " › " + t.name
+}
+
+// status describes the state of the task
+func (t *Task) status() string {
+	switch {
+	case t.err != nil:
+		return "failed: " + t.err.Error()
+	case t.done:
+		return "done"
+	default:
+		return fmt.Sprintf("%.0f%%", t.percent())
+	}
+}
+
+// render draws the task and its sub-tasks, indented by depth, with
+// statuses aligned in a column
+func (t *Task) render(color bool) string {
+	var tasks []*Task
+	var collect func(task *Task)
+	collect = func(task *Task) {
+		tasks = append(tasks, task)
+		for _, child := range task.children {
+			collect(child)
+		}
+	}
+	collect(t)
+
+	width := 0
+	for _, task := range tasks {
+		if n := 2*(task.depth()-t.depth()) + len([]rune(task.name)); n > width {
+			width = n
+		}
+	}
+
+	var b strings.Builder
+	for _, task := range tasks {
+		label := strings.Repeat("  ", task.depth()-t.depth()) + task.name
+		status := task.status()
+		if color {
+			switch {
+			case task.err != nil:
+				status = paint(t.tree.term, t.tree.theme.ErrorColor, status)
+			case task.done:
+				status = paint(t.tree.term, t.tree.theme.SuccessColor, status)
+			}
+		}
+		fmt.Fprintf(&b, "%s%s %s\n", label, strings.Repeat(" ", width-len([]rune(label))), status)
+	}
+	return b.String()
+}
+
+// changed records a change to task: printing lines for it and its
+// ancestors in plain mode, or marking the tree for a redraw
+func (tree *taskTree) changed(task *Task) {
+	if !tree.usePlain() {
+		tree.dirty = true
+		return
+	}
+
+	for ; task != nil; task = task.parent {
+		step := int64(task.percent()) / plainStep
+		if task.err != nil || task.done {
+			step = -1
+		}
+		if last, ok := tree.printed[task]; ok && last == step || !ok && step == 0 {
+			continue
+		}
+		tree.printed[task] = step
+		tree.last = time.Now()
+		fmt.Fprintf(tree.writer(), "%s %s\n", task.path(), task.status())
+	}
+}
+
+// print writes the current status of task as a plain line
+func (tree *taskTree) print(task *Task) {
+	tree.last = time.Now()
+	fmt.Fprintf(tree.writer(), "%s %s\n", task.path(), task.status())
+}
+
+// redraw rewrites the tree of root over the one drawn before
+func (tree *taskTree) redraw(root *Task) {
+	var b strings.Builder
+	if tree.drawn > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", tree.drawn)
+	}
+	lines := strings.Split(strings.TrimSuffix(root.render(true), "\n"), "\n")
+	for _, line := range lines {
+		b.WriteString("\r\x1b[2K")
+		b.WriteString(clip(line))
+		b.WriteString("\n")
+	}
+	tree.drawn = len(lines)
+	tree.dirty = false
+	io.WriteString(tree.writer(), b.String())
+}
+
+// usePlain reports whether to print plain lines instead of redrawing
+func (tree *taskTree) usePlain() bool {
+	if tree.plain != nil {
+		return *tree.plain
+	}
+	return !tree.term.IsTTY()
+}
+
+// writer returns the configured output or the shared stdout
+func (tree *taskTree) writer() io.Writer {
+	if tree.output != nil {
+		return tree.output
+	}
+	return terminal.Stdout()
+}
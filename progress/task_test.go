@@ -0,0 +1,82 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTaskRollUp(t *testing.T) {
+	install := NewTask("install", 0).WithPlain(true).WithOutput(&bytes.Buffer{})
+	download := install.Sub("download", 100)
+	verify := install.Sub("verify", 10)
+	extract := install.Sub("extract", 4).WithWeight(2)
+
+	download.Add(100)
+	verify.SetCurrent(5)
+	if got := install.Percent(); got != 37.5 {
+		t.Errorf("Expected 37.5%%, got %v", got)
+	}
+
+	extract.Done()
+	if got := install.Percent(); got != 87.5 {
+		t.Errorf("Expected 87.5%%, got %v", got)
+	}
+
+	want := "install    88%\n" +
+		"  download 100%\n" +
+		"  verify   50%\n" +
+		"  extract  done\n"
+	if got := install.String(); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestTaskNested(t *testing.T) {
+	root := NewTask("sync", 0).WithPlain(true).WithOutput(&bytes.Buffer{})
+	ext := root.Sub("extension", 0)
+	files := ext.Sub("files", 10)
+	ext.Sub("hooks", 10)
+
+	files.Add(10)
+	if got := ext.Percent(); got != 50 {
+		t.Errorf("Expected 50%%, got %v", got)
+	}
+	if got := root.Percent(); got != 50 {
+		t.Errorf("Expected nested roll-up of 50%%, got %v", got)
+	}
+	if !strings.Contains(root.String(), "\n    files ") {
+		t.Errorf("Expected grandchildren indented twice, got:\n%s", root.String())
+	}
+}
+
+func TestTaskWithContextPlain(t *testing.T) {
+	var out bytes.Buffer
+	install := NewTask("install", 0).WithPlain(true).WithOutput(&out)
+	download := install.Sub("download", 100)
+	verify := install.Sub("verify", 1)
+
+	expectedErr := errors.New("checksum mismatch")
+	err := install.WithContext(context.Background(), func(task *Task) error {
+		download.Add(50)
+		download.Add(50)
+		verify.Fail(expectedErr)
+		return expectedErr
+	})
+	if err != expectedErr {
+		t.Fatalf("Expected %v, got %v", expectedErr, err)
+	}
+
+	want := "install 0%\n" +
+		"install › download 50%\n" +
+		"install 25%\n" +
+		"install › download 100%\n" +
+		"install 50%\n" +
+		"install › verify failed: checksum mismatch\n" +
+		"install failed: checksum mismatch\n"
+	if out.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out.String())
+	}
+}
@@ -0,0 +1,223 @@
+package progress
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/edsonmichaque/tykctl-go/terminal"
+	"github.com/vbauerster/mpb/v8"
+)
+
+// Theme sets the characters, colors and widths progress indicators are
+// drawn with, so hosts can match their brand or fall back to plain ASCII
+// on limited terminals
+type Theme struct {
+	// SpinnerFrames are the animation frames of a spinner
+	SpinnerFrames []string
+	// SpinnerInterval is the delay between spinner frames
+	SpinnerInterval time.Duration
+	// CompleteMessage is printed when a spinner stops
+	CompleteMessage string
+
+	// BarLeft, BarFill, BarTip, BarEmpty and BarRight draw a bar such as
+	// [=====>----]
+	BarLeft  string
+	BarFill  string
+	BarTip   string
+	BarEmpty string
+	BarRight string
+	// BarWidth is the width of a bar in columns
+	BarWidth int
+
+	// StepPending, StepRunning, StepDone, StepFailed and StepSkipped mark
+	// the steps of a checklist
+	StepPending string
+	StepRunning string
+	StepDone    string
+	StepFailed  string
+	StepSkipped string
+
+	// ActiveColor, SuccessColor, ErrorColor and MutedColor are ANSI
+	// sequences such as terminal.ColorGreen. Empty leaves text uncolored
+	ActiveColor  string
+	SuccessColor string
+	ErrorColor   string
+	MutedColor   string
+}
+
+// DefaultTheme returns the theme used unless another is set
+func DefaultTheme() Theme {
+	return Theme{
+		SpinnerFrames:   []string{"⠋", "⠙", "⠹", "⠸", "⠼"},
+		SpinnerInterval: 100 * time.Millisecond,
+		CompleteMessage: "✓ Complete!\n",
+		BarLeft:         "[",
+		BarFill:         "=",
+		BarTip:          ">",
+		BarEmpty:        "-",
+		BarRight:        "]",
+		BarWidth:        64,
+		StepPending:     " ",
+		StepRunning:     "→",
+		StepDone:        "✓",
+		StepFailed:      "✗",
+		StepSkipped:     "-",
+		ActiveColor:     terminal.ColorCyan,
+		SuccessColor:    terminal.ColorGreen,
+		ErrorColor:      terminal.ColorRed,
+		MutedColor:      terminal.ColorGray,
+	}
+}
+
+// ASCIITheme returns a theme using only ASCII characters and no colors,
+// for terminals that cannot show Unicode or ANSI sequences
+func ASCIITheme() Theme {
+	return Theme{
+		SpinnerFrames:   []string{"|", "/", "-", `\`},
+		SpinnerInterval: 100 * time.Millisecond,
+		CompleteMessage: "Complete!\n",
+		BarLeft:         "[",
+		BarFill:         "#",
+		BarTip:          "#",
+		BarEmpty:        ".",
+		BarRight:        "]",
+		BarWidth:        40,
+		StepPending:     " ",
+		StepRunning:     ">",
+		StepDone:        "+",
+		StepFailed:      "x",
+		StepSkipped:     "-",
+	}
+}
+
+// BarData is what a bar template set with Bar.WithTemplate is executed
+// with
+type BarData struct {
+	Message string
+	Current int64
+	Total   int64
+	// Percent is from 0 to 100
+	Percent float64
+	Elapsed time.Duration
+	// Bar is the bar drawn with the theme, such as [=====>----]
+	Bar string
+}
+
+// StepData is what a step template set with Steps.WithTemplate is
+// executed with, once per step
+type StepData struct {
+	// Marker is the uncolored theme marker for the status
+	Marker   string
+	Name     string
+	Status   StepStatus
+	Duration time.Duration
+	Err      error
+}
+
+// withDefaults fills fields left empty from the default theme, so a
+// partial theme only changes what it sets
+func (t Theme) withDefaults() Theme {
+	defaults := DefaultTheme()
+	if len(t.SpinnerFrames) == 0 {
+		t.SpinnerFrames = defaults.SpinnerFrames
+	}
+	if t.SpinnerInterval <= 0 {
+		t.SpinnerInterval = defaults.SpinnerInterval
+	}
+	if t.BarFill == "" {
+		t.BarFill = defaults.BarFill
+	}
+	if t.BarWidth <= 0 {
+		t.BarWidth = defaults.BarWidth
+	}
+	if t.StepDone == "" {
+		t.StepDone = defaults.StepDone
+	}
+	if t.StepFailed == "" {
+		t.StepFailed = defaults.StepFailed
+	}
+	if t.StepRunning == "" {
+		t.StepRunning = defaults.StepRunning
+	}
+	if t.StepPending == "" {
+		t.StepPending = defaults.StepPending
+	}
+	if t.StepSkipped == "" {
+		t.StepSkipped = defaults.StepSkipped
+	}
+	return t
+}
+
+// paint colors text when a color is set and the terminal supports it
+func paint(term *terminal.Terminal, color, text string) string {
+	if color == "" {
+		return text
+	}
+	return term.Colorize(text, color)
+}
+
+// clip cuts a redrawn line to the terminal width. A line that wrapped would
+// take more rows than were counted, so the next redraw would move up too
+// little and leave copies of the list behind
+func clip(line string) string {
+	return ansi.Truncate(line, terminal.Width()-1, "…")
+}
+
+// barStyle builds the mpb bar style for the theme
+func (t Theme) barStyle() mpb.BarStyleComposer {
+	return mpb.BarStyle().
+		Lbound(t.BarLeft).
+		Filler(t.BarFill).
+		Tip(t.BarTip).
+		Padding(t.BarEmpty).
+		Rbound(t.BarRight)
+}
+
+// drawBar draws a bar of the theme's width at percent, for templates
+func (t Theme) drawBar(percent float64) string {
+	width := t.BarWidth - len([]rune(t.BarLeft)) - len([]rune(t.BarRight))
+	if width < 1 {
+		width = 1
+	}
+	filled := int(percent * float64(width) / 100)
+	if filled > width {
+		filled = width
+	}
+
+	var b strings.Builder
+	b.WriteString(t.BarLeft)
+	switch {
+	case filled == width:
+		b.WriteString(strings.Repeat(t.BarFill, width))
+	case filled > 0 && t.BarTip != "":
+		b.WriteString(strings.Repeat(t.BarFill, filled-1))
+		b.WriteString(t.BarTip)
+		b.WriteString(strings.Repeat(orSpace(t.BarEmpty), width-filled))
+	default:
+		b.WriteString(strings.Repeat(t.BarFill, filled))
+		b.WriteString(strings.Repeat(orSpace(t.BarEmpty), width-filled))
+	}
+	b.WriteString(t.BarRight)
+	return b.String()
+}
+
+// orSpace returns s, or a space when s is empty
+func orSpace(s string) string {
+	if s == "" {
+		return " "
+	}
+	return s
+}
+
+// executeTemplate renders tmpl with data, describing a failure in place
+// of the output so a bad template shows up where it is used
+func executeTemplate(tmpl *template.Template, data interface{}) string {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return fmt.Sprintf("template error: %v", err)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,98 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestThemeDrawBar(t *testing.T) {
+	theme := ASCIITheme()
+	theme.BarWidth = 12
+
+	tests := []struct {
+		percent float64
+		want    string
+	}{
+		{0, "[..........]"},
+		{50, "[#####.....]"},
+		{100, "[##########]"},
+	}
+	for _, tt := range tests {
+		if got := theme.drawBar(tt.percent); got != tt.want {
+			t.Errorf("drawBar(%v) = %q, want %q", tt.percent, got, tt.want)
+		}
+	}
+
+	arrow := DefaultTheme()
+	arrow.BarWidth = 7
+	if got := arrow.drawBar(60); got != "[==>--]" {
+		t.Errorf("Expected tip at the end of the fill, got %q", got)
+	}
+}
+
+func TestThemeWithDefaults(t *testing.T) {
+	theme := Theme{StepDone: "ok"}.withDefaults()
+	if theme.StepDone != "ok" || theme.StepFailed != "✗" || theme.BarWidth != 64 {
+		t.Errorf("Expected partial theme filled from defaults, got %+v", theme)
+	}
+}
+
+func TestStepsTheme(t *testing.T) {
+	var out bytes.Buffer
+	steps := NewSteps("fetch").WithPlain(true).WithOutput(&out).WithTheme(ASCIITheme())
+
+	steps.Start("fetch")
+	steps.Skip("lint")
+
+	if out.String() != "> fetch\n- lint\n" {
+		t.Errorf("Unexpected ASCII output %q", out.String())
+	}
+}
+
+func TestStepsTemplate(t *testing.T) {
+	var out bytes.Buffer
+	tmpl := template.Must(template.New("step").Parse("[{{.Status}}] {{.Name}}"))
+	steps := NewSteps("fetch").WithPlain(true).WithOutput(&out).WithTemplate(tmpl)
+
+	steps.Start("fetch")
+	steps.Done("fetch")
+
+	if out.String() != "[running] fetch\n[done] fetch\n" {
+		t.Errorf("Unexpected templated output %q", out.String())
+	}
+}
+
+func TestBarTemplate(t *testing.T) {
+	var out bytes.Buffer
+	theme := ASCIITheme()
+	theme.BarWidth = 7
+	tmpl := template.Must(template.New("bar").Parse("{{.Message}} {{.Bar}} {{printf \"%.0f\" .Percent}}"))
+	bar := NewBar(10).WithPlain(true).WithOutput(&out).WithTheme(theme).WithTemplate(tmpl)
+
+	err := bar.WithContext(context.Background(), "pull", 10, func(update func(int64)) error {
+		update(4)
+		update(6)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{"pull [.....] 0", "pull [##...] 40", "pull [#####] 100"}
+	for i, line := range want {
+		if i >= len(lines) || lines[i] != line {
+			t.Fatalf("Expected lines %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestExecuteTemplateError(t *testing.T) {
+	tmpl := template.Must(template.New("bad").Parse("{{.Missing}}"))
+	if got := executeTemplate(tmpl, StepData{}); !strings.HasPrefix(got, "template error: ") {
+		t.Errorf("Expected template error in output, got %q", got)
+	}
+}
@@ -0,0 +1,209 @@
+package script
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stateFileName is the hidden file in the script directory that records run results
+const stateFileName = ".state.json"
+
+// maxHeaderLines limits how far into a script the header comment is parsed
+const maxHeaderLines = 50
+
+// ScriptRun records the outcome of the last execution of a script
+type ScriptRun struct {
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Success   bool          `json:"success"`
+	ExitCode  int           `json:"exit_code"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// scriptHeader holds metadata parsed from a script's header comments
+type scriptHeader struct {
+	Description string
+	Events      []ScriptEvent
+	RequiredEnv []string
+}
+
+// stateMu serializes writes to state files across managers
+var stateMu sync.Mutex
+
+// MissingEnv returns the required environment variables that are not set
+// in the process environment or the script's own environment
+func (s *Script) MissingEnv() []string {
+	var missing []string
+	for _, key := range s.RequiredEnv {
+		if _, ok := s.Environment[key]; ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		missing = append(missing, key)
+	}
+	return missing
+}
+
+// HandlesEvent reports whether a script is bound to an event. Scripts that
+// declare no events run for every event.
+func (s *Script) HandlesEvent(event ScriptEvent) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// parseScriptHeader reads metadata from the leading comment block of a script.
+// Supported tags are "@description", "@events" and "@env"; without an
+// explicit description the first free-text comment line is used.
+//
+//	#!/bin/bash
+//	# Sync API definitions after install
+//	# @events: after-install, after-update
+//	# @env: TYK_URL, TYK_TOKEN
+func parseScriptHeader(path string) (*scriptHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := &scriptHeader{}
+	firstComment := ""
+
+	scanner := bufio.NewScanner(file)
+	for line := 0; line < maxHeaderLines && scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 0 && strings.HasPrefix(text, "#!") {
+			continue
+		}
+		if text == "" {
+			continue
+		}
+
+		comment, ok := stripComment(text)
+		if !ok {
+			break
+		}
+
+		if tag, value, found := strings.Cut(comment, ":"); found && strings.HasPrefix(tag, "@") {
+			value = strings.TrimSpace(value)
+			switch strings.ToLower(strings.TrimPrefix(tag, "@")) {
+			case "description":
+				header.Description = value
+			case "events", "event":
+				for _, event := range splitList(value) {
+					header.Events = append(header.Events, ScriptEvent(event))
+				}
+			case "env", "requires-env":
+				header.RequiredEnv = append(header.RequiredEnv, splitList(value)...)
+			}
+			continue
+		}
+
+		if firstComment == "" && comment != "" {
+			firstComment = comment
+		}
+	}
+
+	if header.Description == "" {
+		header.Description = firstComment
+	}
+
+	return header, scanner.Err()
+}
+
+// stripComment returns the text of a shell, batch or C-style comment line
+func stripComment(line string) (string, bool) {
+	for _, prefix := range []string{"#", "//", "::", "REM ", "rem "} {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix)), true
+		}
+	}
+	return "", false
+}
+
+// splitList splits a comma or space separated list
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+// loadState reads the last run results for all scripts
+func (sm *ScriptManager) loadState() (map[string]*ScriptRun, error) {
+	state := make(map[string]*ScriptRun)
+
+	data, err := os.ReadFile(filepath.Join(sm.scriptDir, stateFileName))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse script state: %w", err)
+	}
+	return state, nil
+}
+
+// recordRun stores the outcome of a script execution in the state file
+func (sm *ScriptManager) recordRun(name string, started time.Time, runErr error) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := sm.loadState()
+	if err != nil {
+		// A corrupt state file should not block recording new results
+		state = make(map[string]*ScriptRun)
+	}
+
+	run := &ScriptRun{
+		StartedAt: started,
+		Duration:  time.Since(started),
+		Success:   runErr == nil,
+	}
+	if runErr != nil {
+		run.Error = runErr.Error()
+		run.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) {
+			run.ExitCode = exitErr.ExitCode()
+		}
+	}
+	state[name] = run
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal script state: %w", err)
+	}
+
+	if err := os.MkdirAll(sm.scriptDir, 0755); err != nil {
+		return fmt.Errorf("failed to create script directory: %w", err)
+	}
+
+	tmpPath := filepath.Join(sm.scriptDir, stateFileName+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write script state: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(sm.scriptDir, stateFileName)); err != nil {
+		return fmt.Errorf("failed to replace script state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package script
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestListScriptsMetadata(t *testing.T) {
+	dir := t.TempDir()
+	content := `#!/bin/sh
+# Sync API definitions after install
+# @events: after-install, after-update
+# @env: TYKCTL_TEST_REQUIRED_VAR
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(dir, "sync"), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewScriptManager(dir)
+	scripts, err := manager.ListScripts()
+	if err != nil {
+		t.Fatalf("ListScripts failed: %v", err)
+	}
+	if len(scripts) != 1 {
+		t.Fatalf("Expected 1 script, got %d", len(scripts))
+	}
+
+	script := scripts[0]
+	if script.Description != "Sync API definitions after install" {
+		t.Errorf("Expected description from header, got '%s'", script.Description)
+	}
+	if len(script.Events) != 2 || script.Events[0] != "after-install" || script.Events[1] != "after-update" {
+		t.Errorf("Expected bound events, got %v", script.Events)
+	}
+	if !script.HandlesEvent("after-update") || script.HandlesEvent("before-install") {
+		t.Error("HandlesEvent does not match bound events")
+	}
+	if missing := script.MissingEnv(); len(missing) != 1 || missing[0] != "TYKCTL_TEST_REQUIRED_VAR" {
+		t.Errorf("Expected missing required env, got %v", missing)
+	}
+	if script.LastRun != nil {
+		t.Error("Expected no last run before execution")
+	}
+}
+
+func TestListScriptsLastRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ok"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "fail"), []byte("#!/bin/sh\nexit 3\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewScriptManager(dir)
+	scripts, err := manager.ListScripts()
+	if err != nil {
+		t.Fatalf("ListScripts failed: %v", err)
+	}
+
+	scriptCtx := &ScriptContext{Event: "test", WorkingDir: dir}
+	for _, script := range scripts {
+		script.Timeout = 5 * time.Second
+		manager.ExecuteScript(context.Background(), script, scriptCtx)
+	}
+
+	scripts, err = manager.ListScripts()
+	if err != nil {
+		t.Fatalf("ListScripts failed: %v", err)
+	}
+	if len(scripts) != 2 {
+		t.Fatalf("Expected state file to be hidden from listing, got %d scripts", len(scripts))
+	}
+
+	for _, script := range scripts {
+		if script.LastRun == nil {
+			t.Fatalf("Expected last run for %s", script.Name)
+		}
+		switch script.Name {
+		case "ok":
+			if !script.LastRun.Success {
+				t.Errorf("Expected ok script to succeed, got %+v", script.LastRun)
+			}
+		case "fail":
+			if script.LastRun.Success || script.LastRun.ExitCode != 3 {
+				t.Errorf("Expected fail script to exit 3, got %+v", script.LastRun)
+			}
+		}
+	}
+}
@@ -49,6 +49,9 @@ type Script struct {
 	Timeout     time.Duration     `json:"timeout"`
 	Environment map[string]string `json:"environment"`
 	WorkingDir  string            `json:"working_dir"`
+	Events      []ScriptEvent     `json:"events,omitempty"`
+	RequiredEnv []string          `json:"required_env,omitempty"`
+	LastRun     *ScriptRun        `json:"last_run,omitempty"`
 }
 
 // ScriptManager manages scripts for the application
@@ -210,7 +213,11 @@ func (sm *ScriptManager) ExecuteScript(ctx context.Context, script *Script, scri
 	env["TYKCTL_SCRIPT_WORKING_DIR"] = scriptCtx.WorkingDir
 
 	// Execute the script script
+	started := time.Now()
 	err := sm.executeScript(execCtx, script, scriptCtx, env)
+	if stateErr := sm.recordRun(script.Name, started, err); stateErr != nil {
+		sm.logger.Warn("Failed to record script run", zap.String("script", script.Name), zap.Error(stateErr))
+	}
 	if err != nil {
 		sm.logger.Error("Script execution failed", zap.String("script", script.Name), zap.Error(err))
 		return fmt.Errorf("script %s failed: %w", script.Name, err)
@@ -253,7 +260,8 @@ func (sm *ScriptManager) executeScript(ctx context.Context, script *Script, scri
 	return nil
 }
 
-// ListScripts returns all available scripts
+// ListScripts returns all available scripts with metadata parsed from their
+// header comments and the result of their last run
 func (sm *ScriptManager) ListScripts() ([]*Script, error) {
 	scripts := []*Script{}
 
@@ -269,6 +277,12 @@ func (sm *ScriptManager) ListScripts() ([]*Script, error) {
 		return nil, fmt.Errorf("failed to read script directory: %w", err)
 	}
 
+	state, err := sm.loadState()
+	if err != nil {
+		sm.logger.Warn("Failed to load script state", zap.Error(err))
+		state = make(map[string]*ScriptRun)
+	}
+
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
@@ -287,6 +301,18 @@ func (sm *ScriptManager) ListScripts() ([]*Script, error) {
 			Timeout:     30 * time.Second,
 			Environment: make(map[string]string),
 			WorkingDir:  sm.scriptDir,
+			LastRun:     state[scriptName],
+		}
+
+		header, err := parseScriptHeader(script.Script)
+		if err != nil {
+			sm.logger.Debug("Failed to parse script header", zap.String("script", scriptName), zap.Error(err))
+		} else {
+			if header.Description != "" {
+				script.Description = header.Description
+			}
+			script.Events = header.Events
+			script.RequiredEnv = header.RequiredEnv
 		}
 
 		scripts = append(scripts, script)
@@ -385,9 +411,10 @@ func (sm *ScriptManager) ExecuteScriptsForEvent(ctx context.Context, event Scrip
 		return err
 	}
 
-	// Execute all enabled scripts - extensions can implement their own filtering
+	// Execute enabled scripts bound to the event; scripts without an @events
+	// header run for every event
 	for _, script := range scripts {
-		if script.Enabled {
+		if script.Enabled && script.HandlesEvent(event) {
 			if err := sm.ExecuteScript(ctx, script, scriptCtx); err != nil {
 				sm.logger.Error("Script execution failed", zap.String("script", script.Name), zap.Error(err))
 				// Continue with other scripts even if one fails
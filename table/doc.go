@@ -7,6 +7,11 @@
 //   - Terminal Integration: Integration with terminal utilities for styling
 //   - Customizable: Configurable separators, alignment, and styling
 //   - Output Control: Support for different output writers
+//   - Column Formatters: Sizes, durations and timestamps in a readable form
+//   - Conditional Styling: Style cells by row, column and value, such as status colors
+//   - Groups and Totals: Grouped rows, footers and count, sum and average aggregates
+//   - Interactive View: Scroll, sort and filter large tables in the terminal
+//   - Terminal Fitting: Columns shrink to the terminal width by priority, truncating or wrapping cells
 //
 // Example:
 //   tbl := table.New()
@@ -0,0 +1,235 @@
+package table
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// Overflow says how a cell wider than its column is shown
+type Overflow int
+
+const (
+	// OverflowDefault wraps when SetAutoWrapText is on and truncates
+	// otherwise
+	OverflowDefault Overflow = iota
+	// OverflowTruncate cuts the cell short and ends it with Ellipsis
+	OverflowTruncate
+	// OverflowWrap breaks the cell into lines at spaces, and inside words
+	// such as URLs that are wider than the column
+	OverflowWrap
+)
+
+// Ellipsis ends truncated cells
+const Ellipsis = "…"
+
+// minColumnWidth is the narrowest a column shrinks to by default
+const minColumnWidth = 3
+
+// wrapBreakpoints are where words wider than a column may be broken, so
+// URLs and paths wrap at their separators
+const wrapBreakpoints = "/-_?&"
+
+// ColumnConfig sets how a column fits in the table width
+type ColumnConfig struct {
+	// MaxWidth caps the column even when there is room; 0 means no cap
+	MaxWidth int
+	// MinWidth is the narrowest the column shrinks to. It defaults to the
+	// header's width, or minColumnWidth if that is less
+	MinWidth int
+	// Overflow is how cells wider than the column are shown
+	Overflow Overflow
+	// Priority orders shrinking: columns with a lower priority shrink
+	// first, so IDs and names can stay whole while descriptions and URLs
+	// give way
+	Priority int
+}
+
+// SetMaxWidth sets the width the table fits in. The default, 0, fits the
+// terminal when the table is written to one and leaves it unbounded
+// otherwise, so piped output is never cut. A negative width turns fitting
+// off
+func (t *Table) SetMaxWidth(width int) {
+	t.maxWidth = width
+}
+
+// SetColumnConfig sets how a column, counted from 0, fits in the table
+// width
+func (t *Table) SetColumnConfig(column int, config ColumnConfig) {
+	if t.columns == nil {
+		t.columns = map[int]ColumnConfig{}
+	}
+	t.columns[column] = config
+}
+
+// availableWidth returns the width the table fits in, or 0 when unbounded
+func (t *Table) availableWidth() int {
+	if t.maxWidth != 0 {
+		if t.maxWidth < 0 {
+			return 0
+		}
+		return t.maxWidth
+	}
+	// The size is read at render time, so tables drawn after a resize fit
+	// the new width
+	if terminal.IsTerminalWriter(t.output) {
+		return terminal.Width()
+	}
+	return 0
+}
+
+// fitColumns shrinks the column widths to their MaxWidth and then, lowest
+// priority first, until the table fits the available width. Within a
+// priority the widest column gives way first
+func (t *Table) fitColumns() {
+	for i := range t.widths {
+		if max := t.columns[i].MaxWidth; max > 0 && t.widths[i] > max {
+			t.widths[i] = max
+		}
+	}
+
+	limit := t.availableWidth()
+	if limit <= 0 || len(t.widths) == 0 {
+		return
+	}
+
+	excess := t.totalWidth() - limit
+	if excess <= 0 {
+		return
+	}
+
+	// Group the columns by priority, lowest first
+	byPriority := map[int][]int{}
+	var priorities []int
+	for i := range t.widths {
+		p := t.columns[i].Priority
+		if _, ok := byPriority[p]; !ok {
+			priorities = append(priorities, p)
+		}
+		byPriority[p] = append(byPriority[p], i)
+	}
+	sort.Ints(priorities)
+
+	for _, p := range priorities {
+		group := byPriority[p]
+		for excess > 0 {
+			widest := -1
+			for _, i := range group {
+				if t.widths[i] > t.minWidth(i) && (widest < 0 || t.widths[i] > t.widths[widest]) {
+					widest = i
+				}
+			}
+			if widest < 0 {
+				break
+			}
+			t.widths[widest]--
+			excess--
+		}
+	}
+}
+
+// totalWidth returns the width of a line with the current column widths
+func (t *Table) totalWidth() int {
+	width := 0
+	for _, w := range t.widths {
+		width += w
+	}
+	if t.border {
+		// "| " before each cell, " " after it and the closing "|"
+		return width + 3*len(t.widths) + 1
+	}
+	return width + 2*(len(t.widths)-1)
+}
+
+// minWidth returns the narrowest column i shrinks to
+func (t *Table) minWidth(i int) int {
+	if min := t.columns[i].MinWidth; min > 0 {
+		return min
+	}
+	min := minColumnWidth
+	if i < len(t.headers) && len(t.headers[i]) > min {
+		min = len(t.headers[i])
+	}
+	return min
+}
+
+// cellLines returns the lines of a cell fitted to column i
+func (t *Table) cellLines(i int, text string) []string {
+	if i >= len(t.widths) {
+		return []string{text}
+	}
+	width := t.widths[i]
+	if t.visibleLength(text) <= width {
+		return []string{text}
+	}
+
+	overflow := t.columns[i].Overflow
+	if overflow == OverflowDefault {
+		overflow = OverflowTruncate
+		if t.wrap {
+			overflow = OverflowWrap
+		}
+	}
+	if overflow == OverflowWrap {
+		return strings.Split(ansi.Wrap(text, width, wrapBreakpoints), "\n")
+	}
+	return []string{truncate(text, width)}
+}
+
+// truncate cuts text to width, ending it with Ellipsis
+func truncate(text string, width int) string {
+	return ansi.Truncate(text, width, Ellipsis)
+}
+
+// layoutRow fits each cell of row to its column and pads it, returning
+// one slice of cells per line the row takes
+func (t *Table) layoutRow(row []string) [][]string {
+	cells := make([][]string, len(t.headers))
+	height := 1
+	for i := range cells {
+		var text string
+		if i < len(row) {
+			text = row[i]
+		}
+		cells[i] = t.cellLines(i, text)
+		if len(cells[i]) > height {
+			height = len(cells[i])
+		}
+	}
+
+	lines := make([][]string, height)
+	for n := range lines {
+		lines[n] = make([]string, len(cells))
+		for i, cell := range cells {
+			var text string
+			if n < len(cell) {
+				text = cell[n]
+			}
+			lines[n][i] = t.pad(i, text, t.columnAlignment(i))
+		}
+	}
+	return lines
+}
+
+// pad pads text to the width of column i with the given alignment
+func (t *Table) pad(i int, text string, align int) string {
+	if i >= len(t.widths) {
+		return text
+	}
+	padding := t.widths[i] - t.visibleLength(text)
+	if padding <= 0 {
+		return text
+	}
+
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", padding) + text
+	case AlignCenter:
+		left := padding / 2
+		return strings.Repeat(" ", left) + text + strings.Repeat(" ", padding-left)
+	default:
+		return text + strings.Repeat(" ", padding)
+	}
+}
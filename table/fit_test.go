@@ -0,0 +1,133 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const longURL = "https://gateway.example.com/apis/payments/v2/transactions/export"
+
+func fitTable(buf *bytes.Buffer, width int) *Table {
+	table := NewWithWriter(buf)
+	table.SetMaxWidth(width)
+	table.SetHeaders([]string{"ID", "Name", "URL"})
+	table.AddRow([]string{"api-1", "Payments", longURL})
+	table.AddRow([]string{"api-2", "Users", "https://example.com"})
+	return table
+}
+
+func renderLines(t *testing.T, table *Table, buf *bytes.Buffer) []string {
+	t.Helper()
+	if err := table.Render(); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestFit_Truncate(t *testing.T) {
+	var buf bytes.Buffer
+	table := fitTable(&buf, 40)
+	lines := renderLines(t, table, &buf)
+
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		if width := ansi.StringWidth(line); width != 40 {
+			t.Errorf("Expected lines 40 wide, got %d: %q", width, line)
+		}
+	}
+	// ID and Name keep their width; URL gives way
+	if !strings.HasPrefix(lines[1], "api-1  Payments  https://gateway.exampl") || !strings.HasSuffix(lines[1], Ellipsis) {
+		t.Errorf("Expected the URL truncated with an ellipsis, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "https://example.com") {
+		t.Errorf("Expected short cells to stay whole, got %q", lines[2])
+	}
+}
+
+func TestFit_Wrap(t *testing.T) {
+	var buf bytes.Buffer
+	table := fitTable(&buf, 40)
+	table.SetAutoWrapText(true)
+	lines := renderLines(t, table, &buf)
+
+	if len(lines) != 6 {
+		t.Fatalf("Expected the URL wrapped over 4 lines, got:\n%s", buf.String())
+	}
+	var url string
+	for _, line := range lines[1:5] {
+		if ansi.StringWidth(line) > 40 {
+			t.Errorf("Expected lines at most 40 wide, got %q", line)
+		}
+		url += strings.TrimSpace(line[len("api-1  Payments  "):])
+	}
+	if url != longURL {
+		t.Errorf("Expected the wrapped lines to hold the URL, got %q", url)
+	}
+	if !strings.HasPrefix(lines[2], strings.Repeat(" ", len("api-1  Payments  "))) {
+		t.Errorf("Expected continuation lines to leave other columns empty, got %q", lines[2])
+	}
+}
+
+func TestFit_Priority(t *testing.T) {
+	var buf bytes.Buffer
+	table := NewWithWriter(&buf)
+	table.SetMaxWidth(30)
+	table.SetHeaders([]string{"Name", "Description", "Owner"})
+	table.SetColumnConfig(0, ColumnConfig{Priority: 2})
+	table.SetColumnConfig(1, ColumnConfig{MinWidth: 5})
+	table.SetColumnConfig(2, ColumnConfig{Priority: 1})
+	table.AddRow([]string{"payments", "Handles card payments and refunds", "team-payments"})
+	lines := renderLines(t, table, &buf)
+
+	// Description has the lowest priority, so it shrinks to its minimum
+	// before Owner or Name give way
+	if !strings.Contains(lines[1], "Hand"+Ellipsis) {
+		t.Errorf("Expected the description at its minimum, got %q", lines[1])
+	}
+	if !strings.Contains(lines[0], "DESC"+Ellipsis) {
+		t.Errorf("Expected the header truncated, got %q", lines[0])
+	}
+	if ansi.StringWidth(lines[1]) != 30 {
+		t.Errorf("Expected lines 30 wide, got %q", lines[1])
+	}
+}
+
+func TestFit_MaxWidth(t *testing.T) {
+	var buf bytes.Buffer
+	table := fitTable(&buf, -1)
+	table.SetColumnConfig(2, ColumnConfig{MaxWidth: 20, Overflow: OverflowTruncate})
+	lines := renderLines(t, table, &buf)
+
+	if !strings.HasSuffix(lines[1], "https://gateway.exa"+Ellipsis) {
+		t.Errorf("Expected the URL capped at 20, got %q", lines[1])
+	}
+}
+
+func TestFit_Unbounded(t *testing.T) {
+	// Output that isn't a terminal is never cut
+	var buf bytes.Buffer
+	table := fitTable(&buf, 0)
+	lines := renderLines(t, table, &buf)
+
+	if !strings.Contains(lines[1], longURL) {
+		t.Errorf("Expected the whole URL, got %q", lines[1])
+	}
+}
+
+func TestFit_Borders(t *testing.T) {
+	var buf bytes.Buffer
+	table := fitTable(&buf, 40)
+	table.SetBorder(true)
+	lines := renderLines(t, table, &buf)
+
+	for _, line := range lines {
+		if width := ansi.StringWidth(line); width != 40 {
+			t.Errorf("Expected lines 40 wide, got %d: %q", width, line)
+		}
+	}
+}
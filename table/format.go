@@ -0,0 +1,149 @@
+package table
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// Column alignments for SetAlignment
+const (
+	AlignLeft = iota
+	AlignCenter
+	AlignRight
+)
+
+// Formatter turns a cell's value into what is shown, such as a byte count
+// into "1.5 MiB". Values a formatter cannot parse are shown as they are
+type Formatter func(value string) string
+
+// SetColumnFormatter sets the formatter of a column, counted from 0. It is
+// applied at render time, before any StyleFunc
+func (t *Table) SetColumnFormatter(column int, formatter Formatter) {
+	if t.formatters == nil {
+		t.formatters = map[int]Formatter{}
+	}
+	t.formatters[column] = formatter
+}
+
+// FormatDuration shows durations such as "3723s", "1h2m3.5s" or a number
+// of seconds at a precision that suits their length: "250ms", "4.2s",
+// "3m12s", "2h3m" or "3d4h"
+func FormatDuration() Formatter {
+	return func(value string) string {
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			seconds, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return value
+			}
+			d = time.Duration(seconds * float64(time.Second))
+		}
+		return humanDuration(d)
+	}
+}
+
+// FormatSize shows byte counts in binary units, such as "1.5 MiB"
+func FormatSize() Formatter {
+	return func(value string) string {
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return value
+		}
+		return humanSize(n)
+	}
+}
+
+// FormatTime shows RFC 3339 timestamps and Unix times in seconds in the
+// local time zone with layout, such as time.DateTime
+func FormatTime(layout string) Formatter {
+	return func(value string) string {
+		value = strings.TrimSpace(value)
+		if ts, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return ts.Local().Format(layout)
+		}
+		if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Unix(secs, 0).Local().Format(layout)
+		}
+		return value
+	}
+}
+
+// humanDuration formats d at a precision that suits its length
+func humanDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	switch {
+	case d < time.Second:
+		return sign + d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return sign + strconv.FormatFloat(d.Seconds(), 'f', 1, 64) + "s"
+	case d < time.Hour:
+		d = d.Round(time.Second)
+		return fmt.Sprintf("%s%dm%ds", sign, d/time.Minute, d%time.Minute/time.Second)
+	case d < 24*time.Hour:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%s%dh%dm", sign, d/time.Hour, d%time.Hour/time.Minute)
+	default:
+		d = d.Round(time.Hour)
+		return fmt.Sprintf("%s%dd%dh", sign, d/(24*time.Hour), d%(24*time.Hour)/time.Hour)
+	}
+}
+
+// humanSize formats a byte count in binary units
+func humanSize(n float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	i := 0
+	for math.Abs(n) >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	if i == 0 {
+		return strconv.FormatFloat(n, 'f', -1, 64) + " B"
+	}
+	return strconv.FormatFloat(n, 'f', 1, 64) + " " + units[i]
+}
+
+// alignmentOf converts a tablewriter alignment
+func alignmentOf(align tw.Align) int {
+	switch align {
+	case tw.AlignRight:
+		return AlignRight
+	case tw.AlignCenter:
+		return AlignCenter
+	default:
+		return AlignLeft
+	}
+}
+
+// columnAlignment returns the alignment of column i
+func (t *Table) columnAlignment(i int) int {
+	if i < len(t.alignment) {
+		return t.alignment[i]
+	}
+	return AlignLeft
+}
+
+// headerAlign returns the alignment of the header of column i, which
+// follows the column unless SetHeaderAlignment says otherwise
+func (t *Table) headerAlign(i int) int {
+	if i < len(t.headerAlignment) {
+		return t.headerAlignment[i]
+	}
+	return t.columnAlignment(i)
+}
+
+// formatCell applies the formatter of column col to value
+func (t *Table) formatCell(col int, value string) string {
+	if formatter, ok := t.formatters[col]; ok {
+		return formatter(value)
+	}
+	return value
+}
@@ -0,0 +1,127 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+func TestAlignment(t *testing.T) {
+	for _, border := range []bool{false, true} {
+		var buf bytes.Buffer
+		table := NewWithWriter(&buf)
+		table.SetBorder(border)
+		table.SetHeaders([]string{"Product", "Price", "Stock"})
+		table.SetAlignment([]int{AlignLeft, AlignRight, AlignCenter})
+		table.AddRow([]string{"Laptop", "$999.99", "15"})
+		table.AddRow([]string{"Mouse", "$9.99", "1500"})
+		if err := table.Render(); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+
+		output := buf.String()
+		for _, want := range []string{"PRODUCT", "Laptop ", "  PRICE", "  $9.99", " 15 ", "STOCK"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected %q with border=%v, got:\n%s", want, border, output)
+			}
+		}
+	}
+}
+
+func TestSetColumnAlignment_Headers(t *testing.T) {
+	var buf bytes.Buffer
+	table := NewWithWriter(&buf)
+	table.SetHeaders([]string{"Name", "Count"})
+	table.SetColumnAlignment([]tw.Align{tw.AlignLeft, tw.AlignRight})
+	table.SetHeaderAlignment([]tw.Align{tw.AlignLeft, tw.AlignLeft})
+	table.AddRow([]string{"requests", "1234567"})
+	if err := table.Render(); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if lines[0] != "NAME      COUNT  " {
+		t.Errorf("Expected left-aligned headers, got %q", lines[0])
+	}
+	if lines[1] != "requests  1234567" {
+		t.Errorf("Expected right-aligned counts, got %q", lines[1])
+	}
+}
+
+func TestColumnFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	table := NewWithWriter(&buf)
+	table.SetHeaders([]string{"Name", "Size", "Took"})
+	table.SetAlignment([]int{AlignLeft, AlignRight, AlignRight})
+	table.SetColumnFormatter(1, FormatSize())
+	table.SetColumnFormatter(2, FormatDuration())
+	table.AddRow([]string{"bundle.tar", "1572864", "3723s"})
+	table.AddRow([]string{"notes.txt", "512", "n/a"})
+	if err := table.Render(); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if lines[1] != "bundle.tar  1.5 MiB  1h2m" {
+		t.Errorf("Expected formatted values, got %q", lines[1])
+	}
+	if lines[2] != "notes.txt     512 B   n/a" {
+		t.Errorf("Expected unparsable values kept, got %q", lines[2])
+	}
+	if table.rows[0][1] != "1572864" {
+		t.Errorf("Expected rows to keep their values, got %q", table.rows[0][1])
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	format := FormatDuration()
+	tests := map[string]string{
+		"250ms":    "250ms",
+		"4.23":     "4.2s",
+		"192s":     "3m12s",
+		"2h3m10s":  "2h3m",
+		"76h":      "3d4h",
+		"-90s":     "-1m30s",
+		"whenever": "whenever",
+	}
+	for value, want := range tests {
+		if got := format(value); got != want {
+			t.Errorf("FormatDuration(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	format := FormatSize()
+	tests := map[string]string{
+		"0":          "0 B",
+		"1023":       "1023 B",
+		"1024":       "1.0 KiB",
+		"5368709120": "5.0 GiB",
+		"big":        "big",
+	}
+	for value, want := range tests {
+		if got := format(value); got != want {
+			t.Errorf("FormatSize(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	format := FormatTime(time.RFC3339)
+	ts := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	want := ts.Local().Format(time.RFC3339)
+
+	if got := format("2024-03-01T12:00:00Z"); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := format("1709294400"); got != want {
+		t.Errorf("Expected %q from a Unix time, got %q", want, got)
+	}
+	if got := format("yesterday"); got != "yesterday" {
+		t.Errorf("Expected unparsable values kept, got %q", got)
+	}
+}
@@ -0,0 +1,244 @@
+package table
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Aggregate summarizes a column in group summaries and the footer
+type Aggregate int
+
+const (
+	// AggregateCount counts the non-empty cells
+	AggregateCount Aggregate = iota + 1
+	// AggregateSum adds up the numeric cells
+	AggregateSum
+	// AggregateAvg averages the numeric cells
+	AggregateAvg
+)
+
+// group is a run of rows sharing the value of the grouping column
+type group struct {
+	title      string
+	start, end int
+	summary    []string
+}
+
+// SetGroupBy groups rows by the value of a column, counted from 0. Groups
+// keep the order their first row was added in, and each starts with a line
+// naming it. With aggregates, each ends with a summary line
+func (t *Table) SetGroupBy(column int) {
+	t.grouped = true
+	t.groupBy = column
+}
+
+// SetAggregate summarizes a column, counted from 0, in the footer and in
+// each group's summary line. Sums and averages are shown with the column's
+// formatter, so a column of byte counts sums to "1.5 GiB"
+func (t *Table) SetAggregate(column int, aggregate Aggregate) {
+	if t.aggregates == nil {
+		t.aggregates = map[int]Aggregate{}
+	}
+	t.aggregates[column] = aggregate
+}
+
+// display returns a copy of the table holding the cells as shown: rows in
+// group order, formatted and styled, with group summaries and the footer
+func (t *Table) display() *Table {
+	shown := *t
+	shown.groups = nil
+	shown.footerRow = nil
+
+	order := make([]int, len(t.rows))
+	for i := range order {
+		order[i] = i
+	}
+	if t.grouped {
+		order = nil
+		var keys []string
+		members := map[string][]int{}
+		for i, row := range t.rows {
+			key := cell(row, t.groupBy)
+			if _, ok := members[key]; !ok {
+				keys = append(keys, key)
+			}
+			members[key] = append(members[key], i)
+		}
+
+		for _, key := range keys {
+			g := group{title: t.groupTitle(key, len(members[key])), start: len(order)}
+			order = append(order, members[key]...)
+			g.end = len(order)
+			if len(t.aggregates) > 0 {
+				g.summary = t.aggregateRow(members[key], nil)
+			}
+			shown.groups = append(shown.groups, g)
+		}
+	}
+
+	shown.rows = make([][]string, len(order))
+	for n, r := range order {
+		row := make([]string, len(t.rows[r]))
+		for c, value := range t.rows[r] {
+			row[c] = t.styleCell(r, c, t.formatCell(c, value))
+		}
+		shown.rows[n] = row
+	}
+
+	if len(t.footer) > 0 || len(t.aggregates) > 0 {
+		shown.footerRow = t.aggregateRow(order, t.footer)
+	}
+	return &shown
+}
+
+// groupTitle names a group by its column and value
+func (t *Table) groupTitle(value string, rows int) string {
+	if value == "" {
+		value = "(none)"
+	}
+	if t.groupBy < len(t.headers) {
+		value = strings.ToUpper(t.headers[t.groupBy]) + ": " + value
+	}
+	return fmt.Sprintf("%s (%d)", value, rows)
+}
+
+// aggregateRow returns a summary line for the given rows, starting from
+// cells and filling in the aggregated columns
+func (t *Table) aggregateRow(rows []int, cells []string) []string {
+	summary := make([]string, len(t.headers))
+	copy(summary, cells)
+	for col, aggregate := range t.aggregates {
+		if col < len(summary) {
+			summary[col] = t.aggregate(col, aggregate, rows)
+		}
+	}
+	return summary
+}
+
+// aggregate summarizes column col over rows
+func (t *Table) aggregate(col int, aggregate Aggregate, rows []int) string {
+	var count, numbers int
+	var sum float64
+	for _, r := range rows {
+		value := strings.TrimSpace(cell(t.rows[r], col))
+		if value == "" {
+			continue
+		}
+		count++
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			sum += n
+			numbers++
+		}
+	}
+
+	switch aggregate {
+	case AggregateCount:
+		return strconv.Itoa(count)
+	case AggregateSum:
+		return t.formatCell(col, strconv.FormatFloat(sum, 'f', -1, 64))
+	case AggregateAvg:
+		if numbers == 0 {
+			return ""
+		}
+		avg := math.Round(sum/float64(numbers)*100) / 100
+		return t.formatCell(col, strconv.FormatFloat(avg, 'f', -1, 64))
+	default:
+		return ""
+	}
+}
+
+// measuredRows returns the lines whose cells set the column widths
+func (t *Table) measuredRows() [][]string {
+	rows := t.rows
+	if len(t.groups) > 0 || t.footerRow != nil {
+		rows = append([][]string{}, t.rows...)
+		for _, g := range t.groups {
+			if g.summary != nil {
+				rows = append(rows, g.summary)
+			}
+		}
+		if t.footerRow != nil {
+			rows = append(rows, t.footerRow)
+		}
+	}
+	return rows
+}
+
+// renderBody renders the rows, with group titles and summaries, and the
+// footer using the functions of the current mode
+func (t *Table) renderBody(renderRow func([]string) error, renderTitle func(string) error) error {
+	if len(t.groups) == 0 {
+		for _, row := range t.rows {
+			if err := renderRow(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, g := range t.groups {
+		if err := renderTitle(g.title); err != nil {
+			return err
+		}
+		for _, row := range t.rows[g.start:g.end] {
+			if err := renderRow(row); err != nil {
+				return err
+			}
+		}
+		if g.summary != nil {
+			if err := renderRow(t.paint(g.summary, t.terminal.Gray)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if t.footerRow == nil {
+		return nil
+	}
+	if t.border {
+		if err := t.renderHeaderSeparator(); err != nil {
+			return err
+		}
+	}
+	return renderRow(t.paint(t.footerRow, t.terminal.Blue))
+}
+
+// paint colors the non-empty cells of a summary line
+func (t *Table) paint(cells []string, color func(string) string) []string {
+	painted := make([]string, len(cells))
+	for i, c := range cells {
+		if c != "" {
+			c = color(c)
+		}
+		painted[i] = c
+	}
+	return painted
+}
+
+// renderSimpleTitle renders a group title without borders
+func (t *Table) renderSimpleTitle(title string) error {
+	_, err := fmt.Fprintln(t.output, t.terminal.Cyan(t.fitTitle(title, t.totalWidth())))
+	return err
+}
+
+// renderTitleWithBorders renders a group title across the columns
+func (t *Table) renderTitleWithBorders(title string) error {
+	// The title takes the width of the cells and the separators between
+	// them, inside the outer separators and their padding
+	width := t.totalWidth() - 4
+	title = t.fitTitle(title, width)
+	if padding := width - t.visibleLength(title); padding > 0 {
+		title += strings.Repeat(" ", padding)
+	}
+	_, err := fmt.Fprintln(t.output, t.columnSeparator+" "+t.terminal.Cyan(title)+" "+t.columnSeparator)
+	return err
+}
+
+// fitTitle truncates a title wider than width
+func (t *Table) fitTitle(title string, width int) string {
+	if width > 0 && t.visibleLength(title) > width {
+		return truncate(title, width)
+	}
+	return title
+}
@@ -0,0 +1,115 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func groupTable(buf *bytes.Buffer) *Table {
+	table := NewWithWriter(buf)
+	table.SetHeaders([]string{"Name", "Status", "Requests"})
+	table.AddRow([]string{"payments", "active", "120"})
+	table.AddRow([]string{"users", "inactive", "9"})
+	table.AddRow([]string{"reports", "active", "1500"})
+	table.AddRow([]string{"audit", "", "n/a"})
+	return table
+}
+
+func TestSetFooter_Render(t *testing.T) {
+	var buf bytes.Buffer
+	table := groupTable(&buf)
+	table.SetFooter([]string{"Total", "", "1629"})
+	if err := table.Render(); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 6 || lines[5] != "Total               1629    " {
+		t.Errorf("Expected the footer last, got:\n%s", buf.String())
+	}
+}
+
+func TestSetAggregate(t *testing.T) {
+	var buf bytes.Buffer
+	table := groupTable(&buf)
+	table.SetFooter([]string{"Total"})
+	table.SetAggregate(1, AggregateCount)
+	table.SetAggregate(2, AggregateSum)
+	if err := table.Render(); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if got := strings.Fields(lines[len(lines)-1]); strings.Join(got, " ") != "Total 3 1629" {
+		t.Errorf("Expected count and sum in the footer, got %q", lines[len(lines)-1])
+	}
+
+	table.SetAggregate(2, AggregateAvg)
+	if got := table.aggregate(2, AggregateAvg, []int{0, 1, 2, 3}); got != "543" {
+		t.Errorf("Expected the average of the numeric cells, got %q", got)
+	}
+	if got := table.aggregate(2, AggregateAvg, []int{3}); got != "" {
+		t.Errorf("Expected no average without numbers, got %q", got)
+	}
+}
+
+func TestSetGroupBy(t *testing.T) {
+	var buf bytes.Buffer
+	table := groupTable(&buf)
+	table.SetGroupBy(1)
+	table.SetAggregate(2, AggregateSum)
+	table.SetColumnFormatter(2, func(value string) string { return value + " req" })
+	if err := table.Render(); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		got = append(got, strings.Join(strings.Fields(line), " "))
+	}
+	want := []string{
+		"NAME STATUS REQUESTS",
+		"STATUS: active (2)",
+		"payments active 120 req",
+		"reports active 1500 req",
+		"1620 req",
+		"STATUS: inactive (1)",
+		"users inactive 9 req",
+		"9 req",
+		"STATUS: (none) (1)",
+		"audit n/a req",
+		"0 req",
+		"1629 req",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if table.rows[1][0] != "users" {
+		t.Errorf("Expected rows to keep the order they were added in, got %v", table.rows)
+	}
+}
+
+func TestSetGroupBy_Borders(t *testing.T) {
+	var buf bytes.Buffer
+	table := groupTable(&buf)
+	table.SetBorder(true)
+	table.SetGroupBy(1)
+	table.SetFooter([]string{"Total", "", "1629"})
+	if err := table.Render(); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for _, line := range lines {
+		if len(line) != len(lines[0]) {
+			t.Errorf("Expected every line as wide as the border, got %q", line)
+		}
+	}
+	if !strings.HasPrefix(lines[2], "| STATUS: active (2) ") {
+		t.Errorf("Expected a group title inside the borders, got %q", lines[2])
+	}
+	if !strings.HasPrefix(lines[len(lines)-3], "+--") || !strings.HasPrefix(lines[len(lines)-2], "| Total") {
+		t.Errorf("Expected the footer below a rule, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,312 @@
+package table
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// pagerHelp lists the keys of the interactive view
+const pagerHelp = "↑/↓ scroll · 1-9 sort · / search · q quit"
+
+// RenderInteractive shows the table in a scrollable view where rows can be
+// sorted by pressing a column's number, pressing it again to reverse, and
+// filtered by typing after /. It renders the table as Render does when
+// output or input is not a terminal
+func (t *Table) RenderInteractive(ctx context.Context) error {
+	if len(t.headers) == 0 {
+		return fmt.Errorf("no headers set")
+	}
+
+	if !terminal.IsTerminalWriter(t.output) || !terminal.IsTerminal(os.Stdin.Fd()) {
+		return t.Render()
+	}
+
+	model := newPagerModel(t)
+	options := append([]tea.ProgramOption{tea.WithContext(ctx), tea.WithOutput(t.output)}, terminal.AltScreenOptions()...)
+	if _, err := tea.NewProgram(model, options...).Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return fmt.Errorf("failed to run interactive table: %w", err)
+	}
+	return ctx.Err()
+}
+
+// pagerModel is the Bubble Tea model of the interactive view
+type pagerModel struct {
+	table *Table
+
+	width  int
+	height int
+	offset int
+
+	// sortColumn is the column rows are sorted by, or -1
+	sortColumn int
+	sortDesc   bool
+
+	filter    string
+	searching bool
+
+	// header holds the lines kept at the top and lines the rows scrolling
+	// below them, rendered again when the rows, their order or the width
+	// change
+	header []string
+	lines  []string
+	shown  int
+}
+
+// newPagerModel returns a view of t sized to the terminal
+func newPagerModel(t *Table) *pagerModel {
+	m := &pagerModel{table: t, sortColumn: -1, height: 24}
+	if t.terminal != nil {
+		m.width, m.height = t.terminal.GetSize()
+	}
+	m.layout()
+	return m
+}
+
+// Init starts the view
+func (m *pagerModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles keys and resizes
+func (m *pagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.layout()
+	case tea.KeyMsg:
+		if m.searching {
+			return m, m.search(msg)
+		}
+		return m, m.key(msg)
+	}
+	return m, nil
+}
+
+// key handles a key while browsing
+func (m *pagerModel) key(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "esc":
+		// Clear the filter first, then quit
+		if m.filter == "" {
+			return tea.Quit
+		}
+		m.filter = ""
+		m.layout()
+	case "up", "k":
+		m.scroll(-1)
+	case "down", "j":
+		m.scroll(1)
+	case "pgup", "b":
+		m.scroll(-m.page())
+	case "pgdown", "f", " ":
+		m.scroll(m.page())
+	case "home", "g":
+		m.offset = 0
+	case "end", "G":
+		m.scroll(len(m.lines))
+	case "/":
+		m.searching = true
+	case "0":
+		m.sortColumn, m.sortDesc = -1, false
+		m.layout()
+	default:
+		if n, err := strconv.Atoi(msg.String()); err == nil && n >= 1 && n <= len(m.table.headers) {
+			if m.sortColumn == n-1 {
+				m.sortDesc = !m.sortDesc
+			} else {
+				m.sortColumn, m.sortDesc = n-1, false
+			}
+			m.layout()
+		}
+	}
+	return nil
+}
+
+// search handles a key while typing a filter, which applies as it is typed
+func (m *pagerModel) search(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEnter:
+		m.searching = false
+		return nil
+	case tea.KeyEsc:
+		m.searching = false
+		m.filter = ""
+	case tea.KeyBackspace:
+		if r := []rune(m.filter); len(r) > 0 {
+			m.filter = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+	default:
+		return nil
+	}
+	m.offset = 0
+	m.layout()
+	return nil
+}
+
+// page returns how many lines of rows fit on the screen
+func (m *pagerModel) page() int {
+	// The header stays at the top and the status line at the bottom
+	if page := m.height - len(m.header) - 1; page > 1 {
+		return page
+	}
+	return 1
+}
+
+// scroll moves the view by n lines, staying within the rows
+func (m *pagerModel) scroll(n int) {
+	m.offset += n
+	if max := len(m.lines) - m.page(); m.offset > max {
+		m.offset = max
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// layout renders the filtered and sorted rows for the current width
+func (m *pagerModel) layout() {
+	rows := m.rows()
+	m.shown = len(rows)
+
+	view := *m.table
+	view.rows = rows
+	if m.width > 0 && view.maxWidth >= 0 {
+		view.maxWidth = m.width
+	}
+
+	var buf bytes.Buffer
+	view.output = &buf
+	view.Render()
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	// The header is the top border, the headers and the line under them
+	fixed := 1
+	if view.border {
+		fixed = 2
+		if view.headerLine {
+			fixed = 3
+		}
+	}
+	if fixed > len(lines) {
+		fixed = len(lines)
+	}
+	m.header, m.lines = lines[:fixed], lines[fixed:]
+	m.scroll(0)
+}
+
+// rows returns the rows matching the filter in the chosen order
+func (m *pagerModel) rows() [][]string {
+	filter := strings.ToLower(m.filter)
+	var rows [][]string
+	for _, row := range m.table.rows {
+		if filter == "" || rowContains(row, filter) {
+			rows = append(rows, row)
+		}
+	}
+
+	if col := m.sortColumn; col >= 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			a, b := cell(rows[i], col), cell(rows[j], col)
+			if m.sortDesc {
+				return lessValue(b, a)
+			}
+			return lessValue(a, b)
+		})
+	}
+	return rows
+}
+
+// View draws the visible lines and a status line
+func (m *pagerModel) View() string {
+	var b strings.Builder
+	for _, line := range m.header {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	end := m.offset + m.page()
+	if end > len(m.lines) {
+		end = len(m.lines)
+	}
+	for _, line := range m.lines[m.offset:end] {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	b.WriteString(m.status())
+	return b.String()
+}
+
+// status describes the position, order and filter, or shows the filter
+// being typed
+func (m *pagerModel) status() string {
+	if m.searching {
+		return "/" + m.filter + "█"
+	}
+
+	parts := []string{fmt.Sprintf("%d rows", m.shown)}
+	if m.shown != len(m.table.rows) {
+		parts[0] = fmt.Sprintf("%d of %d rows", m.shown, len(m.table.rows))
+	}
+	if m.sortColumn >= 0 {
+		arrow := "↑"
+		if m.sortDesc {
+			arrow = "↓"
+		}
+		parts = append(parts, "sort: "+strings.ToUpper(m.table.headers[m.sortColumn])+" "+arrow)
+	}
+	if m.filter != "" {
+		parts = append(parts, "filter: "+m.filter)
+	}
+	parts = append(parts, pagerHelp)
+
+	status := strings.Join(parts, " · ")
+	if m.table.terminal != nil {
+		status = m.table.terminal.Gray(status)
+	}
+	return status
+}
+
+// rowContains reports whether any cell of row contains filter, which is
+// lower case
+func rowContains(row []string, filter string) bool {
+	for _, value := range row {
+		if strings.Contains(strings.ToLower(value), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// cell returns column col of row, or "" for short rows
+func cell(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
+
+// lessValue orders numbers numerically and other values without regard to
+// case
+func lessValue(a, b string) bool {
+	x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
@@ -0,0 +1,138 @@
+package table
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+func pagerTable() *Table {
+	table := NewWithWriter(&bytes.Buffer{})
+	table.SetTerminal(&terminal.Terminal{Width: 80, Height: 6, NoColor: true})
+	table.SetHeaders([]string{"Name", "Requests"})
+	table.AddRow([]string{"payments", "120"})
+	table.AddRow([]string{"Users", "9"})
+	table.AddRow([]string{"reports", "1500"})
+	table.AddRow([]string{"audit", "42"})
+	table.AddRow([]string{"billing", "7"})
+	return table
+}
+
+func keys(m *pagerModel, input ...string) {
+	for _, k := range input {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m.Update(msg)
+	}
+}
+
+// names returns the first column of the rows in view
+func names(m *pagerModel) []string {
+	view := strings.Split(m.View(), "\n")
+	var names []string
+	for _, line := range view[1 : len(view)-1] {
+		names = append(names, strings.Fields(line)[0])
+	}
+	return names
+}
+
+func TestPager_Scroll(t *testing.T) {
+	m := newPagerModel(pagerTable())
+
+	// 6 lines: the header, 4 rows and the status line
+	if got := names(m); strings.Join(got, ",") != "payments,Users,reports,audit" {
+		t.Errorf("Expected the first page, got %v", got)
+	}
+	keys(m, "down", "down")
+	if got := names(m); strings.Join(got, ",") != "Users,reports,audit,billing" {
+		t.Errorf("Expected scrolling to stop at the last row, got %v", got)
+	}
+	keys(m, "g")
+	if m.offset != 0 {
+		t.Errorf("Expected g to go to the top, got offset %d", m.offset)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+	if got := names(m); len(got) != 5 {
+		t.Errorf("Expected every row after a resize, got %v", got)
+	}
+}
+
+func TestPager_Sort(t *testing.T) {
+	m := newPagerModel(pagerTable())
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	keys(m, "2")
+	if got := names(m); strings.Join(got, ",") != "billing,Users,audit,payments,reports" {
+		t.Errorf("Expected a numeric sort, got %v", got)
+	}
+	keys(m, "2")
+	if got := names(m); got[0] != "reports" {
+		t.Errorf("Expected pressing again to reverse, got %v", got)
+	}
+	keys(m, "1")
+	if got := names(m); strings.Join(got, ",") != "audit,billing,payments,reports,Users" {
+		t.Errorf("Expected a case-insensitive sort, got %v", got)
+	}
+	if !strings.Contains(m.status(), "sort: NAME ↑") {
+		t.Errorf("Expected the sort in the status line, got %q", m.status())
+	}
+	keys(m, "0")
+	if got := names(m); got[0] != "payments" {
+		t.Errorf("Expected 0 to restore the order, got %v", got)
+	}
+}
+
+func TestPager_Search(t *testing.T) {
+	m := newPagerModel(pagerTable())
+
+	keys(m, "/", "i", "n", "g")
+	if !strings.HasPrefix(m.status(), "/ing") {
+		t.Errorf("Expected the filter being typed, got %q", m.status())
+	}
+	if got := names(m); strings.Join(got, ",") != "billing" {
+		t.Errorf("Expected rows matching as typed, got %v", got)
+	}
+	keys(m, "backspace", "backspace", "enter")
+	if got := names(m); strings.Join(got, ",") != "audit,billing" {
+		t.Errorf("Expected rows matching \"i\", got %v", got)
+	}
+	if !strings.Contains(m.status(), "2 of 5 rows · filter: i") {
+		t.Errorf("Expected the filter in the status line, got %q", m.status())
+	}
+
+	keys(m, "esc")
+	if m.filter != "" {
+		t.Errorf("Expected esc to clear the filter, got %q", m.filter)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd == nil {
+		t.Error("Expected esc without a filter to quit")
+	}
+}
+
+func TestRenderInteractive_NotTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	table := pagerTable()
+	table.SetOutput(&buf)
+	if err := table.RenderInteractive(context.Background()); err != nil {
+		t.Fatalf("RenderInteractive failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "reports   1500") {
+		t.Errorf("Expected static output, got %q", buf.String())
+	}
+}
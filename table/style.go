@@ -0,0 +1,79 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+// StyleFunc returns how a cell is shown, such as its value in color. row
+// counts data rows from 0 and col columns from 0. Styles are applied at
+// render time, and their ANSI codes are dropped when the terminal has no
+// color
+type StyleFunc func(row, col int, value string) string
+
+// SetStyleFunc sets the style applied to every cell; Styles combines
+// several
+func (t *Table) SetStyleFunc(fn StyleFunc) {
+	t.style = fn
+}
+
+// Styles applies each style in turn, the next one receiving what the one
+// before returned
+func Styles(fns ...StyleFunc) StyleFunc {
+	return func(row, col int, value string) string {
+		for _, fn := range fns {
+			value = fn(row, col, value)
+		}
+		return value
+	}
+}
+
+// ColumnStyle applies fn to column col only
+func ColumnStyle(col int, fn StyleFunc) StyleFunc {
+	return func(row, c int, value string) string {
+		if c != col {
+			return value
+		}
+		return fn(row, c, value)
+	}
+}
+
+// ColorValues colors cells whose value is one of values, ignoring case,
+// with a terminal color such as terminal.ColorRed
+func ColorValues(color string, values ...string) StyleFunc {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.ToLower(v)] = true
+	}
+	return func(row, col int, value string) string {
+		if !set[strings.ToLower(strings.TrimSpace(ansi.Strip(value)))] {
+			return value
+		}
+		return color + value + terminal.ColorReset
+	}
+}
+
+// StatusColors colors common status values: failures red, healthy states
+// green and states needing attention yellow
+func StatusColors() StyleFunc {
+	return Styles(
+		ColorValues(terminal.ColorRed, "error", "failed", "failure", "unhealthy", "down", "crashed"),
+		ColorValues(terminal.ColorGreen, "active", "ok", "healthy", "success", "succeeded", "up", "running", "enabled"),
+		ColorValues(terminal.ColorYellow, "pending", "warning", "degraded", "inactive", "disabled", "unknown"),
+	)
+}
+
+// styleCell applies the style to a cell, without colors when the terminal
+// has none
+func (t *Table) styleCell(row, col int, value string) string {
+	if t.style == nil {
+		return value
+	}
+	styled := t.style(row, col, value)
+	if styled != value && (t.terminal == nil || !t.terminal.SupportsColor()) {
+		styled = ansi.Strip(styled)
+	}
+	return styled
+}
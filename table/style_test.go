@@ -0,0 +1,87 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/edsonmichaque/tykctl-go/terminal"
+)
+
+func styledTable(buf *bytes.Buffer, color bool) *Table {
+	table := NewWithWriter(buf)
+	table.SetTerminal(&terminal.Terminal{Color: color, ForceTTY: true, NoColor: !color})
+	table.SetHeaders([]string{"Name", "Status"})
+	table.AddRow([]string{"payments", "active"})
+	table.AddRow([]string{"users", "Failed"})
+	table.AddRow([]string{"reports", "archived"})
+	return table
+}
+
+func TestStyleFunc(t *testing.T) {
+	var buf bytes.Buffer
+	table := styledTable(&buf, true)
+	table.SetStyleFunc(ColumnStyle(1, StatusColors()))
+	if err := table.Render(); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, terminal.ColorGreen+"active"+terminal.ColorReset) {
+		t.Errorf("Expected active in green, got %q", output)
+	}
+	if !strings.Contains(output, terminal.ColorRed+"Failed"+terminal.ColorReset) {
+		t.Errorf("Expected Failed in red, got %q", output)
+	}
+	if strings.Contains(output, "\x1b[33m") || strings.Contains(output, terminal.ColorGreen+"archived") {
+		t.Errorf("Expected other values unstyled, got %q", output)
+	}
+
+	// Colors don't count towards the column widths
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	for _, line := range lines {
+		if width := ansi.StringWidth(line); width != len("payments  archived") {
+			t.Errorf("Expected aligned lines, got width %d for %q", width, line)
+		}
+	}
+
+	if table.rows[0][1] != "active" {
+		t.Errorf("Expected rows to keep their values, got %q", table.rows[0][1])
+	}
+}
+
+func TestStyleFunc_NoColor(t *testing.T) {
+	var buf bytes.Buffer
+	table := styledTable(&buf, false)
+	table.SetStyleFunc(Styles(
+		StatusColors(),
+		func(row, col int, value string) string {
+			if row == 0 && col == 0 {
+				return "* " + value
+			}
+			return value
+		},
+	))
+	if err := table.Render(); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "\x1b[") {
+		t.Errorf("Expected no colors, got %q", output)
+	}
+	if !strings.Contains(output, "* payments  active") {
+		t.Errorf("Expected styles changing text to apply, got %q", output)
+	}
+}
+
+func TestColorValues(t *testing.T) {
+	style := ColorValues(terminal.ColorYellow, "Pending")
+	if got := style(0, 0, " pending "); got != terminal.ColorYellow+" pending "+terminal.ColorReset {
+		t.Errorf("Expected a case-insensitive match, got %q", got)
+	}
+	if got := style(0, 0, "pending review"); got != "pending review" {
+		t.Errorf("Expected whole values only, got %q", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/edsonmichaque/tykctl-go/terminal"
 	"github.com/olekukonko/tablewriter/tw"
 )
@@ -24,6 +25,22 @@ type Table struct {
 	columnSeparator string
 	rowSeparator    string
 	headerLine      bool
+	// maxWidth, columns and wrap fit the table in the terminal width
+	maxWidth int
+	columns  map[int]ColumnConfig
+	wrap     bool
+	// formatters and style are applied to cells at render time
+	formatters      map[int]Formatter
+	style           StyleFunc
+	headerAlignment []int
+	// footer, grouping and aggregates add group and summary lines
+	footer     []string
+	grouped    bool
+	groupBy    int
+	aggregates map[int]Aggregate
+	// groups and footerRow are set on the copy display returns
+	groups    []group
+	footerRow []string
 }
 
 // New creates a new table instance
@@ -60,7 +77,7 @@ func (t *Table) SetHeaders(headers []string) {
 	t.widths = make([]int, len(headers))
 	for i, header := range headers {
 		t.headers[i] = strings.ToUpper(header)
-		t.widths[i] = len(t.headers[i])
+		t.widths[i] = t.visibleLength(t.headers[i])
 	}
 }
 
@@ -71,29 +88,17 @@ func (t *Table) AddRow(row []string) {
 	// Update column widths
 	for i, cell := range row {
 		if i < len(t.widths) {
-			if len(cell) > t.widths[i] {
-				t.widths[i] = len(cell)
+			if width := t.visibleLength(cell); width > t.widths[i] {
+				t.widths[i] = width
 			}
 		}
 	}
 }
 
-// visibleLength returns the visible length of text without ANSI escape codes
+// visibleLength returns the width of text on screen, without ANSI escape
+// codes and counting wide characters twice
 func (t *Table) visibleLength(text string) int {
-	// Remove ANSI escape sequences to get the actual visible length
-	visible := text
-	for {
-		start := strings.Index(visible, "\x1b[")
-		if start == -1 {
-			break
-		}
-		end := strings.Index(visible[start:], "m")
-		if end == -1 {
-			break
-		}
-		visible = visible[:start] + visible[start+end+1:]
-	}
-	return len(visible)
+	return ansi.StringWidth(text)
 }
 
 // calculateColumnWidths calculates optimal column widths for better alignment
@@ -105,14 +110,16 @@ func (t *Table) calculateColumnWidths() {
 	// Initialize widths with header lengths (visible length only)
 	t.widths = make([]int, len(t.headers))
 	for i, header := range t.headers {
-		t.widths[i] = len(header) // headers are stored as uppercase without color
+		t.widths[i] = t.visibleLength(header) // headers are stored as uppercase without color
 	}
 
-	// Update widths based on data rows
-	for _, row := range t.rows {
+	// Update widths based on data rows, group summaries and the footer
+	for _, row := range t.measuredRows() {
 		for i, cell := range row {
-			if i < len(t.widths) && len(cell) > t.widths[i] {
-				t.widths[i] = len(cell)
+			if i < len(t.widths) {
+				if width := t.visibleLength(cell); width > t.widths[i] {
+					t.widths[i] = width
+				}
 			}
 		}
 	}
@@ -132,7 +139,8 @@ func (t *Table) AddRows(rows [][]string) {
 	}
 }
 
-// SetAlignment sets the alignment for columns
+// SetAlignment sets the alignment of each column to AlignLeft,
+// AlignCenter or AlignRight. Columns without one are left-aligned
 func (t *Table) SetAlignment(alignment []int) {
 	t.alignment = alignment
 }
@@ -148,16 +156,24 @@ func (t *Table) Render() error {
 		return fmt.Errorf("no headers set")
 	}
 
-	if t.border {
-		return t.renderWithBorders()
+	// Render a copy holding the cells as shown, so widths are measured on
+	// what is shown and the rows themselves stay as added
+	shown := t.display()
+	var err error
+	if shown.border {
+		err = shown.renderWithBorders()
+	} else {
+		err = shown.renderWithoutBorders()
 	}
-	return t.renderWithoutBorders()
+	t.widths = shown.widths
+	return err
 }
 
 // renderWithoutBorders renders the table without borders (default behavior)
 func (t *Table) renderWithoutBorders() error {
-	// Calculate column widths first
+	// Calculate column widths first, then fit them in the terminal
 	t.calculateColumnWidths()
+	t.fitColumns()
 
 	// Render headers
 	if err := t.renderSimpleHeaders(); err != nil {
@@ -165,17 +181,14 @@ func (t *Table) renderWithoutBorders() error {
 	}
 
 	// Render rows
-	for _, row := range t.rows {
-		if err := t.renderSimpleRow(row); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return t.renderBody(t.renderSimpleRow, t.renderSimpleTitle)
 }
 
 // renderWithBorders renders the table with borders
 func (t *Table) renderWithBorders() error {
+	t.calculateColumnWidths()
+	t.fitColumns()
+
 	// Render top border
 	if err := t.renderTopBorder(); err != nil {
 		return err
@@ -194,10 +207,8 @@ func (t *Table) renderWithBorders() error {
 	}
 
 	// Render rows with borders
-	for _, row := range t.rows {
-		if err := t.renderRowWithBorders(row); err != nil {
-			return err
-		}
+	if err := t.renderBody(t.renderRowWithBorders, t.renderTitleWithBorders); err != nil {
+		return err
 	}
 
 	// Render bottom border
@@ -206,52 +217,33 @@ func (t *Table) renderWithBorders() error {
 
 // renderSimpleHeaders renders headers with manual column alignment
 func (t *Table) renderSimpleHeaders() error {
-	var parts []string
-	for i, header := range t.headers {
-		headerText := strings.ToUpper(header)
-		coloredHeader := t.terminal.Blue(headerText)
-
-		if i < len(t.widths) {
-			// Pad to column width, accounting for visible text length only
-			padding := t.widths[i] - len(headerText)
-			if padding > 0 {
-				parts = append(parts, coloredHeader+strings.Repeat(" ", padding))
-			} else {
-				parts = append(parts, coloredHeader)
-			}
-		} else {
-			parts = append(parts, coloredHeader)
-		}
-	}
-
-	_, err := fmt.Fprintln(t.output, strings.Join(parts, "  "))
+	_, err := fmt.Fprintln(t.output, strings.Join(t.headerCells(), "  "))
 	return err
 }
 
-// renderSimpleRow renders a data row with manual column alignment
+// renderSimpleRow renders a data row with manual column alignment, over
+// several lines when cells wrap
 func (t *Table) renderSimpleRow(row []string) error {
-	var parts []string
-	for i := 0; i < len(t.headers); i++ {
-		var cell string
-		if i < len(row) {
-			cell = row[i]
+	for _, parts := range t.layoutRow(row) {
+		if _, err := fmt.Fprintln(t.output, strings.Join(parts, "  ")); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if i < len(t.widths) {
-			// Pad to column width
-			padding := t.widths[i] - len(cell)
-			if padding > 0 {
-				parts = append(parts, cell+strings.Repeat(" ", padding))
-			} else {
-				parts = append(parts, cell)
-			}
-		} else {
-			parts = append(parts, cell)
+// headerCells returns the colored headers padded to their columns. Headers
+// are truncated rather than wrapped when their column is too narrow
+func (t *Table) headerCells() []string {
+	parts := make([]string, len(t.headers))
+	for i, header := range t.headers {
+		headerText := strings.ToUpper(header)
+		if i < len(t.widths) && t.visibleLength(headerText) > t.widths[i] {
+			headerText = ansi.Truncate(headerText, t.widths[i], Ellipsis)
 		}
+		parts[i] = t.pad(i, t.terminal.Blue(headerText), t.headerAlign(i))
 	}
-
-	_, err := fmt.Fprintln(t.output, strings.Join(parts, "  "))
-	return err
+	return parts
 }
 
 // renderHeaders renders the table headers (legacy tabwriter method)
@@ -307,31 +299,21 @@ func (t *Table) renderHeaderSeparator() error {
 
 // renderHeadersWithBorders renders headers with column separators
 func (t *Table) renderHeadersWithBorders() error {
-	headerRow := make([]string, len(t.headers))
-	for i, header := range t.headers {
-		headerRow[i] = t.terminal.Blue(strings.ToUpper(header))
-	}
-
-	line := t.columnSeparator + " " + strings.Join(headerRow, " "+t.columnSeparator+" ") + " " + t.columnSeparator
+	line := t.columnSeparator + " " + strings.Join(t.headerCells(), " "+t.columnSeparator+" ") + " " + t.columnSeparator
 	_, err := fmt.Fprintln(t.output, line)
 	return err
 }
 
-// renderRowWithBorders renders a row with column separators
+// renderRowWithBorders renders a row with column separators, over several
+// lines when cells wrap
 func (t *Table) renderRowWithBorders(row []string) error {
-	// Pad row to match header length
-	paddedRow := make([]string, len(t.headers))
-	for i := 0; i < len(t.headers); i++ {
-		if i < len(row) {
-			paddedRow[i] = row[i]
-		} else {
-			paddedRow[i] = ""
+	for _, paddedRow := range t.layoutRow(row) {
+		line := t.columnSeparator + " " + strings.Join(paddedRow, " "+t.columnSeparator+" ") + " " + t.columnSeparator
+		if _, err := fmt.Fprintln(t.output, line); err != nil {
+			return err
 		}
 	}
-
-	line := t.columnSeparator + " " + strings.Join(paddedRow, " "+t.columnSeparator+" ") + " " + t.columnSeparator
-	_, err := fmt.Fprintln(t.output, line)
-	return err
+	return nil
 }
 
 // createHorizontalBorder creates a horizontal border line
@@ -374,6 +356,8 @@ func (t *Table) Clear() {
 	t.rows = nil
 	t.widths = nil
 	t.alignment = nil
+	t.headerAlignment = nil
+	t.footer = nil
 }
 
 // IsEmpty returns whether the table is empty
@@ -427,6 +411,16 @@ func (t *Table) Format() string {
 		columnSeparator: t.columnSeparator,
 		rowSeparator:    t.rowSeparator,
 		headerLine:      t.headerLine,
+		maxWidth:        t.maxWidth,
+		columns:         t.columns,
+		wrap:            t.wrap,
+		formatters:      t.formatters,
+		style:           t.style,
+		headerAlignment: t.headerAlignment,
+		footer:          t.footer,
+		grouped:         t.grouped,
+		groupBy:         t.groupBy,
+		aggregates:      t.aggregates,
 	}
 
 	if err := tempTable.Render(); err != nil {
@@ -477,19 +471,28 @@ func (t *Table) SetHeaderLine(line bool) {
 	t.headerLine = line
 }
 
-// SetColumnAlignment sets the column alignment (no-op for borderless tables)
+// SetColumnAlignment sets the column alignment with tablewriter values, as
+// SetAlignment does
 func (t *Table) SetColumnAlignment(alignment []tw.Align) {
-	// No-op: this implementation uses fixed left alignment
+	t.alignment = make([]int, len(alignment))
+	for i, align := range alignment {
+		t.alignment[i] = alignmentOf(align)
+	}
 }
 
-// SetHeaderAlignment sets the header alignment (no-op for borderless tables)
+// SetHeaderAlignment sets the header alignment, which otherwise follows
+// the column's
 func (t *Table) SetHeaderAlignment(alignment []tw.Align) {
-	// No-op: this implementation uses fixed left alignment
+	t.headerAlignment = make([]int, len(alignment))
+	for i, align := range alignment {
+		t.headerAlignment[i] = alignmentOf(align)
+	}
 }
 
-// SetAutoWrapText sets the auto wrap text flag (no-op for borderless tables)
+// SetAutoWrapText sets whether cells wider than their column wrap rather
+// than being truncated, for columns whose ColumnConfig leaves Overflow unset
 func (t *Table) SetAutoWrapText(wrap bool) {
-	// No-op: this implementation doesn't support text wrapping
+	t.wrap = wrap
 }
 
 // SetReflowDuringAutoWrap sets the reflow during auto wrap flag (no-op for borderless tables)
@@ -507,7 +510,8 @@ func (t *Table) SetNoWhiteSpace(noWhiteSpace bool) {
 	// No-op: this implementation uses fixed spacing
 }
 
-// SetFooter sets the table footer (no-op for borderless tables)
+// SetFooter sets the footer shown below the rows, such as totals. Columns
+// with an aggregate show it instead of their footer cell
 func (t *Table) SetFooter(footer []string) {
-	// No-op: this implementation doesn't support footers
+	t.footer = footer
 }
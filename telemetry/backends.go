@@ -0,0 +1,272 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// BackendHTTP sends event batches as JSON to Config.Endpoint.
+	BackendHTTP = "http"
+	// BackendSegment sends events to the Segment HTTP tracking API.
+	BackendSegment = "segment"
+	// BackendPostHog sends events to the PostHog capture API.
+	BackendPostHog = "posthog"
+)
+
+const (
+	// DefaultEndpoint is the endpoint of the default HTTP backend.
+	DefaultEndpoint = "https://telemetry.tyk.io/v1/events"
+	// DefaultSegmentEndpoint is the base URL of the Segment HTTP API.
+	DefaultSegmentEndpoint = "https://api.segment.io"
+	// DefaultPostHogEndpoint is the base URL of PostHog Cloud.
+	DefaultPostHogEndpoint = "https://us.i.posthog.com"
+)
+
+// NewTransport creates the transport for the backend selected in config.
+// The Segment and PostHog backends require config.WriteKey and use their
+// public endpoints unless config.Endpoint points somewhere else.
+func NewTransport(config *Config) (Transport, error) {
+	switch strings.ToLower(config.Backend) {
+	case "", BackendHTTP:
+		return NewHTTPTransport(config.Endpoint, config.UserAgent, config.Timeout), nil
+	case BackendSegment:
+		if config.WriteKey == "" {
+			return nil, fmt.Errorf("segment backend requires a write key")
+		}
+		return NewSegmentTransport(backendEndpoint(config, DefaultSegmentEndpoint), config.WriteKey, config.UserAgent, config.Timeout), nil
+	case BackendPostHog:
+		if config.WriteKey == "" {
+			return nil, fmt.Errorf("posthog backend requires a project API key")
+		}
+		return NewPostHogTransport(backendEndpoint(config, DefaultPostHogEndpoint), config.WriteKey, config.UserAgent, config.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown telemetry backend %q", config.Backend)
+	}
+}
+
+// backendEndpoint returns config.Endpoint, or fallback when it is unset or
+// still the default HTTP endpoint.
+func backendEndpoint(config *Config, fallback string) string {
+	if config.Endpoint == "" || config.Endpoint == DefaultEndpoint {
+		return fallback
+	}
+	return config.Endpoint
+}
+
+// SegmentTransport implements the Transport interface using the Segment
+// HTTP tracking API. Every event becomes a track call identified by the
+// anonymous installation ID.
+type SegmentTransport struct {
+	client    *http.Client
+	endpoint  string
+	writeKey  string
+	userAgent string
+}
+
+// NewSegmentTransport creates a new Segment transport. endpoint is the API
+// base URL, such as DefaultSegmentEndpoint.
+func NewSegmentTransport(endpoint, writeKey, userAgent string, timeout time.Duration) Transport {
+	return &SegmentTransport{
+		client:    &http.Client{Timeout: timeout},
+		endpoint:  strings.TrimSuffix(endpoint, "/") + "/v1/batch",
+		writeKey:  writeKey,
+		userAgent: userAgent,
+	}
+}
+
+// segmentMessage is a track call in a Segment batch.
+type segmentMessage struct {
+	Type        string                 `json:"type"`
+	Event       string                 `json:"event"`
+	AnonymousID string                 `json:"anonymousId"`
+	Properties  map[string]interface{} `json:"properties"`
+	Context     map[string]interface{} `json:"context"`
+	Timestamp   time.Time              `json:"timestamp"`
+}
+
+// Send sends a batch of events to Segment.
+func (t *SegmentTransport) Send(events []*Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	batch := make([]segmentMessage, 0, len(events))
+	for _, event := range events {
+		batch = append(batch, segmentMessage{
+			Type:        "track",
+			Event:       string(event.EventType),
+			AnonymousID: anonymousID(event),
+			Properties:  eventProperties(event),
+			Context: map[string]interface{}{
+				"app": map[string]interface{}{"version": event.CLIVersion},
+				"os":  map[string]interface{}{"name": event.OS},
+			},
+			Timestamp: event.Timestamp,
+		})
+	}
+
+	req, err := newJSONRequest(t.endpoint, t.userAgent, map[string]interface{}{"batch": batch})
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.writeKey, "")
+
+	return sendRequest(t.client, req, "segment")
+}
+
+// Close closes the transport.
+func (t *SegmentTransport) Close() error {
+	return nil
+}
+
+// PostHogTransport implements the Transport interface using the PostHog
+// batch capture API. Every event is captured with the anonymous
+// installation ID as its distinct ID.
+type PostHogTransport struct {
+	client    *http.Client
+	endpoint  string
+	apiKey    string
+	userAgent string
+}
+
+// NewPostHogTransport creates a new PostHog transport. endpoint is the
+// instance base URL, such as DefaultPostHogEndpoint.
+func NewPostHogTransport(endpoint, apiKey, userAgent string, timeout time.Duration) Transport {
+	return &PostHogTransport{
+		client:    &http.Client{Timeout: timeout},
+		endpoint:  strings.TrimSuffix(endpoint, "/") + "/batch/",
+		apiKey:    apiKey,
+		userAgent: userAgent,
+	}
+}
+
+// postHogEvent is a captured event in a PostHog batch.
+type postHogEvent struct {
+	Event      string                 `json:"event"`
+	DistinctID string                 `json:"distinct_id"`
+	Properties map[string]interface{} `json:"properties"`
+	Timestamp  time.Time              `json:"timestamp"`
+}
+
+// Send sends a batch of events to PostHog.
+func (t *PostHogTransport) Send(events []*Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	batch := make([]postHogEvent, 0, len(events))
+	for _, event := range events {
+		properties := eventProperties(event)
+		properties["$session_id"] = event.SessionID
+		properties["$os"] = event.OS
+		properties["$lib"] = "tykctl-go"
+		properties["$lib_version"] = event.CLIVersion
+		// Keep PostHog from creating person profiles for anonymous installs
+		properties["$process_person_profile"] = false
+
+		batch = append(batch, postHogEvent{
+			Event:      string(event.EventType),
+			DistinctID: anonymousID(event),
+			Properties: properties,
+			Timestamp:  event.Timestamp,
+		})
+	}
+
+	req, err := newJSONRequest(t.endpoint, t.userAgent, map[string]interface{}{
+		"api_key": t.apiKey,
+		"batch":   batch,
+	})
+	if err != nil {
+		return err
+	}
+
+	return sendRequest(t.client, req, "posthog")
+}
+
+// Close closes the transport.
+func (t *PostHogTransport) Close() error {
+	return nil
+}
+
+// anonymousID returns the identifier an analytics backend attributes an
+// event to: the installation ID, or the session ID without one.
+func anonymousID(event *Event) string {
+	if event.UserID != "" {
+		return event.UserID
+	}
+	if event.SessionID != "" {
+		return event.SessionID
+	}
+	return "anonymous"
+}
+
+// eventProperties flattens an event into the properties sent to analytics
+// backends. Event properties never override the standard fields.
+func eventProperties(event *Event) map[string]interface{} {
+	properties := make(map[string]interface{}, len(event.Properties)+10)
+	for key, value := range event.Properties {
+		properties[key] = value
+	}
+
+	properties["success"] = event.Success
+	properties["session_id"] = event.SessionID
+	properties["cli_version"] = event.CLIVersion
+	properties["os"] = event.OS
+	properties["arch"] = event.Arch
+	if event.Command != "" {
+		properties["command"] = event.Command
+	}
+	if event.Duration != 0 {
+		properties["duration_ms"] = event.Duration
+	}
+	if event.ErrorType != "" {
+		properties["error_type"] = event.ErrorType
+	}
+	if event.ErrorMessage != "" {
+		properties["error_message"] = event.ErrorMessage
+	}
+	if event.Feature != "" {
+		properties["feature"] = event.Feature
+	}
+
+	return properties
+}
+
+// newJSONRequest creates a POST request carrying payload as JSON.
+func newJSONRequest(endpoint, userAgent string, payload interface{}) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	return req, nil
+}
+
+// sendRequest sends req and fails unless the backend accepted it.
+func sendRequest(client *http.Client, req *http.Request, backend string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s endpoint returned status %d", backend, resp.StatusCode)
+	}
+
+	return nil
+}
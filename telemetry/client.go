@@ -13,35 +13,107 @@ type client struct {
 	config    *Config
 	transport Transport
 	storage   Storage
+	sampler   *Sampler
+	identity  *IdentityStore
+	sanitizer *Sanitizer
+	metrics   *MetricsRegistry
+	stats     *StatsStore
 	enabled   bool
 	mu        sync.RWMutex
+	flushMu   sync.Mutex
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
 }
 
+// ClientOption configures a telemetry client.
+type ClientOption func(*client)
+
+// WithSampler sets the sampler deciding which events are tracked. By default
+// a sampler built from the config with an in-memory daily quota is used.
+func WithSampler(sampler *Sampler) ClientOption {
+	return func(c *client) {
+		c.sampler = sampler
+	}
+}
+
+// WithIdentity sets the store of the installation ID attached to events as
+// UserID. Without one, events keep the UserID they were built with.
+func WithIdentity(identity *IdentityStore) ClientOption {
+	return func(c *client) {
+		c.identity = identity
+	}
+}
+
+// WithSanitizer sets the sanitizer applied to events before they are stored
+// and sent. By default the shared DefaultSanitizer is used.
+func WithSanitizer(sanitizer *Sanitizer) ClientOption {
+	return func(c *client) {
+		c.sanitizer = sanitizer
+	}
+}
+
+// WithMetrics records every tracked command event in registry, whether or
+// not telemetry is enabled, since the metrics never leave the process
+// unless the host exports them.
+func WithMetrics(registry *MetricsRegistry) ClientOption {
+	return func(c *client) {
+		c.metrics = registry
+	}
+}
+
+// WithStats records every tracked command event in store, whether or not
+// telemetry is enabled, so users can review their own usage.
+func WithStats(store *StatsStore) ClientOption {
+	return func(c *client) {
+		c.stats = store
+	}
+}
+
 // NewClient creates a new telemetry client.
-func NewClient(config *Config, transport Transport, storage Storage) Client {
+func NewClient(config *Config, transport Transport, storage Storage, options ...ClientOption) Client {
 	ctx, cancel := context.WithCancel(context.Background())
 	
 	c := &client{
 		config:    config,
 		transport: transport,
 		storage:   storage,
+		sampler:   NewSampler(config, ""),
+		sanitizer: DefaultSanitizer(),
 		enabled:   config.Enabled,
 		ctx:       ctx,
 		cancel:    cancel,
 	}
 	
+	for _, option := range options {
+		option(c)
+	}
+	
+	// Replay events spooled by earlier runs, such as ones made offline
+	replay := false
+	if count, err := storage.Count(); err == nil && count > 0 {
+		replay = true
+	}
+	
 	// Start the background worker for batching and sending events
 	c.wg.Add(1)
-	go c.worker()
+	go c.worker(replay)
 	
 	return c
 }
 
 // Track records a telemetry event.
 func (c *client) Track(event *Event) error {
+	if c.metrics != nil {
+		c.metrics.ObserveEvent(event)
+	}
+	
+	if c.stats != nil {
+		if err := c.stats.Record(event); err != nil {
+			return err
+		}
+	}
+	
 	c.mu.RLock()
 	enabled := c.enabled
 	c.mu.RUnlock()
@@ -50,13 +122,51 @@ func (c *client) Track(event *Event) error {
 		return nil
 	}
 	
+	// Drop events that are not sampled or are over the daily quota
+	keep, rate := c.sampler.Sample(event)
+	if !keep {
+		return nil
+	}
+	
 	// Sanitize the event to remove sensitive data
-	sanitized := SanitizeEvent(event)
+	sanitized := c.sanitizer.Sanitize(event)
+	if err := c.identify(sanitized); err != nil {
+		return err
+	}
+	if rate < 1 {
+		if sanitized.Properties == nil {
+			sanitized.Properties = make(map[string]interface{})
+		}
+		sanitized.Properties[PropertySampleRate] = rate
+	}
 	
 	// Store the event for later transmission
 	return c.storage.Store(sanitized)
 }
 
+// identify attaches an event ID and the installation and session IDs to an
+// event that does not have its own.
+func (c *client) identify(event *Event) error {
+	if event.ID == "" {
+		id, err := newUUID()
+		if err != nil {
+			return err
+		}
+		event.ID = id
+	}
+	if event.SessionID == "" {
+		event.SessionID = SessionID()
+	}
+	if event.UserID == "" && c.identity != nil {
+		id, err := c.identity.InstallID()
+		if err != nil {
+			return err
+		}
+		event.UserID = id
+	}
+	return nil
+}
+
 // Flush sends any pending events immediately.
 func (c *client) Flush() error {
 	c.mu.RLock()
@@ -67,6 +177,10 @@ func (c *client) Flush() error {
 		return nil
 	}
 	
+	// Only one flush at a time, so events are never sent twice
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+	
 	// Retrieve all stored events
 	events, err := c.storage.Retrieve()
 	if err != nil {
@@ -131,9 +245,15 @@ func (c *client) SetEnabled(enabled bool) error {
 }
 
 // worker runs in the background to batch and send events periodically.
-func (c *client) worker() {
+// When replay is set, pending events are sent straight away; failures are
+// ignored since the events stay stored for the next attempt.
+func (c *client) worker(replay bool) {
 	defer c.wg.Done()
 	
+	if replay {
+		_ = c.Flush()
+	}
+	
 	ticker := time.NewTicker(c.config.FlushInterval)
 	defer ticker.Stop()
 	
@@ -151,12 +271,17 @@ func (c *client) worker() {
 }
 
 // sendBatches sends events in batches according to the configured batch size.
+// Storages implementing Acknowledger only lose the events that were sent,
+// others are cleared once every batch has been sent.
 func (c *client) sendBatches(events []*Event) error {
 	batchSize := c.config.BatchSize
 	if batchSize <= 0 {
 		batchSize = 100 // Default batch size
 	}
 	
+	acknowledger, canAcknowledge := c.storage.(Acknowledger)
+	
+	var sent []string
 	for i := 0; i < len(events); i += batchSize {
 		end := i + batchSize
 		if end > len(events) {
@@ -167,8 +292,21 @@ func (c *client) sendBatches(events []*Event) error {
 		
 		// Send the batch with retry logic
 		if err := c.sendWithRetry(batch); err != nil {
+			// Keep the unsent events, dropping those already delivered
+			if canAcknowledge && len(sent) > 0 {
+				if ackErr := acknowledger.Acknowledge(sent); ackErr != nil {
+					return fmt.Errorf("failed to acknowledge sent events: %w", ackErr)
+				}
+			}
 			return fmt.Errorf("failed to send batch %d-%d: %w", i, end-1, err)
 		}
+		for _, event := range batch {
+			sent = append(sent, event.ID)
+		}
+	}
+	
+	if canAcknowledge {
+		return acknowledger.Acknowledge(sent)
 	}
 	
 	// Clear the storage after successful send
@@ -41,13 +41,14 @@ func (cm *ConfigManager) Load() error {
 		return fmt.Errorf("failed to read config file: %w", err)
 	}
 	
-	// Unmarshal YAML
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	// Unmarshal YAML over the defaults, so settings added since the file
+	// was written keep their default values
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, config); err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 	
-	cm.config = &config
+	cm.config = config
 	return nil
 }
 
@@ -117,6 +118,14 @@ func NewEnvironmentConfigManager() *EnvironmentConfigManager {
 		config.Endpoint = endpoint
 	}
 	
+	if backend := os.Getenv("TYKCTL_TELEMETRY_BACKEND"); backend != "" {
+		config.Backend = backend
+	}
+	
+	if writeKey := os.Getenv("TYKCTL_TELEMETRY_WRITE_KEY"); writeKey != "" {
+		config.WriteKey = writeKey
+	}
+	
 	if userAgent := os.Getenv("TYKCTL_TELEMETRY_USER_AGENT"); userAgent != "" {
 		config.UserAgent = userAgent
 	}
@@ -173,7 +182,8 @@ type ConfigManagerInterface interface {
 	GetConfigPath() string
 }
 
-// GetDefaultStoragePath returns the default storage path for telemetry events.
+// GetDefaultStoragePath returns the storage path used for telemetry events
+// before the offline spool. Events found there are moved to the spool.
 func GetDefaultStoragePath() string {
 	return filepath.Join(xdg.DataHome, "tykctl", "telemetry.json")
 }
@@ -210,20 +220,25 @@ func CreateDefaultClient() (Client, error) {
 	
 	config := configManager.GetConfig()
 	
-	// Create storage
-	storagePath := GetDefaultStoragePath()
-	storage := NewFileStorage(storagePath)
+	// Create storage, keeping events that cannot be sent yet on disk
+	storage := NewSpool(GetDefaultSpoolPath(), config.SpoolMaxEvents, config.SpoolMaxBytes)
+	if err := migrateLegacyStorage(GetDefaultStoragePath(), storage); err != nil {
+		return nil, err
+	}
+	
+	// Create transport for the configured backend
+	transport, err := NewTransport(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transport: %w", err)
+	}
 	
-	// Create transport
-	transport := NewHTTPTransport(
-		config.Endpoint,
-		config.UserAgent,
-		config.Timeout,
+	// Create client, counting the daily quota across runs
+	client := NewClient(config, transport, storage,
+		WithSampler(NewSampler(config, GetDefaultQuotaPath())),
+		WithIdentity(NewIdentityStore(GetDefaultInstallIDPath())),
+		WithStats(NewStatsStore(GetDefaultStatsPath())),
 	)
 	
-	// Create client
-	client := NewClient(config, transport, storage)
-	
 	return client, nil
 }
 
@@ -233,12 +248,12 @@ func CreateNoOpClient() Client {
 }
 
 // CreateClientFromConfig creates a telemetry client from a configuration.
+// A misconfigured backend yields a no-op client.
 func CreateClientFromConfig(config *Config, storage Storage) Client {
-	transport := NewHTTPTransport(
-		config.Endpoint,
-		config.UserAgent,
-		config.Timeout,
-	)
+	transport, err := NewTransport(config)
+	if err != nil {
+		return NewNoOpClient()
+	}
 	
 	return NewClient(config, transport, storage)
 }
@@ -0,0 +1,194 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrg/xdg"
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyVersion is the version of the telemetry policy users consent to.
+// Changing it asks every user again.
+const PolicyVersion = "1"
+
+// DefaultConsentNotice is shown before asking for consent.
+const DefaultConsentNotice = `tykctl can collect anonymous usage data, such as the commands you run, how
+long they take and whether they fail, to help improve the CLI. No API keys,
+tokens, file contents or personal information are collected. You can change
+your mind at any time with "tykctl telemetry enable" or "tykctl telemetry disable".
+`
+
+// ConsentDecision records a user's answer to the telemetry consent prompt.
+type ConsentDecision struct {
+	// Granted reports whether the user agreed to share telemetry.
+	Granted bool `yaml:"granted" json:"granted"`
+
+	// PolicyVersion is the policy version the user answered for.
+	PolicyVersion string `yaml:"policy_version" json:"policy_version"`
+
+	// DecidedAt is when the user answered.
+	DecidedAt time.Time `yaml:"decided_at" json:"decided_at"`
+}
+
+// ConsentPrompter asks the user a yes or no question. *prompt.Prompt
+// implements it.
+type ConsentPrompter interface {
+	IsInteractive() bool
+	AskConfirmationWithDefault(question string, defaultValue bool) (bool, error)
+}
+
+// ConsentManager asks for telemetry consent once, on first run, and again
+// whenever the policy version changes.
+type ConsentManager struct {
+	path          string
+	policyVersion string
+	prompter      ConsentPrompter
+	out           io.Writer
+	notice        string
+	now           func() time.Time
+}
+
+// NewConsentManager creates a consent manager asking with prompter and
+// keeping the decision in path, or GetDefaultConsentPath when path is empty.
+// An empty policyVersion uses PolicyVersion.
+func NewConsentManager(prompter ConsentPrompter, path, policyVersion string) *ConsentManager {
+	if path == "" {
+		path = GetDefaultConsentPath()
+	}
+	if policyVersion == "" {
+		policyVersion = PolicyVersion
+	}
+
+	return &ConsentManager{
+		path:          path,
+		policyVersion: policyVersion,
+		prompter:      prompter,
+		out:           os.Stderr,
+		notice:        DefaultConsentNotice,
+		now:           time.Now,
+	}
+}
+
+// SetNotice replaces the notice shown before the consent question.
+func (m *ConsentManager) SetNotice(notice string) {
+	m.notice = notice
+}
+
+// SetOutput sets where the notice is written. It defaults to stderr.
+func (m *ConsentManager) SetOutput(w io.Writer) {
+	m.out = w
+}
+
+// Decision returns the recorded decision, or nil if the user was never asked.
+func (m *ConsentManager) Decision() (*ConsentDecision, error) {
+	data, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read consent: %w", err)
+	}
+
+	var decision ConsentDecision
+	if err := yaml.Unmarshal(data, &decision); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal consent: %w", err)
+	}
+
+	return &decision, nil
+}
+
+// NeedsConsent reports whether the user has not answered for the current
+// policy version.
+func (m *ConsentManager) NeedsConsent() (bool, error) {
+	decision, err := m.Decision()
+	if err != nil {
+		return false, err
+	}
+
+	return decision == nil || decision.PolicyVersion != m.policyVersion, nil
+}
+
+// Ensure returns whether telemetry may be sent, asking the user first if
+// they have not answered for the current policy version. When there is no
+// terminal to ask on, it returns false without recording anything, so the
+// user is asked on the next interactive run.
+func (m *ConsentManager) Ensure() (bool, error) {
+	decision, err := m.Decision()
+	if err != nil {
+		return false, err
+	}
+	if decision != nil && decision.PolicyVersion == m.policyVersion {
+		return decision.Granted, nil
+	}
+
+	if m.prompter == nil || !m.prompter.IsInteractive() {
+		return false, nil
+	}
+
+	if decision != nil {
+		fmt.Fprintf(m.out, "The tykctl telemetry policy has changed since you last answered (version %s, now %s).\n\n",
+			decision.PolicyVersion, m.policyVersion)
+	}
+	if m.notice != "" {
+		fmt.Fprintln(m.out, m.notice)
+	}
+
+	granted, err := m.prompter.AskConfirmationWithDefault("Share anonymous usage data to help improve tykctl?", false)
+	if err != nil {
+		return false, fmt.Errorf("failed to ask for telemetry consent: %w", err)
+	}
+
+	if err := m.Record(granted); err != nil {
+		return false, err
+	}
+
+	return granted, nil
+}
+
+// Record saves a decision for the current policy version without asking,
+// such as when the user enables or disables telemetry explicitly.
+func (m *ConsentManager) Record(granted bool) error {
+	decision := ConsentDecision{
+		Granted:       granted,
+		PolicyVersion: m.policyVersion,
+		DecidedAt:     m.now().UTC(),
+	}
+
+	data, err := yaml.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("failed to marshal consent: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("failed to create consent directory: %w", err)
+	}
+
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write consent: %w", err)
+	}
+	if err := os.Rename(tmp, m.path); err != nil {
+		return fmt.Errorf("failed to write consent: %w", err)
+	}
+
+	return nil
+}
+
+// Reset forgets the recorded decision, so the user is asked again.
+func (m *ConsentManager) Reset() error {
+	if err := os.Remove(m.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove consent: %w", err)
+	}
+
+	return nil
+}
+
+// GetDefaultConsentPath returns the default path of the consent decision.
+func GetDefaultConsentPath() string {
+	return filepath.Join(xdg.ConfigHome, "tykctl", "telemetry-consent.yaml")
+}
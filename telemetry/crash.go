@@ -0,0 +1,282 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/edsonmichaque/tykctl-go/command"
+	"github.com/spf13/cobra"
+)
+
+// CrashReport is the local record of a panic.
+type CrashReport struct {
+	// ID identifies the report and names its file.
+	ID string `json:"id"`
+
+	// Time is when the panic was recovered.
+	Time time.Time `json:"time"`
+
+	// Command is the command that was running, if known.
+	Command string `json:"command,omitempty"`
+
+	// Panic is the panic value. It stays on this machine.
+	Panic string `json:"panic"`
+
+	// PanicType is the Go type of the panic value.
+	PanicType string `json:"panic_type"`
+
+	// Frames are the stack frames with their arguments and directories
+	// removed. Only these are sent with a crash event.
+	Frames []string `json:"frames"`
+
+	// Stack is the raw stack trace. It stays on this machine.
+	Stack string `json:"stack"`
+
+	// CLIVersion, OS and Arch describe the build that crashed.
+	CLIVersion string `json:"cli_version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+}
+
+// CrashReporter records panics locally and, when opted in, sends a crash
+// event with the redacted stack frames.
+type CrashReporter struct {
+	client Client
+	send   bool
+	dir    string
+	stderr io.Writer
+}
+
+// NewCrashReporter creates a crash reporter writing reports to dir, or to
+// GetDefaultCrashDir when dir is empty. Crash events are only sent through
+// client when config.CrashReports is set.
+func NewCrashReporter(client Client, config *Config, dir string) *CrashReporter {
+	if dir == "" {
+		dir = GetDefaultCrashDir()
+	}
+
+	return &CrashReporter{
+		client: client,
+		send:   client != nil && config != nil && config.CrashReports,
+		dir:    dir,
+		stderr: os.Stderr,
+	}
+}
+
+// Report writes a crash report for a recovered panic value and its stack,
+// and sends a crash event when opted in.
+func (r *CrashReporter) Report(commandPath string, value interface{}, stack []byte) (*CrashReport, error) {
+	now := time.Now()
+	report := &CrashReport{
+		ID:         fmt.Sprintf("%s-%d", now.UTC().Format("20060102T150405Z"), os.Getpid()),
+		Time:       now,
+		Command:    commandPath,
+		Panic:      fmt.Sprint(value),
+		PanicType:  fmt.Sprintf("%T", value),
+		Frames:     RedactStack(stack),
+		Stack:      string(stack),
+		CLIVersion: GetCLIVersion(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+
+	if err := r.write(report); err != nil {
+		return report, err
+	}
+
+	if r.send {
+		if err := r.sendEvent(report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// recoverPanic reports a recovered panic and panics again with the same
+// value, so the process still fails as it would have.
+func (r *CrashReporter) recoverPanic(commandPath string, value interface{}) {
+	report, err := r.Report(commandPath, value, debug.Stack())
+	if err != nil {
+		fmt.Fprintf(r.stderr, "Warning: failed to record crash report: %v\n", err)
+	} else {
+		fmt.Fprintf(r.stderr, "tykctl crashed. A crash report was written to %s\n", r.path(report.ID))
+	}
+	panic(value)
+}
+
+// WrapCommand wraps a Cobra command and its subcommands so panics are
+// reported before the process exits.
+func (r *CrashReporter) WrapCommand(cmd *cobra.Command) *cobra.Command {
+	return command.Use(cmd, r.Wrap)
+}
+
+// Wrap reports a panic in a command before panicking again. It is a
+// command.Middleware, for composing crash reporting with other middleware in
+// command.Use. List it after command.Recover, which would otherwise turn the
+// panic into an error before it is reported.
+func (r *CrashReporter) Wrap(next command.RunFunc) command.RunFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		defer func() {
+			if value := recover(); value != nil {
+				r.recoverPanic(command.CanonicalPath(cmd, args), value)
+			}
+		}()
+		return next(cmd, args)
+	}
+}
+
+// ListCrashReports returns the crash reports in the reporter's directory,
+// newest first.
+func (r *CrashReporter) ListCrashReports() ([]*CrashReport, error) {
+	entries, err := os.ReadDir(r.dir)
+	if os.IsNotExist(err) {
+		return []*CrashReport{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crash reports: %w", err)
+	}
+
+	reports := make([]*CrashReport, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		name := entries[i].Name()
+		if entries[i].IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(r.dir, name))
+		if err != nil {
+			continue
+		}
+		var report CrashReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			continue
+		}
+		reports = append(reports, &report)
+	}
+
+	return reports, nil
+}
+
+// write saves a crash report to the reporter's directory.
+func (r *CrashReporter) write(report *CrashReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal crash report: %w", err)
+	}
+
+	if err := os.MkdirAll(r.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create crash report directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.path(report.ID), data, 0600); err != nil {
+		return fmt.Errorf("failed to write crash report: %w", err)
+	}
+
+	return nil
+}
+
+// sendEvent tracks and immediately flushes the crash event, since the
+// process is about to exit.
+func (r *CrashReporter) sendEvent(report *CrashReport) error {
+	event := NewEventBuilder(EventTypeCrash).
+		Command(report.Command).
+		Error(report.PanicType, "panic").
+		Property("frames", report.Frames).
+		Property("report_id", report.ID).
+		Build()
+
+	event.CLIVersion = report.CLIVersion
+	event.OS = report.OS
+	event.Arch = report.Arch
+
+	if err := r.client.Track(event); err != nil {
+		return fmt.Errorf("failed to track crash event: %w", err)
+	}
+	if err := r.client.Flush(); err != nil {
+		return fmt.Errorf("failed to send crash event: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the file of a crash report.
+func (r *CrashReporter) path(id string) string {
+	return filepath.Join(r.dir, id+".json")
+}
+
+// RedactStack reduces a goroutine stack trace to one line per frame holding
+// the function and the base name of its file, dropping argument values and
+// directories, which can contain user data such as paths or tokens.
+func RedactStack(stack []byte) []string {
+	var frames []string
+	var function string
+
+	for _, line := range strings.Split(string(stack), "\n") {
+		switch {
+		case strings.HasPrefix(line, "goroutine "), strings.TrimSpace(line) == "":
+			function = ""
+		case strings.HasPrefix(line, "\t"):
+			if function == "" || strings.HasPrefix(function, "runtime/debug.") {
+				function = ""
+				continue
+			}
+			location := strings.TrimSpace(line)
+			if i := strings.LastIndex(location, " +0x"); i >= 0 {
+				location = location[:i]
+			}
+			frames = append(frames, fmt.Sprintf("%s %s", function, filepath.Base(location)))
+			function = ""
+		case strings.HasPrefix(line, "created by "):
+			function = "created by " + strings.Fields(line)[2]
+		default:
+			function = line
+			if i := strings.LastIndex(line, "("); i > 0 && strings.HasSuffix(line, ")") {
+				function = line[:i] + "(...)"
+			}
+		}
+	}
+
+	return frames
+}
+
+// GetDefaultCrashDir returns the default directory for crash reports.
+func GetDefaultCrashDir() string {
+	return filepath.Join(xdg.StateHome, "tykctl", "crashes")
+}
+
+type crashReporterKey struct{}
+
+// ContextWithCrashReporter returns a copy of ctx carrying a crash reporter
+// for RecoverAndReport.
+func ContextWithCrashReporter(ctx context.Context, reporter *CrashReporter) context.Context {
+	return context.WithValue(ctx, crashReporterKey{}, reporter)
+}
+
+// RecoverAndReport reports a panic with the crash reporter carried by ctx,
+// or only writes a local report if there is none, then panics again with
+// the same value. It must be deferred directly:
+//
+//	defer telemetry.RecoverAndReport(ctx)
+func RecoverAndReport(ctx context.Context) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	reporter, ok := ctx.Value(crashReporterKey{}).(*CrashReporter)
+	if !ok || reporter == nil {
+		reporter = NewCrashReporter(nil, nil, "")
+	}
+	reporter.recoverPanic("", value)
+}
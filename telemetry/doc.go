@@ -8,10 +8,18 @@
 //   - Privacy-first design (no sensitive data transmitted)
 //   - Opt-out capability (easily disabled by users)
 //   - Configurable (flexible configuration options)
-//   - Multiple transports (HTTP, file, mock)
+//   - Multiple transports (HTTP, Segment, PostHog, file, mock)
 //   - Cobra integration (seamless command tracking)
 //   - Performance tracking (built-in metrics collection)
 //   - Error tracking (automatic error reporting)
+//   - Offline spool (size-capped, replayed on the next run)
+//   - Sampling and quotas (per event type rates, daily event cap)
+//   - Anonymous identifiers (random install ID, per-process session ID)
+//   - Crash reporting (local reports, opt-in redacted crash events)
+//   - First-run consent (asked once, again when the policy version changes)
+//   - Redaction rules (key and regex based, applied before transport)
+//   - Local usage stats (per-command counts and durations, never sent)
+//   - Prometheus metrics (in-process registry, scrape handler, Pushgateway)
 //
 // Basic Usage:
 //
@@ -64,6 +72,7 @@
 // Example configuration:
 //
 //	enabled: true
+//	backend: "http"
 //	endpoint: "https://telemetry.tyk.io/v1/events"
 //	batch_size: 100
 //	flush_interval: "5m"
@@ -71,6 +80,13 @@
 //	retry_delay: "1s"
 //	timeout: "30s"
 //	user_agent: "tykctl-go-telemetry/1.0"
+//	spool_max_events: 10000
+//	spool_max_bytes: 5242880
+//	sample_rates:
+//	  command: 0.1
+//	  error: 1.0
+//	daily_quota: 1000
+//	crash_reports: false
 //
 // Environment Variables:
 //
@@ -79,6 +95,8 @@
 //	TYKCTL_NO_TELEMETRY=1                    # Disable telemetry
 //	TYKCTL_TELEMETRY_ENABLED=false          # Disable telemetry
 //	TYKCTL_TELEMETRY_ENDPOINT="https://..." # Custom endpoint
+//	TYKCTL_TELEMETRY_BACKEND=posthog        # http, segment or posthog
+//	TYKCTL_TELEMETRY_WRITE_KEY="..."        # Segment or PostHog key
 //	TYKCTL_TELEMETRY_USER_AGENT="my-app/1.0" # Custom user agent
 //
 // Privacy and Security:
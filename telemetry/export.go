@@ -0,0 +1,167 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// LocalDataExport is the human-readable view of everything telemetry keeps
+// on the local machine.
+type LocalDataExport struct {
+	// GeneratedAt is when the export was produced.
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// Enabled reports whether telemetry is currently enabled.
+	Enabled bool `json:"enabled"`
+
+	// Endpoint is where pending events would be sent.
+	Endpoint string `json:"endpoint"`
+
+	// Identifiers lists the anonymous identifiers derived from stored events.
+	Identifiers LocalIdentifiers `json:"identifiers"`
+
+	// PendingEvents contains every event that has not yet been sent.
+	PendingEvents []*Event `json:"pending_events"`
+}
+
+// LocalIdentifiers lists the anonymous identifiers found in local data.
+type LocalIdentifiers struct {
+	// UserIDs are the distinct anonymous user identifiers.
+	UserIDs []string `json:"user_ids"`
+
+	// SessionIDs are the distinct anonymous session identifiers.
+	SessionIDs []string `json:"session_ids"`
+}
+
+// ExportLocalData writes every locally stored event and the identifiers
+// derived from them to w as indented JSON.
+func (c *client) ExportLocalData(w io.Writer) error {
+	events, err := c.storage.Retrieve()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve events: %w", err)
+	}
+
+	c.mu.RLock()
+	export := &LocalDataExport{
+		GeneratedAt:   time.Now(),
+		Enabled:       c.enabled,
+		Endpoint:      c.config.Endpoint,
+		Identifiers:   collectIdentifiers(events),
+		PendingEvents: events,
+	}
+	c.mu.RUnlock()
+
+	return writeExport(w, export)
+}
+
+// PurgeLocalData removes every locally stored event without sending it,
+// along with the installation ID and usage stats.
+func (c *client) PurgeLocalData() error {
+	if err := c.storage.Clear(); err != nil {
+		return fmt.Errorf("failed to clear events: %w", err)
+	}
+
+	if c.identity != nil {
+		if err := c.identity.Reset(); err != nil {
+			return err
+		}
+	}
+
+	if c.stats != nil {
+		if err := c.stats.Reset(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportLocalData writes an empty export, as no data is kept.
+func (c *NoOpClient) ExportLocalData(w io.Writer) error {
+	return writeExport(w, &LocalDataExport{
+		GeneratedAt:   time.Now(),
+		Identifiers:   collectIdentifiers(nil),
+		PendingEvents: []*Event{},
+	})
+}
+
+// PurgeLocalData does nothing.
+func (c *NoOpClient) PurgeLocalData() error {
+	return nil
+}
+
+// NewExportCommand creates a "telemetry export" command that writes the
+// locally stored telemetry data to stdout, or removes it with --purge.
+func NewExportCommand(client Client) *cobra.Command {
+	var purge bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Show all locally stored telemetry data",
+		Long:  "Print every pending telemetry event and anonymous identifier stored on this machine as JSON.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := client.ExportLocalData(cmd.OutOrStdout()); err != nil {
+				return err
+			}
+
+			if purge {
+				return client.PurgeLocalData()
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&purge, "purge", false, "Delete the local telemetry data after exporting it")
+
+	return cmd
+}
+
+// collectIdentifiers returns the sorted, distinct identifiers used by events.
+func collectIdentifiers(events []*Event) LocalIdentifiers {
+	users := make(map[string]struct{})
+	sessions := make(map[string]struct{})
+
+	for _, event := range events {
+		if event.UserID != "" {
+			users[event.UserID] = struct{}{}
+		}
+		if event.SessionID != "" {
+			sessions[event.SessionID] = struct{}{}
+		}
+	}
+
+	return LocalIdentifiers{
+		UserIDs:    sortedKeys(users),
+		SessionIDs: sortedKeys(sessions),
+	}
+}
+
+// sortedKeys returns the keys of a set in sorted order.
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeExport encodes an export as indented JSON.
+func writeExport(w io.Writer, export *LocalDataExport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("failed to encode local data: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+)
+
+// IdentityStore keeps the anonymous installation ID. The ID is random and
+// derived from nothing on the machine, so it links events from the same
+// installation without identifying the user.
+type IdentityStore struct {
+	path string
+	id   string
+	mu   sync.Mutex
+}
+
+// NewIdentityStore creates an identity store keeping the installation ID in
+// path.
+func NewIdentityStore(path string) *IdentityStore {
+	return &IdentityStore{path: path}
+}
+
+// InstallID returns the installation ID, generating and saving one on
+// first use.
+func (s *IdentityStore) InstallID() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.id != "" {
+		return s.id, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			s.id = id
+			return id, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read install ID: %w", err)
+	}
+
+	return s.generate()
+}
+
+// Rotate replaces the installation ID with a new random one and returns it.
+// Events tracked afterwards cannot be linked to earlier ones.
+func (s *IdentityStore) Rotate() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.generate()
+}
+
+// Reset removes the installation ID. A new one is generated the next time
+// it is needed.
+func (s *IdentityStore) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.id = ""
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove install ID: %w", err)
+	}
+
+	return nil
+}
+
+// Path returns the installation ID file path.
+func (s *IdentityStore) Path() string {
+	return s.path
+}
+
+// generate creates and saves a new installation ID without locking.
+func (s *IdentityStore) generate() (string, error) {
+	id, err := newUUID()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create install ID directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(id+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write install ID: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return "", fmt.Errorf("failed to write install ID: %w", err)
+	}
+
+	s.id = id
+	return id, nil
+}
+
+// GetDefaultInstallIDPath returns the default path of the installation ID.
+func GetDefaultInstallIDPath() string {
+	return filepath.Join(xdg.DataHome, "tykctl", "telemetry", "install_id")
+}
+
+// NewResetIDCommand creates a "telemetry reset-id" command that replaces the
+// installation ID with a new random one.
+func NewResetIDCommand(identity *IdentityStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset-id",
+		Short: "Generate a new anonymous installation ID",
+		Long:  "Replace the anonymous installation ID so future telemetry events cannot be linked to earlier ones.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := identity.Rotate()
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "New installation ID: %s\n", id)
+			return nil
+		},
+	}
+}
+
+var (
+	sessionID   string
+	sessionOnce sync.Once
+)
+
+// SessionID returns the anonymous ID of the current process. Every event
+// tracked by the process shares it, so events from one invocation can be
+// grouped.
+func SessionID() string {
+	sessionOnce.Do(func() {
+		var b [8]byte
+		if _, err := rand.Read(b[:]); err == nil {
+			sessionID = hex.EncodeToString(b[:])
+		}
+	})
+	return sessionID
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate ID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	h := hex.EncodeToString(b[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32]), nil
+}
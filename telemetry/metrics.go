@@ -0,0 +1,223 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDurationBuckets are the default upper bounds, in seconds, of the
+// command duration histogram.
+var DefaultDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// commandMetrics holds the metrics of a single command.
+type commandMetrics struct {
+	success  uint64
+	failure  uint64
+	sum      float64
+	buckets  []uint64
+	failures map[string]uint64
+}
+
+// MetricsRegistry aggregates command counts, durations and errors in
+// process, for export in the Prometheus text format or to a Pushgateway.
+// It keeps everything local and is independent of whether telemetry is
+// enabled, which suits long-running daemon-style extensions.
+type MetricsRegistry struct {
+	namespace string
+	buckets   []float64
+	commands  map[string]*commandMetrics
+	mu        sync.Mutex
+}
+
+// NewMetricsRegistry creates a metrics registry whose metric names start with
+// namespace, or "tykctl" when it is empty. Without buckets the duration
+// histogram uses DefaultDurationBuckets.
+func NewMetricsRegistry(namespace string, buckets ...float64) *MetricsRegistry {
+	if namespace == "" {
+		namespace = "tykctl"
+	}
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &MetricsRegistry{
+		namespace: namespace,
+		buckets:   sorted,
+		commands:  make(map[string]*commandMetrics),
+	}
+}
+
+// Observe records a command run. errorType classifies a failure and is
+// ignored when success is true.
+func (r *MetricsRegistry) Observe(command string, duration time.Duration, success bool, errorType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.commands[command]
+	if !ok {
+		m = &commandMetrics{
+			buckets:  make([]uint64, len(r.buckets)),
+			failures: make(map[string]uint64),
+		}
+		r.commands[command] = m
+	}
+
+	seconds := duration.Seconds()
+	m.sum += seconds
+	for i, bound := range r.buckets {
+		if seconds <= bound {
+			m.buckets[i]++
+		}
+	}
+
+	if success {
+		m.success++
+		return
+	}
+
+	m.failure++
+	if errorType == "" {
+		errorType = "unknown"
+	}
+	m.failures[errorType]++
+}
+
+// ObserveEvent records a command event. Other events are ignored.
+func (r *MetricsRegistry) ObserveEvent(event *Event) {
+	if event.EventType != EventTypeCommand || event.Command == "" {
+		return
+	}
+
+	r.Observe(event.Command, time.Duration(event.Duration)*time.Millisecond, event.Success, event.ErrorType)
+}
+
+// Reset removes every recorded metric.
+func (r *MetricsRegistry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.commands = make(map[string]*commandMetrics)
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format.
+func (r *MetricsRegistry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	commands := make([]string, 0, len(r.commands))
+	for command := range r.commands {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+
+	var b bytes.Buffer
+
+	total := r.namespace + "_commands_total"
+	fmt.Fprintf(&b, "# HELP %s Number of commands run, by command and status.\n", total)
+	fmt.Fprintf(&b, "# TYPE %s counter\n", total)
+	for _, command := range commands {
+		m := r.commands[command]
+		fmt.Fprintf(&b, "%s{command=%s,status=\"success\"} %d\n", total, quoteLabel(command), m.success)
+		fmt.Fprintf(&b, "%s{command=%s,status=\"error\"} %d\n", total, quoteLabel(command), m.failure)
+	}
+
+	errors := r.namespace + "_command_errors_total"
+	fmt.Fprintf(&b, "# HELP %s Number of failed commands, by command and error type.\n", errors)
+	fmt.Fprintf(&b, "# TYPE %s counter\n", errors)
+	for _, command := range commands {
+		m := r.commands[command]
+		errorTypes := make([]string, 0, len(m.failures))
+		for errorType := range m.failures {
+			errorTypes = append(errorTypes, errorType)
+		}
+		sort.Strings(errorTypes)
+		for _, errorType := range errorTypes {
+			fmt.Fprintf(&b, "%s{command=%s,error_type=%s} %d\n", errors, quoteLabel(command), quoteLabel(errorType), m.failures[errorType])
+		}
+	}
+
+	duration := r.namespace + "_command_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s Command duration in seconds.\n", duration)
+	fmt.Fprintf(&b, "# TYPE %s histogram\n", duration)
+	for _, command := range commands {
+		m := r.commands[command]
+		label := quoteLabel(command)
+		for i, bound := range r.buckets {
+			fmt.Fprintf(&b, "%s_bucket{command=%s,le=\"%s\"} %d\n", duration, label, formatFloat(bound), m.buckets[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket{command=%s,le=\"+Inf\"} %d\n", duration, label, m.success+m.failure)
+		fmt.Fprintf(&b, "%s_sum{command=%s} %s\n", duration, label, formatFloat(m.sum))
+		fmt.Fprintf(&b, "%s_count{command=%s} %d\n", duration, label, m.success+m.failure)
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// Handler returns an HTTP handler serving the metrics for Prometheus to scrape.
+func (r *MetricsRegistry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.WritePrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// Push sends the metrics to a Prometheus Pushgateway at gatewayURL under
+// the given job, replacing the metrics previously pushed for the job.
+func (r *MetricsRegistry) Push(ctx context.Context, gatewayURL, job string) error {
+	if job == "" {
+		return fmt.Errorf("job name is required")
+	}
+
+	var body bytes.Buffer
+	if err := r.WritePrometheus(&body); err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(gatewayURL, "/") + "/metrics/job/" + url.PathEscape(job)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("pushgateway returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// quoteLabel quotes a label value, escaping backslashes, quotes and newlines.
+func quoteLabel(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return `"` + value + `"`
+}
+
+// formatFloat formats a sample value or bucket bound.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
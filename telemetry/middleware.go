@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/edsonmichaque/tykctl-go/command"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -22,37 +23,20 @@ func NewMiddleware(client Client) *Middleware {
 	}
 }
 
-// WrapCommand wraps a Cobra command with telemetry tracking.
+// WrapCommand wraps a Cobra command and its subcommands with telemetry
+// tracking.
 func (m *Middleware) WrapCommand(cmd *cobra.Command) *cobra.Command {
-	// Store the original RunE function
-	originalRunE := cmd.RunE
-	originalRun := cmd.Run
-	
-	// Wrap RunE if it exists
-	if originalRunE != nil {
-		cmd.RunE = func(cmd *cobra.Command, args []string) error {
-			return m.trackCommand(cmd, args, func() error {
-				return originalRunE(cmd, args)
-			})
-		}
-	}
-	
-	// Wrap Run if it exists
-	if originalRun != nil {
-		cmd.Run = func(cmd *cobra.Command, args []string) {
-			m.trackCommand(cmd, args, func() error {
-				originalRun(cmd, args)
-				return nil
-			})
-		}
-	}
-	
-	// Wrap all subcommands
-	for _, subcmd := range cmd.Commands() {
-		m.WrapCommand(subcmd)
+	return command.Use(cmd, m.Wrap)
+}
+
+// Wrap tracks a command's execution. It is a command.Middleware, for
+// composing tracking with other middleware in command.Use.
+func (m *Middleware) Wrap(next command.RunFunc) command.RunFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		return m.trackCommand(cmd, args, func() error {
+			return next(cmd, args)
+		})
 	}
-	
-	return cmd
 }
 
 // trackCommand tracks command execution with telemetry.
@@ -61,7 +45,7 @@ func (m *Middleware) trackCommand(cmd *cobra.Command, args []string, fn func() e
 	
 	// Create command event
 	event := NewEventBuilder(EventTypeCommand).
-		Command(cmd.CommandPath()).
+		Command(command.CanonicalPath(cmd, args)).
 		Properties(map[string]interface{}{
 			"args_count": len(args),
 			"flags":      m.extractFlags(cmd),
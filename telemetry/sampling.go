@@ -0,0 +1,170 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// PropertySampleRate is the event property recording the rate an event was
+// sampled at, so sampled counts can be scaled back up.
+const PropertySampleRate = "sample_rate"
+
+// DefaultDailyQuota is the default maximum number of events tracked per day.
+const DefaultDailyQuota = 1000
+
+// quotaState is the on-disk format of the daily quota counter.
+type quotaState struct {
+	Day   string `json:"day"`
+	Count int    `json:"count"`
+}
+
+// Sampler decides which events are tracked. Each event type is kept with its
+// configured probability, and at most a daily quota of events is kept per
+// UTC day. The quota counter is kept in a state file when a path is given,
+// so it applies across runs; concurrent processes may overshoot it slightly.
+type Sampler struct {
+	rates     map[EventType]float64
+	quota     int
+	quotaPath string
+	state     quotaState
+	random    func() float64
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewSampler creates a sampler from the sample rates and daily quota in
+// config, keeping the quota counter in quotaPath, or in memory when it is
+// empty.
+func NewSampler(config *Config, quotaPath string) *Sampler {
+	rates := make(map[EventType]float64, len(config.SampleRates))
+	for eventType, rate := range config.SampleRates {
+		rates[eventType] = rate
+	}
+
+	return &Sampler{
+		rates:     rates,
+		quota:     config.DailyQuota,
+		quotaPath: quotaPath,
+		random:    rand.Float64,
+		now:       time.Now,
+	}
+}
+
+// SampleRate returns the probability that an event of the given type is
+// kept. Event types without a configured rate are always kept.
+func (s *Sampler) SampleRate(eventType EventType) float64 {
+	rate, ok := s.rates[eventType]
+	if !ok || rate > 1 {
+		return 1
+	}
+	if rate < 0 {
+		return 0
+	}
+	return rate
+}
+
+// Sample reports whether an event should be tracked and the rate it was
+// sampled at. Events that are kept count towards the daily quota.
+func (s *Sampler) Sample(event *Event) (bool, float64) {
+	rate := s.SampleRate(event.EventType)
+	if rate < 1 && s.random() >= rate {
+		return false, rate
+	}
+
+	return s.takeQuota(), rate
+}
+
+// Remaining returns how many more events can be tracked today, or -1 when
+// there is no quota.
+func (s *Sampler) Remaining() int {
+	if s.quota <= 0 {
+		return -1
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.load()
+	if remaining := s.quota - s.state.Count; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// takeQuota counts an event against the daily quota, reporting false once
+// the quota is used up.
+func (s *Sampler) takeQuota() bool {
+	if s.quota <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.load()
+	if s.state.Count >= s.quota {
+		return false
+	}
+
+	s.state.Count++
+	if err := s.save(); err != nil {
+		// Keep counting in memory if the state file cannot be written
+		s.quotaPath = ""
+	}
+	return true
+}
+
+// load refreshes the quota counter, resetting it on a new day.
+func (s *Sampler) load() {
+	today := s.now().UTC().Format("2006-01-02")
+
+	if s.quotaPath != "" {
+		var state quotaState
+		if data, err := os.ReadFile(s.quotaPath); err == nil && json.Unmarshal(data, &state) == nil {
+			s.state = state
+		}
+	}
+
+	if s.state.Day != today {
+		s.state = quotaState{Day: today}
+	}
+}
+
+// save writes the quota counter atomically.
+func (s *Sampler) save() error {
+	if s.quotaPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quota state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.quotaPath), 0755); err != nil {
+		return fmt.Errorf("failed to create quota directory: %w", err)
+	}
+
+	tmp := s.quotaPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write quota state: %w", err)
+	}
+	if err := os.Rename(tmp, s.quotaPath); err != nil {
+		return fmt.Errorf("failed to write quota state: %w", err)
+	}
+
+	return nil
+}
+
+// GetDefaultQuotaPath returns the default path of the daily quota counter.
+func GetDefaultQuotaPath() string {
+	return filepath.Join(xdg.StateHome, "tykctl", "telemetry", "quota.json")
+}
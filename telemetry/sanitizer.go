@@ -0,0 +1,177 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DefaultSensitiveKeys are the property keys removed by every new Sanitizer.
+var DefaultSensitiveKeys = []string{
+	"token", "key", "secret", "password", "auth",
+	"credential", "api_key", "access_token", "refresh_token",
+}
+
+// valueRule replaces matches of a pattern in string values.
+type valueRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Sanitizer removes sensitive data from events before they are stored or
+// sent. Properties whose key matches a key rule are removed, and value
+// rules are applied to string property values and error messages. Nested
+// maps and slices are sanitized recursively. Rules can be added at any
+// time and are safe for concurrent use.
+type Sanitizer struct {
+	keys        map[string]bool
+	keyPatterns []*regexp.Regexp
+	valueRules  []valueRule
+	mu          sync.RWMutex
+}
+
+// NewSanitizer creates a sanitizer removing DefaultSensitiveKeys.
+func NewSanitizer() *Sanitizer {
+	s := &Sanitizer{keys: make(map[string]bool)}
+	s.RedactKeys(DefaultSensitiveKeys...)
+	return s
+}
+
+// RedactKeys removes properties with the given keys, compared case-insensitively.
+func (s *Sanitizer) RedactKeys(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		s.keys[strings.ToLower(key)] = true
+	}
+}
+
+// RedactKeyPattern removes properties whose key matches the regular expression.
+func (s *Sanitizer) RedactKeyPattern(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keyPatterns = append(s.keyPatterns, re)
+	return nil
+}
+
+// RedactValuePattern replaces matches of the regular expression in string
+// values with replacement, which may refer to submatches as in
+// regexp.ReplaceAllString. An empty replacement uses "[REDACTED]".
+func (s *Sanitizer) RedactValuePattern(pattern, replacement string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid value pattern %q: %w", pattern, err)
+	}
+	if replacement == "" {
+		replacement = "[REDACTED]"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.valueRules = append(s.valueRules, valueRule{pattern: re, replacement: replacement})
+	return nil
+}
+
+// Sanitize returns a copy of the event with the rules applied. The original
+// event is not modified.
+func (s *Sanitizer) Sanitize(event *Event) *Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sanitized := *event
+	if event.Properties != nil {
+		sanitized.Properties = s.sanitizeMap(event.Properties)
+	}
+	if sanitized.ErrorMessage != "" {
+		sanitized.ErrorMessage = s.sanitizeString(sanitized.ErrorMessage)
+	}
+
+	return &sanitized
+}
+
+// SanitizeString applies the value rules to s.
+func (s *Sanitizer) SanitizeString(value string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sanitizeString(value)
+}
+
+// sanitizeMap returns a copy of m without redacted keys.
+func (s *Sanitizer) sanitizeMap(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if s.redactKey(key) {
+			continue
+		}
+		result[key] = s.sanitizeValue(value)
+	}
+	return result
+}
+
+// sanitizeValue applies the rules to a property value.
+func (s *Sanitizer) sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return s.sanitizeString(v)
+	case map[string]interface{}:
+		return s.sanitizeMap(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = s.sanitizeValue(item)
+		}
+		return result
+	case []string:
+		result := make([]string, len(v))
+		for i, item := range v {
+			result[i] = s.sanitizeString(item)
+		}
+		return result
+	}
+	return value
+}
+
+// redactKey reports whether a property key matches a key rule.
+func (s *Sanitizer) redactKey(key string) bool {
+	if s.keys[strings.ToLower(key)] {
+		return true
+	}
+	for _, re := range s.keyPatterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeString applies the value rules without locking.
+func (s *Sanitizer) sanitizeString(value string) string {
+	for _, rule := range s.valueRules {
+		value = rule.pattern.ReplaceAllString(value, rule.replacement)
+	}
+	return value
+}
+
+// Global sanitizer instance
+var defaultSanitizer *Sanitizer
+var sanitizerOnce sync.Once
+
+// DefaultSanitizer returns the sanitizer used by SanitizeEvent and by
+// clients without their own. Rules added to it apply to all of them.
+func DefaultSanitizer() *Sanitizer {
+	sanitizerOnce.Do(func() {
+		defaultSanitizer = NewSanitizer()
+	})
+	return defaultSanitizer
+}
@@ -0,0 +1,358 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/adrg/xdg"
+)
+
+const (
+	// DefaultSpoolMaxEvents is the default maximum number of spooled events.
+	DefaultSpoolMaxEvents = 10000
+
+	// DefaultSpoolMaxBytes is the default maximum size of the spool file.
+	DefaultSpoolMaxBytes = 5 * 1024 * 1024
+)
+
+// Acknowledger is implemented by storages that can remove events once they
+// have been sent, so events stored during a flush are not lost and a
+// partially failed flush is not sent twice.
+type Acknowledger interface {
+	// Acknowledge removes the stored events with the given IDs.
+	Acknowledge(ids []string) error
+}
+
+// Spool implements the Storage interface using a size-capped JSON Lines
+// file. Events stay in the spool until they are sent, so usage recorded
+// without network access is replayed on the next successful flush. When the
+// spool grows past its limits the oldest events are dropped.
+type Spool struct {
+	filename  string
+	maxEvents int
+	maxBytes  int64
+	dropped   int
+	// count is the number of spooled events, or -1 until it is known.
+	count int
+	mu    sync.Mutex
+}
+
+// NewSpool creates a spool stored in filename. Limits of zero or less use
+// DefaultSpoolMaxEvents and DefaultSpoolMaxBytes.
+func NewSpool(filename string, maxEvents int, maxBytes int64) *Spool {
+	if maxEvents <= 0 {
+		maxEvents = DefaultSpoolMaxEvents
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultSpoolMaxBytes
+	}
+
+	return &Spool{
+		filename:  filename,
+		maxEvents: maxEvents,
+		maxBytes:  maxBytes,
+		count:     -1,
+	}
+}
+
+// Store appends an event to the spool, giving it an ID if it has none. The
+// spool is only rewritten when it grows past its limits, to drop the oldest
+// events.
+func (s *Spool) Store(event *Event) error {
+	if event.ID == "" {
+		id, err := newUUID()
+		if err != nil {
+			return err
+		}
+		withID := *event
+		withID.ID = id
+		event = &withID
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size, err := s.appendLine(line)
+	if err != nil {
+		return err
+	}
+
+	if s.count < 0 {
+		lines, err := s.readLines()
+		if err != nil {
+			return err
+		}
+		s.count = len(lines)
+	} else {
+		s.count++
+	}
+
+	if s.count <= s.maxEvents && size <= s.maxBytes {
+		return nil
+	}
+
+	lines, err := s.readLines()
+	if err != nil {
+		return err
+	}
+	return s.writeLines(s.trim(lines))
+}
+
+// Retrieve returns every spooled event, oldest first. Lines that cannot be
+// parsed are skipped.
+func (s *Spool) Retrieve() ([]*Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines, err := s.readLines()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*Event, 0, len(lines))
+	for _, line := range lines {
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			continue
+		}
+		if event.ID == "" {
+			event.ID = lineID(line)
+		}
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+// Acknowledge removes the events with the given IDs from the spool. Events
+// stored since they were retrieved are kept.
+func (s *Spool) Acknowledge(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	acknowledged := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		acknowledged[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines, err := s.readLines()
+	if err != nil {
+		return err
+	}
+
+	kept := lines[:0]
+	for _, line := range lines {
+		var event struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(line, &event); err == nil {
+			if event.ID == "" {
+				event.ID = lineID(line)
+			}
+			if acknowledged[event.ID] {
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+
+	if len(kept) == len(lines) {
+		return nil
+	}
+	return s.writeLines(kept)
+}
+
+// Clear removes all spooled events.
+func (s *Spool) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.filename); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove spool: %w", err)
+	}
+	s.count = 0
+
+	return nil
+}
+
+// Count returns the number of spooled events.
+func (s *Spool) Count() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines, err := s.readLines()
+	if err != nil {
+		return 0, err
+	}
+	s.count = len(lines)
+
+	return len(lines), nil
+}
+
+// Dropped returns how many events this spool has dropped to stay within
+// its limits.
+func (s *Spool) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dropped
+}
+
+// Path returns the spool file path.
+func (s *Spool) Path() string {
+	return s.filename
+}
+
+// trim drops the oldest lines until the spool is within its limits.
+func (s *Spool) trim(lines [][]byte) [][]byte {
+	var size int64
+	for _, line := range lines {
+		size += int64(len(line)) + 1
+	}
+
+	for len(lines) > 0 && (len(lines) > s.maxEvents || size > s.maxBytes) {
+		size -= int64(len(lines[0])) + 1
+		lines = lines[1:]
+		s.dropped++
+	}
+
+	return lines
+}
+
+// lineID identifies a spooled event stored without an ID by a hash of its
+// line.
+func lineID(line []byte) string {
+	sum := sha256.Sum256(line)
+	return "line-" + hex.EncodeToString(sum[:8])
+}
+
+// appendLine appends line to the spool file and returns the file's new
+// size.
+func (s *Spool) appendLine(line []byte) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(s.filename), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	file, err := os.OpenFile(s.filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open spool: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return 0, fmt.Errorf("failed to write spool: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to write spool: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// readLines reads the non-empty lines of the spool file.
+func (s *Spool) readLines() ([][]byte, error) {
+	data, err := os.ReadFile(s.filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool: %w", err)
+	}
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), int(s.maxBytes)+1)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read spool: %w", err)
+	}
+
+	return lines, nil
+}
+
+// writeLines atomically replaces the spool file with lines.
+func (s *Spool) writeLines(lines [][]byte) error {
+	// Recount on the next Store unless the spool is replaced.
+	s.count = -1
+	if len(lines) == 0 {
+		if err := os.Remove(s.filename); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove spool: %w", err)
+		}
+		s.count = 0
+		return nil
+	}
+
+	dir := filepath.Dir(s.filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmp := s.filename + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write spool: %w", err)
+	}
+	if err := os.Rename(tmp, s.filename); err != nil {
+		return fmt.Errorf("failed to write spool: %w", err)
+	}
+	s.count = len(lines)
+
+	return nil
+}
+
+// GetDefaultSpoolPath returns the default path of the offline event spool.
+func GetDefaultSpoolPath() string {
+	return filepath.Join(xdg.StateHome, "tykctl", "telemetry", "spool.jsonl")
+}
+
+// migrateLegacyStorage moves events left in the file storage used before the
+// spool into the spool, then removes the old file.
+func migrateLegacyStorage(legacyPath string, spool *Spool) error {
+	if _, err := os.Stat(legacyPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	events, err := NewFileStorage(legacyPath).Retrieve()
+	if err != nil {
+		return fmt.Errorf("failed to read legacy telemetry storage: %w", err)
+	}
+
+	for _, event := range events {
+		if err := spool.Store(event); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(legacyPath); err != nil {
+		return fmt.Errorf("failed to remove legacy telemetry storage: %w", err)
+	}
+
+	return nil
+}
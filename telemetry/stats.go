@@ -0,0 +1,275 @@
+// Package telemetry provides anonymous usage analytics for tykctl-go.
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+)
+
+// CommandStats is the aggregated local usage of a single command.
+type CommandStats struct {
+	// Command is the command that was run.
+	Command string `json:"command"`
+
+	// Count is the number of times the command was run.
+	Count int64 `json:"count"`
+
+	// Failures is the number of runs that failed.
+	Failures int64 `json:"failures"`
+
+	// TotalDuration is the combined duration of every run in milliseconds.
+	TotalDuration int64 `json:"total_duration_ms"`
+
+	// LastUsed is when the command last ran.
+	LastUsed time.Time `json:"last_used"`
+}
+
+// AverageDuration returns the mean duration of a run.
+func (s CommandStats) AverageDuration() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return time.Duration(s.TotalDuration/s.Count) * time.Millisecond
+}
+
+// UsageReport is the user's own command usage, as kept on the local machine.
+type UsageReport struct {
+	// Since is when the first command was recorded.
+	Since time.Time `json:"since,omitempty"`
+
+	// Total is the number of recorded command runs.
+	Total int64 `json:"total"`
+
+	// Commands lists the commands by descending run count.
+	Commands []CommandStats `json:"commands"`
+}
+
+// statsFile is the on-disk format of the stats store.
+type statsFile struct {
+	Since    time.Time                `json:"since,omitempty"`
+	Commands map[string]*CommandStats `json:"commands"`
+}
+
+// StatsStore aggregates command events into per-command counts and
+// durations kept on disk. The stats never leave the machine and are
+// recorded whether or not telemetry is enabled.
+type StatsStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStatsStore creates a stats store kept in path.
+func NewStatsStore(path string) *StatsStore {
+	return &StatsStore{path: path}
+}
+
+// Record adds a command event to the stats. Other events are ignored.
+func (s *StatsStore) Record(event *Event) error {
+	if event.EventType != EventTypeCommand || event.Command == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	when := event.Timestamp
+	if when.IsZero() {
+		when = time.Now()
+	}
+	if file.Since.IsZero() || when.Before(file.Since) {
+		file.Since = when
+	}
+
+	stats, ok := file.Commands[event.Command]
+	if !ok {
+		stats = &CommandStats{Command: event.Command}
+		file.Commands[event.Command] = stats
+	}
+	stats.Count++
+	stats.TotalDuration += event.Duration
+	if !event.Success {
+		stats.Failures++
+	}
+	if when.After(stats.LastUsed) {
+		stats.LastUsed = when
+	}
+
+	return s.save(file)
+}
+
+// Report returns the aggregated stats.
+func (s *StatsStore) Report() (*UsageReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UsageReport{
+		Since:    file.Since,
+		Commands: make([]CommandStats, 0, len(file.Commands)),
+	}
+	for _, stats := range file.Commands {
+		report.Total += stats.Count
+		report.Commands = append(report.Commands, *stats)
+	}
+	sort.Slice(report.Commands, func(i, j int) bool {
+		a, b := report.Commands[i], report.Commands[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Command < b.Command
+	})
+
+	return report, nil
+}
+
+// Reset removes the stats.
+func (s *StatsStore) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove usage stats: %w", err)
+	}
+
+	return nil
+}
+
+// Path returns the stats file path.
+func (s *StatsStore) Path() string {
+	return s.path
+}
+
+// load reads the stats file without locking, returning empty stats if it
+// does not exist.
+func (s *StatsStore) load() (*statsFile, error) {
+	file := &statsFile{Commands: make(map[string]*CommandStats)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage stats: %w", err)
+	}
+
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse usage stats: %w", err)
+	}
+	if file.Commands == nil {
+		file.Commands = make(map[string]*CommandStats)
+	}
+
+	return file, nil
+}
+
+// save writes the stats file atomically without locking.
+func (s *StatsStore) save(file *statsFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode usage stats: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create usage stats directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write usage stats: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write usage stats: %w", err)
+	}
+
+	return nil
+}
+
+// GetDefaultStatsPath returns the default path of the local usage stats.
+func GetDefaultStatsPath() string {
+	return filepath.Join(xdg.StateHome, "tykctl", "telemetry", "stats.json")
+}
+
+// LocalReport returns the usage stats kept at the default path, without any
+// network access.
+func LocalReport() (*UsageReport, error) {
+	return NewStatsStore(GetDefaultStatsPath()).Report()
+}
+
+// WriteUsageReport writes a report as a table of commands.
+func WriteUsageReport(w io.Writer, report *UsageReport) error {
+	if len(report.Commands) == 0 {
+		_, err := fmt.Fprintln(w, "No commands recorded yet.")
+		return err
+	}
+
+	fmt.Fprintf(w, "%d commands run since %s\n\n", report.Total, report.Since.Local().Format("2006-01-02"))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMMAND\tRUNS\tFAILURES\tAVG DURATION\tLAST USED")
+	for _, stats := range report.Commands {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n",
+			stats.Command,
+			stats.Count,
+			stats.Failures,
+			stats.AverageDuration(),
+			stats.LastUsed.Local().Format("2006-01-02 15:04"),
+		)
+	}
+
+	return tw.Flush()
+}
+
+// NewStatsCommand creates a "stats" command that shows the user their own
+// command usage from the local stats store.
+func NewStatsCommand(store *StatsStore) *cobra.Command {
+	var asJSON, reset bool
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show your command usage",
+		Long:  "Show how often each command was run and how long it took, from statistics kept on this machine only.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if reset {
+				return store.Reset()
+			}
+
+			report, err := store.Report()
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+
+			return WriteUsageReport(cmd.OutOrStdout(), report)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the stats as JSON")
+	cmd.Flags().BoolVar(&reset, "reset", false, "Delete the recorded stats")
+
+	return cmd
+}
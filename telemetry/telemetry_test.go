@@ -1,6 +1,15 @@
 package telemetry
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -241,4 +250,784 @@ func TestDefaultConfig(t *testing.T) {
 	if config.UserAgent == "" {
 		t.Error("Default config should have a user agent")
 	}
-}
\ No newline at end of file
+}
+func TestExportAndPurgeLocalData(t *testing.T) {
+	storage := NewMemoryStorage()
+	client := NewClient(DefaultConfig(), NewMockTransport(), storage)
+	defer client.Close()
+
+	event := NewEventBuilder(EventTypeCommand).
+		Command("test").
+		Success(true).
+		Build()
+	event.UserID = "user-1"
+	event.SessionID = "session-1"
+
+	if err := client.Track(event); err != nil {
+		t.Fatalf("Failed to track event: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := client.ExportLocalData(&buf); err != nil {
+		t.Fatalf("Failed to export local data: %v", err)
+	}
+
+	var export LocalDataExport
+	if err := json.Unmarshal(buf.Bytes(), &export); err != nil {
+		t.Fatalf("Export is not valid JSON: %v", err)
+	}
+
+	if len(export.PendingEvents) != 1 {
+		t.Errorf("Expected 1 pending event, got %d", len(export.PendingEvents))
+	}
+
+	if len(export.Identifiers.UserIDs) != 1 || export.Identifiers.UserIDs[0] != "user-1" {
+		t.Errorf("Expected user ID 'user-1', got %v", export.Identifiers.UserIDs)
+	}
+
+	if len(export.Identifiers.SessionIDs) != 1 || export.Identifiers.SessionIDs[0] != "session-1" {
+		t.Errorf("Expected session ID 'session-1', got %v", export.Identifiers.SessionIDs)
+	}
+
+	if err := client.PurgeLocalData(); err != nil {
+		t.Fatalf("Failed to purge local data: %v", err)
+	}
+
+	if count, _ := storage.Count(); count != 0 {
+		t.Errorf("Expected no stored events after purge, got %d", count)
+	}
+}
+
+func TestSpoolDropsOldest(t *testing.T) {
+	spool := NewSpool(filepath.Join(t.TempDir(), "spool.jsonl"), 3, 0)
+
+	for _, command := range []string{"one", "two", "three", "four", "five"} {
+		event := NewEventBuilder(EventTypeCommand).Command(command).Build()
+		if err := spool.Store(event); err != nil {
+			t.Fatalf("Failed to store event: %v", err)
+		}
+	}
+
+	events, err := spool.Retrieve()
+	if err != nil {
+		t.Fatalf("Failed to retrieve events: %v", err)
+	}
+
+	if len(events) != 3 || events[0].Command != "three" || events[2].Command != "five" {
+		t.Errorf("Expected the three newest events, got %d", len(events))
+	}
+
+	if spool.Dropped() != 2 {
+		t.Errorf("Expected 2 dropped events, got %d", spool.Dropped())
+	}
+}
+
+func TestSpoolSizeLimit(t *testing.T) {
+	event := NewEventBuilder(EventTypeCommand).Command("test").Build()
+	event.ID = "00000000-0000-4000-8000-000000000000"
+	line, _ := json.Marshal(event)
+
+	spool := NewSpool(filepath.Join(t.TempDir(), "spool.jsonl"), 0, int64(2*(len(line)+1)))
+	for i := 0; i < 4; i++ {
+		if err := spool.Store(event); err != nil {
+			t.Fatalf("Failed to store event: %v", err)
+		}
+	}
+
+	if count, _ := spool.Count(); count != 2 {
+		t.Errorf("Expected 2 spooled events, got %d", count)
+	}
+}
+
+func TestSpoolAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool.jsonl")
+	spool := NewSpool(path, 0, 0)
+
+	if err := spool.Store(NewEventBuilder(EventTypeCommand).Command("one").Build()); err != nil {
+		t.Fatalf("Failed to store event: %v", err)
+	}
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat spool: %v", err)
+	}
+
+	event := NewEventBuilder(EventTypeCommand).Command("two").Build()
+	if err := spool.Store(event); err != nil {
+		t.Fatalf("Failed to store event: %v", err)
+	}
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat spool: %v", err)
+	}
+
+	if !os.SameFile(before, after) || after.Size() <= before.Size() {
+		t.Error("Expected the event to be appended to the spool file, not the file to be replaced")
+	}
+	if event.ID != "" {
+		t.Errorf("Expected the stored event to be left alone, got ID %q", event.ID)
+	}
+}
+
+func TestSpoolAcknowledge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool.jsonl")
+
+	// An event spooled without an ID
+	legacy, _ := json.Marshal(NewEventBuilder(EventTypeCommand).Command("legacy").Build())
+	if err := os.WriteFile(path, append(legacy, '\n'), 0600); err != nil {
+		t.Fatalf("Failed to write spool: %v", err)
+	}
+
+	spool := NewSpool(path, 0, 0)
+	for _, command := range []string{"one", "two"} {
+		if err := spool.Store(NewEventBuilder(EventTypeCommand).Command(command).Build()); err != nil {
+			t.Fatalf("Failed to store event: %v", err)
+		}
+	}
+
+	events, err := spool.Retrieve()
+	if err != nil || len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d (%v)", len(events), err)
+	}
+	for _, event := range events {
+		if event.ID == "" {
+			t.Errorf("Expected event %s to have an ID", event.Command)
+		}
+	}
+
+	// An event stored while the others are being sent
+	if err := spool.Store(NewEventBuilder(EventTypeCommand).Command("three").Build()); err != nil {
+		t.Fatalf("Failed to store event: %v", err)
+	}
+
+	if err := spool.Acknowledge([]string{events[0].ID, events[2].ID, "unknown"}); err != nil {
+		t.Fatalf("Failed to acknowledge events: %v", err)
+	}
+
+	remaining, err := spool.Retrieve()
+	if err != nil {
+		t.Fatalf("Failed to retrieve events: %v", err)
+	}
+	if len(remaining) != 2 || remaining[0].Command != "one" || remaining[1].Command != "three" {
+		t.Errorf("Expected events one and three to remain, got %d", len(remaining))
+	}
+}
+
+func TestSpoolReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool.jsonl")
+
+	config := DefaultConfig()
+	config.BatchSize = 1
+	config.RetryAttempts = 0
+
+	// First run: the network is down after the first batch
+	offline := NewMockTransport()
+	offline.errors = []error{nil, errors.New("network unreachable")}
+	first := NewClient(config, offline, NewSpool(path, 0, 0))
+
+	for _, command := range []string{"one", "two", "three"} {
+		if err := first.Track(NewEventBuilder(EventTypeCommand).Command(command).Build()); err != nil {
+			t.Fatalf("Failed to track event: %v", err)
+		}
+	}
+
+	if err := first.Flush(); err == nil {
+		t.Error("Expected flush to fail while offline")
+	}
+	first.(*client).cancel()
+
+	if count, _ := NewSpool(path, 0, 0).Count(); count != 2 {
+		t.Fatalf("Expected 2 spooled events, got %d", count)
+	}
+
+	// Next run: spooled events are replayed on start
+	online := NewMockTransport()
+	next := NewClient(config, online, NewSpool(path, 0, 0))
+	next.(*client).cancel()
+	next.(*client).wg.Wait()
+
+	sent := online.GetEvents()
+	if len(sent) != 2 || sent[0][0].Command != "two" || sent[1][0].Command != "three" {
+		t.Errorf("Expected events two and three to be replayed, got %d batches", len(sent))
+	}
+
+	if count, _ := NewSpool(path, 0, 0).Count(); count != 0 {
+		t.Errorf("Expected an empty spool after replay, got %d", count)
+	}
+}
+
+func TestSamplerRates(t *testing.T) {
+	config := DefaultConfig()
+	config.DailyQuota = 0
+	config.SampleRates[EventTypeCommand] = 0.1
+
+	sampler := NewSampler(config, "")
+	rolls := []float64{0.05, 0.5}
+	sampler.random = func() float64 {
+		roll := rolls[0]
+		rolls = rolls[1:]
+		return roll
+	}
+
+	command := NewEventBuilder(EventTypeCommand).Build()
+	if keep, rate := sampler.Sample(command); !keep || rate != 0.1 {
+		t.Errorf("Expected command event kept at rate 0.1, got %v at %v", keep, rate)
+	}
+	if keep, _ := sampler.Sample(command); keep {
+		t.Error("Expected command event above the sample rate to be dropped")
+	}
+
+	if keep, rate := sampler.Sample(NewEventBuilder(EventTypeError).Build()); !keep || rate != 1 {
+		t.Errorf("Expected error event always kept, got %v at %v", keep, rate)
+	}
+}
+
+func TestSamplerDailyQuota(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota.json")
+	config := DefaultConfig()
+	config.DailyQuota = 2
+
+	day := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newSampler := func() *Sampler {
+		sampler := NewSampler(config, path)
+		sampler.now = func() time.Time { return day }
+		return sampler
+	}
+
+	event := NewEventBuilder(EventTypeFeature).Build()
+	first := newSampler()
+	first.Sample(event)
+
+	// The quota is shared with later runs on the same day
+	second := newSampler()
+	if keep, _ := second.Sample(event); !keep {
+		t.Error("Expected second event within quota")
+	}
+	if keep, _ := second.Sample(event); keep {
+		t.Error("Expected third event over quota to be dropped")
+	}
+	if remaining := second.Remaining(); remaining != 0 {
+		t.Errorf("Expected no remaining quota, got %d", remaining)
+	}
+
+	day = day.Add(24 * time.Hour)
+	if keep, _ := newSampler().Sample(event); !keep {
+		t.Error("Expected quota to reset on a new day")
+	}
+}
+
+func TestClientSampling(t *testing.T) {
+	config := DefaultConfig()
+	config.SampleRates[EventTypeCommand] = 0.5
+
+	sampler := NewSampler(config, "")
+	sampler.random = func() float64 { return 0.1 }
+
+	storage := NewMemoryStorage()
+	client := NewClient(config, NewMockTransport(), storage, WithSampler(sampler))
+	defer client.Close()
+
+	if err := client.Track(NewEventBuilder(EventTypeCommand).Command("test").Build()); err != nil {
+		t.Fatalf("Failed to track event: %v", err)
+	}
+
+	events, _ := storage.Retrieve()
+	if len(events) != 1 || events[0].Properties[PropertySampleRate] != 0.5 {
+		t.Errorf("Expected stored event with sample rate 0.5, got %v", events)
+	}
+}
+
+func TestIdentityStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "install_id")
+
+	id, err := NewIdentityStore(path).InstallID()
+	if err != nil {
+		t.Fatalf("Failed to get install ID: %v", err)
+	}
+	if len(id) != 36 {
+		t.Errorf("Expected a UUID, got %q", id)
+	}
+
+	store := NewIdentityStore(path)
+	if again, _ := store.InstallID(); again != id {
+		t.Errorf("Expected install ID %q to be stable, got %q", id, again)
+	}
+
+	rotated, err := store.Rotate()
+	if err != nil {
+		t.Fatalf("Failed to rotate install ID: %v", err)
+	}
+	if rotated == id {
+		t.Error("Expected a new install ID after rotation")
+	}
+	if current, _ := NewIdentityStore(path).InstallID(); current != rotated {
+		t.Errorf("Expected rotated install ID %q to be saved, got %q", rotated, current)
+	}
+
+	if err := store.Reset(); err != nil {
+		t.Fatalf("Failed to reset install ID: %v", err)
+	}
+	if current, _ := store.InstallID(); current == rotated || current == "" {
+		t.Errorf("Expected a fresh install ID after reset, got %q", current)
+	}
+}
+
+func TestClientIdentity(t *testing.T) {
+	identity := NewIdentityStore(filepath.Join(t.TempDir(), "install_id"))
+	storage := NewMemoryStorage()
+	client := NewClient(DefaultConfig(), NewMockTransport(), storage, WithIdentity(identity))
+	defer client.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := client.Track(NewEventBuilder(EventTypeCommand).Command("test").Build()); err != nil {
+			t.Fatalf("Failed to track event: %v", err)
+		}
+	}
+
+	installID, _ := identity.InstallID()
+	events, _ := storage.Retrieve()
+	for _, event := range events {
+		if event.UserID != installID {
+			t.Errorf("Expected user ID %q, got %q", installID, event.UserID)
+		}
+		if event.SessionID == "" || event.SessionID != SessionID() {
+			t.Errorf("Expected session ID %q, got %q", SessionID(), event.SessionID)
+		}
+	}
+
+	if err := client.PurgeLocalData(); err != nil {
+		t.Fatalf("Failed to purge local data: %v", err)
+	}
+	if _, err := os.Stat(identity.Path()); !os.IsNotExist(err) {
+		t.Error("Expected purge to remove the install ID")
+	}
+}
+
+func TestRedactStack(t *testing.T) {
+	stack := []byte(`goroutine 1 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+github.com/example/app.(*Server).login(0xc000010000, {0xc00001a0c0, 0x6})
+	/home/alice/src/app/server.go:42 +0x1d
+main.main()
+	/home/alice/src/app/main.go:10 +0x25
+created by main.start in goroutine 1
+	/home/alice/src/app/main.go:20 +0x30
+`)
+
+	expected := []string{
+		"github.com/example/app.(*Server).login(...) server.go:42",
+		"main.main(...) main.go:10",
+		"created by main.start main.go:20",
+	}
+
+	frames := RedactStack(stack)
+	if len(frames) != len(expected) {
+		t.Fatalf("Expected %d frames, got %v", len(expected), frames)
+	}
+	for i, frame := range frames {
+		if frame != expected[i] {
+			t.Errorf("Expected frame %q, got %q", expected[i], frame)
+		}
+	}
+}
+
+func TestRecoverAndReport(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.CrashReports = true
+
+	transport := NewMockTransport()
+	client := NewClient(config, transport, NewMemoryStorage())
+	defer client.Close()
+
+	reporter := NewCrashReporter(client, config, dir)
+	reporter.stderr = &bytes.Buffer{}
+	ctx := ContextWithCrashReporter(context.Background(), reporter)
+
+	var repanicked interface{}
+	func() {
+		defer func() { repanicked = recover() }()
+		defer RecoverAndReport(ctx)
+		panic("token abc123 leaked")
+	}()
+
+	if repanicked != "token abc123 leaked" {
+		t.Errorf("Expected the panic to be raised again, got %v", repanicked)
+	}
+
+	reports, err := reporter.ListCrashReports()
+	if err != nil || len(reports) != 1 {
+		t.Fatalf("Expected 1 local crash report, got %d (%v)", len(reports), err)
+	}
+	if reports[0].Panic != "token abc123 leaked" || reports[0].Stack == "" {
+		t.Error("Expected the local report to keep the raw panic and stack")
+	}
+
+	sent := transport.GetEvents()
+	if len(sent) != 1 || sent[0][0].EventType != EventTypeCrash {
+		t.Fatalf("Expected 1 crash event to be sent, got %v", sent)
+	}
+	payload, _ := json.Marshal(sent[0][0])
+	if bytes.Contains(payload, []byte("abc123")) {
+		t.Errorf("Expected the crash event not to contain the panic value, got %s", payload)
+	}
+}
+
+func TestCrashReportsOptIn(t *testing.T) {
+	transport := NewMockTransport()
+	client := NewClient(DefaultConfig(), transport, NewMemoryStorage())
+	defer client.Close()
+
+	reporter := NewCrashReporter(client, DefaultConfig(), t.TempDir())
+	if _, err := reporter.Report("tykctl test", "boom", []byte{}); err != nil {
+		t.Fatalf("Failed to report crash: %v", err)
+	}
+
+	if len(transport.GetEvents()) != 0 {
+		t.Error("Expected no crash event without opting in")
+	}
+}
+
+// fakePrompter answers consent questions with a fixed answer.
+type fakePrompter struct {
+	interactive bool
+	answer      bool
+	asked       int
+}
+
+func (p *fakePrompter) IsInteractive() bool { return p.interactive }
+
+func (p *fakePrompter) AskConfirmationWithDefault(question string, defaultValue bool) (bool, error) {
+	p.asked++
+	return p.answer, nil
+}
+
+func TestConsentManager(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "consent.yaml")
+	prompter := &fakePrompter{interactive: true, answer: true}
+
+	manager := NewConsentManager(prompter, path, "1")
+	manager.SetOutput(&bytes.Buffer{})
+
+	for i := 0; i < 2; i++ {
+		granted, err := manager.Ensure()
+		if err != nil || !granted {
+			t.Fatalf("Expected consent to be granted, got %v (%v)", granted, err)
+		}
+	}
+	if prompter.asked != 1 {
+		t.Errorf("Expected to be asked once, got %d", prompter.asked)
+	}
+
+	decision, _ := manager.Decision()
+	if decision == nil || decision.PolicyVersion != "1" || decision.DecidedAt.IsZero() {
+		t.Errorf("Expected a recorded decision for policy 1, got %+v", decision)
+	}
+
+	// A new policy version asks again
+	prompter.answer = false
+	updated := NewConsentManager(prompter, path, "2")
+	var out bytes.Buffer
+	updated.SetOutput(&out)
+
+	if granted, _ := updated.Ensure(); granted {
+		t.Error("Expected consent to be declined for the new policy")
+	}
+	if prompter.asked != 2 {
+		t.Errorf("Expected to be asked again for a new policy, got %d", prompter.asked)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("policy has changed")) {
+		t.Errorf("Expected a policy change notice, got %q", out.String())
+	}
+}
+
+func TestConsentManagerNonInteractive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "consent.yaml")
+	manager := NewConsentManager(&fakePrompter{}, path, "")
+
+	granted, err := manager.Ensure()
+	if err != nil || granted {
+		t.Errorf("Expected no consent without a terminal, got %v (%v)", granted, err)
+	}
+
+	if needs, _ := manager.NeedsConsent(); !needs {
+		t.Error("Expected consent to still be needed")
+	}
+}
+
+func TestSanitizerRules(t *testing.T) {
+	sanitizer := NewSanitizer()
+	sanitizer.RedactKeys("Tenant")
+	if err := sanitizer.RedactKeyPattern(`(?i)_id$`); err != nil {
+		t.Fatal(err)
+	}
+	if err := sanitizer.RedactValuePattern(`https?://[^\s]+`, "<url>"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sanitizer.RedactValuePattern(`[`, ""); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+
+	event := &Event{
+		EventType: EventTypeCommand,
+		Properties: map[string]interface{}{
+			"tenant":  "acme",
+			"org_id":  "1234",
+			"api_key": "secret",
+			"target":  "calling https://dashboard.acme.internal/api",
+			"nested": map[string]interface{}{
+				"password": "hunter2",
+				"hosts":    []interface{}{"http://a.internal", 3},
+			},
+			"count": 2,
+		},
+		ErrorMessage: "GET http://gateway.internal failed",
+	}
+
+	sanitized := sanitizer.Sanitize(event)
+
+	for _, key := range []string{"tenant", "org_id", "api_key"} {
+		if _, ok := sanitized.Properties[key]; ok {
+			t.Errorf("Expected %s to be removed", key)
+		}
+	}
+	if sanitized.Properties["target"] != "calling <url>" {
+		t.Errorf("Expected URL to be redacted, got %v", sanitized.Properties["target"])
+	}
+	nested := sanitized.Properties["nested"].(map[string]interface{})
+	if _, ok := nested["password"]; ok {
+		t.Error("Expected nested password to be removed")
+	}
+	if hosts := nested["hosts"].([]interface{}); hosts[0] != "<url>" || hosts[1] != 3 {
+		t.Errorf("Expected nested values to be sanitized, got %v", hosts)
+	}
+	if sanitized.ErrorMessage != "GET <url> failed" {
+		t.Errorf("Expected error message to be sanitized, got %q", sanitized.ErrorMessage)
+	}
+
+	if event.Properties["api_key"] != "secret" {
+		t.Error("Expected the original event to be left unchanged")
+	}
+}
+
+func TestMetricsRegistry(t *testing.T) {
+	registry := NewMetricsRegistry("", 1, 5)
+	registry.Observe("tykctl api list", 500*time.Millisecond, true, "")
+	registry.Observe("tykctl api list", 3*time.Second, false, "command_error")
+	registry.ObserveEvent(NewEventBuilder(EventTypeFeature).Feature("ignored").Build())
+
+	var buf bytes.Buffer
+	if err := registry.WritePrometheus(&buf); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+
+	for _, line := range []string{
+		`# TYPE tykctl_commands_total counter`,
+		`tykctl_commands_total{command="tykctl api list",status="success"} 1`,
+		`tykctl_commands_total{command="tykctl api list",status="error"} 1`,
+		`tykctl_command_errors_total{command="tykctl api list",error_type="command_error"} 1`,
+		`tykctl_command_duration_seconds_bucket{command="tykctl api list",le="1"} 1`,
+		`tykctl_command_duration_seconds_bucket{command="tykctl api list",le="5"} 2`,
+		`tykctl_command_duration_seconds_bucket{command="tykctl api list",le="+Inf"} 2`,
+		`tykctl_command_duration_seconds_sum{command="tykctl api list"} 3.5`,
+		`tykctl_command_duration_seconds_count{command="tykctl api list"} 2`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(line+"\n")) {
+			t.Errorf("Expected line %q in output:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestMetricsPush(t *testing.T) {
+	var method, path string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	registry := NewMetricsRegistry("ext")
+	client := NewClient(DefaultConfig(), NewMockTransport(), NewMemoryStorage(), WithMetrics(registry))
+	defer client.Close()
+	client.SetEnabled(false)
+
+	event := NewEventBuilder(EventTypeCommand).Command("ext sync").Duration(time.Second).Success(true).Build()
+	if err := client.Track(event); err != nil {
+		t.Fatalf("Failed to track event: %v", err)
+	}
+
+	if err := registry.Push(context.Background(), server.URL, "sync daemon"); err != nil {
+		t.Fatalf("Failed to push metrics: %v", err)
+	}
+
+	if method != http.MethodPut || path != "/metrics/job/sync daemon" {
+		t.Errorf("Expected PUT to /metrics/job/sync daemon, got %s %s", method, path)
+	}
+	if !bytes.Contains(body, []byte(`ext_commands_total{command="ext sync",status="success"} 1`)) {
+		t.Errorf("Expected pushed metrics to include the command, got:\n%s", body)
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	config := DefaultConfig()
+	if _, ok := mustTransport(t, config).(*HTTPTransport); !ok {
+		t.Errorf("Expected HTTP transport for the default backend")
+	}
+
+	config.Backend = BackendSegment
+	if _, err := NewTransport(config); err == nil {
+		t.Errorf("Expected error for segment backend without a write key")
+	}
+
+	config.WriteKey = "key"
+	transport := mustTransport(t, config).(*SegmentTransport)
+	if transport.endpoint != DefaultSegmentEndpoint+"/v1/batch" {
+		t.Errorf("Expected default Segment endpoint, got %s", transport.endpoint)
+	}
+
+	config.Backend = "unknown"
+	if _, err := NewTransport(config); err == nil {
+		t.Errorf("Expected error for unknown backend")
+	}
+}
+
+func mustTransport(t *testing.T, config *Config) Transport {
+	t.Helper()
+	transport, err := NewTransport(config)
+	if err != nil {
+		t.Fatalf("Failed to create transport: %v", err)
+	}
+	return transport
+}
+
+func TestSegmentTransport(t *testing.T) {
+	var path, user string
+	var payload struct {
+		Batch []map[string]interface{} `json:"batch"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		user, _, _ = r.BasicAuth()
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.Backend = BackendSegment
+	config.Endpoint = server.URL
+	config.WriteKey = "write-key"
+	transport := mustTransport(t, config)
+
+	event := NewEventBuilder(EventTypeCommand).Command("tykctl api list").Success(true).Build()
+	event.UserID = "install-1"
+	if err := transport.Send([]*Event{event}); err != nil {
+		t.Fatalf("Failed to send events: %v", err)
+	}
+
+	if path != "/v1/batch" || user != "write-key" {
+		t.Errorf("Expected authenticated request to /v1/batch, got %s as %q", path, user)
+	}
+	if len(payload.Batch) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(payload.Batch))
+	}
+	message := payload.Batch[0]
+	if message["type"] != "track" || message["event"] != "command" || message["anonymousId"] != "install-1" {
+		t.Errorf("Expected track call for install-1, got %v", message)
+	}
+	if properties := message["properties"].(map[string]interface{}); properties["command"] != "tykctl api list" {
+		t.Errorf("Expected command property, got %v", properties)
+	}
+}
+
+func TestPostHogTransport(t *testing.T) {
+	var path string
+	var payload struct {
+		APIKey string                   `json:"api_key"`
+		Batch  []map[string]interface{} `json:"batch"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	transport := NewPostHogTransport(server.URL, "project-key", "test", time.Second)
+
+	event := NewEventBuilder(EventTypeFeature).Feature("export").Build()
+	event.UserID = "install-1"
+	event.SessionID = "session-1"
+	if err := transport.Send([]*Event{event}); err != nil {
+		t.Fatalf("Failed to send events: %v", err)
+	}
+
+	if path != "/batch/" || payload.APIKey != "project-key" {
+		t.Errorf("Expected request to /batch/ with the API key, got %s with %q", path, payload.APIKey)
+	}
+	if len(payload.Batch) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(payload.Batch))
+	}
+	captured := payload.Batch[0]
+	properties := captured["properties"].(map[string]interface{})
+	if captured["distinct_id"] != "install-1" || properties["$session_id"] != "session-1" || properties["feature"] != "export" {
+		t.Errorf("Expected feature event for install-1, got %v", captured)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+	if err := NewPostHogTransport(failing.URL, "bad", "test", time.Second).Send([]*Event{event}); err == nil {
+		t.Errorf("Expected error for rejected batch")
+	}
+}
+
+func TestStatsStore(t *testing.T) {
+	store := NewStatsStore(filepath.Join(t.TempDir(), "stats.json"))
+	client := NewClient(DefaultConfig(), NewMockTransport(), NewMemoryStorage(), WithStats(store))
+	defer client.Close()
+	client.SetEnabled(false)
+
+	track := func(command string, duration time.Duration, success bool) {
+		event := NewEventBuilder(EventTypeCommand).Command(command).Duration(duration).Success(success).Build()
+		if err := client.Track(event); err != nil {
+			t.Fatalf("Failed to track event: %v", err)
+		}
+	}
+	track("tykctl api list", 100*time.Millisecond, true)
+	track("tykctl api list", 300*time.Millisecond, false)
+	track("tykctl api get", time.Second, true)
+	client.Track(NewEventBuilder(EventTypeFeature).Feature("export").Build())
+
+	report, err := NewStatsStore(store.Path()).Report()
+	if err != nil {
+		t.Fatalf("Failed to build report: %v", err)
+	}
+
+	if report.Total != 3 || len(report.Commands) != 2 {
+		t.Fatalf("Expected 3 runs of 2 commands, got %d runs of %d commands", report.Total, len(report.Commands))
+	}
+	list := report.Commands[0]
+	if list.Command != "tykctl api list" || list.Count != 2 || list.Failures != 1 {
+		t.Errorf("Expected api list first with 2 runs and 1 failure, got %+v", list)
+	}
+	if list.AverageDuration() != 200*time.Millisecond {
+		t.Errorf("Expected average duration 200ms, got %v", list.AverageDuration())
+	}
+
+	var buf bytes.Buffer
+	if err := WriteUsageReport(&buf, report); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("tykctl api get")) {
+		t.Errorf("Expected report to list api get, got:\n%s", buf.String())
+	}
+
+	if err := client.PurgeLocalData(); err != nil {
+		t.Fatalf("Failed to purge local data: %v", err)
+	}
+	if report, _ := store.Report(); report.Total != 0 {
+		t.Errorf("Expected no stats after purge, got %d runs", report.Total)
+	}
+}
@@ -4,6 +4,7 @@
 package telemetry
 
 import (
+	"io"
 	"time"
 )
 
@@ -19,10 +20,16 @@ const (
 	EventTypeFeature EventType = "feature"
 	// EventTypePerformance represents a performance metric event.
 	EventTypePerformance EventType = "performance"
+	// EventTypeCrash represents a panic reported by a crash reporter.
+	EventTypeCrash EventType = "crash"
 )
 
 // Event represents a telemetry event to be collected.
 type Event struct {
+	// ID uniquely identifies the event, so storages can remove it once it
+	// has been sent.
+	ID string `json:"id,omitempty"`
+	
 	// EventType is the type of event being recorded.
 	EventType EventType `json:"event_type"`
 	
@@ -50,10 +57,11 @@ type Event struct {
 	// Timestamp is when the event occurred.
 	Timestamp time.Time `json:"timestamp"`
 	
-	// SessionID is an anonymous session identifier.
+	// SessionID is an anonymous identifier of the process that tracked the
+	// event.
 	SessionID string `json:"session_id"`
 	
-	// UserID is an anonymous user identifier (hashed).
+	// UserID is the anonymous installation identifier.
 	UserID string `json:"user_id"`
 	
 	// CLI version information.
@@ -71,9 +79,17 @@ type Config struct {
 	// Enabled controls whether telemetry is active.
 	Enabled bool `yaml:"enabled" json:"enabled"`
 	
-	// Endpoint is the telemetry data collection endpoint.
+	// Backend selects where events are sent: "http" (the default) posts
+	// batches to Endpoint, "segment" and "posthog" use those services.
+	Backend string `yaml:"backend,omitempty" json:"backend,omitempty"`
+	
+	// Endpoint is the telemetry data collection endpoint. For the Segment
+	// and PostHog backends it overrides the service's base URL.
 	Endpoint string `yaml:"endpoint" json:"endpoint"`
 	
+	// WriteKey is the Segment write key or PostHog project API key.
+	WriteKey string `yaml:"write_key,omitempty" json:"write_key,omitempty"`
+	
 	// BatchSize is the maximum number of events to batch before sending.
 	BatchSize int `yaml:"batch_size" json:"batch_size"`
 	
@@ -91,19 +107,44 @@ type Config struct {
 	
 	// UserAgent is the user agent string for HTTP requests.
 	UserAgent string `yaml:"user_agent" json:"user_agent"`
+	
+	// SpoolMaxEvents is the maximum number of events kept while they cannot
+	// be sent. The oldest events are dropped beyond it.
+	SpoolMaxEvents int `yaml:"spool_max_events" json:"spool_max_events"`
+	
+	// SpoolMaxBytes is the maximum size of the offline spool in bytes. The
+	// oldest events are dropped beyond it.
+	SpoolMaxBytes int64 `yaml:"spool_max_bytes" json:"spool_max_bytes"`
+	
+	// SampleRates is the probability, from 0 to 1, that an event of each
+	// type is tracked. Types without a rate are always tracked.
+	SampleRates map[EventType]float64 `yaml:"sample_rates,omitempty" json:"sample_rates,omitempty"`
+	
+	// DailyQuota is the maximum number of events tracked per UTC day.
+	// Zero means no limit.
+	DailyQuota int `yaml:"daily_quota" json:"daily_quota"`
+	
+	// CrashReports opts in to sending crash events when the CLI panics.
+	// Crash reports are always written locally.
+	CrashReports bool `yaml:"crash_reports" json:"crash_reports"`
 }
 
 // DefaultConfig returns the default telemetry configuration.
 func DefaultConfig() *Config {
 	return &Config{
 		Enabled:        true,
-		Endpoint:       "https://telemetry.tyk.io/v1/events",
+		Backend:        BackendHTTP,
+		Endpoint:       DefaultEndpoint,
 		BatchSize:      100,
 		FlushInterval:  5 * time.Minute,
 		RetryAttempts:  3,
 		RetryDelay:     1 * time.Second,
 		Timeout:        30 * time.Second,
 		UserAgent:      "tykctl-go-telemetry/1.0",
+		SpoolMaxEvents: DefaultSpoolMaxEvents,
+		SpoolMaxBytes:  DefaultSpoolMaxBytes,
+		SampleRates:    make(map[EventType]float64),
+		DailyQuota:     DefaultDailyQuota,
 	}
 }
 
@@ -123,6 +164,12 @@ type Client interface {
 	
 	// SetEnabled enables or disables telemetry.
 	SetEnabled(enabled bool) error
+	
+	// ExportLocalData writes all locally stored telemetry data to w as JSON.
+	ExportLocalData(w io.Writer) error
+	
+	// PurgeLocalData removes all locally stored telemetry data.
+	PurgeLocalData() error
 }
 
 // Transport represents a transport mechanism for sending telemetry data.
@@ -222,50 +269,14 @@ func (b *EventBuilder) Build() *Event {
 	return b.event
 }
 
-// SanitizeEvent removes or masks sensitive information from an event.
+// SanitizeEvent removes or masks sensitive information from an event using
+// the default sanitizer.
 func SanitizeEvent(event *Event) *Event {
-	sanitized := *event
-	
-	// Remove any properties that might contain sensitive data
-	if sanitized.Properties != nil {
-		sensitiveKeys := []string{
-			"token", "key", "secret", "password", "auth",
-			"credential", "api_key", "access_token", "refresh_token",
-		}
-		
-		for _, key := range sensitiveKeys {
-			delete(sanitized.Properties, key)
-		}
-	}
-	
-	// Sanitize error messages to remove sensitive data
-	if sanitized.ErrorMessage != "" {
-		sanitized.ErrorMessage = sanitizeErrorMessage(sanitized.ErrorMessage)
-	}
-	
-	return &sanitized
+	return DefaultSanitizer().Sanitize(event)
 }
 
-// sanitizeErrorMessage removes sensitive information from error messages.
+// sanitizeErrorMessage removes sensitive information from error messages
+// using the default sanitizer.
 func sanitizeErrorMessage(msg string) string {
-	// This is a simple implementation - in practice, you might want
-	// more sophisticated sanitization based on your specific needs
-	sensitivePatterns := []string{
-		"token", "key", "secret", "password", "auth",
-		"credential", "api_key", "access_token", "refresh_token",
-	}
-	
-	sanitized := msg
-	for _, pattern := range sensitivePatterns {
-		// Replace sensitive patterns with [REDACTED]
-		// This is a simplified approach - you might want regex-based replacement
-		if len(sanitized) > 0 {
-			// Simple string replacement for demonstration
-			// In practice, use regex for more sophisticated matching
-			// For now, just return the original message
-			_ = pattern // Avoid unused variable warning
-		}
-	}
-	
-	return sanitized
-}
\ No newline at end of file
+	return DefaultSanitizer().SanitizeString(msg)
+}
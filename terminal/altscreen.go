@@ -0,0 +1,163 @@
+package terminal
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Alternate screen and cursor escape sequences
+const (
+	enterAltScreen = "\x1b[?1049h"
+	exitAltScreen  = "\x1b[?1049l"
+	showCursor     = "\x1b[?25h"
+	hideCursor     = "\x1b[?25l"
+	clearLine      = "\r\x1b[2K"
+	cursorUp       = "\x1b[1A"
+)
+
+var (
+	// altScreenOutput is where alternate screen and cursor sequences are
+	// written
+	altScreenOutput io.Writer = os.Stdout
+
+	// altScreenDepth counts nested WithAltScreen and EnterAltScreen calls,
+	// and explicitAltDepth the EnterAltScreen calls among them
+	altScreenDepth   int
+	explicitAltDepth int
+	altScreenMu      sync.Mutex
+)
+
+// WithAltScreen runs fn on the alternate screen buffer, so full-screen UIs
+// don't leave output in the user's scrollback. The main screen is restored
+// when fn returns, panics, or the process receives an interrupt or
+// termination signal; on a signal the context passed to fn is also cancelled.
+// Nested calls share the outer alternate screen, and nothing is switched when
+// output is not a terminal.
+func WithAltScreen(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !enterAlt() {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var once sync.Once
+	restore := func() {
+		once.Do(exitAlt)
+	}
+	defer restore()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-signals:
+			restore()
+			cancel()
+		case <-done:
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// EnterAltScreen switches to the alternate screen, for components that
+// render transient UI outside Bubble Tea and can't wrap it in
+// WithAltScreen. Each call must be paired with ExitAltScreen; until then, an
+// interrupt or termination signal restores the main screen before the
+// process exits, and Restore can be deferred in main to restore it on a
+// panic. Nothing is switched when output is not a terminal
+func EnterAltScreen() {
+	if !enterAlt() {
+		return
+	}
+
+	altScreenMu.Lock()
+	defer altScreenMu.Unlock()
+	explicitAltDepth++
+	updateGuardLocked()
+}
+
+// ExitAltScreen leaves the alternate screen entered by EnterAltScreen, once
+// the outermost call finishes
+func ExitAltScreen() {
+	altScreenMu.Lock()
+	defer altScreenMu.Unlock()
+
+	if explicitAltDepth == 0 {
+		return
+	}
+	explicitAltDepth--
+	exitAltLocked()
+	updateGuardLocked()
+}
+
+// InAltScreen returns whether a WithAltScreen or EnterAltScreen call is
+// active
+func InAltScreen() bool {
+	altScreenMu.Lock()
+	defer altScreenMu.Unlock()
+	return altScreenDepth > 0
+}
+
+// AltScreenOptions returns the Bubble Tea program options for a full-screen
+// component. Inside WithAltScreen the program renders on the screen that is
+// already active, since letting Bubble Tea toggle the alternate screen itself
+// would return to the main screen when the program exits.
+func AltScreenOptions() []tea.ProgramOption {
+	if InAltScreen() {
+		return nil
+	}
+	return []tea.ProgramOption{tea.WithAltScreen()}
+}
+
+// enterAlt switches to the alternate screen unless it is already active, and
+// returns whether the caller is responsible for restoring the main screen
+func enterAlt() bool {
+	altScreenMu.Lock()
+	defer altScreenMu.Unlock()
+
+	if altScreenDepth > 0 {
+		altScreenDepth++
+		return true
+	}
+	if !New().IsTTY() {
+		return false
+	}
+
+	altScreenDepth++
+	io.WriteString(altScreenOutput, enterAltScreen)
+	return true
+}
+
+// exitAlt leaves the alternate screen once the outermost call finishes
+func exitAlt() {
+	altScreenMu.Lock()
+	defer altScreenMu.Unlock()
+	exitAltLocked()
+	updateGuardLocked()
+}
+
+// exitAltLocked is exitAlt for callers holding altScreenMu. The screen may
+// already have been restored by Restore
+func exitAltLocked() {
+	if altScreenDepth == 0 {
+		return
+	}
+	altScreenDepth--
+	if altScreenDepth == 0 {
+		io.WriteString(altScreenOutput, exitAltScreen+showCursor)
+		cursorHidden = false
+	}
+}
@@ -0,0 +1,137 @@
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func useAltScreenOutput(t *testing.T, forceTTY bool) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := altScreenOutput
+	altScreenOutput = &buf
+	t.Cleanup(func() { altScreenOutput = previous })
+
+	if forceTTY {
+		t.Setenv("TYKCTL_FORCE_TTY", "1")
+	} else {
+		t.Setenv("TYKCTL_FORCE_TTY", "")
+	}
+	return &buf
+}
+
+func TestWithAltScreen(t *testing.T) {
+	buf := useAltScreenOutput(t, true)
+
+	expectedErr := errors.New("done")
+	err := WithAltScreen(context.Background(), func(ctx context.Context) error {
+		if !InAltScreen() {
+			t.Error("InAltScreen() should be true inside WithAltScreen")
+		}
+		if len(AltScreenOptions()) != 0 {
+			t.Error("AltScreenOptions() should be empty inside WithAltScreen")
+		}
+
+		// Nested calls reuse the outer alternate screen
+		return WithAltScreen(ctx, func(ctx context.Context) error {
+			return expectedErr
+		})
+	})
+
+	if err != expectedErr {
+		t.Errorf("Expected %v, got %v", expectedErr, err)
+	}
+	if InAltScreen() {
+		t.Error("InAltScreen() should be false after WithAltScreen returns")
+	}
+	if buf.String() != enterAltScreen+exitAltScreen+showCursor {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+	if len(AltScreenOptions()) != 1 {
+		t.Error("AltScreenOptions() should enable the alternate screen outside WithAltScreen")
+	}
+}
+
+func TestWithAltScreenRestoresOnPanic(t *testing.T) {
+	buf := useAltScreenOutput(t, true)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic to propagate")
+			}
+		}()
+		WithAltScreen(context.Background(), func(ctx context.Context) error {
+			panic("boom")
+		})
+	}()
+
+	if InAltScreen() {
+		t.Error("InAltScreen() should be false after a panic")
+	}
+	if buf.String() != enterAltScreen+exitAltScreen+showCursor {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestWithAltScreenNotTTY(t *testing.T) {
+	buf := useAltScreenOutput(t, false)
+
+	called := false
+	WithAltScreen(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	if !called {
+		t.Error("fn should be called when output is not a terminal")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got %q", buf.String())
+	}
+}
+
+func TestEnterAltScreen(t *testing.T) {
+	buf := useAltScreenOutput(t, true)
+	t.Cleanup(Restore)
+
+	EnterAltScreen()
+	EnterAltScreen()
+	if !InAltScreen() {
+		t.Error("InAltScreen() should be true after EnterAltScreen")
+	}
+	if len(AltScreenOptions()) != 0 {
+		t.Error("AltScreenOptions() should be empty after EnterAltScreen")
+	}
+
+	ExitAltScreen()
+	if !InAltScreen() {
+		t.Error("Nested EnterAltScreen calls should keep the alternate screen")
+	}
+	ExitAltScreen()
+	ExitAltScreen()
+
+	if InAltScreen() {
+		t.Error("InAltScreen() should be false after ExitAltScreen")
+	}
+	if buf.String() != enterAltScreen+exitAltScreen+showCursor {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestRestoreInsideWithAltScreen(t *testing.T) {
+	buf := useAltScreenOutput(t, true)
+
+	WithAltScreen(context.Background(), func(ctx context.Context) error {
+		Restore()
+		return nil
+	})
+
+	if InAltScreen() {
+		t.Error("InAltScreen() should be false after Restore")
+	}
+	if buf.String() != enterAltScreen+exitAltScreen+showCursor {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
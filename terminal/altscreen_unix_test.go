@@ -0,0 +1,66 @@
+//go:build unix
+
+package terminal
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWithAltScreenRestoresOnSignal(t *testing.T) {
+	buf := useAltScreenOutput(t, true)
+
+	err := WithAltScreen(context.Background(), func(ctx context.Context) error {
+		syscall.Kill(os.Getpid(), syscall.SIGTERM)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("Context was not cancelled by the signal")
+		}
+
+		if InAltScreen() {
+			t.Error("Main screen should be restored as soon as the signal arrives")
+		}
+		return ctx.Err()
+	})
+
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if buf.String() != enterAltScreen+exitAltScreen+showCursor {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestEnterAltScreenRestoresOnSignal(t *testing.T) {
+	buf := useAltScreenOutput(t, true)
+
+	exited := make(chan int, 1)
+	previous := exitProcess
+	exitProcess = func(code int) { exited <- code }
+	t.Cleanup(func() { exitProcess = previous })
+
+	EnterAltScreen()
+	HideCursor()
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+
+	select {
+	case code := <-exited:
+		if code != 143 {
+			t.Errorf("Expected exit status 143, got %d", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("The signal did not restore the terminal")
+	}
+
+	if InAltScreen() {
+		t.Error("Main screen should be restored when the signal arrives")
+	}
+	if buf.String() != enterAltScreen+hideCursor+exitAltScreen+showCursor {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,111 @@
+package terminal
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Background is whether the terminal draws on a dark or light background
+type Background int
+
+// Backgrounds
+const (
+	// BackgroundUnknown means the background could not be detected
+	BackgroundUnknown Background = iota
+	BackgroundDark
+	BackgroundLight
+)
+
+// String names the background
+func (b Background) String() string {
+	switch b {
+	case BackgroundDark:
+		return "dark"
+	case BackgroundLight:
+		return "light"
+	}
+	return "unknown"
+}
+
+// EnvBackground is the environment variable overriding background
+// detection, set to dark or light
+const EnvBackground = "TYKCTL_BACKGROUND"
+
+// DefaultBackgroundTimeout is how long DetectBackground waits for the
+// terminal to answer by default. Local terminals answer within a few
+// milliseconds; the rest is for SSH sessions
+const DefaultBackgroundTimeout = 100 * time.Millisecond
+
+// osc11Pattern matches the reply to an OSC 11 query, such as
+// "\x1b]11;rgb:1e1e/1e1e/1e1e\x1b\\"
+var osc11Pattern = regexp.MustCompile(`\x1b\]11;rgb:([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})`)
+
+// DetectBackground returns whether the terminal background is dark or
+// light. TYKCTL_BACKGROUND is used when set, then COLORFGBG, which some
+// terminals such as rxvt and Konsole set. Otherwise, when stdin and stdout
+// are terminals, the terminal is asked for its background color with an
+// OSC 11 query, waiting at most timeout for an answer. Terminals that don't
+// support the query are detected without waiting, as they answer the
+// device attributes query sent along with it
+func DetectBackground(timeout time.Duration) Background {
+	switch strings.ToLower(os.Getenv(EnvBackground)) {
+	case "dark":
+		return BackgroundDark
+	case "light":
+		return BackgroundLight
+	}
+	if bg := backgroundFromColorFGBG(os.Getenv("COLORFGBG")); bg != BackgroundUnknown {
+		return bg
+	}
+
+	if !IsTerminal(os.Stdin.Fd()) || !IsTerminal(os.Stdout.Fd()) || os.Getenv("TERM") == "dumb" {
+		return BackgroundUnknown
+	}
+	reply, err := queryBackgroundColor(timeout)
+	if err != nil {
+		return BackgroundUnknown
+	}
+	return backgroundFromOSC11(reply)
+}
+
+// backgroundFromColorFGBG reads COLORFGBG, such as "15;0", whose last field
+// is the background color of the 16 basic colors
+func backgroundFromColorFGBG(value string) Background {
+	fields := strings.Split(value, ";")
+	index, err := strconv.Atoi(fields[len(fields)-1])
+	if value == "" || err != nil || index < 0 || index > 15 {
+		return BackgroundUnknown
+	}
+	// White and the bright colors other than bright black are light
+	if index == 7 || index > 8 {
+		return BackgroundLight
+	}
+	return BackgroundDark
+}
+
+// backgroundFromOSC11 reads the color in the reply to an OSC 11 query
+func backgroundFromOSC11(reply string) Background {
+	m := osc11Pattern.FindStringSubmatch(reply)
+	if m == nil {
+		return BackgroundUnknown
+	}
+
+	var rgb [3]float64
+	for i, hex := range m[1:] {
+		value, err := strconv.ParseUint(hex, 16, 16)
+		if err != nil {
+			return BackgroundUnknown
+		}
+		// Components have 1 to 4 hex digits, scaled to their maximum
+		rgb[i] = float64(value) / float64(uint64(1)<<(4*len(hex))-1)
+	}
+
+	luminance := 0.299*rgb[0] + 0.587*rgb[1] + 0.114*rgb[2]
+	if luminance < 0.5 {
+		return BackgroundDark
+	}
+	return BackgroundLight
+}
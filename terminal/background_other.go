@@ -0,0 +1,14 @@
+//go:build !unix
+
+package terminal
+
+import (
+	"errors"
+	"time"
+)
+
+// queryBackgroundColor is not supported without Unix terminals, where
+// stdin can't be polled for the reply
+func queryBackgroundColor(timeout time.Duration) (string, error) {
+	return "", errors.New("background color query is not supported")
+}
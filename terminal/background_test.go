@@ -0,0 +1,51 @@
+package terminal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectBackground_Env(t *testing.T) {
+	t.Setenv("COLORFGBG", "0;15")
+
+	t.Setenv(EnvBackground, "dark")
+	if got := DetectBackground(time.Millisecond); got != BackgroundDark {
+		t.Errorf("DetectBackground() = %v, want dark", got)
+	}
+
+	t.Setenv(EnvBackground, "")
+	if got := DetectBackground(time.Millisecond); got != BackgroundLight {
+		t.Errorf("DetectBackground() with COLORFGBG = %v, want light", got)
+	}
+}
+
+func TestBackgroundFromColorFGBG(t *testing.T) {
+	tests := map[string]Background{
+		"15;0":        BackgroundDark,
+		"0;15":        BackgroundLight,
+		"0;default;7": BackgroundLight,
+		"7;8":         BackgroundDark,
+		"":            BackgroundUnknown,
+		"15;default":  BackgroundUnknown,
+	}
+	for value, want := range tests {
+		if got := backgroundFromColorFGBG(value); got != want {
+			t.Errorf("backgroundFromColorFGBG(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestBackgroundFromOSC11(t *testing.T) {
+	tests := map[string]Background{
+		"\x1b]11;rgb:1e1e/1e1e/1e1e\x1b\\\x1b[?62;22c": BackgroundDark,
+		"\x1b]11;rgb:ffff/ffff/ffff\a\x1b[?1;2c":       BackgroundLight,
+		"\x1b]11;rgb:fd/f6/e3\x1b\\":                   BackgroundLight,
+		"\x1b]11;rgb:0/2/3\x1b\\":                      BackgroundDark,
+		"\x1b[?62;22c":                                 BackgroundUnknown,
+	}
+	for reply, want := range tests {
+		if got := backgroundFromOSC11(reply); got != want {
+			t.Errorf("backgroundFromOSC11(%q) = %v, want %v", reply, got, want)
+		}
+	}
+}
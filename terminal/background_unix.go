@@ -0,0 +1,70 @@
+//go:build unix
+
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"golang.org/x/sys/unix"
+)
+
+// backgroundQuery asks for the background color, then for the primary
+// device attributes, which every terminal answers
+const backgroundQuery = "\x1b]11;?\x1b\\\x1b[c"
+
+// queryBackgroundColor sends an OSC 11 query and returns the terminal's
+// reply, read from stdin in raw mode. Reading stops at the reply to the
+// device attributes query, or when timeout expires
+func queryBackgroundColor(timeout time.Duration) (string, error) {
+	fd := os.Stdin.Fd()
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to set raw mode: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	if _, err := os.Stdout.WriteString(backgroundQuery); err != nil {
+		return "", fmt.Errorf("failed to write query: %w", err)
+	}
+
+	var reply strings.Builder
+	buf := make([]byte, 256)
+	deadline := time.Now().Add(timeout)
+	for !deviceAttributesReceived(reply.String()) {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return reply.String(), errors.New("timed out waiting for the terminal")
+		}
+
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(remaining.Milliseconds())+1)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return reply.String(), fmt.Errorf("failed to wait for the terminal: %w", err)
+		}
+		if n == 0 {
+			continue
+		}
+
+		n, err = unix.Read(int(fd), buf)
+		if err != nil {
+			return reply.String(), fmt.Errorf("failed to read reply: %w", err)
+		}
+		reply.Write(buf[:n])
+	}
+	return reply.String(), nil
+}
+
+// deviceAttributesReceived reports whether s holds a complete reply to the
+// device attributes query, such as "\x1b[?62;22c"
+func deviceAttributesReceived(s string) bool {
+	i := strings.Index(s, "\x1b[?")
+	return i >= 0 && strings.Contains(s[i:], "c")
+}
@@ -0,0 +1,104 @@
+package terminal
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrClipboardUnavailable is returned by CopyToClipboard when there is
+// neither a terminal to send OSC 52 to nor a clipboard command
+var ErrClipboardUnavailable = errors.New("no clipboard available")
+
+var (
+	// clipboardOutput is where OSC 52 sequences are written
+	clipboardOutput io.Writer = os.Stdout
+
+	// lookPath and runClipboard find and run native clipboard commands
+	lookPath     = exec.LookPath
+	runClipboard = func(name string, args []string, text string) error {
+		cmd := exec.Command(name, args...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+)
+
+// CopyToClipboard puts text on the user's clipboard. When stdout is a
+// terminal, the text is sent with OSC 52, which reaches the local clipboard
+// even over SSH, and through tmux and screen. Since terminals ignore OSC 52
+// silently when they don't support it, a native clipboard command is also
+// run when there is one outside SSH: pbcopy on macOS, clip on Windows and
+// WSL, and wl-copy, xclip or xsel on Linux
+func CopyToClipboard(text string) error {
+	sent := false
+	if New().IsTTY() {
+		if _, err := io.WriteString(clipboardOutput, osc52(text, os.Getenv)); err != nil {
+			return fmt.Errorf("failed to write to terminal: %w", err)
+		}
+		sent = true
+	}
+	if sent && os.Getenv("SSH_TTY") != "" {
+		return nil
+	}
+
+	name, args, ok := clipboardCommand(os.Getenv, runtime.GOOS)
+	if !ok {
+		if sent {
+			return nil
+		}
+		return ErrClipboardUnavailable
+	}
+	if err := runClipboard(name, args, text); err != nil && !sent {
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return nil
+}
+
+// osc52 returns the sequence setting the clipboard to text, wrapped in the
+// passthrough of tmux or screen when running in one
+func osc52(text string, getenv func(string) string) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case getenv("TMUX") != "":
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(getenv("TERM"), "screen"):
+		return "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+// clipboardCommand returns the first installed native clipboard command
+// for the platform
+func clipboardCommand(getenv func(string) string, goos string) (string, []string, bool) {
+	var candidates [][]string
+	switch goos {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		if getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		if getenv("DISPLAY") != "" {
+			candidates = append(candidates,
+				[]string{"xclip", "-selection", "clipboard"},
+				[]string{"xsel", "--clipboard", "--input"})
+		}
+		if getenv("WSL_DISTRO_NAME") != "" {
+			candidates = append(candidates, []string{"clip.exe"})
+		}
+	}
+
+	for _, candidate := range candidates {
+		if path, err := lookPath(candidate[0]); err == nil {
+			return path, candidate[1:], true
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,130 @@
+package terminal
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+type clipboardCall struct {
+	name string
+	args []string
+	text string
+}
+
+func useClipboard(t *testing.T, tty bool, installed ...string) (*bytes.Buffer, *[]clipboardCall) {
+	var buf bytes.Buffer
+	var calls []clipboardCall
+
+	previousOutput, previousLookPath, previousRun := clipboardOutput, lookPath, runClipboard
+	t.Cleanup(func() {
+		clipboardOutput, lookPath, runClipboard = previousOutput, previousLookPath, previousRun
+	})
+
+	clipboardOutput = &buf
+	lookPath = func(name string) (string, error) {
+		for _, command := range installed {
+			if command == name {
+				return name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	runClipboard = func(name string, args []string, text string) error {
+		calls = append(calls, clipboardCall{name, args, text})
+		return nil
+	}
+
+	if tty {
+		t.Setenv("TYKCTL_FORCE_TTY", "1")
+	} else {
+		t.Setenv("TYKCTL_FORCE_TTY", "")
+	}
+	for _, key := range []string{"SSH_TTY", "TMUX", "TERM", "DISPLAY", "WAYLAND_DISPLAY", "WSL_DISTRO_NAME"} {
+		t.Setenv(key, "")
+	}
+	return &buf, &calls
+}
+
+func TestCopyToClipboard_OSC52(t *testing.T) {
+	buf, calls := useClipboard(t, true)
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+
+	if err := CopyToClipboard("secret"); err != nil {
+		t.Fatalf("CopyToClipboard() error = %v", err)
+	}
+	if got, want := buf.String(), "\x1b]52;c;c2VjcmV0\a"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("ran %v over SSH", *calls)
+	}
+}
+
+func TestCopyToClipboard_Native(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("clipboard commands differ on " + runtime.GOOS)
+	}
+	buf, calls := useClipboard(t, false, "xsel")
+	t.Setenv("DISPLAY", ":0")
+
+	if err := CopyToClipboard("secret"); err != nil {
+		t.Fatalf("CopyToClipboard() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %q when not a terminal", buf.String())
+	}
+	if len(*calls) != 1 || (*calls)[0].name != "xsel" || (*calls)[0].text != "secret" {
+		t.Errorf("calls = %v", *calls)
+	}
+}
+
+func TestCopyToClipboard_Unavailable(t *testing.T) {
+	useClipboard(t, false)
+
+	if err := CopyToClipboard("secret"); !errors.Is(err, ErrClipboardUnavailable) {
+		t.Errorf("CopyToClipboard() error = %v, want ErrClipboardUnavailable", err)
+	}
+}
+
+func TestOSC52Passthrough(t *testing.T) {
+	env := map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"}
+	got := osc52("hi", func(key string) string { return env[key] })
+	if want := "\x1bPtmux;\x1b\x1b]52;c;aGk=\a\x1b\\"; got != want {
+		t.Errorf("osc52() in tmux = %q, want %q", got, want)
+	}
+
+	env = map[string]string{"TERM": "screen-256color"}
+	got = osc52("hi", func(key string) string { return env[key] })
+	if want := "\x1bP\x1b]52;c;aGk=\a\x1b\\"; got != want {
+		t.Errorf("osc52() in screen = %q, want %q", got, want)
+	}
+}
+
+func TestClipboardCommand(t *testing.T) {
+	useClipboard(t, false, "pbcopy", "xclip", "wl-copy")
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		goos string
+		want string
+		ok   bool
+	}{
+		{"macOS", nil, "darwin", "pbcopy", true},
+		{"Wayland", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, "linux", "wl-copy", true},
+		{"X11", map[string]string{"DISPLAY": ":0"}, "linux", "xclip", true},
+		{"headless", nil, "linux", "", false},
+		{"Windows without clip", nil, "windows", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, _, ok := clipboardCommand(func(key string) string { return tt.env[key] }, tt.goos)
+			if name != tt.want || ok != tt.ok {
+				t.Errorf("clipboardCommand() = %q, %v, want %q, %v", name, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
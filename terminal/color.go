@@ -0,0 +1,134 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// ColorLevel is how many colors a terminal can show
+type ColorLevel int
+
+// Color levels, from none to 24-bit color
+const (
+	// ColorNone means no escape sequences at all, for pipes, files and
+	// NO_COLOR
+	ColorNone ColorLevel = iota
+	// Color16 is the basic 8 colors and their bright variants
+	Color16
+	// Color256 is the xterm 256 color palette
+	Color256
+	// ColorTrue is 24-bit RGB color
+	ColorTrue
+)
+
+// String names the level
+func (l ColorLevel) String() string {
+	switch l {
+	case Color16:
+		return "16"
+	case Color256:
+		return "256"
+	case ColorTrue:
+		return "truecolor"
+	}
+	return "none"
+}
+
+// DetectColorLevel returns the color level of stdout. The conventions are,
+// in order:
+//   - NO_COLOR set to anything disables color
+//   - FORCE_COLOR forces color even when not a terminal: 0 or false
+//     disables it, 1 or true gives at least 16 colors, 2 at least 256 and
+//     3 truecolor. CLICOLOR_FORCE=1 is like FORCE_COLOR=1
+//   - otherwise output that is not a terminal, CLICOLOR=0 and TERM=dumb
+//     get no color
+//   - COLORTERM=truecolor or 24bit, or a TERM such as xterm-direct, give
+//     truecolor, and a TERM such as xterm-256color 256 colors
+//
+// On Windows, escape sequences are enabled on the console first, and
+// consoles that cannot process them get no color
+func DetectColorLevel() ColorLevel {
+	return ColorLevelOf(os.Stdout)
+}
+
+// ColorLevelOf returns the color level of output written to w, following
+// the conventions of DetectColorLevel. Writers that are not files, such as
+// buffers, are not terminals and only get color when it is forced
+func ColorLevelOf(w io.Writer) ColorLevel {
+	file, ok := w.(*os.File)
+	tty := (ok && IsTerminal(file.Fd())) || getForceTTY()
+	if runtime.GOOS == "windows" && tty && (!ok || !enableVirtualTerminal(file.Fd())) {
+		tty = false
+	}
+	return detectColorLevel(os.Getenv, runtime.GOOS, tty)
+}
+
+// detectColorLevel applies the conventions of DetectColorLevel to an
+// environment
+func detectColorLevel(getenv func(string) string, goos string, tty bool) ColorLevel {
+	if getenv("NO_COLOR") != "" {
+		return ColorNone
+	}
+
+	forced := ColorNone
+	switch strings.ToLower(getenv("FORCE_COLOR")) {
+	case "":
+	case "0", "false":
+		return ColorNone
+	case "2":
+		forced = Color256
+	case "3":
+		forced = ColorTrue
+	default:
+		forced = Color16
+	}
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" && forced == ColorNone {
+		forced = Color16
+	}
+
+	if forced == ColorNone {
+		if !tty || getenv("CLICOLOR") == "0" {
+			return ColorNone
+		}
+	}
+
+	level := environColorLevel(getenv, goos)
+	if level < forced {
+		level = forced
+	}
+	return level
+}
+
+// environColorLevel returns the level TERM and related variables describe
+func environColorLevel(getenv func(string) string, goos string) ColorLevel {
+	term := strings.ToLower(getenv("TERM"))
+	if term == "dumb" {
+		return ColorNone
+	}
+
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ColorTrue
+	}
+	switch {
+	case strings.HasSuffix(term, "-direct"), strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"):
+		return ColorTrue
+	case strings.Contains(term, "256color"):
+		return Color256
+	}
+
+	// Windows Terminal and Windows 10 consoles with escape sequences
+	// enabled show 24-bit color, and don't set TERM
+	if goos == "windows" {
+		return ColorTrue
+	}
+	if getenv("WT_SESSION") != "" {
+		return ColorTrue
+	}
+	if term != "" {
+		return Color16
+	}
+	return ColorNone
+}
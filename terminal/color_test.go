@@ -0,0 +1,67 @@
+package terminal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestDetectColorLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		goos string
+		tty  bool
+		want ColorLevel
+	}{
+		{"truecolor", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, "linux", true, ColorTrue},
+		{"256 colors", map[string]string{"TERM": "xterm-256color"}, "linux", true, Color256},
+		{"direct", map[string]string{"TERM": "xterm-direct"}, "linux", true, ColorTrue},
+		{"16 colors", map[string]string{"TERM": "xterm"}, "linux", true, Color16},
+		{"no TERM", map[string]string{}, "linux", true, ColorNone},
+		{"dumb", map[string]string{"TERM": "dumb", "COLORTERM": "truecolor"}, "linux", true, ColorNone},
+		{"windows console", map[string]string{}, "windows", true, ColorTrue},
+		{"windows terminal over ssh", map[string]string{"WT_SESSION": "1"}, "linux", true, ColorTrue},
+		{"not a terminal", map[string]string{"TERM": "xterm-256color"}, "linux", false, ColorNone},
+		{"NO_COLOR", map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1", "FORCE_COLOR": "3"}, "linux", true, ColorNone},
+		{"CLICOLOR=0", map[string]string{"TERM": "xterm-256color", "CLICOLOR": "0"}, "linux", true, ColorNone},
+		{"FORCE_COLOR=0", map[string]string{"TERM": "xterm-256color", "FORCE_COLOR": "0"}, "linux", true, ColorNone},
+		{"FORCE_COLOR pipe", map[string]string{"FORCE_COLOR": "1"}, "linux", false, Color16},
+		{"FORCE_COLOR=2", map[string]string{"FORCE_COLOR": "2"}, "linux", false, Color256},
+		{"FORCE_COLOR=3", map[string]string{"FORCE_COLOR": "3"}, "linux", false, ColorTrue},
+		{"FORCE_COLOR keeps better", map[string]string{"FORCE_COLOR": "true", "COLORTERM": "24bit"}, "linux", false, ColorTrue},
+		{"CLICOLOR_FORCE", map[string]string{"CLICOLOR_FORCE": "1", "CLICOLOR": "0"}, "linux", false, Color16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectColorLevel(env(tt.vars), tt.goos, tt.tty); got != tt.want {
+				t.Errorf("detectColorLevel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorLevelOf(t *testing.T) {
+	t.Setenv("TYKCTL_FORCE_TTY", "")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	var buf bytes.Buffer
+	t.Setenv("FORCE_COLOR", "")
+	if got := ColorLevelOf(&buf); got != ColorNone {
+		t.Errorf("ColorLevelOf(buffer) = %s, want none", got)
+	}
+	t.Setenv("FORCE_COLOR", "1")
+	if got := ColorLevelOf(&buf); got != Color256 {
+		t.Errorf("ColorLevelOf(buffer) with FORCE_COLOR = %s, want 256", got)
+	}
+	if IsTerminalWriter(&buf) {
+		t.Error("IsTerminalWriter(buffer) = true, want false")
+	}
+}
@@ -0,0 +1,134 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+var (
+	// cursorHidden is whether HideCursor hid the cursor, guarded by
+	// altScreenMu
+	cursorHidden bool
+
+	// guardStop stops the signal guard while one runs
+	guardStop chan struct{}
+
+	// exitProcess ends the process once the guard restored the terminal
+	exitProcess = os.Exit
+)
+
+// HideCursor hides the cursor, such as while drawing a spinner. Until
+// ShowCursor, an interrupt or termination signal shows it again before the
+// process exits, and Restore can be deferred in main to show it on a panic.
+// Nothing is written when output is not a terminal
+func HideCursor() {
+	altScreenMu.Lock()
+	defer altScreenMu.Unlock()
+
+	if cursorHidden || !New().IsTTY() {
+		return
+	}
+	io.WriteString(altScreenOutput, hideCursor)
+	cursorHidden = true
+	updateGuardLocked()
+}
+
+// ShowCursor shows the cursor hidden by HideCursor
+func ShowCursor() {
+	altScreenMu.Lock()
+	defer altScreenMu.Unlock()
+
+	if !cursorHidden {
+		return
+	}
+	io.WriteString(altScreenOutput, showCursor)
+	cursorHidden = false
+	updateGuardLocked()
+}
+
+// ClearLine erases the line the cursor is on and moves the cursor to its
+// start, to redraw a status line in place
+func ClearLine() {
+	ClearLines(0)
+}
+
+// ClearLines erases the current line and the n lines above it, leaving the
+// cursor at the start of the topmost. After writing n lines ending in
+// newlines, it removes them so they can be redrawn
+func ClearLines(n int) {
+	if !New().IsTTY() {
+		return
+	}
+	io.WriteString(altScreenOutput, clearLine+strings.Repeat(cursorUp+clearLine, max(n, 0)))
+}
+
+// Restore leaves the alternate screen and shows the cursor, whatever
+// EnterAltScreen and HideCursor calls are outstanding. Deferred in main, it
+// leaves the terminal usable when a panic ends the program:
+//
+//	func main() {
+//		defer terminal.Restore()
+//		...
+//	}
+func Restore() {
+	altScreenMu.Lock()
+	defer altScreenMu.Unlock()
+	restoreLocked()
+}
+
+func restoreLocked() {
+	switch {
+	case altScreenDepth > 0:
+		io.WriteString(altScreenOutput, exitAltScreen+showCursor)
+	case cursorHidden:
+		io.WriteString(altScreenOutput, showCursor)
+	}
+	altScreenDepth = 0
+	explicitAltDepth = 0
+	cursorHidden = false
+	updateGuardLocked()
+}
+
+// updateGuardLocked starts the signal guard while EnterAltScreen or
+// HideCursor left the terminal changed, and stops it once restored. The
+// guard restores the terminal on an interrupt or termination signal and
+// exits as the signal would have, with status 130 or 143. WithAltScreen
+// handles signals itself, cancelling its context instead
+func updateGuardLocked() {
+	active := explicitAltDepth > 0 || cursorHidden
+	if active == (guardStop != nil) {
+		return
+	}
+	if !active {
+		close(guardStop)
+		guardStop = nil
+		return
+	}
+
+	stop := make(chan struct{})
+	guardStop = stop
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			altScreenMu.Lock()
+			if guardStop == stop {
+				restoreLocked()
+			}
+			altScreenMu.Unlock()
+
+			code := 130
+			if sig == syscall.SIGTERM {
+				code = 143
+			}
+			exitProcess(code)
+		case <-stop:
+		}
+	}()
+}
@@ -0,0 +1,76 @@
+package terminal
+
+import "testing"
+
+func TestHideCursor(t *testing.T) {
+	buf := useAltScreenOutput(t, true)
+	t.Cleanup(Restore)
+
+	HideCursor()
+	HideCursor()
+	ShowCursor()
+	ShowCursor()
+
+	if buf.String() != hideCursor+showCursor {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestHideCursorNotTTY(t *testing.T) {
+	buf := useAltScreenOutput(t, false)
+
+	HideCursor()
+	ClearLines(2)
+	ShowCursor()
+	Restore()
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got %q", buf.String())
+	}
+}
+
+func TestClearLines(t *testing.T) {
+	buf := useAltScreenOutput(t, true)
+
+	ClearLine()
+	if buf.String() != "\r\x1b[2K" {
+		t.Errorf("ClearLine() wrote %q", buf.String())
+	}
+
+	buf.Reset()
+	ClearLines(2)
+	if want := "\r\x1b[2K\x1b[1A\r\x1b[2K\x1b[1A\r\x1b[2K"; buf.String() != want {
+		t.Errorf("ClearLines(2) wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	buf := useAltScreenOutput(t, true)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic to propagate")
+			}
+		}()
+		defer Restore()
+
+		EnterAltScreen()
+		HideCursor()
+		panic("boom")
+	}()
+
+	if InAltScreen() {
+		t.Error("InAltScreen() should be false after Restore")
+	}
+	if buf.String() != enterAltScreen+hideCursor+exitAltScreen+showCursor {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+
+	buf.Reset()
+	Restore()
+	ExitAltScreen()
+	if buf.Len() != 0 {
+		t.Errorf("Restoring twice wrote %q", buf.String())
+	}
+}
@@ -4,7 +4,16 @@
 //   - Terminal Detection: Detect terminal capabilities and features
 //   - Color Support: Cross-platform color support for terminal output
 //   - Styling: Text styling including bold, italic, underline
+//   - Color Levels: Truecolor, 256, 16 or no colors, with styles converting colors down
+//   - Themes: Semantic styles for dark and light backgrounds, detected with OSC 11
+//   - Pager: Page long output through $PAGER or less when interactive
+//   - Markdown: Render Markdown as styled, width-aware terminal output
+//   - Hyperlinks and Clipboard: OSC 8 links and OSC 52 clipboard copying with native fallbacks
 //   - ANSI Escape Codes: Support for ANSI escape sequences
+//   - Terminal Size: Live width and height with resize notifications
+//   - Alternate Screen: Run full-screen UIs on the alternate screen buffer
+//   - Cursor Control: Hide the cursor and clear lines, restored on panics and signals
+//   - Synchronized Output: Serialize writes to shared stdout and stderr
 //   - Cross-platform: Works on Windows, macOS, and Linux
 //
 // Example:
@@ -0,0 +1,54 @@
+package terminal
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Hyperlink returns text linking to url. Terminals that support OSC 8 show
+// text as a clickable link; elsewhere the url follows text in parentheses,
+// or is shown alone when it is the text
+func Hyperlink(text, url string) string {
+	if SupportsHyperlinks() {
+		return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+	}
+	if text == "" || text == url {
+		return url
+	}
+	return text + " (" + url + ")"
+}
+
+// SupportsHyperlinks reports whether stdout is a terminal known to support
+// OSC 8 hyperlinks. FORCE_HYPERLINK=1 or 0 overrides detection
+func SupportsHyperlinks() bool {
+	return supportsHyperlinks(os.Getenv, New().IsTTY())
+}
+
+// supportsHyperlinks applies the detection of SupportsHyperlinks to an
+// environment
+func supportsHyperlinks(getenv func(string) string, tty bool) bool {
+	if force := getenv("FORCE_HYPERLINK"); force != "" {
+		return force != "0"
+	}
+	if !tty || getenv("TERM") == "dumb" {
+		return false
+	}
+
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty":
+		return true
+	}
+	if getenv("WT_SESSION") != "" || getenv("KITTY_WINDOW_ID") != "" ||
+		getenv("KONSOLE_VERSION") != "" || getenv("DOMTERM") != "" {
+		return true
+	}
+	// VTE based terminals, such as GNOME Terminal, support them since 0.50
+	if vte, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+
+	term := getenv("TERM")
+	return strings.HasPrefix(term, "xterm-kitty") || strings.HasPrefix(term, "alacritty") ||
+		strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "wezterm")
+}
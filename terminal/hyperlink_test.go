@@ -0,0 +1,46 @@
+package terminal
+
+import "testing"
+
+func TestHyperlink(t *testing.T) {
+	t.Setenv("FORCE_HYPERLINK", "1")
+	if got, want := Hyperlink("docs", "https://tyk.io"), "\x1b]8;;https://tyk.io\x1b\\docs\x1b]8;;\x1b\\"; got != want {
+		t.Errorf("Hyperlink() = %q, want %q", got, want)
+	}
+
+	t.Setenv("FORCE_HYPERLINK", "0")
+	if got, want := Hyperlink("docs", "https://tyk.io"), "docs (https://tyk.io)"; got != want {
+		t.Errorf("Hyperlink() = %q, want %q", got, want)
+	}
+	if got, want := Hyperlink("https://tyk.io", "https://tyk.io"), "https://tyk.io"; got != want {
+		t.Errorf("Hyperlink() = %q, want %q", got, want)
+	}
+}
+
+func TestSupportsHyperlinks(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		tty  bool
+		want bool
+	}{
+		{"not a terminal", map[string]string{"TERM_PROGRAM": "iTerm.app"}, false, false},
+		{"forced", map[string]string{"FORCE_HYPERLINK": "1"}, false, true},
+		{"forced off", map[string]string{"FORCE_HYPERLINK": "0", "TERM_PROGRAM": "iTerm.app"}, true, false},
+		{"iTerm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, true, true},
+		{"Windows Terminal", map[string]string{"WT_SESSION": "abc"}, true, true},
+		{"new VTE", map[string]string{"VTE_VERSION": "6003"}, true, true},
+		{"old VTE", map[string]string{"VTE_VERSION": "4601"}, true, false},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, true, true},
+		{"plain xterm", map[string]string{"TERM": "xterm-256color"}, true, false},
+		{"dumb", map[string]string{"TERM": "dumb", "WT_SESSION": "abc"}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := supportsHyperlinks(getenv, tt.tty); got != tt.want {
+				t.Errorf("supportsHyperlinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
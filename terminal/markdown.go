@@ -0,0 +1,436 @@
+package terminal
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Block patterns of the Markdown subset RenderMarkdown understands
+var (
+	headingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItemPattern  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	rulePattern      = regexp.MustCompile(`^\s{0,3}((-\s*){3,}|(\*\s*){3,}|(_\s*){3,})$`)
+	separatorPattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	fencePattern     = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// Inline patterns, applied in order so code spans are left alone
+var (
+	codeSpanPattern = regexp.MustCompile("`([^`]+)`")
+	linkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldPattern     = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	italicPattern   = regexp.MustCompile(`\*([^*\s][^*]*)\*|(^|\W)_([^_\s][^_]*)_(\W|$)`)
+)
+
+// listBullets mark unordered list items by depth
+var listBullets = []string{"•", "◦", "▪"}
+
+// RenderMarkdown renders Markdown for stdout, wrapped to its width and
+// styled at its color level, for help text, extension READMEs and release
+// notes
+func RenderMarkdown(md string) string {
+	return RenderMarkdownWidth(md, Width(), DetectColorLevel())
+}
+
+// RenderMarkdownWidth renders Markdown wrapped to width columns and styled
+// at level. Headings, paragraphs, lists, block quotes, fenced code blocks,
+// pipe tables and rules are supported, with bold, italic, code spans and
+// links inline. At ColorNone, heading markers and code span backticks are
+// kept so the structure stays visible
+func RenderMarkdownWidth(md string, width int, level ColorLevel) string {
+	if width < 20 {
+		width = 20
+	}
+	r := &markdownRenderer{level: level}
+	blocks := r.blocks(strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n"), width)
+	if len(blocks) == 0 {
+		return ""
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// markdownRenderer holds the styles of a rendering
+type markdownRenderer struct {
+	level ColorLevel
+}
+
+func (r *markdownRenderer) style() Style {
+	return Style{level: r.level}
+}
+
+func (r *markdownRenderer) plain() bool {
+	return r.level == ColorNone
+}
+
+// blocks renders lines as blocks, each of one or more lines
+func (r *markdownRenderer) blocks(lines []string, width int) []string {
+	var blocks []string
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case fencePattern.MatchString(line):
+			block, next := r.fencedCode(lines, i)
+			blocks = append(blocks, block)
+			i = next
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			blocks = append(blocks, r.heading(len(m[1]), m[2], width))
+			i++
+		case rulePattern.MatchString(line):
+			blocks = append(blocks, r.style().Foreground(BrightBlack).Render(strings.Repeat("─", width)))
+			i++
+		case i+1 < len(lines) && strings.Contains(line, "|") && separatorPattern.MatchString(lines[i+1]):
+			block, next := r.table(lines, i, width)
+			blocks = append(blocks, block)
+			i = next
+		case strings.HasPrefix(strings.TrimSpace(line), ">"):
+			block, next := r.quote(lines, i, width)
+			blocks = append(blocks, block)
+			i = next
+		case listItemPattern.MatchString(line):
+			block, next := r.list(lines, i, width)
+			blocks = append(blocks, block)
+			i = next
+		case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"):
+			block, next := r.indentedCode(lines, i)
+			blocks = append(blocks, block)
+			i = next
+		default:
+			block, next := r.paragraph(lines, i, width)
+			blocks = append(blocks, block)
+			i = next
+		}
+	}
+	return blocks
+}
+
+// heading renders a heading, the top levels standing out most
+func (r *markdownRenderer) heading(level int, text string, width int) string {
+	text = r.inline(text)
+	if r.plain() {
+		text = strings.Repeat("#", level) + " " + text
+	}
+	style := r.style().Bold()
+	switch level {
+	case 1:
+		style = style.Underline().Foreground(Magenta)
+	case 2:
+		style = style.Foreground(Cyan)
+	}
+	return style.Render(ansi.Wrap(text, width, ""))
+}
+
+// paragraph joins lines up to a blank line or another block, rendering a
+// setext heading when underlined with = or -
+func (r *markdownRenderer) paragraph(lines []string, i, width int) (string, int) {
+	var text []string
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || (len(text) > 0 && r.startsBlock(lines, i)) {
+			break
+		}
+		if len(text) > 0 && strings.Trim(line, "=") == "" {
+			return r.heading(1, strings.Join(text, " "), width), i + 1
+		}
+		if len(text) > 0 && strings.Trim(line, "-") == "" {
+			return r.heading(2, strings.Join(text, " "), width), i + 1
+		}
+		text = append(text, line)
+	}
+	return ansi.Wrap(r.inline(strings.Join(text, " ")), width, ""), i
+}
+
+// startsBlock reports whether line i interrupts a paragraph
+func (r *markdownRenderer) startsBlock(lines []string, i int) bool {
+	line := lines[i]
+	return fencePattern.MatchString(line) || headingPattern.MatchString(line) ||
+		strings.HasPrefix(strings.TrimSpace(line), ">") || listItemPattern.MatchString(line)
+}
+
+// fencedCode renders a fenced code block, indented and unwrapped
+func (r *markdownRenderer) fencedCode(lines []string, i int) (string, int) {
+	fence := fencePattern.FindStringSubmatch(lines[i])[1]
+	var code []string
+	for i++; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	return r.code(code), i
+}
+
+// indentedCode renders lines indented by four spaces or a tab as code
+func (r *markdownRenderer) indentedCode(lines []string, i int) (string, int) {
+	var code []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "    "):
+			code = append(code, line[4:])
+		case strings.HasPrefix(line, "\t"):
+			code = append(code, line[1:])
+		default:
+			return r.code(code), i
+		}
+	}
+	return r.code(code), i
+}
+
+// code indents code lines and colors them, leaving them unwrapped so
+// commands can be copied
+func (r *markdownRenderer) code(lines []string) string {
+	style := r.style().Foreground(Yellow)
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = "  " + style.Render(strings.ReplaceAll(line, "\t", "    "))
+	}
+	return strings.Join(out, "\n")
+}
+
+// quote renders a block quote behind a bar
+func (r *markdownRenderer) quote(lines []string, i, width int) (string, int) {
+	var inner []string
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, ">") {
+			break
+		}
+		line = strings.TrimPrefix(line, ">")
+		inner = append(inner, strings.TrimPrefix(line, " "))
+	}
+
+	bar := r.style().Foreground(BrightBlack).Render("│") + " "
+	var out []string
+	for _, block := range r.blocks(inner, width-2) {
+		for _, line := range strings.Split(block, "\n") {
+			out = append(out, bar+line)
+		}
+		out = append(out, strings.TrimSuffix(bar, " "))
+	}
+	if len(out) > 0 {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n"), i
+}
+
+// listItem is an item of a list with its depth and marker
+type listItem struct {
+	depth  int
+	marker string
+	text   []string
+}
+
+// list renders consecutive list items, nested by indentation, with
+// wrapped lines hanging under the text
+func (r *markdownRenderer) list(lines []string, i, width int) (string, int) {
+	var items []*listItem
+	var indents []int
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			// A blank line ends the list unless another item of it follows
+			if i+1 < len(lines) && listItemPattern.MatchString(lines[i+1]) &&
+				ordered(listItemPattern.FindStringSubmatch(lines[i+1])[2]) == ordered(items[0].marker) {
+				continue
+			}
+			break
+		}
+		m := listItemPattern.FindStringSubmatch(line)
+		if m != nil && len(items) > 0 && m[1] == "" && ordered(m[2]) != ordered(items[0].marker) {
+			break
+		}
+		if m == nil {
+			if len(items) == 0 || !strings.HasPrefix(line, " ") {
+				break
+			}
+			last := items[len(items)-1]
+			last.text = append(last.text, strings.TrimSpace(line))
+			continue
+		}
+
+		indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+		for len(indents) > 0 && indent < indents[len(indents)-1] {
+			indents = indents[:len(indents)-1]
+		}
+		if len(indents) == 0 || indent > indents[len(indents)-1] {
+			indents = append(indents, indent)
+		}
+		items = append(items, &listItem{depth: len(indents) - 1, marker: m[2], text: []string{m[3]}})
+	}
+
+	var out []string
+	for _, item := range items {
+		marker := item.marker
+		if !ordered(marker) {
+			marker = r.style().Foreground(Cyan).Render(listBullets[item.depth%len(listBullets)])
+		}
+		indent := strings.Repeat("  ", item.depth)
+		prefix := indent + marker + " "
+		hang := strings.Repeat(" ", ansi.StringWidth(prefix))
+
+		text := ansi.Wrap(r.inline(strings.Join(item.text, " ")), width-len(hang), "")
+		for j, line := range strings.Split(text, "\n") {
+			if j == 0 {
+				out = append(out, prefix+line)
+			} else {
+				out = append(out, hang+line)
+			}
+		}
+	}
+	return strings.Join(out, "\n"), i
+}
+
+// ordered reports whether a list marker numbers its item
+func ordered(marker string) bool {
+	return strings.ContainsAny(marker, "0123456789")
+}
+
+// table renders a pipe table with aligned columns, shrinking the widest
+// columns to fit the width
+func (r *markdownRenderer) table(lines []string, i, width int) (string, int) {
+	header := splitRow(lines[i])
+	aligns := splitRow(lines[i+1])
+	var rows [][]string
+	for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+		rows = append(rows, splitRow(lines[i]))
+	}
+
+	cols := len(header)
+	cells := make([][]string, 0, len(rows)+1)
+	for _, row := range append([][]string{header}, rows...) {
+		rendered := make([]string, cols)
+		for c := 0; c < cols && c < len(row); c++ {
+			rendered[c] = r.inline(row[c])
+		}
+		cells = append(cells, rendered)
+	}
+
+	widths := make([]int, cols)
+	for _, row := range cells {
+		for c, cell := range row {
+			if w := ansi.StringWidth(cell); w > widths[c] {
+				widths[c] = w
+			}
+		}
+	}
+	// Columns are separated by two spaces
+	for total(widths)+2*(cols-1) > width {
+		widest := 0
+		for c := range widths {
+			if widths[c] > widths[widest] {
+				widest = c
+			}
+		}
+		if widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+	}
+
+	var out []string
+	for n, row := range cells {
+		parts := make([]string, cols)
+		for c, cell := range row {
+			if ansi.StringWidth(cell) > widths[c] {
+				cell = ansi.Truncate(cell, widths[c], "…")
+			}
+			align := ""
+			if c < len(aligns) {
+				align = aligns[c]
+			}
+			parts[c] = alignCell(cell, widths[c], align)
+		}
+		line := strings.TrimRight(strings.Join(parts, "  "), " ")
+		if n == 0 {
+			out = append(out, r.style().Bold().Render(line))
+			rules := make([]string, cols)
+			for c := range rules {
+				rules[c] = strings.Repeat("─", widths[c])
+			}
+			out = append(out, r.style().Foreground(BrightBlack).Render(strings.Join(rules, "  ")))
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n"), i
+}
+
+// splitRow splits a table row into trimmed cells
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return cells
+}
+
+// alignCell pads a cell to width as its separator cell, such as ":-:",
+// asks
+func alignCell(cell string, width int, align string) string {
+	gap := width - ansi.StringWidth(cell)
+	if gap <= 0 {
+		return cell
+	}
+	switch {
+	case strings.HasPrefix(align, ":") && strings.HasSuffix(align, ":"):
+		return strings.Repeat(" ", gap/2) + cell + strings.Repeat(" ", gap-gap/2)
+	case strings.HasSuffix(align, ":"):
+		return strings.Repeat(" ", gap) + cell
+	}
+	return cell + strings.Repeat(" ", gap)
+}
+
+func total(values []int) int {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// inline styles code spans, links, bold and italic text. Code spans are
+// set aside first so their content is not styled
+func (r *markdownRenderer) inline(text string) string {
+	var spans []string
+	text = codeSpanPattern.ReplaceAllStringFunc(text, func(m string) string {
+		code := codeSpanPattern.FindStringSubmatch(m)[1]
+		if r.plain() {
+			code = "`" + code + "`"
+		}
+		spans = append(spans, r.style().Foreground(Yellow).Render(code))
+		return "\x00" + string(rune('A'+len(spans)-1)) + "\x00"
+	})
+
+	text = linkPattern.ReplaceAllStringFunc(text, func(m string) string {
+		parts := linkPattern.FindStringSubmatch(m)
+		if parts[1] == parts[2] {
+			return r.style().Underline().Foreground(Blue).Render(parts[2])
+		}
+		return parts[1] + " (" + r.style().Underline().Foreground(Blue).Render(parts[2]) + ")"
+	})
+	text = boldPattern.ReplaceAllStringFunc(text, func(m string) string {
+		parts := boldPattern.FindStringSubmatch(m)
+		return r.style().Bold().Render(parts[1] + parts[2])
+	})
+	text = italicPattern.ReplaceAllStringFunc(text, func(m string) string {
+		parts := italicPattern.FindStringSubmatch(m)
+		if parts[1] != "" {
+			return r.style().Italic().Render(parts[1])
+		}
+		return parts[2] + r.style().Italic().Render(parts[3]) + parts[4]
+	})
+
+	for i, span := range spans {
+		text = strings.Replace(text, "\x00"+string(rune('A'+i))+"\x00", span, 1)
+	}
+	return text
+}
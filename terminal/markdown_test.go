@@ -0,0 +1,90 @@
+package terminal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderMarkdownWidth_Plain(t *testing.T) {
+	md := "# Title\n\nSome *emphasis* and `code`, see [docs](https://tyk.io).\n\n" +
+		"- one\n  - nested\n- two\n\n1. first\n2. second\n\n" +
+		"```\ntykctl get apis\n```\n\n> quoted\n\n---\n"
+	want := "# Title\n\n" +
+		"Some emphasis and `code`, see docs (https://tyk.io).\n\n" +
+		"• one\n  ◦ nested\n• two\n\n" +
+		"1. first\n2. second\n\n" +
+		"  tykctl get apis\n\n" +
+		"│ quoted\n\n" +
+		strings.Repeat("─", 60) + "\n"
+
+	if got := RenderMarkdownWidth(md, 60, ColorNone); got != want {
+		t.Errorf("RenderMarkdownWidth() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdownWidth_Wraps(t *testing.T) {
+	md := "- " + strings.Repeat("word ", 20) + "\n\nA paragraph " + strings.Repeat("longer ", 12)
+	got := RenderMarkdownWidth(md, 30, ColorNone)
+
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if w := ansi.StringWidth(line); w > 30 {
+			t.Errorf("line %q is %d columns wide", line, w)
+		}
+	}
+	lines := strings.Split(got, "\n")
+	if !strings.HasPrefix(lines[1], "  word") {
+		t.Errorf("list item does not hang under its text: %q", lines[1])
+	}
+}
+
+func TestRenderMarkdownWidth_Table(t *testing.T) {
+	md := "| Name | Port |\n|:-----|-----:|\n| gateway | 8080 |\n| dashboard | 3000 |\n"
+	want := "Name       Port\n" +
+		"─────────  ────\n" +
+		"gateway    8080\n" +
+		"dashboard  3000\n"
+
+	if got := RenderMarkdownWidth(md, 40, ColorNone); got != want {
+		t.Errorf("RenderMarkdownWidth() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdownWidth_TableFits(t *testing.T) {
+	md := "| Key | Description |\n|---|---|\n| a | " + strings.Repeat("long ", 20) + "|\n"
+	got := RenderMarkdownWidth(md, 30, ColorNone)
+
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if w := ansi.StringWidth(line); w > 30 {
+			t.Errorf("line %q is %d columns wide", line, w)
+		}
+	}
+}
+
+func TestRenderMarkdownWidth_Setext(t *testing.T) {
+	got := RenderMarkdownWidth("Release notes\n=============\n\nFixes\n-----\n", 40, ColorNone)
+	if want := "# Release notes\n\n## Fixes\n"; got != want {
+		t.Errorf("RenderMarkdownWidth() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMarkdownWidth_Styled(t *testing.T) {
+	got := RenderMarkdownWidth("## Usage\n\nRun **now** with `tykctl`", 40, Color16)
+
+	for _, want := range []string{"\x1b[1;36mUsage\x1b[0m", "\x1b[1mnow\x1b[0m", "\x1b[33mtykctl\x1b[0m"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderMarkdownWidth() = %q, missing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "##") || strings.Contains(got, "`") {
+		t.Errorf("markers were kept in styled output: %q", got)
+	}
+}
+
+func TestRenderMarkdownWidth_CodeIsNotStyledInline(t *testing.T) {
+	got := RenderMarkdownWidth("Use `*args*` here", 40, ColorNone)
+	if want := "Use `*args*` here\n"; got != want {
+		t.Errorf("RenderMarkdownWidth() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// EnvPager is the environment variable choosing the pager, before PAGER.
+// Setting either to "cat" or an empty TYKCTL_PAGER disables paging
+const EnvPager = "TYKCTL_PAGER"
+
+// DefaultPager is the pager used when neither TYKCTL_PAGER nor PAGER is set
+const DefaultPager = "less"
+
+// pagerOutput is where Page and the pager write
+var pagerOutput io.Writer = os.Stdout
+
+// Page writes content to stdout through a pager, so long output such as
+// lists and descriptions doesn't flood the screen. The pager is
+// TYKCTL_PAGER, PAGER or less, with LESS defaulting to FRX so colors pass
+// through, and short output is left on screen. Content is written directly
+// when stdout is not a terminal, when it fits on the screen, or when the
+// pager is disabled or not installed
+func Page(content string) error {
+	pager := pagerCommand()
+	if len(pager) == 0 || !New().IsTTY() || fitsScreen(content, Width(), Height()) {
+		return writeDirect(content)
+	}
+
+	path, err := exec.LookPath(pager[0])
+	if err != nil {
+		return writeDirect(content)
+	}
+
+	cmd := exec.Command(path, pager[1:]...)
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = pagerOutput
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+
+	if err := cmd.Start(); err != nil {
+		return writeDirect(content)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("failed to run pager: %w", err)
+	}
+	return nil
+}
+
+// pagerCommand returns the pager and its arguments, or nothing when paging
+// is disabled
+func pagerCommand() []string {
+	pager, ok := os.LookupEnv(EnvPager)
+	if !ok {
+		pager = os.Getenv("PAGER")
+		if pager == "" {
+			pager = DefaultPager
+		}
+	}
+
+	fields := strings.Fields(pager)
+	if len(fields) == 0 || fields[0] == "cat" {
+		return nil
+	}
+	return fields
+}
+
+// fitsScreen reports whether content, wrapped to width, fits on a screen
+// of height rows, keeping one for the prompt
+func fitsScreen(content string, width, height int) bool {
+	rows := 0
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		rows += 1 + max(ansi.StringWidth(line)-1, 0)/max(width, 1)
+		if rows >= height {
+			return false
+		}
+	}
+	return true
+}
+
+func writeDirect(content string) error {
+	if _, err := io.WriteString(pagerOutput, content); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
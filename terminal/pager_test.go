@@ -0,0 +1,116 @@
+package terminal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func usePagerOutput(t *testing.T, forceTTY bool) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := pagerOutput
+	pagerOutput = &buf
+	t.Cleanup(func() { pagerOutput = previous })
+
+	if forceTTY {
+		t.Setenv("TYKCTL_FORCE_TTY", "1")
+	} else {
+		t.Setenv("TYKCTL_FORCE_TTY", "")
+	}
+	t.Setenv("COLUMNS", "80")
+	t.Setenv("LINES", "5")
+	return &buf
+}
+
+// scriptPager installs a pager that marks what it pages, recording LESS
+func scriptPager(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("pager scripts need a Unix shell")
+	}
+	path := filepath.Join(t.TempDir(), "pager")
+	script := "#!/bin/sh\necho \"paged LESS=$LESS\"\ncat\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvPager, path)
+	t.Setenv("LESS", "")
+	os.Unsetenv("LESS")
+}
+
+func TestPage(t *testing.T) {
+	buf := usePagerOutput(t, true)
+	scriptPager(t)
+
+	content := strings.Repeat("row\n", 10)
+	if err := Page(content); err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	if want := "paged LESS=FRX\n" + content; buf.String() != want {
+		t.Errorf("Page() wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestPage_Direct(t *testing.T) {
+	tests := []struct {
+		name    string
+		tty     bool
+		pager   string
+		content string
+	}{
+		{"not a terminal", false, "", strings.Repeat("row\n", 10)},
+		{"fits the screen", true, "", "one\ntwo\n"},
+		{"disabled", true, "cat", strings.Repeat("row\n", 10)},
+		{"not installed", true, "tykctl-missing-pager", strings.Repeat("row\n", 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := usePagerOutput(t, tt.tty)
+			if tt.pager == "" {
+				scriptPager(t)
+			} else {
+				t.Setenv(EnvPager, tt.pager)
+			}
+
+			if err := Page(tt.content); err != nil {
+				t.Fatalf("Page() error = %v", err)
+			}
+			if buf.String() != tt.content {
+				t.Errorf("Page() wrote %q, want it unpaged", buf.String())
+			}
+		})
+	}
+}
+
+func TestPagerCommand(t *testing.T) {
+	t.Setenv(EnvPager, "")
+	os.Unsetenv(EnvPager)
+	t.Setenv("PAGER", "")
+	if got := pagerCommand(); len(got) != 1 || got[0] != DefaultPager {
+		t.Errorf("pagerCommand() = %v, want less", got)
+	}
+
+	t.Setenv("PAGER", "most -s")
+	if got := pagerCommand(); len(got) != 2 || got[0] != "most" {
+		t.Errorf("pagerCommand() = %v, want PAGER", got)
+	}
+
+	t.Setenv(EnvPager, "")
+	if got := pagerCommand(); got != nil {
+		t.Errorf("pagerCommand() = %v, want paging disabled", got)
+	}
+}
+
+func TestFitsScreen(t *testing.T) {
+	if !fitsScreen("a\nb\nc\n", 80, 4) {
+		t.Error("three lines should fit four rows")
+	}
+	if fitsScreen("a\nb\nc\nd\n", 80, 4) {
+		t.Error("four lines should not fit four rows, leaving one for the prompt")
+	}
+	if fitsScreen(strings.Repeat("x", 250), 80, 4) {
+		t.Error("a line wrapping to four rows should not fit")
+	}
+}
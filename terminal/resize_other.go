@@ -0,0 +1,33 @@
+//go:build !unix
+
+package terminal
+
+import (
+	"context"
+	"time"
+)
+
+// resizePollInterval is how often the size is checked where there is no
+// resize signal, such as on Windows
+var resizePollInterval = 250 * time.Millisecond
+
+// watchResize polls the size until ctx is done, sending it when it changes
+func watchResize(ctx context.Context, sizes chan Size) {
+	defer close(sizes)
+
+	ticker := time.NewTicker(resizePollInterval)
+	defer ticker.Stop()
+
+	last := CurrentSize()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if size := CurrentSize(); size != last {
+				last = size
+				sendSize(sizes, size)
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package terminal
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchResize sends the size on every SIGWINCH until ctx is done
+func watchResize(ctx context.Context, sizes chan Size) {
+	defer close(sizes)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			sendSize(sizes, CurrentSize())
+		}
+	}
+}
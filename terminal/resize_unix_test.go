@@ -0,0 +1,38 @@
+//go:build unix
+
+package terminal
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestResize_SIGWINCH(t *testing.T) {
+	t.Setenv("COLUMNS", "120")
+	t.Setenv("LINES", "40")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sizes := Resize(ctx)
+
+	// The watcher registers for the signal asynchronously, so signal until
+	// it answers
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case size := <-sizes:
+			if size != (Size{Width: 120, Height: 40}) {
+				t.Errorf("received %+v, want 120x40", size)
+			}
+			return
+		case <-ticker.C:
+			syscall.Kill(syscall.Getpid(), syscall.SIGWINCH)
+		case <-timeout:
+			t.Fatal("no size after SIGWINCH")
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package terminal
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// Default size when output is not a terminal and COLUMNS and LINES are
+// unset
+const (
+	DefaultWidth  = 80
+	DefaultHeight = 24
+)
+
+// Size is the size of the terminal in columns and lines
+type Size struct {
+	Width  int
+	Height int
+}
+
+// Width returns the width of the terminal on stdout. COLUMNS overrides it,
+// and DefaultWidth is used when stdout is not a terminal
+func Width() int {
+	return CurrentSize().Width
+}
+
+// Height returns the height of the terminal on stdout. LINES overrides it,
+// and DefaultHeight is used when stdout is not a terminal
+func Height() int {
+	return CurrentSize().Height
+}
+
+// CurrentSize returns the size of the terminal on stdout, as Width and
+// Height do
+func CurrentSize() Size {
+	size := Size{Width: DefaultWidth, Height: DefaultHeight}
+	if w, h, err := term.GetSize(os.Stdout.Fd()); err == nil && w > 0 && h > 0 {
+		size = Size{Width: w, Height: h}
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		size.Width = w
+	}
+	if h, err := strconv.Atoi(os.Getenv("LINES")); err == nil && h > 0 {
+		size.Height = h
+	}
+	return size
+}
+
+// Resize returns a channel receiving the new size each time the terminal
+// is resized, until ctx is done, when it is closed. Only the latest size
+// is kept for slow receivers. Resizes are signalled by SIGWINCH on Unix and
+// polled for elsewhere
+func Resize(ctx context.Context) <-chan Size {
+	sizes := make(chan Size, 1)
+	go watchResize(ctx, sizes)
+	return sizes
+}
+
+// sendSize delivers size, replacing a size not received yet
+func sendSize(sizes chan Size, size Size) {
+	for {
+		select {
+		case sizes <- size:
+			return
+		default:
+		}
+		select {
+		case <-sizes:
+		default:
+		}
+	}
+}
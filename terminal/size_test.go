@@ -0,0 +1,57 @@
+package terminal
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCurrentSize_Env(t *testing.T) {
+	t.Setenv("COLUMNS", "132")
+	t.Setenv("LINES", "50")
+
+	if got := CurrentSize(); got != (Size{Width: 132, Height: 50}) {
+		t.Errorf("CurrentSize() = %+v, want 132x50", got)
+	}
+	if Width() != 132 || Height() != 50 {
+		t.Errorf("Width(), Height() = %d, %d", Width(), Height())
+	}
+}
+
+func TestCurrentSize_NotTerminal(t *testing.T) {
+	if IsTerminal(os.Stdout.Fd()) {
+		t.Skip("stdout is a terminal")
+	}
+	t.Setenv("COLUMNS", "")
+	t.Setenv("LINES", "not a number")
+
+	if got := CurrentSize(); got != (Size{Width: DefaultWidth, Height: DefaultHeight}) {
+		t.Errorf("CurrentSize() = %+v, want the defaults", got)
+	}
+}
+
+func TestSendSize_KeepsLatest(t *testing.T) {
+	sizes := make(chan Size, 1)
+	sendSize(sizes, Size{Width: 80, Height: 24})
+	sendSize(sizes, Size{Width: 100, Height: 30})
+
+	if got := <-sizes; got != (Size{Width: 100, Height: 30}) {
+		t.Errorf("received %+v, want the latest size", got)
+	}
+}
+
+func TestResize_ClosedOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sizes := Resize(ctx)
+	cancel()
+
+	select {
+	case _, ok := <-sizes:
+		if ok {
+			t.Error("received a size without a resize")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
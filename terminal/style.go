@@ -0,0 +1,288 @@
+package terminal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color is a color a Style can use, converted down to what the terminal
+// shows
+type Color struct {
+	kind    colorKind
+	index   uint8
+	r, g, b uint8
+}
+
+type colorKind uint8
+
+const (
+	noColor colorKind = iota
+	basicColor
+	paletteColor
+	rgbColor
+)
+
+// The 16 basic colors
+var (
+	Black         = Color{kind: basicColor, index: 0}
+	Red           = Color{kind: basicColor, index: 1}
+	Green         = Color{kind: basicColor, index: 2}
+	Yellow        = Color{kind: basicColor, index: 3}
+	Blue          = Color{kind: basicColor, index: 4}
+	Magenta       = Color{kind: basicColor, index: 5}
+	Cyan          = Color{kind: basicColor, index: 6}
+	White         = Color{kind: basicColor, index: 7}
+	BrightBlack   = Color{kind: basicColor, index: 8}
+	BrightRed     = Color{kind: basicColor, index: 9}
+	BrightGreen   = Color{kind: basicColor, index: 10}
+	BrightYellow  = Color{kind: basicColor, index: 11}
+	BrightBlue    = Color{kind: basicColor, index: 12}
+	BrightMagenta = Color{kind: basicColor, index: 13}
+	BrightCyan    = Color{kind: basicColor, index: 14}
+	BrightWhite   = Color{kind: basicColor, index: 15}
+)
+
+// basicRGB approximates the 16 basic colors, as xterm shows them, for
+// picking the nearest one
+var basicRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the intensities of the 6x6x6 cube of the 256 color palette
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// Palette returns color n of the 256 color palette
+func Palette(n uint8) Color {
+	return Color{kind: paletteColor, index: n}
+}
+
+// RGB returns a 24-bit color
+func RGB(r, g, b uint8) Color {
+	return Color{kind: rgbColor, r: r, g: g, b: b}
+}
+
+// Hex parses a color such as "#ff8800" or "f80"
+func Hex(s string) (Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	return RGB(uint8(value>>16), uint8(value>>8), uint8(value)), nil
+}
+
+// colorNames are the names ParseColor accepts for the basic colors
+var colorNames = map[string]Color{
+	"black": Black, "red": Red, "green": Green, "yellow": Yellow,
+	"blue": Blue, "magenta": Magenta, "cyan": Cyan, "white": White,
+	"gray": BrightBlack, "grey": BrightBlack, "bright-black": BrightBlack,
+	"bright-red": BrightRed, "bright-green": BrightGreen, "bright-yellow": BrightYellow,
+	"bright-blue": BrightBlue, "bright-magenta": BrightMagenta, "bright-cyan": BrightCyan,
+	"bright-white": BrightWhite,
+}
+
+// ParseColor parses a color as written in configuration: a basic color name
+// such as "green" or "bright-red", a palette number from 0 to 255, or a hex
+// color such as "#ff8800"
+func ParseColor(s string) (Color, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if c, ok := colorNames[name]; ok {
+		return c, nil
+	}
+	if n, err := strconv.ParseUint(name, 10, 8); err == nil {
+		return Palette(uint8(n)), nil
+	}
+	if strings.HasPrefix(name, "#") {
+		return Hex(name)
+	}
+	return Color{}, fmt.Errorf("invalid color %q", s)
+}
+
+// sequence returns the SGR parameters of the color at a level, base being
+// 30 for foregrounds and 40 for backgrounds
+func (c Color) sequence(level ColorLevel, base int) string {
+	if c.kind == noColor || level == ColorNone {
+		return ""
+	}
+
+	kind, index := c.kind, c.index
+	if kind == rgbColor && level < ColorTrue {
+		kind, index = paletteColor, rgbToPalette(c.r, c.g, c.b)
+	}
+	if kind == paletteColor && level < Color256 {
+		kind, index = basicColor, nearestBasic(paletteToRGB(index))
+	}
+	if kind == paletteColor && index < 16 {
+		kind = basicColor
+	}
+
+	switch kind {
+	case basicColor:
+		if index >= 8 {
+			return strconv.Itoa(base + 60 + int(index-8))
+		}
+		return strconv.Itoa(base + int(index))
+	case paletteColor:
+		return fmt.Sprintf("%d;5;%d", base+8, index)
+	}
+	return fmt.Sprintf("%d;2;%d;%d;%d", base+8, c.r, c.g, c.b)
+}
+
+// rgbToPalette picks the nearest color of the palette's cube or gray ramp
+func rgbToPalette(r, g, b uint8) uint8 {
+	cube := func(v uint8) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if distance1(v, level) < distance1(v, cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	ri, gi, bi := cube(r), cube(g), cube(b)
+	cubeIndex := uint8(16 + 36*ri + 6*gi + bi)
+
+	// The gray ramp runs from 8 to 238 in steps of 10
+	average := (int(r) + int(g) + int(b)) / 3
+	grayStep := (average - 3) / 10
+	if grayStep < 0 {
+		grayStep = 0
+	}
+	if grayStep > 23 {
+		grayStep = 23
+	}
+	grayIndex := uint8(232 + grayStep)
+
+	if distance(paletteToRGB(grayIndex), [3]uint8{r, g, b}) < distance(paletteToRGB(cubeIndex), [3]uint8{r, g, b}) {
+		return grayIndex
+	}
+	return cubeIndex
+}
+
+// paletteToRGB returns the color of a palette entry
+func paletteToRGB(n uint8) [3]uint8 {
+	switch {
+	case n < 16:
+		return basicRGB[n]
+	case n < 232:
+		n -= 16
+		return [3]uint8{cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]}
+	}
+	gray := 8 + 10*(n-232)
+	return [3]uint8{gray, gray, gray}
+}
+
+// nearestBasic picks the basic color closest to rgb
+func nearestBasic(rgb [3]uint8) uint8 {
+	best := 0
+	for i := range basicRGB {
+		if distance(rgb, basicRGB[i]) < distance(rgb, basicRGB[best]) {
+			best = i
+		}
+	}
+	return uint8(best)
+}
+
+// distance is the squared distance between colors
+func distance(a, b [3]uint8) int {
+	d := 0
+	for i := range a {
+		d += distance1(a[i], b[i]) * distance1(a[i], b[i])
+	}
+	return d
+}
+
+func distance1(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+// Style draws text with colors and attributes, converted down to the color
+// level of the terminal. Styles are values: each method returns a copy
+type Style struct {
+	level      ColorLevel
+	foreground Color
+	background Color
+	attributes []string
+}
+
+// NewStyle returns a style for stdout, at the level DetectColorLevel finds
+func NewStyle() Style {
+	return Style{level: DetectColorLevel()}
+}
+
+// Level sets the color level to draw at, such as for output that is not
+// stdout
+func (s Style) Level(level ColorLevel) Style {
+	s.level = level
+	return s
+}
+
+// Foreground sets the text color
+func (s Style) Foreground(c Color) Style {
+	s.foreground = c
+	return s
+}
+
+// Background sets the background color
+func (s Style) Background(c Color) Style {
+	s.background = c
+	return s
+}
+
+// Bold draws text in bold
+func (s Style) Bold() Style {
+	return s.attribute("1")
+}
+
+// Dim draws text faint
+func (s Style) Dim() Style {
+	return s.attribute("2")
+}
+
+// Italic draws text in italics
+func (s Style) Italic() Style {
+	return s.attribute("3")
+}
+
+// Underline underlines text
+func (s Style) Underline() Style {
+	return s.attribute("4")
+}
+
+func (s Style) attribute(code string) Style {
+	s.attributes = append(append([]string(nil), s.attributes...), code)
+	return s
+}
+
+// Render draws text in the style. At ColorNone, text is returned as is,
+// without attributes either, so pipes and files never get escape sequences
+func (s Style) Render(text string) string {
+	if s.level == ColorNone {
+		return text
+	}
+
+	codes := append([]string(nil), s.attributes...)
+	if seq := s.foreground.sequence(s.level, 30); seq != "" {
+		codes = append(codes, seq)
+	}
+	if seq := s.background.sequence(s.level, 40); seq != "" {
+		codes = append(codes, seq)
+	}
+	if len(codes) == 0 || text == "" {
+		return text
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + text + ColorReset
+}
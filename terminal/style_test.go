@@ -0,0 +1,77 @@
+package terminal
+
+import "testing"
+
+func TestStyle_Render(t *testing.T) {
+	orange := RGB(255, 135, 0)
+	tests := []struct {
+		name  string
+		style Style
+		want  string
+	}{
+		{"truecolor", Style{}.Level(ColorTrue).Foreground(orange), "\x1b[38;2;255;135;0mok\x1b[0m"},
+		{"down to 256", Style{}.Level(Color256).Foreground(orange), "\x1b[38;5;208mok\x1b[0m"},
+		{"down to 16", Style{}.Level(Color16).Foreground(orange), "\x1b[33mok\x1b[0m"},
+		{"none", Style{}.Level(ColorNone).Foreground(orange).Bold(), "ok"},
+		{"gray to 256", Style{}.Level(Color256).Foreground(RGB(128, 128, 128)), "\x1b[38;5;244mok\x1b[0m"},
+		{"palette low index", Style{}.Level(Color256).Foreground(Palette(1)), "\x1b[31mok\x1b[0m"},
+		{"palette down to 16", Style{}.Level(Color16).Foreground(Palette(21)), "\x1b[34mok\x1b[0m"},
+		{"basic", Style{}.Level(Color16).Foreground(Green).Background(BrightBlack), "\x1b[32;100mok\x1b[0m"},
+		{"attributes", Style{}.Level(Color16).Bold().Underline().Foreground(Red), "\x1b[1;4;31mok\x1b[0m"},
+		{"plain", Style{}.Level(ColorTrue), "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.Render("ok"); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyle_IsValue(t *testing.T) {
+	base := Style{}.Level(Color16).Bold()
+	_ = base.Underline()
+
+	if got := base.Render("ok"); got != "\x1b[1mok\x1b[0m" {
+		t.Errorf("deriving a style changed the original: %q", got)
+	}
+}
+
+func TestHex(t *testing.T) {
+	for _, s := range []string{"#ff8700", "ff8700", "#F80"} {
+		c, err := Hex(s)
+		if err != nil {
+			t.Fatalf("Hex(%q) error = %v", s, err)
+		}
+		if c.r != 255 || c.b != 0 {
+			t.Errorf("Hex(%q) = %+v", s, c)
+		}
+	}
+	for _, s := range []string{"", "#12345", "#gggggg"} {
+		if _, err := Hex(s); err == nil {
+			t.Errorf("Hex(%q) succeeded", s)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := map[string]Color{
+		"green":      Green,
+		"Bright-Red": BrightRed,
+		"grey":       BrightBlack,
+		"208":        Palette(208),
+		"#ff8700":    RGB(255, 135, 0),
+	}
+	for s, want := range tests {
+		got, err := ParseColor(s)
+		if err != nil || got != want {
+			t.Errorf("ParseColor(%q) = %+v, %v, want %+v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "orange", "256", "ff8700"} {
+		if _, err := ParseColor(s); err == nil {
+			t.Errorf("ParseColor(%q) succeeded", s)
+		}
+	}
+}
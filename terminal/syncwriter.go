@@ -0,0 +1,47 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// SyncWriter serializes writes to an underlying writer, so output from
+// concurrent goroutines or subprocesses never interleaves within a Write
+type SyncWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+// NewSyncWriter returns a SyncWriter with its own lock
+func NewSyncWriter(w io.Writer) *SyncWriter {
+	return &SyncWriter{mu: &sync.Mutex{}, w: w}
+}
+
+// Write writes p to the underlying writer while holding the lock
+func (s *SyncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+var (
+	// outputMu is shared by the standard streams, since both usually end up
+	// on the same terminal
+	outputMu sync.Mutex
+
+	stdout = &SyncWriter{mu: &outputMu, w: os.Stdout}
+	stderr = &SyncWriter{mu: &outputMu, w: os.Stderr}
+)
+
+// Stdout returns the shared synchronized writer for standard output. Writes
+// through Stdout and Stderr are serialized with each other
+func Stdout() *SyncWriter {
+	return stdout
+}
+
+// Stderr returns the shared synchronized writer for standard error. Writes
+// through Stdout and Stderr are serialized with each other
+func Stderr() *SyncWriter {
+	return stderr
+}
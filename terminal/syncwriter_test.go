@@ -0,0 +1,69 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// overlapWriter records whether two writes were ever in progress at once
+type overlapWriter struct {
+	active  int32
+	overlap int32
+	buf     bytes.Buffer
+}
+
+func (w *overlapWriter) Write(p []byte) (int, error) {
+	if atomic.AddInt32(&w.active, 1) > 1 {
+		atomic.StoreInt32(&w.overlap, 1)
+	}
+	defer atomic.AddInt32(&w.active, -1)
+
+	for _, b := range p {
+		w.buf.WriteByte(b)
+	}
+	return len(p), nil
+}
+
+func TestSyncWriter(t *testing.T) {
+	target := &overlapWriter{}
+	writer := NewSyncWriter(target)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			line := strings.Repeat(string(rune('a'+i)), 64) + "\n"
+			for j := 0; j < 100; j++ {
+				writer.Write([]byte(line))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if target.overlap != 0 {
+		t.Error("Expected writes to be serialized")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(target.buf.String(), "\n"), "\n")
+	if len(lines) != 800 {
+		t.Fatalf("Expected 800 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if line != strings.Repeat(line[:1], 64) {
+			t.Fatalf("Expected whole lines, got %q", line)
+		}
+	}
+}
+
+func TestSharedWriters(t *testing.T) {
+	if Stdout() != Stdout() || Stderr() != Stderr() {
+		t.Error("Expected the shared writers to be singletons")
+	}
+	if Stdout().mu != Stderr().mu {
+		t.Error("Expected stdout and stderr to share a lock")
+	}
+}
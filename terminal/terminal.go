@@ -2,8 +2,8 @@ package terminal
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/x/term"
@@ -40,22 +40,12 @@ func isTTY() bool {
 
 // GetWidth returns the terminal width
 func getWidth() int {
-	if width := os.Getenv("COLUMNS"); width != "" {
-		if w, err := strconv.Atoi(width); err == nil {
-			return w
-		}
-	}
-	return 80 // default width
+	return Width()
 }
 
 // GetHeight returns the terminal height
 func getHeight() int {
-	if height := os.Getenv("LINES"); height != "" {
-		if h, err := strconv.Atoi(height); err == nil {
-			return h
-		}
-	}
-	return 24 // default height
+	return Height()
 }
 
 // GetColor returns whether color output is enabled
@@ -233,3 +223,9 @@ func (t *Terminal) Gray(text string) string {
 func IsTerminal(fd uintptr) bool {
 	return term.IsTerminal(fd)
 }
+
+// IsTerminalWriter reports whether w is a file open on a terminal
+func IsTerminalWriter(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	return ok && IsTerminal(file.Fd())
+}
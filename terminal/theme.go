@@ -0,0 +1,145 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Theme holds the semantic styles commands draw messages with, so output
+// stays readable on both dark and light backgrounds
+type Theme struct {
+	Success Style
+	Warning Style
+	Error   Style
+	Muted   Style
+}
+
+// ThemeConfig configures a theme. Background is "auto", the default,
+// "dark" or "light"; colors are names, palette numbers or hex colors as
+// ParseColor accepts, and replace those of the background's theme
+type ThemeConfig struct {
+	Background string `yaml:"background,omitempty" json:"background,omitempty"`
+	Success    string `yaml:"success,omitempty" json:"success,omitempty"`
+	Warning    string `yaml:"warning,omitempty" json:"warning,omitempty"`
+	Error      string `yaml:"error,omitempty" json:"error,omitempty"`
+	Muted      string `yaml:"muted,omitempty" json:"muted,omitempty"`
+}
+
+var (
+	currentTheme     Theme
+	currentThemeOnce sync.Once
+	currentThemeMu   sync.Mutex
+)
+
+// DefaultTheme returns the theme for a background at the color level of
+// stdout. Dark backgrounds get light colors and light backgrounds dark
+// ones; when the background is unknown, the basic colors are used, which
+// terminals adjust to their background themselves
+func DefaultTheme(bg Background) Theme {
+	return defaultTheme(bg, DetectColorLevel())
+}
+
+func defaultTheme(bg Background, level ColorLevel) Theme {
+	style := Style{level: level}
+	switch bg {
+	case BackgroundDark:
+		return Theme{
+			Success: style.Foreground(RGB(95, 215, 135)),
+			Warning: style.Foreground(RGB(255, 175, 0)),
+			Error:   style.Foreground(RGB(255, 95, 95)).Bold(),
+			Muted:   style.Foreground(RGB(138, 138, 138)),
+		}
+	case BackgroundLight:
+		return Theme{
+			Success: style.Foreground(RGB(0, 135, 0)),
+			Warning: style.Foreground(RGB(175, 95, 0)),
+			Error:   style.Foreground(RGB(215, 0, 0)).Bold(),
+			Muted:   style.Foreground(RGB(108, 108, 108)),
+		}
+	}
+	return Theme{
+		Success: style.Foreground(Green),
+		Warning: style.Foreground(Yellow),
+		Error:   style.Foreground(Red).Bold(),
+		Muted:   style.Foreground(BrightBlack),
+	}
+}
+
+// NewTheme returns the theme cfg describes, detecting the background when
+// it is "auto" or empty
+func NewTheme(cfg ThemeConfig) (Theme, error) {
+	var bg Background
+	switch strings.ToLower(cfg.Background) {
+	case "", "auto":
+		bg = DetectBackground(DefaultBackgroundTimeout)
+	case "dark":
+		bg = BackgroundDark
+	case "light":
+		bg = BackgroundLight
+	default:
+		return Theme{}, fmt.Errorf("invalid background %q: must be auto, dark or light", cfg.Background)
+	}
+	return themeFromConfig(cfg, defaultTheme(bg, DetectColorLevel()))
+}
+
+// themeFromConfig replaces the colors of theme that cfg sets
+func themeFromConfig(cfg ThemeConfig, theme Theme) (Theme, error) {
+	overrides := []struct {
+		name  string
+		value string
+		style *Style
+	}{
+		{"success", cfg.Success, &theme.Success},
+		{"warning", cfg.Warning, &theme.Warning},
+		{"error", cfg.Error, &theme.Error},
+		{"muted", cfg.Muted, &theme.Muted},
+	}
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		c, err := ParseColor(o.value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("invalid %s color: %w", o.name, err)
+		}
+		*o.style = o.style.Foreground(c)
+	}
+	return theme, nil
+}
+
+// Level returns the theme drawing at level, such as for output that is not
+// stdout
+func (t Theme) Level(level ColorLevel) Theme {
+	return Theme{
+		Success: t.Success.Level(level),
+		Warning: t.Warning.Level(level),
+		Error:   t.Error.Level(level),
+		Muted:   t.Muted.Level(level),
+	}
+}
+
+// CurrentTheme returns the theme set with SetTheme, or else the default
+// theme for the detected background, detected on first use
+func CurrentTheme() Theme {
+	currentThemeOnce.Do(func() {
+		theme := DefaultTheme(DetectBackground(DefaultBackgroundTimeout))
+		currentThemeMu.Lock()
+		currentTheme = theme
+		currentThemeMu.Unlock()
+	})
+
+	currentThemeMu.Lock()
+	defer currentThemeMu.Unlock()
+	return currentTheme
+}
+
+// SetTheme sets the theme CurrentTheme returns, such as one built from the
+// user's configuration with NewTheme
+func SetTheme(theme Theme) {
+	currentThemeOnce.Do(func() {})
+
+	currentThemeMu.Lock()
+	defer currentThemeMu.Unlock()
+	currentTheme = theme
+}
@@ -0,0 +1,70 @@
+package terminal
+
+import "testing"
+
+func TestDefaultTheme(t *testing.T) {
+	tests := []struct {
+		bg   Background
+		want string
+	}{
+		{BackgroundDark, "\x1b[38;5;78mok\x1b[0m"},
+		{BackgroundLight, "\x1b[38;5;28mok\x1b[0m"},
+		{BackgroundUnknown, "\x1b[32mok\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bg.String(), func(t *testing.T) {
+			if got := defaultTheme(tt.bg, Color256).Success.Render("ok"); got != tt.want {
+				t.Errorf("Success.Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := defaultTheme(BackgroundDark, ColorNone).Error.Render("ok"); got != "ok" {
+		t.Errorf("Error.Render() without color = %q", got)
+	}
+}
+
+func TestThemeFromConfig(t *testing.T) {
+	cfg := ThemeConfig{Success: "bright-green", Muted: "#808080"}
+	theme, err := themeFromConfig(cfg, defaultTheme(BackgroundDark, Color16))
+	if err != nil {
+		t.Fatalf("themeFromConfig() error = %v", err)
+	}
+	if got := theme.Success.Render("ok"); got != "\x1b[92mok\x1b[0m" {
+		t.Errorf("Success.Render() = %q", got)
+	}
+	if got := theme.Error.Render("ok"); got != "\x1b[1;91mok\x1b[0m" {
+		t.Errorf("Error.Render() = %q, want the default kept", got)
+	}
+
+	if _, err := themeFromConfig(ThemeConfig{Warning: "orange"}, Theme{}); err == nil {
+		t.Error("themeFromConfig() accepted an invalid color")
+	}
+}
+
+func TestNewTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "1")
+
+	theme, err := NewTheme(ThemeConfig{Background: "light"})
+	if err != nil {
+		t.Fatalf("NewTheme() error = %v", err)
+	}
+	if got := theme.Level(Color256).Muted.Render("ok"); got != "\x1b[38;5;242mok\x1b[0m" {
+		t.Errorf("Muted.Render() = %q", got)
+	}
+
+	if _, err := NewTheme(ThemeConfig{Background: "blue"}); err == nil {
+		t.Error("NewTheme() accepted an invalid background")
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	previous := CurrentTheme()
+	t.Cleanup(func() { SetTheme(previous) })
+
+	SetTheme(defaultTheme(BackgroundUnknown, ColorNone))
+	if got := CurrentTheme().Warning.Render("ok"); got != "ok" {
+		t.Errorf("CurrentTheme() did not return the set theme: %q", got)
+	}
+}
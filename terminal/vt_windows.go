@@ -0,0 +1,35 @@
+//go:build windows
+
+package terminal
+
+import (
+	"sync"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	vtMu      sync.Mutex
+	vtEnabled = map[uintptr]bool{}
+)
+
+// enableVirtualTerminal turns on escape sequence processing for the
+// console behind fd, once per console, reporting whether it is on
+func enableVirtualTerminal(fd uintptr) bool {
+	vtMu.Lock()
+	defer vtMu.Unlock()
+
+	if enabled, ok := vtEnabled[fd]; ok {
+		return enabled
+	}
+
+	handle := windows.Handle(fd)
+	var mode uint32
+	enabled := false
+	if err := windows.GetConsoleMode(handle, &mode); err == nil {
+		enabled = mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 ||
+			windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+	}
+	vtEnabled[fd] = enabled
+	return enabled
+}
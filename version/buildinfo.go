@@ -0,0 +1,105 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// readBuildInfo reads the build information embedded by the Go toolchain,
+// and is replaced in tests
+var readBuildInfo = debug.ReadBuildInfo
+
+// BuildInfo describes how the running binary was built, for --version
+// output
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	Dirty     bool   `json:"dirty"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+	// Module is the main module path, when the binary has one
+	Module string `json:"module,omitempty"`
+}
+
+// FromBuildInfo collects the build information of the running binary.
+// Values set with -ldflags come first; the rest are read from the version
+// control and module information the Go toolchain embeds, so binaries
+// built with go install also report their version and commit
+func FromBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   Version,
+		GoVersion: GoVersion,
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Dirty:     GitTreeState == "dirty",
+	}
+	if GitCommit != "unknown" {
+		info.Commit = GitCommit
+	}
+	if BuildDate != "unknown" {
+		info.Date = BuildDate
+	}
+
+	build, ok := readBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Module = build.Main.Path
+	if build.GoVersion != "" {
+		info.GoVersion = build.GoVersion
+	}
+	if Version == defaultVersion && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = trimV(build.Main.Version)
+	}
+
+	// The toolchain only records version control settings for builds in a
+	// work tree, which -ldflags builds usually are too, so they only fill
+	// gaps
+	ldflagsCommit := info.Commit != ""
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if !ldflagsCommit {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = setting.Value
+			}
+		case "vcs.modified":
+			if GitTreeState == "" && !ldflagsCommit {
+				info.Dirty = setting.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+// String renders the build information for people, as Full does
+func (b BuildInfo) String() string {
+	lines := []string{"version " + b.Version}
+	if b.Commit != "" {
+		commit := b.Commit
+		if b.Dirty {
+			commit += " (dirty)"
+		}
+		lines = append(lines, "Git commit: "+commit)
+	}
+	if b.Date != "" {
+		lines = append(lines, "Build date: "+b.Date)
+	}
+	lines = append(lines, "Go version: "+b.GoVersion, "Platform: "+b.Platform)
+	return strings.Join(lines, "\n")
+}
+
+// JSON renders the build information for scripts
+func (b BuildInfo) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal build info: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,91 @@
+package version
+
+import (
+	"encoding/json"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+// withBuild sets the ldflags variables and embedded build information for
+// a test
+func withBuild(t *testing.T, version, commit, date, treeState string, build *debug.BuildInfo) {
+	t.Helper()
+	saved := [4]string{Version, GitCommit, BuildDate, GitTreeState}
+	savedRead := readBuildInfo
+	t.Cleanup(func() {
+		Version, GitCommit, BuildDate, GitTreeState = saved[0], saved[1], saved[2], saved[3]
+		readBuildInfo = savedRead
+	})
+
+	Version, GitCommit, BuildDate, GitTreeState = version, commit, date, treeState
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return build, build != nil
+	}
+}
+
+func goInstalled() *debug.BuildInfo {
+	return &debug.BuildInfo{
+		GoVersion: "go1.25.1",
+		Main:      debug.Module{Path: "github.com/edsonmichaque/tykctl", Version: "v1.4.0"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.time", Value: "2026-10-01T12:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+}
+
+func TestFromBuildInfo_GoInstall(t *testing.T) {
+	withBuild(t, defaultVersion, "unknown", "unknown", "", goInstalled())
+
+	info := FromBuildInfo()
+	if info.Version != "1.4.0" || info.Commit != "abc123" || info.Date != "2026-10-01T12:00:00Z" || !info.Dirty {
+		t.Errorf("FromBuildInfo() = %+v", info)
+	}
+	if info.GoVersion != "go1.25.1" || info.Module != "github.com/edsonmichaque/tykctl" {
+		t.Errorf("FromBuildInfo() = %+v", info)
+	}
+}
+
+func TestFromBuildInfo_Ldflags(t *testing.T) {
+	withBuild(t, "2.0.0", "def456", "2026-10-02T08:00:00Z", "clean", goInstalled())
+
+	info := FromBuildInfo()
+	if info.Version != "2.0.0" || info.Commit != "def456" || info.Date != "2026-10-02T08:00:00Z" || info.Dirty {
+		t.Errorf("FromBuildInfo() = %+v", info)
+	}
+}
+
+func TestFromBuildInfo_NoBuildInfo(t *testing.T) {
+	withBuild(t, defaultVersion, "unknown", "unknown", "", nil)
+
+	info := FromBuildInfo()
+	if info.Version != defaultVersion || info.Commit != "" || info.Date != "" || info.Platform == "" {
+		t.Errorf("FromBuildInfo() = %+v", info)
+	}
+}
+
+func TestBuildInfo_Render(t *testing.T) {
+	info := BuildInfo{Version: "1.4.0", Commit: "abc123", Dirty: true, GoVersion: "go1.25.1", Platform: "linux/amd64"}
+
+	want := "version 1.4.0\nGit commit: abc123 (dirty)\nGo version: go1.25.1\nPlatform: linux/amd64"
+	if got := info.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	data, err := info.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["commit"] != "abc123" || decoded["dirty"] != true || decoded["goVersion"] != "go1.25.1" {
+		t.Errorf("JSON() = %s", data)
+	}
+	if strings.Contains(string(data), `"date"`) {
+		t.Errorf("JSON() includes an empty date: %s", data)
+	}
+}
@@ -0,0 +1,120 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Increment names the part of a version to bump
+type Increment string
+
+// Increments for Bump
+const (
+	IncrementMajor      Increment = "major"
+	IncrementMinor      Increment = "minor"
+	IncrementPatch      Increment = "patch"
+	IncrementPrerelease Increment = "prerelease"
+)
+
+// DefaultChannel is the pre-release channel Bump starts from a release
+const DefaultChannel = "rc"
+
+// Bump returns the version after v for an increment, dropping build
+// metadata. Bumping a pre-release to the release it leads up to finishes
+// it, so 2.0.0-rc.1 bumps to 2.0.0 for major, and 1.2.4-rc.1 to 1.2.4 for
+// patch. Pre-release bumps continue v's channel, or start DefaultChannel
+// for the next patch
+func Bump(v Semver, inc Increment) (Semver, error) {
+	next := Semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	pre := v.IsPrerelease()
+
+	switch inc {
+	case IncrementMajor:
+		if !pre || v.Minor != 0 || v.Patch != 0 {
+			next = Semver{Major: v.Major + 1}
+		}
+	case IncrementMinor:
+		if !pre || v.Patch != 0 {
+			next = Semver{Major: v.Major, Minor: v.Minor + 1}
+		}
+	case IncrementPatch:
+		if !pre {
+			next.Patch++
+		}
+	case IncrementPrerelease:
+		channel := DefaultChannel
+		if pre {
+			channel = v.Prerelease[0]
+		}
+		return v.NextPrerelease(channel)
+	default:
+		return Semver{}, fmt.Errorf("unknown increment %q", inc)
+	}
+	return next, nil
+}
+
+// NextPrerelease returns the next pre-release on a channel, such as
+// "alpha", "beta" or "rc", numbered from 1. From a release it starts the
+// channel for the next patch: 1.2.3 gives 1.2.4-rc.1. From a pre-release on
+// the same channel it counts up, 1.2.4-rc.1 giving 1.2.4-rc.2, and from a
+// lower channel it switches, 1.2.4-beta.3 giving 1.2.4-rc.1. Switching to
+// a channel that sorts lower would go backwards and fails
+func (v Semver) NextPrerelease(channel string) (Semver, error) {
+	if !validIdentifier(channel) || isNumeric(channel) {
+		return Semver{}, fmt.Errorf("invalid pre-release channel %q", channel)
+	}
+
+	next := Semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	if !v.IsPrerelease() {
+		next.Patch++
+		next.Prerelease = []string{channel, "1"}
+		return next, nil
+	}
+
+	if v.Prerelease[0] == channel {
+		// Count up the last number, or start one after the channel
+		ids := append([]string(nil), v.Prerelease...)
+		for i := len(ids) - 1; i > 0; i-- {
+			if n, err := strconv.ParseUint(ids[i], 10, 64); isNumeric(ids[i]) && err == nil {
+				ids[i] = strconv.FormatUint(n+1, 10)
+				next.Prerelease = ids
+				return next, nil
+			}
+		}
+		next.Prerelease = append(ids, "1")
+		return next, nil
+	}
+
+	next.Prerelease = []string{channel, "1"}
+	if next.Compare(v) <= 0 {
+		return Semver{}, fmt.Errorf("pre-release channel %q would go back from %s", channel, v)
+	}
+	return next, nil
+}
+
+// IncrementFor picks the increment for a release from its commit messages,
+// following Conventional Commits: a "!" after the type, as in "feat!:", or
+// a "BREAKING CHANGE:" footer needs a major release, "feat" a minor one and
+// anything else a patch. It returns "" when there are no messages
+func IncrementFor(messages []string) Increment {
+	var inc Increment
+	for _, message := range messages {
+		header, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+		kind, _, ok := strings.Cut(header, ":")
+		if !ok {
+			kind = ""
+		}
+		kind = strings.TrimSpace(kind)
+
+		switch {
+		case strings.HasSuffix(kind, "!") || strings.Contains(body, "BREAKING CHANGE:") || strings.Contains(body, "BREAKING-CHANGE:"):
+			return IncrementMajor
+		case kind == "feat" || strings.HasPrefix(kind, "feat("):
+			inc = IncrementMinor
+		case inc == "":
+			inc = IncrementPatch
+		}
+	}
+	return inc
+}
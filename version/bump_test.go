@@ -0,0 +1,101 @@
+package version
+
+import "testing"
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		version string
+		inc     Increment
+		want    string
+	}{
+		{"1.2.3", IncrementMajor, "2.0.0"},
+		{"1.2.3", IncrementMinor, "1.3.0"},
+		{"1.2.3", IncrementPatch, "1.2.4"},
+		{"1.2.3+build.5", IncrementPatch, "1.2.4"},
+		{"2.0.0-rc.1", IncrementMajor, "2.0.0"},
+		{"1.2.0-rc.1", IncrementMajor, "2.0.0"},
+		{"1.3.0-beta.2", IncrementMinor, "1.3.0"},
+		{"1.3.1-beta.2", IncrementMinor, "1.4.0"},
+		{"1.2.4-rc.1", IncrementPatch, "1.2.4"},
+		{"1.2.3", IncrementPrerelease, "1.2.4-rc.1"},
+		{"1.2.4-beta.2", IncrementPrerelease, "1.2.4-beta.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+"/"+string(tt.inc), func(t *testing.T) {
+			v := MustParse(tt.version)
+			got, err := Bump(v, tt.inc)
+			if err != nil {
+				t.Fatalf("Bump() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Bump() = %s, want %s", got, tt.want)
+			}
+			if !v.LessThan(got) {
+				t.Errorf("Bump() = %s does not follow %s", got, v)
+			}
+		})
+	}
+
+	if _, err := Bump(MustParse("1.2.3"), "huge"); err == nil {
+		t.Error("Bump() accepted an unknown increment")
+	}
+}
+
+func TestNextPrerelease(t *testing.T) {
+	tests := []struct {
+		version string
+		channel string
+		want    string
+	}{
+		{"1.2.3", "beta", "1.2.4-beta.1"},
+		{"1.2.4-beta.1", "beta", "1.2.4-beta.2"},
+		{"1.2.4-beta.9", "beta", "1.2.4-beta.10"},
+		{"1.2.4-beta", "beta", "1.2.4-beta.1"},
+		{"1.2.4-beta.1.nightly", "beta", "1.2.4-beta.2.nightly"},
+		{"1.2.4-alpha.3", "rc", "1.2.4-rc.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+"/"+tt.channel, func(t *testing.T) {
+			got, err := MustParse(tt.version).NextPrerelease(tt.channel)
+			if err != nil {
+				t.Fatalf("NextPrerelease() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("NextPrerelease() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	for _, tt := range []struct{ version, channel string }{
+		{"1.2.4-rc.1", "beta"},
+		{"1.2.3", ""},
+		{"1.2.3", "1"},
+		{"1.2.3", "rc.1"},
+	} {
+		if got, err := MustParse(tt.version).NextPrerelease(tt.channel); err == nil {
+			t.Errorf("NextPrerelease(%q) from %s = %s, want an error", tt.channel, tt.version, got)
+		}
+	}
+}
+
+func TestIncrementFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		want     Increment
+	}{
+		{"none", nil, ""},
+		{"fixes", []string{"fix: handle empty config", "docs: typo"}, IncrementPatch},
+		{"feature", []string{"fix: typo", "feat(table): add footers"}, IncrementMinor},
+		{"breaking bang", []string{"feat(api)!: drop v1 endpoints", "fix: typo"}, IncrementMajor},
+		{"breaking footer", []string{"refactor: config loader\n\nBREAKING CHANGE: config.Load takes a context"}, IncrementMajor},
+		{"free form", []string{"Update dependencies"}, IncrementPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IncrementFor(tt.messages); got != tt.want {
+				t.Errorf("IncrementFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoMatch is returned by MatchLatest when no version satisfies the
+// constraint
+var ErrNoMatch = errors.New("no version matches constraint")
+
+// Constraint is a version range, such as "^1.2", ">=1.4.0 <2" or
+// "~1.2.3 || ^2". Ranges separated by "||" are alternatives, and the
+// comparators of a range, separated by spaces or commas, must all hold.
+// Supported comparators are:
+//
+//	=1.2.3 or 1.2.3  exactly 1.2.3
+//	!=1.2.3          anything but 1.2.3
+//	>, >=, <, <=     ordered comparisons
+//	^1.2.3           compatible: >=1.2.3 <2.0.0, or <0.3.0 for ^0.2.3
+//	~1.2.3           patch updates: >=1.2.3 <1.3.0
+//	1.2.x, 1.2, *    wildcards: >=1.2.0 <1.3.0, any version
+//
+// As in npm, pre-releases only match when a comparator of the range names
+// a pre-release of the same version, so ">=1.2.0-beta" matches 1.2.0-rc.1
+// but not 1.3.0-rc.1
+type Constraint struct {
+	raw    string
+	ranges [][]comparator
+}
+
+// comparator is one condition on a version
+type comparator struct {
+	op      string
+	version Semver
+}
+
+// ParseConstraint parses a version constraint
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+	for _, part := range strings.Split(s, "||") {
+		fields := strings.FieldsFunc(part, func(r rune) bool {
+			return r == ' ' || r == ',' || r == '\t'
+		})
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q: empty range", s)
+		}
+
+		var comparators []comparator
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			// Allow a space after the operator, as in ">= 1.2"
+			if strings.Trim(field, "<>=!^~") == "" && i+1 < len(fields) {
+				i++
+				field += fields[i]
+			}
+			parsed, err := parseComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			comparators = append(comparators, parsed...)
+		}
+		c.ranges = append(c.ranges, comparators)
+	}
+	return c, nil
+}
+
+// MustParseConstraint is like ParseConstraint but panics on errors, for
+// constraints known at compile time
+func MustParseConstraint(s string) *Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// String returns the constraint as it was written
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether v satisfies the constraint. Versions are read as
+// Compare reads them, and those that cannot be parsed never satisfy it
+func (c *Constraint) Check(v string) bool {
+	parsed, err := parseLoose(v)
+	return err == nil && c.check(parsed)
+}
+
+// check reports whether v satisfies any range of the constraint
+func (c *Constraint) check(v Semver) bool {
+	for _, comparators := range c.ranges {
+		if matchRange(comparators, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Latest returns the newest of versions that satisfies the constraint
+func (c *Constraint) Latest(versions []string) (string, bool) {
+	var latest string
+	var newest Semver
+	found := false
+	for _, s := range versions {
+		v, err := parseLoose(s)
+		if err != nil || !c.check(v) {
+			continue
+		}
+		if !found || newest.LessThan(v) {
+			latest, newest, found = s, v, true
+		}
+	}
+	return latest, found
+}
+
+// MatchLatest returns the newest of versions that satisfies constraint,
+// such as the release of an extension to install
+func MatchLatest(versions []string, constraint string) (string, error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+	latest, ok := c.Latest(versions)
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrNoMatch, constraint)
+	}
+	return latest, nil
+}
+
+// matchRange reports whether v satisfies every comparator
+func matchRange(comparators []comparator, v Semver) bool {
+	for _, cmp := range comparators {
+		if !cmp.match(v) {
+			return false
+		}
+	}
+	if !v.IsPrerelease() {
+		return true
+	}
+	for _, cmp := range comparators {
+		bound := cmp.version
+		if bound.IsPrerelease() && bound.Major == v.Major && bound.Minor == v.Minor && bound.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether v satisfies the comparator
+func (c comparator) match(v Semver) bool {
+	result := v.Compare(c.version)
+	switch c.op {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+	return false
+}
+
+// parseComparator turns one comparator into the ordered comparisons it
+// stands for
+func parseComparator(s string) ([]comparator, error) {
+	op := s[:len(s)-len(strings.TrimLeft(s, "<>=!^~"))]
+	lower, n, err := parsePartial(s[len(op):])
+	if err != nil {
+		return nil, err
+	}
+	parts := [3]uint64{lower.Major, lower.Minor, lower.Patch}
+
+	// next bumps the number at index i, for exclusive upper bounds
+	next := func(i int) Semver {
+		bumped := [3]uint64{}
+		copy(bumped[:i], parts[:i])
+		bumped[i] = parts[i] + 1
+		return Semver{Major: bumped[0], Minor: bumped[1], Patch: bumped[2]}
+	}
+
+	switch op {
+	case "", "=":
+		if n == 0 {
+			return nil, nil
+		}
+		if n == 3 {
+			return []comparator{{"=", lower}}, nil
+		}
+		return []comparator{{">=", lower}, {"<", next(n - 1)}}, nil
+	case "^":
+		if n == 0 {
+			return nil, nil
+		}
+		// The first non-zero number may not change
+		i := 0
+		for i < n-1 && parts[i] == 0 {
+			i++
+		}
+		return []comparator{{">=", lower}, {"<", next(i)}}, nil
+	case "~":
+		if n == 0 {
+			return nil, nil
+		}
+		i := 1
+		if n == 1 {
+			i = 0
+		}
+		return []comparator{{">=", lower}, {"<", next(i)}}, nil
+	case ">", "<=":
+		if n == 0 {
+			if op == ">" {
+				return []comparator{{"<", Semver{}}}, nil
+			}
+			return nil, nil
+		}
+		if n < 3 {
+			// >1.2 means >=1.3.0, and <=1.2 means <1.3.0
+			if op == ">" {
+				return []comparator{{">=", next(n - 1)}}, nil
+			}
+			return []comparator{{"<", next(n - 1)}}, nil
+		}
+		return []comparator{{op, lower}}, nil
+	case ">=", "<", "!=":
+		if n == 0 {
+			if op == ">=" {
+				return nil, nil
+			}
+			return []comparator{{"<", Semver{}}}, nil
+		}
+		return []comparator{{op, lower}}, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+// parsePartial parses a version that may leave out or wildcard numbers,
+// such as "1.2", "1.2.x" or "*", returning it with the missing numbers
+// zeroed and how many are given. Only full versions, which are parsed by
+// Parse, may have a pre-release
+func parsePartial(s string) (Semver, int, error) {
+	v := trimV(strings.TrimSpace(s))
+	core := v
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		core = v[:i]
+	}
+	if core == "" {
+		return Semver{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+
+	fields := strings.Split(core, ".")
+	if len(fields) > 3 {
+		return Semver{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	n := 0
+	for i, field := range fields {
+		if field == "x" || field == "X" || field == "*" {
+			continue
+		}
+		// Numbers cannot follow a wildcard, as in "1.x.3"
+		if n < i {
+			return Semver{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		n++
+	}
+
+	if n == 3 {
+		version, err := Parse(v)
+		return version, n, err
+	}
+	if core != v {
+		return Semver{}, 0, fmt.Errorf("invalid version %q: pre-release needs major, minor and patch", s)
+	}
+	if n == 0 {
+		return Semver{}, 0, nil
+	}
+	version, err := parseLoose(strings.Join(fields[:n], "."))
+	if err != nil {
+		return Semver{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	return version, n, nil
+}
@@ -0,0 +1,124 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConstraint_Check(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"=1.2.3", "v1.2.3", true},
+		{"1.2.3", "1.2.4", false},
+		{"!=1.2.3", "1.2.4", true},
+		{"!=1.2.3", "1.2.3", false},
+
+		{"^1.2.3", "1.2.3", true},
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^1.2.3", "1.2.2", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"^1.2", "1.2.0", true},
+		{"^1", "1.99.0", true},
+
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1.2", "1.2.0", true},
+		{"~1", "1.9.0", true},
+		{"~1", "2.0.0", false},
+
+		{">=1.4.0 <2", "1.4.0", true},
+		{">=1.4.0 <2", "2.0.0", false},
+		{">=1.4.0, <2", "1.9.9", true},
+		{">= 1.4, < 2", "1.3.0", false},
+		{">1.2", "1.2.5", false},
+		{">1.2", "1.3.0", true},
+		{"<=1.2", "1.2.5", true},
+		{"<=1.2", "1.3.0", false},
+
+		{"1.2.x", "1.2.7", true},
+		{"1.2.x", "1.3.0", false},
+		{"1.x", "1.9.0", true},
+		{"1.2", "1.2.7", true},
+		{"*", "3.1.4", true},
+
+		{"~1.2.3 || ^2", "2.5.0", true},
+		{"~1.2.3 || ^2", "1.2.5", true},
+		{"~1.2.3 || ^2", "1.5.0", false},
+
+		{">=1.2.0", "1.3.0-rc.1", false},
+		{">=1.2.0-beta", "1.2.0-rc.1", true},
+		{">=1.2.0-beta", "1.2.0-alpha", false},
+		{">=1.2.0-beta", "1.3.0-rc.1", false},
+		{"^1.2.3", "1.2.3+build.5", true},
+
+		{"^1.2.3", "1.3", true},
+		{"^1.2.3", "latest", false},
+		{"^1.2.3", "1.2.3-01", false},
+		{"*", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint+"/"+tt.version, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint() error = %v", err)
+			}
+			if got := c.Check(tt.version); got != tt.want {
+				t.Errorf("Check(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Invalid(t *testing.T) {
+	for _, constraint := range []string{"", "||", "^1.2 ||", ">=", "=>1.2", "1.2.3.4", "1.a", "~1.2-beta", "^1.2.3 foo", "1.x.3"} {
+		if _, err := ParseConstraint(constraint); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded", constraint)
+		}
+	}
+}
+
+func TestConstraint_String(t *testing.T) {
+	if got := MustParseConstraint(" ^1.2 || ~2.0 ").String(); got != "^1.2 || ~2.0" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestMatchLatest(t *testing.T) {
+	versions := []string{"1.0.0", "v1.4.2", "1.10.0", "2.0.0-rc.1", "2.0.0", "2.1.0"}
+
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"^1", "1.10.0"},
+		{"~1.4", "v1.4.2"},
+		{"<2", "1.10.0"},
+		{"*", "2.1.0"},
+		{">=2.0.0-rc.0 <2.0.0", "2.0.0-rc.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, err := MatchLatest(versions, tt.constraint)
+			if err != nil {
+				t.Fatalf("MatchLatest() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MatchLatest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := MatchLatest(versions, "^3"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("MatchLatest(^3) error = %v, want ErrNoMatch", err)
+	}
+	if _, err := MatchLatest(versions, "^x.y"); err == nil || errors.Is(err, ErrNoMatch) {
+		t.Errorf("MatchLatest(^x.y) error = %v, want a parse error", err)
+	}
+}
@@ -6,9 +6,13 @@
 //   - Version Comparison: Compare versions using semantic versioning rules
 //   - Version Validation: Validate version string formats
 //   - String Formatting: Format versions for display
+//   - Bumping Versions: Next major, minor, patch or pre-release, chosen from commit messages
+//   - Version Constraints: Ranges such as ^1.2, ~1.2.3 and >=1.4 <2 || ^3
+//   - Build Information: Structured build details from ldflags and debug.ReadBuildInfo
+//   - Version History: Track installed versions to detect upgrades and downgrades
 //
 // Example:
-//   v := version.New("1.2.3")
+//   v := version.MustParse("1.2.3")
 //   fmt.Println(v.String()) // "1.2.3"
-//   isNewer := v.IsNewerThan("1.0.0")
+//   isNewer := version.MustParse("1.0.0").LessThan(v)
 package version
\ No newline at end of file
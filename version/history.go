@@ -0,0 +1,337 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// HostName is the name under which the host CLI's versions are recorded
+const HostName = "tykctl"
+
+// ChangeKind describes how an installed version changed since the last run
+type ChangeKind string
+
+const (
+	// ChangeNone means the version is the same as on the last run
+	ChangeNone ChangeKind = "none"
+
+	// ChangeInstall means no version was recorded before
+	ChangeInstall ChangeKind = "install"
+
+	// ChangeUpgrade means the version is newer than on the last run
+	ChangeUpgrade ChangeKind = "upgrade"
+
+	// ChangeDowngrade means the version is older than on the last run
+	ChangeDowngrade ChangeKind = "downgrade"
+
+	// ChangeReinstall means a different build of the same version was installed
+	ChangeReinstall ChangeKind = "reinstall"
+)
+
+// Installation is a version of the CLI or an extension that was seen installed
+type Installation struct {
+	Version     string    `json:"version"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// Change describes the difference between the recorded and the running version
+type Change struct {
+	Name           string     `json:"name"`
+	Kind           ChangeKind `json:"kind"`
+	Previous       string     `json:"previous,omitempty"`
+	Current        string     `json:"current"`
+	PreviousRunAt  time.Time  `json:"previous_run_at,omitempty"`
+	HighestVersion string     `json:"highest_version,omitempty"`
+}
+
+// IsDowngrade reports whether the running version is older than the last one
+func (c *Change) IsDowngrade() bool {
+	return c.Kind == ChangeDowngrade
+}
+
+// Message returns a user facing description of the change, or an empty
+// string when nothing changed
+func (c *Change) Message() string {
+	switch c.Kind {
+	case ChangeUpgrade:
+		return fmt.Sprintf("%s was upgraded from v%s to v%s", c.Name, trimV(c.Previous), trimV(c.Current))
+	case ChangeDowngrade:
+		return fmt.Sprintf("warning: %s was downgraded from v%s to v%s", c.Name, trimV(c.Previous), trimV(c.Current))
+	case ChangeReinstall:
+		return fmt.Sprintf("%s v%s was reinstalled", c.Name, trimV(c.Current))
+	default:
+		return ""
+	}
+}
+
+// componentHistory is the recorded history of a single component
+type componentHistory struct {
+	Installations []Installation `json:"installations"`
+	LastRunAt     time.Time      `json:"last_run_at"`
+	LastBuild     string         `json:"last_build,omitempty"`
+}
+
+// historyFile is the on-disk format of the version history
+type historyFile struct {
+	Components map[string]*componentHistory `json:"components"`
+}
+
+// History records every version of the CLI and its extensions that has run.
+// Entries are only ever appended, and their timestamps never go backwards
+// even if the system clock does.
+type History struct {
+	path string
+	now  func() time.Time
+}
+
+// historyMu serializes access to history files across History values
+var historyMu sync.Mutex
+
+// DefaultHistoryPath returns the location of the version history state file
+func DefaultHistoryPath() string {
+	return filepath.Join(xdg.StateHome, "tykctl", "versions.json")
+}
+
+// NewHistory creates a version history stored at path, using
+// DefaultHistoryPath when path is empty
+func NewHistory(path string) *History {
+	if path == "" {
+		path = DefaultHistoryPath()
+	}
+	return &History{path: path, now: time.Now}
+}
+
+// Path returns the location of the state file
+func (h *History) Path() string {
+	return h.path
+}
+
+// Record notes that version of the named component is running and returns
+// how it changed since the previous run. A new installation is appended
+// whenever the version differs from the most recently recorded one.
+func (h *History) Record(name, version string) (*Change, error) {
+	return h.RecordBuild(name, version, "")
+}
+
+// RecordBuild is like Record, but also detects a different build of the same
+// version, such as a new commit, and reports it as a reinstall
+func (h *History) RecordBuild(name, version, build string) (*Change, error) {
+	if name == "" || version == "" {
+		return nil, fmt.Errorf("name and version are required")
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	state, err := h.load()
+	if err != nil {
+		return nil, err
+	}
+
+	component, exists := state.Components[name]
+	if !exists {
+		component = &componentHistory{}
+		state.Components[name] = component
+	}
+
+	now := h.now()
+	last := component.latest()
+	if last != nil && now.Before(last.InstalledAt) {
+		now = last.InstalledAt
+	}
+	if now.Before(component.LastRunAt) {
+		now = component.LastRunAt
+	}
+
+	change := &Change{
+		Name:           name,
+		Current:        version,
+		PreviousRunAt:  component.LastRunAt,
+		HighestVersion: component.highest(),
+	}
+
+	switch {
+	case last == nil:
+		change.Kind = ChangeInstall
+	case last.Version != version:
+		change.Previous = last.Version
+		if Compare(version, last.Version) < 0 {
+			change.Kind = ChangeDowngrade
+		} else {
+			change.Kind = ChangeUpgrade
+		}
+	case build != "" && component.LastBuild != "" && build != component.LastBuild:
+		change.Previous = last.Version
+		change.Kind = ChangeReinstall
+	default:
+		change.Previous = last.Version
+		change.Kind = ChangeNone
+	}
+
+	if change.Kind != ChangeNone {
+		component.Installations = append(component.Installations, Installation{
+			Version:     version,
+			InstalledAt: now,
+		})
+	}
+	component.LastRunAt = now
+	if build != "" {
+		component.LastBuild = build
+	}
+
+	if err := h.save(state); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// Installations returns every recorded installation of a component, oldest first
+func (h *History) Installations(name string) ([]Installation, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	state, err := h.load()
+	if err != nil {
+		return nil, err
+	}
+
+	component, exists := state.Components[name]
+	if !exists {
+		return nil, nil
+	}
+	return append([]Installation(nil), component.Installations...), nil
+}
+
+// Since returns the installations of a component recorded after t
+func (h *History) Since(name string, t time.Time) ([]Installation, error) {
+	installations, err := h.Installations(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Installation
+	for _, installation := range installations {
+		if installation.InstalledAt.After(t) {
+			result = append(result, installation)
+		}
+	}
+	return result, nil
+}
+
+// Current returns the most recently recorded installation of a component
+func (h *History) Current(name string) (*Installation, bool, error) {
+	installations, err := h.Installations(name)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(installations) == 0 {
+		return nil, false, nil
+	}
+	return &installations[len(installations)-1], true, nil
+}
+
+// LastRun returns when a component was last recorded running
+func (h *History) LastRun(name string) (time.Time, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	state, err := h.load()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if component, exists := state.Components[name]; exists {
+		return component.LastRunAt, nil
+	}
+	return time.Time{}, nil
+}
+
+// Names returns the names of all components with recorded history, sorted
+func (h *History) Names() ([]string, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	state, err := h.load()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(state.Components))
+	for name := range state.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RecordHost records the running host CLI version in the default history
+func RecordHost() (*Change, error) {
+	return NewHistory("").RecordBuild(HostName, Version, GitCommit)
+}
+
+// load reads the state file; the caller must hold historyMu
+func (h *History) load() (*historyFile, error) {
+	state := &historyFile{Components: make(map[string]*componentHistory)}
+
+	data, err := os.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse version history: %w", err)
+	}
+	if state.Components == nil {
+		state.Components = make(map[string]*componentHistory)
+	}
+	return state, nil
+}
+
+// save atomically writes the state file; the caller must hold historyMu
+func (h *History) save(state *historyFile) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal version history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmpPath := h.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write version history: %w", err)
+	}
+	if err := os.Rename(tmpPath, h.path); err != nil {
+		return fmt.Errorf("failed to replace version history: %w", err)
+	}
+	return nil
+}
+
+// latest returns the most recent installation, or nil
+func (c *componentHistory) latest() *Installation {
+	if len(c.Installations) == 0 {
+		return nil
+	}
+	return &c.Installations[len(c.Installations)-1]
+}
+
+// highest returns the highest version ever installed
+func (c *componentHistory) highest() string {
+	highest := ""
+	for _, installation := range c.Installations {
+		if highest == "" || Compare(installation.Version, highest) > 0 {
+			highest = installation.Version
+		}
+	}
+	return highest
+}
@@ -0,0 +1,163 @@
+package version
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestHistory(t *testing.T, clock *time.Time) *History {
+	h := NewHistory(filepath.Join(t.TempDir(), "versions.json"))
+	h.now = func() time.Time { return *clock }
+	return h
+}
+
+func TestHistoryRecord(t *testing.T) {
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := newTestHistory(t, &clock)
+
+	steps := []struct {
+		version  string
+		kind     ChangeKind
+		previous string
+	}{
+		{"1.0.0", ChangeInstall, ""},
+		{"1.0.0", ChangeNone, "1.0.0"},
+		{"1.1.0", ChangeUpgrade, "1.0.0"},
+		{"v1.0.5", ChangeDowngrade, "1.1.0"},
+		{"1.2.0", ChangeUpgrade, "v1.0.5"},
+	}
+
+	for _, step := range steps {
+		clock = clock.Add(time.Hour)
+		change, err := h.Record("tykctl-portal", step.version)
+		if err != nil {
+			t.Fatalf("Record(%s) failed: %v", step.version, err)
+		}
+		if change.Kind != step.kind {
+			t.Errorf("Record(%s) kind = %s, expected %s", step.version, change.Kind, step.kind)
+		}
+		if change.Previous != step.previous {
+			t.Errorf("Record(%s) previous = %s, expected %s", step.version, change.Previous, step.previous)
+		}
+	}
+
+	installations, err := h.Installations("tykctl-portal")
+	if err != nil {
+		t.Fatalf("Installations failed: %v", err)
+	}
+	if len(installations) != 4 {
+		t.Fatalf("Installations() returned %d entries, expected 4", len(installations))
+	}
+
+	current, ok, err := h.Current("tykctl-portal")
+	if err != nil || !ok || current.Version != "1.2.0" {
+		t.Errorf("Current() = %v, %v, %v, expected 1.2.0", current, ok, err)
+	}
+
+	since, _ := h.Since("tykctl-portal", installations[1].InstalledAt)
+	if len(since) != 2 {
+		t.Errorf("Since() returned %d entries, expected 2", len(since))
+	}
+}
+
+func TestHistoryDowngradeMessage(t *testing.T) {
+	clock := time.Now()
+	h := newTestHistory(t, &clock)
+
+	h.Record(HostName, "2.0.0")
+	change, err := h.Record(HostName, "1.9.0")
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	if !change.IsDowngrade() {
+		t.Error("IsDowngrade() should be true")
+	}
+	if change.HighestVersion != "2.0.0" {
+		t.Errorf("HighestVersion = %s, expected 2.0.0", change.HighestVersion)
+	}
+	expected := "warning: tykctl was downgraded from v2.0.0 to v1.9.0"
+	if change.Message() != expected {
+		t.Errorf("Message() = %q, expected %q", change.Message(), expected)
+	}
+}
+
+func TestHistoryMonotonicTimestamps(t *testing.T) {
+	clock := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	h := newTestHistory(t, &clock)
+
+	h.Record(HostName, "1.0.0")
+	clock = clock.Add(-24 * time.Hour)
+	h.Record(HostName, "1.1.0")
+
+	installations, _ := h.Installations(HostName)
+	if installations[1].InstalledAt.Before(installations[0].InstalledAt) {
+		t.Error("InstalledAt should never go backwards")
+	}
+}
+
+func TestHistoryReinstall(t *testing.T) {
+	clock := time.Now()
+	h := newTestHistory(t, &clock)
+
+	h.RecordBuild(HostName, "1.0.0", "abc123")
+	change, _ := h.RecordBuild(HostName, "1.0.0", "abc123")
+	if change.Kind != ChangeNone {
+		t.Errorf("Same build kind = %s, expected %s", change.Kind, ChangeNone)
+	}
+
+	change, _ = h.RecordBuild(HostName, "1.0.0", "def456")
+	if change.Kind != ChangeReinstall {
+		t.Errorf("New build kind = %s, expected %s", change.Kind, ChangeReinstall)
+	}
+}
+
+func TestHistoryNames(t *testing.T) {
+	clock := time.Now()
+	h := newTestHistory(t, &clock)
+
+	h.Record("tykctl-portal", "1.0.0")
+	h.Record(HostName, "1.0.0")
+
+	names, err := h.Names()
+	if err != nil {
+		t.Fatalf("Names failed: %v", err)
+	}
+	if len(names) != 2 || names[0] != HostName || names[1] != "tykctl-portal" {
+		t.Errorf("Names() = %v", names)
+	}
+
+	lastRun, _ := h.LastRun(HostName)
+	if !lastRun.Equal(clock) {
+		t.Errorf("LastRun() = %v, expected %v", lastRun, clock)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.0.0", "1.0.0", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.0.0", "2.0.0", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-rc", "1.0.0-rc.1", -1},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+		{"1", "1.0.0", 0},
+		{"dev", "0.0.1", -1},
+		{"1.0.0", "(devel)", 1},
+		{"dev", "dev", 0},
+	}
+
+	for _, tt := range tests {
+		if result := Compare(tt.a, tt.b); result != tt.expected {
+			t.Errorf("Compare(%s, %s) = %d, expected %d", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
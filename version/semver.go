@@ -0,0 +1,259 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Semver is a semantic version as defined by semver 2.0. It is not named
+// Version, which holds the version of the running binary
+type Semver struct {
+	Major, Minor, Patch uint64
+	// Prerelease holds the dot separated identifiers after "-", such as
+	// ["rc", "1"]
+	Prerelease []string
+	// Build holds the identifiers after "+", which do not affect ordering
+	Build []string
+}
+
+// Parse parses a semantic version, such as "1.2.3", "v1.2.3-rc.1" or
+// "1.2.3+build.5". Versions must have all three numbers, and numbers and
+// numeric pre-release identifiers may not have leading zeros
+func Parse(s string) (Semver, error) {
+	var v Semver
+	rest := trimV(strings.TrimSpace(s))
+
+	if i := strings.Index(rest, "+"); i >= 0 {
+		build := strings.Split(rest[i+1:], ".")
+		for _, id := range build {
+			if !validIdentifier(id) {
+				return Semver{}, fmt.Errorf("invalid version %q: bad build identifier %q", s, id)
+			}
+		}
+		v.Build, rest = build, rest[:i]
+	}
+	if i := strings.Index(rest, "-"); i >= 0 {
+		pre := strings.Split(rest[i+1:], ".")
+		for _, id := range pre {
+			if !validIdentifier(id) || (isNumeric(id) && len(id) > 1 && id[0] == '0') {
+				return Semver{}, fmt.Errorf("invalid version %q: bad pre-release identifier %q", s, id)
+			}
+		}
+		v.Prerelease, rest = pre, rest[:i]
+	}
+
+	core := strings.Split(rest, ".")
+	if len(core) != 3 {
+		return Semver{}, fmt.Errorf("invalid version %q: want major.minor.patch", s)
+	}
+	numbers := [3]*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range core {
+		if !isNumeric(part) || (len(part) > 1 && part[0] == '0') {
+			return Semver{}, fmt.Errorf("invalid version %q: bad number %q", s, part)
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return Semver{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// parseLoose parses a version as Compare and constraints read them: like
+// Parse, but minor and patch may be left out, as in "1.2", and count as zero
+func parseLoose(s string) (Semver, error) {
+	v := trimV(strings.TrimSpace(s))
+	core, suffix := v, ""
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		core, suffix = v[:i], v[i:]
+	}
+	if dots := strings.Count(core, "."); core != "" && dots < 2 {
+		core += strings.Repeat(".0", 2-dots)
+	}
+	return Parse(core + suffix)
+}
+
+// MustParse is like Parse but panics on errors, for versions known at
+// compile time
+func MustParse(s string) Semver {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// String formats the version without a leading "v"
+func (v Semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// IsPrerelease reports whether the version is a pre-release
+func (v Semver) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns -1 if v has lower precedence than o, 1 if higher and 0 if
+// equal. Build metadata is ignored
+func (v Semver) Compare(o Semver) int {
+	for _, pair := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+	return compareIdentifiers(v.Prerelease, o.Prerelease)
+}
+
+// LessThan reports whether v has lower precedence than o
+func (v Semver) LessThan(o Semver) bool {
+	return v.Compare(o) < 0
+}
+
+// Sort sorts versions from oldest to newest. Versions of equal precedence,
+// differing only in build metadata, keep their order
+func Sort(versions []Semver) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].LessThan(versions[j])
+	})
+}
+
+// Latest returns the newest of versions, including pre-releases
+func Latest(versions []Semver) (Semver, bool) {
+	return latest(versions, true)
+}
+
+// LatestStable returns the newest of versions that is not a pre-release
+func LatestStable(versions []Semver) (Semver, bool) {
+	return latest(versions, false)
+}
+
+func latest(versions []Semver, prerelease bool) (Semver, bool) {
+	var newest Semver
+	found := false
+	for _, v := range versions {
+		if v.IsPrerelease() && !prerelease {
+			continue
+		}
+		if !found || newest.LessThan(v) {
+			newest, found = v, true
+		}
+	}
+	return newest, found
+}
+
+// Compare compares two semantic versions, returning -1 if a is older than b,
+// 1 if a is newer and 0 if they are equal. A leading "v" and build metadata
+// are ignored, missing minor and patch numbers count as zero, and
+// pre-releases are older than the corresponding release. Versions that
+// cannot be parsed, such as "dev", are older than any that can, and are
+// compared as strings among themselves
+func Compare(a, b string) int {
+	va, errA := parseLoose(a)
+	vb, errB := parseLoose(b)
+	switch {
+	case errA == nil && errB == nil:
+		return va.Compare(vb)
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// validIdentifier reports whether id is a non-empty run of ASCII letters,
+// digits and hyphens
+func validIdentifier(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumeric reports whether s is a non-empty run of ASCII digits
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareIdentifiers compares pre-release identifiers pairwise. Numeric
+// identifiers compare numerically and sort before alphanumeric ones, and a
+// shorter list of otherwise equal identifiers sorts first
+func compareIdentifiers(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		x, y := a[i], b[i]
+		switch {
+		case isNumeric(x) && isNumeric(y):
+			if c := compareNumeric(x, y); c != 0 {
+				return c
+			}
+		case isNumeric(x):
+			return -1
+		case isNumeric(y):
+			return 1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// compareNumeric compares numeric identifiers of any size, ignoring
+// leading zeros
+func compareNumeric(x, y string) int {
+	x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+	if len(x) != len(y) {
+		if len(x) < len(y) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(x, y)
+}
+
+// trimV removes a leading "v" from a version
+func trimV(v string) string {
+	return strings.TrimPrefix(v, "v")
+}
@@ -0,0 +1,96 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+// precedence is the example ordering from the semver 2.0 specification
+var precedence = []string{
+	"1.0.0-alpha",
+	"1.0.0-alpha.1",
+	"1.0.0-alpha.beta",
+	"1.0.0-beta",
+	"1.0.0-beta.2",
+	"1.0.0-beta.11",
+	"1.0.0-rc.1",
+	"1.0.0",
+	"1.0.1",
+	"1.1.0",
+	"2.0.0",
+}
+
+func TestParse(t *testing.T) {
+	v, err := Parse("v1.2.3-rc.1+build.5")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := Semver{Major: 1, Minor: 2, Patch: 3, Prerelease: []string{"rc", "1"}, Build: []string{"build", "5"}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("Parse() = %+v, want %+v", v, want)
+	}
+	if got := v.String(); got != "1.2.3-rc.1+build.5" {
+		t.Errorf("String() = %q", got)
+	}
+	if !v.IsPrerelease() {
+		t.Error("IsPrerelease() = false")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, s := range []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.2.x", "1.2.3-", "1.2.3-01", "1.2.3-rc..1", "1.2.3+", "1.2.3-rc_1", "-1.2.3"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) succeeded", s)
+		}
+	}
+}
+
+func TestSemver_Compare(t *testing.T) {
+	for i := 0; i < len(precedence)-1; i++ {
+		a, b := MustParse(precedence[i]), MustParse(precedence[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("%s should precede %s", a, b)
+		}
+		if Compare(precedence[i], precedence[i+1]) != -1 {
+			t.Errorf("Compare(%q, %q) != -1", precedence[i], precedence[i+1])
+		}
+	}
+
+	if c := MustParse("1.0.0+build.1").Compare(MustParse("1.0.0+build.2")); c != 0 {
+		t.Errorf("build metadata affected ordering: %d", c)
+	}
+	if c := Compare("1.0.0-rc.99999999999999999999", "1.0.0-rc.100000000000000000000"); c != -1 {
+		t.Errorf("large numeric identifiers: Compare() = %d, want -1", c)
+	}
+}
+
+func TestSort(t *testing.T) {
+	var versions []Semver
+	for i := len(precedence) - 1; i >= 0; i-- {
+		versions = append(versions, MustParse(precedence[i]))
+	}
+	Sort(versions)
+
+	for i, v := range versions {
+		if v.String() != precedence[i] {
+			t.Errorf("versions[%d] = %s, want %s", i, v, precedence[i])
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	versions := []Semver{MustParse("1.4.0"), MustParse("2.0.0-rc.1"), MustParse("1.10.0"), MustParse("0.9.0")}
+
+	if got, ok := Latest(versions); !ok || got.String() != "2.0.0-rc.1" {
+		t.Errorf("Latest() = %s, %v", got, ok)
+	}
+	if got, ok := LatestStable(versions); !ok || got.String() != "1.10.0" {
+		t.Errorf("LatestStable() = %s, %v", got, ok)
+	}
+	if _, ok := LatestStable([]Semver{MustParse("1.0.0-beta")}); ok {
+		t.Error("LatestStable() found a version among pre-releases only")
+	}
+	if _, ok := Latest(nil); ok {
+		t.Error("Latest(nil) found a version")
+	}
+}
@@ -5,11 +5,17 @@ import (
 	"runtime"
 )
 
+// defaultVersion is Version when it was not set with -ldflags
+const defaultVersion = "1.0.0"
+
 var (
-	Version   = "1.0.0"
+	Version   = defaultVersion
 	GitCommit = "unknown"
 	BuildDate = "unknown"
 	GoVersion = runtime.Version()
+	// GitTreeState is "dirty" when built from a work tree with uncommitted
+	// changes
+	GitTreeState = ""
 )
 
 // String returns the version string